@@ -2,20 +2,25 @@ package app
 
 import (
 	"fmt"
-	"image/color"
-	"log"
-	"math"
+	"io"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
 	"singAssist/internal/audio"
 	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/lyrics"
+	"singAssist/internal/quiz"
+	"singAssist/internal/scoring"
+	"singAssist/internal/theory"
 	"singAssist/internal/ui"
+	"singAssist/internal/warmup"
 
+	"github.com/gordonklaus/portaudio"
 	"github.com/hajimehoshi/ebiten/v2"
 	eaudio "github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
@@ -25,19 +30,160 @@ const (
 	StateStartScreen GameState = iota
 	StateCalibrating
 	StatePlaying
+	StateHeatmap
+	StateHistory
+	StateWarmupMenu
+	StateLatencyCalibration
+	StateSongBrowser
+	StateCountdown
+	StateResults
+	StateSessionCompare
+	StateSetup
+	StateNoteEditor
 )
 
+/*
+Options holds launch-time settings passed in from the command line.
+
+Fields:
+  - Load: Practice section the song is cropped to when loading
+  - AutoStart: If true, skip the start screen and start DefaultMode on launch
+  - DefaultMode: Mode to start automatically when AutoStart is set
+  - IgnoreOctave: Count a note sung an octave up/down as a hit
+  - BlockView: Draw the song as piano-roll note blocks instead of a line
+  - Record: Save the mic during playback to the song folder (off with -no-record)
+  - Offline: No internet or Python (-offline): skip the first-run setup screen
+  - TransposeSteps: Semitones to shift the song pitch (+ = up), changed with +/- keys
+  - Setlist: Song folders to play back-to-back in the same mode (-setlist), nil for none
+*/
+type Options struct {
+	Load         audio.LoadOptions
+	AutoStart    bool
+	DefaultMode  audio.Mode
+	IgnoreOctave bool
+	BlockView    bool
+	Record       bool
+	Offline      bool
+
+	TransposeSteps int
+	Setlist        []string
+}
+
+// startModes maps ui.StartButtons indices to the mode each button starts.
+var startModes = []audio.Mode{
+	audio.ModeSinging,
+	audio.ModeRoughVocals,
+	audio.ModeInstrumental,
+	audio.ModeFullMix,
+	audio.ModeNoAudio,
+	audio.ModeDuet,
+	audio.ModeWarmup,
+	audio.ModeHarmony,
+	audio.ModeFreestyle,
+	audio.ModeRangeTest,
+	audio.ModeIntervalQuiz,
+}
+
 /*
 App is the main application structure holding all game state.
 
 Fields:
   - state: Current GameState (StartScreen, Calibrating, Playing)
-  - mode: Current audio.Mode (Singing, Instrumental, FullMix, NoAudio)
+  - mode: Current audio.Mode (Singing, RoughVocals, Instrumental, FullMix, NoAudio, Duet, Warmup, Harmony, Freestyle, RangeTest, IntervalQuiz)
   - songDir: Path to song folder (e.g., "songs/MySong")
+  - opts: Launch options (practice section, etc.)
   - audioPlayer: Ebiten audio player for playback
+  - songAudioFile: File audioPlayer plays, time-stretched for other playback speeds
+  - playbackSpeed: Playback speed factor (1 = original; Ctrl+Down/Up halve/double it)
+  - speedReady: Delivers the slowed-down or sped-up player being prepared (nil if none)
+  - sensitivity: Mic noise gate margin (config/sensitivity.json, start screen slider)
+  - sensitivityDrag: The sensitivity slider is being dragged
+  - liveScore, liveScoreFrames, liveScoreAt: Score of the run for LiveStatus, the
+    sessionPitch length it was computed at and when (recomputed at most every
+    config.LiveScoreInterval, shared by all web HUD clients)
   - songPitch: Pre-analyzed pitch data from song (100 samples/sec)
+  - songPitches: One pitch array per vocal part with -parts 2 (nil otherwise, see songParts)
+  - songChords: Chord frequencies per 10ms frame (instrumental mode only)
+  - songBPM: Tempo detected from the song audio (0 if unknown)
+  - songKey: Key detected from the song pitch (e.g. "D Major", "" if unknown)
+  - songPCM: Decoded PCM behind songPitch, kept for re-analysis
+  - reanalyzing: A full re-analysis of songPCM is running in the background
+  - songDuration: Length of the loaded song (or practice section)
+  - waveform: Song energy overview for the bottom bar
+  - segmentDifficulty: Difficulty of each second of the song (0..1) for the heat bar
   - userPitch: Recorded user pitch pairs [timeMs, pitch, ...]
-  - mic: Microphone handler for real-time input
+  - formant1Pitch: First formant pairs [timeMs, F1 Hz, ...] recorded alongside
+    userPitch in vocal modes (0 while unvoiced), drawn as a dashed line
+  - heatmap: Whole-session note/cents-offset histogram (not pruned)
+  - sessionPitch: Unpruned [timeMs, pitch, ...] pairs for scoring the whole run
+  - userPitch2, sessionPitch2: The second singer's trail and session pairs (duet mode)
+  - sessionStart: When the current session started
+  - history: Recent runs shown on the history screen
+  - bestScore: Best saved score for this song, -1 if none
+  - difficulty: Song difficulty in stars (difficulty.json), 0 until the song is analyzed
+  - hitTolerance: Semitones within which the user hits the song (settings.json, +/- on the start screen)
+  - ghost: Pitch trail of the best saved run (best_session.json), empty if none
+  - lyrics: Timed lyrics from lyrics.lrc, empty if none
+  - showGhost: Whether the ghost trail is drawn (G toggles)
+  - showGrid: Whether the semitone grid is drawn (N toggles, starts at config.ShowSemitoneGrid)
+  - showIntonation: Whether the intonation graph is drawn (I toggles)
+  - sightReading: Whether the upcoming song line is hidden (H toggles, kept between songs)
+  - sightReadRun: Sight reading was on since playback started, so the run earns
+    config.SightReadingMultiplier
+  - scrollSpeed: Pitch graph scroll speed in pixels per second (Ctrl +/- adjusts)
+  - metronomeEnabled, metronomeBPM: Click track on/off (M) and its tempo (Ctrl+[ / Ctrl+])
+  - clickPlayer: Player holding one click, created when the metronome is first enabled
+  - lastBeat: Beat index of the last click (see currentBeat)
+  - refTone: Reference tone playing in no-audio mode (T toggles) or sounding the
+    range test note, nil when off
+  - toneMidi: MIDI note of the reference tone (+/- step it while it plays)
+  - micMonitor, micMonitorPlayer: Mic monitoring stream micLoop feeds and the player
+    sounding it (V toggles), nil when off
+  - recorder: Mic recording of the current session, finished by cleanup (nil with -no-record)
+  - mic: Microphone input (audio.MicHandler or any other audio.MicInput)
+  - phonemes: Vowel classifier for the mic buffer (vocal modes only, nil otherwise)
+  - breaths: Breaths found in the user's pitch (vocal modes only, nil otherwise)
+  - phoneme: Last class it detected, shown in the HUD
+  - songWatcher: Stops the song file watcher (nil when not watching)
+  - songChanged: Set by the watcher when the song file changed; Update re-analyzes
+  - devices: Input devices offered on the start screen
+  - deviceSel: Selected index into devices, -1 for the system default
+  - echoCaptureFrom: Position (ms) where the echo phrase capture began, -1 if none
+  - echoStart: When the echo clock started (zero when echo practice is off)
+  - echoLoop: Length of one echo loop
+  - echoSavedPitch: Song pitch stashed while the echo phrase is the reference
+  - loopStart, loopEnd: Practice loop boundaries (active when loopEnd > loopStart)
+  - refStart: When the local clock for a reference MIDI melody started (no-audio mode)
+    or for a freestyle session, range test or interval quiz
+  - rangeTest: Vocal range test in ModeRangeTest (nil otherwise)
+  - rangeNoteAt: Position where the current range test note started, -1 before the first
+  - quiz: Interval quiz in ModeIntervalQuiz (nil otherwise)
+  - quizAt: Position where the current quiz question started, -1 before the first
+  - quizAnswered: The current question's answer has been recorded
+  - countdownEnd: When the pre-song countdown reaches "GO!"
+  - warmupCfg, warmupRow: Warmup sub-menu settings and highlighted row
+  - exercise: Warmup exercise played in ModeWarmup (generated instead of loading audio)
+  - songs, songFilter, songSel: Song browser list, typed filter and selection in the filtered list
+  - songInfos, genres, genreFilter: info.json tags of the listed songs, the genres they use
+    and the browser's genre filter ("" for all)
+  - setlist, setlistIndex: Songs played back-to-back and which one is current
+    (-1 before the first, after Escape or when an unlisted song was picked)
+  - nextSongAt: When the results screen moves on to the next setlist song (zero if it will not)
+  - harmony: Target harmony offsets in semitones for ModeHarmony (from harmony.json)
+  - vizMode: Pitch line or spectrogram view (S toggles)
+  - spectrum: Recent mic spectrogram columns (only filled in the spectrogram view)
+  - loadProgress: Fraction of the song pitch analysed (0 when no analysis is running)
+  - latencyDone: Whether the latency calibration screen has a result
+  - notes: The song's practice notes (notes.md), shown on the start screen
+  - noteInput, noteMessage: Notes being edited in the note editor and the last save result
+  - setupInput, setupMessage: Venv path typed on the first-run setup screen and
+    why the last attempt was rejected
+  - setupReady: Delivers the result of the venv check in progress (nil if none)
+  - resultPanels: Statistics shown on the results screen after a finished song
+  - compareSong: Song pitch of the last finished run, drawn under the compared sessions
+  - compareSessions: Saved runs of the song (ListSessions), oldest first
+  - compareA, compareB: Indices of the two compared runs; comparePitchA/B are their trails
+  - compareClock: Shared comparison position; comparePaused stops it
   - mu: Mutex for thread-safe access to shared state
   - message: Status/error message to display
 */
@@ -45,13 +191,129 @@ type App struct {
 	state   GameState
 	mode    audio.Mode
 	songDir string
-
-	audioPlayer *eaudio.Player
-	songPitch   []float64
-
-	userPitch []float64
-
-	mic *audio.MicHandler
+	opts    Options
+
+	audioPlayer  *eaudio.Player
+	songPitch    []float64
+	songPitches  [][]float64
+	songChords   [][]float64
+	songBPM      float64
+	songKey      string
+	songPCM      []byte
+	songDuration time.Duration
+	waveform     []float64
+	reanalyzing  bool
+
+	segmentDifficulty []float64
+	songAudioFile     string
+	playbackSpeed     float64
+	speedReady        chan speedChange
+
+	sensitivity     float64
+	sensitivityDrag bool
+
+	liveScore       float64
+	liveScoreFrames int
+	liveScoreAt     time.Time
+
+	userPitch      []float64
+	formant1Pitch  []float64
+	sessionPitch   []float64
+	userPitch2     []float64
+	sessionPitch2  []float64
+	heatmap        *scoring.Heatmap
+	sessionStart   time.Time
+	history        []scoring.HistoryEntry
+	bestScore      float64
+	difficulty     int
+	hitTolerance   float64
+	ghost          []float64
+	lyrics         lyrics.Lyrics
+	showGhost      bool
+	showGrid       bool
+	showIntonation bool
+	sightReading   bool
+	sightReadRun   bool
+	scrollSpeed    float64
+
+	metronomeEnabled bool
+	metronomeBPM     float64
+	clickPlayer      *eaudio.Player
+	lastBeat         int64
+
+	refTone  *audio.ReferenceTonePlayer
+	toneMidi int
+
+	micMonitor       *audio.MicMonitorStream
+	micMonitorPlayer *eaudio.Player
+	recorder         *audio.WavRecorder
+
+	mic      audio.MicInput
+	phonemes *audio.PhonemeDetector
+	phoneme  audio.Phoneme
+	breaths  *audio.BreathDetector
+
+	songWatcher io.Closer
+	songChanged bool
+
+	echoCaptureFrom float64
+	echoStart       time.Time
+	echoLoop        time.Duration
+	echoSavedPitch  []float64
+	refStart        time.Time
+	countdownEnd    time.Time
+
+	rangeTest   *audio.RangeTestSession
+	rangeNoteAt time.Duration
+
+	quiz         *quiz.IntervalQuizSession
+	quizAt       time.Duration
+	quizAnswered bool
+
+	loopStart time.Duration
+	loopEnd   time.Duration
+
+	warmupCfg warmup.WarmupConfig
+	warmupRow int
+	harmony   []int
+	exercise  warmup.ScaleExercise
+
+	songs       []string
+	songFilter  string
+	songSel     int
+	songInfos   map[string]config.SongInfo
+	genres      []string
+	genreFilter string
+
+	setlist      []string
+	setlistIndex int
+	nextSongAt   time.Time
+
+	devices   []portaudio.DeviceInfo
+	deviceSel int
+
+	vizMode  VisualizationMode
+	spectrum []ui.SpectrumColumn
+
+	loadProgress float64
+	latencyDone  bool
+	setupInput   string
+	setupMessage string
+	setupReady   chan setupCheck
+
+	notes        string
+	noteInput    string
+	noteMessage  string
+	resultPanels []ui.ResultsPanel
+
+	compareSong     []float64
+	compareSessions []scoring.SessionResult
+	compareA        int
+	compareB        int
+	comparePitchA   []float64
+	comparePitchB   []float64
+	compareClock    time.Duration
+	comparePaused   bool
 
 	mu      sync.Mutex
 	message string
@@ -61,7 +323,8 @@ type App struct {
 New creates a new App instance for the given song directory.
 
 Input:
-  - songDir: string - Path to song folder (e.g., "songs/MySong")
+  - songDir: string - Path to song folder (e.g., "songs/MySong"), "" to pick one in the song browser
+  - opts: Options - Launch options from the command line
 
 Called by:
   - main.main after resolving song path
@@ -71,18 +334,52 @@ Task:
 
 Logic:
  1. Set state to StartScreen
- 2. Store songDir
+ 2. Store songDir and opts
  3. Initialize empty userPitch slice
+ 4. List the input devices and restore the saved one
+ 5. Load the song's best saved score, difficulty rating, practice loop,
+    settings and notes (no song yet: open the song browser instead)
+ 6. First launch (no venv_path.txt) and not opts.Offline: show the setup screen before either
 
 Output:
   - *App: Ready to be passed to ebiten.RunGame
 */
-func New(songDir string) *App {
-	return &App{
-		state:     StateStartScreen,
-		songDir:   songDir,
-		userPitch: make([]float64, 0),
+func New(songDir string, opts Options) *App {
+	a := &App{
+		state:           StateStartScreen,
+		songDir:         songDir,
+		opts:            opts,
+		userPitch:       make([]float64, 0),
+		echoCaptureFrom: -1,
+		showGhost:       true,
+		showGrid:        config.ShowSemitoneGrid,
+		scrollSpeed:     config.PixelsPerSec,
+		playbackSpeed:   1,
+		metronomeBPM:    config.DefaultMetronomeBPM,
+		toneMidi:        config.ReferenceToneMidi,
+		hitTolerance:    config.DefaultHitTolerance,
+		sensitivity:     config.DefaultSensitivity,
+		setlist:         opts.Setlist,
 	}
+	a.loadDevices()
+	if s, err := config.LoadSensitivity(); err != nil {
+		logging.Warnf("Ignoring %s: %v", config.SensitivityFile, err)
+	} else {
+		a.sensitivity = s
+	}
+	if songDir == "" {
+		a.openSongBrowser()
+	} else {
+		a.refreshBestScore()
+		a.refreshDifficulty()
+		a.loadLoop()
+		a.loadSongSettings()
+		a.notes = config.LoadNotes(songDir)
+	}
+	if config.NeedsSetup() && !a.opts.Offline {
+		a.openSetup()
+	}
+	return a
 }
 
 /*
@@ -121,8 +418,22 @@ Task:
 
 Logic:
  1. Get current window size
- 2. If StartScreen: check for button clicks
- 3. If Playing/Calibrating: check for keyboard input
+ 2. On first frame with AutoStart set and a song chosen: start DefaultMode once
+    (ModeWarmup opens the warmup sub-menu); waits until first-run setup is done
+ 3. If StartScreen: check for button clicks
+ 4. If Playing/Calibrating: re-analyze a changed song file, check for keyboard
+    input, apply the practice loop, click the metronome; at the end of the song
+    save the score and show the results screen
+ 5. If Heatmap: check for keys that close it
+ 6. If History: check for keys that close it
+ 7. If WarmupMenu: edit or start the warmup exercise
+ 8. If LatencyCalibration: wait for the result, then allow going back
+ 9. If SongBrowser: filter and pick a song
+ 10. If Countdown: re-analyze a changed song file, start playback when it runs out
+ 11. If Results: wait for Enter/Space to go back to the start screen
+    (or on to the next setlist song, automatically after a few seconds)
+ 12. If SessionCompare: pick runs and advance the comparison clock
+ 13. If Setup: edit and validate the venv path
 
 Output:
   - error: nil always (returning error would exit game)
@@ -130,10 +441,57 @@ Output:
 func (a *App) Update() error {
 	sw, sh := ebiten.WindowSize()
 
+	if a.opts.AutoStart && a.songDir != "" && a.state != StateSetup {
+		a.opts.AutoStart = false
+		if a.opts.DefaultMode == audio.ModeWarmup {
+			a.openWarmupMenu()
+		} else {
+			a.startGame(a.opts.DefaultMode)
+		}
+		return nil
+	}
+
+	a.applySpeedChange()
+
 	if a.state == StateStartScreen {
 		a.handleStartScreenInput(sw, sh)
 	} else if a.state == StatePlaying || a.state == StateCalibrating {
-		a.handlePlayingInput()
+		a.reloadChangedSong()
+		a.handlePlayingInput(sw, sh)
+		if a.state == StatePlaying {
+			a.applyLoop()
+			a.tickMetronome()
+			if a.mode == audio.ModeRangeTest {
+				a.tickRangeTest()
+			}
+			if a.mode == audio.ModeIntervalQuiz {
+				a.tickIntervalQuiz()
+			}
+			if a.songFinished() {
+				a.finishSong()
+			}
+		}
+	} else if a.state == StateHeatmap {
+		a.handleHeatmapInput()
+	} else if a.state == StateHistory {
+		a.handleHistoryInput()
+	} else if a.state == StateWarmupMenu {
+		a.handleWarmupMenuInput()
+	} else if a.state == StateLatencyCalibration {
+		a.handleLatencyInput()
+	} else if a.state == StateSongBrowser {
+		a.handleSongBrowserInput()
+	} else if a.state == StateCountdown {
+		a.reloadChangedSong()
+		a.handleCountdownInput()
+	} else if a.state == StateResults {
+		a.handleResultsInput()
+	} else if a.state == StateSessionCompare {
+		a.handleSessionCompareInput()
+	} else if a.state == StateSetup {
+		a.handleSetupInput()
+	} else if a.state == StateNoteEditor {
+		a.handleNoteEditorInput()
 	}
 
 	return nil
@@ -154,36 +512,92 @@ Task:
 Logic:
  1. Check for left mouse button press
  2. Get cursor position
- 3. Check if cursor is inside each button's bounds
- 4. Call startGame with corresponding mode if clicked
+ 3. Check if cursor is inside each ui.StartButtonRect
+ 4. Call startGame with the matching startModes entry if clicked
+    (ModeWarmup opens the warmup sub-menu first; the button after the
+    modes opens latency calibration)
+ 5. H key: open the practice history screen
+ 6. L key: open the song browser
+ 7. Left/Right: choose the microphone (handleDeviceInput)
+ 8. +/-: change the song's hit tolerance (handleHitToleranceInput)
+ 9. N key: edit the song's notes (openNoteEditor)
+ 10. Dragging the mic sensitivity slider (handleSensitivityInput) takes the
+    mouse away from the buttons
 
 Output:
   - None (calls startGame to change state)
 */
 func (a *App) handleStartScreenInput(sw, sh int) {
+	a.handleDeviceInput()
+	a.handleHitToleranceInput()
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) {
+		a.openHistory()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+		a.openSongBrowser()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) && a.songDir != "" {
+		a.openNoteEditor()
+		return
+	}
+	if a.handleSensitivityInput(sh) {
+		return
+	}
+
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 		x, y := ebiten.CursorPosition()
 
-		if ui.InRect(x, y, sw/2-100, sh/2-120, 200, 50) {
-			a.startGame(audio.ModeSinging)
-		}
-		if ui.InRect(x, y, sw/2-100, sh/2-60, 200, 50) {
-			a.startGame(audio.ModeInstrumental)
-		}
-		if ui.InRect(x, y, sw/2-100, sh/2, 200, 50) {
-			a.startGame(audio.ModeFullMix)
-		}
-		if ui.InRect(x, y, sw/2-100, sh/2+60, 200, 50) {
-			a.startGame(audio.ModeNoAudio)
+		for i := range ui.StartButtons {
+			bx, by, bw, bh := ui.StartButtonRect(i, sw, sh)
+			if !ui.InRect(x, y, bx, by, bw, bh) {
+				continue
+			}
+			if i >= len(startModes) {
+				a.startLatencyCalibration()
+			} else if startModes[i] == audio.ModeWarmup {
+				a.openWarmupMenu()
+			} else {
+				a.startGame(startModes[i])
+			}
+			return
 		}
 	}
 }
 
 /*
-handlePlayingInput processes keyboard input during playback.
+graphClickTime returns the song time under a click on the pitch graph.
 
 Input:
-  - None
+  - mx: int - Cursor X
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - handlePlayingInput on a click above the waveform bar
+
+Task:
+  - Seek to the point of the song the user clicked
+
+Logic:
+ 1. PitchVisualizer.TimeAtX at the current scroll speed and song position
+ 2. Clamp to 0..songDuration
+
+Output:
+  - time.Duration: Position within the song
+*/
+func (a *App) graphClickTime(mx, sw, sh int) time.Duration {
+	vis := ui.NewPitchVisualizer(sw, sh)
+	vis.PixelsPerSec = a.scrollSpeed
+	t := vis.TimeAtX(float64(mx), a.songPosition().Seconds())
+	return time.Duration(max(0, min(t, a.songDuration.Seconds())) * float64(time.Second))
+}
+
+/*
+handlePlayingInput processes keyboard and mouse input during playback.
+
+Input:
+  - sw, sh: int - Screen width and height (for the waveform bar)
 
 Called by:
   - Update when state is StateCalibrating or StatePlaying
@@ -193,20 +607,83 @@ Task:
 
 Logic:
  1. F key: toggle fullscreen
- 2. Space: toggle play/pause
+ 2. Space: toggle play/pause (ignored during echo practice)
  3. Left arrow: rewind 10 seconds
  4. Right arrow: forward 10 seconds
- 5. Escape: exit to menu
+ 5. B: switch between line and block (piano-roll) song view; +/-: transpose song ±1 semitone (max ±12);
+    Ctrl +/-: scroll speed ±ScrollSpeedStep px/s (MinScrollSpeed..MaxScrollSpeed);
+    Ctrl+Down/Up: halve/double the playback speed (handlePlaybackSpeedInput)
+ 6. E: capture a phrase / start or stop echo practice (see toggleEcho)
+ 7. R: pause and show the pitch heatmap
+ 8. Shift+Up/Down: tune silence threshold, re-analyze visible window; Shift+S: save session PNG;
+    S: toggle between the pitch line and the spectrogram; G: show or hide the ghost trail;
+    N: show or hide the semitone grid; I: show or hide the intonation graph;
+    H: toggle sight reading (only a run that had it on before playback started
+    gets the sight-reading multiplier)
+ 9. Shift+A: re-analyze the whole song with current parameters
+ 10. Shift+G: cycle gap filling (fixed, adaptive, off) and re-analyze visible window
+ 11. [ / ] / \: set loop start / set loop end / clear loop (see handleLoopInput)
+ 12. Click on the waveform bar or the difficulty heat bar above it: seek to that point of the song; click on the pitch
+    graph above it: seek to the time under the cursor (graphClickTime; not
+    during echo practice); V: toggle mic monitoring
+ 13. Escape: exit to menu
+ 14. Freestyle mode: only its own, smaller set of keys (handleFreestyleInput);
+    range test and interval quiz: handleRangeTestInput
+
+The keys above are the defaults; all except Shift+Up/Down/A, Ctrl+Up/Down, the loop keys
+and the numpad +/- alternates come from config.Keys (keybindings.json).
 
 Output:
   - None (modifies app state or audio player)
 */
-func (a *App) handlePlayingInput() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+func (a *App) handlePlayingInput(sw, sh int) {
+	if a.mode == audio.ModeFreestyle {
+		a.handleFreestyleInput()
+		return
+	}
+	if a.mode == audio.ModeRangeTest || a.mode == audio.ModeIntervalQuiz {
+		a.handleRangeTestInput()
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(config.Keys.Fullscreen) {
 		ebiten.SetFullscreen(!ebiten.IsFullscreen())
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+	if inpututil.IsKeyJustPressed(config.Keys.BlockView) {
+		a.opts.BlockView = !a.opts.BlockView
+	}
+
+	a.handleLoopInput()
+	a.handleMetronomeInput()
+	if inpututil.IsKeyJustPressed(config.Keys.MicMonitor) {
+		a.toggleMicMonitor()
+	}
+
+	up := inpututil.IsKeyJustPressed(config.Keys.TransposeUp) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd)
+	down := inpututil.IsKeyJustPressed(config.Keys.TransposeDown) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract)
+	tone := a.mode == audio.ModeNoAudio && a.handleReferenceToneInput(up, down)
+	if ebiten.IsKeyPressed(ebiten.KeyControl) {
+		if up {
+			a.scrollSpeed = min(config.MaxScrollSpeed, a.scrollSpeed+config.ScrollSpeedStep)
+		}
+		if down {
+			a.scrollSpeed = max(config.MinScrollSpeed, a.scrollSpeed-config.ScrollSpeedStep)
+		}
+	} else if !tone {
+		if up {
+			a.shiftTranspose(1)
+		}
+		if down {
+			a.shiftTranspose(-1)
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(config.Keys.Echo) && a.state == StatePlaying {
+		a.toggleEcho()
+	}
+
+	if inpututil.IsKeyJustPressed(config.Keys.Pause) && a.echoStart.IsZero() {
 		if a.audioPlayer != nil {
 			if a.audioPlayer.IsPlaying() {
 				a.audioPlayer.Pause()
@@ -216,24 +693,217 @@ func (a *App) handlePlayingInput() {
 		}
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+	if inpututil.IsKeyJustPressed(config.Keys.SeekBack) {
 		if a.audioPlayer != nil {
-			pos := a.audioPlayer.Position()
+			pos := a.songPosition()
 			newPos := pos - 10*time.Second
 			if newPos < 0 {
 				newPos = 0
 			}
-			a.audioPlayer.SetPosition(newPos)
+			a.seekSong(newPos)
 		}
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+	if inpututil.IsKeyJustPressed(config.Keys.SeekForward) {
 		if a.audioPlayer != nil {
-			pos := a.audioPlayer.Position()
-			a.audioPlayer.SetPosition(pos + 10*time.Second)
+			pos := a.songPosition()
+			a.seekSong(pos + 10*time.Second)
 		}
 	}
 
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && a.audioPlayer != nil && a.songDuration > 0 {
+		mx, my := ebiten.CursorPosition()
+		bx, by, bw, bh := ui.WaveformBarRect(sw, sh)
+		if ui.InRect(mx, my, bx, by-8, bw, bh+8) {
+			frac := float64(mx-bx) / float64(bw)
+			a.seekSong(time.Duration(frac * float64(a.songDuration)))
+		} else if my < by-8 && a.echoStart.IsZero() {
+			a.seekSong(a.graphClickTime(mx, sw, sh))
+		}
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyControl) {
+		a.handlePlaybackSpeedInput(inpututil.IsKeyJustPressed(ebiten.KeyUp), inpututil.IsKeyJustPressed(ebiten.KeyDown))
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+			a.retuneAnalysis(0.5, false)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+			a.retuneAnalysis(-0.5, false)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+			a.retuneAnalysis(0, true)
+		}
+		if inpututil.IsKeyJustPressed(config.Keys.Ghost) {
+			a.opts.Load.Analysis.Gaps = (a.opts.Load.Analysis.Gaps + 1) % 3
+			a.retuneAnalysis(0, false)
+		}
+		if inpututil.IsKeyJustPressed(config.Keys.Visualization) {
+			a.exportSessionImage()
+		}
+	} else {
+		if inpututil.IsKeyJustPressed(config.Keys.Visualization) {
+			a.toggleVisualization()
+		}
+		if inpututil.IsKeyJustPressed(config.Keys.Ghost) {
+			a.showGhost = !a.showGhost
+		}
+		if inpututil.IsKeyJustPressed(config.Keys.Grid) {
+			a.showGrid = !a.showGrid
+		}
+		if inpututil.IsKeyJustPressed(config.Keys.Intonation) {
+			a.showIntonation = !a.showIntonation
+		}
+		if inpututil.IsKeyJustPressed(config.Keys.SightReading) {
+			a.sightReading = !a.sightReading
+			a.sightReadRun = a.sightReading && a.state == StateCalibrating
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(config.Keys.Heatmap) && a.state == StatePlaying {
+		a.mu.Lock()
+		if a.audioPlayer != nil {
+			a.audioPlayer.Pause()
+		}
+		a.state = StateHeatmap
+		a.mu.Unlock()
+	}
+
+	if inpututil.IsKeyJustPressed(config.Keys.Exit) {
+		a.exitToMenu()
+	}
+}
+
+/*
+retuneAnalysis changes analysis parameters and recomputes song pitch.
+
+Input:
+  - silenceDelta: float64 - Amount to add to SilenceFactor
+  - full: bool - Re-analyze the whole song instead of the visible window
+
+Called by:
+  - handlePlayingInput for the Shift+Up/Down/A/G tuning keys
+
+Task:
+  - Interactive tuning of the detection engine
+
+Logic:
+ 1. Skip if no PCM is loaded yet or a full re-analysis is still running
+ 2. Adjust SilenceFactor (min 0.5) and show the new value; remove the
+    song's pitch cache for this mode (audio.InvalidatePitchCache) so the
+    next load does not bring back the old contour
+ 3. If full: reanalyzeSong in a goroutine (it takes seconds for a whole song)
+ 4. Else: leave echo practice (songPitch must be the song again), then
+    audio.ReanalyzeWindow over the visible -3s..+5s window and drop
+    songPitches (the parts keep the default parameters and would no longer
+    match the tuned songPitch; scoring falls back to songPitch), then
+    recompute segmentDifficulty for the heat bar
+
+Output:
+  - None (updates songPitch, segmentDifficulty and message)
+*/
+func (a *App) retuneAnalysis(silenceDelta float64, full bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.songPCM == nil || a.audioPlayer == nil || a.reanalyzing {
+		return
+	}
+
+	params := &a.opts.Load.Analysis
+	params.SilenceFactor += silenceDelta
+	if params.SilenceFactor < 0.5 {
+		params.SilenceFactor = 0.5
+	}
+	if err := audio.InvalidatePitchCache(a.songDir, a.mode); err != nil {
+		logging.Warnf("Could not remove the pitch cache: %v", err)
+	}
+
+	if full {
+		a.reanalyzing = true
+		a.message = fmt.Sprintf("Re-analyzing (silence factor %.1f, gap fill %s)...", params.SilenceFactor, params.Gaps)
+		go a.reanalyzeSong(a.songPCM, a.mode, *params)
+		return
+	}
+
+	a.stopEcho()
+	now := a.songPosition().Seconds()
+	audio.ReanalyzeWindow(a.songPCM, a.mode, *params, a.songPitch, now-3, now+5)
+	a.songPitches = nil
+	a.segmentDifficulty = audio.ComputeSegmentDifficulty(a.songPitch, 100)
+	a.message = fmt.Sprintf("Silence factor: %.1f  Gap fill: %s", params.SilenceFactor, params.Gaps)
+}
+
+/*
+reanalyzeSong re-runs pitch analysis over the whole song.
+
+Input:
+  - pcm: []byte - songPCM when the re-analysis was requested
+  - mode: audio.Mode - Analysis mode of the session
+  - params: audio.AnalysisParams - Tuned analysis parameters
+
+Called by:
+  - retuneAnalysis (as goroutine)
+
+Task:
+  - Keep the UI responsive while the song is analyzed again
+
+Logic:
+ 1. audio.Reanalyze without holding mu
+ 2. Drop the result if the session moved on to another song meanwhile
+ 3. Leave echo practice: the new contour replaces the song reference, so
+    the phrase swapped into songPitch would otherwise be lost
+ 4. Swap the new contour into songPitch, drop songPitches and recompute
+    segmentDifficulty, as retuneAnalysis does for a window
+
+Output:
+  - None (updates songPitch, songPitches, segmentDifficulty and message)
+*/
+func (a *App) reanalyzeSong(pcm []byte, mode audio.Mode, params audio.AnalysisParams) {
+	pitch := audio.Reanalyze(pcm, mode, params)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.reanalyzing = false
+	if len(a.songPCM) == 0 || &a.songPCM[0] != &pcm[0] {
+		return
+	}
+
+	a.stopEcho()
+	a.songPitch = pitch
+	a.songPitches = nil
+	a.segmentDifficulty = audio.ComputeSegmentDifficulty(pitch, 100)
+	a.message = fmt.Sprintf("Silence factor: %.1f  Gap fill: %s", params.SilenceFactor, params.Gaps)
+}
+
+/*
+handleHeatmapInput processes keyboard input on the heatmap view.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateHeatmap
+
+Task:
+  - Return to the paused session or exit to the menu
+
+Logic:
+ 1. R or Space: back to StatePlaying (still paused; Space there resumes)
+ 2. Escape: exit to menu
+
+Output:
+  - None (changes state)
+*/
+func (a *App) handleHeatmapInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		a.mu.Lock()
+		a.state = StatePlaying
+		a.mu.Unlock()
+	}
+
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 		a.exitToMenu()
 	}
@@ -256,9 +926,16 @@ Task:
 Logic:
  1. Call cleanup to release previous resources
  2. Set mode and state to Calibrating
- 3. Reset userPitch slice
- 4. Create and start microphone handler
- 5. Launch calibrateAndPlay goroutine
+ 3. Reset userPitch, formant1Pitch, sessionPitch (and the second singer's) and heatmap;
+    the run counts as sight read if sight reading is already on;
+    load the best run's ghost trail and the song's lyrics; ModeRangeTest
+    starts a fresh audio.RangeTestSession, ModeIntervalQuiz a quiz within the
+    saved vocal range (newIntervalQuiz)
+ 4. Create and start microphone handler (stereo for ModeDuet) and, in vocal
+    modes, the phoneme and breath detectors; unless -no-record (opts.Record)
+    start a WAV recorder (config.RecordingPath)
+ 5. Watch the song file for changes (startSongWatcher)
+ 6. Launch calibrateAndPlay goroutine
 
 Output:
   - None (transitions to calibration state)
@@ -270,14 +947,52 @@ func (a *App) startGame(m audio.Mode) {
 	a.state = StateCalibrating
 	a.message = "Calibrating background noise..."
 	a.userPitch = make([]float64, 0)
+	a.formant1Pitch = make([]float64, 0)
+	a.heatmap = scoring.NewHeatmap(a.opts.IgnoreOctave)
+	a.sessionPitch = a.sessionPitch[:0]
+	a.liveScore, a.liveScoreFrames = 0, 0
+	a.userPitch2 = make([]float64, 0)
+	a.sessionPitch2 = a.sessionPitch2[:0]
+	a.sessionStart = time.Now()
+	a.sightReadRun = a.sightReading
+	a.rangeTest, a.rangeNoteAt = nil, -1
+	if m == audio.ModeRangeTest {
+		a.rangeTest = audio.NewRangeTestSession()
+	}
+	a.quiz, a.quizAt, a.quizAnswered = nil, -1, false
+	if m == audio.ModeIntervalQuiz {
+		a.newIntervalQuiz()
+	}
+
+	ghost, err := scoring.LoadBestSession(a.songDir)
+	if err != nil {
+		logging.Warnf("Ignoring best_session.json: %v", err)
+	}
+	a.ghost = ghost
+
+	a.lyrics = nil
+	if lrc, err := lyrics.LoadLRC(config.GetSongPaths(a.songDir).LyricsFile); err == nil {
+		a.lyrics = lrc
+	} else if !os.IsNotExist(err) {
+		logging.Warnf("Ignoring lyrics.lrc: %v", err)
+	}
 
-	a.mic = audio.NewMicHandler()
+	a.mic = a.newMic(m == audio.ModeDuet)
+	a.phonemes, a.breaths = nil, nil
+	if m.IsVocal() {
+		a.phonemes = audio.NewPhonemeDetector(config.SampleRate)
+		a.breaths = audio.NewBreathDetector()
+	}
 	if err := a.mic.Start(); err != nil {
-		log.Printf("Failed to start microphone: %v", err)
+		logging.Errorf("Failed to start microphone: %v", err)
 		a.message = "Error: Failed to start microphone"
 		a.state = StateStartScreen
 		return
 	}
+	if a.opts.Record {
+		a.recorder = audio.NewWavRecorder(config.RecordingPath(a.songDir, time.Now()), config.SampleRate)
+	}
+	a.startSongWatcher()
 
 	go a.calibrateAndPlay()
 }
@@ -294,51 +1009,117 @@ Called by:
 Task:
   - Calibrate noise threshold
   - Load and analyze song
-  - Start playback
+  - Start the countdown to playback
 
 Logic:
  1. Run mic.Calibrate for 2 seconds
- 2. Update state to Playing
- 3. Call audio.LoadAndAnalyzeSong
- 4. If error: display error message, return
- 5. Store player and songPitch
- 6. Start playback
- 7. Launch micLoop goroutine
+ 2. Load the song with loadSong
+ 3. Launch micLoop goroutine (it warms up the smoother during the countdown)
 
 Output:
-  - None (updates app state, starts playback)
+  - None (updates app state, starts the countdown)
 */
 func (a *App) calibrateAndPlay() {
 	a.mic.Calibrate(2 * time.Second)
 
+	if a.loadSong("Loading Song...") {
+		go a.micLoop()
+	}
+}
+
+/*
+loadSong loads and analyzes the current song for the current mode.
+
+Input:
+  - msg: string - Status shown until the analysis reports progress
+
+Called by:
+  - calibrateAndPlay (in its goroutine)
+  - reloadChangedSong (as goroutine) after the song file changed
+
+Task:
+  - Fill the song data a session plays against
+
+Logic:
+ 1. Update state to Playing and show msg
+ 2. Call audio.LoadAndAnalyzeSong with the practice section options, tracking
+    its analysis progress in loadProgress for the progress bar
+    (ModeWarmup: generate the exercise pitch instead, no audio file;
+    ModeFreestyle, ModeRangeTest, ModeIntervalQuiz: nothing to load, there is no reference;
+    ModeHarmony: also load the song's target harmonies)
+ 3. If error: display error message, return
+ 4. Store player, songPitch, songPitches and PCM
+ 5. Switch to StateCountdown; Update calls startPlayback when it ends
+
+Output:
+  - bool: true if the song was loaded
+*/
+func (a *App) loadSong(msg string) bool {
 	a.mu.Lock()
 	a.state = StatePlaying
-	a.message = "Loading Song..."
+	a.message = msg
 	a.mu.Unlock()
 
-	result, err := audio.LoadAndAnalyzeSong(a.songDir, a.mode, func(msg string) {
+	var result *audio.LoadResult
+	var err error
+	if a.mode == audio.ModeWarmup {
+		pitch := a.exercise.Generate()
+		result = &audio.LoadResult{
+			SongPitch: pitch,
+			Duration:  time.Duration(len(pitch)) * 10 * time.Millisecond,
+		}
+	} else if a.mode == audio.ModeFreestyle || a.mode == audio.ModeRangeTest || a.mode == audio.ModeIntervalQuiz {
+		result = &audio.LoadResult{}
+	} else {
+		if a.mode == audio.ModeHarmony {
+			harmony, herr := theory.LoadHarmony(config.GetSongPaths(a.songDir).HarmonyFile)
+			if herr != nil {
+				logging.Warnf("Could not read harmony.json, using defaults: %v", herr)
+			}
+			a.mu.Lock()
+			a.harmony = harmony
+			a.mu.Unlock()
+		}
+		opts := a.opts.Load
+		opts.OnProgress = func(done, total int) {
+			a.mu.Lock()
+			a.loadProgress = float64(done) / float64(max(1, total))
+			a.mu.Unlock()
+		}
+		result, err = audio.LoadAndAnalyzeSong(a.songDir, a.mode, opts, func(msg string) {
+			a.mu.Lock()
+			a.message = msg
+			a.mu.Unlock()
+		})
 		a.mu.Lock()
-		a.message = msg
+		a.loadProgress = 0
 		a.mu.Unlock()
-	})
+	}
 
 	if err != nil {
 		a.mu.Lock()
 		a.message = "Error: " + err.Error()
 		a.mu.Unlock()
-		return
+		return false
 	}
 
 	a.mu.Lock()
 	a.audioPlayer = result.Player
 	a.songPitch = result.SongPitch
+	a.songPitches = result.SongPitches
+	a.songChords = result.Chords
+	a.songBPM = result.BPM
+	a.songKey = result.Key
+	a.songPCM = result.PCM
+	a.songDuration = result.Duration
+	a.waveform = result.Waveform
+	a.songAudioFile, a.playbackSpeed = result.AudioFile, 1
+	a.segmentDifficulty = audio.ComputeSegmentDifficulty(result.SongPitch, 100)
 	a.message = ""
-	if a.audioPlayer != nil {
-		a.audioPlayer.Play()
-	}
+	a.countdownEnd = time.Now().Add(countdownLength)
+	a.state = StateCountdown
 	a.mu.Unlock()
-
-	go a.micLoop()
+	return true
 }
 
 /*
@@ -357,13 +1138,25 @@ Task:
 
 Logic:
  1. Loop until mic is nil or Done
- 2. Read microphone buffer
- 3. If not Playing state, continue
- 4. Detect pitch using current mode settings
+ 2. Read microphone buffer; on error try recoverMic, stop if it gives up
+ 3. Under mu, take the monitor, state and vizMode (the UI goroutine changes
+    them); if not Playing or Countdown state, continue
+ 4. Detect pitch using current mode settings (two pitches in duet mode);
+    in the spectrogram view also compute the buffer's ui.MicSpectrum
+    (during the countdown stop here: the smoother warms up, nothing is recorded)
  5. Lock mutex
- 6. If playing: append (time, pitch) to userPitch
- 7. Call pruneUserPitch to limit memory usage
- 8. Unlock mutex
+ 6. If playing (player or echo clock): stream the mic's Delivered audio to
+    the recorder (unless -no-record), append (time, pitch) to userPitch
+    and, in vocal modes, (time, F1) from audio.TrackFormant1 to formant1Pitch
+    and the pitch to the breath detector,
+    clearing them first when the echo loop wraps around
+ 7. Shift the frame back into the song's key (undo TransposeSteps)
+ 8. Add it to the heatmap against the latency-compensated song pitch
+ 9. Outside echo practice: also keep it in the unpruned sessionPitch for scoring
+ 10. Duet mode: record the second singer in userPitch2 and sessionPitch2
+    (spectrogram view: store the spectrum column)
+ 11. Call pruneUserPitch to limit memory usage
+ 12. Unlock mutex
 
 Output:
   - None (appends to userPitch slice)
@@ -375,25 +1168,155 @@ func (a *App) micLoop() {
 		}
 
 		if err := a.mic.Read(); err != nil {
-			return
+			if !a.recoverMic(err) {
+				return
+			}
+			continue
 		}
 
-		if a.state != StatePlaying {
+		a.mu.Lock()
+		monitor, state, vizMode := a.micMonitor, a.state, a.vizMode
+		a.mu.Unlock()
+		if monitor != nil {
+			monitor.Write(a.mic.Samples())
+		}
+
+		if state != StatePlaying && state != StateCountdown {
 			continue
 		}
 
-		pitch := a.mic.DetectPitchFromMic(a.mode)
+		pitch, pitch2 := a.mic.DetectPitchFromMic(a.mode)
+		if state == StateCountdown {
+			continue
+		}
+		var levels []float64
+		if vizMode == VizSpectrogram {
+			levels = ui.MicSpectrum(a.mic.Samples())
+		}
+		phoneme := audio.PhonemeNone
+		if a.phonemes != nil {
+			phoneme = a.phonemes.Detect(a.mic.Samples(), pitch)
+		}
+		formant1 := 0.0
+		if a.mode.IsVocal() && pitch > 0 {
+			formant1 = audio.TrackFormant1(a.mic.Samples(), config.SampleRate)
+		}
 
 		a.mu.Lock()
-		if a.audioPlayer != nil && a.audioPlayer.IsPlaying() {
-			pos := a.audioPlayer.Position()
+		a.phoneme = phoneme
+		if pos, running := a.playbackPos(); running {
+			if a.recorder != nil {
+				a.recorder.Add(a.mic.Delivered())
+			}
+			if !a.echoStart.IsZero() && len(a.userPitch) >= 2 && float64(pos.Milliseconds()) < a.userPitch[len(a.userPitch)-2] {
+				a.userPitch = a.userPitch[:0]
+				a.userPitch2 = a.userPitch2[:0]
+				a.formant1Pitch = a.formant1Pitch[:0]
+			}
 			a.userPitch = append(a.userPitch, float64(pos.Milliseconds()), pitch)
+			if a.mode.IsVocal() {
+				a.formant1Pitch = append(a.formant1Pitch, float64(pos.Milliseconds()), formant1)
+			}
+			if a.breaths != nil {
+				a.breaths.Update(pitch, float64(pos.Milliseconds()))
+			}
+			inSongKey := theory.Transpose(pitch, -a.opts.TransposeSteps)
+			if a.echoStart.IsZero() {
+				a.sessionPitch = append(a.sessionPitch, float64(pos.Milliseconds()), inSongKey)
+			}
+			if a.mode == audio.ModeDuet {
+				a.userPitch2 = append(a.userPitch2, float64(pos.Milliseconds()), pitch2)
+				if a.echoStart.IsZero() {
+					a.sessionPitch2 = append(a.sessionPitch2, float64(pos.Milliseconds()), theory.Transpose(pitch2, -a.opts.TransposeSteps))
+				}
+			}
+			if levels != nil && a.vizMode == VizSpectrogram {
+				a.addSpectrumColumn(float64(pos.Milliseconds()), levels)
+			}
+			sIdx := int((pos.Seconds() - config.GetAudioLatencyMs()/1000.0) * 100)
+			if sIdx >= 0 && sIdx < len(a.songPitch) {
+				a.heatmap.Add(inSongKey, a.songPitch[sIdx], float64(len(a.mic.Samples()))/config.SampleRate)
+			}
 			a.pruneUserPitch(pos.Milliseconds())
 		}
 		a.mu.Unlock()
 	}
 }
 
+/*
+shiftTranspose moves TransposeSteps by some semitones.
+
+Input:
+  - steps: int - Semitones to add (+1 for the + key, -1 for -)
+
+Called by:
+  - handlePlayingInput
+
+Task:
+  - Change the key the song is sung in while micLoop is recording
+
+Logic:
+ 1. Lock mu (micLoop reads TransposeSteps to store frames in the song's key)
+ 2. Add steps, clamped to one octave either way
+
+Output:
+  - None (updates opts.TransposeSteps)
+*/
+func (a *App) shiftTranspose(steps int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.opts.TransposeSteps = max(-12, min(12, a.opts.TransposeSteps+steps))
+}
+
+/*
+recoverMic tries to reopen the microphone after a read error.
+
+Input:
+  - readErr: error - Error returned by mic.Read
+
+Called by:
+  - micLoop when a read fails
+
+Task:
+  - Survive transient disconnects without silently losing input
+
+Logic:
+ 1. Up to 5 times: wait (500ms, 1s, 2s...), stop early if the session ended
+ 2. Call mic.Reopen; on success clear any warning and return true
+ 3. After all attempts fail, show "Microphone disconnected" and return false
+
+Output:
+  - bool: true if the mic is usable again
+*/
+func (a *App) recoverMic(readErr error) bool {
+	logging.Warnf("Microphone read failed: %v", readErr)
+	maxRetries := 5
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		time.Sleep(time.Duration(500*(1<<attempt)) * time.Millisecond)
+		if a.mic == nil || a.mic.IsDone() {
+			return false
+		}
+
+		a.mu.Lock()
+		a.message = fmt.Sprintf("Microphone lost, reconnecting (%d/%d)...", attempt+1, maxRetries)
+		a.mu.Unlock()
+
+		if err := a.mic.Reopen(); err == nil {
+			a.mu.Lock()
+			a.message = ""
+			a.mu.Unlock()
+			logging.Infof("Microphone reconnected")
+			return true
+		}
+	}
+
+	a.mu.Lock()
+	a.message = "Warning: microphone disconnected"
+	a.mu.Unlock()
+	return false
+}
+
 /*
 pruneUserPitch removes old pitch data to limit memory usage.
 
@@ -406,40 +1329,65 @@ Called by:
 Task:
   - Remove pitch data older than MaxUserPitchHistory seconds
 
+Logic:
+ 1. Prune userPitch, userPitch2 and formant1Pitch with prunePitchTrail
+
+Output:
+  - None (replaces the userPitch slices)
+*/
+func (a *App) pruneUserPitch(currentMs int64) {
+	a.userPitch = prunePitchTrail(a.userPitch, currentMs)
+	a.userPitch2 = prunePitchTrail(a.userPitch2, currentMs)
+	a.formant1Pitch = prunePitchTrail(a.formant1Pitch, currentMs)
+}
+
+/*
+prunePitchTrail drops [timeMs, pitch] pairs older than MaxUserPitchHistory.
+
+Input:
+  - trail: []float64 - Pairs of [timeMs, pitch, ...]
+  - currentMs: int64 - Current playback position in milliseconds
+
+Called by:
+  - pruneUserPitch for each singer's trail
+
+Task:
+  - Keep the on-screen trails bounded
+
 Logic:
  1. Calculate minimum time threshold
  2. If threshold <= 0, nothing to prune
  3. Find first index with time >= threshold
- 4. Create new slice containing only recent data
- 5. Replace userPitch with new slice (allows GC of old data)
+ 4. Return a new slice containing only recent data (allows GC of old data)
 
 Output:
-  - None (modifies userPitch slice in place)
+  - []float64: The pruned trail (trail itself when nothing was cut)
 */
-func (a *App) pruneUserPitch(currentMs int64) {
-	if len(a.userPitch) == 0 {
-		return
+func prunePitchTrail(trail []float64, currentMs int64) []float64 {
+	if len(trail) == 0 {
+		return trail
 	}
 
 	minMs := currentMs - int64(config.MaxUserPitchHistory*1000)
 	if minMs <= 0 {
-		return
+		return trail
 	}
 
 	cutIdx := 0
-	for i := 0; i < len(a.userPitch); i += 2 {
-		if a.userPitch[i] >= float64(minMs) {
+	for i := 0; i < len(trail); i += 2 {
+		if trail[i] >= float64(minMs) {
 			cutIdx = i
 			break
 		}
 		cutIdx = i + 2
 	}
 
-	if cutIdx > 0 && cutIdx < len(a.userPitch) {
-		newPitch := make([]float64, len(a.userPitch)-cutIdx)
-		copy(newPitch, a.userPitch[cutIdx:])
-		a.userPitch = newPitch
+	if cutIdx > 0 && cutIdx < len(trail) {
+		newPitch := make([]float64, len(trail)-cutIdx)
+		copy(newPitch, trail[cutIdx:])
+		return newPitch
 	}
+	return trail
 }
 
 /*
@@ -450,6 +1398,7 @@ Input:
 
 Called by:
   - handlePlayingInput when ESC is pressed
+  - handleCountdownInput when ESC is pressed
 
 Task:
   - Exit fullscreen
@@ -458,15 +1407,18 @@ Task:
 
 Logic:
  1. Disable fullscreen
- 2. Call cleanup
- 3. Set state to StartScreen
+ 2. Record the session in the history log
+ 3. Call cleanup and reload the difficulty rating (the session may have rated the song)
+ 4. Set state to StartScreen
 
 Output:
   - None (transitions to start screen)
 */
 func (a *App) exitToMenu() {
 	ebiten.SetFullscreen(false)
+	a.recordHistory()
 	a.cleanup()
+	a.refreshDifficulty()
 	a.state = StateStartScreen
 }
 
@@ -486,11 +1438,12 @@ Task:
   - Clear data structures
 
 Logic:
- 1. Stop and nil microphone handler
- 2. Pause, close, and nil audio player
- 3. Nil songPitch slice
- 4. Reset userPitch to empty slice
- 5. Clear message
+ 1. Stop and nil microphone handler, reference tone, mic monitoring and song
+    watcher; save the session recording (stopRecording)
+ 2. Pause, close, and nil audio player; stop echo practice
+ 3. Nil songPitch, songPitches, songChords and songPCM slices, forget songBPM and songKey
+ 4. Reset userPitch, userPitch2 and formant1Pitch to empty slices
+ 5. Clear message and the phoneme label
 
 Output:
   - None (releases resources)
@@ -500,6 +1453,18 @@ func (a *App) cleanup() {
 		a.mic.Stop()
 		a.mic = nil
 	}
+	if a.refTone != nil {
+		a.refTone.Stop()
+		a.refTone = nil
+	}
+	a.stopMicMonitor()
+	a.stopRecording()
+	if a.songWatcher != nil {
+		a.songWatcher.Close()
+		a.songWatcher = nil
+	}
+	a.songChanged = false
+	a.phoneme = audio.PhonemeNone
 
 	if a.audioPlayer != nil {
 		a.audioPlayer.Pause()
@@ -507,8 +1472,25 @@ func (a *App) cleanup() {
 		a.audioPlayer = nil
 	}
 
+	a.echoCaptureFrom = -1
+	a.echoStart = time.Time{}
+	a.echoSavedPitch = nil
+	a.refStart = time.Time{}
 	a.songPitch = nil
+	a.songPitches = nil
+	a.songChords = nil
+	a.songBPM = 0
+	a.songKey = ""
+	a.songPCM = nil
+	a.reanalyzing = false
+	a.songDuration = 0
+	a.songAudioFile = ""
+	a.waveform = nil
+	a.segmentDifficulty = nil
 	a.userPitch = make([]float64, 0)
+	a.userPitch2 = make([]float64, 0)
+	a.formant1Pitch = make([]float64, 0)
+	a.spectrum = nil
 	a.message = ""
 }
 
@@ -526,12 +1508,19 @@ Task:
 
 Logic:
  1. Get window size
- 2. If StartScreen: call ui.DrawStartScreen
- 3. If Calibrating: call ui.DrawCalibrating
- 4. Lock mutex for thread-safe data access
+ 2. If StartScreen: call ui.DrawStartScreen, the microphone selector, hit
+    tolerance and mic sensitivity slider
+ 3. If Calibrating: call ui.DrawCalibrating (History: call drawHistory,
+    WarmupMenu: call drawWarmupMenu, LatencyCalibration: call drawLatencyCalibration,
+    SongBrowser: call drawSongBrowser, Results: call drawResults,
+    SessionCompare: call drawSessionCompare, NoteEditor: call drawNoteEditor,
+    Setup: call ui.DrawSetupScreen)
+ 4. Lock mutex for thread-safe data access (Heatmap: call drawHeatmap,
+    Countdown: call drawCountdown)
  5. Fill screen black
  6. If message set: display it
- 7. If NoAudio mode: call drawNoAudioMode
+ 7. If RangeTest: call drawRangeTest; IntervalQuiz: drawIntervalQuiz; if Freestyle, or NoAudio mode without a
+    reference melody: call drawFreestyleMode
  8. If not playing: return
  9. Call drawPlayingMode
 
@@ -542,7 +1531,12 @@ func (a *App) Draw(screen *ebiten.Image) {
 	sw, sh := ebiten.WindowSize()
 
 	if a.state == StateStartScreen {
-		ui.DrawStartScreen(screen, sw, sh, a.SongName())
+		ui.DrawStartScreen(screen, sw, sh, a.SongName(), a.bestScore, a.difficulty, len(a.setlist), a.notes)
+		a.drawDeviceSelector(screen, sw)
+		ui.DrawHitTolerance(screen, a.hitTolerance, sw)
+		x, y, w := ui.SensitivitySliderPos(sh)
+		ui.DrawSlider(screen, x, y, w, a.sensitivity, config.MinSensitivity, config.MaxSensitivity,
+			fmt.Sprintf("Mic sensitivity: %.1fx (lower hears quieter singing)", a.sensitivity))
 		return
 	}
 
@@ -551,21 +1545,82 @@ func (a *App) Draw(screen *ebiten.Image) {
 		return
 	}
 
+	if a.state == StateHistory {
+		a.drawHistory(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateWarmupMenu {
+		a.drawWarmupMenu(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateLatencyCalibration {
+		a.drawLatencyCalibration(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateSongBrowser {
+		a.drawSongBrowser(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateResults {
+		a.drawResults(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateSessionCompare {
+		a.drawSessionCompare(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateNoteEditor {
+		a.drawNoteEditor(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateSetup {
+		ui.DrawSetupScreen(screen, a.setupInput, a.setupMessage, sw, sh)
+		return
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	screen.Fill(color.Black)
+	screen.Fill(ui.ActiveTheme.Background)
+
+	if a.state == StateHeatmap {
+		a.drawHeatmap(screen, sw, sh)
+		return
+	}
+
+	if a.state == StateCountdown {
+		a.drawCountdown(screen, sw, sh)
+		return
+	}
 
 	if a.message != "" {
 		ui.DrawMessage(screen, a.message)
 	}
+	if a.loadProgress > 0 {
+		ui.DrawProgressBar(screen, a.loadProgress)
+	}
 
-	if a.mode == audio.ModeNoAudio {
-		a.drawNoAudioMode(screen, sw, sh)
+	if a.mode == audio.ModeRangeTest {
+		a.drawRangeTest(screen, sw, sh)
+		return
+	}
+	if a.mode == audio.ModeIntervalQuiz {
+		a.drawIntervalQuiz(screen, sw, sh)
+		return
+	}
+	if a.mode == audio.ModeFreestyle || (a.mode == audio.ModeNoAudio && a.refStart.IsZero()) {
+		a.drawFreestyleMode(screen, sw, sh)
 		return
 	}
 
-	if a.audioPlayer == nil || !a.audioPlayer.IsPlaying() {
+	if _, running := a.playbackPos(); !running {
 		return
 	}
 
@@ -573,43 +1628,39 @@ func (a *App) Draw(screen *ebiten.Image) {
 }
 
 /*
-drawNoAudioMode renders the practice mode without audio playback.
+drawHeatmap renders the session pitch heatmap view.
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
   - sw, sh: int - Screen dimensions
 
 Called by:
-  - Draw when mode is ModeNoAudio
+  - Draw when state is StateHeatmap
 
 Task:
-  - Display current pitch with visual feedback
+  - Show where the user's pitch spent time relative to each target note
 
 Logic:
- 1. Get current mic pitch
- 2. Convert to note name
- 3. Display pitch info text
- 4. If pitch detected: draw pitch marker
- 5. Show exit hint
+ 1. Crop heatmap rows to the notes that have data
+ 2. Call ui.DrawHeatmap in a centered panel
+ 3. Draw title and key hints
 
 Output:
   - None (draws to screen)
 */
-func (a *App) drawNoAudioMode(screen *ebiten.Image, sw, sh int) {
-	pitch := 0.0
-	if a.mic != nil {
-		pitch = a.mic.Pitch
-	}
-	playNote, _ := ui.FreqToNote(pitch)
-	stats := fmt.Sprintf("YOUR PITCH: %-4s (%.0f Hz)\n\nNo audio playback - practice mode", playNote, pitch)
-	ebitenutil.DebugPrintAt(screen, stats, 10, 10)
-
-	if pitch > 10 {
-		vis := ui.NewPitchVisualizer(sw, sh)
-		vis.DrawCurrentPitch(screen, pitch)
+func (a *App) drawHeatmap(screen *ebiten.Image, sw, sh int) {
+	var cells [][]float64
+	firstMidi := scoring.HeatmapMinMidi
+	if a.heatmap != nil {
+		if first, last := a.heatmap.UsedRange(); first >= 0 {
+			cells = a.heatmap.Cells[first : last+1]
+			firstMidi += first
+		}
 	}
 
-	ebitenutil.DebugPrintAt(screen, "ESC: Exit", 10, sh-20)
+	ui.DrawTextAt(screen, "PITCH HEATMAP  (x: target note, y: cents sharp/flat, bright: more time)", 60, 20, ui.ActiveTheme.HUDText)
+	ui.DrawHeatmap(screen, cells, firstMidi, scoring.HeatmapCentsSpan, 60, 50, sw-100, sh-120)
+	ui.DrawTextAt(screen, "R/SPACE: Back  ESC: Exit", 10, sh-20, ui.ActiveTheme.HUDText)
 }
 
 /*
@@ -628,64 +1679,166 @@ Task:
 Logic:
  1. Get current playback time
  2. Get current mic pitch
- 3. Convert user and song pitches to note names
- 4. Display pitch comparison stats
+ 3. Convert user and song pitches to note names (with several vocal parts,
+    the song note is the part closest to the user)
+ 4. Display pitch comparison stats and the user's vibrato while there is one
+    (duet mode: SONG, USER 1 and USER 2 columns;
+    harmony mode: the interval between them in the centre, YOU is green on a target harmony;
+    instrumental mode: the song's chord in the centre)
  5. Create PitchVisualizer
- 6. Draw song pitch line
- 7. Draw user pitch trail with hit detection
- 8. Draw current pitch marker
- 9. Draw "now" line
- 10. Draw control hints
+ 6. Draw song pitch line, one colour per vocal part (or note blocks when BlockView is on),
+    over the mic spectrogram in the spectrogram view and the semitone grid;
+    sight reading hides everything right of the "now" line
+ 7. Pitch line view: draw user pitch trail with hit detection against every part (duet partner's in magenta underneath,
+    the best run's ghost behind both while showGhost is on), the dashed F1 line over it and the breath markers
+ 8. Draw current pitch marker and tuning-lock indicator
+ 9. Draw loop markers (if a loop is set), the "now" line (pulsing on the song's beats, see onBeat), the waveform overview bar
+    with the difficulty heat bar and the current lyric line above it (lyrics are timed from the song start,
+    so the practice section offset is added; hidden during echo practice)
+ 10. Draw the intonation graph bottom left while showIntonation is on
+ 11. Draw control hints, the song key and tempo, the playback speed (with a player), the sung phoneme (vocal modes), the "SIGHT READ" badge, the metronome tempo and reference tone
+    while they are on (and the exercise name in warmup mode)
 
 Output:
   - None (draws to screen)
 */
 func (a *App) drawPlayingMode(screen *ebiten.Image, sw, sh int) {
-	currTime := a.audioPlayer.Position().Seconds()
+	pos, _ := a.playbackPos()
+	currTime := pos.Seconds()
 
 	pitch := 0.0
 	if a.mic != nil {
-		pitch = a.mic.Pitch
+		pitch = a.mic.CurrentPitch()
 	}
 
-	userNote, userOctave := ui.FreqToNote(pitch)
+	userNote, userOctave := theory.FreqToNote(pitch)
 	songNoteStr := "-"
 	songOctave := 0
-	songFreq := 0.0
 
 	sIdx := int(currTime * 100)
-	if sIdx >= 0 && sIdx < len(a.songPitch) {
-		songFreq = a.songPitch[sIdx]
-		if songFreq > 10 {
-			songNoteStr, songOctave = ui.FreqToNote(songFreq)
-		}
+	parts := a.songParts()
+	songFreq := a.songFreqAt(sIdx, pitch)
+	if songFreq > 10 {
+		songNoteStr, songOctave = theory.FreqToNote(songFreq)
 	}
 
-	isMatched := false
-	if pitch > 10 && songFreq > 10 {
-		diff := math.Abs(ui.FreqToMidi(pitch) - ui.FreqToMidi(songFreq))
-		isMatched = diff < 0.7
-	}
+	isMatched := a.isHit(pitch, songFreq)
 
 	songDisplay := ui.NoteDisplay{
-		Note:   songNoteStr,
-		Octave: songOctave,
-		Freq:   songFreq,
+		Note:      songNoteStr,
+		Octave:    songOctave,
+		Freq:      songFreq,
+		Transpose: a.opts.TransposeSteps,
 	}
 	userDisplay := ui.NoteDisplay{
 		Note:      userNote,
 		Octave:    userOctave,
 		Freq:      pitch,
 		IsMatched: isMatched,
+		CentsDev:  theory.CentsOffset(pitch),
+	}
+	duet := a.mode == audio.ModeDuet && a.mic != nil
+	if duet {
+		userDisplay.Label = "USER 1"
 	}
 	ui.DrawNoteHUD(screen, sw, songDisplay, userDisplay)
+	if a.mic != nil {
+		if v := a.mic.CurrentVibrato(); v.Present {
+			ui.DrawVibrato(screen, sw, v.Rate, v.DepthSemitones)
+		}
+	}
+	if a.mode == audio.ModeHarmony {
+		interval := "-"
+		if pitch > 10 && songFreq > 10 {
+			interval = theory.IntervalLabel(pitch, songFreq)
+		}
+		ui.DrawIntervalPanel(screen, sw, interval, isMatched)
+	}
+	if a.mode == audio.ModeInstrumental && sIdx >= 0 && sIdx < len(a.songChords) {
+		var notes []string
+		for _, f := range a.songChords[sIdx] {
+			n, _ := theory.FreqToNote(theory.Transpose(f, a.opts.TransposeSteps))
+			notes = append(notes, n)
+		}
+		ui.DrawChordPanel(screen, sw, theory.ChordName(notes), notes)
+	}
+	if duet {
+		pitch2 := a.mic.CurrentPitch2()
+		note2, octave2 := theory.FreqToNote(pitch2)
+		ui.DrawUserNotePanel(screen, sw/2-65, ui.NoteDisplay{
+			Note:      note2,
+			Octave:    octave2,
+			Freq:      pitch2,
+			IsMatched: pitch2 > 10 && songFreq > 10 && theory.SemitoneDistance(pitch2, songFreq, a.opts.IgnoreOctave) < a.hitTolerance,
+			CentsDev:  theory.CentsOffset(pitch2),
+			Label:     "USER 2",
+		})
+	}
 
 	vis := ui.NewPitchVisualizer(sw, sh)
-	vis.DrawSongPitch(screen, a.songPitch, currTime, sw, sh)
-	vis.DrawUserPitch(screen, a.userPitch, a.songPitch, currTime, sw, sh)
+	vis.PixelsPerSec = a.scrollSpeed
+	vis.IgnoreOctave = a.opts.IgnoreOctave
+	vis.TransposeSteps = a.opts.TransposeSteps
+	vis.HideFuture = a.sightReading
+	if a.showGhost {
+		vis.Ghost = a.ghost
+	}
+	if a.vizMode == VizSpectrogram {
+		vis.DrawSpectrogram(screen, a.spectrum, currTime, sw)
+	}
+	if a.showGrid {
+		ui.DrawSemitoneGrid(screen, vis, sh)
+	}
+	if a.opts.BlockView {
+		vis.DrawSongPitchBlocks(screen, a.visibleNoteBlocks(currTime, sw), currTime, sw, sh)
+	} else {
+		vis.DrawSongPitch(screen, parts, ui.SongPartColors(len(parts)), currTime, sw, sh)
+	}
+	if a.vizMode == VizPitchLine {
+		if duet {
+			vis.DrawSecondUserPitch(screen, a.userPitch2, parts, currTime, sw, sh, a.hitTolerance)
+		}
+		vis.DrawUserPitch(screen, a.userPitch, parts, currTime, sw, sh, a.hitTolerance)
+		vis.DrawFormantTrack(screen, a.formant1Pitch, currTime, sw)
+		if a.breaths != nil {
+			ui.DrawBreathMarkers(screen, vis, a.breaths.Breaths(), currTime)
+		}
+	}
 	vis.DrawCurrentPitch(screen, pitch)
-	vis.DrawNowLine(screen, sh)
-	ui.DrawControls(screen, sh)
+	vis.DrawLockIndicator(screen, pitch, a.lockCharge(currTime*1000))
+	if a.loopActive() {
+		vis.DrawLoopMarkers(screen, a.loopStart.Seconds(), a.loopEnd.Seconds(), currTime, sh)
+	}
+	vis.DrawNowLine(screen, sh, a.onBeat(currTime))
+	if a.songDuration > 0 {
+		ui.DrawWaveformBar(screen, a.waveform, currTime/a.songDuration.Seconds(), sw, sh)
+		wx, wy, ww, _ := ui.WaveformBarRect(sw, sh)
+		ui.DrawDifficultyHeatBar(screen, a.segmentDifficulty, currTime, a.songDuration.Seconds(), wx, wy-8, ww, 6)
+	}
+	if line := a.lyrics.CurrentLine(pos + a.opts.Load.Start); line != "" && a.echoStart.IsZero() {
+		ui.DrawLyricLine(screen, line, sw, sh)
+	}
+	if a.showIntonation {
+		ui.DrawIntonationGraph(screen, a.userPitch, currTime, 10, sh-175, 300, 100)
+	}
+	ui.DrawControls(screen, sh, a.scrollSpeed)
+	ui.DrawSongKey(screen, a.songKey)
+	ui.DrawSongBPM(screen, a.songBPM)
+	if a.audioPlayer != nil {
+		ui.DrawPlaybackSpeed(screen, a.playbackSpeed)
+	}
+	if a.phonemes != nil {
+		ui.DrawPhonemeLabel(screen, string(a.phoneme))
+	}
+	if a.sightReading {
+		ui.DrawSightReadingLabel(screen, sw)
+	}
+	a.drawMetronome(screen, sw)
+	a.drawReferenceTone(screen, sw)
+	a.drawMicMonitor(screen, sw)
+	if a.mode == audio.ModeWarmup {
+		ui.DrawTextAt(screen, "Warmup: "+a.exercise.Label(), sw/2-80, 20, ui.ActiveTheme.HUDText)
+	}
 }
 
 /*
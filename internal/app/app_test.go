@@ -0,0 +1,121 @@
+package app
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"singAssist/internal/audio"
+
+	eaudio "github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+var (
+	testContextOnce sync.Once
+	testContext     *eaudio.Context
+)
+
+// newTestPlayer returns a player over silence. Ebiten allows one audio
+// context per process, so the tests share it.
+func newTestPlayer() *eaudio.Player {
+	testContextOnce.Do(func() { testContext = eaudio.NewContext(44100) })
+	return testContext.NewPlayerFromBytes(make([]byte, 4*44100))
+}
+
+// newMicLoopApp returns an app playing a no-audio session (the reference
+// clock started a second ago) that reads pitches from mic.
+func newMicLoopApp(mic *FakeMic) *App {
+	return &App{
+		mic:      mic,
+		state:    StatePlaying,
+		mode:     audio.ModeFullMix,
+		refStart: time.Now().Add(-time.Second),
+	}
+}
+
+// trailPitches returns the pitch half of a [timeMs, pitch, ...] trail.
+func trailPitches(trail []float64) []float64 {
+	var out []float64
+	for i := 1; i < len(trail); i += 2 {
+		out = append(out, trail[i])
+	}
+	return out
+}
+
+func equalPitches(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMicLoopAppendsPitch(t *testing.T) {
+	mic := &FakeMic{Pitches: []float64{220, 0, 440, 330}}
+	a := newMicLoopApp(mic)
+	a.micLoop()
+
+	if got := trailPitches(a.userPitch); !equalPitches(got, mic.Pitches) {
+		t.Errorf("userPitch pitches = %v, want %v", got, mic.Pitches)
+	}
+	if got := trailPitches(a.sessionPitch); !equalPitches(got, mic.Pitches) {
+		t.Errorf("sessionPitch pitches = %v, want %v", got, mic.Pitches)
+	}
+	prev := 0.0
+	for i := 0; i < len(a.userPitch); i += 2 {
+		ms := a.userPitch[i]
+		if ms < 1000 || ms < prev {
+			t.Errorf("timestamp %d = %v ms, want >= 1000 and not before %v", i/2, ms, prev)
+		}
+		prev = ms
+	}
+}
+
+func TestMicLoopStoresSessionInSongKey(t *testing.T) {
+	mic := &FakeMic{Pitches: []float64{440, 660}}
+	a := newMicLoopApp(mic)
+	a.opts.TransposeSteps = 12
+	a.micLoop()
+
+	if got := trailPitches(a.userPitch); !equalPitches(got, []float64{440, 660}) {
+		t.Errorf("userPitch pitches = %v, want the sung pitches", got)
+	}
+	if got := trailPitches(a.sessionPitch); !equalPitches(got, []float64{220, 330}) {
+		t.Errorf("sessionPitch pitches = %v, want them an octave down in the song's key", got)
+	}
+}
+
+func TestMicLoopRecordsNothingWhenNotPlaying(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(a *App)
+	}{
+		{"countdown", func(a *App) { a.state = StateCountdown }},
+		{"clock stopped", func(a *App) { a.refStart = time.Time{} }},
+	}
+	for _, tt := range tests {
+		mic := &FakeMic{Pitches: []float64{220, 440}}
+		a := newMicLoopApp(mic)
+		tt.setup(a)
+		a.micLoop()
+		if len(a.userPitch) != 0 || len(a.sessionPitch) != 0 {
+			t.Errorf("%s: recorded userPitch %v, sessionPitch %v; want nothing", tt.name, a.userPitch, a.sessionPitch)
+		}
+		if mic.next != len(mic.Pitches) {
+			t.Errorf("%s: DetectPitchFromMic called %d times, want %d", tt.name, mic.next, len(mic.Pitches))
+		}
+	}
+}
+
+func TestMicLoopStopsWhenMicIsDone(t *testing.T) {
+	mic := &FakeMic{Pitches: []float64{220, 440}, stopped: true}
+	a := newMicLoopApp(mic)
+	a.micLoop()
+	if mic.next != 0 || len(a.userPitch) != 0 {
+		t.Errorf("micLoop read %d pitches from a stopped mic, want none", mic.next)
+	}
+}
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// benchDetectCalls is how many mic buffers Benchmark runs pitch detection on.
+	benchDetectCalls = 1000
+	// benchFrames is how many playback frames Benchmark draws (one second at 60fps).
+	benchFrames = 60
+)
+
+/*
+Benchmark times the expensive parts of a session.
+
+Input:
+  - songDir: string - Song folder to load
+  - mode: audio.Mode - Mode to load and draw it in
+
+Called by:
+  - main.main with -benchmark
+
+Task:
+  - Show developers where the time goes: analysis, pitch detection or drawing
+
+Logic:
+ 1. runBenchmark
+ 2. Print one row per stage: total time and time per call
+
+Output:
+  - error: Load or game loop error
+*/
+func Benchmark(songDir string, mode audio.Mode) error {
+	stages, err := runBenchmark(songDir, mode)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-18s %8s %12s %12s\n", "stage", "calls", "total", "per call")
+	for _, st := range stages {
+		fmt.Printf("%-18s %8d %12v %12v\n", st.name, st.calls, st.total.Round(time.Microsecond), (st.total / time.Duration(st.calls)).Round(time.Microsecond))
+	}
+	return nil
+}
+
+/*
+benchStage is one timed row of the benchmark table.
+
+Fields:
+  - name: Stage shown in the table
+  - calls: How many times the stage ran
+  - total: Time for all calls
+*/
+type benchStage struct {
+	name  string
+	calls int
+	total time.Duration
+}
+
+/*
+runBenchmark measures each benchmark stage.
+
+Input:
+  - songDir: string - Song folder to load
+  - mode: audio.Mode - Mode to load and draw it in
+
+Called by:
+  - Benchmark
+
+Task:
+  - Time analysis, pitch detection and drawing
+
+Logic:
+ 1. Time audio.LoadAndAnalyzeSong with the default analysis (Recache, so the
+    analysis itself is measured rather than the pitch cache)
+ 2. Time benchDetectCalls audio.DetectPitch calls on a synthetic 220Hz
+    config.BufferSize buffer
+ 3. Build a playing App around the result, 10 seconds into the song on the
+    reference clock, with the song pitch as a fake user trail
+ 4. Time benchFrames frames of drawPlayingMode inside ebiten.RunGame with
+    vsync off (see benchGame): outside the game loop ebiten only queues draw
+    commands, so the figure would leave out the actual rendering
+
+Output:
+  - []benchStage: load+analyze, pitch detection and draw frame
+  - error: Load or game loop error
+*/
+func runBenchmark(songDir string, mode audio.Mode) ([]benchStage, error) {
+	start := time.Now()
+	opts := audio.LoadOptions{Analysis: audio.DefaultAnalysisParams(), Recache: true}
+	result, err := audio.LoadAndAnalyzeSong(songDir, mode, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	load := time.Since(start)
+	if result.Player != nil {
+		result.Player.Close()
+	}
+
+	buf := make([]float32, config.BufferSize)
+	for i := range buf {
+		buf[i] = float32(0.5 * math.Sin(2*math.Pi*220*float64(i)/config.SampleRate))
+	}
+	start = time.Now()
+	for range benchDetectCalls {
+		audio.DetectPitch(buf, 85, 1100)
+	}
+	detect := time.Since(start)
+
+	a := &App{
+		state:           StatePlaying,
+		mode:            mode,
+		songDir:         songDir,
+		songPitch:       result.SongPitch,
+		songChords:      result.Chords,
+		songBPM:         result.BPM,
+		songKey:         result.Key,
+		songDuration:    result.Duration,
+		waveform:        result.Waveform,
+		echoCaptureFrom: -1,
+		showGrid:        config.ShowSemitoneGrid,
+		scrollSpeed:     config.PixelsPerSec,
+		hitTolerance:    config.DefaultHitTolerance,
+		refStart:        time.Now().Add(-10 * time.Second),
+	}
+	for i, p := range result.SongPitch {
+		a.userPitch = append(a.userPitch, float64(i*10), p)
+	}
+	g := &benchGame{app: a}
+	ebiten.SetWindowSize(config.ScreenW, config.ScreenH)
+	ebiten.SetWindowTitle("SingAssist - benchmark")
+	ebiten.SetVsyncEnabled(false)
+	ebiten.SetTPS(ebiten.SyncWithFPS)
+	if err := ebiten.RunGame(g); err != nil {
+		return nil, err
+	}
+	draw := g.end.Sub(g.start)
+
+	return []benchStage{
+		{"load+analyze", 1, load},
+		{"pitch detection", benchDetectCalls, detect},
+		{"draw frame", benchFrames, draw},
+	}, nil
+}
+
+/*
+benchGame runs runBenchmark's drawing stage in the ebiten game loop.
+
+Fields:
+  - app: Playing App whose drawPlayingMode is timed
+  - frames: Frames drawn so far
+  - start: When the first frame was drawn
+  - end: When the frame after the last timed one started (its Update
+    follows the presentation of the last timed frame)
+*/
+type benchGame struct {
+	app        *App
+	frames     int
+	start, end time.Time
+}
+
+/*
+Update ends the game loop once benchFrames frames were drawn.
+
+Input:
+  - None
+
+Called by:
+  - Ebiten game loop (once per frame, ebiten.SyncWithFPS)
+
+Task:
+  - Stop the benchmark window after the timed frames
+
+Logic:
+ 1. After benchFrames draws: record end and return ebiten.Termination
+
+Output:
+  - error: ebiten.Termination when done, nil otherwise
+*/
+func (g *benchGame) Update() error {
+	if g.frames >= benchFrames {
+		g.end = time.Now()
+		return ebiten.Termination
+	}
+	return nil
+}
+
+/*
+Draw renders one playback frame.
+
+Input:
+  - screen: *ebiten.Image - Frame to draw on
+
+Called by:
+  - Ebiten game loop
+
+Task:
+  - Draw the same frame a session would
+
+Logic:
+ 1. Record start on the first frame
+ 2. drawPlayingMode at config.ScreenW × config.ScreenH and count the frame
+
+Output:
+  - None (draws to screen)
+*/
+func (g *benchGame) Draw(screen *ebiten.Image) {
+	if g.frames == 0 {
+		g.start = time.Now()
+	}
+	g.app.drawPlayingMode(screen, config.ScreenW, config.ScreenH)
+	g.frames++
+}
+
+/*
+Layout returns the fixed benchmark screen size.
+
+Input:
+  - outsideWidth, outsideHeight: int - Window size (ignored)
+
+Called by:
+  - Ebiten game loop
+
+Task:
+  - Draw at the size a session uses by default
+
+Logic:
+ 1. Return config.ScreenW, config.ScreenH
+
+Output:
+  - int, int: Screen width and height
+*/
+func (g *benchGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return config.ScreenW, config.ScreenH
+}
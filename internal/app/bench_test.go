@@ -0,0 +1,36 @@
+//go:build bench
+
+package app
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+)
+
+func TestBenchmarkSyntheticSong(t *testing.T) {
+	dir := t.TempDir()
+	samples := make([]int16, 3*config.SampleRate)
+	for i := range samples {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*220*float64(i)/config.SampleRate))
+	}
+	if err := audio.WriteWAV(filepath.Join(dir, "song.wav"), samples, config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+
+	stages, err := runBenchmark(dir, audio.ModeFullMix)
+	if err != nil {
+		t.Fatalf("runBenchmark: %v", err)
+	}
+	if len(stages) != 3 {
+		t.Fatalf("runBenchmark returned %d stages, want 3", len(stages))
+	}
+	for _, st := range stages {
+		if st.calls <= 0 || st.total <= 0 {
+			t.Errorf("stage %q: %d calls in %v, want non-zero timings", st.name, st.calls, st.total)
+		}
+	}
+}
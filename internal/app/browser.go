@@ -0,0 +1,276 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+openSongBrowser lists the songs folder and shows the song browser.
+
+Input:
+  - None
+
+Called by:
+  - New when no song was given on the command line
+  - handleStartScreenInput when L is pressed
+
+Task:
+  - Read the folder once instead of every frame
+
+Logic:
+ 1. config.ListSongs (on error: log it and show an empty list)
+ 2. Load each song's info.json tags and the genres they use
+ 3. Clear the filters and select the current song if it is listed
+ 4. Set state to StateSongBrowser
+
+Output:
+  - None (changes state)
+*/
+func (a *App) openSongBrowser() {
+	songs, err := config.ListSongs()
+	if err != nil {
+		logging.Warnf("Could not list songs: %v", err)
+	}
+	a.songs = songs
+	a.songInfos = make(map[string]config.SongInfo, len(songs))
+	for _, s := range songs {
+		info, err := config.LoadSongInfo(filepath.Join(config.SongsDir, s))
+		if err != nil {
+			logging.Warnf("Ignoring info.json of %s: %v", s, err)
+		}
+		a.songInfos[s] = info
+	}
+	a.genres = config.SongGenres(a.songInfos)
+	a.songFilter = ""
+	a.genreFilter = ""
+	a.songSel = 0
+	for i, s := range songs {
+		if a.songDir != "" && s == filepath.Base(a.songDir) {
+			a.songSel = i
+		}
+	}
+	a.state = StateSongBrowser
+}
+
+/*
+browserSongs returns the songs the browser lists with the current filters.
+
+Input:
+  - None (reads songs, songInfos, songFilter and genreFilter)
+
+Called by:
+  - handleSongBrowserInput, drawSongBrowser
+
+Task:
+  - Combine the genre filter with the typed search
+
+Logic:
+ 1. With a genre selected, skip songs tagged with another genre (case-insensitive)
+ 2. Keep songs whose folder name or artist/title label contains the query
+    (config.FilterSongs)
+
+Output:
+  - []string: Matching song folder names, in folder order
+*/
+func (a *App) browserSongs() []string {
+	var out []string
+	for _, s := range a.songs {
+		info := a.songInfos[s]
+		if a.genreFilter != "" && !strings.EqualFold(info.Genre, a.genreFilter) {
+			continue
+		}
+		if len(config.FilterSongs([]string{s, info.Label(s)}, a.songFilter)) > 0 {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+/*
+cycleGenre selects the next genre in the song browser's genre filter.
+
+Input:
+  - None
+
+Called by:
+  - handleSongBrowserInput on Tab or a click on the genre selector
+
+Task:
+  - Step through "All" and every genre in use
+
+Logic:
+ 1. Order is "" (all), then genres in turn, then back to ""
+ 2. Reset the selection to the first match
+
+Output:
+  - None (changes genreFilter)
+*/
+func (a *App) cycleGenre() {
+	next := ""
+	if i := slices.Index(a.genres, a.genreFilter); i+1 < len(a.genres) {
+		next = a.genres[i+1]
+	}
+	a.genreFilter = next
+	a.songSel = 0
+}
+
+/*
+handleSongBrowserInput processes keyboard input in the song browser.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateSongBrowser
+
+Task:
+  - Pick a song
+
+Logic:
+ 1. Typed characters extend the filter, Backspace removes the last one
+    (either resets the selection to the first match); Tab or a click on the
+    genre selector cycles the genre filter
+ 2. Up/Down: move the selection within the filtered list
+ 3. Enter: open the selected song's start screen with pickSong
+ 4. Escape: back to the start screen when a song is already loaded
+
+Output:
+  - None (may change state)
+*/
+func (a *App) handleSongBrowserInput() {
+	if chars := ebiten.AppendInputChars(nil); len(chars) > 0 {
+		a.songFilter += string(chars)
+		a.songSel = 0
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && a.songFilter != "" {
+		r := []rune(a.songFilter)
+		a.songFilter = string(r[:len(r)-1])
+		a.songSel = 0
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		a.cycleGenre()
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		gx, gy, gw, gh := ui.GenreSelectorRect(config.ScreenW)
+		if ui.InRect(mx, my, gx, gy, gw, gh) {
+			a.cycleGenre()
+		}
+	}
+
+	songs := a.browserSongs()
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) && a.songSel > 0 {
+		a.songSel--
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) && a.songSel < len(songs)-1 {
+		a.songSel++
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		if a.songSel < len(songs) {
+			a.pickSong(filepath.Join(config.SongsDir, songs[a.songSel]))
+		}
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) && a.songDir != "" {
+		a.state = StateStartScreen
+	}
+}
+
+/*
+pickSong opens a song the user chose in the song browser.
+
+Input:
+  - songDir: string - Path to the song folder (e.g., "songs/MySong")
+
+Called by:
+  - handleSongBrowserInput on Enter
+
+Task:
+  - Keep the setlist's "next song" after the song actually played
+
+Logic:
+ 1. selectSong(songDir)
+ 2. setlistIndex = songDir's position in the setlist, -1 when it is not
+    listed (the setlist then continues from its first song)
+
+Output:
+  - None (changes state)
+*/
+func (a *App) pickSong(songDir string) {
+	a.selectSong(songDir)
+	a.setlistIndex = slices.Index(a.setlist, songDir)
+}
+
+/*
+selectSong switches the app to another song folder.
+
+Input:
+  - songDir: string - Path to the song folder (e.g., "songs/MySong")
+
+Called by:
+  - pickSong
+  - playNextSetlistSong
+
+Task:
+  - Load the per-song state New would have loaded
+
+Logic:
+ 1. Store songDir and reset the practice loop
+ 2. Load the song's best score, difficulty rating, saved loop, settings and notes
+ 3. Retitle the window and show the start screen
+
+Output:
+  - None (changes state)
+*/
+func (a *App) selectSong(songDir string) {
+	a.songDir = songDir
+	a.loopStart, a.loopEnd = 0, 0
+	a.refreshBestScore()
+	a.refreshDifficulty()
+	a.loadLoop()
+	a.loadSongSettings()
+	a.notes = config.LoadNotes(songDir)
+	ebiten.SetWindowTitle("SingAssist - " + a.SongName())
+	a.state = StateStartScreen
+}
+
+/*
+drawSongBrowser renders the song browser.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateSongBrowser
+
+Task:
+  - Show the filtered list and the query being typed
+
+Logic:
+ 1. ui.DrawSongBrowser with the artist/title labels of browserSongs, the
+    query for its search field and the selected genre
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawSongBrowser(screen *ebiten.Image, sw, sh int) {
+	songs := a.browserSongs()
+	labels := make([]string, len(songs))
+	for i, s := range songs {
+		labels[i] = a.songInfos[s].Label(s)
+	}
+	ui.DrawSongBrowser(screen, labels, a.songFilter, a.genreFilter, a.songSel, sw, sh)
+}
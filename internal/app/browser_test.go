@@ -0,0 +1,49 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestBrowserSearchFiltersAsTyped(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for name, info := range map[string]config.SongInfo{
+		"Kasoor":          {Title: "Kasoor", Artist: "Prateek Kuhad", Genre: "Indie"},
+		"Tum Hi Ho":       {Title: "Tum Hi Ho", Artist: "Arijit Singh", Genre: "Bollywood"},
+		"Aaoge Tum Kabhi": {Genre: "Indie"},
+	} {
+		dir := filepath.Join(config.SongsDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := config.SaveSongInfo(dir, info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := &App{}
+	a.openSongBrowser()
+	all := []string{"Aaoge Tum Kabhi", "Kasoor", "Tum Hi Ho"}
+	tests := []struct {
+		query, genre string
+		want         []string
+	}{
+		{"", "", all},
+		{"tum", "", []string{"Aaoge Tum Kabhi", "Tum Hi Ho"}},
+		{"TUM", "", []string{"Aaoge Tum Kabhi", "Tum Hi Ho"}},
+		{"arijit", "", []string{"Tum Hi Ho"}},
+		{"tum", "indie", []string{"Aaoge Tum Kabhi"}},
+		{"zzz", "", nil},
+		{"", "", all},
+	}
+	for _, tt := range tests {
+		a.songFilter, a.genreFilter = tt.query, tt.genre
+		if got := a.browserSongs(); !slices.Equal(got, tt.want) {
+			t.Errorf("query %q, genre %q: browser lists %q, want %q", tt.query, tt.genre, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"singAssist/internal/logging"
+	"singAssist/internal/scoring"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+openSessionCompare switches from the results screen to the A/B comparison.
+
+Input:
+  - None
+
+Called by:
+  - handleResultsInput on C
+
+Task:
+  - Compare today's run against an earlier one
+
+Logic:
+ 1. scoring.ListSessions for the song; with none, show a message and stay
+ 2. B = the newest run, A = the one before it (the same run if there is only one)
+ 3. Load both trails, rewind the clock and set state to StateSessionCompare
+
+Output:
+  - None (changes state)
+*/
+func (a *App) openSessionCompare() {
+	sessions, err := scoring.ListSessions(a.songDir)
+	if err != nil {
+		logging.Warnf("Could not list sessions: %v", err)
+	}
+	if len(sessions) == 0 {
+		a.message = "No saved sessions to compare"
+		return
+	}
+
+	a.message = ""
+	a.compareSessions = sessions
+	a.compareB = len(sessions) - 1
+	a.compareA = max(0, a.compareB-1)
+	a.loadComparedSessions()
+	a.compareClock = 0
+	a.comparePaused = false
+	a.state = StateSessionCompare
+}
+
+/*
+loadComparedSessions reads the trails of the two selected runs.
+
+Input:
+  - None
+
+Called by:
+  - openSessionCompare, handleSessionCompareInput after a selection change
+
+Task:
+  - Load trails only for the runs on screen
+
+Logic:
+ 1. scoring.LoadSessionPitch for compareA and compareB (an unreadable one is empty)
+
+Output:
+  - None (sets comparePitchA, comparePitchB)
+*/
+func (a *App) loadComparedSessions() {
+	load := func(i int) []float64 {
+		pitch, err := scoring.LoadSessionPitch(a.compareSessions[i].File)
+		if err != nil {
+			logging.Warnf("Could not read session: %v", err)
+		}
+		return pitch
+	}
+	a.comparePitchA = load(a.compareA)
+	a.comparePitchB = load(a.compareB)
+}
+
+/*
+handleSessionCompareInput processes keyboard input in the comparison.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateSessionCompare
+
+Task:
+  - Pick the two runs and play them back side by side
+
+Logic:
+ 1. Left/Right: previous/next run as A; Up/Down: previous/next run as B (wrapping)
+ 2. Space: pause or resume; Backspace: back to the start
+ 3. Escape: back to the results screen
+ 4. Advance the clock by one tick (1/TPS) unless paused; pause at the end of
+    the longer trail
+
+Output:
+  - None (updates the comparison, may change state)
+*/
+func (a *App) handleSessionCompareInput() {
+	n := len(a.compareSessions)
+	changed := true
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		a.compareA = (a.compareA + n - 1) % n
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		a.compareA = (a.compareA + 1) % n
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		a.compareB = (a.compareB + n - 1) % n
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		a.compareB = (a.compareB + 1) % n
+	default:
+		changed = false
+	}
+	if changed {
+		a.loadComparedSessions()
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		a.comparePaused = !a.comparePaused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		a.compareClock = 0
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		a.state = StateResults
+		return
+	}
+
+	if !a.comparePaused {
+		a.compareClock += time.Second / time.Duration(ebiten.TPS())
+		end := max(trailEnd(a.comparePitchA), trailEnd(a.comparePitchB))
+		if a.compareClock > end {
+			a.compareClock = end
+			a.comparePaused = true
+		}
+	}
+}
+
+/*
+trailEnd returns the time of the last point of a pitch trail.
+
+Input:
+  - trail: []float64 - Pairs [timeMs, pitch, ...]
+
+Called by:
+  - handleSessionCompareInput
+
+Task:
+  - Know when the comparison has run out
+
+Logic:
+ 1. Largest timestamp (trails may jump back after seeks)
+
+Output:
+  - time.Duration: End of the trail, 0 if empty
+*/
+func trailEnd(trail []float64) time.Duration {
+	end := 0.0
+	for i := 0; i+1 < len(trail); i += 2 {
+		end = max(end, trail[i])
+	}
+	return time.Duration(end * float64(time.Millisecond))
+}
+
+/*
+drawSessionCompare renders the A/B comparison.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateSessionCompare
+
+Task:
+  - Show both runs and which ones they are
+
+Logic:
+ 1. ui.DrawSessionCompare at the comparison clock, with the song's hitTolerance
+ 2. Label A and B with date, mode and score, each in its trail colour
+ 3. Show the clock and key hints
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawSessionCompare(screen *ebiten.Image, sw, sh int) {
+	ui.DrawSessionCompare(screen, a.comparePitchA, a.comparePitchB, a.compareSong, a.compareClock.Seconds(), sw, sh, a.hitTolerance)
+
+	label := func(i int) string {
+		s := a.compareSessions[i]
+		return fmt.Sprintf("%s  %s  %.1f%%  (%d/%d)", s.PlayedAt.Format("2006-01-02 15:04"), s.Mode, s.Score, i+1, len(a.compareSessions))
+	}
+	ui.DrawTextAt(screen, "A ←/→: "+label(a.compareA), 10, 10, ui.ActiveTheme.Highlight)
+	ui.DrawTextAt(screen, "B ↑/↓: "+label(a.compareB), 10, 26, ui.ActiveTheme.Info)
+
+	state := ""
+	if a.comparePaused {
+		state = "  (paused)"
+	}
+	ui.DrawTextAt(screen, fmt.Sprintf("%s%s", a.compareClock.Truncate(100*time.Millisecond), state), 10, 42, ui.ActiveTheme.HUDText)
+	ui.DrawTextAt(screen, "SPACE: Pause  BACKSPACE: Restart  ESC: Back to results", 10, sh-20, ui.ActiveTheme.HUDText)
+}
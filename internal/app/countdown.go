@@ -0,0 +1,103 @@
+package app
+
+import (
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	// countdownLength is how long "3… 2… 1…" runs before playback.
+	countdownLength = 3 * time.Second
+
+	// countdownGoHold is how long "GO!" stays up before playback starts.
+	countdownGoHold = 400 * time.Millisecond
+)
+
+/*
+handleCountdownInput advances the pre-song countdown.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateCountdown
+
+Task:
+  - Start the song when the countdown is over
+
+Logic:
+ 1. Escape: abandon the run and go back to the menu
+ 2. Once countdownEnd plus countdownGoHold has passed: startPlayback
+
+Output:
+  - None (may change state)
+*/
+func (a *App) handleCountdownInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		a.exitToMenu()
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.state == StateCountdown && time.Now().After(a.countdownEnd.Add(countdownGoHold)) {
+		a.startPlayback()
+	}
+}
+
+/*
+startPlayback starts the song clock and switches to StatePlaying.
+
+Input:
+  - None (caller holds mu)
+
+Called by:
+  - handleCountdownInput when the countdown ends
+
+Task:
+  - Begin playback only after the user had time to prepare
+
+Logic:
+ 1. Play the audio player, or start the local clock for a reference melody
+    or a freestyle session, range test or interval quiz
+ 2. Set state to StatePlaying (micLoop starts recording from here)
+
+Output:
+  - None (starts playback)
+*/
+func (a *App) startPlayback() {
+	if a.audioPlayer != nil {
+		a.audioPlayer.Play()
+	} else if len(a.songPitch) > 0 || a.mode == audio.ModeFreestyle || a.mode == audio.ModeRangeTest || a.mode == audio.ModeIntervalQuiz {
+		a.refStart = time.Now()
+	}
+	a.state = StatePlaying
+}
+
+/*
+drawCountdown renders the countdown screen.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateCountdown (mu held)
+
+Task:
+  - Show the time left before the song starts
+
+Logic:
+ 1. ui.DrawCountdown with the time until countdownEnd
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawCountdown(screen *ebiten.Image, sw, sh int) {
+	ui.DrawCountdown(screen, time.Until(a.countdownEnd), sw, sh)
+}
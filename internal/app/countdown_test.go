@@ -0,0 +1,41 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"singAssist/internal/audio"
+)
+
+func TestCountdownRecordsNothingUntilGo(t *testing.T) {
+	mic := &FakeMic{Pitches: []float64{220, 230, 240}}
+	a := &App{
+		mic:           mic,
+		state:         StateCountdown,
+		mode:          audio.ModeFullMix,
+		audioPlayer:   newTestPlayer(),
+		playbackSpeed: 1,
+		countdownEnd:  time.Now().Add(countdownLength),
+	}
+
+	a.micLoop()
+	if len(a.userPitch) != 0 || len(a.sessionPitch) != 0 {
+		t.Errorf("recorded %v during the countdown, want nothing", a.userPitch)
+	}
+	a.handleCountdownInput()
+	if a.state != StateCountdown || a.audioPlayer.IsPlaying() {
+		t.Fatalf("countdown ended early: state %v, playing %v", a.state, a.audioPlayer.IsPlaying())
+	}
+
+	a.countdownEnd = time.Now().Add(-countdownGoHold - time.Millisecond)
+	a.handleCountdownInput()
+	if a.state != StatePlaying || !a.audioPlayer.IsPlaying() {
+		t.Fatalf("after GO: state %v, playing %v; want StatePlaying with the song playing", a.state, a.audioPlayer.IsPlaying())
+	}
+
+	mic.Pitches, mic.next = []float64{250, 260}, 0
+	a.micLoop()
+	if got := trailPitches(a.userPitch); !equalPitches(got, []float64{250, 260}) {
+		t.Errorf("recorded %v after GO, want [250 260]", got)
+	}
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+loadDevices lists the input devices and restores the saved choice.
+
+Input:
+  - None
+
+Called by:
+  - New
+
+Task:
+  - Fill the start screen device selector
+
+Logic:
+ 1. audio.ListInputDevices (on error: only the system default is offered)
+ 2. Select the saved device from config.LoadInputDevice, matching by name
+    first and by index if no name matches; -1 (default) otherwise
+
+Output:
+  - None (sets devices and deviceSel)
+*/
+func (a *App) loadDevices() {
+	a.deviceSel = -1
+	devices, err := audio.ListInputDevices()
+	if err != nil {
+		logging.Warnf("Could not list input devices: %v", err)
+		return
+	}
+	a.devices = devices
+
+	saved, ok := config.LoadInputDevice()
+	if !ok {
+		return
+	}
+	for i, d := range devices {
+		if d.Name == saved.Name {
+			a.deviceSel = i
+			return
+		}
+	}
+	for i, d := range devices {
+		if d.Index == saved.Index {
+			a.deviceSel = i
+			return
+		}
+	}
+}
+
+/*
+handleDeviceInput cycles the microphone on the start screen.
+
+Input:
+  - None
+
+Called by:
+  - handleStartScreenInput
+
+Task:
+  - Pick the input device with the arrow keys
+
+Logic:
+ 1. Left/Right: previous/next device, wrapping through "Default" (-1)
+ 2. Save the choice with config.SaveInputDevice after a change
+
+Output:
+  - None (updates deviceSel, writes DeviceFile)
+*/
+func (a *App) handleDeviceInput() {
+	step := 0
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+		step = -1
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		step = 1
+	}
+	if step == 0 || len(a.devices) == 0 {
+		return
+	}
+
+	n := len(a.devices) + 1
+	a.deviceSel = (a.deviceSel+1+step+n)%n - 1
+
+	var saved config.InputDevice
+	if a.deviceSel >= 0 {
+		d := a.devices[a.deviceSel]
+		saved = config.InputDevice{Name: d.Name, Index: d.Index}
+	}
+	if err := config.SaveInputDevice(saved); err != nil {
+		logging.Warnf("Could not save input device: %v", err)
+	}
+}
+
+/*
+newMic creates a microphone handler on the selected device.
+
+Input:
+  - duet: bool - Capture two singers on a stereo input
+
+Called by:
+  - startGame, startLatencyCalibration
+
+Task:
+  - One place that applies the device selection
+
+Logic:
+ 1. audio.NewDuetMicHandler or audio.NewMicHandler
+ 2. Set Device to the selected device's PortAudio index (-1 for the default)
+ 3. Set SensitivityMultiplier to the slider's value, so it applies even when
+    config/sensitivity.json could not be saved
+
+Output:
+  - *audio.MicHandler: Handler ready for Start()
+*/
+func (a *App) newMic(duet bool) *audio.MicHandler {
+	mic := audio.NewMicHandler()
+	if duet {
+		mic = audio.NewDuetMicHandler()
+	}
+	if a.deviceSel >= 0 {
+		mic.Device = a.devices[a.deviceSel].Index
+	}
+	mic.SensitivityMultiplier = a.sensitivity
+	return mic
+}
+
+/*
+drawDeviceSelector renders the microphone selector on the start screen.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+
+Called by:
+  - Draw when state is StateStartScreen
+
+Task:
+  - Show which input will be used and its neighbours in the list
+
+Logic:
+ 1. Names are "Default" followed by the device names
+ 2. ui.DrawDeviceSelector with the selection shifted past "Default"
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawDeviceSelector(screen *ebiten.Image, sw int) {
+	names := make([]string, 0, len(a.devices)+1)
+	names = append(names, "Default")
+	for _, d := range a.devices {
+		names = append(names, d.Name)
+	}
+	ui.DrawDeviceSelector(screen, names, a.deviceSel+1, sw)
+}
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"time"
+
+	"singAssist/internal/config"
+)
+
+// echoGap is the silence added before and after an echo phrase so each loop
+// starts with a short lead-in.
+const echoGap = time.Second
+
+/*
+playbackPos returns the current session position and whether time is running.
+
+Input:
+  - None
+
+Called by:
+  - micLoop, Draw, drawPlayingMode
+
+Task:
+  - Give one time source for drawing and recording, whether it is the
+    audio player, the local echo clock or the reference-melody clock
+
+Logic:
+ 1. If the echo clock is running: time since echoStart modulo echoLoop
+ 2. Else if there is an audio player: its position in song time (songPosition) and IsPlaying
+ 3. Else if a reference MIDI melody is playing: time since refStart
+ 4. Else: (0, false)
+
+Output:
+  - time.Duration: Position within the song (or echo phrase)
+  - bool: true if playback is advancing
+*/
+func (a *App) playbackPos() (time.Duration, bool) {
+	if !a.echoStart.IsZero() {
+		d := time.Since(a.echoStart)
+		if a.echoLoop > 0 {
+			d %= a.echoLoop
+		}
+		return d, true
+	}
+	if a.audioPlayer != nil {
+		return a.songPosition(), a.audioPlayer.IsPlaying()
+	}
+	if !a.refStart.IsZero() {
+		return time.Since(a.refStart), true
+	}
+	return 0, false
+}
+
+/*
+toggleEcho marks the start/end of a phrase to echo, or leaves echo practice.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput on the E key
+
+Task:
+  - Drive the "replay with my pitch as the reference" practice mode
+
+Logic:
+ 1. If echo practice is running: stop it, restore the song reference, keep paused
+ 2. Else if no capture is in progress: remember the current position as phrase start
+ 3. Else: end the capture, build the phrase with phraseFromUserPitch and start echo:
+    - song audio is paused and songPitch is swapped for the phrase
+    - a local clock loops over [gap, phrase, gap]
+    - the user trail is cleared on each loop so every repetition is scored fresh
+
+Output:
+  - None (changes echo state and message)
+*/
+func (a *App) toggleEcho() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.echoStart.IsZero() {
+		a.stopEcho()
+		a.message = "Echo practice off (SPACE to resume)"
+		return
+	}
+
+	pos, _ := a.playbackPos()
+	if a.echoCaptureFrom < 0 {
+		a.echoCaptureFrom = float64(pos.Milliseconds())
+		a.message = "Capturing phrase... press E again to echo it"
+		return
+	}
+
+	phrase := phraseFromUserPitch(a.userPitch, a.echoCaptureFrom, float64(pos.Milliseconds()))
+	a.echoCaptureFrom = -1
+	if phrase == nil {
+		a.message = "Nothing sung to echo"
+		return
+	}
+
+	if a.audioPlayer != nil {
+		a.audioPlayer.Pause()
+	}
+	a.echoSavedPitch = a.songPitch
+	a.songPitch = phrase
+	a.echoLoop = time.Duration(len(phrase)) * 10 * time.Millisecond
+	a.echoStart = time.Now()
+	a.userPitch = make([]float64, 0)
+	a.formant1Pitch = make([]float64, 0)
+	a.message = "Echo: sing your phrase back (E to stop)"
+}
+
+/*
+phraseFromUserPitch turns recorded user pitch into a 10ms reference contour.
+
+Input:
+  - userPitch: []float64 - Recorded [timeMs, pitch, ...] pairs
+  - fromMs, toMs: float64 - Capture range (player positions)
+
+Called by:
+  - toggleEcho when a capture ends
+
+Task:
+  - Promote the user's phrase to the songPitch role
+
+Logic:
+ 1. Shift timestamps back by config.GetAudioLatencyMs() (as DrawUserPitch does)
+ 2. Lay pitches on a 10ms grid, holding each value until the next sample
+ 3. Pad echoGap of silence before and after
+ 4. Return nil if nothing voiced was captured
+
+Output:
+  - []float64: Reference pitch at 10ms intervals, or nil
+*/
+func phraseFromUserPitch(userPitch []float64, fromMs, toMs float64) []float64 {
+	if toMs <= fromMs {
+		return nil
+	}
+
+	gapFrames := int(echoGap / (10 * time.Millisecond))
+	bodyFrames := int((toMs-fromMs)/10) + 1
+	phrase := make([]float64, gapFrames*2+bodyFrames)
+
+	latencyMs := config.GetAudioLatencyMs()
+	voiced := false
+	for i := 0; i+3 < len(userPitch); i += 2 {
+		t := userPitch[i] - latencyMs
+		if t < fromMs || t > toMs {
+			continue
+		}
+		start := int((t-fromMs)/10) + gapFrames
+		end := int((userPitch[i+2]-latencyMs-fromMs)/10) + gapFrames
+		if end > gapFrames+bodyFrames {
+			end = gapFrames + bodyFrames
+		}
+		for j := start; j < end && j < len(phrase); j++ {
+			phrase[j] = userPitch[i+1]
+		}
+		if userPitch[i+1] > 10 {
+			voiced = true
+		}
+	}
+
+	if !voiced {
+		return nil
+	}
+	return phrase
+}
+
+/*
+stopEcho leaves echo practice if it is running.
+
+Input:
+  - None (caller holds mu)
+
+Called by:
+  - toggleEcho, retuneAnalysis, reanalyzeSong, finishSong
+
+Task:
+  - Put the song back as the reference
+
+Logic:
+ 1. Nothing if echo practice is off
+ 2. Restore songPitch from echoSavedPitch, stop the echo clock and clear the
+    trails sung against the phrase
+
+Output:
+  - None (changes echo state)
+*/
+func (a *App) stopEcho() {
+	if a.echoStart.IsZero() {
+		return
+	}
+	a.songPitch = a.echoSavedPitch
+	a.echoSavedPitch = nil
+	a.echoStart = time.Time{}
+	a.echoLoop = 0
+	a.userPitch = make([]float64, 0)
+	a.formant1Pitch = make([]float64, 0)
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"singAssist/internal/export"
+	"singAssist/internal/logging"
+)
+
+/*
+exportSessionImage saves the session's pitch comparison as a PNG.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput on Shift+S
+
+Task:
+  - Let users share a performance
+
+Logic:
+ 1. Copy sessionPitch and the real song pitch under mu, and take hitRule so
+    the colors follow the session's hit tolerance and octave setting
+ 2. Render with export.ExportPitchImage in a goroutine to songs/<name>/session_<timestamp>.png
+ 3. Report the saved path (or the error) in the status message
+
+Output:
+  - None (writes a file)
+*/
+func (a *App) exportSessionImage() {
+	a.mu.Lock()
+	song := a.songPitch
+	if a.echoSavedPitch != nil {
+		song = a.echoSavedPitch
+	}
+	song = slices.Clone(song)
+	user := slices.Clone(a.sessionPitch)
+	duration := a.songDuration.Seconds()
+	hit := a.hitRule()
+	a.mu.Unlock()
+
+	outPath := filepath.Join(a.songDir, fmt.Sprintf("session_%s.png", time.Now().Format("20060102_150405")))
+
+	go func() {
+		msg := "Saved " + outPath
+		if err := export.ExportPitchImage(user, song, duration, hit, outPath); err != nil {
+			logging.Errorf("Failed to export session image: %v", err)
+			msg = "Error: Failed to export session image"
+		} else {
+			logging.Infof("Saved session image to %s", outPath)
+		}
+
+		a.mu.Lock()
+		a.message = msg
+		a.mu.Unlock()
+	}()
+}
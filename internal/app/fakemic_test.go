@@ -0,0 +1,48 @@
+package app
+
+import (
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+)
+
+// FakeMic replays a recorded pitch sequence, one value per DetectPitchFromMic
+// call, and reports Done once every value was returned.
+type FakeMic struct {
+	Pitches []float64
+	next    int
+	stopped bool
+	pitch   float64
+	samples []float32
+}
+
+var _ audio.MicInput = (*FakeMic)(nil)
+
+func (f *FakeMic) Start() error  { return nil }
+func (f *FakeMic) Reopen() error { return nil }
+func (f *FakeMic) Stop()         { f.stopped = true }
+func (f *FakeMic) IsDone() bool  { return f.stopped || f.next >= len(f.Pitches) }
+
+func (f *FakeMic) Read() error { return nil }
+
+func (f *FakeMic) Calibrate(time.Duration) float64 { return 0 }
+
+func (f *FakeMic) DetectPitchFromMic(audio.Mode) (float64, float64) {
+	f.pitch = f.Pitches[f.next]
+	f.next++
+	return f.pitch, 0
+}
+
+func (f *FakeMic) CurrentPitch() float64             { return f.pitch }
+func (f *FakeMic) CurrentPitch2() float64            { return 0 }
+func (f *FakeMic) CurrentVibrato() audio.VibratoInfo { return audio.VibratoInfo{} }
+
+func (f *FakeMic) Samples() []float32 {
+	if f.samples == nil {
+		f.samples = make([]float32, config.BufferSize)
+	}
+	return f.samples
+}
+
+func (f *FakeMic) Delivered() []float32 { return f.Samples() }
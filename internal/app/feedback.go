@@ -0,0 +1,106 @@
+package app
+
+import (
+	"math"
+
+	"singAssist/internal/config"
+	"singAssist/internal/scoring"
+	"singAssist/internal/theory"
+	"singAssist/internal/ui"
+)
+
+/*
+lockCharge measures how long the user has held the note steadily in tune.
+
+Input:
+  - currTimeMs: float64 - Current playback position in milliseconds
+
+Called by:
+  - drawPlayingMode for the tuning-lock indicator
+
+Task:
+  - Compute the lock charge from recent userPitch stability
+
+Logic:
+ 1. Walk userPitch backwards from the newest sample
+ 2. Stop at the first sample that is silent or off by more than
+    config.LockToleranceCents (against the latency-compensated, transposed song pitch,
+    or the nearest semitone when the song is silent there)
+ 3. Charge = in-tune duration / config.LockChargeSec, clamped to 1
+ 4. A stale trail (newest sample >250ms old) gives 0
+
+Output:
+  - float64: Charge in [0, 1]; 1 means locked
+*/
+func (a *App) lockCharge(currTimeMs float64) float64 {
+	n := len(a.userPitch)
+	if n < 2 || currTimeMs-a.userPitch[n-2] > 250 {
+		return 0
+	}
+
+	newest := a.userPitch[n-2]
+	oldest := newest
+	latencyMs := config.GetAudioLatencyMs()
+	for i := n - 2; i >= 0; i -= 2 {
+		p := a.userPitch[i+1]
+		if p <= 10 {
+			break
+		}
+
+		cents := math.Abs(theory.CentsOffset(p))
+		sIdx := int((a.userPitch[i] - latencyMs) / 10)
+		if sIdx >= 0 && sIdx < len(a.songPitch) && a.songPitch[sIdx] > 10 {
+			ref := theory.Transpose(a.songPitch[sIdx], a.opts.TransposeSteps)
+			cents = theory.SemitoneDistance(p, ref, a.opts.IgnoreOctave) * 100
+		}
+		if cents > config.LockToleranceCents {
+			break
+		}
+		oldest = a.userPitch[i]
+	}
+
+	return math.Min(1, (newest-oldest)/1000/config.LockChargeSec)
+}
+
+/*
+visibleNoteBlocks segments the on-screen part of the song into note blocks.
+
+Input:
+  - currTime: float64 - Current playback time in seconds
+  - sw: int - Screen width
+
+Called by:
+  - drawPlayingMode when BlockView is enabled
+
+Task:
+  - Feed ui.DrawSongPitchBlocks from scoring.SegmentNotes
+
+Logic:
+ 1. Take the songPitch on screen at scrollSpeed (now line at 20% of sw),
+    plus a second on both sides
+ 2. Segment with a 50ms minimum note length
+ 3. Convert frame indices to seconds
+
+Output:
+  - []ui.NoteBlock: Blocks to draw
+*/
+func (a *App) visibleNoteBlocks(currTime float64, sw int) []ui.NoteBlock {
+	back := 0.2*float64(sw)/a.scrollSpeed + 1
+	ahead := 0.8*float64(sw)/a.scrollSpeed + 1
+	from := max(0, int((currTime-back)*100))
+	to := min(len(a.songPitch), int((currTime+ahead)*100))
+	if from >= to {
+		return nil
+	}
+
+	segs := scoring.SegmentNotes(a.songPitch[from:to], 5)
+	blocks := make([]ui.NoteBlock, len(segs))
+	for i, seg := range segs {
+		blocks[i] = ui.NoteBlock{
+			Start: float64(from+seg.StartFrame) / 100,
+			End:   float64(from+seg.EndFrame) / 100,
+			Midi:  seg.Midi,
+		}
+	}
+	return blocks
+}
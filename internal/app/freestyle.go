@@ -0,0 +1,162 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/scoring"
+	"singAssist/internal/theory"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+handleFreestyleInput processes keyboard input during a freestyle session.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput in ModeFreestyle
+
+Task:
+  - Freestyle has no song, so most playback keys do nothing
+
+Logic:
+ 1. Fullscreen, Grid, metronome and mic monitoring keys (config.Keys) work as in playback
+ 2. Exit key: finish the session and show its overview once it has started,
+    otherwise (still loading) exit to the menu
+
+Output:
+  - None (may change state)
+*/
+func (a *App) handleFreestyleInput() {
+	if inpututil.IsKeyJustPressed(config.Keys.Fullscreen) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
+	if inpututil.IsKeyJustPressed(config.Keys.Grid) {
+		a.showGrid = !a.showGrid
+	}
+	a.handleMetronomeInput()
+	if inpututil.IsKeyJustPressed(config.Keys.MicMonitor) {
+		a.toggleMicMonitor()
+	}
+
+	if inpututil.IsKeyJustPressed(config.Keys.Exit) {
+		a.mu.Lock()
+		_, running := a.playbackPos()
+		a.mu.Unlock()
+		if running {
+			a.finishFreestyle()
+		} else {
+			a.exitToMenu()
+		}
+	}
+}
+
+/*
+finishFreestyle saves the freestyle pitch journal and shows the results screen.
+
+Input:
+  - None
+
+Called by:
+  - handleFreestyleInput on the Exit key
+
+Task:
+  - Keep the session for later review; nothing is scored
+
+Logic:
+ 1. Copy the unpruned sessionPitch under mu
+ 2. Save it with scoring.SaveFreestyle (freestyle_<timestamp>.json)
+ 3. Show the results screen with a single journal panel
+
+Output:
+  - None (writes to disk, changes state)
+*/
+func (a *App) finishFreestyle() {
+	a.mu.Lock()
+	journal := slices.Clone(a.sessionPitch)
+	a.mu.Unlock()
+
+	if path, err := scoring.SaveFreestyle(a.songDir, journal, time.Now()); err != nil {
+		logging.Warnf("Could not save freestyle session: %v", err)
+	} else {
+		logging.Infof("Saved freestyle session to %s", path)
+	}
+
+	a.showResults([]ui.ResultsPanel{{Label: "Freestyle", Journal: journal}})
+}
+
+/*
+drawFreestyleMode renders the modes that have no song to follow.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw in ModeFreestyle, and in ModeNoAudio without a reference melody
+
+Task:
+  - Display current pitch with visual feedback
+  - Freestyle: scroll the user's own trail as a pitch journal
+
+Logic:
+ 1. Get current mic pitch and convert it to a note name
+ 2. Display pitch info text
+ 3. Freestyle: move the "now" line to the right edge, draw the semitone grid
+    (if shown) and the last MaxUserPitchHistory seconds of userPitch behind it
+ 4. If pitch detected: draw pitch marker
+ 5. Show key hints, the metronome tempo and the no-audio reference tone
+    while they are on
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawFreestyleMode(screen *ebiten.Image, sw, sh int) {
+	pitch := 0.0
+	if a.mic != nil {
+		pitch = a.mic.CurrentPitch()
+	}
+	freestyle := a.mode == audio.ModeFreestyle
+
+	caption := "No audio playback - practice mode"
+	if freestyle {
+		caption = "Freestyle - no reference, just sing"
+	}
+	playNote, _ := theory.FreqToNote(pitch)
+	stats := fmt.Sprintf("YOUR PITCH: %-4s (%.0f Hz)\n\n%s", playNote, pitch, caption)
+	ui.DrawTextAt(screen, stats, 10, 10, ui.ActiveTheme.HUDText)
+
+	vis := ui.NewPitchVisualizer(sw, sh)
+	if freestyle {
+		vis.OffsetX = float64(sw) - 60
+		if a.showGrid {
+			ui.DrawSemitoneGrid(screen, vis, sh)
+		}
+		if pos, running := a.playbackPos(); running {
+			now := float64(pos.Milliseconds())
+			vis.DrawPitchJournal(screen, a.userPitch, now-config.MaxUserPitchHistory*1000, now, 40, vis.OffsetX)
+		}
+		vis.DrawNowLine(screen, sh, false)
+	}
+	if pitch > 10 {
+		vis.DrawCurrentPitch(screen, pitch)
+	}
+
+	a.drawMetronome(screen, sw)
+	a.drawReferenceTone(screen, sw)
+	a.drawMicMonitor(screen, sw)
+	if freestyle {
+		ui.DrawTextAt(screen, "N: Grid  M: Click  V: Monitor  ESC: Finish", 10, sh-20, ui.ActiveTheme.HUDText)
+	} else {
+		ui.DrawTextAt(screen, "T: Reference tone  +/-: Tone pitch  ESC: Exit", 10, sh-20, ui.ActiveTheme.HUDText)
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/scoring"
+)
+
+func TestFreestyleRecordsWithoutReference(t *testing.T) {
+	t.Chdir(t.TempDir())
+	songDir, err := config.EnsureSongDir("Freestyle")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mic := &FakeMic{Pitches: []float64{196, 0, 247, 262}}
+	a := newMicLoopApp(mic)
+	a.mode = audio.ModeFreestyle
+	a.songDir = songDir
+	a.micLoop()
+
+	if len(a.songPitch) != 0 {
+		t.Fatalf("freestyle session has a reference of %d frames", len(a.songPitch))
+	}
+	if got := trailPitches(a.sessionPitch); !equalPitches(got, mic.Pitches) {
+		t.Fatalf("sessionPitch pitches = %v, want %v", got, mic.Pitches)
+	}
+	journal := append([]float64(nil), a.sessionPitch...)
+
+	a.finishFreestyle()
+	if a.state != StateResults || len(a.resultPanels) != 1 {
+		t.Fatalf("state %v with %d panels, want the results screen with one panel", a.state, len(a.resultPanels))
+	}
+	p := a.resultPanels[0]
+	if p.Score != 0 || p.TotalFrames != 0 || !equalPitches(p.Journal, journal) {
+		t.Errorf("panel = score %v over %d frames, journal %v; want no score and the recorded journal", p.Score, p.TotalFrames, p.Journal)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(songDir, "freestyle_*.json"))
+	if len(files) != 1 {
+		t.Fatalf("found %d freestyle files, want 1", len(files))
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved scoring.FreestyleSession
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("decoding %s: %v", files[0], err)
+	}
+	if !equalPitches(saved.UserPitch, journal) {
+		t.Errorf("saved journal = %v, want %v", saved.UserPitch, journal)
+	}
+}
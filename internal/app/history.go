@@ -0,0 +1,148 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/scoring"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// historyRows is how many recent runs the history screen lists.
+const historyRows = 25
+
+/*
+recordHistory appends the finished session to the history log.
+
+Input:
+  - None (reads the session via sessionResults)
+
+Called by:
+  - exitToMenu before cleanup discards the session
+
+Task:
+  - Track progress across days and weeks
+
+Logic:
+ 1. Score the session with sessionResults (one result per duet singer)
+ 2. Skip results with no scorable frames (no-audio mode without a melody, quit during calibration)
+ 3. Build a HistoryEntry from each result
+ 4. Append it to config.HistoryPath(), logging any error
+
+Output:
+  - None (writes to disk)
+*/
+func (a *App) recordHistory() {
+	for _, r := range a.sessionResults() {
+		if r.TotalFrames == 0 {
+			continue
+		}
+
+		e := scoring.HistoryEntry{
+			PlayedAt: r.PlayedAt,
+			Song:     r.SongName,
+			Mode:     r.Mode,
+			Score:    r.Score,
+			Seconds:  time.Since(a.sessionStart).Seconds(),
+			Singer:   r.Singer,
+		}
+		if err := scoring.AppendHistory(config.HistoryPath(), e); err != nil {
+			logging.Warnf("Could not save practice history: %v", err)
+		}
+	}
+}
+
+/*
+openHistory loads recent runs and switches to the history screen.
+
+Input:
+  - None
+
+Called by:
+  - handleStartScreenInput when H is pressed
+
+Task:
+  - Read the log once instead of every frame
+
+Logic:
+ 1. LoadHistory with historyRows
+ 2. On error: log it and show an empty list
+ 3. Set state to StateHistory
+
+Output:
+  - None (changes state)
+*/
+func (a *App) openHistory() {
+	entries, err := scoring.LoadHistory(config.HistoryPath(), historyRows)
+	if err != nil {
+		logging.Warnf("Could not read practice history: %v", err)
+	}
+	a.history = entries
+	a.state = StateHistory
+}
+
+/*
+handleHistoryInput processes keyboard input on the history screen.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateHistory
+
+Task:
+  - Return to the start screen
+
+Logic:
+ 1. H or Escape: back to StateStartScreen
+
+Output:
+  - None (changes state)
+*/
+func (a *App) handleHistoryInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		a.state = StateStartScreen
+	}
+}
+
+/*
+drawHistory renders the recent-runs list.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateHistory
+
+Task:
+  - Show date, song, mode and score of each run
+
+Logic:
+ 1. Format each entry as one ui.HistoryRow (duet runs show the singer, e.g. "duet #2")
+ 2. Call ui.DrawHistory
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawHistory(screen *ebiten.Image, sw, sh int) {
+	rows := make([]ui.HistoryRow, len(a.history))
+	for i, e := range a.history {
+		mode := e.Mode
+		if e.Singer > 0 {
+			mode = fmt.Sprintf("%s #%d", e.Mode, e.Singer)
+		}
+		rows[i] = ui.HistoryRow{
+			When:  e.PlayedAt.Local().Format("2006-01-02 15:04"),
+			Song:  e.Song,
+			Mode:  mode,
+			Score: e.Score,
+		}
+	}
+	ui.DrawHistory(screen, rows, sw, sh)
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/latency"
+	"singAssist/internal/logging"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// latencyTestDuration is how long the click track plays.
+const latencyTestDuration = 8 * time.Second
+
+/*
+startLatencyCalibration opens the mic and runs the click test.
+
+Input:
+  - None
+
+Called by:
+  - handleStartScreenInput when Calibrate Latency is clicked
+
+Task:
+  - Enter StateLatencyCalibration and start the measurement
+
+Logic:
+ 1. Show the current latency and switch state
+ 2. Start a microphone handler; on failure show the error and stop
+ 3. Run runLatencyCalibration in a goroutine
+
+Output:
+  - None (changes state)
+*/
+func (a *App) startLatencyCalibration() {
+	a.state = StateLatencyCalibration
+	a.latencyDone = false
+	a.message = fmt.Sprintf("Measuring... (current: %.0f ms)", config.GetAudioLatencyMs())
+
+	mic := a.newMic(false)
+	if err := mic.Start(); err != nil {
+		logging.Errorf("Failed to start microphone: %v", err)
+		a.message = "Error: Failed to start microphone"
+		a.latencyDone = true
+		return
+	}
+
+	go a.runLatencyCalibration(mic)
+}
+
+/*
+runLatencyCalibration plays the click track and saves the measured latency.
+
+Input:
+  - mic: audio.MicInput - Started microphone (stopped on return)
+
+Called by:
+  - startLatencyCalibration (as goroutine)
+
+Task:
+  - Measure and persist the latency
+
+Logic:
+ 1. Build a player for latency.ClickPCM
+ 2. latency.Measure, then stop the mic and close the player
+ 3. On success: config.SaveAudioLatencyMs and show the result
+ 4. On failure: show the error (the previous latency stays in use)
+ 5. Mark the screen done under mu
+
+Output:
+  - None (updates message, writes config/latency.json)
+*/
+func (a *App) runLatencyCalibration(mic audio.MicInput) {
+	defer mic.Stop()
+
+	msg := ""
+	player, err := audio.AudioContext.NewPlayer(bytes.NewReader(latency.ClickPCM(latencyTestDuration)))
+	if err == nil {
+		var ms float64
+		ms, err = latency.Measure(mic, player, latencyTestDuration)
+		player.Close()
+		if err == nil {
+			msg = fmt.Sprintf("Latency: %.0f ms (saved)", ms)
+			if saveErr := config.SaveAudioLatencyMs(ms); saveErr != nil {
+				msg = fmt.Sprintf("Latency: %.0f ms (could not save: %v)", ms, saveErr)
+			}
+			logging.Infof("Measured audio latency %.0f ms", ms)
+		}
+	}
+	if err != nil {
+		logging.Warnf("Latency calibration failed: %v", err)
+		msg = "Error: " + err.Error()
+	}
+
+	a.mu.Lock()
+	a.message = msg
+	a.latencyDone = true
+	a.mu.Unlock()
+}
+
+/*
+handleLatencyInput processes keyboard input on the latency screen.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateLatencyCalibration
+
+Task:
+  - Return to the menu once the test has finished
+
+Logic:
+ 1. Ignore input while measuring (the goroutine owns the mic)
+ 2. Escape or Enter: clear the message and go back to StateStartScreen
+
+Output:
+  - None (changes state)
+*/
+func (a *App) handleLatencyInput() {
+	a.mu.Lock()
+	done := a.latencyDone
+	a.mu.Unlock()
+	if !done {
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		a.message = ""
+		a.state = StateStartScreen
+	}
+}
+
+/*
+drawLatencyCalibration renders the latency screen.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateLatencyCalibration
+
+Task:
+  - Show progress and the result
+
+Logic:
+ 1. Read message and latencyDone under mu
+ 2. Call ui.DrawLatencyCalibration
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawLatencyCalibration(screen *ebiten.Image, sw, sh int) {
+	a.mu.Lock()
+	msg, done := a.message, a.latencyDone
+	a.mu.Unlock()
+	ui.DrawLatencyCalibration(screen, msg, done, sw, sh)
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/scoring"
+	"singAssist/internal/theory"
+)
+
+/*
+LiveStatus is a snapshot of the running session for displays outside the window.
+
+Fields:
+  - Song: Song folder name
+  - Playing: A song is being sung (StatePlaying)
+  - MicPitch: Current mic pitch in Hz (0 = silence)
+  - MicNote: Note name of MicPitch with octave (e.g. "A4", "-" for silence)
+  - SongPitch: Target pitch in Hz at the current position (0 = silent)
+  - SongNote: Note name of SongPitch ("-" for silence)
+  - Cents: How far MicPitch is from SongPitch in cents (0 unless both are voiced)
+  - Hit: MicPitch hits the target by the session's rule (isHit)
+  - Score: Hit percentage of the session so far (0..100)
+*/
+type LiveStatus struct {
+	Song      string  `json:"song"`
+	Playing   bool    `json:"playing"`
+	MicPitch  float64 `json:"mic_pitch"`
+	MicNote   string  `json:"mic_note"`
+	SongPitch float64 `json:"song_pitch"`
+	SongNote  string  `json:"song_note"`
+	Cents     float64 `json:"cents"`
+	Hit       bool    `json:"hit"`
+	Score     float64 `json:"score"`
+}
+
+/*
+LiveStatus returns what the playing screen currently shows.
+
+Input:
+  - None (reads the mic, the song and sessionPitch under mu)
+
+Called by:
+  - web.StartHTTPHUD for each Server-Sent Event
+
+Task:
+  - Let the score HUD live on another screen (web.StartHTTPHUD)
+
+Logic:
+ 1. Lock mu (micLoop appends concurrently)
+ 2. Mic pitch from CurrentPitch, target from songFreqAt at the playback position
+ 3. Note names from theory.FreqToNote; cents from the semitone difference;
+    Hit from isHit, as drawPlayingMode colors the note
+ 4. Report the cached liveScore; when sessionPitch has grown and the cache is
+    older than config.LiveScoreInterval, claim the update (liveScoreAt), copy
+    the run and score it with liveScorer after unlocking mu
+
+Output:
+  - LiveStatus: Current snapshot
+*/
+func (a *App) LiveStatus() LiveStatus {
+	a.mu.Lock()
+	st := LiveStatus{
+		Song:     a.SongName(),
+		Playing:  a.state == StatePlaying,
+		MicNote:  "-",
+		SongNote: "-",
+	}
+	if !st.Playing {
+		a.mu.Unlock()
+		return st
+	}
+
+	if a.mic != nil {
+		st.MicPitch = a.mic.CurrentPitch()
+	}
+	pos, _ := a.playbackPos()
+	if f := a.songFreqAt(int(pos.Seconds()*100), st.MicPitch); f > 10 {
+		st.SongPitch = f
+	}
+	if st.MicPitch > 10 {
+		note, octave := theory.FreqToNote(st.MicPitch)
+		st.MicNote = fmt.Sprintf("%s%d", note, octave)
+	}
+	if st.SongPitch > 0 {
+		note, octave := theory.FreqToNote(st.SongPitch)
+		st.SongNote = fmt.Sprintf("%s%d", note, octave)
+		if st.MicPitch > 10 {
+			st.Cents = 1200 * math.Log2(st.MicPitch/st.SongPitch)
+		}
+	}
+	st.Hit = a.isHit(st.MicPitch, st.SongPitch)
+
+	st.Score = a.liveScore
+	var score func() float64
+	if len(a.sessionPitch) != a.liveScoreFrames && time.Since(a.liveScoreAt) >= config.LiveScoreInterval {
+		a.liveScoreFrames, a.liveScoreAt = len(a.sessionPitch), time.Now()
+		score = a.liveScorer(slices.Clone(a.sessionPitch))
+	}
+	a.mu.Unlock()
+
+	if score != nil {
+		st.Score = score()
+		a.mu.Lock()
+		a.liveScore = st.Score
+		a.mu.Unlock()
+	}
+	return st
+}
+
+/*
+liveScorer captures what is needed to score a run without holding mu.
+
+Input:
+  - trail: []float64 - Copy of sessionPitch (startGame reuses its array)
+
+Called by:
+  - LiveStatus (with mu held)
+
+Task:
+  - Keep the O(run length) scoring out of the lock micLoop needs
+
+Logic:
+ 1. Pick the reference with scoringSong and capture mode, harmony, octave
+    setting and hit tolerance now
+ 2. The returned function scores like sessionResults (harmony mode against
+    the target harmonies)
+
+Output:
+  - func() float64: Computes the hit percentage (0..100)
+*/
+func (a *App) liveScorer(trail []float64) func() float64 {
+	song := a.scoringSong(trail)
+	harmony := a.mode == audio.ModeHarmony
+	offsets, ignoreOctave, tolerance := a.harmony, a.opts.IgnoreOctave, a.hitTolerance
+	return func() float64 {
+		if harmony {
+			return scoring.ComputeHarmonyScore(trail, song, config.GetAudioLatencyMs(), offsets, ignoreOctave, tolerance).Score
+		}
+		return scoring.ComputeScore(trail, song, config.GetAudioLatencyMs(), ignoreOctave, tolerance).Score
+	}
+}
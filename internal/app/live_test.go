@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+
+	"singAssist/internal/audio"
+)
+
+func TestLiveStatusHitFollowsSessionRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		mic          float64
+		tolerance    float64
+		ignoreOctave bool
+		want         bool
+	}{
+		{"on the note", 220, 1, false, true},
+		{"a semitone sharp, tolerance 1.5", 233.08, 1.5, false, true},
+		{"a semitone sharp, tolerance 0.5", 233.08, 0.5, false, false},
+		{"an octave up", 440, 1, false, false},
+		{"an octave up, octave-agnostic", 440, 1, true, true},
+		{"silence", 0, 1, true, false},
+	}
+	for _, tt := range tests {
+		mic := &FakeMic{Pitches: []float64{tt.mic}}
+		mic.DetectPitchFromMic(audio.ModeFullMix)
+		a := newMicLoopApp(mic)
+		a.songPitch = make([]float64, 500)
+		for i := range a.songPitch {
+			a.songPitch[i] = 220
+		}
+		a.hitTolerance = tt.tolerance
+		a.opts.IgnoreOctave = tt.ignoreOctave
+
+		if got := a.LiveStatus(); got.Hit != tt.want {
+			t.Errorf("%s: Hit = %v, want %v (cents %.0f)", tt.name, got.Hit, tt.want, got.Cents)
+		}
+	}
+}
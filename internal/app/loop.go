@@ -0,0 +1,193 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+loopFile is the on-disk form of the practice loop.
+
+Fields:
+  - StartMs, EndMs: Loop boundaries in milliseconds of (possibly cropped) playback
+*/
+type loopFile struct {
+	StartMs int64 `json:"start_ms"`
+	EndMs   int64 `json:"end_ms"`
+}
+
+/*
+handleLoopInput sets and clears the practice loop.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput
+
+Task:
+  - Mark a difficult phrase to repeat
+
+Logic:
+ 1. [: loop start = current position
+ 2. ]: loop end = current position
+ 3. \: clear the loop
+ 4. Save the loop after any change (nothing while Ctrl is held: Ctrl+[ / Ctrl+]
+    change the metronome tempo)
+
+Output:
+  - None (updates loopStart/loopEnd)
+*/
+func (a *App) handleLoopInput() {
+	if a.audioPlayer == nil || ebiten.IsKeyPressed(ebiten.KeyControl) {
+		return
+	}
+
+	changed := true
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft):
+		a.loopStart = a.songPosition()
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketRight):
+		a.loopEnd = a.songPosition()
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackslash):
+		a.loopStart, a.loopEnd = 0, 0
+	default:
+		changed = false
+	}
+
+	if changed {
+		a.saveLoop()
+	}
+}
+
+/*
+loopActive reports whether a valid loop is set.
+
+Input:
+  - None
+
+Called by:
+  - applyLoop, drawPlayingMode
+
+Task:
+  - A loop needs an end after its start
+
+Logic:
+ 1. loopEnd > loopStart
+
+Output:
+  - bool: true when playback should loop
+*/
+func (a *App) loopActive() bool {
+	return a.loopEnd > a.loopStart
+}
+
+/*
+applyLoop jumps back to the loop start once playback passes the loop end.
+
+Input:
+  - None
+
+Called by:
+  - Update every frame while playing
+
+Task:
+  - Repeat the marked phrase
+
+Logic:
+ 1. Skip when no loop is set, there is no player, or echo practice is running
+ 2. If songPosition >= loopEnd: seek to loopStart
+ 3. Clear the pruned user trails (both singers in a duet) so the new pass is
+    drawn cleanly
+
+Output:
+  - None (seeks the player)
+*/
+func (a *App) applyLoop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.loopActive() || a.audioPlayer == nil || !a.echoStart.IsZero() {
+		return
+	}
+	if a.songPosition() >= a.loopEnd {
+		a.seekSong(a.loopStart)
+		a.userPitch = a.userPitch[:0]
+		a.userPitch2 = a.userPitch2[:0]
+	}
+}
+
+/*
+loadLoop restores the saved loop for the current song.
+
+Input:
+  - None
+
+Called by:
+  - New, selectSong
+
+Task:
+  - Keep loop points between runs
+
+Logic:
+ 1. Read loop.json; a missing file means no loop
+ 2. Decode and convert to durations
+
+Output:
+  - None (sets loopStart/loopEnd)
+*/
+func (a *App) loadLoop() {
+	data, err := os.ReadFile(config.GetSongPaths(a.songDir).LoopFile)
+	if err != nil {
+		return
+	}
+
+	var lf loopFile
+	if err := json.Unmarshal(data, &lf); err != nil {
+		logging.Warnf("Ignoring bad loop file: %v", err)
+		return
+	}
+	a.loopStart = time.Duration(lf.StartMs) * time.Millisecond
+	a.loopEnd = time.Duration(lf.EndMs) * time.Millisecond
+}
+
+/*
+saveLoop writes the current loop points to loop.json.
+
+Input:
+  - None
+
+Called by:
+  - handleLoopInput after a change
+
+Task:
+  - Persist loop points for the next run
+
+Logic:
+ 1. Cleared loop: remove the file
+ 2. Otherwise marshal start/end in milliseconds and write the file
+
+Output:
+  - None (writes to disk, logs errors)
+*/
+func (a *App) saveLoop() {
+	path := config.GetSongPaths(a.songDir).LoopFile
+	if a.loopStart == 0 && a.loopEnd == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logging.Warnf("Could not remove loop file: %v", err)
+		}
+		return
+	}
+
+	data, _ := json.Marshal(loopFile{StartMs: a.loopStart.Milliseconds(), EndMs: a.loopEnd.Milliseconds()})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		logging.Warnf("Could not save loop: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyLoopSeeksBackAtLoopEnd(t *testing.T) {
+	a := &App{
+		audioPlayer:   newTestPlayer(),
+		playbackSpeed: 1,
+		loopStart:     5 * time.Second,
+		loopEnd:       10 * time.Second,
+		userPitch:     []float64{9000, 220, 9990, 230},
+		userPitch2:    []float64{9000, 330, 9990, 340},
+	}
+
+	a.audioPlayer.SetPosition(9 * time.Second)
+	a.applyLoop()
+	if got := a.audioPlayer.Position(); got != 9*time.Second {
+		t.Errorf("inside the loop the player moved to %v", got)
+	}
+
+	a.audioPlayer.SetPosition(10*time.Second + 20*time.Millisecond)
+	a.applyLoop()
+	if got := a.audioPlayer.Position(); got != 5*time.Second {
+		t.Errorf("after crossing the loop end the player is at %v, want 5s", got)
+	}
+	if len(a.userPitch) != 0 || len(a.userPitch2) != 0 {
+		t.Errorf("user trails kept %d and %d values across the jump, want them cleared", len(a.userPitch), len(a.userPitch2))
+	}
+}
+
+func TestApplyLoopInSongTimeAtHalfSpeed(t *testing.T) {
+	a := &App{
+		audioPlayer:   newTestPlayer(),
+		playbackSpeed: 0.5,
+		loopStart:     5 * time.Second,
+		loopEnd:       10 * time.Second,
+	}
+
+	// At half speed 10 s of song is 20 s of the stretched file.
+	a.audioPlayer.SetPosition(19 * time.Second)
+	a.applyLoop()
+	if got := a.audioPlayer.Position(); got != 19*time.Second {
+		t.Errorf("before the song-time loop end the player moved to %v", got)
+	}
+	a.audioPlayer.SetPosition(20 * time.Second)
+	a.applyLoop()
+	if got := a.audioPlayer.Position(); got != 10*time.Second {
+		t.Errorf("player at %v after the loop, want 10s (5s of song)", got)
+	}
+}
+
+func TestApplyLoopInactive(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(a *App)
+	}{
+		{"no loop", func(a *App) { a.loopStart, a.loopEnd = 0, 0 }},
+		{"end before start", func(a *App) { a.loopStart, a.loopEnd = 10*time.Second, 5*time.Second }},
+		{"echo practice", func(a *App) { a.echoStart = time.Now() }},
+	}
+	for _, tt := range tests {
+		a := &App{
+			audioPlayer:   newTestPlayer(),
+			playbackSpeed: 1,
+			loopStart:     5 * time.Second,
+			loopEnd:       10 * time.Second,
+		}
+		tt.setup(a)
+		a.audioPlayer.SetPosition(12 * time.Second)
+		a.applyLoop()
+		if got := a.audioPlayer.Position(); got != 12*time.Second {
+			t.Errorf("%s: player moved to %v", tt.name, got)
+		}
+	}
+}
+
+func TestLoopPersistsPerSong(t *testing.T) {
+	dir := t.TempDir()
+	a := &App{songDir: dir, loopStart: 5 * time.Second, loopEnd: 10 * time.Second}
+	a.saveLoop()
+
+	b := &App{songDir: dir}
+	b.loadLoop()
+	if b.loopStart != 5*time.Second || b.loopEnd != 10*time.Second {
+		t.Errorf("reloaded loop = %v..%v, want 5s..10s", b.loopStart, b.loopEnd)
+	}
+
+	a.loopStart, a.loopEnd = 0, 0
+	a.saveLoop()
+	c := &App{songDir: dir}
+	c.loadLoop()
+	if c.loopActive() {
+		t.Errorf("cleared loop came back as %v..%v", c.loopStart, c.loopEnd)
+	}
+}
@@ -0,0 +1,202 @@
+package app
+
+import (
+	"math"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+handleMetronomeInput toggles the click track and changes its tempo.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput
+
+Task:
+  - Let the user practise against a steady beat independent of the song
+
+Logic:
+ 1. config.Keys.Metronome: toggleMetronome
+ 2. Ctrl+[ / Ctrl+]: tempo ±config.MetronomeBPMStep within
+    MinMetronomeBPM..MaxMetronomeBPM (plain [ and ] set the practice loop)
+
+Output:
+  - None (updates metronome state)
+*/
+func (a *App) handleMetronomeInput() {
+	if inpututil.IsKeyJustPressed(config.Keys.Metronome) {
+		a.toggleMetronome()
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyControl) {
+		if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+			a.metronomeBPM = max(config.MinMetronomeBPM, a.metronomeBPM-config.MetronomeBPMStep)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+			a.metronomeBPM = min(config.MaxMetronomeBPM, a.metronomeBPM+config.MetronomeBPMStep)
+		}
+	}
+}
+
+/*
+toggleMetronome turns the click track on or off.
+
+Input:
+  - None
+
+Called by:
+  - handleMetronomeInput
+
+Task:
+  - Start clicking from the next beat
+
+Logic:
+ 1. Create the click player on first use (audio.NewClickPlayer)
+ 2. Flip metronomeEnabled and mark the current beat as already clicked
+
+Output:
+  - None (updates metronome state)
+*/
+func (a *App) toggleMetronome() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.clickPlayer == nil {
+		a.clickPlayer = audio.NewClickPlayer()
+	}
+	a.metronomeEnabled = !a.metronomeEnabled
+	a.lastBeat = a.currentBeat()
+}
+
+/*
+currentBeat returns which beat the playback position is in.
+
+Input:
+  - None (caller holds mu)
+
+Called by:
+  - toggleMetronome, tickMetronome, drawMetronome
+
+Task:
+  - Tie the clicks to the playback clock so pause and seeking keep them in time
+
+Logic:
+ 1. floor(position in seconds × metronomeBPM / 60), -1 when not playing
+
+Output:
+  - int64: Beat index since playback start
+*/
+func (a *App) currentBeat() int64 {
+	pos, running := a.playbackPos()
+	if !running {
+		return -1
+	}
+	return int64(math.Floor(pos.Seconds() * a.metronomeBPM / 60))
+}
+
+/*
+onBeat reports whether playback is on a beat of the song's detected tempo.
+
+Input:
+  - currTime: float64 - Playback position in seconds
+
+Called by:
+  - drawPlayingMode for the "now" line pulse
+
+Task:
+  - Let the "now" line pulse with the song
+
+Logic:
+ 1. False while paused or when no tempo was detected (songBPM 0)
+ 2. Beats are on a grid anchored at song time 0: with -start the section
+    begins opts.Load.Start into the song, so the grid origin on the playback
+    clock is -opts.Load.Start
+ 3. True within config.BeatPulseWindow of the next or the previous beat
+
+Output:
+  - bool: true on a beat
+*/
+func (a *App) onBeat(currTime float64) bool {
+	if a.songBPM <= 0 || a.audioPlayer == nil || !a.audioPlayer.IsPlaying() {
+		return false
+	}
+	next := audio.NextBeatTime(currTime, -a.opts.Load.Start.Seconds(), a.songBPM)
+	prev := next - 60/a.songBPM
+	return min(next-currTime, currTime-prev) <= config.BeatPulseWindow
+}
+
+/*
+tickMetronome plays a click when playback enters a new beat.
+
+Input:
+  - None
+
+Called by:
+  - Update every frame while playing
+
+Task:
+  - Schedule clicks from the number of beat intervals elapsed since playback start
+
+Logic:
+ 1. Skip while the metronome is off
+ 2. When currentBeat differs from lastBeat (next beat, or a seek):
+    remember it and, while playing, rewind and play the click
+
+Output:
+  - None (plays the click player)
+*/
+func (a *App) tickMetronome() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.metronomeEnabled {
+		return
+	}
+	beat := a.currentBeat()
+	if beat == a.lastBeat {
+		return
+	}
+	a.lastBeat = beat
+	if beat >= 0 {
+		a.clickPlayer.Rewind()
+		a.clickPlayer.Play()
+	}
+}
+
+/*
+drawMetronome shows the click track tempo in the HUD.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+
+Called by:
+  - drawPlayingMode, drawFreestyleMode (under mu)
+
+Task:
+  - Show the tempo and flash on each beat
+
+Logic:
+ 1. Nothing while the metronome is off
+ 2. The beat indicator is lit for the first fifth of each beat
+ 3. ui.DrawMetronome
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawMetronome(screen *ebiten.Image, sw int) {
+	if !a.metronomeEnabled {
+		return
+	}
+	pos, running := a.playbackPos()
+	beats := pos.Seconds() * a.metronomeBPM / 60
+	ui.DrawMetronome(screen, sw, a.metronomeBPM, running && beats-math.Floor(beats) < 0.2)
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTickMetronomeClicksOncePerBeat(t *testing.T) {
+	a := &App{
+		audioPlayer:      newTestPlayer(),
+		clickPlayer:      newTestPlayer(),
+		playbackSpeed:    1,
+		metronomeEnabled: true,
+		metronomeBPM:     120,
+		lastBeat:         -1,
+	}
+	a.audioPlayer.Play()
+
+	clicks := 0
+	for ms := 0; ms < 2000; ms += 10 {
+		a.audioPlayer.SetPosition(time.Duration(ms) * time.Millisecond)
+		a.tickMetronome()
+		if a.clickPlayer.IsPlaying() {
+			clicks++
+			a.clickPlayer.Pause()
+		}
+	}
+	if clicks != 4 {
+		t.Errorf("%d clicks in 2 s at 120 BPM, want 4", clicks)
+	}
+
+	a.metronomeEnabled = false
+	a.audioPlayer.SetPosition(2500 * time.Millisecond)
+	a.tickMetronome()
+	if a.clickPlayer.IsPlaying() {
+		t.Error("disabled metronome clicked")
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+/*
+toggleMicMonitor starts or stops hearing the mic through the speakers.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput, handleFreestyleInput on config.Keys.MicMonitor
+
+Task:
+  - Let the singer hear themselves while practising
+
+Logic:
+ 1. If monitoring is on: stopMicMonitor
+ 2. Otherwise create an audio.MicMonitorStream and a player reading it with a
+    config.MicMonitorBuffer buffer, and play it (log and stay off on error)
+
+Output:
+  - None (updates micMonitor and micMonitorPlayer)
+*/
+func (a *App) toggleMicMonitor() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.micMonitorPlayer != nil {
+		a.stopMicMonitor()
+		return
+	}
+	stream := audio.NewMicMonitorStream(config.SampleRate)
+	player, err := audio.AudioContext.NewPlayer(stream)
+	if err != nil {
+		logging.Warnf("Could not start mic monitoring: %v", err)
+		return
+	}
+	player.SetBufferSize(config.MicMonitorBuffer)
+	player.Play()
+	a.micMonitor, a.micMonitorPlayer = stream, player
+}
+
+/*
+stopMicMonitor silences mic monitoring.
+
+Input:
+  - None (caller holds mu, or owns the App as cleanup does)
+
+Called by:
+  - toggleMicMonitor, cleanup
+
+Task:
+  - Release the monitor player
+
+Logic:
+ 1. Pause and close the player if there is one; nil both fields
+
+Output:
+  - None
+*/
+func (a *App) stopMicMonitor() {
+	if a.micMonitorPlayer == nil {
+		return
+	}
+	a.micMonitorPlayer.Pause()
+	a.micMonitorPlayer.Close()
+	a.micMonitor, a.micMonitorPlayer = nil, nil
+}
+
+/*
+drawMicMonitor warns about feedback while monitoring is on.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+
+Called by:
+  - drawPlayingMode, drawFreestyleMode (under mu)
+
+Task:
+  - Speakers feed the monitored voice back into the mic; remind the user
+
+Logic:
+ 1. Nothing while monitoring is off, otherwise ui.DrawMicMonitor
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawMicMonitor(screen *ebiten.Image, sw int) {
+	if a.micMonitorPlayer == nil {
+		return
+	}
+	ui.DrawMicMonitor(screen, sw)
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+openNoteEditor shows the practice notes editor for the current song.
+
+Input:
+  - None
+
+Called by:
+  - handleStartScreenInput when N is pressed
+
+Task:
+  - Let the user annotate the song (tricky passages, breathing spots, ...)
+
+Logic:
+ 1. Start editing from the saved notes, clear the message, set state to StateNoteEditor
+
+Output:
+  - None (changes state)
+*/
+func (a *App) openNoteEditor() {
+	a.noteInput = a.notes
+	a.noteMessage = ""
+	a.state = StateNoteEditor
+}
+
+/*
+handleNoteEditorInput processes keyboard input in the note editor.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateNoteEditor
+
+Task:
+  - Edit and save notes.md
+
+Logic:
+ 1. Typed characters are appended (not while Ctrl is held), Backspace removes
+    the last one, Enter starts a new line
+ 2. Ctrl+S: config.SaveNotes; on success the start screen shows the new notes,
+    otherwise show why it failed
+ 3. Escape: back to the start screen (unsaved edits are dropped)
+
+Output:
+  - None (may write notes.md and change state)
+*/
+func (a *App) handleNoteEditorInput() {
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+	if chars := ebiten.AppendInputChars(nil); len(chars) > 0 && !ctrl {
+		a.noteInput += string(chars)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && a.noteInput != "" {
+		r := []rune(a.noteInput)
+		a.noteInput = string(r[:len(r)-1])
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		a.noteInput += "\n"
+	}
+
+	if ctrl && inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		if err := config.SaveNotes(a.songDir, a.noteInput); err != nil {
+			logging.Warnf("Could not save notes.md: %v", err)
+			a.noteMessage = "Could not save notes.md: " + err.Error()
+			return
+		}
+		a.notes = a.noteInput
+		a.noteMessage = "Saved"
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		a.state = StateStartScreen
+	}
+}
+
+/*
+drawNoteEditor renders the note editor.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateNoteEditor
+
+Task:
+  - Show the notes being edited
+
+Logic:
+ 1. ui.DrawNoteEditor with the song name, the edited text and the save message
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawNoteEditor(screen *ebiten.Image, sw, sh int) {
+	ui.DrawNoteEditor(screen, a.SongName(), a.noteInput, a.noteMessage, sw, sh)
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/quiz"
+	"singAssist/internal/theory"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+/*
+newIntervalQuiz starts an interval quiz within the user's vocal range.
+
+Input:
+  - None
+
+Called by:
+  - startGame for ModeIntervalQuiz
+
+Task:
+  - Only ask for notes the user can sing
+
+Logic:
+ 1. config.LoadVocalRange (a bad file is logged and the default range is used)
+ 2. quiz.NewIntervalQuizSession seeded with the current time
+
+Output:
+  - None (sets quiz)
+*/
+func (a *App) newIntervalQuiz() {
+	vr, err := config.LoadVocalRange()
+	if err != nil {
+		logging.Warnf("Ignoring %s: %v", config.VocalRangeFile, err)
+	}
+	a.quiz = quiz.NewIntervalQuizSession(vr, uint64(time.Now().UnixNano()))
+}
+
+/*
+tickIntervalQuiz plays the quiz's root notes and judges the answers.
+
+Input:
+  - None
+
+Called by:
+  - Update while playing in ModeIntervalQuiz
+
+Task:
+  - Play each root, listen for the interval, then show the verdict
+
+Logic:
+ 1. Nothing before playback starts
+ 2. First tick, or config.QuizFeedbackDuration after an answer: ask the next
+    question and sound its root (startQuizQuestion)
+ 3. After config.QuizToneDuration: stop the tone so the mic only hears the user
+ 4. After a further config.QuizListenDuration: RecordUserAnswer with the median
+    voiced pitch of the listening window, once per question
+
+Output:
+  - None (plays tones, advances quiz)
+*/
+func (a *App) tickIntervalQuiz() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	pos, running := a.playbackPos()
+	if a.quiz == nil || !running {
+		return
+	}
+	elapsed := pos - a.quizAt
+	if a.quizAt < 0 || elapsed >= config.QuizToneDuration+config.QuizListenDuration+config.QuizFeedbackDuration {
+		a.startQuizQuestion(pos)
+		return
+	}
+
+	if elapsed >= config.QuizToneDuration && a.refTone != nil {
+		a.refTone.Stop()
+		a.refTone = nil
+	}
+	if elapsed < config.QuizToneDuration+config.QuizListenDuration || a.quizAnswered {
+		return
+	}
+	from := float64((a.quizAt + config.QuizToneDuration).Milliseconds())
+	a.quiz.RecordUserAnswer(medianVoicedPitch(a.userPitch, from))
+	a.quizAnswered = true
+}
+
+/*
+startQuizQuestion asks the quiz's next question and sounds its root.
+
+Input:
+  - pos: time.Duration - Current session position (caller holds mu)
+
+Called by:
+  - tickIntervalQuiz
+
+Task:
+  - Give the user the note to sing the interval above
+
+Logic:
+ 1. quiz.NextQuestion; remember pos as the question's start
+ 2. Play an audio.ReferenceTonePlayer at the root (log and carry on silently
+    if it fails)
+
+Output:
+  - None (sets quizAt, quizAnswered and refTone)
+*/
+func (a *App) startQuizQuestion(pos time.Duration) {
+	root, _, _ := a.quiz.NextQuestion()
+	a.quizAt, a.quizAnswered = pos, false
+	a.refTone = audio.NewReferenceTonePlayer(root)
+	if err := a.refTone.Play(); err != nil {
+		logging.Warnf("Could not play quiz tone: %v", err)
+		a.refTone = nil
+	}
+}
+
+/*
+drawIntervalQuiz renders the interval quiz.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw in ModeIntervalQuiz (under mu)
+
+Task:
+  - Show the question, the user's pitch and the verdict on each answer
+
+Logic:
+ 1. Nothing until the first question
+ 2. ui.DrawIntervalQuiz with the interval, its root, whether the tone has
+    stopped, the feedback once the answer is recorded, and the score
+ 3. The user's pitch in the corner; key hints at the bottom
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawIntervalQuiz(screen *ebiten.Image, sw, sh int) {
+	if a.quiz == nil || a.quizAt < 0 {
+		return
+	}
+	root, _ := a.quiz.Question()
+	note, octave := theory.FreqToNote(root)
+	feedback := ""
+	if a.quizAnswered {
+		feedback = a.quiz.Feedback()
+	}
+	correct, asked := a.quiz.Counts()
+	ui.DrawIntervalQuiz(screen, a.quiz.Interval(), fmt.Sprintf("%s%d", note, octave), a.refTone == nil, feedback, correct, asked, a.quiz.Score(), sw, sh)
+
+	if a.mic != nil {
+		pitch := a.mic.CurrentPitch()
+		note, octave := theory.FreqToNote(pitch)
+		if pitch <= 10 {
+			ui.DrawTextAt(screen, "YOUR PITCH: -", 10, 10, ui.ActiveTheme.HUDText)
+		} else {
+			ui.DrawTextAt(screen, fmt.Sprintf("YOUR PITCH: %s%d (%.0f Hz)", note, octave, pitch), 10, 10, ui.ActiveTheme.HUDText)
+		}
+	}
+	ui.DrawTextAt(screen, "Sing the interval above the root  ESC: Stop", 10, sh-20, ui.ActiveTheme.HUDText)
+}
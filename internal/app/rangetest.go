@@ -0,0 +1,245 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/theory"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+handleRangeTestInput processes keyboard input during the vocal range test.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput in ModeRangeTest and ModeIntervalQuiz
+
+Task:
+  - The test and the quiz run by themselves; only a couple of keys apply
+
+Logic:
+ 1. Fullscreen key toggles fullscreen
+ 2. Exit key: back to the menu (a finished test is already saved)
+
+Output:
+  - None (may change state)
+*/
+func (a *App) handleRangeTestInput() {
+	if inpututil.IsKeyJustPressed(config.Keys.Fullscreen) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
+	if inpututil.IsKeyJustPressed(config.Keys.Exit) {
+		a.exitToMenu()
+	}
+}
+
+/*
+tickRangeTest plays the range test notes and judges the answers.
+
+Input:
+  - None
+
+Called by:
+  - Update while playing in ModeRangeTest
+
+Task:
+  - Play each note, then listen for the user singing it back
+
+Logic:
+ 1. Nothing before playback starts or after the test is done
+ 2. First tick: sound the first note (startRangeNote)
+ 3. After config.RangeTestToneDuration: stop the tone so the mic only hears the user
+ 4. After a further config.RangeTestListenDuration: RecordResult with the median
+    voiced pitch of the listening window, then sound the next note, or save
+    the range (finishRangeTest) when the test is done
+
+Output:
+  - None (plays tones, advances rangeTest)
+*/
+func (a *App) tickRangeTest() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	pos, running := a.playbackPos()
+	if a.rangeTest == nil || a.rangeTest.Done() || !running {
+		return
+	}
+	if a.rangeNoteAt < 0 {
+		a.startRangeNote(pos)
+		return
+	}
+
+	elapsed := pos - a.rangeNoteAt
+	if elapsed >= config.RangeTestToneDuration && a.refTone != nil {
+		a.refTone.Stop()
+		a.refTone = nil
+	}
+	if elapsed < config.RangeTestToneDuration+config.RangeTestListenDuration {
+		return
+	}
+
+	from := float64((a.rangeNoteAt + config.RangeTestToneDuration).Milliseconds())
+	a.rangeTest.RecordResult(medianVoicedPitch(a.userPitch, from))
+	if a.rangeTest.Done() {
+		a.finishRangeTest()
+		return
+	}
+	a.startRangeNote(pos)
+}
+
+/*
+startRangeNote sounds the range test's next note.
+
+Input:
+  - pos: time.Duration - Current session position (caller holds mu)
+
+Called by:
+  - tickRangeTest
+
+Task:
+  - Give the user the pitch to match
+
+Logic:
+ 1. Remember pos as the note's start
+ 2. Play an audio.ReferenceTonePlayer at rangeTest.NextNote (log and carry on
+    silently if it fails)
+
+Output:
+  - None (sets rangeNoteAt and refTone)
+*/
+func (a *App) startRangeNote(pos time.Duration) {
+	a.rangeNoteAt = pos
+	a.refTone = audio.NewReferenceTonePlayer(a.rangeTest.NextNote())
+	if err := a.refTone.Play(); err != nil {
+		logging.Warnf("Could not play range test tone: %v", err)
+		a.refTone = nil
+	}
+}
+
+/*
+finishRangeTest saves the range the test found.
+
+Input:
+  - None (caller holds mu)
+
+Called by:
+  - tickRangeTest when the test is done
+
+Task:
+  - Let later sessions search the mic pitch within the user's range
+
+Logic:
+ 1. Nothing matched: keep the previous range
+ 2. Otherwise config.SaveVocalRange with rangeTest's Range and Summary
+    (logging any error); the next session's mic picks it up
+
+Output:
+  - None (writes config/vocal_range.json)
+*/
+func (a *App) finishRangeTest() {
+	lowNote, highNote := a.rangeTest.Summary()
+	if highNote == "" {
+		return
+	}
+	lowHz, highHz := a.rangeTest.Range()
+	r := config.VocalRange{LowHz: lowHz, HighHz: highHz, LowNote: lowNote, HighNote: highNote}
+	if err := config.SaveVocalRange(r); err != nil {
+		logging.Warnf("Could not save vocal range: %v", err)
+		return
+	}
+	logging.Infof("Saved vocal range %s - %s", lowNote, highNote)
+}
+
+/*
+medianVoicedPitch returns the typical pitch sung since a given time.
+
+Input:
+  - pairs: []float64 - [timeMs, pitch, ...] pairs (userPitch)
+  - fromMs: float64 - Start of the window
+
+Called by:
+  - tickRangeTest, tickIntervalQuiz at the end of each listening window
+
+Task:
+  - Judge a note by what was sung most of the time, not by a stray frame
+
+Logic:
+ 1. Collect pitches above 10Hz at or after fromMs
+ 2. Return their median (0 if none)
+
+Output:
+  - float64: Median pitch in Hz
+*/
+func medianVoicedPitch(pairs []float64, fromMs float64) float64 {
+	var voiced []float64
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i] >= fromMs && pairs[i+1] > 10 {
+			voiced = append(voiced, pairs[i+1])
+		}
+	}
+	if len(voiced) == 0 {
+		return 0
+	}
+	sort.Float64s(voiced)
+	return voiced[len(voiced)/2]
+}
+
+/*
+drawRangeTest renders the vocal range test.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw in ModeRangeTest (under mu)
+
+Task:
+  - Show the note to sing, the user's pitch and finally the range found
+
+Logic:
+ 1. Nothing until the test has started
+ 2. ui.DrawRangeTest with the target note (none once done), whether the tone
+    has stopped, and the range matched so far
+ 3. While running: the user's pitch in the corner; key hints at the bottom
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawRangeTest(screen *ebiten.Image, sw, sh int) {
+	if a.rangeTest == nil || a.rangeNoteAt < 0 {
+		return
+	}
+	low, high := a.rangeTest.Summary()
+	target := ""
+	if !a.rangeTest.Done() {
+		note, octave := theory.FreqToNote(a.rangeTest.NextNote())
+		target = fmt.Sprintf("%s%d", note, octave)
+	}
+	ui.DrawRangeTest(screen, target, a.refTone == nil, low, high, sw, sh)
+
+	if target != "" && a.mic != nil {
+		pitch := a.mic.CurrentPitch()
+		note, octave := theory.FreqToNote(pitch)
+		if pitch <= 10 {
+			ui.DrawTextAt(screen, "YOUR PITCH: -", 10, 10, ui.ActiveTheme.HUDText)
+		} else {
+			ui.DrawTextAt(screen, fmt.Sprintf("YOUR PITCH: %s%d (%.0f Hz)", note, octave, pitch), 10, 10, ui.ActiveTheme.HUDText)
+		}
+	}
+	if target == "" {
+		ui.DrawTextAt(screen, "ESC: Menu", 10, sh-20, ui.ActiveTheme.HUDText)
+	} else {
+		ui.DrawTextAt(screen, "Match each note  ESC: Stop", 10, sh-20, ui.ActiveTheme.HUDText)
+	}
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"singAssist/internal/logging"
+)
+
+/*
+stopRecording saves the session's mic recording.
+
+Input:
+  - None
+
+Called by:
+  - cleanup
+
+Task:
+  - Let the user listen back to the session
+
+Logic:
+ 1. Take the recorder under mu so micLoop stops adding to it
+ 2. recorder.Stop writes the WAV file (nothing if playback never started);
+    log where it went or why it failed
+
+Output:
+  - None (nils recorder)
+*/
+func (a *App) stopRecording() {
+	a.mu.Lock()
+	rec := a.recorder
+	a.recorder = nil
+	a.mu.Unlock()
+	if rec == nil {
+		return
+	}
+	path, err := rec.Stop()
+	if err != nil {
+		logging.Warnf("Could not save recording: %v", err)
+	} else if path != "" {
+		logging.Infof("Saved recording to %s", path)
+	}
+}
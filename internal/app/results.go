@@ -0,0 +1,192 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"time"
+
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// setlistAdvanceDelay is how long the results screen stays up before the
+// next setlist song starts.
+const setlistAdvanceDelay = 5 * time.Second
+
+/*
+showResults ends the session and switches to the results screen.
+
+Input:
+  - panels: []ui.ResultsPanel - Statistics per singer
+
+Called by:
+  - finishSong when the song plays to the end
+  - finishFreestyle
+
+Task:
+  - Let the user see how the run went before returning to the menu
+
+Logic:
+ 1. Leave fullscreen, log the run to history and release the session (as exitToMenu),
+    keeping the song pitch for the session comparison
+ 2. Store the panels and set state to StateResults
+ 3. With another setlist song to come: schedule it setlistAdvanceDelay from now
+
+Output:
+  - None (changes state)
+*/
+func (a *App) showResults(panels []ui.ResultsPanel) {
+	ebiten.SetFullscreen(false)
+	a.recordHistory()
+	a.compareSong = a.songPitch
+	a.cleanup()
+	a.resultPanels = panels
+	a.state = StateResults
+
+	a.nextSongAt = time.Time{}
+	if a.setlistIndex+1 < len(a.setlist) {
+		a.nextSongAt = time.Now().Add(setlistAdvanceDelay)
+	}
+}
+
+/*
+handleResultsInput processes keyboard input on the results screen.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateResults
+
+Task:
+  - Return to the start screen, or move on through the setlist
+
+Logic:
+ 1. Escape: back to StateStartScreen with stopSetlist
+ 2. Enter or Space: the next setlist song if there is one, else StateStartScreen
+ 3. C: compare saved sessions of this song (stops an automatic advance)
+ 4. Once nextSongAt has passed: the next setlist song
+
+Output:
+  - None (may change state)
+*/
+func (a *App) handleResultsInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		a.stopSetlist()
+		a.state = StateStartScreen
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) {
+		a.nextSongAt = time.Time{}
+		a.openSessionCompare()
+		return
+	}
+
+	next := !a.nextSongAt.IsZero()
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) ||
+		inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		if next {
+			a.playNextSetlistSong()
+		} else {
+			a.state = StateStartScreen
+		}
+		return
+	}
+	if next && time.Now().After(a.nextSongAt) {
+		a.playNextSetlistSong()
+	}
+}
+
+/*
+stopSetlist ends the setlist run the results screen was part of.
+
+Input:
+  - None
+
+Called by:
+  - handleResultsInput on Escape
+
+Task:
+  - Make the next results screen start the setlist over instead of
+    continuing from where the user left it
+
+Logic:
+ 1. Clear nextSongAt (stops an automatic advance)
+ 2. setlistIndex = -1, so the next song offered is the setlist's first
+
+Output:
+  - None
+*/
+func (a *App) stopSetlist() {
+	a.nextSongAt = time.Time{}
+	a.setlistIndex = -1
+}
+
+/*
+playNextSetlistSong starts the next song of the setlist in the current mode.
+
+Input:
+  - None
+
+Called by:
+  - handleResultsInput
+
+Task:
+  - Play the setlist back-to-back
+
+Logic:
+ 1. Past the last song: clear nextSongAt and show the start screen
+ 2. Otherwise advance setlistIndex, switch to that song with selectSong
+    and start it with the mode just played
+
+Output:
+  - None (changes state)
+*/
+func (a *App) playNextSetlistSong() {
+	a.nextSongAt = time.Time{}
+	if a.setlistIndex+1 >= len(a.setlist) {
+		a.state = StateStartScreen
+		return
+	}
+	a.setlistIndex++
+	a.selectSong(a.setlist[a.setlistIndex])
+	a.startGame(a.mode)
+}
+
+/*
+drawResults renders the results screen.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateResults
+
+Task:
+  - Show the run's statistics and what comes next in a setlist
+
+Logic:
+ 1. ui.DrawResultsScreen with resultPanels, and the status message if any
+ 2. While a setlist song is scheduled: "Next song in Ns…" above the key hint
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawResults(screen *ebiten.Image, sw, sh int) {
+	ui.DrawResultsScreen(screen, a.SongName(), a.resultPanels, sw, sh)
+	if a.message != "" {
+		ui.DrawMessage(screen, a.message)
+	}
+
+	if !a.nextSongAt.IsZero() {
+		secs := int(math.Ceil(max(0, time.Until(a.nextSongAt).Seconds())))
+		next := filepath.Base(a.setlist[a.setlistIndex+1])
+		msg := fmt.Sprintf("Next song in %ds… %s (%d/%d)   ESC: Stop", secs, next, a.setlistIndex+2, len(a.setlist))
+		ui.DrawTextAt(screen, msg, 10, sh-40, ui.ActiveTheme.HUDText)
+	}
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+)
+
+func TestFinishSongDuringEchoScoresTheSong(t *testing.T) {
+	t.Chdir(t.TempDir())
+	songDir, err := config.EnsureSongDir("Kasoor")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The song is 40 frames of A3; the whole run was sung on it. Echo practice
+	// of a C4 phrase is still running when the song ends.
+	song := make([]float64, 40)
+	for i := range song {
+		song[i] = 220
+	}
+	var session []float64
+	for i := range song {
+		session = append(session, float64(10*i)+config.GetAudioLatencyMs(), 220)
+	}
+	phrase := []float64{0, 262, 262, 262, 0}
+	a := &App{
+		mode:           audio.ModeSinging,
+		songDir:        songDir,
+		songPitch:      phrase,
+		echoSavedPitch: song,
+		echoStart:      time.Now(),
+		echoLoop:       50 * time.Millisecond,
+		sessionPitch:   session,
+		hitTolerance:   config.DefaultHitTolerance,
+	}
+
+	a.finishSong()
+	if a.state != StateResults || len(a.resultPanels) != 1 {
+		t.Fatalf("state %v with %d panels, want the results screen", a.state, len(a.resultPanels))
+	}
+	p := a.resultPanels[0]
+	if p.LongestStreak != 40 || p.HitFrames != 40 || p.BestNote != "A3" {
+		t.Errorf("panel streak %d, hits %d, best %q; want all 40 frames of A3", p.LongestStreak, p.HitFrames, p.BestNote)
+	}
+	if !a.echoStart.IsZero() || a.echoSavedPitch != nil {
+		t.Error("echo practice still running on the results screen")
+	}
+	if !slices.Equal(a.compareSong, song) {
+		t.Errorf("session comparison uses %v, want the song", a.compareSong)
+	}
+}
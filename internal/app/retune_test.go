@@ -0,0 +1,59 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+)
+
+func TestRetuneAnalysisDropsPitchCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := filepath.Join(dir, "pitch_cache_vocals.bin")
+	if err := audio.SavePitchCache(cache, []float64{220, 220, 220}); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{
+		songDir:     dir,
+		mode:        audio.ModeSinging,
+		songPCM:     make([]byte, 4*config.SampleRate),
+		songPitch:   make([]float64, 100),
+		audioPlayer: newTestPlayer(),
+	}
+	a.opts.Load.Analysis = audio.DefaultAnalysisParams()
+	a.retuneAnalysis(0.5, false)
+
+	if _, err := os.Stat(cache); !os.IsNotExist(err) {
+		t.Errorf("pitch cache survived re-tuning (%v)", err)
+	}
+	if got, want := a.opts.Load.Analysis.SilenceFactor, audio.DefaultAnalysisParams().SilenceFactor+0.5; got != want {
+		t.Errorf("silence factor = %v, want %v", got, want)
+	}
+}
+
+func TestReanalysisRefreshesSegmentDifficulty(t *testing.T) {
+	pcm := make([]byte, 4*3*config.SampleRate)
+	a := &App{
+		songDir:     t.TempDir(),
+		mode:        audio.ModeSinging,
+		songPCM:     pcm,
+		songPitch:   make([]float64, 300),
+		audioPlayer: newTestPlayer(),
+	}
+	a.opts.Load.Analysis = audio.DefaultAnalysisParams()
+
+	a.retuneAnalysis(0.5, false)
+	if len(a.segmentDifficulty) != 3 {
+		t.Errorf("heat bar after a window re-analysis has %d seconds, want 3", len(a.segmentDifficulty))
+	}
+
+	a.segmentDifficulty = nil
+	a.reanalyzing = true
+	a.reanalyzeSong(pcm, a.mode, a.opts.Load.Analysis)
+	if a.reanalyzing || len(a.segmentDifficulty) != len(a.songPitch)/100+min(1, len(a.songPitch)%100) {
+		t.Errorf("heat bar after a full re-analysis has %d seconds for %d frames", len(a.segmentDifficulty), len(a.songPitch))
+	}
+}
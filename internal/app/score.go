@@ -0,0 +1,398 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/scoring"
+	"singAssist/internal/theory"
+	"singAssist/internal/ui"
+)
+
+// songEndMargin is how close to the end of the song playback must get before
+// the run counts as finished.
+const songEndMargin = 500 * time.Millisecond
+
+/*
+sessionResults scores everything sung since the session started.
+
+Input:
+  - None (reads sessionPitch, sessionPitch2 and songPitch under mu)
+
+Called by:
+  - finishSong, recordHistory
+
+Task:
+  - One place that builds the SessionResults for the current run
+
+Logic:
+ 1. Lock mu (micLoop may still be appending)
+ 2. Score against the real song even while an echo phrase is the reference
+    (several vocal parts: against the closest part per frame, see scoringSong)
+ 3. Call scoring.ComputeScore with config.GetAudioLatencyMs()
+    (harmony mode: scoring.ComputeHarmonyScore against the target harmonies)
+ 4. Fill in mode, song name and time
+ 5. Duet mode: score sessionPitch2 the same way, tagging results with Singer 1 and 2
+
+Output:
+  - []scoring.SessionResult: One result, or one per singer in duet mode
+*/
+func (a *App) sessionResults() []scoring.SessionResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	trails := [][]float64{a.sessionPitch}
+	if a.mode == audio.ModeDuet {
+		trails = append(trails, a.sessionPitch2)
+	}
+
+	results := make([]scoring.SessionResult, len(trails))
+	for i, trail := range trails {
+		song := a.scoringSong(trail)
+		var r scoring.SessionResult
+		if a.mode == audio.ModeHarmony {
+			r = scoring.ComputeHarmonyScore(trail, song, config.GetAudioLatencyMs(), a.harmony, a.opts.IgnoreOctave, a.hitTolerance)
+		} else {
+			r = scoring.ComputeScore(trail, song, config.GetAudioLatencyMs(), a.opts.IgnoreOctave, a.hitTolerance)
+		}
+		r.Mode = a.mode.String()
+		r.SongName = a.SongName()
+		r.PlayedAt = time.Now()
+		if len(trails) > 1 {
+			r.Singer = i + 1
+		}
+		results[i] = r
+	}
+	return results
+}
+
+/*
+scoringSong returns the song pitch a recorded trail is scored against.
+
+Input:
+  - trail: []float64 - Recorded pairs [timeMs, pitch, ...]
+
+Called by:
+  - sessionResults, finishSong (with mu held)
+
+Task:
+  - One rule for which reference counts, shared by score and statistics
+
+Logic:
+ 1. During echo practice: the real song saved in echoSavedPitch
+ 2. Several vocal parts: scoring.ClosestPart of the trail over songPitches
+ 3. Otherwise songPitch
+
+Output:
+  - []float64: Song pitch at 10ms intervals
+*/
+func (a *App) scoringSong(trail []float64) []float64 {
+	if a.echoSavedPitch != nil {
+		return a.echoSavedPitch
+	}
+	if len(a.songPitches) > 1 {
+		return scoring.ClosestPart(trail, a.songPitches, config.GetAudioLatencyMs())
+	}
+	return a.songPitch
+}
+
+/*
+songParts returns the song pitch lines to draw and hit-test against.
+
+Input:
+  - None
+
+Called by:
+  - drawPlayingMode, songFreqAt
+
+Task:
+  - Show every vocal part, but only the phrase during echo practice
+
+Logic:
+ 1. songPitches when there are several parts and no echo phrase is playing
+ 2. Otherwise songPitch alone
+
+Output:
+  - [][]float64: One pitch array per part
+*/
+func (a *App) songParts() [][]float64 {
+	if len(a.songPitches) > 1 && a.echoSavedPitch == nil {
+		return a.songPitches
+	}
+	return [][]float64{a.songPitch}
+}
+
+/*
+songFreqAt returns the song pitch shown as the target at a frame.
+
+Input:
+  - sIdx: int - Song frame (10ms) index
+  - pitch: float64 - Current mic pitch in Hz (0 = silence)
+
+Called by:
+  - drawPlayingMode, LiveStatus
+
+Task:
+  - Pick the target note, following the singer between vocal parts
+
+Logic:
+ 1. For each part from songParts, transposed by TransposeSteps
+ 2. Keep the first one, replacing it with a voiced part closer to pitch
+
+Output:
+  - float64: Target pitch in Hz (<= 10 when the song is silent there)
+*/
+func (a *App) songFreqAt(sIdx int, pitch float64) float64 {
+	songFreq := 0.0
+	for _, part := range a.songParts() {
+		if sIdx < 0 || sIdx >= len(part) {
+			continue
+		}
+		f := theory.Transpose(part[sIdx], a.opts.TransposeSteps)
+		if songFreq <= 10 || (f > 10 && pitch > 10 && theory.SemitoneDistance(pitch, f, false) < theory.SemitoneDistance(pitch, songFreq, false)) {
+			songFreq = f
+		}
+	}
+	return songFreq
+}
+
+/*
+isHit reports whether the mic pitch counts as a hit on the target right now.
+
+Input:
+  - pitch: float64 - Current mic pitch in Hz (0 = silence)
+  - songFreq: float64 - Target from songFreqAt
+
+Called by:
+  - drawPlayingMode, LiveStatus
+
+Task:
+  - Color the note display and the web HUD with the rule the session is scored by
+
+Logic:
+ 1. Harmony mode: theory.MatchesHarmony against the song's target harmonies
+ 2. Otherwise both voiced and theory.SemitoneDistance below hitTolerance
+    (octave-agnostic with IgnoreOctave)
+
+Output:
+  - bool: true on a hit
+*/
+func (a *App) isHit(pitch, songFreq float64) bool {
+	if a.mode == audio.ModeHarmony {
+		return theory.MatchesHarmony(pitch, songFreq, a.harmony, a.hitTolerance, a.opts.IgnoreOctave)
+	}
+	return pitch > 10 && songFreq > 10 && theory.SemitoneDistance(pitch, songFreq, a.opts.IgnoreOctave) < a.hitTolerance
+}
+
+/*
+songFinished reports whether playback has reached the end of the song.
+
+Input:
+  - None
+
+Called by:
+  - Update while playing
+
+Task:
+  - Detect the end of a run
+
+Logic:
+ 1. Requires a song duration, a running player or reference clock, and echo practice off
+ 2. True once playbackPos is within songEndMargin of songDuration
+
+Output:
+  - bool: true when the song has ended
+*/
+func (a *App) songFinished() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.songDuration <= 0 || !a.echoStart.IsZero() {
+		return false
+	}
+	if a.audioPlayer == nil && a.refStart.IsZero() {
+		return false
+	}
+	pos, _ := a.playbackPos()
+	return pos >= a.songDuration-songEndMargin
+}
+
+/*
+hitRule returns the rule the current mode is scored with.
+
+Input:
+  - None
+
+Called by:
+  - finishSong for the results screen statistics
+  - exportSessionImage for the hit color of the exported trail
+
+Task:
+  - Keep the statistics and the exported image consistent with sessionResults
+
+Logic:
+ 1. Harmony mode: scoring.HarmonyHit with the song's target harmonies
+ 2. Otherwise: scoring.NoteHit
+    (both within the song's hitTolerance)
+
+Output:
+  - scoring.HitRule: The rule
+*/
+func (a *App) hitRule() scoring.HitRule {
+	if a.mode == audio.ModeHarmony {
+		return scoring.HarmonyHit(a.harmony, a.opts.IgnoreOctave, a.hitTolerance)
+	}
+	return scoring.NoteHit(a.opts.IgnoreOctave, a.hitTolerance)
+}
+
+/*
+finishSong saves the score of a completed run and shows the results screen.
+
+Input:
+  - None
+
+Called by:
+  - Update when songFinished reports the end of the song
+
+Task:
+  - Persist per-song high scores and the best run's trail
+
+Logic:
+ 1. Leave echo practice (stopEcho), so the statistics and the session
+    comparison use the song rather than the echo phrase
+ 2. Compute the session results (one per singer in duet mode)
+ 3. For each that scored anything: append it to the song's scores.json
+ 4. Save each of those with its pitch trail to the sessions folder (scoring.SaveSession);
+    if one beats the saved best score: store its pitch trail as the ghost
+    (scoring.SaveBestSession)
+ 5. Compute the results-screen statistics per singer (scoring.ComputeSessionStatsWith;
+    config.SightReadingMultiplier when sight reading was on for the whole run)
+ 6. Log the run to history, release the session and show StateResults
+ 7. Refresh the best score shown on the start screen
+
+Output:
+  - None (writes to disk, changes state)
+*/
+func (a *App) finishSong() {
+	a.mu.Lock()
+	a.stopEcho()
+	a.mu.Unlock()
+
+	results := a.sessionResults()
+	best, bestIdx := a.bestScore, -1
+	for i, r := range results {
+		if r.TotalFrames == 0 {
+			continue
+		}
+		if err := scoring.SaveScore(config.GetSongPaths(a.songDir).ScoresFile, r); err != nil {
+			logging.Warnf("Could not save score: %v", err)
+		}
+		if r.Score > best {
+			best, bestIdx = r.Score, i
+		}
+	}
+
+	a.mu.Lock()
+	trails := [][]float64{slices.Clone(a.sessionPitch), slices.Clone(a.sessionPitch2)}
+	multiplier := 1.0
+	if a.sightReadRun {
+		multiplier = config.SightReadingMultiplier
+	}
+	panels := make([]ui.ResultsPanel, len(results))
+	for i, r := range results {
+		st := scoring.ComputeSessionStatsWith(trails[max(0, r.Singer-1)], a.scoringSong(trails[max(0, r.Singer-1)]), config.GetAudioLatencyMs(), a.hitRule(), multiplier)
+		panels[i] = ui.ResultsPanel{
+			Score:         r.Score,
+			HitFrames:     st.HitFrames,
+			TotalFrames:   st.TotalFrames,
+			LongestStreak: st.LongestStreak,
+			BestNote:      st.BestNote,
+			WorstNote:     st.WorstNote,
+			PitchClasses:  st.PitchClasses,
+			Points:        st.Points,
+			Multiplier:    st.Multiplier,
+		}
+		if r.Singer > 0 {
+			panels[i].Label = fmt.Sprintf("Singer %d", r.Singer)
+		}
+	}
+	a.mu.Unlock()
+
+	for _, r := range results {
+		if r.TotalFrames == 0 {
+			continue
+		}
+		if _, err := scoring.SaveSession(a.songDir, r, trails[max(0, r.Singer-1)]); err != nil {
+			logging.Warnf("Could not save session: %v", err)
+		}
+	}
+
+	if bestIdx >= 0 {
+		trail := trails[max(0, results[bestIdx].Singer-1)]
+		if err := scoring.SaveBestSession(a.songDir, results[bestIdx], trail); err != nil {
+			logging.Warnf("Could not save best session: %v", err)
+		}
+	}
+
+	a.showResults(panels)
+	a.refreshBestScore()
+	a.refreshDifficulty()
+}
+
+/*
+refreshBestScore reloads the high score for the current song.
+
+Input:
+  - None
+
+Called by:
+  - New, finishSong, selectSong
+
+Task:
+  - Avoid reading scores.json every frame on the start screen
+
+Logic:
+ 1. scoring.BestScore on the song's scores.json
+ 2. Store -1 when there is no saved score
+
+Output:
+  - None (updates bestScore)
+*/
+func (a *App) refreshBestScore() {
+	best, ok := scoring.BestScore(config.GetSongPaths(a.songDir).ScoresFile)
+	if !ok {
+		best = -1
+	}
+	a.bestScore = best
+}
+
+/*
+refreshDifficulty reloads the difficulty rating for the current song.
+
+Input:
+  - None
+
+Called by:
+  - New, finishSong, exitToMenu, selectSong
+
+Task:
+  - Show the rating on the start screen once the song has been analyzed
+
+Logic:
+ 1. audio.LoadDifficulty on the song's difficulty.json
+ 2. Store 0 stars (unrated) when there is none
+
+Output:
+  - None (updates difficulty)
+*/
+func (a *App) refreshDifficulty() {
+	d, err := audio.LoadDifficulty(config.GetSongPaths(a.songDir).DifficultyFile)
+	if err != nil {
+		d.Stars = 0
+	}
+	a.difficulty = d.Stars
+}
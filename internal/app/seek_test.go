@@ -0,0 +1,41 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGraphClickTimeStaysInSong(t *testing.T) {
+	a := &App{
+		audioPlayer:   newTestPlayer(),
+		playbackSpeed: 1,
+		scrollSpeed:   150,
+		songDuration:  60 * time.Second,
+	}
+	a.audioPlayer.SetPosition(30 * time.Second)
+
+	// The "now" line of a 1280-wide screen is at x = 256.
+	tests := []struct {
+		x    int
+		want time.Duration
+	}{
+		{256, 30 * time.Second},
+		{406, 31 * time.Second},
+		{1006, 35 * time.Second},
+		{106, 29 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := a.graphClickTime(tt.x, 1280, 720); got != tt.want {
+			t.Errorf("click at x=%d seeks to %v, want %v", tt.x, got, tt.want)
+		}
+	}
+
+	a.audioPlayer.SetPosition(time.Second)
+	if got := a.graphClickTime(0, 1280, 720); got != 0 {
+		t.Errorf("click before the song start seeks to %v, want 0", got)
+	}
+	a.audioPlayer.SetPosition(59 * time.Second)
+	if got := a.graphClickTime(1280, 1280, 720); got != a.songDuration {
+		t.Errorf("click past the song end seeks to %v, want %v", got, a.songDuration)
+	}
+}
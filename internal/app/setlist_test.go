@@ -0,0 +1,71 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// setlistApp returns an app on the results screen of song index of a
+// three-song setlist.
+func setlistApp(t *testing.T, index int) *App {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	songs := []string{
+		filepath.Join(config.SongsDir, "One"),
+		filepath.Join(config.SongsDir, "Two"),
+		filepath.Join(config.SongsDir, "Three"),
+	}
+	a := &App{setlist: songs, setlistIndex: index, songDir: songs[index]}
+	a.showResults(nil)
+	return a
+}
+
+func TestSetlistEndsOnStartScreen(t *testing.T) {
+	a := setlistApp(t, 2)
+	if !a.nextSongAt.IsZero() {
+		t.Error("results after the last song scheduled another one")
+	}
+	a.playNextSetlistSong()
+	if a.state != StateStartScreen || a.setlistIndex != 2 {
+		t.Errorf("after the last song: state %v, index %d; want the start screen at index 2", a.state, a.setlistIndex)
+	}
+}
+
+func TestSetlistSchedulesNextSong(t *testing.T) {
+	a := setlistApp(t, 0)
+	if a.state != StateResults || a.nextSongAt.IsZero() {
+		t.Fatalf("state %v, nextSongAt %v; want the next song scheduled", a.state, a.nextSongAt)
+	}
+}
+
+func TestStopSetlistStartsOver(t *testing.T) {
+	a := setlistApp(t, 1)
+	a.stopSetlist()
+	if !a.nextSongAt.IsZero() {
+		t.Error("Escape kept the automatic advance")
+	}
+
+	// Playing on from the start screen offers the first song, not the third.
+	a.showResults(nil)
+	if a.nextSongAt.IsZero() || a.setlist[a.setlistIndex+1] != a.setlist[0] {
+		t.Errorf("after Escape the next song is index %d, want the first", a.setlistIndex+1)
+	}
+}
+
+func TestPickSongMovesSetlistPosition(t *testing.T) {
+	a := setlistApp(t, 0)
+	a.pickSong(a.setlist[2])
+	if a.setlistIndex != 2 {
+		t.Errorf("picking the third setlist song: index %d, want 2", a.setlistIndex)
+	}
+	if a.songDir != a.setlist[2] || a.state != StateStartScreen {
+		t.Errorf("picked song %q in state %v, want it on the start screen", a.songDir, a.state)
+	}
+
+	a.pickSong(filepath.Join(config.SongsDir, "Elsewhere"))
+	if a.setlistIndex != -1 {
+		t.Errorf("picking an unlisted song: index %d, want -1", a.setlistIndex)
+	}
+}
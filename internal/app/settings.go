@@ -0,0 +1,126 @@
+package app
+
+import (
+	"math"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+loadSongSettings restores the per-song settings of the current song.
+
+Input:
+  - None
+
+Called by:
+  - New, selectSong
+
+Task:
+  - Score each song with the tolerance chosen for it
+
+Logic:
+ 1. config.LoadSongSettings (defaults when there is no settings.json; a bad
+    file is logged and the defaults are used)
+ 2. Store the hit tolerance
+
+Output:
+  - None (sets hitTolerance)
+*/
+func (a *App) loadSongSettings() {
+	s, err := config.LoadSongSettings(a.songDir)
+	if err != nil {
+		logging.Warnf("Ignoring settings.json: %v", err)
+	}
+	a.hitTolerance = s.HitToleranceSemitones
+}
+
+/*
+handleHitToleranceInput adjusts the song's hit tolerance on the start screen.
+
+Input:
+  - None
+
+Called by:
+  - handleStartScreenInput
+
+Task:
+  - Easier scoring for beginners, stricter for advanced singers
+
+Logic:
+ 1. + / numpad +: tolerance +config.HitToleranceStep; - / numpad -: the reverse,
+    within MinHitTolerance..MaxHitTolerance (rounded to one decimal)
+ 2. On a change: save it to the song's settings.json
+
+Output:
+  - None (updates hitTolerance, writes settings.json)
+*/
+func (a *App) handleHitToleranceInput() {
+	step := 0.0
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd) {
+		step = config.HitToleranceStep
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract) {
+		step = -config.HitToleranceStep
+	}
+	if step == 0 || a.songDir == "" {
+		return
+	}
+
+	tol := math.Round((a.hitTolerance+step)*10) / 10
+	tol = max(config.MinHitTolerance, min(config.MaxHitTolerance, tol))
+	if tol == a.hitTolerance {
+		return
+	}
+	a.hitTolerance = tol
+	if err := config.SaveSongSettings(a.songDir, config.SongSettings{HitToleranceSemitones: tol}); err != nil {
+		logging.Warnf("Could not save settings.json: %v", err)
+	}
+}
+
+/*
+handleSensitivityInput drags the mic sensitivity slider on the start screen.
+
+Input:
+  - sh: int - Screen height (for ui.SensitivitySliderPos)
+
+Called by:
+  - handleStartScreenInput
+
+Task:
+  - Let users in quiet rooms lower the noise gate margin
+
+Logic:
+ 1. A click on the slider (track or handle) starts a drag
+ 2. While dragging: ui.SliderValueAt the cursor, rounded to one decimal,
+    within config.MinSensitivity..MaxSensitivity
+ 3. On release: config.SaveSensitivity (logged on error); the next session's
+    mic calibrates with it
+
+Output:
+  - bool: true while the slider has the mouse (the click is not a button press)
+*/
+func (a *App) handleSensitivityInput(sh int) bool {
+	x, y, w := ui.SensitivitySliderPos(sh)
+	mx, my := ebiten.CursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && ui.InRect(mx, my, x-8, y-8, w+16, 16) {
+		a.sensitivityDrag = true
+	}
+	if !a.sensitivityDrag {
+		return false
+	}
+
+	v := ui.SliderValueAt(mx, x, w, config.MinSensitivity, config.MaxSensitivity)
+	a.sensitivity = math.Round(v*10) / 10
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		a.sensitivityDrag = false
+		if err := config.SaveSensitivity(a.sensitivity); err != nil {
+			logging.Warnf("Could not save %s: %v", config.SensitivityFile, err)
+		}
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/audio"
+)
+
+func TestLiveScoreUsesSongHitTolerance(t *testing.T) {
+	t.Chdir(t.TempDir())
+	song := make([]float64, 300)
+	for i := range song {
+		song[i] = 220
+	}
+	// One second sung 1.5 semitones sharp, starting at 0.5 s.
+	var trail []float64
+	for i := range 100 {
+		trail = append(trail, float64(500+10*i), 220*math.Pow(2, 1.5/12))
+	}
+
+	a := &App{mode: audio.ModeSinging, songPitch: song, hitTolerance: 2.0}
+	if got := a.liveScorer(trail)(); got != 100 {
+		t.Errorf("live score at tolerance 2.0 = %v, want 100", got)
+	}
+	a.hitTolerance = 0.7
+	if got := a.liveScorer(trail)(); got != 0 {
+		t.Errorf("live score at tolerance 0.7 = %v, want 0", got)
+	}
+}
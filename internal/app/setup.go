@@ -0,0 +1,190 @@
+package app
+
+import (
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+openSetup shows the first-run setup screen.
+
+Input:
+  - None
+
+Called by:
+  - New when config.NeedsSetup reports a first launch
+
+Task:
+  - Ask for the Python venv before the first separation needs it
+
+Logic:
+ 1. Clear the typed path and message, set state to StateSetup
+
+Output:
+  - None (changes state)
+*/
+func (a *App) openSetup() {
+	a.setupInput = ""
+	a.setupMessage = ""
+	a.state = StateSetup
+}
+
+/*
+handleSetupInput processes keyboard input on the setup screen.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateSetup
+
+Task:
+  - Let the user type, check and save the venv path
+
+Logic:
+ 1. While a venv check is running: apply its result when checkVenv delivers
+    it (applySetupCheck) and ignore the keyboard until then
+ 2. Typed characters extend the path, Backspace removes the last one
+ 3. Enter: run config.RunWizard in checkVenv (a goroutine, it waits for
+    python --version) and show that the path is being checked
+ 4. Escape: skip setup and save an empty venv path, so setup is not shown
+    again and separation uses the system python3
+ 5. Leaving goes to the start screen, or the song browser when no song is chosen
+
+Output:
+  - None (may write venv_path.txt and change state)
+*/
+func (a *App) handleSetupInput() {
+	if a.setupReady != nil {
+		select {
+		case check := <-a.setupReady:
+			a.setupReady = nil
+			a.applySetupCheck(check)
+		default:
+		}
+		return
+	}
+
+	if chars := ebiten.AppendInputChars(nil); len(chars) > 0 {
+		a.setupInput += string(chars)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && a.setupInput != "" {
+		r := []rune(a.setupInput)
+		a.setupInput = string(r[:len(r)-1])
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		a.setupReady = make(chan setupCheck, 1)
+		a.setupMessage = "Checking " + a.setupInput + "..."
+		go checkVenv(a.setupReady, a.setupInput)
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		if err := config.SaveVenvPath(""); err != nil {
+			logging.Warnf("Could not save %s: %v", config.VenvPathFile, err)
+		}
+		a.finishSetup()
+	}
+}
+
+/*
+setupCheck is the result of checkVenv.
+
+Fields:
+  - venv: Path that was checked
+  - ok: Whether config.RunWizard accepted it
+  - msg: Python version, or why the path was rejected
+*/
+type setupCheck struct {
+	venv string
+	ok   bool
+	msg  string
+}
+
+/*
+checkVenv validates a venv path off the UI thread.
+
+Input:
+  - ready: chan setupCheck - Where the result is delivered
+  - venv: string - Path typed on the setup screen
+
+Called by:
+  - handleSetupInput on Enter (as goroutine)
+
+Task:
+  - Keep the window responsive while python --version runs
+
+Logic:
+ 1. config.RunWizard, then send its result on ready (buffered, never blocks)
+
+Output:
+  - None (sends on ready)
+*/
+func checkVenv(ready chan<- setupCheck, venv string) {
+	ok, msg := config.RunWizard(venv)
+	ready <- setupCheck{venv: venv, ok: ok, msg: msg}
+}
+
+/*
+applySetupCheck acts on a finished venv check.
+
+Input:
+  - check: setupCheck - Result from checkVenv
+
+Called by:
+  - handleSetupInput when setupReady delivers
+
+Task:
+  - Save a working venv, or say why it was rejected
+
+Logic:
+ 1. Rejected: show the reason
+ 2. Accepted: save the path (config.SaveVenvPath) and leave setup; a save
+    error is shown instead
+
+Output:
+  - None (may write venv_path.txt and change state)
+*/
+func (a *App) applySetupCheck(check setupCheck) {
+	if !check.ok {
+		a.setupMessage = check.msg
+		return
+	}
+	if err := config.SaveVenvPath(check.venv); err != nil {
+		a.setupMessage = "Could not save " + config.VenvPathFile + ": " + err.Error()
+		return
+	}
+	logging.Infof("Using Python venv %s (%s)", check.venv, check.msg)
+	a.finishSetup()
+}
+
+/*
+finishSetup leaves the setup screen.
+
+Input:
+  - None
+
+Called by:
+  - handleSetupInput (Escape), applySetupCheck
+
+Task:
+  - Continue where New would have started
+
+Logic:
+ 1. No song chosen: open the song browser
+ 2. Otherwise: the start screen
+
+Output:
+  - None (changes state)
+*/
+func (a *App) finishSetup() {
+	if a.songDir == "" {
+		a.openSongBrowser()
+		return
+	}
+	a.state = StateStartScreen
+}
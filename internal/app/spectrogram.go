@@ -0,0 +1,81 @@
+package app
+
+import (
+	"singAssist/internal/config"
+	"singAssist/internal/ui"
+)
+
+// VisualizationMode selects how the user's voice is drawn while playing.
+type VisualizationMode int
+
+const (
+	VizPitchLine   VisualizationMode = iota // Detected pitch trail (default)
+	VizSpectrogram                          // Scrolling microphone spectrogram
+)
+
+/*
+toggleVisualization switches between the pitch line and the spectrogram.
+
+Input:
+  - None
+
+Called by:
+  - handlePlayingInput when S is pressed (without Shift)
+
+Task:
+  - Flip vizMode
+
+Logic:
+ 1. Lock mu (micLoop reads vizMode and appends to spectrum)
+ 2. Switch mode and drop old spectrogram columns
+
+Output:
+  - None (updates vizMode)
+*/
+func (a *App) toggleVisualization() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.vizMode == VizPitchLine {
+		a.vizMode = VizSpectrogram
+	} else {
+		a.vizMode = VizPitchLine
+	}
+	a.spectrum = nil
+}
+
+/*
+addSpectrumColumn stores one analyzed mic buffer for the spectrogram.
+
+Input:
+  - timeMs: float64 - Playback position of the buffer
+  - levels: []float64 - ui.MicSpectrum of the buffer
+
+Called by:
+  - micLoop (with mu held) while the spectrogram view is on
+
+Task:
+  - Keep a bounded, time-ordered column history
+
+Logic:
+ 1. A position earlier than the newest column (seek, echo loop): start over
+ 2. Append the column
+ 3. Drop columns older than config.MaxUserPitchHistory seconds
+
+Output:
+  - None (updates spectrum)
+*/
+func (a *App) addSpectrumColumn(timeMs float64, levels []float64) {
+	if n := len(a.spectrum); n > 0 && timeMs < a.spectrum[n-1].TimeMs {
+		a.spectrum = a.spectrum[:0]
+	}
+	a.spectrum = append(a.spectrum, ui.SpectrumColumn{TimeMs: timeMs, Levels: levels})
+
+	cut := 0
+	for cut < len(a.spectrum) && a.spectrum[cut].TimeMs < timeMs-config.MaxUserPitchHistory*1000 {
+		cut++
+	}
+	if cut > 0 {
+		a.spectrum = append(a.spectrum[:0], a.spectrum[cut:]...)
+	}
+}
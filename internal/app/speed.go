@@ -0,0 +1,203 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+
+	eaudio "github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+/*
+songPosition returns the player's position in song time.
+
+Input:
+  - None (audioPlayer must be set)
+
+Called by:
+  - playbackPos, handlePlayingInput, retuneAnalysis, handleLoopInput, applyLoop
+
+Task:
+  - Keep song pitch, loops and seeking in song time while the audio is stretched
+
+Logic:
+ 1. Player position × playbackSpeed
+
+Output:
+  - time.Duration: Position within the song
+*/
+func (a *App) songPosition() time.Duration {
+	return time.Duration(float64(a.audioPlayer.Position()) * a.playbackSpeed)
+}
+
+/*
+seekSong moves the player to a position in song time.
+
+Input:
+  - pos: time.Duration - Position within the song (audioPlayer must be set)
+
+Called by:
+  - handlePlayingInput, applyLoop, applySpeedChange
+
+Task:
+  - Inverse of songPosition
+
+Logic:
+ 1. SetPosition(pos / playbackSpeed)
+
+Output:
+  - None (seeks the player)
+*/
+func (a *App) seekSong(pos time.Duration) {
+	a.audioPlayer.SetPosition(time.Duration(float64(pos) / a.playbackSpeed))
+}
+
+/*
+handlePlaybackSpeedInput halves or doubles the playback speed.
+
+Input:
+  - up, down: bool - Ctrl+Up / Ctrl+Down was just pressed
+
+Called by:
+  - handlePlayingInput
+
+Task:
+  - Slow fast passages down for learning
+
+Logic:
+ 1. Nothing without a player, for rough vocals (the played audio is filtered,
+    not a file) or while a speed change is still being prepared
+ 2. Ctrl+Down: halve; Ctrl+Up: double, within config.MinPlaybackSpeed..MaxPlaybackSpeed
+ 3. On a change: changePlaybackSpeed in a goroutine (ffmpeg may take a while),
+    which hands the new player to applySpeedChange through speedReady
+
+Output:
+  - None (starts the change)
+*/
+func (a *App) handlePlaybackSpeedInput(up, down bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.audioPlayer == nil || a.songAudioFile == "" || a.mode == audio.ModeRoughVocals || a.speedReady != nil {
+		return
+	}
+	speed := a.playbackSpeed
+	if down {
+		speed = max(config.MinPlaybackSpeed, speed/2)
+	}
+	if up {
+		speed = min(config.MaxPlaybackSpeed, speed*2)
+	}
+	if speed == a.playbackSpeed {
+		return
+	}
+	a.speedReady = make(chan speedChange, 1)
+	a.message = fmt.Sprintf("Preparing %.2fx playback...", speed)
+	go a.changePlaybackSpeed(a.speedReady, speed, a.audioPlayer, a.songAudioFile, a.opts.Load.Start, a.opts.Load.End)
+}
+
+/*
+speedChange is a player prepared by changePlaybackSpeed.
+
+Fields:
+  - speed: Playback speed of player
+  - player: New player (nil on error)
+  - old: Player it replaces
+  - err: Stretch, decode or player error
+*/
+type speedChange struct {
+	speed  float64
+	player *eaudio.Player
+	old    *eaudio.Player
+	err    error
+}
+
+/*
+changePlaybackSpeed prepares a player at a different speed.
+
+Input:
+  - ready: chan speedChange - Where the result is delivered
+  - speed: float64 - New playback speed
+  - old: *eaudio.Player - Player the session uses now
+  - file: string - songAudioFile
+  - start, end: time.Duration - Practice section in song time
+
+Called by:
+  - handlePlaybackSpeedInput (as goroutine)
+
+Task:
+  - Play the song slower or faster without changing its pitch
+
+Logic:
+ 1. audio.NewSpeedPlayer for file and the practice section (the stretched
+    file is cached as e.g. song_0.50x.mp3)
+ 2. Send the result on ready; applySpeedChange swaps it in on the Update
+    thread, so Update never sees audioPlayer and playbackSpeed change under it
+
+Output:
+  - None (sends one speedChange)
+*/
+func (a *App) changePlaybackSpeed(ready chan<- speedChange, speed float64, old *eaudio.Player, file string, start, end time.Duration) {
+	player, err := audio.NewSpeedPlayer(file, speed, start, end)
+	ready <- speedChange{speed: speed, player: player, old: old, err: err}
+}
+
+/*
+applySpeedChange swaps in a player prepared by changePlaybackSpeed.
+
+Input:
+  - None
+
+Called by:
+  - Update, every frame
+
+Task:
+  - Switch playback speed on the thread that reads audioPlayer
+
+Logic:
+ 1. Nothing unless a speed change is pending and its result has arrived
+ 2. On error: log it and show it as the message
+ 3. If the session moved on to another player meanwhile: close the new one
+ 4. Otherwise: remember the song position and whether it was playing, close
+    the old player, switch to the new one at the same song position
+
+Output:
+  - None (updates audioPlayer, playbackSpeed and message)
+*/
+func (a *App) applySpeedChange() {
+	if a.speedReady == nil {
+		return
+	}
+	var change speedChange
+	select {
+	case change = <-a.speedReady:
+	default:
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.speedReady = nil
+	if change.err != nil {
+		logging.Warnf("Could not change playback speed: %v", change.err)
+		a.message = fmt.Sprintf("Speed change failed: %v", change.err)
+		return
+	}
+	if a.audioPlayer != change.old || change.old == nil {
+		change.player.Close()
+		return
+	}
+
+	pos, playing := a.songPosition(), change.old.IsPlaying()
+	change.old.Pause()
+	change.old.Close()
+	a.audioPlayer, a.playbackSpeed = change.player, change.speed
+	a.seekSong(pos)
+	if playing {
+		change.player.Play()
+	}
+	a.message = ""
+}
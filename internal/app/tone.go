@@ -0,0 +1,92 @@
+package app
+
+import (
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/theory"
+	"singAssist/internal/ui"
+	"singAssist/internal/warmup"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+handleReferenceToneInput drives the no-audio mode pitch pipe.
+
+Input:
+  - up, down: bool - Whether the transpose keys (+/-) were just pressed
+
+Called by:
+  - handlePlayingInput in ModeNoAudio
+
+Task:
+  - Let the user hear a note to tune against when there is no song
+
+Logic:
+ 1. config.Keys.ReferenceTone: start a tone at toneMidi, or stop the one playing
+ 2. While it plays (and Ctrl is not held): +/- move toneMidi a semitone (C2..C6)
+    and retune the tone
+
+Output:
+  - bool: true while the tone plays, so +/- do not also transpose
+*/
+func (a *App) handleReferenceToneInput(up, down bool) bool {
+	if inpututil.IsKeyJustPressed(config.Keys.ReferenceTone) {
+		if a.refTone != nil {
+			a.refTone.Stop()
+			a.refTone = nil
+		} else {
+			a.refTone = audio.NewReferenceTonePlayer(warmup.MidiToFreq(a.toneMidi))
+			if err := a.refTone.Play(); err != nil {
+				logging.Warnf("Could not play reference tone: %v", err)
+				a.refTone = nil
+			}
+		}
+	}
+	if a.refTone == nil {
+		return false
+	}
+
+	if !ebiten.IsKeyPressed(ebiten.KeyControl) && (up || down) {
+		if up {
+			a.toneMidi = min(84, a.toneMidi+1)
+		} else {
+			a.toneMidi = max(36, a.toneMidi-1)
+		}
+		if err := a.refTone.SetFrequency(warmup.MidiToFreq(a.toneMidi)); err != nil {
+			logging.Warnf("Could not retune reference tone: %v", err)
+		}
+	}
+	return true
+}
+
+/*
+drawReferenceTone labels the reference tone in the HUD.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+
+Called by:
+  - drawPlayingMode, drawFreestyleMode in ModeNoAudio
+
+Task:
+  - Show which note is sounding next to the user's pitch
+
+Logic:
+ 1. Nothing while the tone is off
+ 2. ui.DrawReferenceTone with the tone's note name and frequency
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawReferenceTone(screen *ebiten.Image, sw int) {
+	if a.refTone == nil {
+		return
+	}
+	freq := a.refTone.Frequency()
+	note, octave := theory.FreqToNote(freq)
+	ui.DrawReferenceTone(screen, sw, note, octave, freq)
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/ui"
+	"singAssist/internal/warmup"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// warmupRows is the number of settings in the warmup sub-menu
+// (scale, starting octave, tempo).
+const warmupRows = 3
+
+// warmupBPMStep is how much LEFT/RIGHT change the tempo.
+const warmupBPMStep = 10.0
+
+/*
+openWarmupMenu loads the saved warmup settings and shows the sub-menu.
+
+Input:
+  - None
+
+Called by:
+  - handleStartScreenInput when the Warmup button is clicked
+
+Task:
+  - Start the sub-menu from the last used exercise
+
+Logic:
+ 1. warmup.LoadConfig on the song's warmup.json (defaults on error, logged)
+ 2. Select the first row and switch to StateWarmupMenu
+
+Output:
+  - None (changes state)
+*/
+func (a *App) openWarmupMenu() {
+	cfg, err := warmup.LoadConfig(config.GetSongPaths(a.songDir).WarmupFile)
+	if err != nil {
+		logging.Warnf("Could not read warmup.json, using defaults: %v", err)
+	}
+	a.warmupCfg = cfg
+	a.warmupRow = 0
+	a.state = StateWarmupMenu
+}
+
+/*
+handleWarmupMenuInput processes keyboard input in the warmup sub-menu.
+
+Input:
+  - None
+
+Called by:
+  - Update when state is StateWarmupMenu
+
+Task:
+  - Edit the exercise and start it
+
+Logic:
+ 1. Escape: back to the start screen
+ 2. Up/Down: move between rows
+ 3. Left/Right: cycle the pattern, change the octave, or change the BPM by warmupBPMStep
+    (settings are clamped the same way Exercise clamps them)
+ 4. Enter: save warmup.json and start ModeWarmup
+
+Output:
+  - None (updates warmupCfg, may change state)
+*/
+func (a *App) handleWarmupMenuInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		a.state = StateStartScreen
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		a.warmupRow = (a.warmupRow + warmupRows - 1) % warmupRows
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		a.warmupRow = (a.warmupRow + 1) % warmupRows
+	}
+
+	delta := 0
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+		delta = -1
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		delta = 1
+	}
+	if delta != 0 {
+		ex := a.warmupCfg.Exercise()
+		switch a.warmupRow {
+		case 0:
+			i := slices.Index(warmup.Patterns, ex.Pattern)
+			i = (i + len(warmup.Patterns) + delta) % len(warmup.Patterns)
+			a.warmupCfg.Pattern = warmup.PatternName(warmup.Patterns[i])
+		case 1:
+			a.warmupCfg.Octave = max(warmup.MinOctave, min(warmup.MaxOctave, ex.RootMidi/12-1+delta))
+		case 2:
+			a.warmupCfg.BPM = max(warmup.MinBPM, min(warmup.MaxBPM, ex.BPM+float64(delta)*warmupBPMStep))
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		if err := warmup.SaveConfig(config.GetSongPaths(a.songDir).WarmupFile, a.warmupCfg); err != nil {
+			logging.Warnf("Could not save warmup.json: %v", err)
+		}
+		a.exercise = a.warmupCfg.Exercise()
+		a.startGame(audio.ModeWarmup)
+	}
+}
+
+/*
+drawWarmupMenu renders the warmup sub-menu.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - Draw when state is StateWarmupMenu
+
+Task:
+  - Show the current settings
+
+Logic:
+ 1. Format the scale, starting note and tempo rows from the clamped exercise
+ 2. Call ui.DrawWarmupMenu with the exercise label as summary
+
+Output:
+  - None (draws to screen)
+*/
+func (a *App) drawWarmupMenu(screen *ebiten.Image, sw, sh int) {
+	ex := a.warmupCfg.Exercise()
+	rows := []string{
+		"Scale: " + ex.Pattern.String(),
+		fmt.Sprintf("Starting note: C%d", ex.RootMidi/12-1),
+		fmt.Sprintf("Tempo: %.0f BPM", ex.BPM),
+	}
+	ui.DrawWarmupMenu(screen, rows, a.warmupRow, "Warmup: "+ex.Label(), sw, sh)
+}
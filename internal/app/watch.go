@@ -0,0 +1,94 @@
+package app
+
+import (
+	"singAssist/internal/audio"
+	"singAssist/internal/logging"
+	"singAssist/internal/scoring"
+)
+
+/*
+startSongWatcher watches the song file for the session that is starting.
+
+Input:
+  - None
+
+Called by:
+  - startGame after the microphone started
+
+Task:
+  - Notice a re-downloaded or replaced song while it is being practised
+
+Logic:
+ 1. Skip modes that do not play the song file (warmup, freestyle, no-audio,
+    range test, interval quiz)
+ 2. audio.WatchSongDir; its callback sets songChanged under mu, and Update
+    calls reloadChangedSong (a watcher error is only logged)
+
+Output:
+  - None (sets songWatcher)
+*/
+func (a *App) startSongWatcher() {
+	switch a.mode {
+	case audio.ModeWarmup, audio.ModeFreestyle, audio.ModeNoAudio, audio.ModeRangeTest, audio.ModeIntervalQuiz:
+		return
+	}
+
+	w, err := audio.WatchSongDir(a.songDir, func() {
+		a.mu.Lock()
+		a.songChanged = true
+		a.mu.Unlock()
+	})
+	if err != nil {
+		logging.Warnf("Not watching %s for changes: %v", a.songDir, err)
+		return
+	}
+	a.songWatcher = w
+}
+
+/*
+reloadChangedSong re-analyzes the song after its file changed.
+
+Input:
+  - None
+
+Called by:
+  - Update every frame
+
+Task:
+  - Replace the stale song data without recalibrating the microphone
+
+Logic:
+ 1. Nothing to do unless songChanged is set; wait while the song is still
+    loading (Playing without a player) so two loads never overlap
+ 2. Stop and drop the player (if one was loaded); reset both singers' userPitch and sessionPitch,
+    and the heatmap
+ 3. Run loadSong with "Song file changed, re-analyzing…" in a goroutine
+    (the watcher already deleted the pitch cache, micLoop keeps running)
+
+Output:
+  - None (changes state)
+*/
+func (a *App) reloadChangedSong() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.songChanged || (a.state != StateCountdown && (a.state != StatePlaying || a.audioPlayer == nil)) {
+		return
+	}
+	a.songChanged = false
+
+	if a.audioPlayer != nil {
+		a.audioPlayer.Pause()
+		a.audioPlayer.Close()
+		a.audioPlayer = nil
+	}
+	a.userPitch = make([]float64, 0)
+	a.formant1Pitch = make([]float64, 0)
+	a.sessionPitch = a.sessionPitch[:0]
+	a.userPitch2 = make([]float64, 0)
+	a.sessionPitch2 = a.sessionPitch2[:0]
+	a.liveScore, a.liveScoreFrames = 0, 0
+	a.heatmap = scoring.NewHeatmap(a.opts.IgnoreOctave)
+	a.state = StatePlaying
+
+	go a.loadSong("Song file changed, re-analyzing…")
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/audio"
+)
+
+func TestReloadChangedSongResetsBothSingers(t *testing.T) {
+	a := &App{
+		state:         StatePlaying,
+		mode:          audio.ModeFreestyle,
+		audioPlayer:   newTestPlayer(),
+		songChanged:   true,
+		userPitch:     []float64{100, 220},
+		userPitch2:    []float64{100, 330},
+		sessionPitch:  []float64{100, 220, 110, 220},
+		sessionPitch2: []float64{100, 330, 110, 330},
+	}
+
+	a.reloadChangedSong()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.songChanged {
+		t.Error("songChanged still set after the reload started")
+	}
+	if len(a.userPitch) != 0 || len(a.sessionPitch) != 0 {
+		t.Errorf("first singer kept %d trail and %d session values", len(a.userPitch), len(a.sessionPitch))
+	}
+	if len(a.userPitch2) != 0 || len(a.sessionPitch2) != 0 {
+		t.Errorf("second singer kept %d trail and %d session values", len(a.userPitch2), len(a.sessionPitch2))
+	}
+}
+
+func TestReloadChangedSongDuringCountdownWithoutPlayer(t *testing.T) {
+	// Range test and interval quiz sessions count down without a player.
+	a := &App{state: StateCountdown, mode: audio.ModeRangeTest, songChanged: true}
+	a.reloadChangedSong()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.songChanged {
+		t.Error("songChanged still set after the reload started")
+	}
+}
+
+func TestSongWatcherSkipsModesWithoutTheSongFile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "song.mp3"), []byte("song"), 0644)
+	for _, mode := range []audio.Mode{audio.ModeWarmup, audio.ModeFreestyle, audio.ModeNoAudio, audio.ModeRangeTest, audio.ModeIntervalQuiz} {
+		a := &App{songDir: dir, mode: mode}
+		a.startSongWatcher()
+		if a.songWatcher != nil {
+			a.songWatcher.Close()
+			t.Errorf("%v: watching the song file", mode)
+		}
+	}
+
+	a := &App{songDir: dir, mode: audio.ModeSinging}
+	a.startSongWatcher()
+	if a.songWatcher == nil {
+		t.Fatal("vocals session is not watching the song file")
+	}
+	a.songWatcher.Close()
+}
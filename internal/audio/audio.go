@@ -2,17 +2,22 @@ package audio
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
-	"io"
-	"log"
+	"math"
+	"math/rand/v2"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"singAssist/internal/config"
+	"singAssist/internal/logging"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
 )
 
 var AudioContext *audio.Context
@@ -28,18 +33,280 @@ const (
 	ModeInstrumental
 	ModeFullMix
 	ModeNoAudio
+	ModeRoughVocals
+	ModeDuet
+	ModeWarmup
+	ModeHarmony
+	ModeFreestyle
+	ModeRangeTest
+	ModeIntervalQuiz
 )
 
+// allModes lists every Mode in menu/help order.
+var allModes = []Mode{ModeSinging, ModeRoughVocals, ModeInstrumental, ModeFullMix, ModeNoAudio, ModeDuet, ModeWarmup, ModeHarmony, ModeFreestyle, ModeRangeTest, ModeIntervalQuiz}
+
+// modeNames maps each Mode to the name used on the command line.
+var modeNames = map[Mode]string{
+	ModeSinging:      "vocals",
+	ModeInstrumental: "instrumental",
+	ModeFullMix:      "fullmix",
+	ModeNoAudio:      "noaudio",
+	ModeRoughVocals:  "roughvocals",
+	ModeDuet:         "duet",
+	ModeWarmup:       "warmup",
+	ModeHarmony:      "harmony",
+	ModeFreestyle:    "freestyle",
+	ModeRangeTest:    "rangetest",
+	ModeIntervalQuiz: "intervalquiz",
+}
+
+/*
+IsVocal reports whether the mode plays and analyzes a vocals-only track.
+
+Input:
+  - None
+
+Called by:
+  - analyzePitch, calibrateSilenceFromAudio, MicHandler.DetectPitchFromMic
+
+Task:
+  - Group modes that use the narrower vocal frequency range
+
+Logic:
+ 1. True for ModeSinging, ModeRoughVocals, ModeDuet, ModeWarmup, ModeHarmony and ModeFreestyle
+
+Output:
+  - bool: true for vocal modes
+*/
+func (m Mode) IsVocal() bool {
+	return m == ModeSinging || m == ModeRoughVocals || m == ModeDuet || m == ModeWarmup || m == ModeHarmony || m == ModeFreestyle
+}
+
+/*
+String returns the command-line name of the mode.
+
+Input:
+  - None
+
+Called by:
+  - ParseMode error messages, logging
+
+Task:
+  - Give each mode a stable, human-readable name
+
+Logic:
+ 1. Look up mode in modeNames
+ 2. Fall back to "Mode(<n>)" for unknown values
+
+Output:
+  - string: Mode name (e.g., "fullmix")
+*/
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+/*
+ParseMode converts a mode name into a Mode.
+
+Input:
+  - name: string - Mode name, case-insensitive (e.g., "fullmix", "Vocals")
+
+Called by:
+  - main.main when handling the -mode flag
+
+Task:
+  - Validate a user-supplied mode string
+
+Logic:
+ 1. Lower-case and trim the name
+ 2. Compare against every entry in modeNames
+ 3. Return an error listing the valid names if nothing matches
+
+Output:
+  - Mode: Matching mode
+  - error: nil on success, descriptive error for unknown names
+*/
+func ParseMode(name string) (Mode, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	valid := make([]string, 0, len(allModes))
+	for _, m := range allModes {
+		if modeNames[m] == name {
+			return m, nil
+		}
+		valid = append(valid, modeNames[m])
+	}
+	return 0, fmt.Errorf("unknown mode %q (valid: %s)", name, strings.Join(valid, ", "))
+}
+
 /*
 LoadResult contains the results from loading and analyzing a song.
 
 Fields:
   - Player: Ebiten audio player for playback (nil for ModeNoAudio)
   - SongPitch: Slice of pitch values at 10ms intervals (100 samples/second)
+  - SongPitches: One pitch array per vocal part (left and right channel, see
+    AnalyzeMultiChannel); nil unless LoadOptions.Parts > 1
+  - Duration: Length of the loaded (possibly cropped) audio
+  - PCM: Decoded PCM that was analyzed, kept for windowed re-analysis
+  - Waveform: Normalized RMS energy per overview bin (see ComputeWaveformThumbnail)
+  - Chords: Chord frequencies per 10ms frame (ModeInstrumental only, see analyzeChords)
+  - BPM: Tempo detected by DetectBPM (0 for a reference MIDI or if none was found)
+  - Key: Key detected from SongPitch by DetectKey (e.g. "D Major", "" if unknown)
+  - AudioFile: File the player plays, before cropping ("" for a reference MIDI)
 */
 type LoadResult struct {
-	Player    *audio.Player
-	SongPitch []float64
+	Player      *audio.Player
+	SongPitch   []float64
+	SongPitches [][]float64
+	Duration    time.Duration
+	PCM         []byte
+	Waveform    []float64
+	Chords      [][]float64
+	BPM         float64
+	Key         string
+	AudioFile   string
+}
+
+/*
+LoadOptions controls how LoadAndAnalyzeSong loads and analyzes a song.
+
+Fields:
+  - Start: Offset of the practice section from the song start (0 = beginning)
+  - End: Offset where the practice section stops (0 = end of song)
+  - Analysis: Pitch detection parameters (use DefaultAnalysisParams)
+  - Recache: Ignore any saved pitch cache and analyze again
+  - OnProgress: Called with analyzed/total 10ms frames while the pitch is analyzed (can be nil)
+  - Parts: Vocal parts on separate stereo channels (-parts); above 1 also fills
+    LoadResult.SongPitches
+*/
+type LoadOptions struct {
+	Start      time.Duration
+	End        time.Duration
+	Analysis   AnalysisParams
+	Recache    bool
+	OnProgress func(done, total int)
+	Parts      int
+}
+
+/*
+AnalysisParams holds song pitch detection parameters that can be tuned at runtime.
+
+Fields:
+  - SilenceFactor: Silence threshold as a multiple of the 10th-percentile energy
+  - MinConfidence: Frames with lower periodicity (DetectPitchWithConfidence) are silence
+  - Channel: Which stereo channel to analyze (default ChannelMix)
+  - Gaps: How silence gaps are bridged in instrumental/full mix modes
+*/
+type AnalysisParams struct {
+	SilenceFactor float64
+	MinConfidence float64
+	Channel       Channel
+	Gaps          GapMode
+}
+
+// GapMode selects how short silences in the song pitch line are filled.
+type GapMode int
+
+const (
+	GapFixed    GapMode = iota // Bridge gaps up to the per-mode config limit
+	GapAdaptive                // Scale the limit by the surrounding note lengths
+	GapOff                     // Leave gaps as detected
+)
+
+var gapModeNames = []string{"fixed", "adaptive", "off"}
+
+/*
+String returns the display name of the gap mode.
+
+Input:
+  - None
+
+Called by:
+  - App.retuneAnalysis and App.reanalyzeSong for the status message
+
+Task:
+  - Human-readable gap mode names
+
+Logic:
+ 1. Index into gapModeNames
+
+Output:
+  - string: "fixed", "adaptive" or "off"
+*/
+func (g GapMode) String() string {
+	if g >= 0 && int(g) < len(gapModeNames) {
+		return gapModeNames[g]
+	}
+	return fmt.Sprintf("GapMode(%d)", int(g))
+}
+
+// Channel selects which side of a stereo file is analyzed for song pitch.
+type Channel int
+
+const (
+	ChannelMix Channel = iota
+	ChannelLeft
+	ChannelRight
+)
+
+/*
+ParseChannel converts a channel name into a Channel.
+
+Input:
+  - name: string - "mix", "left" or "right" (case-insensitive)
+
+Called by:
+  - main.main when handling the -channel flag
+
+Task:
+  - Validate a user-supplied channel string
+
+Logic:
+ 1. Lower-case, trim and match the three known names
+
+Output:
+  - Channel: Matching channel
+  - error: nil on success, descriptive error otherwise
+*/
+func ParseChannel(name string) (Channel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "mix":
+		return ChannelMix, nil
+	case "left", "l":
+		return ChannelLeft, nil
+	case "right", "r":
+		return ChannelRight, nil
+	}
+	return 0, fmt.Errorf("unknown channel %q (valid: mix, left, right)", name)
+}
+
+/*
+DefaultAnalysisParams returns the parameters used when nothing is tuned.
+
+Input:
+  - None
+
+Called by:
+  - main.main when building app options
+
+Task:
+  - Single source for default analysis settings
+
+Logic:
+ 1. SilenceFactor = 3.0
+ 2. MinConfidence = config.MinPitchConfidence
+
+Output:
+  - AnalysisParams: Defaults
+*/
+func DefaultAnalysisParams() AnalysisParams {
+	return AnalysisParams{
+		SilenceFactor: 3.0,
+		MinConfidence: config.MinPitchConfidence,
+	}
 }
 
 /*
@@ -47,7 +314,8 @@ LoadAndAnalyzeSong loads audio from a song directory and analyzes pitch.
 
 Input:
   - songDir: string - Path to song directory (e.g., "songs/MySong")
-  - mode: Mode - Playback mode (ModeSinging, ModeInstrumental, ModeFullMix, ModeNoAudio)
+  - mode: Mode - Playback mode (ModeSinging, ModeInstrumental, ModeFullMix, ModeNoAudio, ModeRoughVocals, ModeDuet, ModeHarmony)
+  - opts: LoadOptions - Optional practice section and analysis parameters
   - onMessage: func(string) - Callback for status messages (can be nil)
 
 Called by:
@@ -61,81 +329,123 @@ Task:
 
 Logic:
  1. Get file paths from config.GetSongPaths
- 2. For ModeSinging/ModeInstrumental: check if separated files exist
- 3. If separation needed: run separate.py using config.GetPythonPath
- 4. Open appropriate audio file (vocals/accompaniment/original)
- 5. Decode MP3 to PCM data
- 6. Create ebiten audio.Player from PCM (skip for ModeNoAudio)
- 7. Run analyzePitch to extract pitch contour
+    (ModeNoAudio with reference.mid: return the MIDI melody, cropped after checkSection,
+    and its key, no player or PCM)
+ 2. For ModeSinging/ModeDuet/ModeHarmony/ModeInstrumental: check that the separated
+    track exists and is not older than the song file (stemIsStale)
+ 3. If separation needed: runSeparation (separate_spleeter.py or separate_demucs.py);
+    in offline mode (config.IsOfflineMode) warn "Separation unavailable (offline mode)"
+    and analyze as ModeFullMix instead
+ 4. Pick the appropriate audio file (vocals/accompaniment/original)
+ 5. Decode it to PCM with decodeAudioFile (MP3, OGG, FLAC or WAV)
+ 6. Reject an empty or reversed opts.Start..opts.End section (checkSection),
+    then crop PCM to it via cropPCM
+    (ModeRoughVocals: replace PCM with SeparateSpectral vocals estimate)
+ 7. Create ebiten audio.Player from PCM (skip for ModeNoAudio)
+ 8. Run analyzePitch to extract pitch contour, keep PCM for re-analysis
+    (whole song with default parameters: load the per-mode and per-detector
+    pitch cache if it is newer than the audio file, from the current
+    pitchCacheVersion and Recache is off, else save a fresh one);
+    its progress goes to opts.OnProgress and to onMessage as analysisMessage
+ 9. Whole song in a vocal mode: save its ComputeDifficulty rating to difficulty.json;
+    ModeInstrumental: detect chords with analyzeChords (not cached);
+    opts.Parts > 1 in a vocal mode other than harmony: one contour per
+    channel with AnalyzeMultiChannel (not cached)
+ 10. Whole song: reuse the tempo and key from metadata.json if it is newer
+    than the audio file and was detected with the same pitch cache (and
+    Recache is off); otherwise detect them with DetectBPM and DetectKey and,
+    for the whole song, save both to metadata.json
+ 11. Compute the waveform overview with ComputeWaveformThumbnail
 
 Output:
-  - *LoadResult: Contains Player and SongPitch data
+  - *LoadResult: Contains Player, SongPitch, SongPitches, Duration, PCM, Waveform, Chords, BPM, Key and AudioFile
   - error: nil on success, descriptive error on failure
 */
-func LoadAndAnalyzeSong(songDir string, mode Mode, onMessage func(string)) (*LoadResult, error) {
+func LoadAndAnalyzeSong(songDir string, mode Mode, opts LoadOptions, onMessage func(string)) (*LoadResult, error) {
 	paths := config.GetSongPaths(songDir)
 	var audioFile string
 
-	if mode == ModeSinging || mode == ModeInstrumental {
-		needsSeparation := false
-		if mode == ModeSinging {
-			if _, err := os.Stat(paths.VocalsFile); os.IsNotExist(err) {
-				needsSeparation = true
+	if mode == ModeNoAudio {
+		if _, err := os.Stat(paths.MidiFile); err == nil {
+			pitch, err := LoadReferenceMIDI(paths.MidiFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %v", paths.MidiFile, err)
 			}
-		} else {
-			if _, err := os.Stat(paths.AccompFile); os.IsNotExist(err) {
-				needsSeparation = true
+			logging.Infof("Using reference MIDI")
+			if err := checkSection(opts.Start, opts.End, time.Duration(len(pitch))*10*time.Millisecond); err != nil {
+				return nil, err
 			}
+			pitch = cropPitch(pitch, opts.Start, opts.End)
+			root, minor, _ := DetectKey(pitch)
+			return &LoadResult{
+				SongPitch: pitch,
+				Duration:  time.Duration(len(pitch)) * 10 * time.Millisecond,
+				Key:       KeyName(root, minor),
+			}, nil
 		}
+	}
 
-		if needsSeparation {
-			log.Println("Running audio separation (this may take a minute)...")
+	if mode == ModeSinging || mode == ModeDuet || mode == ModeHarmony || mode == ModeInstrumental {
+		stem := paths.VocalsFile
+		if mode == ModeInstrumental {
+			stem = paths.AccompFile
+		}
+		needsSeparation := stemIsStale(stem, paths.SongFile)
+
+		if needsSeparation && config.IsOfflineMode() {
+			logging.Warnf("Separation unavailable (offline mode), analyzing the full mix")
+			if onMessage != nil {
+				onMessage("Separation unavailable (offline mode)")
+			}
+			mode = ModeFullMix
+		} else if needsSeparation {
+			logging.Infof("Running audio separation (this may take a minute)...")
 			if onMessage != nil {
 				onMessage("Separating audio (may take a minute)...")
 			}
 
-			pythonCmd := config.GetPythonPath()
-			log.Printf("Using Python: %s", pythonCmd)
-
-			cmd := exec.Command(pythonCmd, "separate.py", paths.SongFile, songDir)
-			output, err := cmd.CombinedOutput()
-			log.Printf("Separator output: %s", string(output))
-
-			if err != nil {
-				return nil, fmt.Errorf("separation failed: %v\nOutput: %s", err, string(output))
+			if err := runSeparation(paths); err != nil {
+				return nil, err
 			}
 		}
 
-		if mode == ModeSinging {
+		if mode == ModeFullMix {
+			audioFile = paths.SongFile
+		} else if mode != ModeInstrumental {
 			audioFile = paths.VocalsFile
-			log.Println("Using vocals track")
+			logging.Infof("Using vocals track")
 		} else {
 			audioFile = paths.AccompFile
-			log.Println("Using accompaniment track")
+			logging.Infof("Using accompaniment track")
 		}
+	} else if mode == ModeRoughVocals {
+		audioFile = paths.SongFile
+		logging.Infof("Using built-in spectral separation (rough quality)")
 	} else {
 		audioFile = paths.SongFile
-		log.Println("Using full mix")
+		logging.Infof("Using full mix")
 	}
 
-	f, err := os.Open(audioFile)
+	pcmData, err := decodeAudioFile(audioFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open %s: %v", audioFile, err)
+		return nil, err
 	}
-	defer f.Close()
-
-	d, err := mp3.DecodeWithoutResampling(f)
-	if err != nil {
+	if err := checkSection(opts.Start, opts.End, time.Duration(len(pcmData)/4)*time.Second/config.SampleRate); err != nil {
 		return nil, err
 	}
+	pcmBytes := cropPCM(pcmData, opts.Start, opts.End)
 
-	var pcmData bytes.Buffer
-	if _, err := io.Copy(&pcmData, d); err != nil {
-		return nil, err
+	if mode == ModeRoughVocals {
+		if onMessage != nil {
+			onMessage("Separating vocals (built-in, rough quality)...")
+		}
+		pcmBytes, _ = SeparateSpectral(pcmBytes)
 	}
-	pcmBytes := pcmData.Bytes()
 
-	result := &LoadResult{}
+	result := &LoadResult{
+		Duration:  time.Duration(len(pcmBytes)/4) * time.Second / config.SampleRate,
+		AudioFile: audioFile,
+	}
 
 	if mode != ModeNoAudio {
 		playerRead := bytes.NewReader(pcmBytes)
@@ -145,87 +455,473 @@ func LoadAndAnalyzeSong(songDir string, mode Mode, onMessage func(string)) (*Loa
 		}
 	}
 
-	result.SongPitch = analyzePitch(pcmBytes, mode)
+	cacheable := opts.Start == 0 && opts.End == 0 && opts.Analysis == DefaultAnalysisParams()
+	cachePath := pitchCachePath(paths.PitchCacheFile, mode, pitchAlgorithm)
+	if cacheable && !opts.Recache && cacheIsFresh(cachePath, audioFile) {
+		if pitch, err := LoadPitchCache(cachePath); err == nil {
+			logging.Infof("Loaded song pitch from %s", cachePath)
+			result.SongPitch = pitch
+		} else {
+			logging.Warnf("Ignoring pitch cache: %v", err)
+		}
+	}
+	if result.SongPitch == nil {
+		analysisStart := time.Now()
+		result.SongPitch = analyzePitch(pcmBytes, mode, opts.Analysis, func(done, total int) {
+			if opts.OnProgress != nil {
+				opts.OnProgress(done, total)
+			}
+			if onMessage != nil {
+				onMessage(analysisMessage(done, total, time.Since(analysisStart)))
+			}
+		})
+		if cacheable {
+			if err := SavePitchCache(cachePath, result.SongPitch); err != nil {
+				logging.Warnf("Could not save pitch cache: %v", err)
+			}
+		}
+	}
+	if cacheable && mode.IsVocal() {
+		if err := SaveDifficulty(paths.DifficultyFile, ComputeDifficulty(result.SongPitch)); err != nil {
+			logging.Warnf("Could not save difficulty: %v", err)
+		}
+	}
+	if mode == ModeInstrumental {
+		result.Chords = analyzeChords(pcmBytes, mode, opts.Analysis)
+	}
+	if opts.Parts > 1 && mode.IsVocal() && mode != ModeHarmony {
+		if onMessage != nil {
+			onMessage(fmt.Sprintf("Analyzing %d vocal parts...", min(opts.Parts, 2)))
+		}
+		result.SongPitches = AnalyzeMultiChannel(pcmBytes, mode, opts.Parts)
+	}
+	analysis, metaCached := filepath.Base(cachePath), false
+	if cacheable && !opts.Recache && cacheIsFresh(paths.MetadataFile, audioFile) {
+		if meta, err := LoadSongMetadata(paths.MetadataFile); err == nil && meta.Analysis == analysis {
+			result.BPM, result.Key = meta.BPM, meta.Key
+			metaCached = true
+		}
+	}
+	if !metaCached {
+		result.BPM = DetectBPM(pcmBytes, config.SampleRate)
+		root, minor, keyConfidence := DetectKey(result.SongPitch)
+		result.Key = KeyName(root, minor)
+		if cacheable {
+			meta := SongMetadata{BPM: result.BPM, Key: result.Key, KeyConfidence: keyConfidence, Analysis: analysis}
+			if err := SaveSongMetadata(paths.MetadataFile, meta); err != nil {
+				logging.Warnf("Could not save song metadata: %v", err)
+			}
+		}
+	}
+	result.PCM = pcmBytes
+	result.Waveform = ComputeWaveformThumbnail(pcmBytes, config.WaveformBins)
 
 	return result, nil
 }
 
+/*
+analysisMessage formats song analysis progress for the status line.
+
+Input:
+  - done, total: int - Analyzed and total 10ms frames
+  - elapsed: time.Duration - Time spent analyzing so far
+
+Called by:
+  - LoadAndAnalyzeSong from the analyzePitch progress callback
+
+Task:
+  - Tell the user how far along the analysis is and how long is left
+
+Logic:
+ 1. Percentage = done/total
+ 2. Remaining time = elapsed scaled by the frames still to do, rounded to seconds
+    (left out until at least one frame is done, and once all are)
+
+Output:
+  - string: e.g. "Analyzing… 45% (about 12s left)"
+*/
+func analysisMessage(done, total int, elapsed time.Duration) string {
+	if total <= 0 {
+		return "Analyzing…"
+	}
+	msg := fmt.Sprintf("Analyzing… %d%%", done*100/total)
+	if done > 0 && done < total {
+		left := time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+		msg += fmt.Sprintf(" (about %v left)", left.Round(time.Second))
+	}
+	return msg
+}
+
+/*
+cropPitch limits a 10ms pitch array to the practice section.
+
+Input:
+  - pitch: []float64 - Pitch at 10ms intervals
+  - start: time.Duration - Section start (0 = beginning)
+  - end: time.Duration - Section end (0 = end of data)
+
+Called by:
+  - LoadAndAnalyzeSong for reference MIDI melodies (which have no PCM to crop)
+
+Task:
+  - Apply -start/-end the same way cropPCM does for audio
+
+Logic:
+ 1. Convert start/end to frame indices, clamped to the array
+ 2. Return the sub-slice (empty if end <= start)
+
+Output:
+  - []float64: Cropped pitch array
+*/
+func cropPitch(pitch []float64, start, end time.Duration) []float64 {
+	from := min(len(pitch), max(0, int(start/(10*time.Millisecond))))
+	to := len(pitch)
+	if end > 0 {
+		to = min(to, int(end/(10*time.Millisecond)))
+	}
+	if to <= from {
+		return nil
+	}
+	return pitch[from:to]
+}
+
+/*
+checkSection validates the -start/-end practice section against the song length.
+
+Input:
+  - start: time.Duration - Section start (0 = beginning)
+  - end: time.Duration - Section end (0 = end of song)
+  - length: time.Duration - Length of the decoded song or MIDI melody
+
+Called by:
+  - LoadAndAnalyzeSong before cropPCM or cropPitch
+
+Task:
+  - Fail the load instead of silently playing the whole song or nothing
+
+Logic:
+ 1. end set and not after start: reversed section error
+ 2. start at or past the song length: empty section error
+    (an end past the song length is left to cropPCM to clamp)
+
+Output:
+  - error: nil if the section has audio, descriptive error otherwise
+*/
+func checkSection(start, end, length time.Duration) error {
+	if end > 0 && end <= start {
+		return fmt.Errorf("practice section end %v is not after start %v", end, start)
+	}
+	if start > 0 && start >= length {
+		return fmt.Errorf("practice section start %v is past the end of the song (%v)", start, length)
+	}
+	return nil
+}
+
+/*
+cropPCM returns the part of the PCM data between start and end.
+
+Input:
+  - pcmBytes: []byte - Raw PCM audio data (16-bit stereo, 4 bytes per frame)
+  - start: time.Duration - Section start (0 = beginning)
+  - end: time.Duration - Section end (0 = end of data)
+
+Called by:
+  - LoadAndAnalyzeSong before creating the player and analyzing pitch
+
+Task:
+  - Limit playback and analysis to a practice section
+
+Logic:
+ 1. Convert start/end to frame-aligned byte offsets
+ 2. Clamp both offsets to the actual data length
+ 3. If end is unset or not after start, use end of data
+ 4. Log the effective range if it was clamped
+
+Output:
+  - []byte: Sub-slice of pcmBytes (shares the underlying array)
+*/
+func cropPCM(pcmBytes []byte, start, end time.Duration) []byte {
+	if start <= 0 && end <= 0 {
+		return pcmBytes
+	}
+
+	toOffset := func(d time.Duration) int {
+		return int(d.Seconds()*config.SampleRate) * 4
+	}
+
+	total := len(pcmBytes) - len(pcmBytes)%4
+	from := toOffset(start)
+	if from < 0 {
+		from = 0
+	}
+	if from > total {
+		from = total
+	}
+
+	to := total
+	if end > 0 {
+		to = toOffset(end)
+	}
+	if to > total || to <= from {
+		to = total
+	}
+
+	if from != toOffset(start) || (end > 0 && to != toOffset(end)) {
+		songLen := time.Duration(total/4) * time.Second / config.SampleRate
+		logging.Warnf("Practice section clamped to %v-%v (song length %v)",
+			time.Duration(from/4)*time.Second/config.SampleRate,
+			time.Duration(to/4)*time.Second/config.SampleRate, songLen)
+	}
+
+	return pcmBytes[from:to]
+}
+
+/*
+ComputeWaveformThumbnail summarizes the whole song as per-column loudness.
+
+Input:
+  - pcmBytes: []byte - Raw PCM audio data (16-bit stereo)
+  - width: int - Number of bins (columns) to produce
+
+Called by:
+  - LoadAndAnalyzeSong for the playing screen's overview bar
+
+Task:
+  - Cheap song-wide energy profile for navigation
+
+Logic:
+ 1. Split the stereo frames into width equal time slices
+ 2. RMS of the mixed-down samples in each slice
+ 3. Normalize so the loudest slice is 1
+
+Output:
+  - []float64: width values in [0, 1] (nil if there is no audio)
+*/
+func ComputeWaveformThumbnail(pcmBytes []byte, width int) []float64 {
+	frames := len(pcmBytes) / 4
+	if frames == 0 || width <= 0 {
+		return nil
+	}
+
+	bins := make([]float64, width)
+	maxRMS := 0.0
+	for b := range bins {
+		from, to := b*frames/width, (b+1)*frames/width
+		sum := 0.0
+		for f := from; f < to; f++ {
+			l := float64(int16(binary.LittleEndian.Uint16(pcmBytes[f*4:])))
+			r := float64(int16(binary.LittleEndian.Uint16(pcmBytes[f*4+2:])))
+			m := (l + r) / 2 / 32768
+			sum += m * m
+		}
+		if to > from {
+			bins[b] = math.Sqrt(sum / float64(to-from))
+		}
+		maxRMS = math.Max(maxRMS, bins[b])
+	}
+
+	if maxRMS > 0 {
+		for b := range bins {
+			bins[b] /= maxRMS
+		}
+	}
+	return bins
+}
+
+// analysisWorkers is how many goroutines analyzePitch splits a song across;
+// the benchmark replaces it to time a single worker.
+var analysisWorkers = runtime.NumCPU
+
 /*
 analyzePitch extracts pitch values from PCM audio data.
 
 Input:
   - pcmBytes: []byte - Raw PCM audio data (16-bit stereo, 44100Hz)
   - mode: Mode - Used to adjust frequency range and energy thresholds
+  - params: AnalysisParams - Tunable detection parameters
+  - progress: func(done, total int) - Called every config.AnalysisProgressFrames
+    10ms frames and once at the end with done == total (can be nil)
 
 Called by:
   - LoadAndAnalyzeSong after loading PCM data
+  - Reanalyze for on-demand full re-analysis
 
 Task:
   - Process audio in 30ms chunks for ~3x faster analysis
-  - Detect fundamental frequency using autocorrelation
+  - Detect fundamental frequency using YIN
   - Triplicate each value to maintain 10ms output timing
 
 Logic:
  1. Calculate step size: 30ms chunks (1323 samples * 4 bytes = 5292 bytes)
- 2. For each chunk:
-    a. Convert bytes to float32 samples (left channel only)
+ 2. Split the chunks into analysisWorkers() contiguous ranges (runtime.NumCPU
+    by default), one goroutine each writing into its own part of the
+    pre-allocated output (no locking).
+    For each chunk, run analyzeChunk:
+    a. Convert bytes to float32 samples via pcmToMono (selected channel)
     b. Calculate energy, mark as 0 if below threshold (silence)
-    c. Run DetectPitch with mode-appropriate frequency range
-    d. Filter non-vocal frequencies for ModeSinging
- 3. Append pitch value 3 times to maintain 10ms timing
- 4. Apply gap-filling for instrumental/full mix modes
+    c. Run DetectPitchWithConfidence with mode-appropriate frequency range
+    d. Mark low-confidence (unpitched) chunks as 0
+    e. Filter non-vocal frequencies for vocal modes
+ 3. Store each pitch value 3 times to maintain 10ms timing; count finished
+    frames (3 per chunk) under a mutex so progress calls arrive in order
+ 4. Drop voiced runs shorter than config.MinVoicedDurationMs (GateShortVoicing),
+    then apply gap-filling for instrumental/full mix modes (applyGapFill)
+ 5. Fix half/double-frequency frames with CorrectOctaveErrors
 
 Output:
   - []float64: Pitch values at 10ms intervals (100 per second)
 */
-func analyzePitch(pcmBytes []byte, mode Mode) []float64 {
-	stepBytes := int(float64(config.SampleRate)*0.03) * 4
-	totalSamples := len(pcmBytes) / 4
+func analyzePitch(pcmBytes []byte, mode Mode, params AnalysisParams, progress func(done, total int)) []float64 {
+	stepBytes := analysisStepBytes()
 
-	songPitch := make([]float64, 0, (totalSamples/stepBytes)*3)
-	floatBuf := make([]float32, stepBytes/4)
+	numChunks := 0
+	if len(pcmBytes) > stepBytes {
+		numChunks = (len(pcmBytes) - 1) / stepBytes
+	}
+	songPitch := make([]float64, numChunks*3)
 
 	startTime := time.Now()
-	log.Println("Starting pitch analysis...")
-
-	minF, maxF := 40.0, 2000.0
-	if mode == ModeSinging {
-		minF = 100.0
-		maxF = 1200.0
+	logging.Debugf("Starting pitch analysis...")
+
+	minEnergy := calibrateSilenceFromAudio(pcmBytes, stepBytes, mode, params)
+	logging.Debugf("Calibrated silence threshold: %.6f", minEnergy)
+
+	var progressMu sync.Mutex
+	done := 0
+	workers := max(1, min(analysisWorkers(), numChunks))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		from, to := w*numChunks/workers, (w+1)*numChunks/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			floatBuf := make([]float32, stepBytes/4)
+			for c := from; c < to; c++ {
+				i := c * stepBytes
+				p := analyzeChunk(pcmBytes[i:i+stepBytes], floatBuf, mode, minEnergy, params)
+				songPitch[c*3], songPitch[c*3+1], songPitch[c*3+2] = p, p, p
+
+				if progress != nil {
+					progressMu.Lock()
+					done += 3
+					if done/config.AnalysisProgressFrames > (done-3)/config.AnalysisProgressFrames && done < len(songPitch) {
+						progress(done, len(songPitch))
+					}
+					progressMu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if progress != nil {
+		progress(len(songPitch), len(songPitch))
 	}
 
-	minEnergy := calibrateSilenceFromAudio(pcmBytes, stepBytes, mode)
-	log.Printf("Calibrated silence threshold: %.6f", minEnergy)
+	songPitch = GateShortVoicing(songPitch, minVoicedFrames())
+	songPitch = applyGapFill(songPitch, mode, params)
+	songPitch = CorrectOctaveErrors(songPitch, config.OctaveWindowFrames)
 
-	for i := 0; i < len(pcmBytes)-stepBytes; i += stepBytes {
-		chunk := pcmBytes[i : i+stepBytes]
-		for j := 0; j < len(chunk); j += 4 {
-			s16 := int16(chunk[j]) | int16(chunk[j+1])<<8
-			floatBuf[j/4] = float32(s16) / 32768.0
-		}
+	logging.Infof("Analysis done in %v", time.Since(startTime))
+	return songPitch
+}
 
-		energy := CalculateEnergy(floatBuf)
-		var p float64
-		if energy < minEnergy {
-			p = 0
-		} else {
-			p = DetectPitch(floatBuf, minF, maxF)
-			if mode == ModeSinging && (p < 80 || p > 1000) {
-				p = 0
-			}
-		}
+/*
+minVoicedFrames returns config.MinVoicedDurationMs in 10ms analysis frames.
+
+Input:
+  - None
+
+Called by:
+  - analyzePitch, ReanalyzeWindow
+
+Task:
+  - Give the song analysis the mic's minimum voiced duration
+
+Logic:
+ 1. Round MinVoicedDurationMs up to whole 10ms frames
+
+Output:
+  - int: Shortest voiced run kept (8 at the default 80ms)
+*/
+func minVoicedFrames() int {
+	return int(math.Ceil(config.MinVoicedDurationMs / 10))
+}
+
+/*
+analysisStepBytes returns the size of one 30ms analysis chunk in bytes.
+
+Input:
+  - None
+
+Called by:
+  - analyzePitch, ReanalyzeWindow
+
+Task:
+  - Keep chunk size consistent between full and windowed analysis
+
+Logic:
+ 1. 30ms of samples * 4 bytes per stereo frame
+
+Output:
+  - int: Chunk size in bytes (5292 at 44100Hz)
+*/
+func analysisStepBytes() int {
+	return int(float64(config.SampleRate)*0.03) * 4
+}
+
+/*
+analyzeChunk detects the pitch of one analysis chunk.
 
-		songPitch = append(songPitch, p, p, p)
+Input:
+  - chunk: []byte - One chunk of stereo PCM
+  - floatBuf: []float32 - Scratch buffer (len = len(chunk)/4)
+  - mode: Mode - Selects the frequency range
+  - minEnergy: float64 - Silence threshold from calibrateSilenceFromAudio
+  - params: AnalysisParams - Supplies Channel and MinConfidence
+
+Called by:
+  - analyzePitch, ReanalyzeWindow
+
+Task:
+  - Single place for per-chunk detection rules
+
+Logic:
+ 1. Convert the selected channel (or L+R mix) to floats
+ 2. Below minEnergy: return 0 (silence)
+ 3. Run DetectPitchWithConfidence with mode-appropriate range (100-1200Hz vocal, 40-2000Hz otherwise;
+    with YIN a 30ms chunk only reaches down to ~67Hz, see detectPitchYIN)
+ 4. Loud but unpitched (confidence < params.MinConfidence, e.g. drums): return 0
+ 5. For vocal modes, discard results outside 80-1000Hz
+
+Output:
+  - float64: Pitch in Hz, or 0 for silence
+*/
+func analyzeChunk(chunk []byte, floatBuf []float32, mode Mode, minEnergy float64, params AnalysisParams) float64 {
+	pcmToMono(chunk, floatBuf, params.Channel)
+
+	if CalculateEnergy(floatBuf) < minEnergy {
+		return 0
 	}
 
-	if mode == ModeInstrumental || mode == ModeFullMix {
-		songPitch = fillShortGaps(songPitch, 20)
+	minF, maxF := 40.0, 2000.0
+	if mode.IsVocal() {
+		minF = 100.0
+		maxF = 1200.0
 	}
 
-	log.Printf("Analysis done in %v", time.Since(startTime))
-	return songPitch
+	p, confidence := DetectPitchWithConfidence(floatBuf, minF, maxF)
+	if confidence < params.MinConfidence {
+		return 0
+	}
+	if mode.IsVocal() && (p < 80 || p > 1000) {
+		p = 0
+	}
+	return p
 }
 
+// silenceSamples is how many chunks calibrateSilenceFromAudio measures.
+const silenceSamples = 500
+
 /*
 calibrateSilenceFromAudio samples the audio to find a good silence threshold.
 
@@ -233,70 +929,251 @@ Input:
   - pcmBytes: []byte - Raw PCM audio data
   - stepBytes: int - Size of each analysis chunk
   - mode: Mode - Current playback mode
+  - params: AnalysisParams - Supplies SilenceFactor
 
 Called by:
   - analyzePitch at the start of analysis
+  - ReanalyzeWindow before re-detecting a window
 
 Task:
   - Sample audio energy and determine adaptive silence threshold
 
 Logic:
- 1. Sample first 5 seconds of audio
- 2. Calculate energy for each chunk
- 3. Find 10th percentile as baseline noise
- 4. Return threshold above baseline
+ 1. Pick up to silenceSamples chunks spread across the whole file: one random
+    chunk per equal stride (fixed seed, so re-analysis gets the same threshold)
+ 2. Calculate energy of the selected channel for each chunk (same as analyzePitch)
+ 3. Sort and take the config.SilencePercentile percentile as baseline noise
+ 4. Return threshold params.SilenceFactor times above baseline
 
 Output:
   - float64: Energy threshold for silence detection
 */
-func calibrateSilenceFromAudio(pcmBytes []byte, stepBytes int, mode Mode) float64 {
-	sampleCount := 500
-	if len(pcmBytes)/stepBytes < sampleCount {
-		sampleCount = len(pcmBytes) / stepBytes
-	}
+func calibrateSilenceFromAudio(pcmBytes []byte, stepBytes int, mode Mode, params AnalysisParams) float64 {
+	totalChunks := len(pcmBytes)/stepBytes - 1
+	sampleCount := min(silenceSamples, totalChunks)
 
-	energies := make([]float64, 0, sampleCount)
+	energies := make([]float64, 0, max(sampleCount, 0))
 	floatBuf := make([]float32, stepBytes/4)
+	rng := rand.New(rand.NewPCG(1, uint64(totalChunks)))
 
-	for i := 0; i < sampleCount*stepBytes && i < len(pcmBytes)-stepBytes; i += stepBytes {
-		chunk := pcmBytes[i : i+stepBytes]
-		for j := 0; j < len(chunk); j += 4 {
-			s16 := int16(chunk[j]) | int16(chunk[j+1])<<8
-			floatBuf[j/4] = float32(s16) / 32768.0
+	for k := 0; k < sampleCount; k++ {
+		from, to := k*totalChunks/sampleCount, (k+1)*totalChunks/sampleCount
+		idx := from
+		if to > from {
+			idx += rng.IntN(to - from)
 		}
+
+		chunk := pcmBytes[idx*stepBytes : (idx+1)*stepBytes]
+		pcmToMono(chunk, floatBuf, params.Channel)
 		energies = append(energies, CalculateEnergy(floatBuf))
 	}
 
 	if len(energies) == 0 {
-		if mode == ModeSinging {
+		if mode.IsVocal() {
 			return 0.005
 		}
 		return 0.001
 	}
 
-	sortedEnergies := make([]float64, len(energies))
-	copy(sortedEnergies, energies)
-	for i := 0; i < len(sortedEnergies)-1; i++ {
-		for j := i + 1; j < len(sortedEnergies); j++ {
-			if sortedEnergies[j] < sortedEnergies[i] {
-				sortedEnergies[i], sortedEnergies[j] = sortedEnergies[j], sortedEnergies[i]
-			}
-		}
-	}
-
-	percentile10 := sortedEnergies[len(sortedEnergies)/10]
+	sort.Float64s(energies)
+	baseline := percentile(energies, config.SilencePercentile)
 
-	threshold := percentile10 * 3.0
-	if mode == ModeSinging && threshold < 0.005 {
+	threshold := baseline * params.SilenceFactor
+	if mode.IsVocal() && threshold < 0.005 {
 		threshold = 0.005
 	}
-	if mode != ModeSinging && threshold < 0.001 {
+	if !mode.IsVocal() && threshold < 0.001 {
 		threshold = 0.001
 	}
 
 	return threshold
 }
 
+/*
+percentile returns the p-th percentile of sorted values.
+
+Input:
+  - sorted: []float64 - Values in ascending order
+  - p: float64 - Percentile in [0, 100]
+
+Called by:
+  - calibrateSilenceFromAudio for the noise baseline
+
+Task:
+  - Percentile with linear interpolation between neighbours
+
+Logic:
+ 1. Empty input: 0
+ 2. Position = p/100 * (n-1), clamped to the slice
+ 3. Interpolate between the elements either side of the position
+
+Output:
+  - float64: Interpolated percentile value
+*/
+func percentile(sorted []float64, p float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+
+	pos := math.Max(0, math.Min(float64(n-1), p/100*float64(n-1)))
+	lo := int(pos)
+	if lo >= n-1 {
+		return sorted[n-1]
+	}
+	frac := pos - float64(lo)
+	return sorted[lo] + (sorted[lo+1]-sorted[lo])*frac
+}
+
+/*
+pcmToMono converts a chunk of 16-bit stereo PCM to mono float samples.
+
+Input:
+  - chunk: []byte - Stereo PCM (4 bytes per frame, little-endian int16)
+  - out: []float32 - Destination buffer (len >= len(chunk)/4)
+  - channel: Channel - ChannelMix, ChannelLeft or ChannelRight
+
+Called by:
+  - analyzeChunk and calibrateSilenceFromAudio for every analysis chunk
+
+Task:
+  - Produce the same mono signal the microphone delivers
+  - Optionally isolate one side (karaoke tracks with a guide vocal on L or R)
+
+Logic:
+ 1. Decode left and right int16 samples
+ 2. ChannelMix: average them ((L+R)/2) so a centered source keeps its level
+    ChannelLeft/ChannelRight: take that side only
+ 3. Normalize to [-1, 1] by dividing by 32768
+
+Output:
+  - None (fills out)
+*/
+func pcmToMono(chunk []byte, out []float32, channel Channel) {
+	for j := 0; j+3 < len(chunk); j += 4 {
+		l := float32(int16(chunk[j]) | int16(chunk[j+1])<<8)
+		r := float32(int16(chunk[j+2]) | int16(chunk[j+3])<<8)
+		switch channel {
+		case ChannelLeft:
+			out[j/4] = l / 32768.0
+		case ChannelRight:
+			out[j/4] = r / 32768.0
+		default:
+			out[j/4] = (l + r) / 65536.0
+		}
+	}
+}
+
+/*
+applyGapFill bridges short silences according to the mode and params.
+
+Input:
+  - pitches: []float64 - Raw pitch data with 0s for silence
+  - mode: Mode - Only instrumental and full mix are gap-filled
+  - params: AnalysisParams - Supplies Gaps
+
+Called by:
+  - analyzePitch, ReanalyzeWindow
+
+Task:
+  - Single place that decides the gap limit
+
+Logic:
+ 1. Vocal/no-audio modes or GapOff: return pitches unchanged
+ 2. Base limit from config.InstrumentalGapFrames / config.FullMixGapFrames
+ 3. GapAdaptive: fillAdaptiveGaps, else fillShortGaps
+
+Output:
+  - []float64: Pitch data with gaps filled
+*/
+func applyGapFill(pitches []float64, mode Mode, params AnalysisParams) []float64 {
+	var base int
+	switch mode {
+	case ModeInstrumental:
+		base = config.InstrumentalGapFrames
+	case ModeFullMix:
+		base = config.FullMixGapFrames
+	default:
+		return pitches
+	}
+
+	switch params.Gaps {
+	case GapOff:
+		return pitches
+	case GapAdaptive:
+		return fillAdaptiveGaps(pitches, base)
+	default:
+		return fillShortGaps(pitches, base)
+	}
+}
+
+/*
+fillAdaptiveGaps bridges gaps with a limit based on the neighbouring notes.
+
+Input:
+  - pitches: []float64 - Raw pitch data with 0s for silence
+  - baseGapFrames: int - Limit used for average-length notes
+
+Called by:
+  - applyGapFill in GapAdaptive mode
+
+Task:
+  - Fill more on legato passages and less on staccato ones
+
+Logic:
+ 1. For each zero run, measure the voiced runs directly before and after
+ 2. Allowed gap = half the shorter neighbour, clamped to [base/2, base*3]
+ 3. Interpolate linearly between the boundary pitches if the gap fits
+
+Output:
+  - []float64: Pitch data with gaps filled
+*/
+func fillAdaptiveGaps(pitches []float64, baseGapFrames int) []float64 {
+	result := make([]float64, len(pitches))
+	copy(result, pitches)
+
+	i := 0
+	for i < len(result) {
+		if result[i] > 0 {
+			i++
+			continue
+		}
+
+		gapStart := i
+		for i < len(result) && result[i] <= 0 {
+			i++
+		}
+		gapEnd := i
+		if gapStart == 0 || gapEnd >= len(result) {
+			continue
+		}
+
+		before := 0
+		for j := gapStart - 1; j >= 0 && pitches[j] > 0; j-- {
+			before++
+		}
+		after := 0
+		for j := gapEnd; j < len(pitches) && pitches[j] > 0; j++ {
+			after++
+		}
+
+		limit := min(before, after) / 2
+		limit = max(baseGapFrames/2, min(limit, baseGapFrames*3))
+
+		gapLen := gapEnd - gapStart
+		if gapLen > limit {
+			continue
+		}
+		startPitch, endPitch := result[gapStart-1], result[gapEnd]
+		for j := gapStart; j < gapEnd; j++ {
+			t := float64(j-gapStart+1) / float64(gapLen+1)
+			result[j] = startPitch + t*(endPitch-startPitch)
+		}
+	}
+
+	return result
+}
+
 /*
 fillShortGaps interpolates pitch values across short silence gaps.
 
@@ -305,7 +1182,7 @@ Input:
   - maxGapFrames: int - Maximum gap size to fill (in 10ms frames)
 
 Called by:
-  - analyzePitch for instrumental/full mix modes
+  - applyGapFill for instrumental/full mix modes (GapFixed)
 
 Task:
   - Fill short gaps to prevent spiky visualization
@@ -352,7 +1229,63 @@ func fillShortGaps(pitches []float64, maxGapFrames int) []float64 {
 }
 
 /*
-DetectPitch estimates fundamental frequency using autocorrelation.
+CorrectOctaveErrors snaps frames that are an octave off their neighbours back in line.
+
+Input:
+  - pitches: []float64 - Pitch data at 10ms intervals (0 = silence)
+  - windowSize: int - Frames in the median window (e.g. config.OctaveWindowFrames)
+
+Called by:
+  - analyzePitch and ReanalyzeWindow after gap filling
+
+Task:
+  - Remove the half/double-frequency jumps pitch detectors make on instrument tracks
+
+Logic:
+ 1. For each voiced frame, take the median of the voiced frames in the
+    surrounding windowSize frames (of the input, so corrections don't chain)
+ 2. If the frame is within 5% of 2x the median: halve it
+ 3. If it is within 5% of 0.5x the median: double it
+ 4. Silence and frames without voiced neighbours are left alone
+
+Output:
+  - []float64: Corrected copy of pitches
+*/
+func CorrectOctaveErrors(pitches []float64, windowSize int) []float64 {
+	result := make([]float64, len(pitches))
+	copy(result, pitches)
+
+	half := windowSize / 2
+	window := make([]float64, 0, windowSize)
+	for i, p := range pitches {
+		if p <= 0 {
+			continue
+		}
+
+		window = window[:0]
+		for j := max(0, i-half); j <= min(len(pitches)-1, i+half); j++ {
+			if pitches[j] > 0 {
+				window = append(window, pitches[j])
+			}
+		}
+		if len(window) < 3 {
+			continue
+		}
+		sort.Float64s(window)
+		median := window[len(window)/2]
+
+		ratio := p / median
+		if math.Abs(ratio-2) <= 2*0.05 {
+			result[i] = p / 2
+		} else if math.Abs(ratio-0.5) <= 0.5*0.05 {
+			result[i] = p * 2
+		}
+	}
+	return result
+}
+
+/*
+DetectPitch estimates fundamental frequency with the selected algorithm.
 
 Input:
   - samples: []float32 - Audio samples normalized to [-1, 1]
@@ -360,64 +1293,168 @@ Input:
   - maxFreq: float64 - Maximum frequency to detect (Hz)
 
 Called by:
-  - analyzePitch when processing song audio
-  - MicHandler.DetectPitchFromMic when processing microphone input
+  - Callers that only need the frequency (the mic uses DetectPitchWithConfidence)
 
 Task:
-  - Find the dominant periodic component in the signal
+  - Find the fundamental period of the signal
 
 Logic:
- 1. Convert frequency bounds to sample periods (period = sampleRate / freq)
- 2. For each candidate period (lag τ):
-    a. Compute autocorrelation: sum of sample[i] * sample[i+τ]
-    b. Skip every other sample for 2x speedup
- 3. Find period with maximum correlation
- 4. Convert best period back to frequency
+ 1. Call DetectPitchWithConfidence and drop the confidence
 
 Output:
   - float64: Detected frequency in Hz, or 0 if no pitch found
 */
 func DetectPitch(samples []float32, minFreq, maxFreq float64) float64 {
-	n := len(samples)
-	if n == 0 {
-		return 0
+	freq, _ := DetectPitchWithConfidence(samples, minFreq, maxFreq)
+	return freq
+}
+
+/*
+DetectPitchWithConfidence estimates fundamental frequency and how periodic the signal is.
+
+Input:
+  - samples: []float32 - Audio samples normalized to [-1, 1]
+  - minFreq: float64 - Minimum frequency to detect (Hz)
+  - maxFreq: float64 - Maximum frequency to detect (Hz)
+
+Called by:
+  - DetectPitch
+  - analyzeChunk when processing song audio
+  - MicHandler.DetectPitchFromMic when processing microphone input
+
+Task:
+  - One entry point for every pitch detector, so they can be compared on the
+    same songs and microphone
+
+Logic:
+ 1. Dispatch on pitchAlgorithm (SINGASSIST_PITCH_ALGO, read at startup):
+    config.AlgoAutoCorrelation: detectPitchAutocorrelation;
+    config.AlgoFFTHPS: detectPitchHPS; otherwise detectPitchYIN
+
+Output:
+  - float64: Detected frequency in Hz, or 0 if no pitch found
+  - float64: Confidence in [0, 1]
+*/
+func DetectPitchWithConfidence(samples []float32, minFreq, maxFreq float64) (float64, float64) {
+	switch pitchAlgorithm {
+	case config.AlgoAutoCorrelation:
+		return detectPitchAutocorrelation(samples, minFreq, maxFreq)
+	case config.AlgoFFTHPS:
+		return detectPitchHPS(samples, minFreq, maxFreq)
 	}
+	return detectPitchYIN(samples, minFreq, maxFreq)
+}
+
+/*
+detectPitchYIN estimates fundamental frequency and how periodic the signal is with YIN.
+
+Input:
+  - samples: []float32 - Audio samples normalized to [-1, 1]
+  - minFreq: float64 - Minimum frequency to detect (Hz)
+  - maxFreq: float64 - Maximum frequency to detect (Hz)
+
+Called by:
+  - DetectPitchWithConfidence (the default config.AlgoYIN)
+
+Task:
+  - Find the fundamental period with YIN (de Cheveigné & Kawahara 2002) and rate its reliability
+
+Logic:
+ 1. Convert frequency bounds to sample periods; cap the longest period at half
+    the buffer so the integration window is at least as long as any lag.
+    This caps the floor too: minFreq below SampleRate / (n/2) is raised to
+    it, ~43Hz for the 2048-sample mic buffer and ~67Hz for a 30ms analysis
+    chunk (1323 samples)
+ 2. Difference function: d(τ) = Σ (x[j] - x[j+τ])² over the window
+ 3. Cumulative mean normalized difference: d'(τ) = d(τ) · τ / Σ_{k≤τ} d(k)
+ 4. Absolute threshold: first τ with d' < config.YinThreshold, then walk down to its
+    local minimum (the first dip is the fundamental, not a sub-harmonic)
+ 5. If no τ passes the threshold, use the global minimum of d'
+ 6. Parabolic interpolation around τ for a sub-sample period (only at a local
+    minimum; at the edge of the range the parabola would extrapolate)
+ 7. Confidence = 1 - d'(τ) (1.0 = perfectly periodic, ~0 = noise/percussion)
+
+Output:
+  - float64: Detected frequency in Hz, or 0 if no pitch found
+  - float64: Confidence in [0, 1]
+*/
+func detectPitchYIN(samples []float32, minFreq, maxFreq float64) (float64, float64) {
+	n := len(samples)
 
 	minPeriod := int(float64(config.SampleRate) / maxFreq)
 	maxPeriod := int(float64(config.SampleRate) / minFreq)
 	if minPeriod < 2 {
 		minPeriod = 2
 	}
-	if maxPeriod >= n {
-		maxPeriod = n - 1
+	if maxPeriod > n/2 {
+		maxPeriod = n / 2
+	}
+	if minPeriod >= maxPeriod {
+		return 0, 0
+	}
+
+	window := n - maxPeriod - 1
+	diff := make([]float64, maxPeriod+2)
+	for tau := 1; tau <= maxPeriod+1; tau++ {
+		sum := 0.0
+		for j := 0; j < window; j++ {
+			d := float64(samples[j]) - float64(samples[j+tau])
+			sum += d * d
+		}
+		diff[tau] = sum
 	}
 
-	bestPeriod := 0
-	maxVal := 0.0
+	cmnd := make([]float64, len(diff))
+	cmnd[0] = 1
+	running := 0.0
+	for tau := 1; tau < len(diff); tau++ {
+		running += diff[tau]
+		if running == 0 {
+			cmnd[tau] = 1
+			continue
+		}
+		cmnd[tau] = diff[tau] * float64(tau) / running
+	}
+	if running == 0 {
+		return 0, 0
+	}
 
-	for tau := minPeriod; tau < maxPeriod; tau++ {
-		cross := 0.0
-		limit := n - tau
-		for i := 0; i < limit; i += 2 {
-			cross += float64(samples[i]) * float64(samples[i+tau])
+	bestTau := -1
+	for tau := minPeriod; tau <= maxPeriod; tau++ {
+		if cmnd[tau] < config.YinThreshold {
+			for tau+1 <= maxPeriod && cmnd[tau+1] < cmnd[tau] {
+				tau++
+			}
+			bestTau = tau
+			break
 		}
-		if cross > maxVal {
-			maxVal = cross
-			bestPeriod = tau
+	}
+	if bestTau < 0 {
+		bestTau = minPeriod
+		for tau := minPeriod + 1; tau <= maxPeriod; tau++ {
+			if cmnd[tau] < cmnd[bestTau] {
+				bestTau = tau
+			}
 		}
 	}
 
-	if bestPeriod == 0 {
-		return 0
+	period := float64(bestTau)
+	if bestTau > 1 && bestTau+1 < len(cmnd) {
+		a, b, c := cmnd[bestTau-1], cmnd[bestTau], cmnd[bestTau+1]
+		if denom := a - 2*b + c; denom > 0 && b <= a && b <= c {
+			period += 0.5 * (a - c) / denom
+		}
 	}
-	return float64(config.SampleRate) / float64(bestPeriod)
+
+	confidence := math.Max(0, math.Min(1, 1-cmnd[bestTau]))
+	return float64(config.SampleRate) / period, confidence
 }
 
 /*
 CalculateEnergy computes the average power of audio samples.
 
 Input:
-  - samples: []float32 - Audio samples normalized to [-1, 1]
+  - samples: []float32 - Mono audio samples normalized to [-1, 1]
 
 Called by:
   - analyzePitch when filtering silence
@@ -431,10 +1468,19 @@ Logic:
  1. Sum squares of all samples
  2. Divide by sample count for average
 
+Notes:
+  - The result is a per-sample mean, so the same steady tone gives the same
+    energy whether measured over the 2048-sample mic buffer or a 1323-sample
+    analysis chunk. Both paths feed mono [-1, 1] samples (see pcmToMono), so
+    thresholds from one can be compared with the other.
+
 Output:
   - float64: Average energy (0.0 = silence, higher = louder)
 */
 func CalculateEnergy(samples []float32) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
 	e := 0.0
 	for _, s := range samples {
 		e += float64(s * s)
@@ -0,0 +1,321 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"singAssist/internal/config"
+)
+
+// sine returns n samples of a freq Hz sine with the given amplitude.
+func sine(n int, freq, amp float64) []float32 {
+	out := make([]float32, n)
+	for i := range out {
+		out[i] = float32(amp * math.Sin(2*math.Pi*freq*float64(i)/config.SampleRate))
+	}
+	return out
+}
+
+// stereoPCM encodes mono samples as 16-bit PCM with the same signal on both channels.
+func stereoPCM(mono []float32) []byte {
+	pcm := make([]byte, 4*len(mono))
+	for i, v := range mono {
+		s := uint16(int16(v * 32767))
+		binary.LittleEndian.PutUint16(pcm[4*i:], s)
+		binary.LittleEndian.PutUint16(pcm[4*i+2:], s)
+	}
+	return pcm
+}
+
+func TestCalculateEnergyIndependentOfBufferLength(t *testing.T) {
+	mic := CalculateEnergy(sine(config.BufferSize, 440, 0.5))
+	chunk := CalculateEnergy(sine(config.SampleRate*30/1000, 440, 0.5))
+	if math.Abs(mic-chunk)/mic > 0.01 {
+		t.Errorf("energy over 2048 samples = %v, over a 30ms chunk = %v; want equal within 1%%", mic, chunk)
+	}
+	if want := 0.5 * 0.5 / 2; math.Abs(mic-want)/want > 0.01 {
+		t.Errorf("energy = %v, want the mean square %v", mic, want)
+	}
+	if got := CalculateEnergy(nil); got != 0 {
+		t.Errorf("CalculateEnergy(nil) = %v, want 0", got)
+	}
+}
+
+func TestPCMToMonoMatchesMicLevel(t *testing.T) {
+	// The same tone on both channels must reach the analysis at the level the
+	// mic would deliver it, so energy thresholds compare.
+	tone := sine(1323, 440, 0.5)
+	pcm := stereoPCM(tone)
+	for _, ch := range []Channel{ChannelMix, ChannelLeft, ChannelRight} {
+		mono := make([]float32, len(tone))
+		pcmToMono(pcm, mono, ch)
+		if got, want := CalculateEnergy(mono), CalculateEnergy(tone); math.Abs(got-want)/want > 0.001 {
+			t.Errorf("channel %v: energy %v, want %v", ch, got, want)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+	tests := []struct {
+		p, want float64
+	}{
+		{0, 1},
+		{10, 1.4},
+		{50, 3},
+		{62.5, 3.5},
+		{100, 5},
+		{150, 5},
+		{-10, 1},
+	}
+	for _, tt := range tests {
+		if got := percentile(values, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 10); got != 0 {
+		t.Errorf("percentile of nothing = %v, want 0", got)
+	}
+	if got := percentile([]float64{7}, 10); got != 7 {
+		t.Errorf("percentile of one value = %v, want 7", got)
+	}
+}
+
+func TestCalibrateSilenceSamplesWholeSong(t *testing.T) {
+	// A 6 s silent intro fills more than the first 500 10 ms chunks, but is
+	// well under 10% of the song, so the noise floor comes from the song.
+	const step = config.SampleRate / 100
+	tone := sine(config.SampleRate*94, 440, 0.3)
+	pcm := append(make([]byte, 4*config.SampleRate*6), stereoPCM(tone)...)
+
+	params := DefaultAnalysisParams()
+	got := calibrateSilenceFromAudio(pcm, 4*step, ModeSinging, params)
+	want := CalculateEnergy(tone[:step]) * params.SilenceFactor
+	if math.Abs(got-want)/want > 0.05 {
+		t.Errorf("threshold = %v, want %v from the song's level", got, want)
+	}
+}
+
+func TestCalibrateSilenceFloor(t *testing.T) {
+	params := DefaultAnalysisParams()
+	silence := make([]byte, 4*config.SampleRate)
+	if got := calibrateSilenceFromAudio(silence, 4*config.BufferSize, ModeSinging, params); got != 0.005 {
+		t.Errorf("vocal threshold on silence = %v, want 0.005", got)
+	}
+	if got := calibrateSilenceFromAudio(silence, 4*config.BufferSize, ModeFullMix, params); got != 0.001 {
+		t.Errorf("full-mix threshold on silence = %v, want 0.001", got)
+	}
+	if got := calibrateSilenceFromAudio(nil, 4*config.BufferSize, ModeSinging, params); got != 0.005 {
+		t.Errorf("threshold with no audio = %v, want 0.005", got)
+	}
+}
+
+// bubbleSort is the sort calibrateSilenceFromAudio used before sort.Float64s.
+func bubbleSort(values []float64) {
+	for i := 0; i < len(values); i++ {
+		for j := i + 1; j < len(values); j++ {
+			if values[j] < values[i] {
+				values[i], values[j] = values[j], values[i]
+			}
+		}
+	}
+}
+
+func BenchmarkSilencePercentile(b *testing.B) {
+	energies := make([]float64, silenceSamples)
+	for i := range energies {
+		energies[i] = math.Abs(math.Sin(float64(i) * 12.9898))
+	}
+	work := make([]float64, len(energies))
+
+	b.Run("bubble", func(b *testing.B) {
+		for b.Loop() {
+			copy(work, energies)
+			bubbleSort(work)
+			_ = work[len(work)/10]
+		}
+	})
+	b.Run("sort", func(b *testing.B) {
+		for b.Loop() {
+			copy(work, energies)
+			sort.Float64s(work)
+			_ = percentile(work, config.SilencePercentile)
+		}
+	})
+}
+
+// BenchmarkAnalyzePitch times a minute of A3 with one worker and with one per
+// CPU, and fails if four or more cores are not at least 1.5x faster.
+func BenchmarkAnalyzePitch(b *testing.B) {
+	// Alternate sung and near-silent seconds so the silence calibration leaves
+	// half the chunks to the pitch detector.
+	mono := sine(60*config.SampleRate, 220, 0.5)
+	for i := range mono {
+		if i/config.SampleRate%2 == 1 {
+			mono[i] *= 0.01
+		}
+	}
+	pcm := stereoPCM(mono)
+	params := DefaultAnalysisParams()
+
+	perRun := map[int]time.Duration{}
+	for _, workers := range []int{1, runtime.NumCPU()} {
+		if _, done := perRun[workers]; done {
+			continue
+		}
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			old := analysisWorkers
+			analysisWorkers = func() int { return workers }
+			b.Cleanup(func() { analysisWorkers = old })
+
+			runs, start := 0, time.Now()
+			for b.Loop() {
+				analyzePitch(pcm, ModeSinging, params, nil)
+				runs++
+			}
+			perRun[workers] = time.Since(start) / time.Duration(runs)
+		})
+	}
+
+	single, parallel := perRun[1], perRun[runtime.NumCPU()]
+	if runtime.NumCPU() >= 4 && single > 0 && parallel > 0 {
+		speedup := float64(single) / float64(parallel)
+		b.ReportMetric(speedup, "speedup")
+		if speedup < 1.5 {
+			b.Errorf("%d workers took %v, one worker %v: %.2fx, want at least 1.5x", runtime.NumCPU(), parallel, single, speedup)
+		}
+	}
+}
+
+func TestCorrectOctaveErrors(t *testing.T) {
+	pitches := make([]float64, 40)
+	for i := range pitches {
+		pitches[i] = 440
+	}
+	pitches[5], pitches[6] = 220, 221
+	pitches[20] = 880
+	pitches[30] = 330 // a real interval, not an octave error
+	pitches[35] = 0
+
+	got := CorrectOctaveErrors(pitches, 9)
+	want := map[int]float64{5: 440, 6: 442, 20: 440, 30: 330, 35: 0}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("frame %d = %v, want %v", i, got[i], w)
+		}
+	}
+	if pitches[5] != 220 {
+		t.Error("CorrectOctaveErrors modified its input")
+	}
+}
+
+func TestCorrectOctaveErrorsNeedsNeighbours(t *testing.T) {
+	// An isolated note with fewer than three voiced frames around it is kept.
+	pitches := []float64{0, 0, 0, 220, 0, 0, 0, 0, 0, 440, 440, 0}
+	got := CorrectOctaveErrors(pitches, 5)
+	if got[3] != 220 {
+		t.Errorf("isolated frame = %v, want it left at 220", got[3])
+	}
+}
+
+func TestAnalyzePitchProgressPerFrame(t *testing.T) {
+	// 3334 chunks of 30ms: 10002 frames of 10ms.
+	pcm := stereoPCM(sine(3334*analysisStepBytes()/4+1, 220, 0.3))
+	var calls [][2]int
+	pitch := analyzePitch(pcm, ModeSinging, DefaultAnalysisParams(), func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	})
+
+	if len(calls) < 5 {
+		t.Fatalf("progress called %d times for %d frames, want at least 5", len(calls), len(pitch))
+	}
+	for i, c := range calls {
+		if c[1] != len(pitch) {
+			t.Fatalf("call %d reports a total of %d, want %d frames", i, c[1], len(pitch))
+		}
+		if i > 0 && c[0] <= calls[i-1][0] {
+			t.Fatalf("progress went from %d to %d", calls[i-1][0], c[0])
+		}
+	}
+	if last := calls[len(calls)-1]; last[0] != last[1] {
+		t.Errorf("final call = %d/%d, want done == total", last[0], last[1])
+	}
+}
+
+func TestAnalysisMessage(t *testing.T) {
+	tests := []struct {
+		done, total int
+		elapsed     time.Duration
+		want        string
+	}{
+		{0, 10000, 0, "Analyzing… 0%"},
+		{4500, 10000, 9 * time.Second, "Analyzing… 45% (about 11s left)"},
+		{10000, 10000, 20 * time.Second, "Analyzing… 100%"},
+		{0, 0, 0, "Analyzing…"},
+	}
+	for _, tt := range tests {
+		if got := analysisMessage(tt.done, tt.total, tt.elapsed); got != tt.want {
+			t.Errorf("analysisMessage(%d, %d, %v) = %q, want %q", tt.done, tt.total, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestLoadAndAnalyzeSongRejectsEmptyOrReversedSection(t *testing.T) {
+	dir := t.TempDir()
+	samples := make([]int16, 2*config.SampleRate)
+	for i := range samples {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*220*float64(i)/config.SampleRate))
+	}
+	if err := WriteWAV(filepath.Join(dir, "song.wav"), samples, config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+	// Half a second of A4 at the default 120 BPM (480 ticks = 0x83 0x60).
+	midiDir := filepath.Dir(writeMIDI(t, []byte{
+		0x00, 0x90, 69, 100,
+		0x83, 0x60, 0x80, 69, 0,
+	}))
+
+	tests := []struct {
+		name       string
+		dir        string
+		mode       Mode
+		start, end time.Duration
+		want       string
+	}{
+		{"start past audio", dir, ModeFullMix, 5 * time.Second, 0, "past the end"},
+		{"start at audio end", dir, ModeFullMix, 2 * time.Second, 3 * time.Second, "past the end"},
+		{"reversed audio", dir, ModeFullMix, time.Second, 500 * time.Millisecond, "not after start"},
+		{"equal audio", dir, ModeFullMix, time.Second, time.Second, "not after start"},
+		{"start past MIDI", midiDir, ModeNoAudio, 5 * time.Second, 0, "past the end"},
+		{"reversed MIDI", midiDir, ModeNoAudio, 400 * time.Millisecond, 200 * time.Millisecond, "not after start"},
+	}
+	for _, tt := range tests {
+		opts := LoadOptions{Start: tt.start, End: tt.end, Analysis: DefaultAnalysisParams()}
+		_, err := LoadAndAnalyzeSong(tt.dir, tt.mode, opts, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+
+	// A section inside the song, or one whose end runs past it, still loads.
+	for _, sec := range []struct{ start, end, want time.Duration }{
+		{500 * time.Millisecond, 1500 * time.Millisecond, time.Second},
+		{time.Second, 10 * time.Second, time.Second},
+	} {
+		opts := LoadOptions{Start: sec.start, End: sec.end, Analysis: DefaultAnalysisParams()}
+		result, err := LoadAndAnalyzeSong(dir, ModeFullMix, opts, nil)
+		if err != nil {
+			t.Fatalf("%v-%v: %v", sec.start, sec.end, err)
+		}
+		if result.Duration != sec.want {
+			t.Errorf("%v-%v: duration %v, want %v", sec.start, sec.end, result.Duration, sec.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package audio
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+
+	"singAssist/internal/config"
+)
+
+/*
+SongMetadata holds facts about a song derived from its audio.
+
+Fields:
+  - BPM: Detected tempo in beats per minute (0 if none was found)
+  - Key: Detected key (e.g. "D Major", see DetectKey and KeyName; "" if unknown)
+  - KeyConfidence: Correlation of that key's profile with the song (-1..1)
+  - Analysis: Pitch cache file the values were detected with (e.g.
+    "pitch_cache_vocals.bin"); each mode and detector analyzes differently
+*/
+type SongMetadata struct {
+	BPM           float64 `json:"bpm"`
+	Key           string  `json:"key,omitempty"`
+	KeyConfidence float64 `json:"key_confidence,omitempty"`
+	Analysis      string  `json:"analysis,omitempty"`
+}
+
+/*
+DetectBPM estimates the tempo of a song from its onset strength.
+
+Input:
+  - pcmBytes: []byte - 16-bit stereo PCM
+  - sampleRate: int - Sample rate of pcmBytes
+
+Called by:
+  - LoadAndAnalyzeSong after analyzePitch
+
+Task:
+  - Tell the user the tempo to practice at
+
+Logic:
+ 1. Energy (mean square) of each 10ms frame of the mono mix
+ 2. Onset curve = positive frame-to-frame energy increases, mean removed
+ 3. Autocorrelate it over lags between config.MaxBPM and config.MinBPM; the
+    unnormalized sum favors the shortest strong period over its multiples
+ 4. Refine the best lag with a parabola through its neighbours
+ 5. BPM = 60 / (lag × 10ms)
+
+Output:
+  - float64: Tempo in BPM, 0 if the audio is too short or has no onsets
+*/
+func DetectBPM(pcmBytes []byte, sampleRate int) float64 {
+	hop := sampleRate / 100
+	if hop <= 0 {
+		return 0
+	}
+	frameRate := float64(sampleRate) / float64(hop)
+	minLag := int(frameRate * 60 / config.MaxBPM)
+	maxLag := int(math.Ceil(frameRate * 60 / config.MinBPM))
+
+	nFrames := len(pcmBytes) / 4 / hop
+	if nFrames < 2*maxLag {
+		return 0
+	}
+
+	samples := make([]float32, hop)
+	energy := make([]float64, nFrames)
+	for i := range energy {
+		pcmToMono(pcmBytes[i*hop*4:(i+1)*hop*4], samples, ChannelMix)
+		var sum float64
+		for _, s := range samples {
+			sum += float64(s) * float64(s)
+		}
+		energy[i] = sum / float64(hop)
+	}
+
+	onset := make([]float64, nFrames)
+	var mean float64
+	for i := 1; i < nFrames; i++ {
+		onset[i] = math.Max(0, energy[i]-energy[i-1])
+		mean += onset[i]
+	}
+	mean /= float64(nFrames)
+	for i := range onset {
+		onset[i] -= mean
+	}
+
+	corr := make([]float64, maxLag+2)
+	bestLag := 0
+	for lag := minLag - 1; lag <= maxLag+1; lag++ {
+		var sum float64
+		for i := lag; i < nFrames; i++ {
+			sum += onset[i] * onset[i-lag]
+		}
+		corr[lag] = sum
+		if lag >= minLag && lag <= maxLag && (bestLag == 0 || sum > corr[bestLag]) {
+			bestLag = lag
+		}
+	}
+	if corr[bestLag] <= 0 {
+		return 0
+	}
+
+	lag := float64(bestLag)
+	a, b, c := corr[bestLag-1], corr[bestLag], corr[bestLag+1]
+	if d := a - 2*b + c; d < 0 {
+		lag += 0.5 * (a - c) / d
+	}
+	return 60 * frameRate / lag
+}
+
+/*
+NextBeatTime returns when the next beat of a steady tempo falls.
+
+Input:
+  - currentTime: float64 - Playback position in seconds
+  - bpmStartTime: float64 - Time of one beat (the beat grid's origin) in seconds
+  - bpm: float64 - Tempo in beats per minute
+
+Called by:
+  - App.onBeat for the "now" line pulse (origin -opts.Load.Start, song time 0)
+
+Task:
+  - Place beats on the playback clock from the tempo DetectBPM found
+
+Logic:
+ 1. Beats fall at bpmStartTime + k × 60/bpm for every integer k
+ 2. Return the first one at or after currentTime
+
+Output:
+  - float64: Next beat time in seconds (+Inf if bpm <= 0)
+*/
+func NextBeatTime(currentTime, bpmStartTime float64, bpm float64) float64 {
+	if bpm <= 0 {
+		return math.Inf(1)
+	}
+	period := 60 / bpm
+	return bpmStartTime + math.Ceil((currentTime-bpmStartTime)/period)*period
+}
+
+/*
+SaveSongMetadata writes a song's metadata to metadata.json.
+
+Input:
+  - path: string - config.SongPaths.MetadataFile
+  - m: SongMetadata - Metadata to store
+
+Called by:
+  - LoadAndAnalyzeSong next to the pitch cache (when it detected the values)
+
+Task:
+  - Keep the detected tempo and key with the song
+
+Logic:
+ 1. Marshal indented JSON and write it
+
+Output:
+  - error: nil on success
+*/
+func SaveSongMetadata(path string, m SongMetadata) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+/*
+LoadSongMetadata reads metadata written by SaveSongMetadata.
+
+Input:
+  - path: string - config.SongPaths.MetadataFile
+
+Called by:
+  - LoadAndAnalyzeSong when metadata.json is newer than the audio file
+
+Task:
+  - Skip tempo and key detection on later loads of the song
+
+Logic:
+ 1. Read and unmarshal the file
+
+Output:
+  - SongMetadata: Stored metadata
+  - error: Read or JSON error (the file is missing until the song is first analyzed)
+*/
+func LoadSongMetadata(path string) (SongMetadata, error) {
+	var m SongMetadata
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	return m, json.Unmarshal(data, &m)
+}
@@ -0,0 +1,138 @@
+package audio
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestNextBeatTime(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentTime float64
+		origin      float64
+		bpm         float64
+		want        float64
+	}{
+		{"120 BPM between beats", 1.2, 0, 120, 1.5},
+		{"120 BPM on a beat", 2.0, 0, 120, 2.0},
+		{"180 BPM between beats", 1.1, 0, 180, 4.0 / 3},
+		{"180 BPM on a beat", 2.0, 0, 180, 2.0},
+		{"120 BPM grid offset by -start 0.25s", 1.2, -0.25, 120, 1.25},
+		{"180 BPM before the origin", 0.1, 0.5, 180, 0.5 - 1.0/3},
+		{"no tempo", 1.0, 0, 0, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		got := NextBeatTime(tt.currentTime, tt.origin, tt.bpm)
+		if got != tt.want && math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: NextBeatTime(%v, %v, %v) = %v, want %v", tt.name, tt.currentTime, tt.origin, tt.bpm, got, tt.want)
+		}
+	}
+}
+
+func TestNextBeatTimeSpacing(t *testing.T) {
+	for _, bpm := range []float64{120, 180} {
+		period := 60 / bpm
+		beat := NextBeatTime(0.01, 0, bpm)
+		next := NextBeatTime(beat+0.001, 0, bpm)
+		if math.Abs(next-beat-period) > 1e-9 {
+			t.Errorf("%v BPM: beats %v and %v are %v apart, want %v", bpm, beat, next, next-beat, period)
+		}
+	}
+}
+
+// clickTrack returns seconds of silence with a 10ms 1 kHz click every period.
+func clickTrack(seconds, period float64) []float32 {
+	out := make([]float32, int(seconds*config.SampleRate))
+	click := sine(config.SampleRate/100, 1000, 0.8)
+	for t := 0.0; t < seconds; t += period {
+		copy(out[int(t*config.SampleRate):], click)
+	}
+	return out
+}
+
+func TestDetectBPMClicks(t *testing.T) {
+	tests := []struct {
+		period, want float64
+	}{
+		{0.5, 120},
+		{0.6, 100},
+		{0.4, 150},
+	}
+	for _, tt := range tests {
+		got := DetectBPM(stereoPCM(clickTrack(10, tt.period)), config.SampleRate)
+		if math.Abs(got-tt.want) > 2 {
+			t.Errorf("clicks every %vs: %.1f BPM, want %v", tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestDetectBPMWithoutBeat(t *testing.T) {
+	if got := DetectBPM(stereoPCM(make([]float32, 10*config.SampleRate)), config.SampleRate); got != 0 {
+		t.Errorf("silence = %.1f BPM, want 0", got)
+	}
+	if got := DetectBPM(stereoPCM(clickTrack(1, 0.5)), config.SampleRate); got != 0 {
+		t.Errorf("one second of audio = %.1f BPM, want 0 (too short)", got)
+	}
+}
+
+func TestSongMetadataRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	want := SongMetadata{BPM: 120, Key: "D Major", KeyConfidence: 0.81, Analysis: "pitch_cache_vocals.bin"}
+	if err := SaveSongMetadata(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadSongMetadata(path); err != nil || got != want {
+		t.Errorf("LoadSongMetadata = %+v, %v; want %+v", got, err, want)
+	}
+	if _, err := LoadSongMetadata(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadSongMetadata succeeded on a missing file")
+	}
+}
+
+func TestLoadAndAnalyzeSongReusesMetadata(t *testing.T) {
+	dir := t.TempDir()
+	clicks := clickTrack(10, 0.5)
+	samples := make([]int16, len(clicks))
+	for i, v := range clicks {
+		samples[i] = int16(v * 32767)
+	}
+	if err := WriteWAV(filepath.Join(dir, "song.wav"), samples, config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+	opts := LoadOptions{Analysis: DefaultAnalysisParams()}
+	result, err := LoadAndAnalyzeSong(dir, ModeFullMix, opts, nil)
+	if err != nil {
+		t.Fatalf("LoadAndAnalyzeSong: %v", err)
+	}
+	metaFile := config.GetSongPaths(dir).MetadataFile
+	saved, err := LoadSongMetadata(metaFile)
+	if err != nil || math.Abs(saved.BPM-120) > 2 || saved.BPM != result.BPM || saved.Analysis == "" {
+		t.Fatalf("metadata.json = %+v, %v; want the detected 120 BPM", saved, err)
+	}
+
+	// A fresh file from the same analysis is used as is.
+	saved.BPM, saved.Key = 97, "F# Minor"
+	if err := SaveSongMetadata(metaFile, saved); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = LoadAndAnalyzeSong(dir, ModeFullMix, opts, nil); err != nil || result.BPM != 97 || result.Key != "F# Minor" {
+		t.Errorf("second load = %v BPM, %q (%v); want 97 and F# Minor from metadata.json", result.BPM, result.Key, err)
+	}
+
+	// Recache and metadata from another mode's analysis detect again.
+	recache := opts
+	recache.Recache = true
+	if result, _ = LoadAndAnalyzeSong(dir, ModeFullMix, recache, nil); math.Abs(result.BPM-120) > 2 {
+		t.Errorf("with Recache = %v BPM, want it detected again", result.BPM)
+	}
+	saved.BPM, saved.Analysis = 97, "pitch_cache_vocals.bin"
+	if err := SaveSongMetadata(metaFile, saved); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ = LoadAndAnalyzeSong(dir, ModeFullMix, opts, nil); math.Abs(result.BPM-120) > 2 {
+		t.Errorf("metadata from the vocals analysis gave %v BPM, want it detected again", result.BPM)
+	}
+}
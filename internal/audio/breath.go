@@ -0,0 +1,124 @@
+package audio
+
+import "singAssist/internal/config"
+
+/*
+BreathDetector finds the short silences where the singer took a breath.
+
+Fields:
+  - holdMs: How late the fed pitch reports the start of a voiced run (the
+    mic's VoiceOnsetTracker hold, config.MinVoicedDurationMs; 0 for a raw stream)
+  - voicedStartMs: When the current (or last) voiced run started
+  - silenceStartMs: When the current silence started
+  - lastMs: Time of the previous Update
+  - voiced: Whether the previous Update was voiced
+  - phrase: The silence in progress follows a voiced run of at least config.BreathMinPhraseMs
+  - breaths: Start times (ms) of the breaths found so far
+*/
+type BreathDetector struct {
+	holdMs         float64
+	voicedStartMs  float64
+	silenceStartMs float64
+	lastMs         float64
+	voiced         bool
+	phrase         bool
+	breaths        []float64
+}
+
+/*
+NewBreathDetector creates a detector with no breaths.
+
+Input:
+  - None
+
+Called by:
+  - App.startGame
+
+Task:
+  - Start a session's breath log
+
+Logic:
+ 1. Silent, no phrase yet; holdMs = config.MinVoicedDurationMs because
+    micLoop feeds the onset-gated mic pitch
+
+Output:
+  - *BreathDetector: Ready-to-use detector
+*/
+func NewBreathDetector() *BreathDetector {
+	return &BreathDetector{holdMs: config.MinVoicedDurationMs}
+}
+
+/*
+Update feeds one mic pitch.
+
+Input:
+  - pitch: float64 - Detected pitch in Hz (<= 10 for silence)
+  - nowMs: float64 - Playback position of the pitch
+
+Called by:
+  - App.micLoop for every pitch recorded during playback
+
+Task:
+  - Tell breaths apart from detection dropouts and pauses between phrases
+
+Logic:
+ 1. Time went backwards (seek or echo loop): forget breaths after nowMs and
+    start over silent
+ 2. Voiced -> silent: remember the silence start, and whether the voiced run
+    before it lasted config.BreathMinPhraseMs
+ 3. Silent -> voiced: the voice really came back holdMs earlier (the onset
+    gate held it back), so the gap is nowMs - holdMs - silence start; a gap of
+    config.BreathMinMs..BreathMaxMs after such a phrase is a breath at the
+    silence start; the new voiced run started at nowMs - holdMs
+
+Output:
+  - None (may append to breaths)
+*/
+func (d *BreathDetector) Update(pitch float64, nowMs float64) {
+	if nowMs < d.lastMs {
+		for len(d.breaths) > 0 && d.breaths[len(d.breaths)-1] > nowMs {
+			d.breaths = d.breaths[:len(d.breaths)-1]
+		}
+		d.voiced, d.phrase = false, false
+	}
+	d.lastMs = nowMs
+
+	isVoiced := pitch > 10
+	if isVoiced == d.voiced {
+		return
+	}
+	d.voiced = isVoiced
+	if !isVoiced {
+		d.phrase = nowMs-d.voicedStartMs >= config.BreathMinPhraseMs
+		d.silenceStartMs = nowMs
+		return
+	}
+	onsetMs := max(d.silenceStartMs, nowMs-d.holdMs)
+	gap := onsetMs - d.silenceStartMs
+	if d.phrase && gap >= config.BreathMinMs && gap <= config.BreathMaxMs {
+		d.breaths = append(d.breaths, d.silenceStartMs)
+	}
+	d.voicedStartMs = onsetMs
+}
+
+/*
+Breaths returns the breath times found so far.
+
+Input:
+  - None
+
+Called by:
+  - App.drawPlayingMode for the breath markers
+
+Task:
+  - Expose the log for drawing
+
+Logic:
+ 1. Return breaths (oldest first; do not modify)
+
+Output:
+  - []float64: Breath start times in ms
+*/
+func (d *BreathDetector) Breaths() []float64 {
+	return d.breaths
+}
@@ -0,0 +1,66 @@
+package audio
+
+import "testing"
+
+// segment is a stretch of the raw mic stream: voiced at pitch, or silent (0).
+type segment struct {
+	ms    float64
+	pitch float64
+}
+
+// feedBreaths runs a raw pitch stream through the mic's onset gate into a
+// new BreathDetector, one pitch every 10ms, and returns the breaths found.
+func feedBreaths(segments []segment) []float64 {
+	onset := NewVoiceOnsetTracker()
+	d := NewBreathDetector()
+	now := 0.0
+	for _, seg := range segments {
+		for end := now + seg.ms; now < end; {
+			now += 10
+			d.Update(onset.Track(seg.pitch, now), now)
+		}
+	}
+	return d.Breaths()
+}
+
+func TestBreathDetector(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []segment
+		want     []float64
+	}{
+		{"100ms breath between two 1s phrases", []segment{{1000, 220}, {100, 0}, {1000, 220}}, []float64{1010}},
+		{"250ms breath (longer than BreathMaxMs with the onset hold)", []segment{{1000, 220}, {250, 0}, {1000, 220}}, []float64{1010}},
+		{"pause longer than a breath", []segment{{1000, 220}, {600, 0}, {1000, 220}}, nil},
+		{"dropout shorter than a breath", []segment{{1000, 220}, {30, 0}, {1000, 220}}, nil},
+		{"phrase too short", []segment{{300, 220}, {100, 0}, {1000, 220}}, nil},
+	}
+	for _, tt := range tests {
+		got := feedBreaths(tt.segments)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: breaths %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: breaths %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestBreathDetectorRawStream(t *testing.T) {
+	// Without an onset gate in front, holdMs must be 0.
+	d := &BreathDetector{}
+	for now := 10.0; now <= 2200; now += 10 {
+		pitch := 220.0
+		if now > 1000 && now <= 1290 {
+			pitch = 0
+		}
+		d.Update(pitch, now)
+	}
+	if got := d.Breaths(); len(got) != 1 || got[0] != 1010 {
+		t.Errorf("Breaths() = %v, want [1010] for a 290ms gap", got)
+	}
+}
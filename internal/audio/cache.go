@@ -0,0 +1,226 @@
+package audio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"singAssist/internal/config"
+)
+
+// pitchCacheMagic starts every pitch cache (inside the gzip stream).
+var pitchCacheMagic = []byte("SAPC")
+
+// pitchCacheVersion is stored in the pitch cache header. Bump it whenever a
+// change to analyzePitch, the detectors or their defaults changes the contour
+// a song analyzes to, so caches written by older builds are analyzed again.
+const pitchCacheVersion = 2
+
+// pitchCacheHeaderSize is the magic plus the uint32 version.
+const pitchCacheHeaderSize = 8
+
+/*
+SavePitchCache writes an analyzed pitch contour to disk.
+
+Input:
+  - path: string - Cache file (see pitchCachePath)
+  - data: []float64 - Pitch at 10ms intervals
+
+Called by:
+  - LoadAndAnalyzeSong after a fresh analysis
+
+Task:
+  - Let the next load of the same song skip analyzePitch
+
+Logic:
+ 1. Create a temp file and wrap it in a gzip.Writer (the smooth contour
+    compresses to roughly half)
+ 2. Write the header (pitchCacheMagic, little-endian uint32
+    pitchCacheVersion), then the slice as little-endian float64s, through it
+ 3. Close the gzip stream and the file, then rename it over the original
+    (the temp file is removed on any error)
+
+Output:
+  - error: nil on success
+*/
+func SavePitchCache(path string, data []float64) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	_, err = zw.Write(pitchCacheMagic)
+	if err == nil {
+		err = binary.Write(zw, binary.LittleEndian, uint32(pitchCacheVersion))
+	}
+	if err == nil {
+		err = binary.Write(zw, binary.LittleEndian, data)
+	}
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+/*
+LoadPitchCache reads a pitch contour written by SavePitchCache.
+
+Input:
+  - path: string - Cache file
+
+Called by:
+  - LoadAndAnalyzeSong when the cache is newer than the audio file
+
+Task:
+  - Decode the cached contour
+
+Logic:
+ 1. Read and decompress the whole file
+ 2. Check the header: without pitchCacheMagic (a cache from before the header
+    existed) or with another pitchCacheVersion the contour may come from an
+    older analysis, so it is rejected and the caller analyzes again
+ 3. Reject payload sizes that are not a multiple of 8 bytes (truncated write)
+ 4. Decode little-endian float64s
+
+Output:
+  - []float64: Cached pitch at 10ms intervals
+  - error: Read or format error, or a stale cache
+*/
+func LoadPitchCache(path string) ([]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("pitch cache %s is from an older version", path)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("pitch cache %s: %w", path, err)
+	}
+
+	if len(raw) < pitchCacheHeaderSize || !bytes.Equal(raw[:4], pitchCacheMagic) {
+		return nil, fmt.Errorf("pitch cache %s is from an older version", path)
+	}
+	if v := binary.LittleEndian.Uint32(raw[4:8]); v != pitchCacheVersion {
+		return nil, fmt.Errorf("pitch cache %s has analysis version %d, want %d", path, v, pitchCacheVersion)
+	}
+	raw = raw[pitchCacheHeaderSize:]
+	if len(raw)%8 != 0 {
+		return nil, fmt.Errorf("pitch cache %s is corrupt (%d bytes)", path, len(raw))
+	}
+
+	data := make([]float64, len(raw)/8)
+	if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+/*
+pitchCachePath returns the cache file for one mode of a song.
+
+Input:
+  - cacheFile: string - The song's config.SongPaths.PitchCacheFile
+  - mode: Mode - Playback mode (each mode analyzes a different track)
+  - algo: config.PitchAlgorithm - Detector the contour was analyzed with
+
+Called by:
+  - LoadAndAnalyzeSong
+
+Task:
+  - Keep vocals, instrumental and full-mix contours apart, and contours of
+    different detectors (SINGASSIST_PITCH_ALGO) too
+
+Logic:
+ 1. Insert "_<mode>" before the extension (pitch_cache.bin -> pitch_cache_vocals.bin)
+ 2. For detectors other than YIN also "_<algo>" (pitch_cache_vocals_fft.bin),
+    so existing YIN caches stay valid
+
+Output:
+  - string: Cache file path
+*/
+func pitchCachePath(cacheFile string, mode Mode, algo config.PitchAlgorithm) string {
+	ext := filepath.Ext(cacheFile)
+	name := strings.TrimSuffix(cacheFile, ext) + "_" + mode.String()
+	if algo != config.AlgoYIN {
+		name += "_" + string(algo)
+	}
+	return name + ext
+}
+
+/*
+InvalidatePitchCache removes the cached contour of one mode of a song.
+
+Input:
+  - songDir: string - Song folder
+  - mode: Mode - Mode whose contour was re-analyzed
+
+Called by:
+  - App.retuneAnalysis
+
+Task:
+  - Make the next load analyze again instead of bringing back the contour
+    the user just re-tuned
+
+Logic:
+ 1. Remove pitchCachePath for the mode and the current detector; a cache
+    that does not exist is not an error
+
+Output:
+  - error: nil on success, filesystem error otherwise
+*/
+func InvalidatePitchCache(songDir string, mode Mode) error {
+	path := pitchCachePath(config.GetSongPaths(songDir).PitchCacheFile, mode, pitchAlgorithm)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+/*
+cacheIsFresh reports whether a cache file was written after its source audio.
+
+Input:
+  - cachePath: string - Cache file
+  - sourcePath: string - Audio file the contour was analyzed from
+
+Called by:
+  - LoadAndAnalyzeSong
+
+Task:
+  - Invalidate the cache when the audio is replaced (re-download, re-separation)
+
+Logic:
+ 1. Stat both files; any error means not fresh
+ 2. Fresh when the cache's modification time is after the source's
+
+Output:
+  - bool: true if the cache can be used
+*/
+func cacheIsFresh(cachePath, sourcePath string) bool {
+	c, err := os.Stat(cachePath)
+	if err != nil {
+		return false
+	}
+	s, err := os.Stat(sourcePath)
+	if err != nil {
+		return false
+	}
+	return c.ModTime().After(s.ModTime())
+}
@@ -0,0 +1,132 @@
+package audio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestPitchCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pitch_cache_vocals.bin")
+	want := []float64{0, 0, 220, 220.5, 261.6255653005986, 0, 880, math.SmallestNonzeroFloat64, 1e-3}
+	if err := SavePitchCache(path, want); err != nil {
+		t.Fatalf("SavePitchCache: %v", err)
+	}
+	got, err := LoadPitchCache(path)
+	if err != nil {
+		t.Fatalf("LoadPitchCache: %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("LoadPitchCache = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+func TestPitchCacheEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pitch_cache_vocals.bin")
+	if err := SavePitchCache(path, nil); err != nil {
+		t.Fatalf("SavePitchCache: %v", err)
+	}
+	if got, err := LoadPitchCache(path); err != nil || len(got) != 0 {
+		t.Errorf("LoadPitchCache = %v, %v; want an empty contour", got, err)
+	}
+}
+
+// writeGzip writes payload gzip-compressed to path.
+func writeGzip(t *testing.T, path string, payload []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(payload)
+	zw.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPitchCacheRejectsStaleCaches(t *testing.T) {
+	contour := make([]byte, 16)
+	binary.LittleEndian.PutUint64(contour, math.Float64bits(440))
+	oldVersion := append([]byte("SAPC\x01\x00\x00\x00"), contour...)
+
+	tests := []struct {
+		name  string
+		write func(path string)
+	}{
+		{"raw cache without header", func(path string) { os.WriteFile(path, contour, 0644) }},
+		{"gzip cache without header", func(path string) { writeGzip(t, path, contour) }},
+		{"older analysis version", func(path string) { writeGzip(t, path, oldVersion) }},
+		{"truncated contour", func(path string) {
+			writeGzip(t, path, append([]byte("SAPC\x02\x00\x00\x00"), contour[:12]...))
+		}},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "pitch_cache_vocals.bin")
+		tt.write(path)
+		if got, err := LoadPitchCache(path); err == nil {
+			t.Errorf("%s: LoadPitchCache = %v, want an error so the song is analyzed again", tt.name, got)
+		}
+	}
+}
+
+func TestPitchCacheCompresses(t *testing.T) {
+	// 100 s of a sung line: held notes with a little vibrato, and pauses.
+	want := make([]float64, 10000)
+	for i := range want {
+		if i%400 >= 320 {
+			continue
+		}
+		note := 220 * math.Pow(2, float64(i/400%7)/12)
+		want[i] = note * math.Pow(2, 0.2*math.Sin(2*math.Pi*5.5*float64(i)/100)/12)
+	}
+	path := filepath.Join(t.TempDir(), "pitch_cache_vocals.bin")
+	if err := SavePitchCache(path, want); err != nil {
+		t.Fatalf("SavePitchCache: %v", err)
+	}
+
+	got, err := LoadPitchCache(path)
+	if err != nil || !slices.Equal(got, want) {
+		t.Fatalf("LoadPitchCache returned %d values (%v), want the 10000 saved", len(got), err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := int64(8 * len(want)); info.Size() >= raw {
+		t.Errorf("cache is %d bytes, not smaller than the %d raw bytes", info.Size(), raw)
+	}
+}
+
+func TestInvalidatePitchCache(t *testing.T) {
+	dir := t.TempDir()
+	cacheFile := config.GetSongPaths(dir).PitchCacheFile
+	vocals := pitchCachePath(cacheFile, ModeSinging, pitchAlgorithm)
+	mix := pitchCachePath(cacheFile, ModeFullMix, pitchAlgorithm)
+	for _, path := range []string{vocals, mix} {
+		if err := SavePitchCache(path, []float64{220, 0, 330}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := InvalidatePitchCache(dir, ModeSinging); err != nil {
+		t.Fatalf("InvalidatePitchCache: %v", err)
+	}
+	if _, err := os.Stat(vocals); !os.IsNotExist(err) {
+		t.Errorf("vocals cache still there (%v)", err)
+	}
+	if _, err := os.Stat(mix); err != nil {
+		t.Errorf("full-mix cache was removed too: %v", err)
+	}
+	if err := InvalidatePitchCache(dir, ModeSinging); err != nil {
+		t.Errorf("removing a missing cache = %v, want nil", err)
+	}
+}
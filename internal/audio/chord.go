@@ -0,0 +1,165 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+)
+
+/*
+DetectChord finds the strongest simultaneous frequencies in a buffer.
+
+Input:
+  - samples: []float32 - Mono audio samples at config.SampleRate
+  - minFreq, maxFreq: float64 - Frequency range to search in Hz
+
+Called by:
+  - analyzeChords for each chord window
+
+Task:
+  - Keep chord information that single-pitch YIN throws away
+
+Logic:
+ 1. Hann-window the samples and zero-pad them to config.ChordFFTSize
+ 2. FFT and take the magnitude spectrum
+ 3. Collect local maxima between minFreq and maxFreq, refining each peak's
+    frequency by parabolic interpolation
+ 4. Sort peaks by magnitude; drop those under ChordPeakRatio of the strongest
+ 5. Keep up to config.ChordNotes peaks, skipping any within a semitone of one
+    already kept
+
+Output:
+  - []float64: Up to ChordNotes frequencies in Hz, strongest first (nil for silence)
+*/
+func DetectChord(samples []float32, minFreq, maxFreq float64) []float64 {
+	n := config.ChordFFTSize
+	if len(samples) == 0 || len(samples) > n {
+		return nil
+	}
+
+	win := hannWindow(len(samples))
+	buf := make([]complex128, n)
+	for i, s := range samples {
+		buf[i] = complex(float64(s)*win[i], 0)
+	}
+	fft(buf, false)
+
+	mags := make([]float64, n/2)
+	for i := range mags {
+		mags[i] = cmplx.Abs(buf[i])
+	}
+
+	type peak struct{ freq, mag float64 }
+	var peaks []peak
+	binHz := float64(config.SampleRate) / float64(n)
+	lo := max(1, int(minFreq/binHz))
+	hi := min(len(mags)-2, int(maxFreq/binHz)+1)
+	for k := lo; k <= hi; k++ {
+		if mags[k] <= mags[k-1] || mags[k] < mags[k+1] {
+			continue
+		}
+		a, b, c := mags[k-1], mags[k], mags[k+1]
+		offset := 0.0
+		if d := a - 2*b + c; d != 0 {
+			offset = 0.5 * (a - c) / d
+		}
+		if f := (float64(k) + offset) * binHz; f >= minFreq && f <= maxFreq {
+			peaks = append(peaks, peak{f, b})
+		}
+	}
+	if len(peaks) == 0 {
+		return nil
+	}
+	sort.Slice(peaks, func(i, j int) bool { return peaks[i].mag > peaks[j].mag })
+
+	var freqs []float64
+	for _, p := range peaks {
+		if p.mag < peaks[0].mag*config.ChordPeakRatio || len(freqs) == config.ChordNotes {
+			break
+		}
+		distinct := true
+		for _, f := range freqs {
+			if math.Abs(12*math.Log2(p.freq/f)) < 1 {
+				distinct = false
+				break
+			}
+		}
+		if distinct {
+			freqs = append(freqs, p.freq)
+		}
+	}
+	return freqs
+}
+
+/*
+analyzeChords detects the chord of a song every ChordHopFrames.
+
+Input:
+  - pcmBytes: []byte - Raw PCM audio data (16-bit stereo, 44100Hz)
+  - mode: Mode - Used for the silence threshold
+  - params: AnalysisParams - Supplies Channel
+
+Called by:
+  - LoadAndAnalyzeSong in ModeInstrumental, next to analyzePitch
+
+Task:
+  - Pre-compute chord frequencies at the same 10ms resolution as song pitch
+
+Logic:
+ 1. Same silence threshold as analyzePitch
+ 2. One ChordFFTSize window centred on every ChordHopFrames-th frame
+    (chords change slowly and each window is long); split the hops
+    across runtime.NumCPU() goroutines
+ 3. Per window: skip silence, else DetectChord over config.ChordMinFreq..ChordMaxFreq
+ 4. Store the result in every frame of its hop (the entries share a slice)
+
+Output:
+  - [][]float64: Chord frequencies per 10ms frame (nil entries for silence)
+*/
+func analyzeChords(pcmBytes []byte, mode Mode, params AnalysisParams) [][]float64 {
+	frameBytes := config.SampleRate / 100 * 4
+	winBytes := config.ChordFFTSize * 4
+	numFrames := len(pcmBytes) / frameBytes
+	chords := make([][]float64, numFrames)
+	if len(pcmBytes) < winBytes {
+		return chords
+	}
+
+	startTime := time.Now()
+	minEnergy := calibrateSilenceFromAudio(pcmBytes, analysisStepBytes(), mode, params)
+
+	numHops := (numFrames + config.ChordHopFrames - 1) / config.ChordHopFrames
+	workers := max(1, min(runtime.NumCPU(), numHops))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		from, to := w*numHops/workers, (w+1)*numHops/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			floatBuf := make([]float32, config.ChordFFTSize)
+			for h := from; h < to; h++ {
+				first := h * config.ChordHopFrames
+				centre := (first*frameBytes + config.ChordHopFrames*frameBytes/2) &^ 3
+				start := max(0, min(len(pcmBytes)-winBytes, centre-winBytes/2))
+				pcmToMono(pcmBytes[start:start+winBytes], floatBuf, params.Channel)
+				if CalculateEnergy(floatBuf) < minEnergy {
+					continue
+				}
+				ch := DetectChord(floatBuf, config.ChordMinFreq, config.ChordMaxFreq)
+				for f := first; f < min(numFrames, first+config.ChordHopFrames); f++ {
+					chords[f] = ch
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	logging.Infof("Chord analysis done in %v", time.Since(startTime))
+	return chords
+}
@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+	"singAssist/internal/theory"
+)
+
+// chordTones mixes sines at freqs, each at amplitude 0.25, n samples long.
+func chordTones(n int, freqs ...float64) []float32 {
+	out := make([]float32, n)
+	for _, f := range freqs {
+		for i, v := range sine(n, f, 0.25) {
+			out[i] += v
+		}
+	}
+	return out
+}
+
+// chordOf names the chord formed by the frequencies DetectChord returned.
+func chordOf(freqs []float64) string {
+	var notes []string
+	for _, f := range freqs {
+		n, _ := theory.FreqToNote(f)
+		notes = append(notes, n)
+	}
+	return theory.ChordName(notes)
+}
+
+func TestDetectChordCMajor(t *testing.T) {
+	c, e, g := midiHz(60), midiHz(64), midiHz(67)
+	freqs := DetectChord(chordTones(config.ChordFFTSize, c, e, g), config.ChordMinFreq, config.ChordMaxFreq)
+	if len(freqs) != 3 {
+		t.Fatalf("detected %v, want three notes", freqs)
+	}
+	for _, want := range []float64{c, e, g} {
+		found := false
+		for _, f := range freqs {
+			if math.Abs(centsOff(f, want)) < 20 {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%.2f Hz missing from %v", want, freqs)
+		}
+	}
+	if got := chordOf(freqs); got != "C" {
+		t.Errorf("C+E+G named %q, want C", got)
+	}
+}
+
+func TestDetectChordSilenceAndRange(t *testing.T) {
+	if got := DetectChord(make([]float32, config.ChordFFTSize), config.ChordMinFreq, config.ChordMaxFreq); got != nil {
+		t.Errorf("silence detected as %v", got)
+	}
+	// A 50 Hz hum is below the search range and must not become a chord note.
+	freqs := DetectChord(chordTones(config.ChordFFTSize, 50, midiHz(69)), config.ChordMinFreq, config.ChordMaxFreq)
+	for _, f := range freqs {
+		if f < config.ChordMinFreq {
+			t.Errorf("kept %.2f Hz below the %v Hz minimum", f, config.ChordMinFreq)
+		}
+	}
+}
+
+func TestAnalyzeChordsEveryHop(t *testing.T) {
+	// Two seconds of A minor: silence first so the calibration has a floor.
+	silence := make([]float32, config.SampleRate/2)
+	tone := chordTones(2*config.SampleRate, midiHz(57), midiHz(60), midiHz(64))
+	chords := analyzeChords(stereoPCM(append(silence, tone...)), ModeInstrumental, AnalysisParams{})
+
+	if len(chords) != 250 {
+		t.Fatalf("got %d frames, want 250", len(chords))
+	}
+	if chords[10] != nil {
+		t.Errorf("silent frame 10 holds %v", chords[10])
+	}
+	for _, f := range []int{100, 150, 200} {
+		if got := chordOf(chords[f]); got != "Am" {
+			t.Errorf("frame %d chord = %q (%v), want Am", f, got, chords[f])
+		}
+	}
+}
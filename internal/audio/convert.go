@@ -0,0 +1,43 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"singAssist/internal/config"
+)
+
+// errNoFFMPEG is returned by ConvertToMP3 when ffmpeg is not installed.
+var errNoFFMPEG = errors.New("ffmpeg is needed to import .m4a and .aac files; install it from https://ffmpeg.org and try again")
+
+/*
+ConvertToMP3 converts an audio file ebiten cannot decode into an MP3.
+
+Input:
+  - srcPath: string - Source file (.m4a or .aac)
+  - dstPath: string - MP3 to write (the song's song.mp3)
+
+Called by:
+  - youtube.ImportSong for .m4a and .aac files
+
+Task:
+  - Let users import the AAC files Apple devices produce
+
+Logic:
+ 1. Without ffmpeg (config.HasFFMPEG): return errNoFFMPEG
+ 2. Run "ffmpeg -i <src> -acodec libmp3lame -q:a 2 <dst>" via Runner
+
+Output:
+  - error: Missing ffmpeg, or the conversion failure with ffmpeg's output
+*/
+func ConvertToMP3(srcPath, dstPath string) error {
+	if !config.HasFFMPEG {
+		return errNoFFMPEG
+	}
+	output, err := Runner.CombinedOutput(context.Background(), "ffmpeg", "-i", srcPath, "-acodec", "libmp3lame", "-q:a", "2", dstPath)
+	if err != nil {
+		return fmt.Errorf("ffmpeg conversion of %s failed: %v\nOutput: %s", srcPath, err, string(output))
+	}
+	return nil
+}
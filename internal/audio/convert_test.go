@@ -0,0 +1,46 @@
+package audio
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestConvertToMP3RunsFFMPEG(t *testing.T) {
+	f := &fakeRunner{}
+	useFakeRunner(t, f)
+
+	src, dst := filepath.Join("in", "Voice Memo.m4a"), filepath.Join("songs", "Voice Memo", "song.mp3")
+	if err := ConvertToMP3(src, dst); err != nil {
+		t.Fatalf("ConvertToMP3: %v", err)
+	}
+	want := []string{"ffmpeg", "-i", src, "-acodec", "libmp3lame", "-q:a", "2", dst}
+	if len(f.calls) != 1 || !slices.Equal(f.calls[0], want) {
+		t.Errorf("ran %q, want %q", f.calls, want)
+	}
+}
+
+func TestConvertToMP3Failures(t *testing.T) {
+	f := &fakeRunner{run: func(string, []string) ([]byte, error) {
+		return []byte("Invalid data found when processing input"), errors.New("exit status 1")
+	}}
+	useFakeRunner(t, f)
+
+	err := ConvertToMP3("broken.aac", "song.mp3")
+	if err == nil || !strings.Contains(err.Error(), "Invalid data found") {
+		t.Errorf("failed conversion = %v, want ffmpeg's output in the error", err)
+	}
+
+	config.HasFFMPEG = false
+	f.calls = nil
+	if err := ConvertToMP3("a.m4a", "song.mp3"); !errors.Is(err, errNoFFMPEG) {
+		t.Errorf("without ffmpeg = %v, want errNoFFMPEG", err)
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("ran %q without ffmpeg installed", f.calls)
+	}
+}
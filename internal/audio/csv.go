@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"singAssist/internal/theory"
+)
+
+/*
+WritePitchCSV writes an analyzed pitch contour as CSV.
+
+Input:
+  - path: string - Output file (e.g. "songs/MySong/pitch.csv")
+  - pitches: []float64 - Pitch at 10ms intervals (LoadResult.SongPitch)
+
+Called by:
+  - main.main with -analyze-only
+
+Task:
+  - Let scripts and spreadsheets read the song pitch without the app
+
+Logic:
+ 1. Header row: time_ms, pitch_hz, note_name
+ 2. One row per frame: i*10 ms, pitch with 2 decimals, theory.FreqToNote name and
+    octave (e.g. "A4"); silent frames (pitch <= 10) get pitch 0 and an empty note
+ 3. Flush and report any write or close error
+
+Output:
+  - error: nil on success
+*/
+func WritePitchCSV(path string, pitches []float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"time_ms", "pitch_hz", "note_name"})
+	for i, p := range pitches {
+		note := ""
+		if p > 10 {
+			name, octave := theory.FreqToNote(p)
+			note = fmt.Sprintf("%s%d", name, octave)
+		} else {
+			p = 0
+		}
+		w.Write([]string{strconv.Itoa(i * 10), strconv.FormatFloat(p, 'f', 2, 64), note})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
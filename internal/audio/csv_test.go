@@ -0,0 +1,49 @@
+package audio
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWritePitchCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pitch.csv")
+	if err := WritePitchCSV(path, []float64{440, 0, 261.6256, 5, 466.1638}); err != nil {
+		t.Fatalf("WritePitchCSV: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("reading the CSV back: %v", err)
+	}
+
+	want := [][]string{
+		{"time_ms", "pitch_hz", "note_name"},
+		{"0", "440.00", "A4"},
+		{"10", "0.00", ""},
+		{"20", "261.63", "C4"},
+		{"30", "0.00", ""},
+		{"40", "466.16", "A#4"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %q", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if !slices.Equal(rows[i], want[i]) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestWritePitchCSVBadPath(t *testing.T) {
+	if err := WritePitchCSV(filepath.Join(t.TempDir(), "missing", "pitch.csv"), []float64{440}); err == nil {
+		t.Error("WritePitchCSV into a missing directory succeeded, want an error")
+	}
+}
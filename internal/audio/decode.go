@@ -0,0 +1,276 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"singAssist/internal/config"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/mewkiz/flac"
+)
+
+/*
+decodeAudioFile decodes an MP3, OGG/Vorbis, FLAC or WAV file to PCM.
+
+Input:
+  - path: string - Audio file; the format is chosen by extension
+
+Called by:
+  - LoadAndAnalyzeSong when opening the song or a separated stem
+
+Task:
+  - Give the analysis and playback code one PCM format regardless of input
+
+Logic:
+ 1. .mp3: ebiten mp3 decoder, resampled to config.SampleRate
+ 2. .ogg: ebiten vorbis decoder, resampled to config.SampleRate
+ 3. .flac: decodeFLAC
+ 4. .wav: decodeWAV
+ 5. Anything else: error
+
+Output:
+  - []byte: 16-bit little-endian stereo PCM at config.SampleRate
+  - error: Open/decode error or unsupported extension
+*/
+func decodeAudioFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var src io.Reader
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".mp3":
+		src, err = mp3.DecodeWithSampleRate(config.SampleRate, f)
+	case ".ogg":
+		src, err = vorbis.DecodeWithSampleRate(config.SampleRate, f)
+	case ".flac":
+		return decodeFLAC(f)
+	case ".wav":
+		return decodeWAV(f)
+	default:
+		return nil, fmt.Errorf("unsupported audio format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+
+	return io.ReadAll(src)
+}
+
+/*
+decodeFLAC decodes a FLAC stream to 16-bit stereo PCM at config.SampleRate.
+
+Input:
+  - r: io.Reader - FLAC file contents
+
+Called by:
+  - decodeAudioFile for .flac files
+
+Task:
+  - Match the PCM layout produced by the ebiten decoders
+
+Logic:
+ 1. Parse frames until EOF
+ 2. Scale each sample from the stream's bit depth to 16 bits
+ 3. Mono: duplicate into both channels; more than 2 channels: keep the first two
+ 4. Resample with resamplePCM if the stream rate differs
+
+Output:
+  - []byte: 16-bit little-endian stereo PCM
+  - error: Parse error
+*/
+func decodeFLAC(r io.Reader) ([]byte, error) {
+	stream, err := flac.New(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode flac: %v", err)
+	}
+	defer stream.Close()
+
+	shift := int(stream.Info.BitsPerSample) - 16
+	scale := func(s int32) int16 {
+		if shift > 0 {
+			s >>= shift
+		} else if shift < 0 {
+			s <<= -shift
+		}
+		return int16(s)
+	}
+
+	var pcm bytes.Buffer
+	var frameBuf [4]byte
+	for {
+		frame, err := stream.ParseNext()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode flac frame: %v", err)
+		}
+
+		left := frame.Subframes[0].Samples
+		right := left
+		if len(frame.Subframes) > 1 {
+			right = frame.Subframes[1].Samples
+		}
+		for i := range left {
+			binary.LittleEndian.PutUint16(frameBuf[0:], uint16(scale(left[i])))
+			binary.LittleEndian.PutUint16(frameBuf[2:], uint16(scale(right[i])))
+			pcm.Write(frameBuf[:])
+		}
+	}
+
+	return resamplePCM(pcm.Bytes(), int(stream.Info.SampleRate))
+}
+
+/*
+resamplePCM converts 16-bit stereo PCM from rate to config.SampleRate.
+
+Input:
+  - pcm: []byte - 16-bit little-endian stereo PCM
+  - rate: int - Sample rate of pcm
+
+Called by:
+  - decodeFLAC, decodeWAV
+
+Task:
+  - Resample without the trailing samples ebiten's resampler produces
+    after the end of its source
+
+Logic:
+ 1. Return pcm unchanged when rate already matches
+ 2. Read the whole audio.ResampleReader output
+ 3. Cut it to the resampled length of pcm (whole frames)
+
+Output:
+  - []byte: 16-bit little-endian stereo PCM at config.SampleRate
+  - error: Read error
+*/
+func resamplePCM(pcm []byte, rate int) ([]byte, error) {
+	if rate == config.SampleRate {
+		return pcm, nil
+	}
+	out, err := io.ReadAll(audio.ResampleReader(bytes.NewReader(pcm), int64(len(pcm)), rate, config.SampleRate))
+	if err != nil {
+		return nil, err
+	}
+	n := int(int64(len(pcm)/4) * config.SampleRate / int64(rate) * 4)
+	return out[:min(n, len(out))], nil
+}
+
+// WAV format tags handled by decodeWAV.
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+/*
+decodeWAV decodes a RIFF/WAVE file to 16-bit stereo PCM at config.SampleRate.
+
+Input:
+  - r: io.Reader - WAV file contents
+
+Called by:
+  - decodeAudioFile for .wav files
+
+Task:
+  - Accept studio exports without converting them to MP3 first
+
+Logic:
+ 1. Check the RIFF/WAVE header, then walk the chunks (padded to even sizes)
+    for "fmt " and "data"
+ 2. Resolve WAVE_FORMAT_EXTENSIBLE to its sub-format
+ 3. Convert each sample to int16: 8-bit unsigned, 16/24/32-bit signed PCM,
+    or 32-bit float
+ 4. Mono: duplicate into both channels; more than 2 channels: keep the first two
+ 5. Resample with resamplePCM if the file rate differs
+
+Output:
+  - []byte: 16-bit little-endian stereo PCM
+  - error: Malformed header or unsupported sample format
+*/
+func decodeWAV(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a WAV file")
+	}
+
+	var format, channels, bits, rate int
+	var samples []byte
+	for off := 12; off+8 <= len(data); {
+		id := string(data[off : off+4])
+		size := int(binary.LittleEndian.Uint32(data[off+4:]))
+		body := data[off+8 : min(len(data), off+8+size)]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, fmt.Errorf("wav: short fmt chunk")
+			}
+			format = int(binary.LittleEndian.Uint16(body[0:]))
+			channels = int(binary.LittleEndian.Uint16(body[2:]))
+			rate = int(binary.LittleEndian.Uint32(body[4:]))
+			bits = int(binary.LittleEndian.Uint16(body[14:]))
+			if format == wavFormatExtensible && len(body) >= 26 {
+				format = int(binary.LittleEndian.Uint16(body[24:]))
+			}
+		case "data":
+			samples = body
+		}
+		off += 8 + size + size%2
+	}
+
+	if channels == 0 || rate == 0 {
+		return nil, fmt.Errorf("wav: missing fmt chunk")
+	}
+
+	var sample func(b []byte) int16
+	switch {
+	case format == wavFormatPCM && bits == 8:
+		sample = func(b []byte) int16 { return int16(int(b[0])-128) << 8 }
+	case format == wavFormatPCM && bits == 16:
+		sample = func(b []byte) int16 { return int16(binary.LittleEndian.Uint16(b)) }
+	case format == wavFormatPCM && bits == 24:
+		sample = func(b []byte) int16 { return int16(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 16) }
+	case format == wavFormatPCM && bits == 32:
+		sample = func(b []byte) int16 { return int16(int32(binary.LittleEndian.Uint32(b)) >> 16) }
+	case format == wavFormatFloat && bits == 32:
+		sample = func(b []byte) int16 {
+			v := math.Float32frombits(binary.LittleEndian.Uint32(b))
+			return int16(max(-1, min(1, v)) * 32767)
+		}
+	default:
+		return nil, fmt.Errorf("wav: unsupported format %d with %d bits per sample", format, bits)
+	}
+
+	width := bits / 8
+	frameSize := width * channels
+	frames := len(samples) / frameSize
+	pcm := make([]byte, frames*4)
+	for i := 0; i < frames; i++ {
+		f := samples[i*frameSize:]
+		left := sample(f)
+		right := left
+		if channels > 1 {
+			right = sample(f[width:])
+		}
+		binary.LittleEndian.PutUint16(pcm[i*4:], uint16(left))
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], uint16(right))
+	}
+
+	return resamplePCM(pcm, rate)
+}
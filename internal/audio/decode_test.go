@@ -0,0 +1,278 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/config"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+// sineSamples returns one second of a 440 Hz sine as 16-bit samples.
+func sineSamples() []int16 {
+	samples := make([]int16, config.SampleRate)
+	for i := range samples {
+		samples[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/config.SampleRate))
+	}
+	return samples
+}
+
+// writeFLAC encodes samples as a mono 16-bit FLAC file at rate.
+func writeFLAC(t *testing.T, path string, samples []int16, rate int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	const blockSize = 4096
+	info := &meta.StreamInfo{
+		BlockSizeMin:  blockSize,
+		BlockSizeMax:  blockSize,
+		SampleRate:    uint32(rate),
+		NChannels:     1,
+		BitsPerSample: 16,
+		NSamples:      uint64(len(samples)),
+	}
+	enc, err := flac.NewEncoder(f, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for start := 0; start < len(samples); start += blockSize {
+		block := samples[start:min(len(samples), start+blockSize)]
+		data := make([]int32, len(block))
+		for i, v := range block {
+			data[i] = int32(v)
+		}
+		fr := &frame.Frame{
+			Header: frame.Header{
+				HasFixedBlockSize: true,
+				BlockSize:         uint16(len(block)),
+				SampleRate:        uint32(rate),
+				Channels:          frame.ChannelsMono,
+				BitsPerSample:     16,
+			},
+			Subframes: []*frame.Subframe{{
+				SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+				Samples:   data,
+				NSamples:  len(data),
+			}},
+		}
+		if err := enc.WriteFrame(fr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkSinePCM checks that pcm is the 440 Hz sine on both channels.
+func checkSinePCM(t *testing.T, name string, pcm []byte) {
+	t.Helper()
+	want := sineSamples()
+	if len(pcm) != 4*len(want) {
+		t.Fatalf("%s: decoded %d bytes, want %d", name, len(pcm), 4*len(want))
+	}
+	for i, w := range want {
+		l := int16(binary.LittleEndian.Uint16(pcm[4*i:]))
+		r := int16(binary.LittleEndian.Uint16(pcm[4*i+2:]))
+		if math.Abs(float64(l-w)) > 1 || l != r {
+			t.Fatalf("%s: frame %d = %d/%d, want %d on both channels", name, i, l, r, w)
+		}
+	}
+}
+
+func TestDecodeAudioFileSine(t *testing.T) {
+	dir := t.TempDir()
+
+	wavPath := filepath.Join(dir, "song.wav")
+	if err := WriteWAV(wavPath, sineSamples(), config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := decodeAudioFile(wavPath)
+	if err != nil {
+		t.Fatalf("decodeAudioFile(wav): %v", err)
+	}
+	checkSinePCM(t, "wav", pcm)
+
+	flacPath := filepath.Join(dir, "song.flac")
+	writeFLAC(t, flacPath, sineSamples(), config.SampleRate)
+	pcm, err = decodeAudioFile(flacPath)
+	if err != nil {
+		t.Fatalf("decodeAudioFile(flac): %v", err)
+	}
+	checkSinePCM(t, "flac", pcm)
+}
+
+func TestDecodeAudioFileResamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "song.flac")
+	half := make([]int16, config.SampleRate/2)
+	for i := range half {
+		half[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/(config.SampleRate/2)))
+	}
+	writeFLAC(t, path, half, config.SampleRate/2)
+	pcm, err := decodeAudioFile(path)
+	if err != nil {
+		t.Fatalf("decodeAudioFile: %v", err)
+	}
+	if frames := len(pcm) / 4; math.Abs(float64(frames-config.SampleRate)) > 8 {
+		t.Errorf("decoded %d frames from one second at 22050 Hz, want about %d", frames, config.SampleRate)
+	}
+
+	mono := make([]float32, config.BufferSize)
+	pcmToMono(pcm[4*config.SampleRate/2:][:4*len(mono)], mono, ChannelMix)
+	if got, _ := detectPitchYIN(mono, 85, 1100); math.Abs(centsOff(got, 440)) > 15 {
+		t.Errorf("resampled sine detected at %.2f Hz, want 440", got)
+	}
+}
+
+func TestDecodeAudioFileRejectsBadInput(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"song.mp3", "song.ogg", "song.flac", "song.wav", "song.aiff"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("this is not audio data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := decodeAudioFile(path); err == nil {
+			t.Errorf("decodeAudioFile(%s) succeeded on garbage", name)
+		}
+	}
+	if _, err := decodeAudioFile(filepath.Join(dir, "missing.mp3")); err == nil {
+		t.Error("decodeAudioFile succeeded on a missing file")
+	}
+}
+
+// wavFile builds a WAV file from raw sample data with the given fmt fields.
+// extensible wraps the format in a WAVE_FORMAT_EXTENSIBLE header.
+func wavFile(format, channels, bits, rate int, extensible bool, data []byte) []byte {
+	fmtLen := 16
+	if extensible {
+		fmtLen = 40
+	}
+	tag := format
+	if extensible {
+		tag = wavFormatExtensible
+	}
+	buf := []byte("RIFF")
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(4+8+fmtLen+8+len(data)))
+	buf = append(buf, "WAVEfmt "...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(fmtLen))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(tag))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(channels))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(rate))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(rate*channels*bits/8))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(channels*bits/8))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(bits))
+	if extensible {
+		buf = binary.LittleEndian.AppendUint16(buf, 22)
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(bits))
+		buf = binary.LittleEndian.AppendUint32(buf, 0)
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(format))
+		buf = append(buf, make([]byte, 14)...)
+	}
+	buf = append(buf, "data"...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(data)))
+	return append(buf, data...)
+}
+
+func TestAnalyzeWAVSine(t *testing.T) {
+	// Half a second of silence first, so silence calibration finds a floor.
+	path := filepath.Join(t.TempDir(), "song.wav")
+	samples := append(make([]int16, config.SampleRate/2), sineSamples()...)
+	if err := WriteWAV(path, samples, config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := decodeAudioFile(path)
+	if err != nil {
+		t.Fatalf("decodeAudioFile: %v", err)
+	}
+	pitches := analyzePitch(pcm, ModeSinging, DefaultAnalysisParams(), nil)
+	if got := voicedMedian(pitches); math.Abs(centsOff(got, 440)) > 10 {
+		t.Errorf("analyzePitch of a 440 Hz WAV = %.2f Hz, want 440", got)
+	}
+	if len(pitches) < 145 || len(pitches) > 150 {
+		t.Errorf("got %d frames for 1.5 seconds, want about 150", len(pitches))
+	}
+	if pitches[20] != 0 {
+		t.Errorf("silent intro analyzed as %.2f Hz", pitches[20])
+	}
+}
+
+func TestDecodeWAVSampleFormats(t *testing.T) {
+	// One frame of +0.5 full scale on the left and -0.25 on the right.
+	const left, right = 16383, -8192
+	f32 := func(v float32) []byte { return binary.LittleEndian.AppendUint32(nil, math.Float32bits(v)) }
+	tests := []struct {
+		name       string
+		format     int
+		bits       int
+		extensible bool
+		frame      []byte
+	}{
+		{"8-bit", wavFormatPCM, 8, false, []byte{128 + 64, 128 - 32}},
+		{"16-bit", wavFormatPCM, 16, false, []byte{0xff, 0x3f, 0x00, 0xe0}},
+		{"24-bit", wavFormatPCM, 24, false, []byte{0, 0xff, 0x3f, 0, 0x00, 0xe0}},
+		{"32-bit", wavFormatPCM, 32, false, []byte{0, 0, 0xff, 0x3f, 0, 0, 0x00, 0xe0}},
+		{"float", wavFormatFloat, 32, false, append(f32(16383.0/32767), f32(-8192.0/32767)...)},
+		{"extensible 16-bit", wavFormatPCM, 16, true, []byte{0xff, 0x3f, 0x00, 0xe0}},
+	}
+	for _, tt := range tests {
+		pcm, err := decodeWAV(bytes.NewReader(wavFile(tt.format, 2, tt.bits, config.SampleRate, tt.extensible, tt.frame)))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(pcm) != 4 {
+			t.Errorf("%s: decoded %d bytes, want one stereo frame", tt.name, len(pcm))
+			continue
+		}
+		l := int16(binary.LittleEndian.Uint16(pcm))
+		r := int16(binary.LittleEndian.Uint16(pcm[2:]))
+		if math.Abs(float64(l-left)) > 256 || math.Abs(float64(r-right)) > 256 {
+			t.Errorf("%s: frame = %d/%d, want about %d/%d", tt.name, l, r, left, right)
+		}
+	}
+}
+
+func TestDecodeWAVResamples(t *testing.T) {
+	half := make([]int16, config.SampleRate/2)
+	for i := range half {
+		half[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/(config.SampleRate/2)))
+	}
+	path := filepath.Join(t.TempDir(), "song.wav")
+	if err := WriteWAV(path, half, config.SampleRate/2); err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := decodeAudioFile(path)
+	if err != nil {
+		t.Fatalf("decodeAudioFile: %v", err)
+	}
+	if frames := len(pcm) / 4; math.Abs(float64(frames-config.SampleRate)) > 8 {
+		t.Errorf("decoded %d frames from one second at 22050 Hz, want about %d", frames, config.SampleRate)
+	}
+}
+
+func TestDecodeWAVRejects(t *testing.T) {
+	tests := map[string][]byte{
+		"no RIFF header": []byte("RIFX....WAVE"),
+		"no fmt chunk":   []byte("RIFF\x0c\x00\x00\x00WAVEdata\x00\x00\x00\x00"),
+		"ADPCM":          wavFile(2, 1, 4, config.SampleRate, false, []byte{0}),
+		"12-bit":         wavFile(wavFormatPCM, 1, 12, config.SampleRate, false, []byte{0, 0}),
+		"64-bit float":   wavFile(wavFormatFloat, 1, 64, config.SampleRate, false, make([]byte, 8)),
+		"short fmt":      []byte("RIFF\x14\x00\x00\x00WAVEfmt \x04\x00\x00\x00\x01\x00\x01\x00"),
+	}
+	for name, data := range tests {
+		if _, err := decodeWAV(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: decodeWAV succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"fmt"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// paDevices, paOpenStream and paOpenDefaultStream are the PortAudio calls
+// device selection and MicHandler.openStream use; tests replace them with
+// a fake device list.
+var (
+	paDevices           = portaudio.Devices
+	paOpenStream        = portaudio.OpenStream
+	paOpenDefaultStream = portaudio.OpenDefaultStream
+)
+
+/*
+ListInputDevices returns every device that can record.
+
+Input:
+  - None (PortAudio must be initialized)
+
+Called by:
+  - App.loadDevices for the start screen device selector
+
+Task:
+  - Let users with several inputs (USB mic, headset, built-in) pick one
+
+Logic:
+ 1. paDevices (portaudio.Devices)
+ 2. Keep devices with at least one input channel, in PortAudio order
+
+Output:
+  - []portaudio.DeviceInfo: Input devices (Index is the PortAudio device index)
+  - error: PortAudio error
+*/
+func ListInputDevices() ([]portaudio.DeviceInfo, error) {
+	devices, err := paDevices()
+	if err != nil {
+		return nil, err
+	}
+	var inputs []portaudio.DeviceInfo
+	for _, d := range devices {
+		if d != nil && d.MaxInputChannels > 0 {
+			inputs = append(inputs, *d)
+		}
+	}
+	return inputs, nil
+}
+
+/*
+OpenStreamOnDevice opens an input stream on a specific device.
+
+Input:
+  - deviceIndex: int - PortAudio device index (DeviceInfo.Index)
+  - sampleRate, bufferSize: int - Stream rate and frames per read
+  - buffer: []float32 - Read buffer; bufferSize samples per channel, interleaved
+
+Called by:
+  - MicHandler.openStream when a device was selected
+
+Task:
+  - Open the chosen microphone instead of the system default
+
+Logic:
+ 1. Channels = len(buffer) / bufferSize
+ 2. Open it through openDeviceStream
+
+Output:
+  - *portaudio.Stream: Opened (not started) stream
+  - error: Unknown device, too few channels, or PortAudio error
+*/
+func OpenStreamOnDevice(deviceIndex int, sampleRate, bufferSize int, buffer []float32) (*portaudio.Stream, error) {
+	return openDeviceStream(deviceIndex, sampleRate, max(1, len(buffer)/max(1, bufferSize)), bufferSize, buffer)
+}
+
+/*
+openDeviceStream opens an input stream on a specific device with a buffer or callback.
+
+Input:
+  - deviceIndex: int - PortAudio device index (DeviceInfo.Index)
+  - sampleRate, channels, bufferSize: int - Stream rate, input channels and frames per buffer
+  - arg: interface{} - Read buffer, or a func([]float32) callback (low-latency ring stream)
+
+Called by:
+  - OpenStreamOnDevice, MicHandler.openStream
+
+Task:
+  - Share device lookup between blocking and callback streams
+
+Logic:
+ 1. Find the device with that index in paDevices
+ 2. Fail if the device has fewer than channels inputs
+ 3. Open it with its low-latency parameters at sampleRate, input only (paOpenStream)
+
+Output:
+  - *portaudio.Stream: Opened (not started) stream
+  - error: Unknown device, too few channels, or PortAudio error
+*/
+func openDeviceStream(deviceIndex int, sampleRate, channels, bufferSize int, arg interface{}) (*portaudio.Stream, error) {
+	devices, err := paDevices()
+	if err != nil {
+		return nil, err
+	}
+	var dev *portaudio.DeviceInfo
+	for _, d := range devices {
+		if d != nil && d.Index == deviceIndex {
+			dev = d
+			break
+		}
+	}
+	if dev == nil {
+		return nil, fmt.Errorf("input device %d not found", deviceIndex)
+	}
+
+	if dev.MaxInputChannels < channels {
+		return nil, fmt.Errorf("%s has %d input channels, need %d", dev.Name, dev.MaxInputChannels, channels)
+	}
+
+	p := portaudio.LowLatencyParameters(dev, nil)
+	p.Input.Channels = channels
+	p.SampleRate = float64(sampleRate)
+	p.FramesPerBuffer = bufferSize
+	return paOpenStream(p, arg)
+}
@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// fakeDevices replaces PortAudio's device list and stream opener; opened
+// records the parameters and buffer of every stream opened.
+type fakeDevices struct {
+	devices []*portaudio.DeviceInfo
+	opened  []portaudio.StreamParameters
+	args    []interface{}
+}
+
+func useFakeDevices(t *testing.T, f *fakeDevices) {
+	t.Helper()
+	oldDevices, oldOpen := paDevices, paOpenStream
+	paDevices = func() ([]*portaudio.DeviceInfo, error) { return f.devices, nil }
+	paOpenStream = func(p portaudio.StreamParameters, args ...interface{}) (*portaudio.Stream, error) {
+		f.opened = append(f.opened, p)
+		f.args = append(f.args, args...)
+		return &portaudio.Stream{}, nil
+	}
+	t.Cleanup(func() { paDevices, paOpenStream = oldDevices, oldOpen })
+}
+
+func testDevices() []*portaudio.DeviceInfo {
+	return []*portaudio.DeviceInfo{
+		{Index: 0, Name: "Built-in Microphone", MaxInputChannels: 1},
+		{Index: 1, Name: "Speakers", MaxOutputChannels: 2},
+		nil,
+		{Index: 3, Name: "USB Audio Interface", MaxInputChannels: 2},
+	}
+}
+
+func TestListInputDevicesKeepsInputs(t *testing.T) {
+	useFakeDevices(t, &fakeDevices{devices: testDevices()})
+
+	got, err := ListInputDevices()
+	if err != nil {
+		t.Fatalf("ListInputDevices: %v", err)
+	}
+	if len(got) != 2 || got[0].Index != 0 || got[1].Index != 3 {
+		t.Errorf("input devices = %+v, want the built-in mic and the USB interface", got)
+	}
+}
+
+func TestOpenStreamOnDeviceParameters(t *testing.T) {
+	f := &fakeDevices{devices: testDevices()}
+	useFakeDevices(t, f)
+
+	buf := make([]float32, 2*512)
+	if _, err := OpenStreamOnDevice(3, 48000, 512, buf); err != nil {
+		t.Fatalf("OpenStreamOnDevice: %v", err)
+	}
+	if len(f.opened) != 1 {
+		t.Fatalf("opened %d streams, want 1", len(f.opened))
+	}
+	p := f.opened[0]
+	if p.Input.Channels != 2 || p.SampleRate != 48000 || p.FramesPerBuffer != 512 || p.Output.Channels != 0 {
+		t.Errorf("stream parameters = %d in / %d out at %v Hz, %d frames; want 2 in, 0 out, 48000 Hz, 512 frames",
+			p.Input.Channels, p.Output.Channels, p.SampleRate, p.FramesPerBuffer)
+	}
+	if b, ok := f.args[0].([]float32); !ok || &b[0] != &buf[0] {
+		t.Errorf("stream reads into %T, want the caller's buffer", f.args[0])
+	}
+}
+
+func TestOpenStreamOnDeviceRejects(t *testing.T) {
+	f := &fakeDevices{devices: testDevices()}
+	useFakeDevices(t, f)
+
+	tests := []struct {
+		name  string
+		index int
+		buf   []float32
+	}{
+		{"unknown device", 7, make([]float32, 512)},
+		{"output-only device", 1, make([]float32, 512)},
+		{"stereo on a mono mic", 0, make([]float32, 2*512)},
+	}
+	for _, tt := range tests {
+		if _, err := OpenStreamOnDevice(tt.index, 44100, 512, tt.buf); err == nil {
+			t.Errorf("%s: OpenStreamOnDevice succeeded, want an error", tt.name)
+		}
+	}
+	if len(f.opened) != 0 {
+		t.Errorf("rejected devices still opened %d streams", len(f.opened))
+	}
+
+	paDevices = func() ([]*portaudio.DeviceInfo, error) { return nil, errors.New("PortAudio not initialized") }
+	if _, err := ListInputDevices(); err == nil {
+		t.Error("ListInputDevices hid the PortAudio error")
+	}
+}
@@ -0,0 +1,226 @@
+package audio
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"slices"
+)
+
+/*
+DifficultyRating rates how hard a song's melody is to sing.
+
+Fields:
+  - Stars: 1 (easy) to 5 (hard), 0 when the song has no pitched frames
+  - MeanJump: Mean semitone change between consecutive pitched frames
+  - StdDev: Standard deviation of the pitch in semitones
+  - Range: Semitones between the lowest and highest note (2nd-98th percentile)
+  - Voiced: Fraction of frames with a pitch
+*/
+type DifficultyRating struct {
+	Stars    int     `json:"stars"`
+	MeanJump float64 `json:"mean_jump"`
+	StdDev   float64 `json:"std_dev"`
+	Range    float64 `json:"range"`
+	Voiced   float64 `json:"voiced"`
+}
+
+/*
+ComputeDifficulty rates a song from its analyzed pitch.
+
+Input:
+  - pitches: []float64 - Song pitch at 10ms intervals (0 = silence)
+
+Called by:
+  - LoadAndAnalyzeSong for vocal modes
+
+Task:
+  - Give the start screen a quick "how hard is this" hint
+
+Logic:
+ 1. Convert pitched frames to MIDI note numbers
+ 2. MeanJump: mean |Δ| between consecutive pitched frames (silence skipped)
+ 3. StdDev of the notes; Range between the 2nd and 98th percentile, so a
+    stray octave error does not stretch it
+ 4. Normalize each to 0..1 (MeanJump/0.5, StdDev/6, (Range-5)/20, Voiced)
+    and weight them 0.3/0.25/0.3/0.15
+ 5. Stars = 1 + floor(5 × score), at most 5: five equal bands of the score,
+    so singing throughout a flat melody (score 0.15) stays 1 star
+
+Output:
+  - DifficultyRating: Rating and the measures behind it
+*/
+func ComputeDifficulty(pitches []float64) DifficultyRating {
+	var notes []float64
+	for _, p := range pitches {
+		if p > 0 {
+			notes = append(notes, 69+12*math.Log2(p/440))
+		}
+	}
+	if len(notes) == 0 {
+		return DifficultyRating{}
+	}
+
+	var d DifficultyRating
+	d.Voiced = float64(len(notes)) / float64(len(pitches))
+
+	var jumps, sum float64
+	for i, n := range notes {
+		sum += n
+		if i > 0 {
+			jumps += math.Abs(n - notes[i-1])
+		}
+	}
+	if len(notes) > 1 {
+		d.MeanJump = jumps / float64(len(notes)-1)
+	}
+	mean := sum / float64(len(notes))
+	var variance float64
+	for _, n := range notes {
+		variance += (n - mean) * (n - mean)
+	}
+	d.StdDev = math.Sqrt(variance / float64(len(notes)))
+
+	sorted := slices.Clone(notes)
+	slices.Sort(sorted)
+	d.Range = sorted[len(sorted)*98/100] - sorted[len(sorted)*2/100]
+
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	score := 0.3*clamp(d.MeanJump/0.5) + 0.25*clamp(d.StdDev/6) + 0.3*clamp((d.Range-5)/20) + 0.15*d.Voiced
+	d.Stars = min(5, 1+int(5*score))
+	return d
+}
+
+/*
+ComputeSegmentDifficulty rates each second of a song's melody.
+
+Input:
+  - pitches: []float64 - Song pitch frames (0 = silence)
+  - samplesPerSec: int - Pitch frames per second (100 for 10ms frames)
+
+Called by:
+  - App.loadSong for the difficulty heat bar
+  - App.retuneAnalysis and App.reanalyzeSong after the contour changed
+
+Task:
+  - Show which parts of a song are hardest, so the user can jump to them
+
+Logic:
+ 1. Convert pitched frames (above 10 Hz) to MIDI note numbers; find the
+    song's median note
+ 2. For each second, over its pitched frames:
+    - jump: mean |Δ| between consecutive pitched frames
+    - speed: note changes (|Δ| of a semitone or more) per second
+    - reach: mean distance from the median note
+ 3. Normalize each to 0..1 (jump/0.5, speed/8, reach/7) and weight them
+    0.4/0.3/0.3; a second without pitched frames scores 0
+
+Output:
+  - []float64: Difficulty 0 (easy)..1 (hard) per second
+*/
+func ComputeSegmentDifficulty(pitches []float64, samplesPerSec int) []float64 {
+	if samplesPerSec <= 0 || len(pitches) == 0 {
+		return nil
+	}
+	notes := make([]float64, len(pitches))
+	var voiced []float64
+	for i, p := range pitches {
+		if p > 10 {
+			notes[i] = 69 + 12*math.Log2(p/440)
+			voiced = append(voiced, notes[i])
+		}
+	}
+	out := make([]float64, (len(pitches)+samplesPerSec-1)/samplesPerSec)
+	if len(voiced) == 0 {
+		return out
+	}
+	slices.Sort(voiced)
+	median := voiced[len(voiced)/2]
+
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	for s := range out {
+		var jumps, reach float64
+		count, steps, changes := 0, 0, 0
+		prev := 0.0
+		for _, n := range notes[s*samplesPerSec : min(len(notes), (s+1)*samplesPerSec)] {
+			if n == 0 {
+				continue
+			}
+			if count > 0 {
+				d := math.Abs(n - prev)
+				jumps += d
+				steps++
+				if d >= 1 {
+					changes++
+				}
+			}
+			reach += math.Abs(n - median)
+			prev = n
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+		jump := 0.0
+		if steps > 0 {
+			jump = jumps / float64(steps)
+		}
+		out[s] = 0.4*clamp(jump/0.5) + 0.3*clamp(float64(changes)/8) + 0.3*clamp(reach/float64(count)/7)
+	}
+	return out
+}
+
+/*
+SaveDifficulty writes a song's rating to difficulty.json.
+
+Input:
+  - path: string - config.SongPaths.DifficultyFile
+  - d: DifficultyRating - Rating to store
+
+Called by:
+  - LoadAndAnalyzeSong next to the pitch cache
+
+Task:
+  - Let the start screen show the rating without analyzing the song
+
+Logic:
+ 1. Marshal indented JSON and write it
+
+Output:
+  - error: nil on success
+*/
+func SaveDifficulty(path string, d DifficultyRating) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+/*
+LoadDifficulty reads a rating written by SaveDifficulty.
+
+Input:
+  - path: string - config.SongPaths.DifficultyFile
+
+Called by:
+  - App.refreshDifficulty
+
+Task:
+  - Read the rating for the start screen
+
+Logic:
+ 1. Read and unmarshal the file
+
+Output:
+  - DifficultyRating: Stored rating
+  - error: Read or JSON error (the file is missing until the song is first analyzed)
+*/
+func LoadDifficulty(path string) (DifficultyRating, error) {
+	var d DifficultyRating
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return d, err
+	}
+	return d, json.Unmarshal(data, &d)
+}
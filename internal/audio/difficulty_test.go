@@ -0,0 +1,91 @@
+package audio
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeDifficultyFlatVersusLeaps(t *testing.T) {
+	flat := make([]float64, 1000)
+	for i := range flat {
+		flat[i] = 220
+	}
+	// A3 and A5 alternating every 100ms: two-octave leaps throughout.
+	leaps := make([]float64, 1000)
+	for i := range leaps {
+		leaps[i] = 220
+		if i/10%2 == 1 {
+			leaps[i] = 880
+		}
+	}
+
+	easy, hard := ComputeDifficulty(flat), ComputeDifficulty(leaps)
+	if easy.Stars != 1 {
+		t.Errorf("flat melody = %d stars (%+v), want 1", easy.Stars, easy)
+	}
+	if easy.MeanJump != 0 || easy.StdDev != 0 || easy.Range != 0 || easy.Voiced != 1 {
+		t.Errorf("flat melody measures = %+v, want no movement, fully voiced", easy)
+	}
+	if hard.Stars != 5 || hard.Range != 24 {
+		t.Errorf("leaping melody = %d stars over %v semitones, want 5 stars over 24", hard.Stars, hard.Range)
+	}
+	if got := ComputeDifficulty(make([]float64, 100)); got != (DifficultyRating{}) {
+		t.Errorf("silence = %+v, want no rating", got)
+	}
+}
+
+func TestDifficultyRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "difficulty.json")
+	want := DifficultyRating{Stars: 3, MeanJump: 0.21, StdDev: 3.5, Range: 12, Voiced: 0.6}
+	if err := SaveDifficulty(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadDifficulty(path); err != nil || got != want {
+		t.Errorf("LoadDifficulty = %+v, %v; want %+v", got, err, want)
+	}
+}
+
+func TestComputeSegmentDifficultyJumpsScoreHigher(t *testing.T) {
+	// Second 0 holds A3; second 1 jumps between A3 and E4 every 50ms;
+	// second 2 is silence; the half second after holds A3 again.
+	pitches := make([]float64, 350)
+	for i := range pitches {
+		switch {
+		case i < 100 || i >= 300:
+			pitches[i] = 220
+		case i < 200 && i/5%2 == 1:
+			pitches[i] = 330
+		case i < 200:
+			pitches[i] = 220
+		}
+	}
+
+	got := ComputeSegmentDifficulty(pitches, 100)
+	if len(got) != 4 {
+		t.Fatalf("got %d seconds, want 4", len(got))
+	}
+	if got[0] >= got[1] {
+		t.Errorf("steady second = %.2f, jumping second = %.2f; want the jumps harder", got[0], got[1])
+	}
+	if got[2] != 0 {
+		t.Errorf("silent second = %.2f, want 0", got[2])
+	}
+}
+
+func TestComputeSegmentDifficultyIgnoresSubAudibleFrames(t *testing.T) {
+	// Values of 10 Hz and below are not pitches anywhere else in the repo.
+	pitches := make([]float64, 200)
+	for i := range pitches {
+		pitches[i] = 220
+		if i >= 100 {
+			pitches[i] = 5
+		}
+	}
+	got := ComputeSegmentDifficulty(pitches, 100)
+	if got[1] != 0 {
+		t.Errorf("second of 5 Hz frames = %.2f, want 0 as silence", got[1])
+	}
+	if want := ComputeSegmentDifficulty(pitches[:100], 100)[0]; got[0] != want {
+		t.Errorf("steady second = %.2f next to 5 Hz frames, %.2f alone", got[0], want)
+	}
+}
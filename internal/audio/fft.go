@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+/*
+fft computes an in-place radix-2 fast Fourier transform.
+
+Input:
+  - x: []complex128 - Signal to transform (length must be a power of two)
+  - inverse: bool - Compute the inverse transform (result scaled by 1/n)
+
+Called by:
+  - stft / istft for spectral separation
+
+Task:
+  - Convert between time and frequency domain in O(n log n)
+
+Logic:
+ 1. Reorder elements by bit-reversed index
+ 2. Run iterative butterflies for sizes 2, 4, ... n
+ 3. For inverse transform, use conjugate twiddles and divide by n
+
+Output:
+  - None (x is overwritten with its transform)
+*/
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, sign*2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a := x[start+k]
+				b := x[start+k+size/2] * w
+				x[start+k] = a + b
+				x[start+k+size/2] = a - b
+				w *= step
+			}
+		}
+	}
+
+	if inverse {
+		scale := complex(1/float64(n), 0)
+		for i := range x {
+			x[i] *= scale
+		}
+	}
+}
+
+/*
+hannWindow returns a periodic Hann window of the given length.
+
+Input:
+  - n: int - Window length in samples
+
+Called by:
+  - SeparateSpectral when setting up the STFT
+
+Task:
+  - Provide a smooth taper that overlap-adds cleanly at 75% overlap
+
+Logic:
+ 1. w[i] = 0.5 - 0.5*cos(2πi/n)
+
+Output:
+  - []float64: Window coefficients
+*/
+func hannWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n))
+	}
+	return w
+}
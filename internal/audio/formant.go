@@ -0,0 +1,109 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+
+	"singAssist/internal/config"
+)
+
+/*
+TrackFormant1 estimates the first formant (F1) of a mic buffer from its spectrum.
+
+Input:
+  - samples: []float32 - Mic buffer (mono)
+  - sampleRate: int - Sample rate of samples
+
+Called by:
+  - App.micLoop for the F1 overlay line (voiced buffers only)
+
+Task:
+  - Follow vocal openness cheaply enough to run on every buffer (one 512-point
+    FFT instead of the LPC fit behind EstimateFormants)
+
+Logic:
+ 1. Decimate to about config.FormantSampleRate by averaging groups of samples
+    and keep the newest config.Formant1FFTSize of them (zero-padded if short)
+ 2. Hann window and FFT; magnitude spectrum
+ 3. Smooth the magnitudes with a ±150Hz triangle so the harmonics of the sung
+    note merge into the vocal tract envelope
+ 4. Detect the fundamental f0 of samples; the search band is
+    config.Formant1MinHz up to the lower of config.Formant1MaxHz and f0
+ 5. F1 is the first envelope peak in that band that reaches half the
+    strongest envelope value there (smoothing ripple is ignored), refined with
+    a parabola through its neighbours and kept only if it is still below f0
+
+Output:
+  - float64: F1 in Hz, 0 for silence, unvoiced buffers, notes at or below
+    config.Formant1MinHz, or when no peak is in range
+*/
+func TrackFormant1(samples []float32, sampleRate int) float64 {
+	f0, _ := DetectPitchWithConfidence(samples, 80, 1200)
+	if f0 <= config.Formant1MinHz {
+		return 0
+	}
+	upper := math.Min(config.Formant1MaxHz, f0)
+
+	factor := max(1, sampleRate/config.FormantSampleRate)
+	n := config.Formant1FFTSize
+	rate := float64(sampleRate) / float64(factor)
+
+	decimated := len(samples) / factor
+	skip := max(0, decimated-n)
+	buf := make([]complex128, n)
+	window := hannWindow(n)
+	var energy float64
+	for i := 0; i < min(n, decimated); i++ {
+		var sum float64
+		for _, s := range samples[(skip+i)*factor : (skip+i+1)*factor] {
+			sum += float64(s)
+		}
+		x := sum / float64(factor)
+		energy += x * x
+		buf[i] = complex(x*window[i], 0)
+	}
+	if energy == 0 {
+		return 0
+	}
+	fft(buf, false)
+
+	binHz := rate / float64(n)
+	mag := make([]float64, n/2)
+	for i := range mag {
+		mag[i] = cmplx.Abs(buf[i])
+	}
+	radius := max(1, int(math.Round(150/binHz)))
+	env := make([]float64, len(mag))
+	for i := range env {
+		var sum, weight float64
+		for k := -radius; k <= radius; k++ {
+			if j := i + k; j >= 0 && j < len(mag) {
+				w := float64(radius+1) - math.Abs(float64(k))
+				sum += w * mag[j]
+				weight += w
+			}
+		}
+		env[i] = sum / weight
+	}
+
+	lo := max(1, int(math.Ceil(config.Formant1MinHz/binHz)))
+	hi := min(len(env)-2, int(math.Ceil(upper/binHz))-1)
+	var strongest float64
+	for i := lo; i <= hi; i++ {
+		strongest = math.Max(strongest, env[i])
+	}
+	for i := lo; i <= hi; i++ {
+		if env[i] > env[i-1] && env[i] >= env[i+1] && env[i] >= 0.5*strongest {
+			bin := float64(i)
+			a, b, c := env[i-1], env[i], env[i+1]
+			if d := a - 2*b + c; d < 0 {
+				bin += 0.5 * (a - c) / d
+			}
+			if f := bin * binHz; f < upper {
+				return f
+			}
+			return 0
+		}
+	}
+	return 0
+}
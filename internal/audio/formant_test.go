@@ -0,0 +1,53 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// mixTones returns 2048 samples of the sum of sines at freqs with amplitudes amps.
+func mixTones(freqs, amps []float64) []float32 {
+	samples := make([]float32, config.BufferSize)
+	for i := range samples {
+		t := float64(i) / config.SampleRate
+		v := 0.0
+		for k, f := range freqs {
+			v += amps[k] * math.Sin(2*math.Pi*f*t)
+		}
+		samples[i] = float32(0.3 * v)
+	}
+	return samples
+}
+
+func TestTrackFormant1BelowFundamental(t *testing.T) {
+	// A 1kHz tone with a band of energy around 450Hz, below the fundamental.
+	samples := mixTones([]float64{1000, 437, 451, 463, 479}, []float64{1, 0.3, 0.3, 0.3, 0.3})
+	f0, _ := DetectPitchWithConfidence(samples, 80, 1200)
+
+	got := TrackFormant1(samples, config.SampleRate)
+	if got < 400 || got > 500 {
+		t.Errorf("TrackFormant1 = %.1f Hz, want the 450Hz peak", got)
+	}
+	if got >= f0 {
+		t.Errorf("TrackFormant1 = %.1f Hz, not below the fundamental %.1f Hz", got, f0)
+	}
+}
+
+func TestTrackFormant1NoPeak(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []float32
+	}{
+		{"silence", make([]float32, config.BufferSize)},
+		{"note below 300Hz", mixTones([]float64{250}, []float64{1})},
+		// The only envelope peaks are the fundamental and its harmonics.
+		{"harmonics only", mixTones([]float64{600, 1200}, []float64{1, 0.5})},
+	}
+	for _, tt := range tests {
+		if got := TrackFormant1(tt.samples, config.SampleRate); got != 0 {
+			t.Errorf("%s: TrackFormant1 = %.1f Hz, want 0", tt.name, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"fmt"
+
+	"singAssist/internal/config"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// hostAPIs maps config.PreferredHostAPI names to PortAudio host API types.
+var hostAPIs = map[string]portaudio.HostApiType{
+	"asio":        portaudio.ASIO,
+	"wasapi":      portaudio.WASAPI,
+	"wdmks":       portaudio.WDMkS,
+	"directsound": portaudio.DirectSound,
+	"mme":         portaudio.MME,
+	"coreaudio":   portaudio.CoreAudio,
+	"alsa":        portaudio.ALSA,
+	"jack":        portaudio.JACK,
+}
+
+/*
+preferredHostAPI returns the host API name the microphone should open.
+
+Input:
+  - None
+
+Called by:
+  - MicHandler.openStream
+
+Task:
+  - Let the environment override the build default
+
+Logic:
+ 1. config.PreferredHostAPI if set ("default" means PortAudio's default stream)
+ 2. Otherwise defaultHostAPI ("asio" with the asio build tag, "" without)
+
+Output:
+  - string: Host API name, "" for the default stream
+*/
+func preferredHostAPI() string {
+	switch config.PreferredHostAPI {
+	case "":
+		return defaultHostAPI
+	case "default":
+		return ""
+	}
+	return config.PreferredHostAPI
+}
+
+/*
+openHostAPIStream opens an input stream on the first suitable device of a host API.
+
+Input:
+  - name: string - Host API name (key of hostAPIs)
+  - channels: int - Input channels needed (1, or 2 for duet)
+  - framesPerBuffer: int - Frames per read
+  - buf: interface{} - Read buffer (interleaved when channels is 2), or a
+    func([]float32) callback for the low-latency ring stream
+
+Called by:
+  - MicHandler.openStream when a host API is preferred
+
+Task:
+  - Low-latency capture (ASIO) where the default WASAPI shared stream is too slow
+
+Logic:
+ 1. Look the host API up with portaudio.HostApi; fail if unknown or unavailable
+ 2. Pick its first device with enough input channels
+ 3. Open it with LowLatencyParameters at config.SampleRate
+
+Output:
+  - *portaudio.Stream: Opened (not started) stream
+  - error: Why the host API could not be used (the caller falls back)
+*/
+func openHostAPIStream(name string, channels, framesPerBuffer int, buf interface{}) (*portaudio.Stream, error) {
+	apiType, ok := hostAPIs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown host API %q", name)
+	}
+	api, err := portaudio.HostApi(apiType)
+	if err != nil {
+		return nil, err
+	}
+	if api == nil {
+		return nil, fmt.Errorf("host API %s not available", name)
+	}
+
+	for _, dev := range api.Devices {
+		if dev.MaxInputChannels < channels {
+			continue
+		}
+		p := portaudio.LowLatencyParameters(dev, nil)
+		p.Input.Channels = channels
+		p.SampleRate = config.SampleRate
+		p.FramesPerBuffer = framesPerBuffer
+		return portaudio.OpenStream(p, buf)
+	}
+	return nil, fmt.Errorf("no %s input device with %d channels", name, channels)
+}
@@ -0,0 +1,13 @@
+//go:build asio
+
+// Low-latency Windows builds open the microphone through ASIO instead of
+// WASAPI shared mode. PortAudio must itself be built with the ASIO SDK:
+//
+//	go build -tags asio .
+//
+// SINGASSIST_HOSTAPI still overrides the choice at run time.
+
+package audio
+
+// defaultHostAPI is the host API used when config.PreferredHostAPI is unset.
+const defaultHostAPI = "asio"
@@ -0,0 +1,7 @@
+//go:build !asio
+
+package audio
+
+// defaultHostAPI is the host API used when config.PreferredHostAPI is unset;
+// "" opens PortAudio's default input stream. Build with -tags asio for ASIO.
+const defaultHostAPI = ""
@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+)
+
+func TestPreferredHostAPI(t *testing.T) {
+	defer func(old string) { config.PreferredHostAPI = old }(config.PreferredHostAPI)
+	tests := []struct{ env, want string }{
+		{"", defaultHostAPI},
+		{"default", ""},
+		{"asio", "asio"},
+		{"wasapi", "wasapi"},
+	}
+	for _, tt := range tests {
+		config.PreferredHostAPI = tt.env
+		if got := preferredHostAPI(); got != tt.want {
+			t.Errorf("preferredHostAPI() with %q = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestOpenHostAPIStreamUnavailable(t *testing.T) {
+	// The PortAudio stub reports no host APIs, as on a machine without ASIO.
+	for _, name := range []string{"asio", "bogus"} {
+		if _, err := openHostAPIStream(name, 1, config.BufferSize, make([]float32, config.BufferSize)); err == nil {
+			t.Errorf("openHostAPIStream(%q) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestOpenStreamFallsBackWithoutASIO(t *testing.T) {
+	t.Chdir(t.TempDir())
+	defer func(old string) { config.PreferredHostAPI = old }(config.PreferredHostAPI)
+	config.PreferredHostAPI = "asio"
+
+	m := NewMicHandler()
+	if err := m.openStream(); err != nil {
+		t.Fatalf("openStream: %v", err)
+	}
+	fellBack := false
+	for _, e := range logging.Recent(logging.LevelWarn) {
+		if strings.Contains(e.Message, "Could not open asio, using the default stream") {
+			fellBack = true
+		}
+	}
+	if !fellBack {
+		t.Error("no fallback warning logged when ASIO is unavailable")
+	}
+}
@@ -0,0 +1,136 @@
+package audio
+
+import "math"
+
+// keyRoots are the pitch-class names DetectKey reports, C = 0.
+var keyRoots = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// majorProfile and minorProfile are the Krumhansl-Kessler tonal hierarchies:
+// how well each scale degree (tonic first) fits a major or minor key.
+var (
+	majorProfile = [12]float64{6.35, 2.23, 3.48, 2.33, 4.38, 4.09, 2.52, 5.19, 2.39, 3.66, 2.29, 2.88}
+	minorProfile = [12]float64{6.33, 2.68, 3.52, 5.38, 2.60, 3.53, 2.54, 4.75, 3.98, 2.69, 3.34, 3.17}
+)
+
+/*
+DetectKey estimates the musical key of a song from its pitch contour.
+
+Input:
+  - pitches: []float64 - Song pitch at 10ms intervals (0 = silence)
+
+Called by:
+  - LoadAndAnalyzeSong after the song pitch is known
+
+Task:
+  - Tell the singer what key the song is in (Krumhansl-Schmuckler)
+
+Logic:
+ 1. Histogram of pitch classes over the pitched frames (nearest semitone),
+    so long notes count more than passing ones
+ 2. For each of the 12 roots, correlate the histogram (rotated so the root
+    comes first) with the major and minor profiles (Pearson r)
+ 3. Return the key with the highest correlation
+
+Output:
+  - string: Root name (e.g. "D", "A#"), "" if no frame is pitched
+  - bool: true for a minor key
+  - float64: Correlation of the best key (-1..1; above ~0.7 is a clear key)
+*/
+func DetectKey(pitches []float64) (string, bool, float64) {
+	var hist [12]float64
+	voiced := false
+	for _, p := range pitches {
+		if p <= 0 {
+			continue
+		}
+		midi := int(math.Round(69 + 12*math.Log2(p/440)))
+		hist[((midi%12)+12)%12]++
+		voiced = true
+	}
+	if !voiced {
+		return "", false, 0
+	}
+
+	bestRoot, bestMinor, bestR := 0, false, math.Inf(-1)
+	var rotated [12]float64
+	for root := 0; root < 12; root++ {
+		for i := range rotated {
+			rotated[i] = hist[(root+i)%12]
+		}
+		if r := pearson(rotated, majorProfile); r > bestR {
+			bestRoot, bestMinor, bestR = root, false, r
+		}
+		if r := pearson(rotated, minorProfile); r > bestR {
+			bestRoot, bestMinor, bestR = root, true, r
+		}
+	}
+	return keyRoots[bestRoot], bestMinor, bestR
+}
+
+/*
+KeyName formats a key for display.
+
+Input:
+  - root: string - Root from DetectKey ("" for unknown)
+  - isMinor: bool - Minor key
+
+Called by:
+  - LoadAndAnalyzeSong for LoadResult.Key
+
+Task:
+  - One spelling of keys for the HUD and metadata.json
+
+Logic:
+ 1. "" stays ""; otherwise "<root> Major" or "<root> Minor"
+
+Output:
+  - string: e.g. "D Major", "A Minor"
+*/
+func KeyName(root string, isMinor bool) string {
+	if root == "" {
+		return ""
+	}
+	if isMinor {
+		return root + " Minor"
+	}
+	return root + " Major"
+}
+
+/*
+pearson returns the correlation coefficient of two 12-element vectors.
+
+Input:
+  - x, y: [12]float64 - Vectors to compare
+
+Called by:
+  - DetectKey
+
+Task:
+  - Score how well a pitch-class histogram matches a key profile
+
+Logic:
+ 1. Subtract each mean, then Σxy / sqrt(Σx² Σy²)
+
+Output:
+  - float64: -1..1 (0 if either vector is constant)
+*/
+func pearson(x, y [12]float64) float64 {
+	var mx, my float64
+	for i := range x {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= 12
+	my /= 12
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
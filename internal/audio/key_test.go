@@ -0,0 +1,50 @@
+package audio
+
+import "testing"
+
+// melody returns a contour holding each MIDI note for the given number of frames.
+func melody(notes []int, frames []int) []float64 {
+	var out []float64
+	for i, n := range notes {
+		for range frames[i] {
+			out = append(out, midiHz(n))
+		}
+		out = append(out, 0)
+	}
+	return out
+}
+
+func TestDetectKeyCMajor(t *testing.T) {
+	// C major scale up and down, with the tonic and dominant held longer as
+	// in most melodies, slightly detuned as a singer would be.
+	notes := []int{60, 62, 64, 65, 67, 69, 71, 72, 67, 64, 60}
+	frames := []int{80, 30, 50, 30, 70, 30, 20, 60, 50, 40, 100}
+	pitches := melody(notes, frames)
+	for i := range pitches {
+		pitches[i] *= 1.004
+	}
+
+	root, minor, confidence := DetectKey(pitches)
+	if got := KeyName(root, minor); got != "C Major" {
+		t.Errorf("key = %q, want C Major", got)
+	}
+	if confidence < 0.8 {
+		t.Errorf("confidence = %.2f, want a clear key (>= 0.8)", confidence)
+	}
+}
+
+func TestDetectKeyMinorAndSilence(t *testing.T) {
+	// A natural minor, resting on A and E.
+	notes := []int{57, 59, 60, 62, 64, 65, 67, 69, 64, 60, 57}
+	frames := []int{80, 30, 50, 30, 70, 30, 20, 60, 50, 40, 100}
+	if root, minor, _ := DetectKey(melody(notes, frames)); KeyName(root, minor) != "A Minor" {
+		t.Errorf("key = %q, want A Minor", KeyName(root, minor))
+	}
+
+	if root, minor, confidence := DetectKey(make([]float64, 100)); root != "" || minor || confidence != 0 {
+		t.Errorf("silence = %q, %v, %v; want no key", root, minor, confidence)
+	}
+	if got := KeyName("", true); got != "" {
+		t.Errorf("KeyName of no root = %q, want empty", got)
+	}
+}
@@ -0,0 +1,71 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+
+	"singAssist/internal/config"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+/*
+GenerateClick synthesizes one metronome click.
+
+Input:
+  - sampleRate: int - Samples per second
+  - durationMs: int - Click length in milliseconds
+
+Called by:
+  - NewClickPlayer
+
+Task:
+  - A short, clearly audible tick that does not mask the song
+
+Logic:
+ 1. durationMs worth of samples of a config.ClickFreq sine at half scale
+ 2. Fade it out linearly so the burst ends without a pop
+
+Output:
+  - []int16: Mono samples
+*/
+func GenerateClick(sampleRate, durationMs int) []int16 {
+	n := sampleRate * durationMs / 1000
+	samples := make([]int16, n)
+	for i := range samples {
+		env := 1 - float64(i)/float64(n)
+		v := math.Sin(2*math.Pi*config.ClickFreq*float64(i)/float64(sampleRate)) * env
+		samples[i] = int16(v * math.MaxInt16 / 2)
+	}
+	return samples
+}
+
+/*
+NewClickPlayer creates a player holding one click.
+
+Input:
+  - None
+
+Called by:
+  - App.toggleMetronome the first time the metronome is enabled
+
+Task:
+  - Mix clicks into the output next to the song player
+
+Logic:
+ 1. GenerateClick at config.SampleRate for config.ClickMs
+ 2. Duplicate each sample into both channels as 16-bit little-endian PCM
+ 3. AudioContext.NewPlayerFromBytes (rewound and played once per beat)
+
+Output:
+  - *audio.Player: Click player on the shared AudioContext
+*/
+func NewClickPlayer() *audio.Player {
+	click := GenerateClick(config.SampleRate, config.ClickMs)
+	pcm := make([]byte, len(click)*4)
+	for i, s := range click {
+		binary.LittleEndian.PutUint16(pcm[i*4:], uint16(s))
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], uint16(s))
+	}
+	return AudioContext.NewPlayerFromBytes(pcm)
+}
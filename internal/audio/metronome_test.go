@@ -0,0 +1,47 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestGenerateClickLength(t *testing.T) {
+	// 10 ms at 44.1 kHz: the 1 kHz sine is zero only at the very first
+	// sample, and the linear fade never reaches zero inside the burst.
+	click := GenerateClick(44100, 10)
+	if len(click) != 441 {
+		t.Fatalf("click has %d samples, want 441", len(click))
+	}
+	nonZero := 0
+	for _, s := range click {
+		if s != 0 {
+			nonZero++
+		}
+	}
+	if nonZero != 440 {
+		t.Errorf("%d non-zero samples, want 440", nonZero)
+	}
+}
+
+func TestGenerateClickFadesOut(t *testing.T) {
+	click := GenerateClick(config.SampleRate, config.ClickMs)
+	peak := func(from, to int) float64 {
+		p := 0.0
+		for _, s := range click[from:to] {
+			p = max(p, math.Abs(float64(s)))
+		}
+		return p
+	}
+	quarter := len(click) / 4
+	if first := peak(0, quarter); first > math.MaxInt16/2 || first < math.MaxInt16/4 {
+		t.Errorf("opening peak = %v, want about half scale", first)
+	}
+	if peak(3*quarter, len(click)) >= peak(0, quarter)/2 {
+		t.Error("click does not fade out")
+	}
+	if got := GenerateClick(config.SampleRate, 0); len(got) != 0 {
+		t.Errorf("0 ms click has %d samples", len(got))
+	}
+}
@@ -2,13 +2,28 @@ package audio
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"singAssist/internal/config"
+	"singAssist/internal/logging"
 
 	"github.com/gordonklaus/portaudio"
 )
 
+/*
+PitchSmoother smooths successive pitch readings.
+
+Fields:
+  - Smooth: Add a raw pitch (<= 0 for silence) and return the smoothed pitch
+  - Reset: Forget all stored pitches
+*/
+type PitchSmoother interface {
+	Smooth(val float64) float64
+	Reset()
+}
+
 /*
 Smoother provides moving average smoothing for pitch values to reduce jitter.
 
@@ -28,7 +43,7 @@ Input:
   - size: int - Number of samples to average (e.g., 5)
 
 Called by:
-  - NewMicHandler when initializing microphone
+  - newPitchSmoother (the default mean smoothing)
 
 Task:
   - Initialize circular buffer for smoothing
@@ -115,24 +130,312 @@ func (s *Smoother) Reset() {
 	}
 }
 
+/*
+MedianSmoother provides moving median smoothing for pitch values.
+
+Fields:
+  - buffer: Circular buffer of recent pitch values
+  - cursor: Current write position in buffer
+  - sorted: Scratch space for the median (reused to avoid allocating per buffer)
+*/
+type MedianSmoother struct {
+	buffer []float64
+	cursor int
+	sorted []float64
+}
+
+/*
+NewMedianSmoother creates a new median pitch smoother with given window size.
+
+Input:
+  - size: int - Number of samples to take the median of (5..9 works well)
+
+Called by:
+  - newPitchSmoother when SINGASSIST_SMOOTH=median
+
+Task:
+  - Initialize circular buffer for smoothing
+
+Logic:
+ 1. Allocate the buffer and the scratch slice
+
+Output:
+  - *MedianSmoother: Ready-to-use smoother instance
+*/
+func NewMedianSmoother(size int) *MedianSmoother {
+	return &MedianSmoother{
+		buffer: make([]float64, size),
+		sorted: make([]float64, 0, size),
+	}
+}
+
+/*
+Smooth applies moving median smoothing to a pitch value.
+
+Input:
+  - val: float64 - Raw pitch value in Hz (0 or negative = silence)
+
+Called by:
+  - detectChannel after raw pitch detection
+
+Task:
+  - Drop single-buffer octave jumps and spikes that the mean would smear in
+
+Logic:
+ 1. If input is <= 0: clear buffer entirely, return 0 (same as Smoother)
+ 2. Store value in circular buffer, advance cursor
+ 3. Copy the non-zero values and sort them (the window is tiny)
+ 4. Return the middle value (mean of the two middle values for even counts)
+
+Output:
+  - float64: Smoothed pitch value in Hz
+*/
+func (s *MedianSmoother) Smooth(val float64) float64 {
+	if val <= 0 {
+		s.Reset()
+		return 0
+	}
+	s.buffer[s.cursor] = val
+	s.cursor = (s.cursor + 1) % len(s.buffer)
+
+	s.sorted = s.sorted[:0]
+	for _, v := range s.buffer {
+		if v > 0 {
+			s.sorted = append(s.sorted, v)
+		}
+	}
+	sort.Float64s(s.sorted)
+
+	n := len(s.sorted)
+	if n%2 == 1 {
+		return s.sorted[n/2]
+	}
+	return (s.sorted[n/2-1] + s.sorted[n/2]) / 2
+}
+
+/*
+Reset clears all values in the median smoother buffer.
+
+Input:
+  - None
+
+Called by:
+  - MedianSmoother.Smooth on silence
+
+Task:
+  - Reset smoother state for fresh start
+
+Logic:
+ 1. Set all buffer values to 0
+
+Output:
+  - None
+*/
+func (s *MedianSmoother) Reset() {
+	for i := range s.buffer {
+		s.buffer[i] = 0
+	}
+}
+
+/*
+newPitchSmoother creates the smoother selected by config.GetSmoothingMode.
+
+Input:
+  - size: int - Window size
+
+Called by:
+  - NewMicHandler, NewDuetMicHandler
+
+Task:
+  - Pick mean or median smoothing from SINGASSIST_SMOOTH
+
+Logic:
+ 1. SmoothMedian: NewMedianSmoother
+ 2. Otherwise: NewSmoother
+
+Output:
+  - PitchSmoother: Smoother for one channel
+*/
+func newPitchSmoother(size int) PitchSmoother {
+	if config.GetSmoothingMode() == config.SmoothMedian {
+		return NewMedianSmoother(size)
+	}
+	return NewSmoother(size)
+}
+
+/*
+RunningNoiseGate tracks the background noise level with an exponential moving average.
+
+Fields:
+  - ewma: Average energy of recent unpitched (noise) frames
+  - alpha: EMA weight of each new frame (config.NoiseGateAlpha)
+  - margin: Multiplier over ewma frames must exceed (the mic sensitivity)
+*/
+type RunningNoiseGate struct {
+	ewma   float64
+	alpha  float64
+	margin float64
+}
+
+/*
+NewRunningNoiseGate creates a gate seeded with a measured noise level.
+
+Input:
+  - initial: float64 - Starting noise energy (e.g. from Calibrate)
+  - alpha: float64 - EMA decay rate per frame
+  - margin: float64 - Safety margin over the noise level (MicHandler.SensitivityMultiplier)
+
+Called by:
+  - MicHandler.Calibrate after the startup measurement
+
+Task:
+  - Start the gate where the static calibration would have put it
+
+Logic:
+ 1. Store initial as the average and keep alpha and margin
+
+Output:
+  - *RunningNoiseGate: Ready-to-use gate
+*/
+func NewRunningNoiseGate(initial, alpha, margin float64) *RunningNoiseGate {
+	return &RunningNoiseGate{ewma: initial, alpha: alpha, margin: margin}
+}
+
+/*
+Update folds the energy of a noise frame into the running average.
+
+Input:
+  - energy: float64 - Energy of a frame with no detected pitch
+
+Called by:
+  - MicHandler.DetectPitchFromMic for gated or unpitched frames
+
+Task:
+  - Follow slow changes in room noise (a fan starting, crowd noise)
+
+Logic:
+ 1. ewma = (1 - alpha) * ewma + alpha * energy
+
+Output:
+  - None
+*/
+func (g *RunningNoiseGate) Update(energy float64) {
+	g.ewma = (1-g.alpha)*g.ewma + g.alpha*energy
+}
+
+/*
+Threshold returns the current gate level.
+
+Input:
+  - None
+
+Called by:
+  - MicHandler.DetectPitchFromMic
+
+Task:
+  - Energy below which a mic frame is treated as silence
+
+Logic:
+ 1. Return margin × the running noise average (the safety margin Calibrate chose)
+
+Output:
+  - float64: Energy threshold
+*/
+func (g *RunningNoiseGate) Threshold() float64 {
+	return g.ewma * g.margin
+}
+
+/*
+MicInput is the microphone as seen by the app, so a fake can stand in for PortAudio.
+
+Fields:
+  - Start: Open the input stream
+  - Reopen: Reopen the stream after a read error
+  - Stop: Close the stream and signal shutdown
+  - Read: Fill the sample buffer
+  - IsDone: Whether Stop has been called
+  - Calibrate: Measure background noise for a duration
+  - DetectPitchFromMic: Detect the pitch of the current buffer (two pitches in duet mode)
+  - CurrentPitch: Last first-singer pitch returned by DetectPitchFromMic
+  - CurrentPitch2: Last second-singer pitch (0 unless capturing stereo)
+  - CurrentVibrato: Vibrato in the first singer's last second of pitch
+  - Samples: The buffer filled by the last Read (first singer's channel)
+  - Delivered: Only the audio that arrived since the previous Read, for recording
+*/
+type MicInput interface {
+	Start() error
+	Reopen() error
+	Stop()
+	Read() error
+	IsDone() bool
+	Calibrate(duration time.Duration) float64
+	DetectPitchFromMic(mode Mode) (float64, float64)
+	CurrentPitch() float64
+	CurrentPitch2() float64
+	CurrentVibrato() VibratoInfo
+	Samples() []float32
+	Delivered() []float32
+}
+
+var _ MicInput = (*MicHandler)(nil)
+
 /*
 MicHandler manages microphone input capture and pitch detection.
 
 Fields:
   - Stream: PortAudio stream handle
-  - Buffer: Audio sample buffer (float32, mono)
+  - Buffer: Audio sample buffer (float32, mono; the left channel in duet mode,
+    the mix of both channels with StereoCapture)
   - Done: Channel to signal goroutine shutdown
-  - Smoother: Pitch smoothing instance
+  - Smoother: Pitch smoothing instance (mean or median, see newPitchSmoother)
   - Pitch: Current detected pitch (updated by DetectPitchFromMic)
-  - Threshold: Noise gate threshold (set by Calibrate)
+  - Gate: Adaptive noise gate (seeded by Calibrate)
+  - Vibrato: Vibrato detector fed the first channel's unsmoothed pitch
+  - Onset: Holds Pitch at 0 until a note lasts config.MinVoicedDurationMs
+  - Stereo: Capture two channels, one singer per channel (duet mode)
+  - Buffer2, Smoother2, Pitch2, Gate2, Onset2: Right-channel counterparts for the second singer
+  - StereoCapture: Capture a stereo mic for one singer and mix it to Buffer
+    (config.StereoMicOff: one channel)
+  - Channels: Left and right samples of the last buffer with StereoCapture, kept for recording
+  - Device: PortAudio index of the input device to open, -1 for the default
+  - VocalRange: Range from the vocal range test (config/vocal_range.json; zero if never taken)
+  - SensitivityMultiplier: Noise gate margin over the calibrated noise (config/sensitivity.json)
+  - interleaved: Raw L/R frames read from the stereo stream (duet or StereoCapture)
+  - ring: Blocks from the callback stream in low-latency mode (nil: blocking reads)
+  - fresh: Samples slid into Buffer from the ring since the last detection
+  - delivered: Samples that arrived since Read started (see Delivered)
+  - seenDropped: ring.Dropped() already filled with silence in delivered
+  - clockMs: Audio time of the detected buffers (sample count), for the onset trackers
 */
 type MicHandler struct {
-	Stream    *portaudio.Stream
-	Buffer    []float32
-	Done      chan struct{}
-	Smoother  *Smoother
-	Pitch     float64
-	Threshold float64
+	Stream   *portaudio.Stream
+	Buffer   []float32
+	Done     chan struct{}
+	Smoother PitchSmoother
+	Pitch    float64
+	Gate     *RunningNoiseGate
+	Vibrato  *VibratoDetector
+	Onset    *VoiceOnsetTracker
+
+	Stereo    bool
+	Buffer2   []float32
+	Smoother2 PitchSmoother
+	Pitch2    float64
+	Gate2     *RunningNoiseGate
+	Onset2    *VoiceOnsetTracker
+
+	StereoCapture config.StereoMicMode
+	Channels      [2][]float32
+
+	Device                int
+	VocalRange            config.VocalRange
+	SensitivityMultiplier float64
+	interleaved           []float32
+	ring                  *RingBuffer
+	fresh                 int
+	delivered             []float32
+	seenDropped           uint64
+	clockMs               float64
 }
 
 /*
@@ -149,16 +452,83 @@ Task:
 
 Logic:
  1. Allocate buffer of config.BufferSize samples
- 2. Create smoother with window of 5
+ 2. Create smoother with window of 5 (mean, or median with SINGASSIST_SMOOTH=median)
+ 3. Use the default input device (Device = -1)
+ 4. With SINGASSIST_LOWLATENCY: add the RingBuffer the callback stream writes
+    config.RingBlockSize blocks into; Buffer stays a config.BufferSize window
+    that slides one block at a time (see pullBlocks)
+ 5. Create the vibrato detector for one pitch per buffer (per block with a
+    ring) and the onset tracker
+ 6. Load the saved vocal range and sensitivity (each logged and defaulted on error)
+ 7. With SINGASSIST_STEREO_MIC (config.GetStereoMicMode) and no ring: capture
+    two channels into Channels and the interleaved buffer
 
 Output:
   - *MicHandler: Handler ready for Start() call
 */
 func NewMicHandler() *MicHandler {
-	return &MicHandler{
+	m := &MicHandler{
 		Buffer:   make([]float32, config.BufferSize),
-		Smoother: NewSmoother(5),
+		Smoother: newPitchSmoother(5),
+		Gate:     NewRunningNoiseGate(0, config.NoiseGateAlpha, config.DefaultSensitivity),
+		Onset:    NewVoiceOnsetTracker(),
+		Device:   -1,
+	}
+	if config.LowLatencyMic() {
+		m.ring = NewRingBuffer(config.RingBlocks, config.RingBlockSize)
 	}
+	m.Vibrato = NewVibratoDetector(float64(config.SampleRate) / float64(m.framesPerBuffer()))
+	vr, err := config.LoadVocalRange()
+	if err != nil {
+		logging.Warnf("Ignoring %s: %v", config.VocalRangeFile, err)
+	}
+	m.VocalRange = vr
+	if m.SensitivityMultiplier, err = config.LoadSensitivity(); err != nil {
+		logging.Warnf("Ignoring %s: %v", config.SensitivityFile, err)
+	}
+	if mode := config.GetStereoMicMode(); mode != config.StereoMicOff && m.ring == nil {
+		m.StereoCapture = mode
+		m.Channels = [2][]float32{make([]float32, config.BufferSize), make([]float32, config.BufferSize)}
+		m.interleaved = make([]float32, 2*config.BufferSize)
+	}
+	return m
+}
+
+/*
+NewDuetMicHandler creates a handler that captures two singers on one stereo input.
+
+Input:
+  - None
+
+Called by:
+  - App.startGame for ModeDuet
+
+Task:
+  - Set up per-channel buffers, smoothers and gates
+
+Logic:
+ 1. Start from NewMicHandler (left channel / first singer); duet always uses
+    blocking config.BufferSize reads, so drop any low-latency ring, and each
+    channel is its own singer, so no StereoCapture mix
+ 2. Add the right-channel buffer, smoother, gate and onset tracker
+ 3. Allocate the interleaved read buffer (2 samples per frame)
+
+Output:
+  - *MicHandler: Stereo handler ready for Start() call
+*/
+func NewDuetMicHandler() *MicHandler {
+	m := NewMicHandler()
+	m.Stereo = true
+	m.StereoCapture, m.Channels = config.StereoMicOff, [2][]float32{}
+	m.Buffer = make([]float32, config.BufferSize)
+	m.ring = nil
+	m.Vibrato = NewVibratoDetector(float64(config.SampleRate) / float64(config.BufferSize))
+	m.Buffer2 = make([]float32, config.BufferSize)
+	m.Smoother2 = newPitchSmoother(5)
+	m.Gate2 = NewRunningNoiseGate(0, config.NoiseGateAlpha, m.SensitivityMultiplier)
+	m.Onset2 = NewVoiceOnsetTracker()
+	m.interleaved = make([]float32, 2*config.BufferSize)
+	return m
 }
 
 /*
@@ -171,19 +541,111 @@ Called by:
   - App.startGame after cleanup
 
 Task:
-  - Open default microphone stream
+  - Open the microphone stream (the selected device, or ASIO when built with
+    -tags asio, falling back to the default stream)
   - Start audio capture with retry logic
 
 Logic:
- 1. Try up to 3 times with exponential backoff
- 2. Open PortAudio default stream (1 input channel, mono, SampleRate Hz)
- 3. Start stream capture
- 4. Initialize Done channel for shutdown signaling
+ 1. Open and start the stream via openStream (retries with backoff)
+ 2. Initialize Done channel for shutdown signaling
 
 Output:
   - error: nil on success, PortAudio error after all retries
 */
 func (m *MicHandler) Start() error {
+	if err := m.openStream(); err != nil {
+		return err
+	}
+	m.Done = make(chan struct{})
+	return nil
+}
+
+/*
+Reopen closes the current stream and opens it again.
+
+Input:
+  - None
+
+Called by:
+  - App.micLoop after a Read error (e.g., USB mic unplugged and replugged)
+
+Task:
+  - Recover from a broken stream without ending the session
+
+Logic:
+ 1. Stop and close the existing stream, ignoring errors
+ 2. Open a fresh stream with openStream's retry logic
+ 3. Leave the Done channel untouched so shutdown still works
+
+Output:
+  - error: nil on success, PortAudio error after all retries
+*/
+func (m *MicHandler) Reopen() error {
+	if m.Stream != nil {
+		m.Stream.Stop()
+		m.Stream.Close()
+		m.Stream = nil
+	}
+	return m.openStream()
+}
+
+/*
+openStream opens and starts the default input stream with retries.
+
+Input:
+  - None
+
+Called by:
+  - Start, Reopen
+
+Task:
+  - Share the retry logic between first start and recovery
+
+Logic:
+ 1. If a device was selected (Device >= 0): open and start it with
+    openDeviceStream; else if a host API is preferred (asio build tag or
+    SINGASSIST_HOSTAPI): its first input device with openHostAPIStream.
+    On any failure log it and fall back to the default stream below.
+    With a ring every stream gets m.ring.Put as its callback instead of a buffer
+ 2. Try up to 3 times with exponential backoff
+ 3. Open PortAudio default stream (inputChannels: 1, or 2 interleaved
+    channels when Stereo or StereoCapture, SampleRate Hz)
+ 4. Start stream capture
+ 5. When a two-channel StereoCapture open fails (steps 1 and 3), drop to one
+    channel with dropStereoCapture and open the same stream again
+
+Output:
+  - error: nil on success, PortAudio error after all retries
+*/
+func (m *MicHandler) openStream() error {
+	if m.Device >= 0 || preferredHostAPI() != "" {
+		source := fmt.Sprintf("input device %d", m.Device)
+		if m.Device < 0 {
+			source = preferredHostAPI()
+		}
+		open := func() (*portaudio.Stream, error) {
+			if m.Device >= 0 {
+				return openDeviceStream(m.Device, config.SampleRate, m.inputChannels(), m.framesPerBuffer(), m.streamArg())
+			}
+			return openHostAPIStream(source, m.inputChannels(), m.framesPerBuffer(), m.streamArg())
+		}
+		stream, err := open()
+		if err != nil && m.dropStereoCapture(err) {
+			stream, err = open()
+		}
+		if err == nil {
+			if err = stream.Start(); err != nil {
+				stream.Close()
+			}
+		}
+		if err == nil {
+			m.Stream = stream
+			logging.Infof("Microphone opened through %s", source)
+			return nil
+		}
+		logging.Warnf("Could not open %s, using the default stream: %v", source, err)
+	}
+
 	var err error
 	maxRetries := 3
 
@@ -192,7 +654,10 @@ func (m *MicHandler) Start() error {
 			time.Sleep(time.Duration(100*(1<<attempt)) * time.Millisecond)
 		}
 
-		m.Stream, err = portaudio.OpenDefaultStream(1, 0, config.SampleRate, len(m.Buffer), m.Buffer)
+		m.Stream, err = paOpenDefaultStream(m.inputChannels(), 0, config.SampleRate, m.framesPerBuffer(), m.streamArg())
+		if err != nil && m.dropStereoCapture(err) {
+			m.Stream, err = paOpenDefaultStream(m.inputChannels(), 0, config.SampleRate, m.framesPerBuffer(), m.streamArg())
+		}
 		if err != nil {
 			continue
 		}
@@ -203,13 +668,164 @@ func (m *MicHandler) Start() error {
 			continue
 		}
 
-		m.Done = make(chan struct{})
 		return nil
 	}
 
 	return fmt.Errorf("failed to start microphone after %d attempts: %v", maxRetries, err)
 }
 
+/*
+dropStereoCapture falls back to one channel after a stereo open failed.
+
+Input:
+  - err: error - Why the two-channel stream did not open
+
+Called by:
+  - openStream
+
+Task:
+  - Keep the mic working on a mono-only device when SINGASSIST_STEREO_MIC is set
+
+Logic:
+ 1. Nothing in duet mode (Stereo needs both channels) or without StereoCapture
+ 2. Log the failure, turn StereoCapture off and clear Channels, so the
+    stream is opened with one channel reading straight into Buffer
+
+Output:
+  - bool: true if the caller should open the stream again
+*/
+func (m *MicHandler) dropStereoCapture(err error) bool {
+	if m.Stereo || m.StereoCapture == config.StereoMicOff {
+		return false
+	}
+	logging.Warnf("Could not open a stereo microphone, capturing one channel: %v", err)
+	m.StereoCapture, m.Channels = config.StereoMicOff, [2][]float32{}
+	return true
+}
+
+/*
+streamArg returns what PortAudio should fill: the read buffer or the ring callback.
+
+Input:
+  - None
+
+Called by:
+  - openStream
+
+Task:
+  - Keep the blocking and callback streams on the same open paths
+
+Logic:
+ 1. With a ring: m.ring.Put (non-blocking callback stream)
+ 2. Two input channels: the interleaved buffer
+ 3. Otherwise: m.Buffer
+
+Output:
+  - interface{}: Buffer or callback for portaudio.OpenStream
+*/
+func (m *MicHandler) streamArg() interface{} {
+	if m.ring != nil {
+		return m.ring.Put
+	}
+	if m.inputChannels() == 2 {
+		return m.interleaved
+	}
+	return m.Buffer
+}
+
+/*
+framesPerBuffer returns how many frames the stream delivers at a time.
+
+Input:
+  - None
+
+Called by:
+  - NewMicHandler (vibrato rate), openStream
+
+Task:
+  - Small callback blocks with a ring, whole buffers for blocking reads
+
+Logic:
+ 1. config.RingBlockSize with a ring, else len(Buffer)
+
+Output:
+  - int: Frames per buffer
+*/
+func (m *MicHandler) framesPerBuffer() int {
+	if m.ring != nil {
+		return config.RingBlockSize
+	}
+	return len(m.Buffer)
+}
+
+/*
+pullBlocks slides every unread ring block into Buffer.
+
+Input:
+  - None (m.ring must be set)
+
+Called by:
+  - Read, DetectPitchFromMic
+
+Task:
+  - Keep Buffer a config.BufferSize window over the newest audio while the
+    callback delivers config.RingBlockSize blocks
+
+Logic:
+ 1. For each block from m.ring.Next, oldest first: shift Buffer left by the
+    block length and copy the block to its end
+ 2. Add the samples to fresh and append them to delivered
+ 3. For blocks the ring dropped since the last call, append as much silence
+    to delivered so a recording keeps the session's timing
+
+Output:
+  - int: Samples slid in (0 if the callback delivered nothing new)
+*/
+func (m *MicHandler) pullBlocks() int {
+	n := 0
+	for {
+		block, ok := m.ring.Next()
+		if !ok {
+			break
+		}
+		keep := copy(m.Buffer, m.Buffer[len(block):])
+		copy(m.Buffer[keep:], block)
+		m.delivered = append(m.delivered, block...)
+		n += len(block)
+	}
+	if dropped := m.ring.Dropped(); dropped > m.seenDropped {
+		m.delivered = append(m.delivered, make([]float32, int(dropped-m.seenDropped)*config.RingBlockSize)...)
+		m.seenDropped = dropped
+	}
+	m.fresh += n
+	return n
+}
+
+/*
+inputChannels returns how many channels the stream captures.
+
+Input:
+  - None
+
+Called by:
+  - openStream, streamArg
+
+Task:
+  - One place that decides between mono and stereo capture
+
+Logic:
+ 1. 2 when Stereo (duet) or StereoCapture is on, else 1
+
+Output:
+  - int: Input channel count
+*/
+func (m *MicHandler) inputChannels() int {
+	if m.Stereo || m.StereoCapture != config.StereoMicOff {
+		return 2
+	}
+	return 1
+}
+
 /*
 Stop safely shuts down microphone capture.
 
@@ -249,7 +865,7 @@ func (m *MicHandler) Stop() {
 Read fills the buffer with samples from microphone.
 
 Input:
-  - None (reads into m.Buffer)
+  - None (reads into m.Buffer, and m.Buffer2 when Stereo)
 
 Called by:
   - App.micLoop on each iteration
@@ -259,8 +875,14 @@ Task:
   - Block until buffer is filled with audio samples
 
 Logic:
- 1. If stream is nil, return nil (no-op)
- 2. Call PortAudio Read to fill buffer
+ 1. If stream is nil, return nil (no-op); otherwise empty delivered
+ 2. With a ring: wait (1ms polls) for the callback to deliver a block and
+    slide the new blocks into Buffer with pullBlocks; return early if Stop is called
+ 3. Otherwise call PortAudio Read to fill buffer
+ 4. If Stereo: split the interleaved frames into Buffer (left) and Buffer2 (right);
+    with StereoCapture: split them into Channels and mix those into Buffer
+    with StereoToMono (the noise gate then sees the mixed signal)
+ 5. Without a ring the whole Buffer is new: delivered is Buffer
 
 Output:
   - error: nil on success, PortAudio error on failure
@@ -269,7 +891,89 @@ func (m *MicHandler) Read() error {
 	if m.Stream == nil {
 		return nil
 	}
-	return m.Stream.Read()
+	m.delivered = m.delivered[:0]
+	if m.ring != nil {
+		for !m.IsDone() {
+			if m.pullBlocks() > 0 {
+				return nil
+			}
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	}
+	if err := m.Stream.Read(); err != nil {
+		return err
+	}
+	if m.Stereo {
+		deinterleave(m.Buffer, m.Buffer2, m.interleaved)
+	} else if m.StereoCapture != config.StereoMicOff {
+		deinterleave(m.Channels[0], m.Channels[1], m.interleaved)
+		StereoToMono(m.Buffer, m.Channels[0], m.Channels[1], m.StereoCapture == config.StereoMicLoudest)
+	}
+	m.delivered = append(m.delivered, m.Buffer...)
+	return nil
+}
+
+/*
+deinterleave splits interleaved stereo frames into two channel buffers.
+
+Input:
+  - left, right: []float32 - Destination buffers (one sample per frame)
+  - frames: []float32 - Interleaved samples, left first (2 per frame)
+
+Called by:
+  - MicHandler.Read for duet and StereoCapture reads
+
+Task:
+  - Separate the two inputs of a stereo device
+
+Logic:
+ 1. left[i] = frames[2i], right[i] = frames[2i+1]
+
+Output:
+  - None (fills left and right)
+*/
+func deinterleave(left, right, frames []float32) {
+	for i := range left {
+		left[i] = frames[2*i]
+		right[i] = frames[2*i+1]
+	}
+}
+
+/*
+StereoToMono mixes a stereo mic buffer down for pitch detection.
+
+Input:
+  - dst: []float32 - Mono output (same length as left and right)
+  - left, right: []float32 - Channel samples
+  - loudest: bool - Keep the channel with more energy instead of averaging
+
+Called by:
+  - MicHandler.Read with StereoCapture
+
+Task:
+  - Use both capsules of a stereo mic; averaging evens out room reflections,
+    picking the louder side favours the capsule facing the singer
+
+Logic:
+ 1. loudest: copy whichever channel has the higher CalculateEnergy
+ 2. Otherwise dst[i] = (left[i] + right[i]) / 2
+
+Output:
+  - None (fills dst)
+*/
+func StereoToMono(dst, left, right []float32, loudest bool) {
+	if loudest {
+		if CalculateEnergy(left) >= CalculateEnergy(right) {
+			copy(dst, left)
+		} else {
+			copy(dst, right)
+		}
+		return
+	}
+	for i := range dst {
+		dst[i] = (left[i] + right[i]) / 2
+	}
 }
 
 /*
@@ -314,31 +1018,32 @@ Task:
 
 Logic:
  1. Record energy samples for specified duration
- 2. Find maximum energy observed
- 3. Set threshold to 1.5x max (safety margin)
+ 2. Find maximum energy observed (per channel when Stereo)
+ 3. Seed the running gate(s) with it (threshold = max × SensitivityMultiplier,
+    the safety margin)
 
 Output:
-  - float64: Calculated noise threshold
+  - float64: Initial noise threshold (first channel)
 */
 func (m *MicHandler) Calibrate(duration time.Duration) float64 {
-	var energies []float64
+	maxE, maxE2 := 0.0, 0.0
 	endTime := time.Now().Add(duration)
 
 	for time.Now().Before(endTime) {
 		if err := m.Read(); err != nil {
 			break
 		}
-		energies = append(energies, CalculateEnergy(m.Buffer))
+		maxE = max(maxE, CalculateEnergy(m.Buffer))
+		if m.Stereo {
+			maxE2 = max(maxE2, CalculateEnergy(m.Buffer2))
+		}
 	}
 
-	maxE := 0.0
-	for _, e := range energies {
-		if e > maxE {
-			maxE = e
-		}
+	m.Gate = NewRunningNoiseGate(maxE, config.NoiseGateAlpha, m.SensitivityMultiplier)
+	if m.Stereo {
+		m.Gate2 = NewRunningNoiseGate(maxE2, config.NoiseGateAlpha, m.SensitivityMultiplier)
 	}
-	m.Threshold = maxE * 1.5
-	return m.Threshold
+	return m.Gate.Threshold()
 }
 
 /*
@@ -351,33 +1056,231 @@ Called by:
   - App.micLoop on each iteration during playback
 
 Task:
-  - Gate noise below threshold
-  - Detect and smooth pitch from microphone buffer
+  - Gate noise below the adaptive threshold
+  - Detect and smooth pitch from microphone buffer(s)
+
+Logic:
+ 1. With a ring: slide in blocks the callback delivered since Read (never
+    waits) and advance clockMs by the fresh samples; otherwise by one buffer
+ 2. Pick the search range with pitchRange
+ 3. Run detectChannel on Buffer (feeding the vibrato detector), gate it with
+    Onset and store in m.Pitch
+ 4. If Stereo: run detectChannel on Buffer2 with its own gate, smoother and
+    Onset2, store in m.Pitch2
+
+Output:
+  - float64: First singer's pitch in Hz (0 if below threshold)
+  - float64: Second singer's pitch in Hz (always 0 for mono capture)
+*/
+func (m *MicHandler) DetectPitchFromMic(mode Mode) (float64, float64) {
+	advance := len(m.Buffer)
+	if m.ring != nil {
+		m.pullBlocks()
+		advance, m.fresh = m.fresh, 0
+	}
+	m.clockMs += float64(advance) / config.SampleRate * 1000
+	minF, maxF := m.pitchRange(mode)
+	m.Pitch = m.Onset.Track(detectChannel(m.Buffer, m.Gate, m.Smoother, m.Vibrato, minF, maxF), m.clockMs)
+	if m.Stereo {
+		m.Pitch2 = m.Onset2.Track(detectChannel(m.Buffer2, m.Gate2, m.Smoother2, nil, minF, maxF), m.clockMs)
+	}
+	return m.Pitch, m.Pitch2
+}
+
+/*
+pitchRange returns the frequency range the mic pitch is searched in.
+
+Input:
+  - mode: Mode - Current playback mode
+
+Called by:
+  - DetectPitchFromMic
+
+Task:
+  - Narrow the search for singing, to the user's own range once it is known
+
+Logic:
+ 1. Other modes: 40-2000Hz (with YIN the 2048-sample buffer only reaches
+    down to ~43Hz, see detectPitchYIN)
+ 2. Vocal modes: 85-1100Hz, or the saved VocalRange widened by
+    config.VocalRangeMargin semitones on each side (kept within 40-2000Hz)
+
+Output:
+  - minF, maxF: float64 - Search range in Hz
+*/
+func (m *MicHandler) pitchRange(mode Mode) (minF, maxF float64) {
+	if !mode.IsVocal() {
+		return 40, 2000
+	}
+	if m.VocalRange.LowHz <= 0 || m.VocalRange.HighHz <= m.VocalRange.LowHz {
+		return 85, 1100
+	}
+	margin := math.Pow(2, config.VocalRangeMargin/12)
+	return max(40, m.VocalRange.LowHz/margin), min(2000, m.VocalRange.HighHz*margin)
+}
+
+/*
+detectChannel gates, detects and smooths the pitch of one channel.
+
+Input:
+  - buf: []float32 - Samples of one channel
+  - gate: *RunningNoiseGate - That channel's noise gate
+  - smoother: PitchSmoother - That channel's smoother
+  - vibrato: *VibratoDetector - That channel's vibrato detector (nil for none)
+  - minF, maxF: float64 - Frequency range to search (see pitchRange)
+
+Called by:
+  - MicHandler.DetectPitchFromMic
+
+Task:
+  - Share the per-channel pipeline between mono and duet capture
 
 Logic:
- 1. Calculate energy of current buffer
- 2. If below threshold: set Pitch to 0, return 0
- 3. Set frequency range based on mode (narrower for singing)
- 4. Run DetectPitch on buffer
- 5. Apply smoothing
- 6. Store in m.Pitch and return
+ 1. Calculate energy of the buffer
+ 2. If below gate.Threshold: update the gate, tell the vibrato detector about
+    the silence and return 0
+ 3. Run DetectPitchWithConfidence on buffer within minF..maxF
+ 4. If confidence < config.MinPitchConfidence (loud but unpitched noise):
+    update the gate and treat as silence
+ 5. Feed the raw pitch to the vibrato detector (smoothing would hide it)
+ 6. Apply smoothing and return
 
 Output:
   - float64: Detected pitch in Hz (0 if below threshold)
 */
-func (m *MicHandler) DetectPitchFromMic(mode Mode) float64 {
-	energy := CalculateEnergy(m.Buffer)
-	if energy < m.Threshold {
-		m.Pitch = 0
+func detectChannel(buf []float32, gate *RunningNoiseGate, smoother PitchSmoother, vibrato *VibratoDetector, minF, maxF float64) float64 {
+	energy := CalculateEnergy(buf)
+	if energy < gate.Threshold() {
+		gate.Update(energy)
+		if vibrato != nil {
+			vibrato.Update(0)
+		}
 		return 0
 	}
 
-	minF, maxF := 40.0, 2000.0
-	if mode == ModeSinging {
-		minF, maxF = 85.0, 1100.0
+	rawPitch, confidence := DetectPitchWithConfidence(buf, minF, maxF)
+	if confidence < config.MinPitchConfidence {
+		gate.Update(energy)
+		rawPitch = 0
+	}
+	if vibrato != nil {
+		vibrato.Update(rawPitch)
 	}
+	return smoother.Smooth(rawPitch)
+}
+
+/*
+CurrentPitch returns the most recently detected pitch.
+
+Input:
+  - None
+
+Called by:
+  - App drawing code through the MicInput interface
+
+Task:
+  - Read-only access to Pitch for MicInput users
+
+Logic:
+ 1. Return m.Pitch
 
-	rawPitch := DetectPitch(m.Buffer, minF, maxF)
-	m.Pitch = m.Smoother.Smooth(rawPitch)
+Output:
+  - float64: Pitch in Hz (0 = silence)
+*/
+func (m *MicHandler) CurrentPitch() float64 {
 	return m.Pitch
 }
+
+/*
+CurrentPitch2 returns the most recently detected second-singer pitch.
+
+Input:
+  - None
+
+Called by:
+  - App drawing code through the MicInput interface (duet mode)
+
+Task:
+  - Read-only access to Pitch2 for MicInput users
+
+Logic:
+ 1. Return m.Pitch2
+
+Output:
+  - float64: Pitch in Hz (0 = silence or mono capture)
+*/
+func (m *MicHandler) CurrentPitch2() float64 {
+	return m.Pitch2
+}
+
+/*
+CurrentVibrato returns the vibrato found in the last second of pitch.
+
+Input:
+  - None
+
+Called by:
+  - App drawing code through the MicInput interface
+
+Task:
+  - Read-only access to the vibrato detector for MicInput users
+
+Logic:
+ 1. Return m.Vibrato.Info (no vibrato if there is no detector)
+
+Output:
+  - VibratoInfo: Rate, depth and whether vibrato is present
+*/
+func (m *MicHandler) CurrentVibrato() VibratoInfo {
+	if m.Vibrato == nil {
+		return VibratoInfo{}
+	}
+	return m.Vibrato.Info()
+}
+
+/*
+Samples returns the buffer filled by the last Read.
+
+Input:
+  - None
+
+Called by:
+  - App.micLoop for the spectrogram view (same goroutine as Read)
+
+Task:
+  - Expose raw samples through the MicInput interface
+
+Logic:
+ 1. Return m.Buffer (not a copy; the next Read overwrites it)
+
+Output:
+  - []float32: Mono samples (left channel in duet mode)
+*/
+func (m *MicHandler) Samples() []float32 {
+	return m.Buffer
+}
+
+/*
+Delivered returns the audio that arrived since the last Read began.
+
+Input:
+  - None
+
+Called by:
+  - App.micLoop to feed the session recorder (same goroutine as Read)
+
+Task:
+  - Record every sample exactly once: Samples is a sliding window that
+    overlaps the previous one in low-latency mode
+
+Logic:
+ 1. Return m.delivered: the read buffer for blocking reads, every ring block
+    slid in by Read and DetectPitchFromMic (plus silence for dropped blocks)
+    in low-latency mode
+
+Output:
+  - []float32: Mono samples (not a copy; the next Read reuses it)
+*/
+func (m *MicHandler) Delivered() []float32 {
+	return m.delivered
+}
@@ -0,0 +1,216 @@
+package audio
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// framesToSettle feeds energy into gate until its threshold is within 10% of
+// the step from start to the new level, and returns the number of frames.
+func framesToSettle(gate *RunningNoiseGate, start, energy float64) int {
+	target := energy * gate.margin
+	step := math.Abs(target - start*gate.margin)
+	for n := 1; n <= 100000; n++ {
+		gate.Update(energy)
+		if math.Abs(gate.Threshold()-target) <= 0.1*step {
+			return n
+		}
+	}
+	return -1
+}
+
+func TestRunningNoiseGateFollowsStep(t *testing.T) {
+	// With alpha = 0.001 the average covers 90% of a step after
+	// ln(10)/alpha ≈ 2302 frames, in either direction.
+	const quiet, loud = 0.001, 0.004
+	gate := NewRunningNoiseGate(quiet, config.NoiseGateAlpha, 1.5)
+	if got := gate.Threshold(); math.Abs(got-quiet*1.5) > 1e-12 {
+		t.Fatalf("initial threshold = %v, want %v", got, quiet*1.5)
+	}
+
+	if n := framesToSettle(gate, quiet, loud); n < 2250 || n > 2350 {
+		t.Errorf("gate took %d frames to follow the noise rising, want about 2302", n)
+	}
+	gate = NewRunningNoiseGate(loud, config.NoiseGateAlpha, 1.5)
+	if n := framesToSettle(gate, loud, quiet); n < 2250 || n > 2350 {
+		t.Errorf("gate took %d frames to follow the noise falling, want about 2302", n)
+	}
+}
+
+func TestRunningNoiseGateIgnoresPitchedFrames(t *testing.T) {
+	gate := NewRunningNoiseGate(0.001, 0.5, 1.5)
+	smoother := NewSmoother(1)
+
+	tone := synthTone(220, []float64{1, 0.5, 0.33})
+	for i := 0; i < 20; i++ {
+		if got := detectChannel(tone, gate, smoother, nil, 80, 1000); got == 0 {
+			t.Fatal("a loud tone was gated")
+		}
+	}
+	if got := gate.Threshold(); math.Abs(got-0.0015) > 1e-12 {
+		t.Errorf("threshold moved to %v while singing, want 0.0015", got)
+	}
+
+	quiet := sine(config.BufferSize, 220, 0.01)
+	if got := detectChannel(quiet, gate, smoother, nil, 80, 1000); got != 0 {
+		t.Errorf("noise below the gate detected as %.2f Hz", got)
+	}
+	if got, want := gate.Threshold(), 1.5*(0.5*0.001+0.5*CalculateEnergy(quiet)); math.Abs(got-want) > 1e-12 {
+		t.Errorf("threshold after a noise frame = %v, want %v", got, want)
+	}
+}
+
+func TestDeinterleaveSeparatesChannels(t *testing.T) {
+	frames := []float32{1, -1, 2, -2, 3, -3, 4, -4}
+	left, right := make([]float32, 4), make([]float32, 4)
+	deinterleave(left, right, frames)
+	for i := range left {
+		if left[i] != float32(i+1) || right[i] != -float32(i+1) {
+			t.Fatalf("frame %d split into %v/%v, want %v/%v", i, left[i], right[i], i+1, -(i + 1))
+		}
+	}
+}
+
+func TestDuetDetectsEachChannel(t *testing.T) {
+	m := NewDuetMicHandler()
+	m.Gate = NewRunningNoiseGate(0.0001, config.NoiseGateAlpha, 1.5)
+	m.Gate2 = NewRunningNoiseGate(0.0001, config.NoiseGateAlpha, 1.5)
+
+	left := synthTone(220, []float64{1, 0.5, 0.33})
+	right := synthTone(330, []float64{1, 0.5, 0.33})
+	frames := make([]float32, 2*len(left))
+	for i := range left {
+		frames[2*i], frames[2*i+1] = left[i], right[i]
+	}
+
+	var p1, p2 float64
+	for range 10 {
+		deinterleave(m.Buffer, m.Buffer2, frames)
+		p1, p2 = m.DetectPitchFromMic(ModeDuet)
+	}
+	if math.Abs(centsOff(p1, 220)) > 15 || math.Abs(centsOff(p2, 330)) > 15 {
+		t.Errorf("duet pitches = %.2f, %.2f Hz; want 220 and 330", p1, p2)
+	}
+}
+
+func TestMedianSmootherIgnoresOutlier(t *testing.T) {
+	sequence := []float64{220, 220, 220, 440, 220, 220, 220}
+	median, mean := NewMedianSmoother(5), NewSmoother(5)
+	skewed := false
+	for i, v := range sequence {
+		if got := median.Smooth(v); got != 220 {
+			t.Errorf("median after value %d (%v) = %v, want 220", i, v, got)
+		}
+		if mean.Smooth(v) > 220 {
+			skewed = true
+		}
+	}
+	if !skewed {
+		t.Error("the mean was never pulled up by the outlier")
+	}
+}
+
+func TestMedianSmootherEvenCountAndSilence(t *testing.T) {
+	s := NewMedianSmoother(5)
+	s.Smooth(200)
+	if got := s.Smooth(220); got != 210 {
+		t.Errorf("median of 200 and 220 = %v, want 210", got)
+	}
+	if got := s.Smooth(0); got != 0 {
+		t.Errorf("silence = %v, want 0", got)
+	}
+	if got := s.Smooth(300); got != 300 {
+		t.Errorf("first value after silence = %v, want 300 (buffer cleared)", got)
+	}
+}
+
+func TestNewPitchSmootherFromEnv(t *testing.T) {
+	t.Setenv(config.SmoothingEnv, "median")
+	if _, ok := newPitchSmoother(5).(*MedianSmoother); !ok {
+		t.Errorf("SINGASSIST_SMOOTH=median gave %T, want *MedianSmoother", newPitchSmoother(5))
+	}
+	for _, v := range []string{"", "mean", "bogus"} {
+		t.Setenv(config.SmoothingEnv, v)
+		if _, ok := newPitchSmoother(5).(*Smoother); !ok {
+			t.Errorf("SINGASSIST_SMOOTH=%q gave %T, want *Smoother", v, newPitchSmoother(5))
+		}
+	}
+}
+
+func TestStereoToMono(t *testing.T) {
+	left, right := make([]float32, 64), make([]float32, 64)
+	for i := range left {
+		left[i], right[i] = 0.5, 0.3
+	}
+	dst := make([]float32, 64)
+	StereoToMono(dst, left, right, false)
+	for i, v := range dst {
+		if math.Abs(float64(v)-0.4) > 1e-6 {
+			t.Fatalf("mix sample %d = %v, want 0.4", i, v)
+		}
+	}
+	StereoToMono(dst, left, right, true)
+	if dst[0] != 0.5 {
+		t.Errorf("loudest channel gave %v, want the left channel's 0.5", dst[0])
+	}
+}
+
+func TestStereoCaptureFallsBackToMono(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(config.StereoMicEnv, "mix")
+	f := &fakeDevices{devices: testDevices()}
+	useFakeDevices(t, f)
+
+	// Device 0 is the built-in microphone with a single input channel.
+	m := NewMicHandler()
+	m.Device = 0
+	if m.StereoCapture != config.StereoMicMix {
+		t.Fatalf("StereoCapture = %q, want mix", m.StereoCapture)
+	}
+	if err := m.openStream(); err != nil {
+		t.Fatalf("openStream: %v", err)
+	}
+	if len(f.opened) != 1 || f.opened[0].Input.Channels != 1 {
+		t.Fatalf("opened %+v, want one stream with 1 input channel", f.opened)
+	}
+	if b, ok := f.args[0].([]float32); !ok || &b[0] != &m.Buffer[0] {
+		t.Errorf("mono stream reads into %T, want Buffer", f.args[0])
+	}
+	if m.StereoCapture != config.StereoMicOff || m.inputChannels() != 1 {
+		t.Errorf("StereoCapture = %q after the fallback, want off", m.StereoCapture)
+	}
+}
+
+func TestStereoCaptureDefaultStreamFallsBackToMono(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(config.StereoMicEnv, "loudest")
+	var channels []int
+	old := paOpenDefaultStream
+	paOpenDefaultStream = func(in, out int, rate float64, frames int, args ...interface{}) (*portaudio.Stream, error) {
+		channels = append(channels, in)
+		if in > 1 {
+			return nil, errors.New("Invalid number of channels")
+		}
+		return &portaudio.Stream{}, nil
+	}
+	t.Cleanup(func() { paOpenDefaultStream = old })
+
+	m := NewMicHandler()
+	if err := m.openStream(); err != nil {
+		t.Fatalf("openStream: %v", err)
+	}
+	if len(channels) != 2 || channels[0] != 2 || channels[1] != 1 {
+		t.Errorf("opened the default stream with %v channels, want 2 then 1", channels)
+	}
+
+	// A duet needs both channels: no fallback.
+	d := NewDuetMicHandler()
+	if d.dropStereoCapture(errors.New("no stereo")) {
+		t.Error("duet handler dropped to one channel")
+	}
+}
@@ -0,0 +1,265 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+/*
+midiNote is one note parsed from a MIDI track.
+
+Fields:
+  - Start, End: Note-on and note-off positions in ticks
+  - Key: MIDI note number
+*/
+type midiNote struct {
+	Start uint64
+	End   uint64
+	Key   int
+}
+
+/*
+tempoChange is a set-tempo meta event.
+
+Fields:
+  - Tick: Position of the change
+  - MicrosPerQuarter: New tempo
+*/
+type tempoChange struct {
+	Tick             uint64
+	MicrosPerQuarter float64
+}
+
+/*
+LoadReferenceMIDI converts a Standard MIDI File into a reference pitch contour.
+
+Input:
+  - path: string - MIDI file (usually <song>/reference.mid)
+
+Called by:
+  - LoadAndAnalyzeSong in ModeNoAudio when the song folder has a reference MIDI
+
+Task:
+  - Give no-audio practice a scrolling melody to follow
+
+Logic:
+ 1. Parse the MThd header (ticks-per-quarter timing only) and every MTrk chunk
+ 2. Collect notes from all channels except 10 (drums) and the tempo map
+ 3. Convert note ticks to seconds through the tempo map (default 120 BPM)
+ 4. Fill a 10ms frame array; where notes overlap keep the highest (the melody)
+
+Output:
+  - []float64: Pitch in Hz at 10ms intervals (0 = rest)
+  - error: Read error or malformed/unsupported MIDI
+*/
+func LoadReferenceMIDI(path string) ([]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 14 || string(data[0:4]) != "MThd" {
+		return nil, errors.New("not a MIDI file")
+	}
+	headerLen := int(binary.BigEndian.Uint32(data[4:8]))
+	if headerLen < 6 || 8+headerLen > len(data) {
+		return nil, errors.New("bad MIDI header")
+	}
+	division := binary.BigEndian.Uint16(data[12:14])
+	if division&0x8000 != 0 {
+		return nil, errors.New("SMPTE-timed MIDI files are not supported")
+	}
+	ticksPerQuarter := float64(division)
+
+	var notes []midiNote
+	var tempos []tempoChange
+
+	rest := data[8+headerLen:]
+	for len(rest) >= 8 {
+		chunkLen := int(binary.BigEndian.Uint32(rest[4:8]))
+		if 8+chunkLen > len(rest) {
+			return nil, errors.New("truncated MIDI chunk")
+		}
+		if string(rest[0:4]) == "MTrk" {
+			n, t, err := parseMIDITrack(rest[8 : 8+chunkLen])
+			if err != nil {
+				return nil, err
+			}
+			notes = append(notes, n...)
+			tempos = append(tempos, t...)
+		}
+		rest = rest[8+chunkLen:]
+	}
+
+	sort.SliceStable(tempos, func(i, j int) bool { return tempos[i].Tick < tempos[j].Tick })
+	toSec := func(tick uint64) float64 {
+		sec, lastTick, tempo := 0.0, uint64(0), 500000.0
+		for _, tc := range tempos {
+			if tc.Tick >= tick {
+				break
+			}
+			sec += float64(tc.Tick-lastTick) / ticksPerQuarter * tempo / 1e6
+			lastTick, tempo = tc.Tick, tc.MicrosPerQuarter
+		}
+		return sec + float64(tick-lastTick)/ticksPerQuarter*tempo/1e6
+	}
+
+	last := 0.0
+	for _, n := range notes {
+		last = math.Max(last, toSec(n.End))
+	}
+	pitches := make([]float64, int(last*100)+1)
+	for _, n := range notes {
+		freq := 440 * math.Pow(2, float64(n.Key-69)/12)
+		from, to := int(toSec(n.Start)*100), int(toSec(n.End)*100)
+		for f := from; f < to && f < len(pitches); f++ {
+			pitches[f] = math.Max(pitches[f], freq)
+		}
+	}
+
+	return pitches, nil
+}
+
+/*
+parseMIDITrack decodes the events of one MTrk chunk.
+
+Input:
+  - track: []byte - Chunk body
+
+Called by:
+  - LoadReferenceMIDI for each track
+
+Task:
+  - Extract notes and tempo changes, skipping everything else
+
+Logic:
+ 1. Read variable-length delta times and accumulate absolute ticks
+ 2. Support running status for channel messages
+ 3. Note-on with velocity > 0 opens a note; note-off (or velocity 0) closes it
+ 4. Meta 0x51 is a tempo change; other meta and sysex events are skipped by length
+ 5. Notes still open at the end of the track close at the last tick
+
+Output:
+  - []midiNote: Notes in this track (channel 10 excluded)
+  - []tempoChange: Tempo events in this track
+  - error: Malformed event data
+*/
+func parseMIDITrack(track []byte) ([]midiNote, []tempoChange, error) {
+	r := bytes.NewReader(track)
+	readVar := func() (uint64, error) {
+		var v uint64
+		for i := 0; i < 4; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			v = v<<7 | uint64(b&0x7f)
+			if b&0x80 == 0 {
+				return v, nil
+			}
+		}
+		return 0, errors.New("bad MIDI variable-length value")
+	}
+	skip := func(n uint64) error {
+		if n > uint64(r.Len()) {
+			return errors.New("truncated MIDI event")
+		}
+		_, err := r.Seek(int64(n), 1)
+		return err
+	}
+
+	var notes []midiNote
+	var tempos []tempoChange
+	open := map[int]uint64{}
+	var tick uint64
+	var status byte
+
+	for r.Len() > 0 {
+		delta, err := readVar()
+		if err != nil {
+			return nil, nil, fmt.Errorf("midi delta time: %w", err)
+		}
+		tick += delta
+
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case b == 0xff:
+			kind, err := r.ReadByte()
+			if err != nil {
+				return nil, nil, err
+			}
+			length, err := readVar()
+			if err != nil {
+				return nil, nil, err
+			}
+			if kind == 0x51 && length == 3 {
+				var t [3]byte
+				if _, err := r.Read(t[:]); err != nil {
+					return nil, nil, err
+				}
+				us := float64(uint32(t[0])<<16 | uint32(t[1])<<8 | uint32(t[2]))
+				tempos = append(tempos, tempoChange{Tick: tick, MicrosPerQuarter: us})
+			} else if err := skip(length); err != nil {
+				return nil, nil, err
+			}
+			continue
+		case b == 0xf0 || b == 0xf7:
+			length, err := readVar()
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := skip(length); err != nil {
+				return nil, nil, err
+			}
+			continue
+		case b&0x80 != 0:
+			status = b
+		default:
+			if status == 0 {
+				return nil, nil, errors.New("MIDI data byte without status")
+			}
+			r.UnreadByte()
+		}
+
+		dataLen := 2
+		if kind := status & 0xf0; kind == 0xc0 || kind == 0xd0 {
+			dataLen = 1
+		}
+		var d [2]byte
+		if _, err := r.Read(d[:dataLen]); err != nil {
+			return nil, nil, err
+		}
+
+		channel := int(status & 0x0f)
+		if channel == 9 {
+			continue
+		}
+		key := channel<<8 | int(d[0])
+		kind := status & 0xf0
+		if kind == 0x90 && d[1] > 0 {
+			if _, ok := open[key]; !ok {
+				open[key] = tick
+			}
+		} else if kind == 0x80 || kind == 0x90 {
+			if start, ok := open[key]; ok {
+				notes = append(notes, midiNote{Start: start, End: tick, Key: int(d[0])})
+				delete(open, key)
+			}
+		}
+	}
+
+	for key, start := range open {
+		notes = append(notes, midiNote{Start: start, End: tick, Key: key & 0xff})
+	}
+
+	return notes, tempos, nil
+}
@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeMIDI writes a format-0 MIDI file with 480 ticks per quarter and one
+// track holding events (delta times included).
+func writeMIDI(t *testing.T, events []byte) string {
+	t.Helper()
+	data := []byte("MThd\x00\x00\x00\x06\x00\x00\x00\x01\x01\xe0MTrk")
+	track := append(events, 0x00, 0xff, 0x2f, 0x00)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(track)))
+	data = append(data, track...)
+
+	path := filepath.Join(t.TempDir(), "reference.mid")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// checkFrames checks that pitches holds want from frame from up to (not including) to.
+func checkFrames(t *testing.T, pitches []float64, from, to int, want float64) {
+	t.Helper()
+	for f := from; f < to; f++ {
+		if math.Abs(pitches[f]-want) > 0.01 {
+			t.Fatalf("frame %d = %.2f Hz, want %.2f Hz", f, pitches[f], want)
+		}
+	}
+}
+
+func TestLoadReferenceMIDITwoNotes(t *testing.T) {
+	// A4 for one quarter, then C5 for one quarter, at the default 120 BPM.
+	// 480 ticks is the variable-length value 0x83 0x60.
+	path := writeMIDI(t, []byte{
+		0x00, 0x90, 69, 100,
+		0x83, 0x60, 0x80, 69, 0,
+		0x00, 0x90, 72, 100,
+		0x83, 0x60, 0x80, 72, 0,
+	})
+
+	pitches, err := LoadReferenceMIDI(path)
+	if err != nil {
+		t.Fatalf("LoadReferenceMIDI: %v", err)
+	}
+	if len(pitches) != 101 {
+		t.Fatalf("got %d frames, want 101 for one second", len(pitches))
+	}
+	checkFrames(t, pitches, 0, 50, 440)
+	checkFrames(t, pitches, 50, 100, midiHz(72))
+	checkFrames(t, pitches, 100, 101, 0)
+}
+
+func TestLoadReferenceMIDITempoAndRunningStatus(t *testing.T) {
+	// The tempo halves after the first quarter; the second note uses running
+	// status and ends with a velocity-0 note-on. A drum note is ignored.
+	path := writeMIDI(t, []byte{
+		0x00, 0x90, 69, 100,
+		0x00, 0x99, 36, 100,
+		0x83, 0x60, 0x80, 69, 0,
+		0x00, 0xff, 0x51, 0x03, 0x0f, 0x42, 0x40,
+		0x00, 0x90, 72, 100,
+		0x83, 0x60, 72, 0,
+	})
+
+	pitches, err := LoadReferenceMIDI(path)
+	if err != nil {
+		t.Fatalf("LoadReferenceMIDI: %v", err)
+	}
+	if len(pitches) != 151 {
+		t.Fatalf("got %d frames, want 151 for 1.5 seconds", len(pitches))
+	}
+	checkFrames(t, pitches, 0, 50, 440)
+	checkFrames(t, pitches, 50, 150, midiHz(72))
+}
+
+func TestLoadReferenceMIDIRejectsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"text.mid":      []byte("not a midi file at all"),
+		"smpte.mid":     []byte("MThd\x00\x00\x00\x06\x00\x00\x00\x01\xe7\x28"),
+		"truncated.mid": []byte("MThd\x00\x00\x00\x06\x00\x00\x00\x01\x01\xe0MTrk\x00\x00\x01\x00\x00\x90"),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadReferenceMIDI(path); err == nil {
+			t.Errorf("LoadReferenceMIDI(%s) succeeded, want an error", name)
+		}
+	}
+	if _, err := LoadReferenceMIDI(filepath.Join(dir, "missing.mid")); err == nil {
+		t.Error("LoadReferenceMIDI succeeded on a missing file")
+	}
+}
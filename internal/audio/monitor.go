@@ -0,0 +1,112 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+
+	"singAssist/internal/config"
+)
+
+/*
+MicMonitorStream passes mic buffers on to an ebiten player so the singer hears
+themselves.
+
+Fields:
+  - mu: Guards buf (micLoop writes, the audio player reads)
+  - buf: 16-bit stereo PCM not yet read by the player
+  - maxBytes: Most PCM kept in buf; older frames are dropped so the delay stays short
+*/
+type MicMonitorStream struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	maxBytes int
+}
+
+/*
+NewMicMonitorStream creates an empty monitor stream.
+
+Input:
+  - sampleRate: int - Sample rate of the mic buffers (and of the player)
+
+Called by:
+  - App.toggleMicMonitor
+
+Task:
+  - Size the backlog to config.MicMonitorBuffer
+
+Logic:
+ 1. maxBytes = 4 bytes per stereo frame × frames in MicMonitorBuffer
+
+Output:
+  - *MicMonitorStream: Stream that reads silence until Write is called
+*/
+func NewMicMonitorStream(sampleRate int) *MicMonitorStream {
+	frames := int(config.MicMonitorBuffer.Seconds() * float64(sampleRate))
+	return &MicMonitorStream{maxBytes: 4 * max(1, frames)}
+}
+
+/*
+Write queues one mic buffer for playback.
+
+Input:
+  - samples: []float32 - Mono mic samples (-1..1)
+
+Called by:
+  - App.micLoop after each mic read while monitoring is on
+
+Task:
+  - Convert the mic format to what the audio context plays
+
+Logic:
+ 1. Clamp each sample to -1..1 and scale it to int16
+ 2. Append it to buf twice (left and right), little-endian
+ 3. Drop the oldest whole frames beyond maxBytes
+
+Output:
+  - None
+*/
+func (s *MicMonitorStream) Write(samples []float32) {
+	frame := make([]byte, 4)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range samples {
+		x := uint16(int16(math.Max(-1, math.Min(1, float64(v))) * math.MaxInt16))
+		binary.LittleEndian.PutUint16(frame, x)
+		binary.LittleEndian.PutUint16(frame[2:], x)
+		s.buf.Write(frame)
+	}
+	if over := s.buf.Len() - s.maxBytes; over > 0 {
+		s.buf.Next(over + (4-over%4)%4)
+	}
+}
+
+/*
+Read hands queued PCM to the audio player.
+
+Input:
+  - p: []byte - Destination buffer
+
+Called by:
+  - The ebiten audio player reading the monitor
+
+Task:
+  - Never run out, so the player keeps going between mic buffers
+
+Logic:
+ 1. Copy whole frames from buf
+ 2. Fill the rest of p (whole frames only) with silence
+
+Output:
+  - int: Bytes written (a multiple of 4)
+  - error: nil always
+*/
+func (s *MicMonitorStream) Read(p []byte) (int, error) {
+	n := len(p) / 4 * 4
+	s.mu.Lock()
+	read, _ := s.buf.Read(p[:n])
+	s.mu.Unlock()
+	clear(p[read:n])
+	return n, nil
+}
@@ -0,0 +1,56 @@
+package audio
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// monitorFrames decodes stereo 16-bit PCM into left and right samples.
+func monitorFrames(pcm []byte) (left, right []int16) {
+	for i := 0; i+3 < len(pcm); i += 4 {
+		left = append(left, int16(binary.LittleEndian.Uint16(pcm[i:])))
+		right = append(right, int16(binary.LittleEndian.Uint16(pcm[i+2:])))
+	}
+	return left, right
+}
+
+func TestMicMonitorStreamConvertsSamples(t *testing.T) {
+	s := NewMicMonitorStream(config.SampleRate)
+	s.Write([]float32{0, 0.5, -0.5, 1, -1, 2, -3})
+	want := []int16{0, 16383, -16383, 32767, -32767, 32767, -32767}
+
+	// Two frames more than were written: the rest is silence.
+	p := make([]byte, 4*(len(want)+2)+3)
+	if n, err := s.Read(p); n != 4*(len(want)+2) || err != nil {
+		t.Fatalf("Read = %d, %v; want %d whole frames", n, err, len(want)+2)
+	}
+	left, right := monitorFrames(p)
+	for i, w := range append(want, 0, 0) {
+		if left[i] != w || right[i] != w {
+			t.Errorf("frame %d = %d/%d, want %d in both channels", i, left[i], right[i], w)
+		}
+	}
+}
+
+func TestMicMonitorStreamDropsOldestFrames(t *testing.T) {
+	s := NewMicMonitorStream(1000)
+	limit := s.maxBytes / 4
+	old := make([]float32, limit)
+	for i := range old {
+		old[i] = -0.5
+	}
+	s.Write(old)
+	s.Write([]float32{0.25, 0.5})
+
+	p := make([]byte, 4*limit+8)
+	s.Read(p)
+	left, _ := monitorFrames(p)
+	if left[limit-2] != 8191 || left[limit-1] != 16383 {
+		t.Errorf("newest frames = %d, %d; want 8191, 16383 at the end of the backlog", left[limit-2], left[limit-1])
+	}
+	if left[0] != -16383 || left[limit] != 0 {
+		t.Errorf("backlog starts with %d and continues with %d; want the old -16383 frames, then silence after %d frames", left[0], left[limit], limit)
+	}
+}
@@ -0,0 +1,46 @@
+package audio
+
+import (
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestLoadAndAnalyzeSongOfflineFallsBackToFullMix(t *testing.T) {
+	dir := t.TempDir()
+	// Half a second of silence, then two seconds of A3; no vocals.mp3.
+	samples := make([]int16, 5*config.SampleRate/2)
+	for i := config.SampleRate / 2; i < len(samples); i++ {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*220*float64(i)/config.SampleRate))
+	}
+	if err := WriteWAV(filepath.Join(dir, "song.wav"), samples, config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+
+	config.SetOfflineMode(true)
+	t.Cleanup(func() { config.SetOfflineMode(false) })
+	useFakeRunner(t, &fakeRunner{run: func(name string, args []string) ([]byte, error) {
+		t.Errorf("offline mode ran %s %v", name, args)
+		return nil, nil
+	}})
+
+	var messages []string
+	result, err := LoadAndAnalyzeSong(dir, ModeSinging, LoadOptions{Analysis: DefaultAnalysisParams()}, func(m string) {
+		messages = append(messages, m)
+	})
+	if err != nil {
+		t.Fatalf("LoadAndAnalyzeSong: %v", err)
+	}
+	if want := config.GetSongPaths(dir).SongFile; result.AudioFile != want {
+		t.Errorf("analyzed %s, want the full mix %s", result.AudioFile, want)
+	}
+	if !slices.Contains(messages, "Separation unavailable (offline mode)") {
+		t.Errorf("messages %q lack the offline warning", messages)
+	}
+	if got := voicedMedian(result.SongPitch); math.Abs(centsOff(got, 220)) > 20 {
+		t.Errorf("song pitch median = %.1f Hz, want 220", got)
+	}
+}
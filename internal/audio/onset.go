@@ -0,0 +1,142 @@
+package audio
+
+import (
+	"math"
+
+	"singAssist/internal/config"
+)
+
+// onsetMaxSemitones is how far the pitch may move between frames while a
+// voiced run is still counted as stable.
+const onsetMaxSemitones = 2.0
+
+/*
+VoiceOnsetTracker holds back a pitch until it has been stable for a while.
+
+Fields:
+  - MinDurationMs: How long the pitch must hold before Track reports it
+  - runStartMs: Time the current stable run started
+  - lastMs: Time of the previous Track call (end of the previous buffer)
+  - lastPitch: Previous pitch passed to Track (0 = silence)
+  - open: The current run passed the onset test; stays set until silence
+*/
+type VoiceOnsetTracker struct {
+	MinDurationMs float64
+	runStartMs    float64
+	lastMs        float64
+	lastPitch     float64
+	open          bool
+}
+
+/*
+NewVoiceOnsetTracker creates a tracker with the configured minimum duration.
+
+Input:
+  - None
+
+Called by:
+  - NewMicHandler, NewDuetMicHandler (one per channel)
+
+Task:
+  - Gate mic pitch with config.MinVoicedDurationMs
+
+Logic:
+ 1. MinDurationMs = config.MinVoicedDurationMs, no run in progress
+
+Output:
+  - *VoiceOnsetTracker: Ready-to-use tracker
+*/
+func NewVoiceOnsetTracker() *VoiceOnsetTracker {
+	return &VoiceOnsetTracker{MinDurationMs: config.MinVoicedDurationMs}
+}
+
+/*
+Track gates one detected pitch.
+
+Input:
+  - pitch: float64 - Detected pitch in Hz (<= 0 for silence)
+  - nowMs: float64 - Time at the end of the buffer the pitch came from
+
+Called by:
+  - MicHandler.DetectPitchFromMic for each channel
+
+Task:
+  - Drop single-buffer noise blips before they reach the trail
+
+Logic:
+ 1. Silence ends the run and returns 0
+ 2. A pitch after silence, or more than onsetMaxSemitones from the previous
+    one before the run opened, starts a new run at the previous call's time
+    (the start of this buffer)
+ 3. Once the run has lasted MinDurationMs it opens; an open run passes every
+    pitch until the next silence, so sung intervals are not delayed
+
+Output:
+  - float64: pitch once the onset test passed, otherwise 0
+*/
+func (t *VoiceOnsetTracker) Track(pitch float64, nowMs float64) float64 {
+	prevMs := t.lastMs
+	if prevMs == 0 || prevMs > nowMs {
+		prevMs = nowMs
+	}
+	t.lastMs = nowMs
+
+	if pitch <= 0 {
+		t.lastPitch, t.open = 0, false
+		return 0
+	}
+	if t.lastPitch <= 0 || (!t.open && math.Abs(12*math.Log2(pitch/t.lastPitch)) > onsetMaxSemitones) {
+		t.runStartMs = prevMs
+	}
+	t.lastPitch = pitch
+
+	if !t.open && nowMs-t.runStartMs >= t.MinDurationMs {
+		t.open = true
+	}
+	if !t.open {
+		return 0
+	}
+	return pitch
+}
+
+/*
+GateShortVoicing removes voiced runs that are too short to be sung notes.
+
+Input:
+  - pitches: []float64 - Pitch data at 10ms intervals (0 = silence)
+  - minFrames: int - Shortest voiced run to keep (config.MinVoicedDurationMs / 10)
+
+Called by:
+  - analyzePitch and ReanalyzeWindow before gap filling
+
+Task:
+  - Apply the mic's onset gate to the song analysis, without its delay
+    (the whole song is known, so runs can be measured before deciding)
+
+Logic:
+ 1. Find each run of consecutive voiced frames; jumps inside a run are
+    left to CorrectOctaveErrors instead of splitting it
+ 2. Zero runs shorter than minFrames
+
+Output:
+  - []float64: Gated copy of pitches
+*/
+func GateShortVoicing(pitches []float64, minFrames int) []float64 {
+	out := make([]float64, len(pitches))
+	copy(out, pitches)
+	for i := 0; i < len(out); {
+		if out[i] <= 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < len(out) && out[j] > 0 {
+			j++
+		}
+		if j-i < minFrames {
+			clear(out[i:j])
+		}
+		i = j
+	}
+	return out
+}
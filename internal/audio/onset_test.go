@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestVoiceOnsetTrackerGatesIsolatedFrame(t *testing.T) {
+	tr := NewVoiceOnsetTracker()
+	// 20ms buffers: silence, one 440 Hz blip, silence.
+	for i, p := range []float64{0, 0, 0, 440, 0, 0, 0} {
+		if got := tr.Track(p, float64(20*(i+1))); got != 0 {
+			t.Errorf("buffer %d (%v Hz) passed as %v, want it gated", i, p, got)
+		}
+	}
+}
+
+func TestVoiceOnsetTrackerOpensAfterHold(t *testing.T) {
+	tr := NewVoiceOnsetTracker()
+	tr.Track(0, 20)
+	var opened float64
+	for ms := 40.0; ms <= 200; ms += 20 {
+		// A 3-semitone jump at 60ms restarts the run before it opens.
+		p := 220.0
+		if ms >= 60 {
+			p = 262
+		}
+		if tr.Track(p, ms) != 0 && opened == 0 {
+			opened = ms
+		}
+	}
+	// The 262 Hz run starts at the start of its first buffer (40ms) and
+	// opens once it has held config.MinVoicedDurationMs.
+	if want := 40 + config.MinVoicedDurationMs; opened != want {
+		t.Errorf("opened at %vms, want %vms", opened, want)
+	}
+
+	// Once open, a sung interval passes straight away.
+	if got := tr.Track(392, 220); got != 392 {
+		t.Errorf("interval after the onset = %v, want 392", got)
+	}
+}
+
+func TestGateShortVoicing(t *testing.T) {
+	in := []float64{0, 220, 0, 0, 220, 220, 220, 0, 330, 330, 330, 330, 0}
+	want := []float64{0, 0, 0, 0, 0, 0, 0, 0, 330, 330, 330, 330, 0}
+	got := GateShortVoicing(in, 4)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("GateShortVoicing = %v, want %v", got, want)
+		}
+	}
+	if in[1] != 220 {
+		t.Error("GateShortVoicing changed its input")
+	}
+}
+
+func TestReanalyzeWindowKeepsRunsAcrossEdges(t *testing.T) {
+	// 3 seconds: A3 from 0.5s to 2.5s, silence around it.
+	mono := make([]float32, 3*config.SampleRate)
+	copy(mono[config.SampleRate/2:], sine(2*config.SampleRate, 220, 0.5))
+	pcm := stereoPCM(mono)
+	params := DefaultAnalysisParams()
+	full := analyzePitch(pcm, ModeSinging, params, nil)
+
+	// Each window cuts a few frames (fewer than the onset hold) off one end
+	// of the note; unchanged parameters must reproduce the full analysis.
+	for _, w := range [][2]float64{{0, 0.55}, {2.45, 3}} {
+		pitch := append([]float64(nil), full...)
+		ReanalyzeWindow(pcm, ModeSinging, params, pitch, w[0], w[1])
+		for i := range pitch {
+			if math.Abs(pitch[i]-full[i]) > 1e-9 {
+				t.Errorf("window %.2f-%.2fs: frame %d = %.1f Hz, full analysis %.1f Hz", w[0], w[1], i, pitch[i], full[i])
+				break
+			}
+		}
+	}
+}
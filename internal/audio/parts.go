@@ -0,0 +1,43 @@
+package audio
+
+/*
+AnalyzeMultiChannel extracts one pitch contour per stereo channel.
+
+Input:
+  - pcmBytes: []byte - Raw PCM audio data (16-bit stereo)
+  - mode: Mode - Playback mode, as for analyzePitch
+  - numChannels: int - Parts to extract: 1 analyzes the L+R mix, 2 (or more,
+    capped at the two channels stereo PCM has) the left and right channel
+
+Called by:
+  - LoadAndAnalyzeSong when LoadOptions.Parts > 1
+
+Task:
+  - Follow songs with two vocal melodies (e.g. a high and low part) that the
+    separator leaves on different sides of the stereo vocals
+
+Logic:
+ 1. Pick the channels: ChannelMix for one part, ChannelLeft and ChannelRight for two
+ 2. Run analyzePitch on each with the default parameters for that channel
+    (each channel gets its own silence threshold, so a quiet part is kept)
+
+Output:
+  - [][]float64: One pitch array at 10ms intervals per part (nil if numChannels < 1)
+*/
+func AnalyzeMultiChannel(pcmBytes []byte, mode Mode, numChannels int) [][]float64 {
+	if numChannels < 1 {
+		return nil
+	}
+	channels := []Channel{ChannelMix}
+	if numChannels > 1 {
+		channels = []Channel{ChannelLeft, ChannelRight}
+	}
+
+	parts := make([][]float64, len(channels))
+	for i, ch := range channels {
+		params := DefaultAnalysisParams()
+		params.Channel = ch
+		parts[i] = analyzePitch(pcmBytes, mode, params, nil)
+	}
+	return parts
+}
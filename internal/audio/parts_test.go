@@ -0,0 +1,106 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// writeStereoWAV writes seconds of a 16-bit stereo WAV with a sine of
+// leftHz on the left channel and rightHz on the right. The tones sound in
+// the second half of every second so silence calibration finds a floor.
+func writeStereoWAV(t *testing.T, path string, leftHz, rightHz, seconds float64) {
+	t.Helper()
+	frames := int(seconds * config.SampleRate)
+	dataSize := 4 * frames
+	buf := make([]byte, 44+dataSize)
+	copy(buf[0:], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:], uint32(36+dataSize))
+	copy(buf[8:], "WAVE")
+	copy(buf[12:], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:], 16)
+	binary.LittleEndian.PutUint16(buf[20:], 1)
+	binary.LittleEndian.PutUint16(buf[22:], 2)
+	binary.LittleEndian.PutUint32(buf[24:], config.SampleRate)
+	binary.LittleEndian.PutUint32(buf[28:], config.SampleRate*4)
+	binary.LittleEndian.PutUint16(buf[32:], 4)
+	binary.LittleEndian.PutUint16(buf[34:], 16)
+	copy(buf[36:], "data")
+	binary.LittleEndian.PutUint32(buf[40:], uint32(dataSize))
+	for i := 0; i < frames; i++ {
+		tm := float64(i) / config.SampleRate
+		if math.Mod(tm, 1) < 0.5 {
+			continue
+		}
+		l := int16(10000 * math.Sin(2*math.Pi*leftHz*tm))
+		r := int16(10000 * math.Sin(2*math.Pi*rightHz*tm))
+		binary.LittleEndian.PutUint16(buf[44+4*i:], uint16(l))
+		binary.LittleEndian.PutUint16(buf[46+4*i:], uint16(r))
+	}
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// voicedMedian is the median of the non-zero frames of a pitch array.
+func voicedMedian(pitches []float64) float64 {
+	var voiced []float64
+	for _, p := range pitches {
+		if p > 0 {
+			voiced = append(voiced, p)
+		}
+	}
+	if len(voiced) == 0 {
+		return 0
+	}
+	sort.Float64s(voiced)
+	return voiced[len(voiced)/2]
+}
+
+func TestAnalyzeMultiChannelSeparatesParts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "duet.wav")
+	writeStereoWAV(t, path, 220, 330, 3)
+	pcm, err := decodeAudioFile(path)
+	if err != nil {
+		t.Fatalf("decodeAudioFile: %v", err)
+	}
+
+	parts := AnalyzeMultiChannel(pcm, ModeSinging, 2)
+	if len(parts) != 2 {
+		t.Fatalf("AnalyzeMultiChannel returned %d parts, want 2", len(parts))
+	}
+	for i, want := range []float64{220, 330} {
+		got := voicedMedian(parts[i])
+		if got == 0 || math.Abs(centsOff(got, want)) > 20 {
+			t.Errorf("part %d median pitch = %.2f Hz, want %v Hz", i, got, want)
+		}
+	}
+	if len(parts[0]) != len(parts[1]) {
+		t.Errorf("parts have %d and %d frames, want the same length", len(parts[0]), len(parts[1]))
+	}
+}
+
+func TestAnalyzeMultiChannelSinglePart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "solo.wav")
+	writeStereoWAV(t, path, 440, 440, 2)
+	pcm, err := decodeAudioFile(path)
+	if err != nil {
+		t.Fatalf("decodeAudioFile: %v", err)
+	}
+
+	parts := AnalyzeMultiChannel(pcm, ModeSinging, 1)
+	if len(parts) != 1 {
+		t.Fatalf("AnalyzeMultiChannel returned %d parts, want 1", len(parts))
+	}
+	if got := voicedMedian(parts[0]); got == 0 || math.Abs(centsOff(got, 440)) > 20 {
+		t.Errorf("median pitch = %.2f Hz, want 440 Hz", got)
+	}
+	if parts := AnalyzeMultiChannel(pcm, ModeSinging, 0); parts != nil {
+		t.Errorf("AnalyzeMultiChannel with 0 channels = %v, want nil", parts)
+	}
+}
@@ -0,0 +1,266 @@
+package audio
+
+import (
+	"math"
+
+	"singAssist/internal/config"
+)
+
+// Phoneme is a broad class of the sound being sung ("" for silence).
+type Phoneme string
+
+const (
+	PhonemeNone      Phoneme = ""
+	PhonemeA         Phoneme = "a"
+	PhonemeE         Phoneme = "e"
+	PhonemeI         Phoneme = "i"
+	PhonemeO         Phoneme = "o"
+	PhonemeU         Phoneme = "u"
+	PhonemeConsonant Phoneme = "consonant"
+)
+
+// vowelFormants are typical (F1, F2) pairs in Hz of the sung vowels.
+var vowelFormants = []struct {
+	vowel  Phoneme
+	f1, f2 float64
+}{
+	{PhonemeA, 750, 1250},
+	{PhonemeE, 450, 1900},
+	{PhonemeI, 300, 2300},
+	{PhonemeO, 450, 850},
+	{PhonemeU, 320, 700},
+}
+
+/*
+PhonemeDetector labels the mic buffer with a vowel (or consonant) class.
+
+Fields:
+  - SampleRate: Sample rate of the buffers passed to Detect
+  - Order: LPC order used by EstimateFormants (config.FormantLPCOrder)
+*/
+type PhonemeDetector struct {
+	SampleRate int
+	Order      int
+}
+
+/*
+NewPhonemeDetector creates a detector for mic buffers.
+
+Input:
+  - sampleRate: int - Mic sample rate (config.SampleRate)
+
+Called by:
+  - App.startGame for vocal modes
+
+Task:
+  - Set up vowel feedback with the default LPC order
+
+Logic:
+ 1. Keep sampleRate and config.FormantLPCOrder
+
+Output:
+  - *PhonemeDetector: Ready-to-use detector
+*/
+func NewPhonemeDetector(sampleRate int) *PhonemeDetector {
+	return &PhonemeDetector{SampleRate: sampleRate, Order: config.FormantLPCOrder}
+}
+
+/*
+Detect classifies one mic buffer.
+
+Input:
+  - samples: []float32 - Mic buffer
+  - pitch: float64 - Pitch detected in the same buffer (0 = gated or unpitched)
+
+Called by:
+  - App.micLoop after DetectPitchFromMic
+
+Task:
+  - Tell the singer which vowel they are actually producing
+
+Logic:
+ 1. Quiet buffers (below config.PhonemeMinEnergy): PhonemeNone
+ 2. Unpitched but loud: PhonemeConsonant (fricatives, plosives, breath)
+ 3. Otherwise estimate F1/F2 with EstimateFormants and ClassifyFormants
+
+Output:
+  - Phoneme: Detected class
+*/
+func (d *PhonemeDetector) Detect(samples []float32, pitch float64) Phoneme {
+	if CalculateEnergy(samples) < config.PhonemeMinEnergy {
+		return PhonemeNone
+	}
+	if pitch <= 0 {
+		return PhonemeConsonant
+	}
+	return ClassifyFormants(EstimateFormants(samples, d.SampleRate, d.Order))
+}
+
+/*
+ClassifyFormants maps a formant pair to the nearest vowel.
+
+Input:
+  - f1, f2: float64 - First and second formant in Hz
+
+Called by:
+  - PhonemeDetector.Detect
+
+Task:
+  - Turn formants into a label the singer understands
+
+Logic:
+ 1. No F1 or F2: PhonemeNone (no clear vowel structure, e.g. merged formants)
+ 2. Distance to each vowelFormants entry in log-frequency (octaves), so an
+    error of 100 Hz weighs more for F1 than for F2
+ 3. Return the closest vowel
+
+Output:
+  - Phoneme: PhonemeA, PhonemeE, PhonemeI, PhonemeO, PhonemeU, or PhonemeNone
+*/
+func ClassifyFormants(f1, f2 float64) Phoneme {
+	if f1 <= 0 || f2 <= 0 {
+		return PhonemeNone
+	}
+	best, bestDist := PhonemeNone, math.Inf(1)
+	for _, v := range vowelFormants {
+		d1 := math.Log2(f1 / v.f1)
+		d2 := math.Log2(f2 / v.f2)
+		if dist := d1*d1 + d2*d2; dist < bestDist {
+			best, bestDist = v.vowel, dist
+		}
+	}
+	return best
+}
+
+/*
+EstimateFormants finds the first two formants of a buffer from its LPC envelope.
+
+Input:
+  - samples: []float32 - Audio buffer (mono)
+  - sampleRate: int - Sample rate of samples
+  - order: int - LPC order (10..14 at the ~11kHz analysis rate)
+
+Called by:
+  - PhonemeDetector.Detect
+
+Task:
+  - Estimate the vocal tract resonances independently of the sung pitch
+
+Logic:
+ 1. Decimate to about config.FormantSampleRate by averaging groups of samples
+    (formants of interest are below 4kHz; a 44.1kHz LPC would need order ~46)
+ 2. Pre-emphasis (y[n] = x[n] - 0.63·x[n-1]) and a Hann window
+ 3. Autocorrelation r[0..order], then Levinson-Durbin for the predictor A(z)
+ 4. Evaluate the envelope 1/|A(e^jw)| every 10Hz from 90Hz to 4kHz
+ 5. F1 and F2 are the first two envelope peaks at or above 200Hz
+
+Output:
+  - float64, float64: F1 and F2 in Hz (0 where no peak was found)
+*/
+func EstimateFormants(samples []float32, sampleRate int, order int) (float64, float64) {
+	factor := max(1, sampleRate/config.FormantSampleRate)
+	n := len(samples) / factor
+	if n <= order+1 || order <= 0 {
+		return 0, 0
+	}
+	rate := float64(sampleRate) / float64(factor)
+
+	x := make([]float64, n)
+	for i := range x {
+		var sum float64
+		for _, s := range samples[i*factor : (i+1)*factor] {
+			sum += float64(s)
+		}
+		x[i] = sum / float64(factor)
+	}
+	for i := n - 1; i > 0; i-- {
+		x[i] -= 0.63 * x[i-1]
+	}
+	for i, w := range hannWindow(n) {
+		x[i] *= w
+	}
+
+	r := make([]float64, order+1)
+	for lag := range r {
+		for i := lag; i < n; i++ {
+			r[lag] += x[i] * x[i-lag]
+		}
+	}
+	a := levinsonDurbin(r, order)
+	if a == nil {
+		return 0, 0
+	}
+
+	var peaks []float64
+	prev, prevPrev := 0.0, 0.0
+	maxF := math.Min(4000, rate/2)
+	for f := 90.0; f <= maxF; f += 10 {
+		w := 2 * math.Pi * f / rate
+		re, im := 0.0, 0.0
+		for k, c := range a {
+			re += c * math.Cos(w*float64(k))
+			im -= c * math.Sin(w*float64(k))
+		}
+		env := 1 / math.Hypot(re, im)
+		if prev > prevPrev && prev > env && f-10 >= 200 {
+			peaks = append(peaks, f-10)
+			if len(peaks) == 2 {
+				return peaks[0], peaks[1]
+			}
+		}
+		prevPrev, prev = prev, env
+	}
+	if len(peaks) == 1 {
+		return peaks[0], 0
+	}
+	return 0, 0
+}
+
+/*
+levinsonDurbin solves the LPC normal equations for an autocorrelation sequence.
+
+Input:
+  - r: []float64 - Autocorrelation r[0..order]
+  - order: int - Predictor order
+
+Called by:
+  - EstimateFormants
+
+Task:
+  - Fit the all-pole model whose peaks are the formants
+
+Logic:
+ 1. a = [1], error = r[0]; give up on a silent buffer (r[0] == 0)
+ 2. For each order i: reflection k = -(r[i] + Σ a[j]·r[i-j]) / error,
+    update a[j] += k·a[i-j], set a[i] = k, error *= 1 - k²
+ 3. Stop early if the error stops being positive (numerically singular)
+
+Output:
+  - []float64: Predictor coefficients a[0..order] with a[0] = 1, nil if r[0] is 0
+*/
+func levinsonDurbin(r []float64, order int) []float64 {
+	if r[0] <= 0 {
+		return nil
+	}
+	a := make([]float64, order+1)
+	a[0] = 1
+	prev := make([]float64, order+1)
+	e := r[0]
+	for i := 1; i <= order; i++ {
+		acc := r[i]
+		for j := 1; j < i; j++ {
+			acc += a[j] * r[i-j]
+		}
+		k := -acc / e
+		copy(prev, a)
+		for j := 1; j < i; j++ {
+			a[j] = prev[j] + k*prev[i-j]
+		}
+		a[i] = k
+		e *= 1 - k*k
+		if e <= 0 {
+			break
+		}
+	}
+	return a
+}
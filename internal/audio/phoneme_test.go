@@ -0,0 +1,79 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// synthVowel renders one mic buffer of a pulse train at f0 through two
+// resonators at the formants f1 and f2 (a crude source-filter voice).
+func synthVowel(f0, f1, f2 float64) []float32 {
+	x := make([]float64, config.BufferSize)
+	period := config.SampleRate / f0
+	for t := 0.0; t < float64(len(x)); t += period {
+		x[int(t)] = 1
+	}
+	for _, f := range []float64{f1, f2} {
+		r := math.Exp(-math.Pi * 80 / config.SampleRate)
+		c1, c2 := 2*r*math.Cos(2*math.Pi*f/config.SampleRate), -r*r
+		var y1, y2 float64
+		for i, v := range x {
+			y := v + c1*y1 + c2*y2
+			x[i], y1, y2 = y, y, y1
+		}
+	}
+	peak := 0.0
+	for _, v := range x {
+		peak = max(peak, math.Abs(v))
+	}
+	out := make([]float32, len(x))
+	for i, v := range x {
+		out[i] = float32(0.5 * v / peak)
+	}
+	return out
+}
+
+func TestEstimateFormantsOpenVowel(t *testing.T) {
+	f1, f2 := EstimateFormants(synthVowel(150, 800, 1200), config.SampleRate, config.FormantLPCOrder)
+	if math.Abs(f1-800) > 100 || math.Abs(f2-1200) > 120 {
+		t.Errorf("formants = %.0f, %.0f Hz; want about 800 and 1200", f1, f2)
+	}
+	if got := ClassifyFormants(f1, f2); got != PhonemeA {
+		t.Errorf("800/1200 Hz formants classified as %q, want %q", got, PhonemeA)
+	}
+}
+
+func TestClassifyFormants(t *testing.T) {
+	tests := []struct {
+		f1, f2 float64
+		want   Phoneme
+	}{
+		{800, 1200, PhonemeA},
+		{430, 2000, PhonemeE},
+		{280, 2400, PhonemeI},
+		{480, 880, PhonemeO},
+		{310, 690, PhonemeU},
+		{0, 1200, PhonemeNone},
+		{800, 0, PhonemeNone},
+	}
+	for _, tt := range tests {
+		if got := ClassifyFormants(tt.f1, tt.f2); got != tt.want {
+			t.Errorf("ClassifyFormants(%v, %v) = %q, want %q", tt.f1, tt.f2, got, tt.want)
+		}
+	}
+}
+
+func TestPhonemeDetectorSilenceAndConsonant(t *testing.T) {
+	d := NewPhonemeDetector(config.SampleRate)
+	if got := d.Detect(make([]float32, config.BufferSize), 0); got != PhonemeNone {
+		t.Errorf("silence detected as %q", got)
+	}
+	if got := d.Detect(synthVowel(150, 800, 1200), 0); got != PhonemeConsonant {
+		t.Errorf("loud unpitched buffer detected as %q, want %q", got, PhonemeConsonant)
+	}
+	if got := d.Detect(synthVowel(150, 800, 1200), 150); got != PhonemeA {
+		t.Errorf("sung /a/ detected as %q", got)
+	}
+}
@@ -0,0 +1,242 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+
+	"singAssist/internal/config"
+)
+
+// pitchAlgorithm is the detector DetectPitchWithConfidence dispatches to,
+// chosen once at startup from SINGASSIST_PITCH_ALGO.
+var pitchAlgorithm = config.GetPitchAlgorithm()
+
+// hpsHarmonics is how many downsampled spectra the harmonic product
+// spectrum multiplies (the spectrum itself and factors 2, 3 and 4);
+// hpsPadding is the zero-padding factor, which is also how many bins on
+// either side of a peak hold the core of the Hann window's main lobe.
+// hpsMinFundamental is the share of the strongest in-range peak a bin needs
+// to count as a fundamental (above the window's -31 dB sidelobes, so a pure
+// tone's leakage is never mistaken for a subharmonic).
+// acfPeakRatio is how close to the strongest autocorrelation the first
+// period must come to be preferred over its multiples.
+const (
+	hpsHarmonics      = 4
+	hpsPadding        = 4
+	hpsMinFundamental = 0.05
+	acfPeakRatio      = 0.9
+)
+
+/*
+detectPitchAutocorrelation estimates fundamental frequency by autocorrelation.
+
+Input:
+  - samples: []float32 - Audio samples normalized to [-1, 1]
+  - minFreq, maxFreq: float64 - Frequency range to detect (Hz)
+
+Called by:
+  - DetectPitchWithConfidence with config.AlgoAutoCorrelation
+
+Task:
+  - Find the dominant periodic component and rate its reliability (the
+    detector YIN replaced, kept for comparison)
+
+Logic:
+ 1. Convert frequency bounds to sample periods (period = sampleRate / freq)
+ 2. For each candidate period τ: mean of sample[i] * sample[i+τ] over the
+    overlap (every other sample for a 2x speedup), so longer periods are
+    not penalised for overlapping less
+ 3. Take the shortest run of periods reaching acfPeakRatio of the maximum
+    and the best period within it (multiples of the period correlate just as
+    well; picking the first avoids octave-down errors)
+ 4. Confidence = that period's correlation normalized by the energy of both
+    overlapping segments (1.0 = perfectly periodic, ~0 = noise/percussion)
+
+Output:
+  - float64: Detected frequency in Hz, or 0 if no pitch found
+  - float64: Confidence in [0, 1]
+*/
+func detectPitchAutocorrelation(samples []float32, minFreq, maxFreq float64) (float64, float64) {
+	n := len(samples)
+	if n == 0 {
+		return 0, 0
+	}
+	minPeriod := max(1, int(float64(config.SampleRate)/maxFreq))
+	maxPeriod := min(n-1, int(float64(config.SampleRate)/minFreq))
+
+	if minPeriod >= maxPeriod {
+		return 0, 0
+	}
+
+	corr := make([]float64, maxPeriod+1)
+	peak := 0.0
+	for tau := minPeriod; tau <= maxPeriod; tau++ {
+		cross := 0.0
+		for i := 0; i < n-tau; i += 2 {
+			cross += float64(samples[i]) * float64(samples[i+tau])
+		}
+		corr[tau] = cross / float64(n-tau)
+		peak = max(peak, corr[tau])
+	}
+	if peak <= 0 {
+		return 0, 0
+	}
+
+	bestPeriod := minPeriod
+	for corr[bestPeriod] < acfPeakRatio*peak {
+		bestPeriod++
+	}
+	for tau := bestPeriod + 1; tau <= maxPeriod && corr[tau] >= acfPeakRatio*peak; tau++ {
+		if corr[tau] > corr[bestPeriod] {
+			bestPeriod = tau
+		}
+	}
+
+	maxVal := 0.0
+	for i := 0; i < n-bestPeriod; i += 2 {
+		maxVal += float64(samples[i]) * float64(samples[i+bestPeriod])
+	}
+
+	e1, e2 := 0.0, 0.0
+	for i := 0; i < n-bestPeriod; i += 2 {
+		a, b := float64(samples[i]), float64(samples[i+bestPeriod])
+		e1 += a * a
+		e2 += b * b
+	}
+	confidence := 0.0
+	if e1 > 0 && e2 > 0 {
+		confidence = math.Min(1, maxVal/math.Sqrt(e1*e2))
+	}
+	return float64(config.SampleRate) / float64(bestPeriod), confidence
+}
+
+/*
+DetectPitchFFT estimates fundamental frequency with the harmonic product spectrum.
+
+Input:
+  - samples: []float32 - Audio samples normalized to [-1, 1]
+  - minFreq, maxFreq: float64 - Frequency range to detect (Hz)
+
+Called by:
+  - Callers comparing detectors (DetectPitchWithConfidence uses detectPitchHPS
+    with config.AlgoFFTHPS)
+
+Task:
+  - Frequency-domain alternative to YIN
+
+Logic:
+ 1. Call detectPitchHPS and drop the confidence
+
+Output:
+  - float64: Detected frequency in Hz, or 0 if no pitch found
+*/
+func DetectPitchFFT(samples []float32, minFreq, maxFreq float64) float64 {
+	freq, _ := detectPitchHPS(samples, minFreq, maxFreq)
+	return freq
+}
+
+/*
+detectPitchHPS finds the fundamental as the peak of the harmonic product spectrum.
+
+Input:
+  - samples: []float32 - Audio samples normalized to [-1, 1]
+  - minFreq, maxFreq: float64 - Frequency range to detect (Hz)
+
+Called by:
+  - DetectPitchFFT, DetectPitchWithConfidence with config.AlgoFFTHPS
+
+Task:
+  - Find the frequency all the voice's harmonics agree on, even when the
+    fundamental itself is weak
+
+Logic:
+ 1. Hann-window the samples and zero-pad them to hpsPadding x the next power of two
+    (finer bins for low voices); FFT and take the magnitude spectrum
+ 2. For each bin k in minFreq..maxFreq that is a spectral peak of at least
+    hpsMinFundamental of the strongest one: sum of log magnitudes at k, 2k,
+    3k and 4k (the product of the spectrum downsampled by 1..hpsHarmonics,
+    in logs so it cannot overflow)
+ 3. Bin with the largest sum, refined by parabolic interpolation of the log
+    magnitude spectrum around it
+ 4. Confidence = share of the spectrum's energy within hpsPadding bins (the
+    main lobe) of the peak's first hpsHarmonics harmonics
+
+Output:
+  - float64: Detected frequency in Hz, or 0 if no pitch found
+  - float64: Confidence in [0, 1]
+*/
+func detectPitchHPS(samples []float32, minFreq, maxFreq float64) (float64, float64) {
+	if len(samples) == 0 || maxFreq <= minFreq {
+		return 0, 0
+	}
+	n := 1
+	for n < len(samples) {
+		n <<= 1
+	}
+	n *= hpsPadding
+
+	win := hannWindow(len(samples))
+	buf := make([]complex128, n)
+	for i, s := range samples {
+		buf[i] = complex(float64(s)*win[i], 0)
+	}
+	fft(buf, false)
+
+	mags := make([]float64, n/2)
+	total := 0.0
+	for i := range mags {
+		mags[i] = cmplx.Abs(buf[i])
+		total += mags[i] * mags[i]
+	}
+	if total == 0 {
+		return 0, 0
+	}
+
+	binHz := float64(config.SampleRate) / float64(n)
+	lo := max(1, int(minFreq/binHz))
+	hi := min((len(mags)-1)/hpsHarmonics, int(maxFreq/binHz)+1)
+	if lo >= hi {
+		return 0, 0
+	}
+	const floor = 1e-12
+	hps := func(k int) float64 {
+		sum := 0.0
+		for h := 1; h <= hpsHarmonics; h++ {
+			sum += math.Log(mags[h*k] + floor)
+		}
+		return sum
+	}
+
+	peak := 0.0
+	for k := lo; k <= hi; k++ {
+		peak = max(peak, mags[k])
+	}
+	best, bestVal := 0, math.Inf(-1)
+	for k := lo; k <= hi; k++ {
+		if mags[k] < hpsMinFundamental*peak || mags[k] < mags[k-1] || mags[k] < mags[k+1] {
+			continue
+		}
+		if v := hps(k); v > bestVal {
+			best, bestVal = k, v
+		}
+	}
+	if best == 0 {
+		return 0, 0
+	}
+
+	bin := float64(best)
+	if best > lo && best < hi {
+		a, b, c := math.Log(mags[best-1]+floor), math.Log(mags[best]+floor), math.Log(mags[best+1]+floor)
+		if d := a - 2*b + c; d < 0 {
+			bin += 0.5 * (a - c) / d
+		}
+	}
+
+	harmonic := 0.0
+	for h := 1; h <= hpsHarmonics; h++ {
+		for k := max(0, h*best-hpsPadding); k <= h*best+hpsPadding && k < len(mags); k++ {
+			harmonic += mags[k] * mags[k]
+		}
+	}
+	return bin * binHz, math.Min(1, harmonic/total)
+}
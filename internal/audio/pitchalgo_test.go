@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// synthTone returns 2048 samples of freq with the given harmonic amplitudes
+// (amps[0] is the fundamental).
+func synthTone(freq float64, amps []float64) []float32 {
+	samples := make([]float32, config.BufferSize)
+	for i := range samples {
+		t := float64(i) / config.SampleRate
+		v := 0.0
+		for h, a := range amps {
+			v += a * math.Sin(2*math.Pi*freq*float64(h+1)*t+float64(h))
+		}
+		samples[i] = float32(0.3 * v)
+	}
+	return samples
+}
+
+// centsOff is the distance from want to got in cents.
+func centsOff(got, want float64) float64 {
+	return 1200 * math.Log2(got/want)
+}
+
+func TestPitchAlgorithmsAccuracy(t *testing.T) {
+	algos := []struct {
+		name   string
+		detect func([]float32, float64, float64) (float64, float64)
+	}{
+		{"yin", detectPitchYIN},
+		{"autocorrelation", detectPitchAutocorrelation},
+		{"fft", detectPitchHPS},
+	}
+	signals := []struct {
+		name string
+		amps []float64
+	}{
+		{"sine", []float64{1}},
+		{"vowel", []float64{1, 0.5, 0.33, 0.25, 0.2}},
+		{"weak fundamental", []float64{0.15, 1, 0.8, 0.5, 0.3}},
+	}
+	const toleranceCents = 15
+
+	for _, algo := range algos {
+		for _, sig := range signals {
+			for _, freq := range []float64{110, 220, 440, 880} {
+				got, _ := algo.detect(synthTone(freq, sig.amps), 85, 1100)
+				if got == 0 || math.Abs(centsOff(got, freq)) > toleranceCents {
+					t.Errorf("%s on %s at %v Hz = %.2f Hz, want within %d cents", algo.name, sig.name, freq, got, toleranceCents)
+				}
+			}
+		}
+	}
+}
+
+func TestPitchAlgorithmsSilence(t *testing.T) {
+	silence := make([]float32, config.BufferSize)
+	for _, detect := range []func([]float32, float64, float64) (float64, float64){detectPitchYIN, detectPitchAutocorrelation, detectPitchHPS} {
+		if got, _ := detect(silence, 85, 1100); got != 0 {
+			t.Errorf("silence detected as %.2f Hz, want 0", got)
+		}
+	}
+}
+
+func TestPitchCachePathPerAlgorithm(t *testing.T) {
+	cacheFile := filepath.Join("songs", "Song", "pitch_cache.bin")
+	tests := []struct {
+		algo config.PitchAlgorithm
+		want string
+	}{
+		{config.AlgoYIN, "pitch_cache_vocals.bin"},
+		{config.AlgoAutoCorrelation, "pitch_cache_vocals_autocorrelation.bin"},
+		{config.AlgoFFTHPS, "pitch_cache_vocals_fft.bin"},
+	}
+	for _, tt := range tests {
+		got := pitchCachePath(cacheFile, ModeSinging, tt.algo)
+		if want := filepath.Join("songs", "Song", tt.want); got != want {
+			t.Errorf("pitchCachePath(%s) = %q, want %q", tt.algo, got, want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+
+	"singAssist/internal/config"
+)
+
+/*
+RangeTestSession walks the user through finding their vocal range.
+
+Fields:
+  - note: MIDI note being tested
+  - descending: The climb is over; notes now step down
+  - done: The test has finished
+  - low, high: Lowest and highest matched MIDI note (0 while none matched)
+*/
+type RangeTestSession struct {
+	note       int
+	descending bool
+	done       bool
+	low, high  int
+}
+
+/*
+NewRangeTestSession creates a range test at its first note.
+
+Input:
+  - None
+
+Called by:
+  - App.startGame for ModeRangeTest
+
+Task:
+  - Start a fresh test
+
+Logic:
+ 1. First note is config.RangeTestStartMidi (C3), climbing
+
+Output:
+  - *RangeTestSession: Test ready for NextNote
+*/
+func NewRangeTestSession() *RangeTestSession {
+	return &RangeTestSession{note: config.RangeTestStartMidi}
+}
+
+/*
+NextNote returns the note the user should sing next.
+
+Input:
+  - None
+
+Called by:
+  - App.tickRangeTest to tune the tone and to display the target
+
+Task:
+  - Tell the caller what to play
+
+Logic:
+ 1. 440 × 2^((note-69)/12), or 0 once the test is done
+
+Output:
+  - float64: Frequency in Hz (0 when done)
+*/
+func (s *RangeTestSession) NextNote() float64 {
+	if s.done {
+		return 0
+	}
+	return 440 * math.Pow(2, float64(s.note-69)/12)
+}
+
+/*
+RecordResult judges what the user sang for the current note and moves on.
+
+Input:
+  - pitch: float64 - Pitch sung back, in Hz (<= 10 for none)
+
+Called by:
+  - App.tickRangeTest at the end of each listening window
+
+Task:
+  - Climb until the voice gives out, then step down from the top
+
+Logic:
+ 1. Hit when the pitch is within config.DefaultHitTolerance semitones of the
+    note (in the same octave); hits widen low/high
+ 2. Climbing: move a semitone up (until RangeTestMaxMidi) after a hit, and
+    after a miss while nothing was matched yet (a low voice may only come in
+    above C3); a miss after the first hit or the top ends the climb, and the
+    descent starts one semitone below the highest matched note (below the
+    start note if none matched)
+ 3. Descending: a hit moves a semitone down; a miss or RangeTestMinMidi ends the test
+
+Output:
+  - bool: true if the note was hit
+*/
+func (s *RangeTestSession) RecordResult(pitch float64) bool {
+	if s.done {
+		return false
+	}
+	hit := pitch > 10 && math.Abs(69+12*math.Log2(pitch/440)-float64(s.note)) < config.DefaultHitTolerance
+	if hit {
+		if s.low == 0 || s.note < s.low {
+			s.low = s.note
+		}
+		s.high = max(s.high, s.note)
+	}
+
+	if !s.descending {
+		if (hit || s.high == 0) && s.note < config.RangeTestMaxMidi {
+			s.note++
+			return hit
+		}
+		s.descending = true
+		s.note = config.RangeTestStartMidi - 1
+		if s.high > 0 {
+			s.note = s.high - 1
+		}
+		return hit
+	}
+
+	if !hit || s.note <= config.RangeTestMinMidi {
+		s.done = true
+		return hit
+	}
+	s.note--
+	return hit
+}
+
+/*
+Done reports whether the test has finished.
+
+Input:
+  - None
+
+Called by:
+  - App.tickRangeTest, App.drawRangeTest
+
+Task:
+  - Tell the caller when to show the result
+
+Logic:
+ 1. Return done
+
+Output:
+  - bool: true once the descent has ended
+*/
+func (s *RangeTestSession) Done() bool {
+	return s.done
+}
+
+/*
+Summary returns the lowest and highest matched notes by name.
+
+Input:
+  - None
+
+Called by:
+  - App.drawRangeTest, App.finishRangeTest
+
+Task:
+  - Show "Your range: E3–G5"
+
+Logic:
+ 1. Name each MIDI note as <pitch class><octave> (MIDI 60 = C4)
+ 2. "" for both while nothing was matched
+
+Output:
+  - lowNote, highNote: string - e.g. "E3", "G5"
+*/
+func (s *RangeTestSession) Summary() (lowNote, highNote string) {
+	if s.high == 0 {
+		return "", ""
+	}
+	name := func(midi int) string {
+		return fmt.Sprintf("%s%d", keyRoots[midi%12], midi/12-1)
+	}
+	return name(s.low), name(s.high)
+}
+
+/*
+Range returns the matched range in Hz.
+
+Input:
+  - None
+
+Called by:
+  - App.finishRangeTest to save config.VocalRange
+
+Task:
+  - Frequencies for narrowing mic pitch detection
+
+Logic:
+ 1. Convert low and high to Hz (0, 0 while nothing was matched)
+
+Output:
+  - lowHz, highHz: float64 - Range edges
+*/
+func (s *RangeTestSession) Range() (lowHz, highHz float64) {
+	if s.high == 0 {
+		return 0, 0
+	}
+	hz := func(midi int) float64 { return 440 * math.Pow(2, float64(midi-69)/12) }
+	return hz(s.low), hz(s.high)
+}
@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+// midiHz is the frequency of a MIDI note.
+func midiHz(midi int) float64 {
+	return 440 * math.Pow(2, float64(midi-69)/12)
+}
+
+// singRange answers every note of a range test with the note itself when it
+// lies in low..high (MIDI) and with silence otherwise.
+func singRange(t *testing.T, s *RangeTestSession, low, high int) {
+	t.Helper()
+	for i := 0; !s.Done(); i++ {
+		if i > 200 {
+			t.Fatal("range test did not finish")
+		}
+		note := int(math.Round(69 + 12*math.Log2(s.NextNote()/440)))
+		pitch := 0.0
+		if note >= low && note <= high {
+			pitch = midiHz(note)
+		}
+		s.RecordResult(pitch)
+	}
+}
+
+func TestRangeTestSummary(t *testing.T) {
+	tests := []struct {
+		name      string
+		low, high int
+		wantLow   string
+		wantHigh  string
+	}{
+		{"starts above C3", 52, 79, "E3", "G5"},
+		{"starts at C3", 48, 72, "C3", "C5"},
+		{"reaches below the start", 40, 64, "E2", "E4"},
+	}
+	for _, tt := range tests {
+		s := NewRangeTestSession()
+		singRange(t, s, tt.low, tt.high)
+		low, high := s.Summary()
+		if low != tt.wantLow || high != tt.wantHigh {
+			t.Errorf("%s: Summary() = %q, %q; want %q, %q", tt.name, low, high, tt.wantLow, tt.wantHigh)
+		}
+	}
+}
+
+func TestRangeTestSummaryWithoutHits(t *testing.T) {
+	s := NewRangeTestSession()
+	singRange(t, s, 0, 0)
+	if low, high := s.Summary(); low != "" || high != "" {
+		t.Errorf("Summary() = %q, %q; want empty names", low, high)
+	}
+	if lowHz, highHz := s.Range(); lowHz != 0 || highHz != 0 {
+		t.Errorf("Range() = %v, %v; want 0, 0", lowHz, highHz)
+	}
+}
+
+func TestRangeTestMissEndsClimbAfterFirstHit(t *testing.T) {
+	s := NewRangeTestSession()
+	if s.RecordResult(0) {
+		t.Fatal("silence counted as a hit")
+	}
+	if got := s.NextNote(); math.Abs(got-midiHz(49)) > 0.01 {
+		t.Fatalf("after a miss before any hit the next note is %.2f Hz, want C#3 (%.2f Hz)", got, midiHz(49))
+	}
+	if !s.RecordResult(midiHz(49)) {
+		t.Fatal("matching C#3 was not a hit")
+	}
+	s.RecordResult(0)
+	if got := s.NextNote(); math.Abs(got-midiHz(48)) > 0.01 {
+		t.Errorf("after the climb ended the next note is %.2f Hz, want C3 (%.2f Hz)", got, midiHz(48))
+	}
+}
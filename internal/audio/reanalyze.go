@@ -0,0 +1,96 @@
+package audio
+
+import (
+	"time"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+)
+
+/*
+ReanalyzeWindow recomputes song pitch for a time window in place.
+
+Input:
+  - pcmBytes: []byte - PCM that songPitch was computed from (LoadResult.PCM)
+  - mode: Mode - Playback mode used for the original analysis
+  - params: AnalysisParams - New detection parameters
+  - songPitch: []float64 - Existing pitch data (10ms frames), updated in place
+  - fromSec, toSec: float64 - Window to recompute, in seconds
+
+Called by:
+  - App.retuneAnalysis when a tuning key is pressed
+
+Task:
+  - Make parameter tweaks feel instant by only redoing the visible section
+
+Logic:
+ 1. Recalibrate the silence threshold with the new params (samples only the intro, cheap)
+ 2. Convert the window to chunk indices (3 frames per 30ms chunk), clamped to data
+ 3. Run analyzeChunk for each chunk and overwrite its 3 frames
+ 4. Drop short voiced runs (GateShortVoicing), gap-fill with applyGapFill,
+    then CorrectOctaveErrors over the window extended by the onset hold
+    (minVoicedFrames) on each side, so runs crossing its edges are measured
+    in full; only the window's frames are written back
+
+Output:
+  - None (modifies songPitch)
+*/
+func ReanalyzeWindow(pcmBytes []byte, mode Mode, params AnalysisParams, songPitch []float64, fromSec, toSec float64) {
+	startTime := time.Now()
+	stepBytes := analysisStepBytes()
+	floatBuf := make([]float32, stepBytes/4)
+	minEnergy := calibrateSilenceFromAudio(pcmBytes, stepBytes, mode, params)
+
+	firstChunk := int(fromSec * 100 / 3)
+	if firstChunk < 0 {
+		firstChunk = 0
+	}
+	lastChunk := int(toSec*100/3) + 1
+	if maxChunk := len(songPitch) / 3; lastChunk > maxChunk {
+		lastChunk = maxChunk
+	}
+	if firstChunk >= lastChunk {
+		return
+	}
+
+	for c := firstChunk; c < lastChunk; c++ {
+		off := c * stepBytes
+		if off+stepBytes > len(pcmBytes) {
+			break
+		}
+		p := analyzeChunk(pcmBytes[off:off+stepBytes], floatBuf, mode, minEnergy, params)
+		songPitch[c*3], songPitch[c*3+1], songPitch[c*3+2] = p, p, p
+	}
+
+	hold := minVoicedFrames()
+	from, to := firstChunk*3, lastChunk*3
+	ctxFrom, ctxTo := max(0, from-hold), min(len(songPitch), to+hold)
+	processed := CorrectOctaveErrors(applyGapFill(GateShortVoicing(songPitch[ctxFrom:ctxTo], hold), mode, params), config.OctaveWindowFrames)
+	copy(songPitch[from:to], processed[from-ctxFrom:to-ctxFrom])
+
+	logging.Debugf("Re-analyzed %.1fs-%.1fs in %v", fromSec, toSec, time.Since(startTime))
+}
+
+/*
+Reanalyze recomputes song pitch for the whole song.
+
+Input:
+  - pcmBytes: []byte - PCM kept from LoadResult.PCM
+  - mode: Mode - Playback mode
+  - params: AnalysisParams - Detection parameters
+
+Called by:
+  - App.reanalyzeSong in a goroutine (full re-analysis on demand)
+
+Task:
+  - Fallback when the whole contour should reflect new parameters
+
+Logic:
+ 1. Run analyzePitch (no progress reporting)
+
+Output:
+  - []float64: New pitch data at 10ms intervals
+*/
+func Reanalyze(pcmBytes []byte, mode Mode, params AnalysisParams) []float64 {
+	return analyzePitch(pcmBytes, mode, params, nil)
+}
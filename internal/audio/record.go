@@ -0,0 +1,219 @@
+package audio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"math"
+	"os"
+	"sync"
+
+	"singAssist/internal/config"
+)
+
+/*
+WavRecorder streams the mic audio of a session to a WAV file.
+
+Fields:
+  - mu: Guards everything below (micLoop adds, cleanup stops)
+  - path: WAV file being written
+  - sampleRate: Sample rate of the mic buffers
+  - file: Open WAV file (nil until the first Add)
+  - w: Buffered writer over file for the sample data
+  - written: Samples written so far (capped at config.RecordingMaxSeconds)
+  - err: First write error; later Adds are ignored and Stop returns it
+*/
+type WavRecorder struct {
+	mu         sync.Mutex
+	path       string
+	sampleRate int
+	file       *os.File
+	w          *bufio.Writer
+	written    int
+	err        error
+}
+
+/*
+NewWavRecorder creates a recorder that has not written anything yet.
+
+Input:
+  - path: string - WAV file to create on the first Add (config.RecordingPath)
+  - sampleRate: int - Sample rate of the mic buffers
+
+Called by:
+  - App.startGame unless -no-record
+
+Task:
+  - Prepare to record a practice session
+
+Logic:
+ 1. Store path and sampleRate; the file is created by the first Add so a
+    session that never starts playback leaves no file
+
+Output:
+  - *WavRecorder: Empty recorder
+*/
+func NewWavRecorder(path string, sampleRate int) *WavRecorder {
+	return &WavRecorder{path: path, sampleRate: sampleRate}
+}
+
+/*
+Add appends mic samples to the file.
+
+Input:
+  - buf: []float32 - Mono mic samples (-1..1)
+
+Called by:
+  - App.micLoop with MicHandler.Delivered during playback
+
+Task:
+  - Grow the recording without keeping it in memory
+
+Logic:
+ 1. After a write error, or once config.RecordingMaxSeconds are written, ignore buf
+ 2. First call: create the file and write a header with zero sizes
+    (patched by Stop)
+ 3. Clamp each sample to -1..1, scale it to int16 and write it little-endian
+    through the buffered writer
+
+Output:
+  - None (errors are kept for Stop)
+*/
+func (r *WavRecorder) Add(buf []float32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	buf = buf[:min(len(buf), config.RecordingMaxSeconds*r.sampleRate-r.written)]
+	if len(buf) == 0 {
+		return
+	}
+	if r.file == nil {
+		f, err := os.Create(r.path)
+		if err != nil {
+			r.err = err
+			return
+		}
+		r.file, r.w = f, bufio.NewWriter(f)
+		if _, r.err = r.w.Write(wavHeader(0, r.sampleRate)); r.err != nil {
+			return
+		}
+	}
+	var b [2]byte
+	for _, v := range buf {
+		binary.LittleEndian.PutUint16(b[:], uint16(int16(math.Max(-1, math.Min(1, float64(v)))*math.MaxInt16)))
+		if _, r.err = r.w.Write(b[:]); r.err != nil {
+			return
+		}
+	}
+	r.written += len(buf)
+}
+
+/*
+Stop finishes the recording.
+
+Input:
+  - None
+
+Called by:
+  - App.stopRecording when the session ends
+
+Task:
+  - Leave a valid WAV file for listening back
+
+Logic:
+ 1. Nothing recorded (playback never started): write nothing
+ 2. Flush the sample data, rewrite the header with the final sizes and close
+    the file; a later Stop does nothing
+
+Output:
+  - string: Path written ("" when nothing was recorded)
+  - error: First write or close error
+*/
+func (r *WavRecorder) Stop() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return "", r.err
+	}
+	err := r.err
+	if err == nil {
+		err = r.w.Flush()
+	}
+	if err == nil {
+		_, err = r.file.WriteAt(wavHeader(2*r.written, r.sampleRate), 0)
+	}
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	r.file, r.w = nil, nil
+	return r.path, err
+}
+
+/*
+WriteWAV writes mono 16-bit PCM as a RIFF/WAVE file.
+
+Input:
+  - path: string - Destination file
+  - samples: []int16 - Mono samples
+  - sampleRate: int - Samples per second
+
+Called by:
+  - The bench test fixture (sessions stream through WavRecorder instead)
+
+Task:
+  - Minimal WAV encoder every player understands
+
+Logic:
+ 1. wavHeader for 2 × len(samples) data bytes
+ 2. Samples follow little-endian
+
+Output:
+  - error: Write error
+*/
+func WriteWAV(path string, samples []int16, sampleRate int) error {
+	buf := wavHeader(2*len(samples), sampleRate)
+	for _, v := range samples {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(v))
+	}
+	return os.WriteFile(path, buf, 0644)
+}
+
+/*
+wavHeader returns the 44-byte header of a mono 16-bit PCM WAV file.
+
+Input:
+  - dataSize: int - Bytes of sample data that follow
+  - sampleRate: int - Samples per second
+
+Called by:
+  - WriteWAV, WavRecorder.Add (placeholder) and WavRecorder.Stop (final sizes)
+
+Task:
+  - Keep one copy of the RIFF layout
+
+Logic:
+ 1. "RIFF" <36 + dataSize> "WAVE", a 16-byte "fmt " chunk (PCM, 1 channel,
+    sampleRate, byte rate, block align 2, 16 bits) and "data" <dataSize>,
+    all little-endian
+
+Output:
+  - []byte: Header (with room to append the samples)
+*/
+func wavHeader(dataSize, sampleRate int) []byte {
+	buf := make([]byte, 44, 44+dataSize)
+	copy(buf[0:], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:], uint32(36+dataSize))
+	copy(buf[8:], "WAVE")
+	copy(buf[12:], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:], 16)
+	binary.LittleEndian.PutUint16(buf[20:], 1)
+	binary.LittleEndian.PutUint16(buf[22:], 1)
+	binary.LittleEndian.PutUint32(buf[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(buf[28:], uint32(sampleRate*2))
+	binary.LittleEndian.PutUint16(buf[32:], 2)
+	binary.LittleEndian.PutUint16(buf[34:], 16)
+	copy(buf[36:], "data")
+	binary.LittleEndian.PutUint32(buf[40:], uint32(dataSize))
+	return buf
+}
@@ -0,0 +1,124 @@
+package audio
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// checkWAVHeader verifies the RIFF header of a mono 16-bit WAV file holding
+// samples samples at sampleRate.
+func checkWAVHeader(t *testing.T, data []byte, samples, sampleRate int) {
+	t.Helper()
+	if len(data) != 44+2*samples {
+		t.Fatalf("file is %d bytes, want %d", len(data), 44+2*samples)
+	}
+	for _, tag := range []struct {
+		at   int
+		want string
+	}{{0, "RIFF"}, {8, "WAVE"}, {12, "fmt "}, {36, "data"}} {
+		if got := string(data[tag.at : tag.at+4]); got != tag.want {
+			t.Errorf("bytes %d-%d = %q, want %q", tag.at, tag.at+3, got, tag.want)
+		}
+	}
+	fields := []struct {
+		name string
+		got  uint32
+		want uint32
+	}{
+		{"RIFF size", binary.LittleEndian.Uint32(data[4:]), uint32(len(data) - 8)},
+		{"fmt size", binary.LittleEndian.Uint32(data[16:]), 16},
+		{"format", uint32(binary.LittleEndian.Uint16(data[20:])), 1},
+		{"channels", uint32(binary.LittleEndian.Uint16(data[22:])), 1},
+		{"sample rate", binary.LittleEndian.Uint32(data[24:]), uint32(sampleRate)},
+		{"byte rate", binary.LittleEndian.Uint32(data[28:]), uint32(2 * sampleRate)},
+		{"block align", uint32(binary.LittleEndian.Uint16(data[32:])), 2},
+		{"bits per sample", uint32(binary.LittleEndian.Uint16(data[34:])), 16},
+		{"data size", binary.LittleEndian.Uint32(data[40:]), uint32(2 * samples)},
+	}
+	for _, f := range fields {
+		if f.got != f.want {
+			t.Errorf("%s = %d, want %d", f.name, f.got, f.want)
+		}
+	}
+}
+
+func TestWriteWAVHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	samples := []int16{0, 1000, -1000, 32767, -32768}
+	if err := WriteWAV(path, samples, 44100); err != nil {
+		t.Fatalf("WriteWAV: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWAVHeader(t, data, len(samples), 44100)
+	for i, want := range samples {
+		if got := int16(binary.LittleEndian.Uint16(data[44+2*i:])); got != want {
+			t.Errorf("sample %d = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestWavRecorderStreams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	r := NewWavRecorder(path, 22050)
+	r.Add([]float32{0, 0.5, -0.5})
+	r.Add([]float32{2, -2})
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("recording not on disk before Stop: %v", err)
+	}
+	got, err := r.Stop()
+	if err != nil || got != path {
+		t.Fatalf("Stop = %q, %v; want %q, nil", got, err, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWAVHeader(t, data, 5, 22050)
+	want := []int16{0, 16383, -16383, 32767, -32767}
+	for i, w := range want {
+		if s := int16(binary.LittleEndian.Uint16(data[44+2*i:])); s != w {
+			t.Errorf("sample %d = %d, want %d", i, s, w)
+		}
+	}
+}
+
+func TestWavRecorderNothingRecorded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	r := NewWavRecorder(path, 44100)
+	if got, err := r.Stop(); got != "" || err != nil {
+		t.Errorf("Stop = %q, %v; want no file", got, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("a session without audio left a file: %v", err)
+	}
+}
+
+func TestDeliveredFillsDroppedBlocks(t *testing.T) {
+	m := &MicHandler{
+		Buffer: make([]float32, config.BufferSize),
+		ring:   NewRingBuffer(2, config.RingBlockSize),
+	}
+	for v := range 3 {
+		m.ring.Put(filledBlock(config.RingBlockSize, float32(v+1)))
+	}
+	// The ring held two blocks; the third was dropped.
+	m.pullBlocks()
+
+	got := m.Delivered()
+	if len(got) != 3*config.RingBlockSize {
+		t.Fatalf("Delivered has %d samples, want %d (two blocks and one of silence)", len(got), 3*config.RingBlockSize)
+	}
+	for i, want := range []float32{1, 2, 0} {
+		if v := got[i*config.RingBlockSize]; v != want {
+			t.Errorf("block %d starts with %v, want %v", i, v, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+
+	"singAssist/internal/theory"
+)
+
+/*
+WriteAnalysisReport prints a song analysis as "key: value" lines.
+
+Input:
+  - w: io.Writer - Destination (stdout)
+  - result: *LoadResult - Loaded and analyzed song
+
+Called by:
+  - main.runAnalyze ("singAssist analyze <song_folder>")
+
+Task:
+  - Summarise the song in a format scripts can read
+
+Logic:
+ 1. duration (seconds), bpm (0 if none was found), key ("unknown" if none)
+ 2. lowest_note and highest_note over the voiced frames ("-" without any)
+ 3. voiced_fraction and difficulty stars from ComputeDifficulty
+
+Output:
+  - error: Write error
+*/
+func WriteAnalysisReport(w io.Writer, result *LoadResult) error {
+	low, high := 0.0, 0.0
+	for _, p := range result.SongPitch {
+		if p <= 10 {
+			continue
+		}
+		if low == 0 || p < low {
+			low = p
+		}
+		high = max(high, p)
+	}
+	noteName := func(f float64) string {
+		if f == 0 {
+			return "-"
+		}
+		note, octave := theory.FreqToNote(f)
+		return fmt.Sprintf("%s%d", note, octave)
+	}
+	key := result.Key
+	if key == "" {
+		key = "unknown"
+	}
+	d := ComputeDifficulty(result.SongPitch)
+
+	_, err := fmt.Fprintf(w, "duration: %.1f\nbpm: %.1f\nkey: %s\nlowest_note: %s\nhighest_note: %s\nvoiced_fraction: %.2f\ndifficulty: %d\n",
+		result.Duration.Seconds(), result.BPM, key, noteName(low), noteName(high), d.Voiced, d.Stars)
+	return err
+}
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"singAssist/internal/config"
+)
+
+func TestWriteAnalysisReportFromPitchCache(t *testing.T) {
+	dir := t.TempDir()
+	samples := make([]int16, 2*config.SampleRate)
+	for i := range samples {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*220*float64(i)/config.SampleRate))
+	}
+	song := filepath.Join(dir, "song.wav")
+	if err := WriteWAV(song, samples, config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(song, old, old); err != nil {
+		t.Fatal(err)
+	}
+	// A known contour: half silence, then A3 and A4. The cache is newer than
+	// the audio, so it is loaded and no analysis runs.
+	pitch := make([]float64, 200)
+	for i := 100; i < 150; i++ {
+		pitch[i] = 220
+	}
+	for i := 150; i < 200; i++ {
+		pitch[i] = 440
+	}
+	cache := pitchCachePath(config.GetSongPaths(dir).PitchCacheFile, ModeFullMix, pitchAlgorithm)
+	if err := SavePitchCache(cache, pitch); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := LoadAndAnalyzeSong(dir, ModeFullMix, LoadOptions{Analysis: DefaultAnalysisParams()}, nil)
+	if err != nil {
+		t.Fatalf("LoadAndAnalyzeSong: %v", err)
+	}
+	var out strings.Builder
+	if err := WriteAnalysisReport(&out, result); err != nil {
+		t.Fatalf("WriteAnalysisReport: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			t.Fatalf("line %q is not key: value", line)
+		}
+		got[key] = value
+	}
+	want := map[string]string{
+		"duration":        "2.0",
+		"lowest_note":     "A3",
+		"highest_note":    "A4",
+		"voiced_fraction": "0.50",
+	}
+	for key, w := range want {
+		if got[key] != w {
+			t.Errorf("%s: %q, want %q", key, got[key], w)
+		}
+	}
+	for _, key := range []string{"bpm", "key", "difficulty"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("report has no %s line", key)
+		}
+	}
+}
+
+func TestWriteAnalysisReportSilentSong(t *testing.T) {
+	var out strings.Builder
+	if err := WriteAnalysisReport(&out, &LoadResult{SongPitch: make([]float64, 100)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"bpm: 0.0", "key: unknown", "lowest_note: -", "highest_note: -", "voiced_fraction: 0.00"} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("report has no %q line:\n%s", line, out.String())
+		}
+	}
+}
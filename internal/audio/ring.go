@@ -0,0 +1,170 @@
+package audio
+
+import "sync/atomic"
+
+/*
+RingBuffer hands fixed-size sample blocks from the PortAudio callback to the
+mic goroutine without locks (one writer, one reader).
+
+Fields:
+  - blocks: Preallocated block storage, reused round-robin
+  - head: Number of blocks ever written (next slot is head % len(blocks))
+  - tail: Number of blocks ever consumed by Get
+  - dropped: Number of blocks Put discarded because the ring was full
+  - out: Block returned by Get or Next (reader-owned, overwritten by the next call)
+*/
+type RingBuffer struct {
+	blocks  [][]float32
+	head    atomic.Uint64
+	tail    atomic.Uint64
+	dropped atomic.Uint64
+	out     []float32
+}
+
+/*
+NewRingBuffer creates a ring of n blocks of size samples each.
+
+Input:
+  - n: int - Number of blocks (config.RingBlocks)
+  - size: int - Samples per block (config.RingBlockSize)
+
+Called by:
+  - NewMicHandler in low-latency mode
+
+Task:
+  - Allocate everything up front so Put never allocates on the audio thread
+
+Logic:
+ 1. Allocate n blocks and the reader's output block
+
+Output:
+  - *RingBuffer: Empty ring
+*/
+func NewRingBuffer(n, size int) *RingBuffer {
+	r := &RingBuffer{
+		blocks: make([][]float32, n),
+		out:    make([]float32, size),
+	}
+	for i := range r.blocks {
+		r.blocks[i] = make([]float32, size)
+	}
+	return r
+}
+
+/*
+Put stores one block of samples.
+
+Input:
+  - block: []float32 - Samples from the stream callback (copied, may be reused)
+
+Called by:
+  - The MicHandler callback stream (PortAudio thread)
+
+Task:
+  - Publish a block without blocking the audio thread
+
+Logic:
+ 1. If every slot holds an unread block, drop this one (the reader is behind
+    and only wants the newest block anyway; overwriting would race with Get)
+    and count it in dropped
+ 2. Copy into slot head % n, then advance head so the reader can see it
+
+Output:
+  - None
+*/
+func (r *RingBuffer) Put(block []float32) {
+	head := r.head.Load()
+	if head-r.tail.Load() >= uint64(len(r.blocks)) {
+		r.dropped.Add(1)
+		return
+	}
+	copy(r.blocks[head%uint64(len(r.blocks))], block)
+	r.head.Store(head + 1)
+}
+
+/*
+Get returns the most recently written block and discards older unread ones.
+
+Input:
+  - None
+
+Called by:
+  - Readers that only want the newest audio (one reader goroutine only; the
+    mic's sliding window uses Next so no block is skipped)
+
+Task:
+  - Always analyze the newest audio instead of working through a backlog
+
+Logic:
+ 1. If head == tail, nothing is unread: return false
+ 2. Copy slot (head-1) % n into out (the writer cannot reach that slot until
+    tail moves past it)
+ 3. Set tail = head, freeing every slot up to it
+
+Output:
+  - []float32: The newest block (reused by the next Get)
+  - bool: false if no block was written since the last Get
+*/
+func (r *RingBuffer) Get() ([]float32, bool) {
+	head := r.head.Load()
+	if head == r.tail.Load() {
+		return nil, false
+	}
+	copy(r.out, r.blocks[(head-1)%uint64(len(r.blocks))])
+	r.tail.Store(head)
+	return r.out, true
+}
+
+/*
+Next returns the oldest unread block.
+
+Input:
+  - None
+
+Called by:
+  - MicHandler.pullBlocks (mic goroutine only)
+
+Task:
+  - Hand over every block in order, for a sliding analysis window
+
+Logic:
+ 1. If head == tail, nothing is unread: return false
+ 2. Copy slot tail % n into out (the writer cannot reach that slot until
+    tail moves past it)
+ 3. Advance tail by one
+
+Output:
+  - []float32: The oldest unread block (reused by the next Get or Next)
+  - bool: false if every written block was already read
+*/
+func (r *RingBuffer) Next() ([]float32, bool) {
+	tail := r.tail.Load()
+	if r.head.Load() == tail {
+		return nil, false
+	}
+	copy(r.out, r.blocks[tail%uint64(len(r.blocks))])
+	r.tail.Store(tail + 1)
+	return r.out, true
+}
+
+/*
+Dropped returns how many blocks Put has discarded so far.
+
+Input:
+  - None
+
+Called by:
+  - MicHandler.pullBlocks to keep the session recording in time
+
+Task:
+  - Tell the reader how much audio it lost while it was behind
+
+Logic:
+ 1. Load the dropped counter
+
+Output:
+  - uint64: Blocks dropped since the ring was created
+*/
+func (r *RingBuffer) Dropped() uint64 {
+	return r.dropped.Load()
+}
@@ -0,0 +1,150 @@
+package audio
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// filledBlock returns a block of size samples all set to v.
+func filledBlock(size int, v float32) []float32 {
+	b := make([]float32, size)
+	for i := range b {
+		b[i] = v
+	}
+	return b
+}
+
+func TestRingBufferEmpty(t *testing.T) {
+	r := NewRingBuffer(4, 8)
+	if _, ok := r.Get(); ok {
+		t.Error("Get on an empty ring returned a block")
+	}
+	if _, ok := r.Next(); ok {
+		t.Error("Next on an empty ring returned a block")
+	}
+
+	r.Put(filledBlock(8, 1))
+	if _, ok := r.Get(); !ok {
+		t.Fatal("Get after Put returned nothing")
+	}
+	if _, ok := r.Get(); ok {
+		t.Error("second Get returned the same block again")
+	}
+}
+
+func TestRingBufferGetReturnsNewest(t *testing.T) {
+	r := NewRingBuffer(4, 8)
+	for v := range 3 {
+		r.Put(filledBlock(8, float32(v)))
+	}
+	block, ok := r.Get()
+	if !ok || block[0] != 2 {
+		t.Errorf("Get = %v, %v; want the newest block (2)", block, ok)
+	}
+	if _, ok := r.Next(); ok {
+		t.Error("Get left older blocks unread")
+	}
+}
+
+func TestRingBufferNextKeepsOrder(t *testing.T) {
+	r := NewRingBuffer(4, 8)
+	for v := range 6 {
+		r.Put(filledBlock(8, float32(v)))
+	}
+	// The ring holds 4 blocks; the last two Puts were dropped.
+	for want := range 4 {
+		block, ok := r.Next()
+		if !ok || block[0] != float32(want) {
+			t.Fatalf("Next = %v, %v; want block %d", block, ok, want)
+		}
+	}
+	if _, ok := r.Next(); ok {
+		t.Error("Next returned more blocks than the ring holds")
+	}
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+	const blocks, size = 20000, 64
+	r := NewRingBuffer(8, size)
+
+	var writerDone atomic.Bool
+	go func() {
+		buf := make([]float32, size)
+		for v := 1; v <= blocks; v++ {
+			for i := range buf {
+				buf[i] = float32(v)
+			}
+			r.Put(buf)
+		}
+		writerDone.Store(true)
+	}()
+
+	last, reads := float32(0), 0
+	for {
+		finished := writerDone.Load()
+		var block []float32
+		var ok bool
+		if reads%2 == 0 {
+			block, ok = r.Next()
+		} else {
+			block, ok = r.Get()
+		}
+		reads++
+		if !ok {
+			if finished {
+				break
+			}
+			continue
+		}
+		for i, s := range block {
+			if s != block[0] {
+				t.Fatalf("torn block: sample %d is %v, sample 0 is %v", i, s, block[0])
+			}
+		}
+		if block[0] <= last {
+			t.Fatalf("block %v read after block %v", block[0], last)
+		}
+		last = block[0]
+	}
+	if last == 0 {
+		t.Error("no block was read")
+	}
+}
+
+func TestPullBlocksSlidesWindow(t *testing.T) {
+	m := &MicHandler{
+		Buffer: make([]float32, config.BufferSize),
+		ring:   NewRingBuffer(config.RingBlocks, config.RingBlockSize),
+	}
+	m.ring.Put(filledBlock(config.RingBlockSize, 1))
+	m.ring.Put(filledBlock(config.RingBlockSize, 2))
+
+	if n := m.pullBlocks(); n != 2*config.RingBlockSize {
+		t.Fatalf("pullBlocks slid in %d samples, want %d", n, 2*config.RingBlockSize)
+	}
+	if len(m.Buffer) != config.BufferSize {
+		t.Fatalf("Buffer has %d samples, want %d", len(m.Buffer), config.BufferSize)
+	}
+	end := len(m.Buffer)
+	tests := []struct {
+		at   int
+		want float32
+	}{
+		{0, 0},
+		{end - 2*config.RingBlockSize - 1, 0},
+		{end - 2*config.RingBlockSize, 1},
+		{end - config.RingBlockSize - 1, 1},
+		{end - config.RingBlockSize, 2},
+		{end - 1, 2},
+	}
+	for _, tt := range tests {
+		if got := m.Buffer[tt.at]; got != tt.want {
+			t.Errorf("Buffer[%d] = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+	if m.pullBlocks() != 0 {
+		t.Error("pullBlocks slid in samples with nothing new in the ring")
+	}
+}
@@ -0,0 +1,191 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"time"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+)
+
+const (
+	sepFrameSize = 2048
+	sepHopSize   = sepFrameSize / 4
+	sepVocalLow  = 120.0
+	sepVocalHigh = 5000.0
+)
+
+/*
+SeparateSpectral produces a rough vocals/accompaniment split without Python.
+
+Input:
+  - pcmBytes: []byte - Raw PCM audio data (16-bit stereo, 44100Hz)
+
+Called by:
+  - LoadAndAnalyzeSong for ModeRoughVocals
+
+Task:
+  - Approximate vocal isolation using center-channel masking
+
+Logic:
+ 1. Split PCM into left/right float channels
+ 2. Run an STFT (2048-sample Hann frames, 75% overlap) on both channels
+ 3. For every bin compute stereo similarity 2|L·R*| / (|L|²+|R|²):
+    lead vocals are usually panned center, so similar bins are vocal-like
+ 4. Weight the mask by a soft band-pass over the vocal range (120Hz-5kHz)
+ 5. Vocals = mask * (L+R)/2; accompaniment = original - vocals per channel
+ 6. Inverse STFT with overlap-add and window-power normalization
+ 7. Convert both results back to 16-bit stereo PCM
+
+Quality:
+  - Much worse than Demucs/Spleeter: centered bass, kick and snare leak
+    into the vocals, and reverb tails stay in the accompaniment. Good enough
+    for a pitch guide, not for listening.
+
+Output:
+  - vocals: []byte - Estimated vocals (stereo PCM, same length as input)
+  - accomp: []byte - Estimated accompaniment (stereo PCM, same length as input)
+*/
+func SeparateSpectral(pcmBytes []byte) (vocals, accomp []byte) {
+	startTime := time.Now()
+	frames := len(pcmBytes) / 4
+
+	left := make([]float64, frames)
+	right := make([]float64, frames)
+	for i := 0; i < frames; i++ {
+		left[i] = float64(int16(pcmBytes[i*4])|int16(pcmBytes[i*4+1])<<8) / 32768.0
+		right[i] = float64(int16(pcmBytes[i*4+2])|int16(pcmBytes[i*4+3])<<8) / 32768.0
+	}
+
+	window := hannWindow(sepFrameSize)
+	bandMask := make([]float64, sepFrameSize/2+1)
+	for k := range bandMask {
+		f := float64(k) * float64(config.SampleRate) / sepFrameSize
+		bandMask[k] = softBand(f, sepVocalLow, sepVocalHigh)
+	}
+
+	vocalOut := make([]float64, frames+sepFrameSize)
+	normOut := make([]float64, frames+sepFrameSize)
+	specL := make([]complex128, sepFrameSize)
+	specR := make([]complex128, sepFrameSize)
+	specV := make([]complex128, sepFrameSize)
+
+	for pos := -sepFrameSize + sepHopSize; pos < frames; pos += sepHopSize {
+		for i := 0; i < sepFrameSize; i++ {
+			idx := pos + i
+			l, r := 0.0, 0.0
+			if idx >= 0 && idx < frames {
+				l, r = left[idx], right[idx]
+			}
+			specL[i] = complex(l*window[i], 0)
+			specR[i] = complex(r*window[i], 0)
+		}
+		fft(specL, false)
+		fft(specR, false)
+
+		for k := 0; k <= sepFrameSize/2; k++ {
+			l, r := specL[k], specR[k]
+			power := real(l)*real(l) + imag(l)*imag(l) + real(r)*real(r) + imag(r)*imag(r)
+			mask := 0.0
+			if power > 1e-12 {
+				similarity := 2 * cmplx.Abs(l*cmplx.Conj(r)) / power
+				mask = math.Pow(similarity, 4) * bandMask[k]
+			}
+			specV[k] = complex(mask, 0) * (l + r) / 2
+			if k > 0 && k < sepFrameSize/2 {
+				specV[sepFrameSize-k] = cmplx.Conj(specV[k])
+			}
+		}
+		fft(specV, true)
+
+		for i := 0; i < sepFrameSize; i++ {
+			idx := pos + i
+			if idx < 0 || idx >= frames {
+				continue
+			}
+			vocalOut[idx] += real(specV[i]) * window[i]
+			normOut[idx] += window[i] * window[i]
+		}
+	}
+
+	vocals = make([]byte, frames*4)
+	accomp = make([]byte, frames*4)
+	for i := 0; i < frames; i++ {
+		v := 0.0
+		if normOut[i] > 1e-9 {
+			v = vocalOut[i] / normOut[i]
+		}
+		putStereo(vocals, i, v, v)
+		putStereo(accomp, i, left[i]-v, right[i]-v)
+	}
+
+	logging.Infof("Spectral separation done in %v", time.Since(startTime))
+	return vocals, accomp
+}
+
+/*
+softBand returns a smooth 0-1 weight that is 1 inside [low, high].
+
+Input:
+  - f: float64 - Frequency in Hz
+  - low, high: float64 - Pass band edges in Hz
+
+Called by:
+  - SeparateSpectral when building the vocal band mask
+
+Task:
+  - Avoid hard spectral edges that cause ringing
+
+Logic:
+ 1. Below low: roll off at 12 dB per octave
+ 2. Above high: roll off at 12 dB per octave
+ 3. Inside band: 1
+
+Output:
+  - float64: Weight in [0, 1]
+*/
+func softBand(f, low, high float64) float64 {
+	switch {
+	case f <= 0:
+		return 0
+	case f < low:
+		r := f / low
+		return r * r
+	case f > high:
+		r := high / f
+		return r * r
+	default:
+		return 1
+	}
+}
+
+/*
+putStereo writes one stereo frame into a 16-bit PCM buffer.
+
+Input:
+  - buf: []byte - Destination PCM (4 bytes per frame)
+  - frame: int - Frame index
+  - l, r: float64 - Sample values in [-1, 1] (clipped if outside)
+
+Called by:
+  - SeparateSpectral when converting results back to PCM
+
+Task:
+  - Encode little-endian int16 samples with clipping
+
+Logic:
+ 1. Clamp each value to [-1, 1]
+ 2. Scale by 32767 and store little-endian
+
+Output:
+  - None (writes into buf)
+*/
+func putStereo(buf []byte, frame int, l, r float64) {
+	for ch, v := range [2]float64{l, r} {
+		v = math.Max(-1, math.Min(1, v))
+		s := int16(v * 32767)
+		buf[frame*4+ch*2] = byte(s)
+		buf[frame*4+ch*2+1] = byte(s >> 8)
+	}
+}
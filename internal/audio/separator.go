@@ -0,0 +1,263 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+)
+
+/*
+CommandRunner runs an external command and returns its output.
+
+Fields:
+  - CombinedOutput: Run name with args under ctx, returning stdout+stderr
+  - Output: Run name with args under ctx, returning stdout only (for output
+    that is parsed line by line, where warnings on stderr would get mixed in)
+*/
+type CommandRunner interface {
+	CombinedOutput(ctx context.Context, name string, args ...string) ([]byte, error)
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// ExecRunner is the CommandRunner backed by os/exec; audio.Runner and
+// youtube.Runner both default to it.
+type ExecRunner struct{}
+
+/*
+CombinedOutput runs the command with exec.CommandContext.
+
+Input:
+  - ctx: context.Context - Cancels (kills) the command
+  - name: string - Executable
+  - args: ...string - Arguments
+
+Called by:
+  - runSeparation, pythonHasPackage, convertDemucsStems and ConvertToMP3 through Runner
+  - youtube.Download and youtube.DownloadPlaylist through youtube.Runner
+
+Task:
+  - Default, real command execution
+
+Logic:
+ 1. exec.CommandContext(ctx, name, args...).CombinedOutput()
+
+Output:
+  - []byte: Combined stdout and stderr
+  - error: Exit or start error
+*/
+func (ExecRunner) CombinedOutput(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+/*
+Output runs the command with exec.CommandContext, keeping stderr apart.
+
+Input:
+  - ctx: context.Context - Cancels (kills) the command
+  - name: string - Executable
+  - args: ...string - Arguments
+
+Called by:
+  - youtube.DownloadPlaylist through youtube.Runner
+
+Task:
+  - Parse a tool's answer without its warnings
+
+Logic:
+ 1. exec.CommandContext(ctx, name, args...).Output()
+ 2. On a failed exit, add the captured stderr to the error
+
+Output:
+  - []byte: Standard output
+  - error: Exit (with stderr) or start error
+*/
+func (ExecRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
+
+// Runner executes the separator scripts and ffmpeg; replace it to run the
+// package without Python.
+var Runner CommandRunner = ExecRunner{}
+
+/*
+stemIsStale reports whether a separated track has to be made again.
+
+Input:
+  - stemFile: string - paths.VocalsFile or paths.AccompFile
+  - songFile: string - paths.SongFile the stem was separated from
+
+Called by:
+  - LoadAndAnalyzeSong before choosing the audio file
+
+Task:
+  - Never play or analyze the stems of a song file that has since been replaced
+
+Logic:
+ 1. A missing stem is stale
+ 2. So is a stem older than the song file (re-downloaded or replaced song);
+    a missing song file leaves the stem alone
+
+Output:
+  - bool: true if runSeparation should run
+*/
+func stemIsStale(stemFile, songFile string) bool {
+	stem, err := os.Stat(stemFile)
+	if err != nil {
+		return true
+	}
+	song, err := os.Stat(songFile)
+	return err == nil && stem.ModTime().Before(song.ModTime())
+}
+
+/*
+runSeparation splits a song into vocals.mp3 and accompaniment.mp3.
+
+Input:
+  - paths: config.SongPaths - The song's files
+
+Called by:
+  - LoadAndAnalyzeSong when the separated track it needs is missing or stale
+
+Task:
+  - Run the user's separation script, or whichever separator (spleeter or
+    demucs) is installed
+
+Logic:
+ 1. A script from config.GetSeparateScript is run with separationArgs and must
+    write vocals.mp3 and accompaniment.mp3 itself
+ 2. Otherwise pick the backend with config.GetSeparatorBackend
+    (SINGASSIST_SEPARATOR, else whichever Python package imports) and run its
+    script the same way via Runner
+ 3. Demucs leaves its stems in htdemucs/<song>/: convert them with convertDemucsStems
+
+Output:
+  - error: nil on success, script or conversion failure with its output
+*/
+func runSeparation(paths config.SongPaths) error {
+	pythonCmd := config.GetPythonPath()
+	script := config.GetSeparateScript()
+	if script == "" {
+		script = config.GetSeparatorBackend(func(pkg string) bool {
+			return pythonHasPackage(pythonCmd, pkg)
+		}).Script()
+	}
+	logging.Debugf("Using Python: %s, separation script: %s", pythonCmd, script)
+
+	output, err := Runner.CombinedOutput(context.Background(), pythonCmd, separationArgs(script, paths)...)
+	logging.Debugf("Separator output: %s", string(output))
+	if err != nil {
+		return fmt.Errorf("separation failed: %v\nOutput: %s", err, string(output))
+	}
+
+	if script == config.BackendDemucs.Script() {
+		return convertDemucsStems(paths)
+	}
+	return nil
+}
+
+/*
+separationArgs builds the Python arguments of a separation script.
+
+Input:
+  - script: string - Script path
+  - paths: config.SongPaths - The song's files
+
+Called by:
+  - runSeparation
+
+Task:
+  - Tell the script what to separate, where to, and in which format
+
+Logic:
+ 1. "<script> <song file> <song dir> --format <ext>", ext being the extension of
+    paths.VocalsFile without the dot ("mp3"); the built-in scripts ignore the flag
+
+Output:
+  - []string: Arguments following the Python executable
+*/
+func separationArgs(script string, paths config.SongPaths) []string {
+	format := strings.TrimPrefix(filepath.Ext(paths.VocalsFile), ".")
+	return []string{script, paths.SongFile, paths.Dir, "--format", format}
+}
+
+/*
+pythonHasPackage reports whether a Python package can be imported.
+
+Input:
+  - pythonCmd: string - Python executable (config.GetPythonPath)
+  - pkg: string - Package name (e.g. "demucs")
+
+Called by:
+  - runSeparation for backend auto-detection
+
+Task:
+  - Detect installed separators without running them
+
+Logic:
+ 1. Run "<python> -c 'import <pkg>'" via Runner; success means importable
+
+Output:
+  - bool: true if the import succeeded
+*/
+func pythonHasPackage(pythonCmd, pkg string) bool {
+	_, err := Runner.CombinedOutput(context.Background(), pythonCmd, "-c", "import "+pkg)
+	return err == nil
+}
+
+/*
+convertDemucsStems moves demucs output to the files the app loads.
+
+Input:
+  - paths: config.SongPaths - The song's files
+
+Called by:
+  - runSeparation after separate_demucs.py
+
+Task:
+  - Map demucs' <songDir>/htdemucs/<song>/vocals.wav and no_vocals.wav to
+    vocals.mp3 and accompaniment.mp3
+
+Logic:
+ 1. Stems folder is htdemucs/<song file name without extension>
+ 2. Convert each stem with "ffmpeg -y -i <wav> -q:a 2 <mp3>" via Runner
+ 3. Remove the htdemucs folder
+
+Output:
+  - error: Missing stem or ffmpeg failure
+*/
+func convertDemucsStems(paths config.SongPaths) error {
+	outDir := filepath.Join(paths.Dir, "htdemucs")
+	base := strings.TrimSuffix(filepath.Base(paths.SongFile), filepath.Ext(paths.SongFile))
+	stemsDir := filepath.Join(outDir, base)
+
+	stems := []struct{ src, dst string }{
+		{filepath.Join(stemsDir, "vocals.wav"), paths.VocalsFile},
+		{filepath.Join(stemsDir, "no_vocals.wav"), paths.AccompFile},
+	}
+	for _, s := range stems {
+		if _, err := os.Stat(s.src); err != nil {
+			return fmt.Errorf("demucs output missing: %v", err)
+		}
+		output, err := Runner.CombinedOutput(context.Background(), "ffmpeg", "-y", "-i", s.src, "-q:a", "2", s.dst)
+		if err != nil {
+			return fmt.Errorf("ffmpeg conversion of %s failed: %v\nOutput: %s", s.src, err, string(output))
+		}
+	}
+
+	if err := os.RemoveAll(outDir); err != nil {
+		logging.Warnf("Could not remove %s: %v", outDir, err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package audio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"singAssist/internal/config"
+)
+
+func TestRunSeparationCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		file       string
+		backend    string
+		wantScript string
+	}{
+		{"environment variable", "/opt/demucs_wrapper.sh", "ignored.py", "", "/opt/demucs_wrapper.sh"},
+		{"config file", "", "  tools/my_separator.py\n", "", "tools/my_separator.py"},
+		{"chosen backend", "", "", "spleeter", "separate_spleeter.py"},
+		{"auto-detected backend", "", "", "", "separate_spleeter.py"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv(config.SeparateScriptEnv, tt.env)
+			t.Setenv(config.SeparatorEnv, tt.backend)
+			if tt.file != "" {
+				os.MkdirAll(filepath.Dir(config.SeparateScriptFile), 0755)
+				os.WriteFile(config.SeparateScriptFile, []byte(tt.file), 0644)
+			}
+			// No demucs: the import probe fails.
+			f := &fakeRunner{run: func(_ string, args []string) ([]byte, error) {
+				if args[0] == "-c" {
+					return nil, errors.New("ModuleNotFoundError")
+				}
+				return nil, nil
+			}}
+			useFakeRunner(t, f)
+
+			paths := config.GetSongPaths(filepath.Join(config.SongsDir, "Kasoor"))
+			if err := runSeparation(paths); err != nil {
+				t.Fatalf("runSeparation: %v", err)
+			}
+			want := []string{"python3", tt.wantScript, paths.SongFile, paths.Dir, "--format", "mp3"}
+			if got := f.calls[len(f.calls)-1]; !slices.Equal(got, want) {
+				t.Errorf("ran %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRunSeparationFailure(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(config.SeparateScriptEnv, "broken.py")
+	useFakeRunner(t, &fakeRunner{run: func(string, []string) ([]byte, error) {
+		return []byte("Traceback"), errors.New("exit status 1")
+	}})
+	if err := runSeparation(config.GetSongPaths("songs/Kasoor")); err == nil {
+		t.Error("runSeparation hid the script failure")
+	}
+}
+
+func TestRunSeparationBackends(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		demucs  bool
+		script  string
+	}{
+		{"spleeter chosen", "spleeter", true, "separate_spleeter.py"},
+		{"demucs chosen", "demucs", false, "separate_demucs.py"},
+		{"demucs installed", "", true, "separate_demucs.py"},
+		{"nothing installed", "", false, "separate_spleeter.py"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv(config.SeparateScriptEnv, "")
+			t.Setenv(config.SeparatorEnv, tt.backend)
+			paths := config.GetSongPaths(filepath.Join(config.SongsDir, "Kasoor"))
+			stems := filepath.Join(paths.Dir, "htdemucs", "song")
+
+			// The demucs script leaves WAV stems behind, like the real one.
+			f := &fakeRunner{run: func(_ string, args []string) ([]byte, error) {
+				switch {
+				case args[0] == "-c":
+					if args[1] == "import demucs" && tt.demucs {
+						return nil, nil
+					}
+					return nil, errors.New("ModuleNotFoundError")
+				case args[0] == "separate_demucs.py":
+					os.MkdirAll(stems, 0755)
+					os.WriteFile(filepath.Join(stems, "vocals.wav"), nil, 0644)
+					os.WriteFile(filepath.Join(stems, "no_vocals.wav"), nil, 0644)
+				}
+				return nil, nil
+			}}
+			useFakeRunner(t, f)
+
+			if err := runSeparation(paths); err != nil {
+				t.Fatalf("runSeparation: %v", err)
+			}
+			var ran [][]string
+			for _, c := range f.calls {
+				if c[1] != "-c" {
+					ran = append(ran, c)
+				}
+			}
+			want := [][]string{{"python3", tt.script, paths.SongFile, paths.Dir, "--format", "mp3"}}
+			if tt.script == "separate_demucs.py" {
+				want = append(want,
+					[]string{"ffmpeg", "-y", "-i", filepath.Join(stems, "vocals.wav"), "-q:a", "2", paths.VocalsFile},
+					[]string{"ffmpeg", "-y", "-i", filepath.Join(stems, "no_vocals.wav"), "-q:a", "2", paths.AccompFile})
+			}
+			if !slices.EqualFunc(ran, want, slices.Equal) {
+				t.Errorf("ran %q, want %q", ran, want)
+			}
+			if _, err := os.Stat(filepath.Join(paths.Dir, "htdemucs")); !os.IsNotExist(err) {
+				t.Errorf("htdemucs folder left behind (%v)", err)
+			}
+		})
+	}
+}
+
+func TestRunSeparationDemucsMissingStems(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(config.SeparateScriptEnv, "")
+	t.Setenv(config.SeparatorEnv, "demucs")
+	useFakeRunner(t, &fakeRunner{})
+	if err := runSeparation(config.GetSongPaths(filepath.Join(config.SongsDir, "Kasoor"))); err == nil {
+		t.Error("runSeparation succeeded without demucs output")
+	}
+}
+
+func TestStemIsStale(t *testing.T) {
+	dir := t.TempDir()
+	song, stem := filepath.Join(dir, "song.wav"), filepath.Join(dir, "vocals.mp3")
+	os.WriteFile(song, []byte("song"), 0644)
+	if !stemIsStale(stem, song) {
+		t.Error("a missing stem is not stale")
+	}
+
+	os.WriteFile(stem, []byte("vocals"), 0644)
+	now := time.Now()
+	os.Chtimes(song, now.Add(-time.Hour), now.Add(-time.Hour))
+	if stemIsStale(stem, song) {
+		t.Error("a stem separated after the song is stale")
+	}
+	os.Chtimes(song, now.Add(time.Hour), now.Add(time.Hour))
+	if !stemIsStale(stem, song) {
+		t.Error("a stem older than the replaced song is not stale")
+	}
+}
+
+func TestLoadAndAnalyzeSongReseparatesReplacedSong(t *testing.T) {
+	dir := t.TempDir()
+	paths := config.GetSongPaths(dir)
+	hourAgo := time.Now().Add(-time.Hour)
+	for _, stem := range []string{paths.VocalsFile, paths.AccompFile} {
+		os.WriteFile(stem, []byte("the old song's stem"), 0644)
+		os.Chtimes(stem, hourAgo, hourAgo)
+	}
+	if err := WriteWAV(filepath.Join(dir, "song.wav"), make([]int16, config.SampleRate), config.SampleRate); err != nil {
+		t.Fatal(err)
+	}
+	paths = config.GetSongPaths(dir)
+
+	t.Setenv(config.SeparateScriptEnv, "separate.py")
+	for _, mode := range []Mode{ModeSinging, ModeInstrumental, ModeDuet, ModeHarmony} {
+		f := &fakeRunner{run: func(string, []string) ([]byte, error) {
+			return nil, errors.New("separator stopped by the test")
+		}}
+		useFakeRunner(t, f)
+
+		if _, err := LoadAndAnalyzeSong(dir, mode, LoadOptions{Analysis: DefaultAnalysisParams()}, nil); err == nil {
+			t.Errorf("%v: loaded the old stems without separating", mode)
+		}
+		want := []string{"python3", "separate.py", paths.SongFile, dir, "--format", "mp3"}
+		if !slices.ContainsFunc(f.calls, func(c []string) bool { return slices.Equal(c, want) }) {
+			t.Errorf("%v: ran %q, want the separator on the new song", mode, f.calls)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"singAssist/internal/config"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+/*
+SpeedCachePath names the time-stretched copy of an audio file.
+
+Input:
+  - audioFile: string - Original audio (e.g. "songs/MySong/song.mp3")
+  - speed: float64 - Playback speed factor (e.g. 0.5)
+
+Called by:
+  - StretchAudio
+
+Task:
+  - Keep one cached copy per speed next to the original
+
+Logic:
+ 1. <dir>/<name without extension>_<speed with two decimals>x.mp3
+
+Output:
+  - string: e.g. "songs/MySong/song_0.50x.mp3"
+*/
+func SpeedCachePath(audioFile string, speed float64) string {
+	base := strings.TrimSuffix(filepath.Base(audioFile), filepath.Ext(audioFile))
+	return filepath.Join(filepath.Dir(audioFile), fmt.Sprintf("%s_%.2fx.mp3", base, speed))
+}
+
+/*
+atempoFilter builds the ffmpeg filter that changes tempo by speed.
+
+Input:
+  - speed: float64 - Playback speed factor (config.MinPlaybackSpeed..MaxPlaybackSpeed)
+
+Called by:
+  - StretchAudio
+
+Task:
+  - Stay within the 0.5..2.0 range older ffmpeg builds allow per atempo
+
+Logic:
+ 1. While the remaining factor is below 0.5: add "atempo=0.5" and halve it
+ 2. Add the remaining factor; join the stages with commas
+
+Output:
+  - string: e.g. "atempo=0.5,atempo=0.5" for 0.25
+*/
+func atempoFilter(speed float64) string {
+	var stages []string
+	for speed < 0.5 {
+		stages = append(stages, "atempo=0.5")
+		speed /= 0.5
+	}
+	stages = append(stages, fmt.Sprintf("atempo=%g", speed))
+	return strings.Join(stages, ",")
+}
+
+/*
+StretchAudio returns an audio file played back at another speed, same pitch.
+
+Input:
+  - audioFile: string - Original audio
+  - speed: float64 - Playback speed factor
+
+Called by:
+  - NewSpeedPlayer when the speed is not 1
+
+Task:
+  - Slow fast passages down for learning without changing their pitch
+
+Logic:
+ 1. SpeedCachePath is newer than audioFile: use it
+ 2. Without ffmpeg (config.HasFFMPEG): error
+ 3. Otherwise run "ffmpeg -y -i <audioFile> -filter:a atempo=<speed> -f mp3 <cache>.tmp"
+    via Runner (see atempoFilter for speeds below 0.5)
+ 4. Rename the temp file to the cache path, so an interrupted run never
+    leaves a truncated file that looks fresh
+
+Output:
+  - string: Path of the stretched file
+  - error: Missing ffmpeg or conversion failure
+*/
+func StretchAudio(audioFile string, speed float64) (string, error) {
+	dst := SpeedCachePath(audioFile, speed)
+	if cacheIsFresh(dst, audioFile) {
+		return dst, nil
+	}
+	if !config.HasFFMPEG {
+		return "", fmt.Errorf("ffmpeg is needed to change the playback speed")
+	}
+	tmp := dst + ".tmp"
+	output, err := Runner.CombinedOutput(context.Background(), "ffmpeg", "-y", "-i", audioFile, "-filter:a", atempoFilter(speed), "-f", "mp3", tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("ffmpeg time stretch of %s failed: %v\nOutput: %s", audioFile, err, string(output))
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return dst, nil
+}
+
+/*
+NewSpeedPlayer creates a player for a song at a given playback speed.
+
+Input:
+  - audioFile: string - Audio the session plays (LoadResult.AudioFile)
+  - speed: float64 - Playback speed factor (1 = original)
+  - start, end: time.Duration - Practice section in song time (0 = whole song)
+
+Called by:
+  - App.changePlaybackSpeed
+
+Task:
+  - Swap in slowed-down (or sped-up) audio for the current session
+
+Logic:
+ 1. speed != 1: StretchAudio (cached)
+ 2. Decode the file with decodeAudioFile
+ 3. Crop to start/speed..end/speed, where the section lies in the stretched audio
+ 4. Create the player on AudioContext
+
+Output:
+  - *audio.Player: Player whose position is song time divided by speed
+  - error: Stretch, decode or player error
+*/
+func NewSpeedPlayer(audioFile string, speed float64, start, end time.Duration) (*audio.Player, error) {
+	path := audioFile
+	if speed != 1 {
+		var err error
+		if path, err = StretchAudio(audioFile, speed); err != nil {
+			return nil, err
+		}
+	}
+	pcm, err := decodeAudioFile(path)
+	if err != nil {
+		return nil, err
+	}
+	scale := func(d time.Duration) time.Duration { return time.Duration(float64(d) / speed) }
+	return AudioContext.NewPlayer(bytes.NewReader(cropPCM(pcm, scale(start), scale(end))))
+}
@@ -0,0 +1,123 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// fakeRunner records the commands it is asked to run and answers them with run.
+type fakeRunner struct {
+	calls [][]string
+	run   func(name string, args []string) ([]byte, error)
+}
+
+func (f *fakeRunner) CombinedOutput(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if f.run == nil {
+		return nil, nil
+	}
+	return f.run(name, args)
+}
+
+func (f *fakeRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return f.CombinedOutput(ctx, name, args...)
+}
+
+// useFakeRunner swaps Runner (and config.HasFFMPEG) for the duration of the test.
+func useFakeRunner(t *testing.T, f *fakeRunner) {
+	t.Helper()
+	oldRunner, oldFFMPEG := Runner, config.HasFFMPEG
+	Runner, config.HasFFMPEG = f, true
+	t.Cleanup(func() { Runner, config.HasFFMPEG = oldRunner, oldFFMPEG })
+}
+
+func TestSpeedCachePath(t *testing.T) {
+	tests := []struct {
+		speed float64
+		want  string
+	}{
+		{0.5, "song_0.50x.mp3"},
+		{0.25, "song_0.25x.mp3"},
+		{2, "song_2.00x.mp3"},
+	}
+	for _, tt := range tests {
+		got := SpeedCachePath(filepath.Join("songs", "My Song", "song.mp3"), tt.speed)
+		if want := filepath.Join("songs", "My Song", tt.want); got != want {
+			t.Errorf("SpeedCachePath(%v) = %q, want %q", tt.speed, got, want)
+		}
+	}
+}
+
+func TestAtempoFilter(t *testing.T) {
+	tests := []struct {
+		speed float64
+		want  string
+	}{
+		{0.5, "atempo=0.5"},
+		{0.25, "atempo=0.5,atempo=0.5"},
+		{2, "atempo=2"},
+	}
+	for _, tt := range tests {
+		if got := atempoFilter(tt.speed); got != tt.want {
+			t.Errorf("atempoFilter(%v) = %q, want %q", tt.speed, got, tt.want)
+		}
+	}
+}
+
+func TestStretchAudioWritesThroughTempFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "song.mp3")
+	if err := os.WriteFile(src, []byte("mp3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeRunner{run: func(_ string, args []string) ([]byte, error) {
+		out := args[len(args)-1]
+		if out == SpeedCachePath(src, 0.5) {
+			t.Errorf("ffmpeg wrote straight to the cache path")
+		}
+		return nil, os.WriteFile(out, []byte("stretched"), 0644)
+	}}
+	useFakeRunner(t, f)
+
+	got, err := StretchAudio(src, 0.5)
+	if err != nil {
+		t.Fatalf("StretchAudio: %v", err)
+	}
+	if got != SpeedCachePath(src, 0.5) {
+		t.Errorf("StretchAudio = %q, want %q", got, SpeedCachePath(src, 0.5))
+	}
+	if data, err := os.ReadFile(got); err != nil || string(data) != "stretched" {
+		t.Errorf("cache file = %q, %v; want the stretched audio", data, err)
+	}
+	if _, err := os.Stat(got + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+func TestStretchAudioFailureLeavesNoCache(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "song.mp3")
+	if err := os.WriteFile(src, []byte("mp3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeRunner{run: func(_ string, args []string) ([]byte, error) {
+		os.WriteFile(args[len(args)-1], []byte("trunc"), 0644)
+		return []byte("killed"), errors.New("exit status 1")
+	}}
+	useFakeRunner(t, f)
+
+	if _, err := StretchAudio(src, 0.5); err == nil {
+		t.Fatal("StretchAudio succeeded, want the ffmpeg error")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries after a failed stretch, want only the source", len(entries))
+	}
+}
@@ -0,0 +1,221 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"singAssist/internal/config"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// toneAmplitude is the reference tone level (full scale would drown the singer).
+const toneAmplitude = 0.3
+
+// sineReader is the io.Reader GenerateSineReader returns.
+type sineReader struct {
+	freq       float64
+	sampleRate int
+	n          int64
+}
+
+/*
+Read fills p with the next stereo frames of the sine.
+
+Input:
+  - p: []byte - Destination buffer
+
+Called by:
+  - The ebiten audio player reading the tone
+
+Task:
+  - Never run out, so the tone plays until it is stopped
+
+Logic:
+ 1. Fill whole 4-byte frames only: sample n is toneAmplitude × sin(2π·freq·n/sampleRate)
+    as 16-bit little-endian, the same value in both channels
+ 2. Keep the sample counter so the phase continues across reads
+
+Output:
+  - int: Bytes written (a multiple of 4)
+  - error: nil always
+*/
+func (r *sineReader) Read(p []byte) (int, error) {
+	frames := len(p) / 4
+	for i := 0; i < frames; i++ {
+		v := toneAmplitude * math.Sin(2*math.Pi*r.freq*float64(r.n)/float64(r.sampleRate))
+		s := uint16(int16(v * math.MaxInt16))
+		binary.LittleEndian.PutUint16(p[i*4:], s)
+		binary.LittleEndian.PutUint16(p[i*4+2:], s)
+		r.n++
+	}
+	return frames * 4, nil
+}
+
+/*
+GenerateSineReader returns an endless 16-bit stereo sine wave.
+
+Input:
+  - freq: float64 - Tone frequency in Hz
+  - sampleRate: int - Samples per second
+
+Called by:
+  - ReferenceTonePlayer.Play
+
+Task:
+  - Feed a continuous tone to an ebiten audio player
+
+Logic:
+ 1. Return a sineReader starting at phase 0
+
+Output:
+  - io.Reader: Infinite PCM stream at toneAmplitude
+*/
+func GenerateSineReader(freq float64, sampleRate int) io.Reader {
+	return &sineReader{freq: freq, sampleRate: sampleRate}
+}
+
+/*
+ReferenceTonePlayer plays a steady pitch to tune against (a pitch pipe).
+
+Fields:
+  - freq: Current tone frequency in Hz
+  - player: Ebiten player reading GenerateSineReader, nil while stopped
+*/
+type ReferenceTonePlayer struct {
+	freq   float64
+	player *audio.Player
+}
+
+/*
+NewReferenceTonePlayer creates a stopped reference tone.
+
+Input:
+  - freq: float64 - Starting frequency in Hz
+
+Called by:
+  - App.handleReferenceToneInput
+
+Task:
+  - Construct the no-audio mode pitch pipe
+
+Logic:
+ 1. Store freq; nothing plays until Play
+
+Output:
+  - *ReferenceTonePlayer: Stopped tone
+*/
+func NewReferenceTonePlayer(freq float64) *ReferenceTonePlayer {
+	return &ReferenceTonePlayer{freq: freq}
+}
+
+/*
+Play starts the tone at the current frequency.
+
+Input:
+  - None
+
+Called by:
+  - App.handleReferenceToneInput, SetFrequency
+
+Task:
+  - Sound the reference pitch
+
+Logic:
+ 1. Ignore if already playing
+ 2. Create a player on AudioContext from GenerateSineReader at
+    config.SampleRate and play it
+
+Output:
+  - error: Player creation error
+*/
+func (t *ReferenceTonePlayer) Play() error {
+	if t.player != nil {
+		return nil
+	}
+	p, err := AudioContext.NewPlayer(GenerateSineReader(t.freq, config.SampleRate))
+	if err != nil {
+		return err
+	}
+	p.Play()
+	t.player = p
+	return nil
+}
+
+/*
+Stop silences the tone.
+
+Input:
+  - None
+
+Called by:
+  - App.handleReferenceToneInput, App.cleanup, SetFrequency
+
+Task:
+  - Release the player
+
+Logic:
+ 1. Pause and close the player if there is one
+
+Output:
+  - None
+*/
+func (t *ReferenceTonePlayer) Stop() {
+	if t.player == nil {
+		return
+	}
+	t.player.Pause()
+	t.player.Close()
+	t.player = nil
+}
+
+/*
+SetFrequency changes the tone pitch.
+
+Input:
+  - freq: float64 - New frequency in Hz
+
+Called by:
+  - App.handleReferenceToneInput on +/-
+
+Task:
+  - Step the pitch pipe through the chromatic scale
+
+Logic:
+ 1. Store freq
+ 2. If playing: restart the player so the new reader is used
+
+Output:
+  - error: Player creation error
+*/
+func (t *ReferenceTonePlayer) SetFrequency(freq float64) error {
+	t.freq = freq
+	if t.player == nil {
+		return nil
+	}
+	t.Stop()
+	return t.Play()
+}
+
+/*
+Frequency returns the tone pitch in Hz.
+
+Input:
+  - None
+
+Called by:
+  - App.drawReferenceTone
+
+Task:
+  - Label the tone in the HUD
+
+Logic:
+ 1. Return freq
+
+Output:
+  - float64: Frequency in Hz
+*/
+func (t *ReferenceTonePlayer) Frequency() float64 {
+	return t.freq
+}
@@ -0,0 +1,43 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestGenerateSineReaderFollowsFormula(t *testing.T) {
+	const freq, rate = 440.0, 44100
+	r := GenerateSineReader(freq, rate)
+
+	// Read in uneven chunks: the phase must carry over between reads.
+	var pcm []byte
+	for _, size := range []int{4, 400, 1024, 36, 4096} {
+		buf := make([]byte, size)
+		n, err := r.Read(buf)
+		if err != nil || n != size {
+			t.Fatalf("Read(%d bytes) = %d, %v", size, n, err)
+		}
+		pcm = append(pcm, buf...)
+	}
+
+	for _, n := range []int{0, 25, 26, 100, 101, 1000, 1388} {
+		want := int16(toneAmplitude * math.Sin(2*math.Pi*freq*float64(n)/rate) * math.MaxInt16)
+		left := int16(binary.LittleEndian.Uint16(pcm[4*n:]))
+		right := int16(binary.LittleEndian.Uint16(pcm[4*n+2:]))
+		if left != want || right != want {
+			t.Errorf("sample %d = %d/%d, want %d in both channels", n, left, right, want)
+		}
+	}
+}
+
+func TestGenerateSineReaderWholeFrames(t *testing.T) {
+	r := GenerateSineReader(440, 44100)
+	if n, err := r.Read(make([]byte, 7)); n != 4 || err != nil {
+		t.Errorf("Read(7 bytes) = %d, %v; want one 4-byte frame", n, err)
+	}
+	if n, err := io.ReadFull(r, make([]byte, 1<<20)); n != 1<<20 || err != nil {
+		t.Errorf("reading a megabyte = %d, %v; the tone should never end", n, err)
+	}
+}
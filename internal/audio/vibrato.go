@@ -0,0 +1,161 @@
+package audio
+
+import (
+	"math"
+
+	"singAssist/internal/config"
+)
+
+/*
+VibratoInfo describes the vibrato in the last second of singing.
+
+Fields:
+  - Rate: Oscillation frequency in Hz (config.VibratoMinHz..VibratoMaxHz)
+  - DepthSemitones: Peak deviation from the mean pitch in semitones
+  - Present: Whether a clear vibrato was found (Rate and Depth are 0 otherwise)
+*/
+type VibratoInfo struct {
+	Rate           float64
+	DepthSemitones float64
+	Present        bool
+}
+
+/*
+VibratoDetector finds periodic pitch oscillation in a rolling window of pitches.
+
+Fields:
+  - history: Circular buffer of the last second of pitches, in semitones (NaN = silence)
+  - cursor: Next write position in history
+  - filled: Number of pitches written so far (capped at len(history))
+  - updateRate: Pitches per second passed to Update
+  - info: Result of the last Update
+*/
+type VibratoDetector struct {
+	history    []float64
+	cursor     int
+	filled     int
+	updateRate float64
+	info       VibratoInfo
+}
+
+/*
+NewVibratoDetector creates a detector for pitches arriving at a fixed rate.
+
+Input:
+  - updateRate: float64 - Calls to Update per second (mic buffers per second)
+
+Called by:
+  - NewMicHandler
+
+Task:
+  - Size the one-second window for the mic buffer size in use
+
+Logic:
+ 1. History length = updateRate rounded up (at least 4)
+
+Output:
+  - *VibratoDetector: Empty detector
+*/
+func NewVibratoDetector(updateRate float64) *VibratoDetector {
+	return &VibratoDetector{
+		history:    make([]float64, max(4, int(math.Ceil(updateRate)))),
+		updateRate: updateRate,
+	}
+}
+
+/*
+Update adds one pitch and re-evaluates the window.
+
+Input:
+  - pitch: float64 - Unsmoothed pitch in Hz (<= 0 for silence); smoothing would
+    average the oscillation away
+
+Called by:
+  - detectChannel next to the smoother (first singer only)
+
+Task:
+  - Keep VibratoInfo current for the HUD
+
+Logic:
+ 1. Store the pitch as a MIDI note number (NaN for silence)
+ 2. Until the window is full, or if it contains silence: no vibrato
+ 3. Subtract the window mean and evaluate the DFT at every 0.1Hz from
+    config.VibratoMinHz to VibratoMaxHz
+ 4. Depth = 2|X(f)|/N at the strongest frequency; vibrato is present when it is
+    at least config.VibratoMinDepth and that sinusoid holds at least half of
+    the window's variance (a glide or random wobble spreads it out)
+
+Output:
+  - VibratoInfo: Result for the current window
+*/
+func (v *VibratoDetector) Update(pitch float64) VibratoInfo {
+	note := math.NaN()
+	if pitch > 0 {
+		note = 69 + 12*math.Log2(pitch/440)
+	}
+	v.history[v.cursor] = note
+	v.cursor = (v.cursor + 1) % len(v.history)
+	v.filled = min(v.filled+1, len(v.history))
+
+	v.info = VibratoInfo{}
+	if v.filled < len(v.history) {
+		return v.info
+	}
+
+	n := len(v.history)
+	var mean float64
+	for _, x := range v.history {
+		if math.IsNaN(x) {
+			return v.info
+		}
+		mean += x
+	}
+	mean /= float64(n)
+
+	var variance float64
+	for _, x := range v.history {
+		variance += (x - mean) * (x - mean)
+	}
+	variance /= float64(n)
+
+	bestRate, bestAmp := 0.0, 0.0
+	for f := config.VibratoMinHz; f <= config.VibratoMaxHz+1e-9; f += 0.1 {
+		var re, im float64
+		for i := 0; i < n; i++ {
+			x := v.history[(v.cursor+i)%n] - mean
+			w := 2 * math.Pi * f * float64(i) / v.updateRate
+			re += x * math.Cos(w)
+			im -= x * math.Sin(w)
+		}
+		if amp := 2 * math.Hypot(re, im) / float64(n); amp > bestAmp {
+			bestRate, bestAmp = f, amp
+		}
+	}
+
+	if bestAmp >= config.VibratoMinDepth && bestAmp*bestAmp/2 >= 0.5*variance {
+		v.info = VibratoInfo{Rate: bestRate, DepthSemitones: bestAmp, Present: true}
+	}
+	return v.info
+}
+
+/*
+Info returns the result of the last Update.
+
+Input:
+  - None
+
+Called by:
+  - MicHandler.CurrentVibrato
+
+Task:
+  - Read the vibrato without adding a pitch
+
+Logic:
+ 1. Return the stored VibratoInfo
+
+Output:
+  - VibratoInfo: Last result
+*/
+func (v *VibratoDetector) Info() VibratoInfo {
+	return v.info
+}
@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// micRate is how often the mic loop hands a pitch to the vibrato detector.
+const micRate = float64(config.SampleRate) / config.BufferSize
+
+// feedVibrato sends two seconds of pitch(t) to a new detector and returns its result.
+func feedVibrato(pitch func(t float64) float64) VibratoInfo {
+	v := NewVibratoDetector(micRate)
+	var info VibratoInfo
+	for i := 0; float64(i) < 2*micRate; i++ {
+		info = v.Update(pitch(float64(i) / micRate))
+	}
+	return info
+}
+
+func TestVibratoDetectorSineModulation(t *testing.T) {
+	// 440 Hz ± 20 Hz at 6 Hz: a depth of 12·log2(460/440) ≈ 0.77 semitones.
+	info := feedVibrato(func(t float64) float64 { return 440 + 20*math.Sin(2*math.Pi*6*t) })
+	if !info.Present {
+		t.Fatal("vibrato not detected")
+	}
+	if math.Abs(info.Rate-6) > 0.3 {
+		t.Errorf("rate = %.1f Hz, want 6", info.Rate)
+	}
+	if want := 12 * math.Log2(460.0/440); math.Abs(info.DepthSemitones-want) > 0.15 {
+		t.Errorf("depth = %.2f semitones, want about %.2f", info.DepthSemitones, want)
+	}
+}
+
+func TestVibratoDetectorRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		pitch func(t float64) float64
+	}{
+		{"steady note", func(float64) float64 { return 440 }},
+		{"slow glide", func(t float64) float64 { return 440 * math.Pow(2, t/12) }},
+		{"1 Hz wobble", func(t float64) float64 { return 440 + 20*math.Sin(2*math.Pi*t) }},
+		{"too shallow", func(t float64) float64 { return 440 + 1*math.Sin(2*math.Pi*6*t) }},
+		{"silence in the window", func(t float64) float64 {
+			if t > 1.5 && t < 1.6 {
+				return 0
+			}
+			return 440 + 20*math.Sin(2*math.Pi*6*t)
+		}},
+	}
+	for _, tt := range tests {
+		if info := feedVibrato(tt.pitch); info.Present {
+			t.Errorf("%s: detected vibrato %+v", tt.name, info)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package audio
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+)
+
+// songWatcher is the io.Closer WatchSongDir returns.
+type songWatcher struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+/*
+Close stops the watcher goroutine.
+
+Input:
+  - None
+
+Called by:
+  - App.cleanup
+
+Task:
+  - Let the watcher be stopped more than once
+
+Logic:
+ 1. Close the stop channel once (does not wait, so it is safe under App.mu)
+
+Output:
+  - error: nil always
+*/
+func (w *songWatcher) Close() error {
+	w.once.Do(func() { close(w.stop) })
+	return nil
+}
+
+/*
+WatchSongDir calls onChanged when a song's audio file is replaced.
+
+Input:
+  - songDir: string - Song folder (e.g., "songs/MySong")
+  - onChanged: func() - Called from the watcher goroutine after a change
+
+Called by:
+  - App.startGame
+
+Task:
+  - Stop a re-downloaded or replaced song.mp3 from being played against its stale pitch cache
+
+Logic:
+ 1. Stat config.GetSongPaths(songDir).SongFile for its starting modification time and size
+ 2. Every config.SongWatchInterval, stat it again (polling, so no extra dependency;
+    a missing file, e.g. mid-download, is skipped)
+ 3. On a change: remember the new state, delete the song's pitch caches
+    (pitch_cache_<mode>*.bin) and call onChanged; the separated tracks are
+    now older than the song, so the reload separates them again (stemIsStale)
+
+Output:
+  - io.Closer: Stops the watcher
+  - error: Stat error for the song file
+*/
+func WatchSongDir(songDir string, onChanged func()) (io.Closer, error) {
+	paths := config.GetSongPaths(songDir)
+	last, err := os.Stat(paths.SongFile)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &songWatcher{stop: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(config.SongWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(paths.SongFile)
+			if err != nil || (info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+				continue
+			}
+			last = info
+
+			logging.Infof("%s changed, clearing its pitch cache", paths.SongFile)
+			removePitchCaches(paths.PitchCacheFile)
+			onChanged()
+		}
+	}()
+	return w, nil
+}
+
+/*
+removePitchCaches deletes every mode's pitch cache for a song.
+
+Input:
+  - cacheFile: string - The song's config.SongPaths.PitchCacheFile
+
+Called by:
+  - WatchSongDir when the song file changes
+
+Task:
+  - Force the next load to re-analyze
+
+Logic:
+ 1. Glob the pitchCachePath pattern (pitch_cache_*.bin) and remove each match
+
+Output:
+  - None (logs failures)
+*/
+func removePitchCaches(cacheFile string) {
+	ext := filepath.Ext(cacheFile)
+	matches, _ := filepath.Glob(strings.TrimSuffix(cacheFile, ext) + "_*" + ext)
+	for _, m := range matches {
+		if err := os.Remove(m); err != nil {
+			logging.Warnf("Could not remove %s: %v", m, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"singAssist/internal/config"
+)
+
+func TestWatchSongDirFiresOnChange(t *testing.T) {
+	dir := t.TempDir()
+	paths := config.GetSongPaths(dir)
+	if err := os.WriteFile(paths.SongFile, []byte("old song"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache := filepath.Join(dir, "pitch_cache_vocals.bin")
+	os.WriteFile(cache, []byte("stale"), 0644)
+
+	changed := make(chan struct{}, 1)
+	w, err := WatchSongDir(dir, func() { changed <- struct{}{} })
+	if err != nil {
+		t.Fatalf("WatchSongDir: %v", err)
+	}
+	defer w.Close()
+
+	if err := os.WriteFile(paths.SongFile, []byte("re-downloaded song"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("onChanged did not fire within 2 seconds")
+	}
+	if _, err := os.Stat(cache); !os.IsNotExist(err) {
+		t.Errorf("pitch cache still there after the change (%v)", err)
+	}
+
+	// Closing twice is fine, and a closed watcher stays quiet.
+	w.Close()
+	w.Close()
+	os.WriteFile(paths.SongFile, []byte("replaced again"), 0644)
+	select {
+	case <-changed:
+		t.Error("onChanged fired after Close")
+	case <-time.After(config.SongWatchInterval + 200*time.Millisecond):
+	}
+}
+
+func TestWatchSongDirMissingSong(t *testing.T) {
+	if _, err := WatchSongDir(t.TempDir(), func() {}); err == nil {
+		t.Error("WatchSongDir succeeded without a song file")
+	}
+}
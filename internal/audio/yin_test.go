@@ -0,0 +1,53 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestDetectPitchYIN(t *testing.T) {
+	signals := []struct {
+		name string
+		amps []float64
+	}{
+		{"sine", []float64{1}},
+		{"vowel", []float64{1, 0.5, 0.33, 0.25, 0.2}},
+		// Octave-error bait: the 2nd or 3rd harmonic is louder than the fundamental.
+		{"strong 2nd harmonic", []float64{0.3, 1, 0.6, 0.4}},
+		{"strong 3rd harmonic", []float64{0.5, 0.2, 1, 0.3}},
+	}
+	for _, sig := range signals {
+		for _, freq := range []float64{220, 440, 880} {
+			got, confidence := detectPitchYIN(synthTone(freq, sig.amps), 85, 1100)
+			if math.Abs(centsOff(got, freq)) > 5 {
+				t.Errorf("%s at %v Hz: detected %.2f Hz, want within 5 cents", sig.name, freq, got)
+			}
+			if confidence < 0.9 {
+				t.Errorf("%s at %v Hz: confidence %.2f, want >= 0.9", sig.name, freq, confidence)
+			}
+		}
+	}
+}
+
+func TestDetectPitchYINFloor(t *testing.T) {
+	got, _ := detectPitchYIN(synthTone(50, []float64{1}), 40, 2000)
+	if math.Abs(centsOff(got, 50)) > 5 {
+		t.Errorf("50 Hz detected as %.2f Hz, want within 5 cents", got)
+	}
+
+	// 40 Hz needs more than half of the 2048-sample buffer per period: the
+	// detector cannot go below SampleRate / 1024.
+	floor := float64(config.SampleRate) / float64(config.BufferSize/2)
+	got, _ = detectPitchYIN(synthTone(40, []float64{1}), 40, 2000)
+	if got < floor-0.01 {
+		t.Errorf("40 Hz detected as %.2f Hz, below the %.2f Hz floor", got, floor)
+	}
+}
+
+func TestDetectPitchYINSilence(t *testing.T) {
+	if got, confidence := detectPitchYIN(make([]float32, config.BufferSize), 85, 1100); got != 0 || confidence != 0 {
+		t.Errorf("silence = %.2f Hz (confidence %.2f), want 0", got, confidence)
+	}
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 const (
@@ -14,14 +16,537 @@ const (
 	PixelsPerSec        = 150.0
 	MaxUserPitchHistory = 30.0
 	SongsDir            = "songs"
-	AudioLatencyMs      = 150.0
+
+	// AudioLatencyMs is the mic latency compensation used until Calibrate
+	// Latency has measured this machine (see GetAudioLatencyMs).
+	AudioLatencyMs = 150.0
+
+	// MinPitchConfidence is the YIN periodicity (1 - normalized difference) a
+	// song frame needs to count as pitched; lower frames (drums, noise) are
+	// treated as silence.
+	MinPitchConfidence = 0.45
+
+	// SilencePercentile is the song-energy percentile treated as background
+	// noise when calibrating the silence threshold.
+	SilencePercentile = 10.0
+
+	// NoiseGateAlpha is the per-frame EMA weight the mic noise gate gives to
+	// each new unpitched frame (~46s time constant at 2048-sample buffers).
+	NoiseGateAlpha = 0.001
+
+	// DefaultSensitivity is the safety margin the mic noise gate puts over
+	// the calibrated noise level; the start screen slider moves it within
+	// MinSensitivity..MaxSensitivity (a lower margin suits quiet rooms).
+	DefaultSensitivity = 1.5
+	MinSensitivity     = 0.5
+	MaxSensitivity     = 5.0
+
+	// YinThreshold is the normalized difference below which YIN accepts the
+	// first dip as the fundamental period.
+	YinThreshold = 0.15
+
+	// LockToleranceCents and LockChargeSec control the tuning-lock indicator:
+	// the user must stay within ±LockToleranceCents for LockChargeSec to lock.
+	LockToleranceCents = 15.0
+	LockChargeSec      = 1.5
+
+	// InstrumentalGapFrames and FullMixGapFrames are the longest silence gaps
+	// (in 10ms frames) bridged in the song pitch line for those modes.
+	InstrumentalGapFrames = 20
+	FullMixGapFrames      = 20
+
+	// OctaveWindowFrames is the median window (in 10ms frames) used to catch
+	// half/double-frequency octave errors in the song pitch.
+	OctaveWindowFrames = 51
+
+	// ChordFFTSize is the window and FFT length DetectChord uses (186ms,
+	// ~5.4 Hz bins at 44100Hz: a 30ms chunk cannot separate chord notes);
+	// ChordNotes is how many peaks it keeps, and peaks weaker than
+	// ChordPeakRatio of the strongest are ignored.
+	ChordFFTSize   = 8192
+	ChordNotes     = 3
+	ChordPeakRatio = 0.2
+
+	// ChordHopFrames is how often (in 10ms frames) the song chord is detected.
+	ChordHopFrames = 9
+
+	// ChordMinFreq and ChordMaxFreq bound the chord search in Hz.
+	ChordMinFreq = 80.0
+	ChordMaxFreq = 1000.0
+
+	// ScrollSpeedStep is how much Ctrl +/- changes the pitch graph scroll
+	// speed (pixels per second), within MinScrollSpeed..MaxScrollSpeed.
+	ScrollSpeedStep = 25.0
+	MinScrollSpeed  = 25.0
+	MaxScrollSpeed  = 400.0
+
+	// MinPlaybackSpeed and MaxPlaybackSpeed bound the playback speed
+	// Ctrl+Down / Ctrl+Up halve and double (1 = original tempo).
+	MinPlaybackSpeed = 0.25
+	MaxPlaybackSpeed = 2.0
+
+	// DefaultMetronomeBPM is the click track tempo until Ctrl+[ / Ctrl+]
+	// change it by MetronomeBPMStep, within MinMetronomeBPM..MaxMetronomeBPM.
+	DefaultMetronomeBPM = 100.0
+	MetronomeBPMStep    = 5.0
+	MinMetronomeBPM     = 30.0
+	MaxMetronomeBPM     = 300.0
+
+	// ClickFreq and ClickMs are the pitch and length of one metronome click.
+	ClickFreq = 1000.0
+	ClickMs   = 10
+
+	// ReferenceToneMidi is the note the no-audio reference tone starts on (A4).
+	ReferenceToneMidi = 69
+
+	// RangeTestStartMidi is where the vocal range test starts climbing (C3);
+	// it climbs to at most RangeTestMaxMidi (C6) and descends to at least
+	// RangeTestMinMidi (C2).
+	RangeTestStartMidi = 48
+	RangeTestMinMidi   = 36
+	RangeTestMaxMidi   = 84
+
+	// RangeTestToneDuration is how long each range test note sounds;
+	// RangeTestListenDuration is the silence after it in which the user
+	// sings it back (the tone is off so the mic does not hear it).
+	RangeTestToneDuration   = 1 * time.Second
+	RangeTestListenDuration = 2 * time.Second
+
+	// QuizToneDuration is how long the interval quiz plays the root note,
+	// QuizListenDuration how long the user then has to sing the interval and
+	// QuizFeedbackDuration how long the verdict shows before the next question.
+	QuizToneDuration     = 1 * time.Second
+	QuizListenDuration   = 3 * time.Second
+	QuizFeedbackDuration = 2 * time.Second
+
+	// QuizToleranceCents is how close (in cents) a sung answer must be to the
+	// quiz target to count as correct.
+	QuizToleranceCents = 50.0
+
+	// VocalRangeMargin widens the saved vocal range (semitones on each side)
+	// to get the frequency range mic pitch detection searches in vocal modes.
+	VocalRangeMargin = 4.0
+
+	// MicMonitorBuffer bounds the delay of mic monitoring: the monitor stream
+	// drops older audio beyond it and the monitor player buffers this much.
+	MicMonitorBuffer = 60 * time.Millisecond
+
+	// SightReadingMultiplier scales the results-screen points of a run sung
+	// with the upcoming song line hidden (H toggles sight reading).
+	SightReadingMultiplier = 1.5
+
+	// WebHUDInterval is how often the web HUD (-web-hud) pushes an update.
+	WebHUDInterval = 100 * time.Millisecond
+
+	// LiveScoreInterval is how often the web HUD score is recomputed; scoring
+	// walks the whole run, so it is not redone for every event and client.
+	LiveScoreInterval = time.Second
+
+	// MinVoicedDurationMs is how long a pitch must hold (voiced, within
+	// 2 semitones) before the mic reports it; shorter voiced runs in the song
+	// analysis are dropped too. Filters out noise blips.
+	MinVoicedDurationMs = 80.0
+
+	// BreathMinMs and BreathMaxMs bound the silence counted as a breath; it
+	// must follow at least BreathMinPhraseMs of singing. Shorter gaps are
+	// detection dropouts, longer ones pauses.
+	BreathMinMs       = 50.0
+	BreathMaxMs       = 300.0
+	BreathMinPhraseMs = 500.0
+
+	// FormantSampleRate is the rate mic buffers are decimated to before LPC
+	// formant estimation, FormantLPCOrder the predictor order there, and
+	// buffers below PhonemeMinEnergy get no phoneme label.
+	FormantSampleRate = 11025
+	FormantLPCOrder   = 12
+	PhonemeMinEnergy  = 0.0005
+
+	// Formant1FFTSize is the DFT length of the F1 tracker (at FormantSampleRate),
+	// which looks for the first spectral envelope peak above Formant1MinHz and
+	// below both Formant1MaxHz and the sung fundamental.
+	Formant1FFTSize = 512
+	Formant1MinHz   = 300.0
+	Formant1MaxHz   = 1000.0
+
+	// VibratoMinHz..VibratoMaxHz is the vibrato rate range the mic vibrato
+	// detector searches; oscillations shallower than VibratoMinDepth
+	// semitones are not reported.
+	VibratoMinHz    = 4.0
+	VibratoMaxHz    = 8.0
+	VibratoMinDepth = 0.1
+
+	// MinBPM and MaxBPM bound the tempo audio.DetectBPM searches for.
+	MinBPM = 60.0
+	MaxBPM = 200.0
+
+	// DefaultHitTolerance is how close (in semitones) the user must be to the
+	// song to count a hit until a song's settings.json says otherwise; the
+	// start screen's +/- change it by HitToleranceStep within
+	// MinHitTolerance..MaxHitTolerance.
+	DefaultHitTolerance = 0.7
+	HitToleranceStep    = 0.1
+	MinHitTolerance     = 0.1
+	MaxHitTolerance     = 3.0
+
+	// BeatPulseWindow is how close (in seconds) playback must be to a beat of
+	// the detected tempo for the "now" line to pulse.
+	BeatPulseWindow = 0.030
+
+	// IntonationGraphSeconds is how much of the user's recent pitch the
+	// intonation graph (I) plots.
+	IntonationGraphSeconds = 30.0
+
+	// AnalysisProgressFrames is how many 10ms frames song analysis finishes
+	// between progress updates.
+	AnalysisProgressFrames = 1000
+
+	// RingBlockSize is the mic block size (11ms) in low-latency mode, where
+	// the PortAudio callback writes blocks into a RingBuffer of RingBlocks.
+	// Pitch detection still looks at the last BufferSize samples (YIN needs
+	// two periods of the lowest note), but that window now advances in
+	// RingBlockSize hops, so a new pitch arrives every 11ms.
+	RingBlockSize = 512
+	RingBlocks    = 8
+
+	// RecordingMaxSeconds caps a session recording (an hour is ~300MB of
+	// 16-bit mono); later audio is not written.
+	RecordingMaxSeconds = 3600
+
+	// SongWatchInterval is how often audio.WatchSongDir checks the song file.
+	SongWatchInterval = time.Second
+
+	// ExportImageW and ExportImageH are the size of the Shift+S session PNG.
+	ExportImageW = 1920
+	ExportImageH = 400
+
+	// WaveformBins is the resolution of the song overview bar (stretched to
+	// the window width when drawn).
+	WaveformBins = 1000
+
+	// HistoryFile is the practice log kept in SongsDir; it is rotated to
+	// HistoryFile+".1" once it grows past HistoryMaxBytes.
+	HistoryFile     = "history.jsonl"
+	HistoryMaxBytes = 1 << 20
+)
+
+// ShowSemitoneGrid is whether the pitch graph starts with the semitone grid
+// shown (N toggles it while playing).
+const ShowSemitoneGrid = true
+
+// SongExtensions are the original-audio formats a song folder may hold, in
+// the order GetSongPaths looks for them.
+var SongExtensions = []string{".mp3", ".flac", ".ogg", ".wav"}
+
+// ConvertExtensions are audio formats that are imported by converting them to
+// song.mp3 with ffmpeg (see audio.ConvertToMP3).
+var ConvertExtensions = []string{".m4a", ".aac"}
+
+// SmoothingMode selects how the microphone pitch is smoothed.
+type SmoothingMode string
+
+const (
+	// SmoothMean averages the recent pitches (the default).
+	SmoothMean SmoothingMode = "mean"
+	// SmoothMedian takes their median, which ignores single-buffer spikes.
+	SmoothMedian SmoothingMode = "median"
+
+	// SmoothingEnv is the environment variable that selects the SmoothingMode.
+	SmoothingEnv = "SINGASSIST_SMOOTH"
+)
+
+/*
+GetSmoothingMode returns the microphone pitch smoothing mode.
+
+Input:
+  - None (reads the SINGASSIST_SMOOTH environment variable)
+
+Called by:
+  - audio.newPitchSmoother when a MicHandler is created
+
+Task:
+  - Let users try median smoothing without a rebuild
+
+Logic:
+ 1. "median" (any case, surrounding spaces ignored): SmoothMedian
+ 2. Anything else, including unset: SmoothMean
+
+Output:
+  - SmoothingMode: SmoothMean or SmoothMedian
+*/
+func GetSmoothingMode() SmoothingMode {
+	if SmoothingMode(strings.ToLower(strings.TrimSpace(os.Getenv(SmoothingEnv)))) == SmoothMedian {
+		return SmoothMedian
+	}
+	return SmoothMean
+}
+
+// StereoMicMode is how a two-channel microphone is turned into the mono
+// signal the pitch detector analyses.
+type StereoMicMode string
+
+const (
+	// StereoMicOff captures one channel (the default).
+	StereoMicOff StereoMicMode = ""
+	// StereoMicMix captures both channels and averages them.
+	StereoMicMix StereoMicMode = "mix"
+	// StereoMicLoudest captures both channels and keeps the one with more
+	// energy in each buffer.
+	StereoMicLoudest StereoMicMode = "loudest"
+
+	// StereoMicEnv is the environment variable that selects the StereoMicMode.
+	StereoMicEnv = "SINGASSIST_STEREO_MIC"
+)
+
+/*
+GetStereoMicMode returns whether and how the microphone is captured in stereo.
+
+Input:
+  - None (reads the SINGASSIST_STEREO_MIC environment variable)
+
+Called by:
+  - audio.NewMicHandler
+
+Task:
+  - Let users with a stereo mic use both channels without a rebuild
+
+Logic:
+ 1. "loudest" (any case, surrounding spaces ignored): StereoMicLoudest
+ 2. "mix", "1", "true" or "on": StereoMicMix
+ 3. Anything else, including unset: StereoMicOff
+
+Output:
+  - StereoMicMode: StereoMicOff, StereoMicMix or StereoMicLoudest
+*/
+func GetStereoMicMode() StereoMicMode {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(StereoMicEnv))) {
+	case "loudest":
+		return StereoMicLoudest
+	case "mix", "1", "true", "on":
+		return StereoMicMix
+	}
+	return StereoMicOff
+}
+
+// PitchAlgorithm selects the pitch detector used for the song and the mic.
+type PitchAlgorithm string
+
+const (
+	// AlgoYIN is the YIN difference-function detector (the default).
+	AlgoYIN PitchAlgorithm = "yin"
+	// AlgoAutoCorrelation picks the lag with the highest autocorrelation.
+	AlgoAutoCorrelation PitchAlgorithm = "autocorrelation"
+	// AlgoFFTHPS takes the peak of the FFT harmonic product spectrum.
+	AlgoFFTHPS PitchAlgorithm = "fft"
+
+	// PitchAlgoEnv is the environment variable that selects the PitchAlgorithm.
+	PitchAlgoEnv = "SINGASSIST_PITCH_ALGO"
+)
+
+/*
+GetPitchAlgorithm returns the pitch detection algorithm to use.
+
+Input:
+  - None (reads the SINGASSIST_PITCH_ALGO environment variable)
+
+Called by:
+  - audio package initialisation (pitchAlgorithm)
+
+Task:
+  - Let users compare pitch detectors without a rebuild
+
+Logic:
+ 1. "autocorrelation" or "acf" (any case, surrounding spaces ignored): AlgoAutoCorrelation
+ 2. "fft" or "hps": AlgoFFTHPS
+ 3. Anything else, including unset: AlgoYIN
+
+Output:
+  - PitchAlgorithm: AlgoYIN, AlgoAutoCorrelation or AlgoFFTHPS
+*/
+func GetPitchAlgorithm() PitchAlgorithm {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(PitchAlgoEnv))) {
+	case "autocorrelation", "acf":
+		return AlgoAutoCorrelation
+	case "fft", "hps":
+		return AlgoFFTHPS
+	}
+	return AlgoYIN
+}
+
+// SeparatorBackend is the Python tool that splits vocals from accompaniment.
+type SeparatorBackend int
+
+const (
+	BackendSpleeter SeparatorBackend = iota
+	BackendDemucs
+)
+
+// SeparatorEnv is the environment variable that forces a SeparatorBackend
+// ("spleeter" or "demucs").
+const SeparatorEnv = "SINGASSIST_SEPARATOR"
+
+/*
+String returns the backend's name (also its Python package name).
+
+Input:
+  - None
+
+Called by:
+  - GetSeparatorBackend, audio.runSeparation logging
+
+Task:
+  - Name the backend
+
+Logic:
+ 1. BackendDemucs: "demucs", otherwise "spleeter"
+
+Output:
+  - string: "spleeter" or "demucs"
+*/
+func (b SeparatorBackend) String() string {
+	if b == BackendDemucs {
+		return "demucs"
+	}
+	return "spleeter"
+}
+
+/*
+Script returns the separation script run for the backend.
+
+Input:
+  - None
+
+Called by:
+  - audio.runSeparation
+
+Task:
+  - Map backend to its script in the working directory
+
+Logic:
+ 1. "separate_<name>.py"
+
+Output:
+  - string: "separate_spleeter.py" or "separate_demucs.py"
+*/
+func (b SeparatorBackend) Script() string {
+	return "separate_" + b.String() + ".py"
+}
+
+/*
+GetSeparatorBackend picks the separation backend.
+
+Input:
+  - hasPackage: func(string) bool - Reports whether a Python package can be imported
+
+Called by:
+  - audio.runSeparation
+
+Task:
+  - Use whichever separator the user has installed, unless they chose one
+
+Logic:
+ 1. SINGASSIST_SEPARATOR set to "demucs" or "spleeter" (any case): use it
+ 2. Otherwise demucs if importable (better quality)
+ 3. Otherwise spleeter (which also gives the clearest error when neither is installed)
+
+Output:
+  - SeparatorBackend: Backend to run
+*/
+func GetSeparatorBackend(hasPackage func(string) bool) SeparatorBackend {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(SeparatorEnv))) {
+	case BackendDemucs.String():
+		return BackendDemucs
+	case BackendSpleeter.String():
+		return BackendSpleeter
+	}
+	if hasPackage(BackendDemucs.String()) {
+		return BackendDemucs
+	}
+	return BackendSpleeter
+}
+
+// SeparateScriptEnv is the environment variable naming a custom separation
+// script; SeparateScriptFile holds the same path when the variable is unset.
+const (
+	SeparateScriptEnv  = "SINGASSIST_SEPARATE_SCRIPT"
+	SeparateScriptFile = "config/separate_script.txt"
 )
 
+/*
+GetSeparateScript returns the user-supplied separation script, if any.
+
+Input:
+  - None (reads SINGASSIST_SEPARATE_SCRIPT and SeparateScriptFile)
+
+Called by:
+  - audio.runSeparation
+
+Task:
+  - Let users run a demucs wrapper, custom model or batch separator without
+    changing the source
+
+Logic:
+ 1. SINGASSIST_SEPARATE_SCRIPT, trimmed, if set
+ 2. Otherwise the trimmed contents of SeparateScriptFile, if it exists
+ 3. Otherwise "" (use the backend's own script, see SeparatorBackend.Script)
+
+Output:
+  - string: Script path, "" when none was configured
+*/
+func GetSeparateScript() string {
+	if script := strings.TrimSpace(os.Getenv(SeparateScriptEnv)); script != "" {
+		return script
+	}
+	data, err := os.ReadFile(SeparateScriptFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// LowLatencyEnv is the environment variable that switches the microphone to
+// a callback stream feeding a RingBuffer of RingBlockSize blocks ("1" or "on").
+const LowLatencyEnv = "SINGASSIST_LOWLATENCY"
+
+/*
+LowLatencyMic reports whether the microphone should use the callback stream.
+
+Input:
+  - None (reads the SINGASSIST_LOWLATENCY environment variable)
+
+Called by:
+  - audio.NewMicHandler
+
+Task:
+  - Let users try 11ms analysis blocks without a rebuild
+
+Logic:
+ 1. "1", "true" or "on" (any case, surrounding spaces ignored): true
+ 2. Anything else, including unset: false
+
+Output:
+  - bool: true for the ring-buffered callback stream
+*/
+func LowLatencyMic() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(LowLatencyEnv))) {
+	case "1", "true", "on":
+		return true
+	}
+	return false
+}
+
+// HostAPIEnv is the environment variable that picks the PortAudio host API
+// for the microphone (e.g. "asio", "wasapi"; "default" for the system default).
+const HostAPIEnv = "SINGASSIST_HOSTAPI"
+
+// PreferredHostAPI is HostAPIEnv, lower-cased and trimmed ("" when unset: use
+// the build default, ASIO with the asio build tag).
+var PreferredHostAPI = strings.ToLower(strings.TrimSpace(os.Getenv(HostAPIEnv)))
+
 /*
 GetPythonPath returns the absolute path to the Python executable from the virtual environment.
 
 Input:
-  - None (reads from venv_path.txt file in current directory)
+  - None (reads VenvPathFile, venv_path.txt in the current directory)
 
 Called by:
   - audio.LoadAndAnalyzeSong when running Python separator script
@@ -34,15 +559,15 @@ Task:
 Logic:
  1. Read venv_path.txt file
  2. If file doesn't exist or is empty, return default "python3"
- 3. If path is relative, join with current working directory
- 4. Construct full path to bin/python
- 5. Verify file exists, return path or fallback to "python3"
+ 3. Resolve <venv>/bin/python with venvPython (relative venvs are joined
+    with the current working directory)
+ 4. Verify file exists, return path or fallback to "python3"
 
 Output:
   - string: Absolute path to Python executable, or "python3" if not found
 */
 func GetPythonPath() string {
-	venvBytes, err := os.ReadFile("venv_path.txt")
+	venvBytes, err := os.ReadFile(VenvPathFile)
 	if err != nil {
 		return "python3"
 	}
@@ -52,14 +577,7 @@ func GetPythonPath() string {
 		return "python3"
 	}
 
-	if !filepath.IsAbs(venvPath) {
-		cwd, err := os.Getwd()
-		if err == nil {
-			venvPath = filepath.Join(cwd, venvPath)
-		}
-	}
-
-	pythonPath := filepath.Join(venvPath, "bin", "python")
+	pythonPath := venvPython(venvPath)
 	if _, err := os.Stat(pythonPath); err == nil {
 		return pythonPath
 	}
@@ -72,15 +590,46 @@ SongPaths holds all file paths for a song folder.
 
 Fields:
   - Dir: Base directory path (e.g., "songs/MySong")
-  - SongFile: Path to original audio (e.g., "songs/MySong/song.mp3")
+  - SongFile: Path to original audio (e.g., "songs/MySong/song.mp3" or song.flac/song.ogg/song.wav)
   - VocalsFile: Path to separated vocals (e.g., "songs/MySong/vocals.mp3")
   - AccompFile: Path to separated accompaniment (e.g., "songs/MySong/accompaniment.mp3")
+  - ScoresFile: Path to saved session scores (e.g., "songs/MySong/scores.json")
+  - BestSessionFile: Pitch trail of the best-scoring run, shown as a ghost (e.g., "songs/MySong/best_session.json")
+  - MidiFile: Optional reference melody for no-audio practice (e.g., "songs/MySong/reference.mid")
+  - LoopFile: Saved practice loop points (e.g., "songs/MySong/loop.json")
+  - WarmupFile: Last warmup exercise settings (e.g., "songs/MySong/warmup.json")
+  - HarmonyFile: Target harmony offsets in semitones for harmony mode (e.g., "songs/MySong/harmony.json")
+  - PitchCSVFile: Song pitch written by -analyze-only (e.g., "songs/MySong/pitch.csv")
+  - LyricsFile: Optional timed lyrics in LRC format (e.g., "songs/MySong/lyrics.lrc")
+  - DifficultyFile: Star rating computed from the song pitch (e.g., "songs/MySong/difficulty.json")
+  - MetadataFile: Facts detected from the audio, such as the tempo (e.g., "songs/MySong/metadata.json")
+  - InfoFile: Title, artist and genre tags, see SongInfo (e.g., "songs/MySong/info.json")
+  - SettingsFile: Per-song practice settings, see SongSettings (e.g., "songs/MySong/settings.json")
+  - NotesFile: The user's practice notes, edited from the start screen (e.g., "songs/MySong/notes.md")
+  - SessionsDir: Every finished run with its pitch trail, one JSON file each (e.g., "songs/MySong/sessions")
+  - PitchCacheFile: Base name of the analyzed pitch caches (e.g., "songs/MySong/pitch_cache.bin";
+    one file per mode, see audio.LoadAndAnalyzeSong)
 */
 type SongPaths struct {
-	Dir        string
-	SongFile   string
-	VocalsFile string
-	AccompFile string
+	Dir             string
+	SongFile        string
+	VocalsFile      string
+	AccompFile      string
+	ScoresFile      string
+	BestSessionFile string
+	MidiFile        string
+	LoopFile        string
+	WarmupFile      string
+	HarmonyFile     string
+	PitchCSVFile    string
+	LyricsFile      string
+	DifficultyFile  string
+	MetadataFile    string
+	InfoFile        string
+	SettingsFile    string
+	NotesFile       string
+	SessionsDir     string
+	PitchCacheFile  string
 }
 
 /*
@@ -100,17 +649,44 @@ Task:
 
 Logic:
  1. Use songDir as base directory
- 2. Join with standard filenames: song.mp3, vocals.mp3, accompaniment.mp3
+ 2. Join with standard filenames: song.mp3, vocals.mp3, accompaniment.mp3, scores.json, best_session.json, reference.mid, loop.json, warmup.json, harmony.json, pitch.csv, lyrics.lrc, difficulty.json, metadata.json, info.json, settings.json, notes.md, sessions/, pitch_cache.bin
+ 3. If song.mp3 doesn't exist: use the first song.flac/song.ogg/song.wav that does
 
 Output:
   - SongPaths struct with all path fields populated
 */
 func GetSongPaths(songDir string) SongPaths {
+	songFile := filepath.Join(songDir, "song.mp3")
+	if _, err := os.Stat(songFile); err != nil {
+		for _, ext := range SongExtensions[1:] {
+			alt := filepath.Join(songDir, "song"+ext)
+			if _, err := os.Stat(alt); err == nil {
+				songFile = alt
+				break
+			}
+		}
+	}
+
 	return SongPaths{
-		Dir:        songDir,
-		SongFile:   filepath.Join(songDir, "song.mp3"),
-		VocalsFile: filepath.Join(songDir, "vocals.mp3"),
-		AccompFile: filepath.Join(songDir, "accompaniment.mp3"),
+		Dir:             songDir,
+		SongFile:        songFile,
+		VocalsFile:      filepath.Join(songDir, "vocals.mp3"),
+		AccompFile:      filepath.Join(songDir, "accompaniment.mp3"),
+		ScoresFile:      filepath.Join(songDir, "scores.json"),
+		BestSessionFile: filepath.Join(songDir, "best_session.json"),
+		MidiFile:        filepath.Join(songDir, "reference.mid"),
+		LoopFile:        filepath.Join(songDir, "loop.json"),
+		WarmupFile:      filepath.Join(songDir, "warmup.json"),
+		HarmonyFile:     filepath.Join(songDir, "harmony.json"),
+		PitchCSVFile:    filepath.Join(songDir, "pitch.csv"),
+		LyricsFile:      filepath.Join(songDir, "lyrics.lrc"),
+		DifficultyFile:  filepath.Join(songDir, "difficulty.json"),
+		MetadataFile:    filepath.Join(songDir, "metadata.json"),
+		InfoFile:        filepath.Join(songDir, "info.json"),
+		SettingsFile:    filepath.Join(songDir, "settings.json"),
+		NotesFile:       filepath.Join(songDir, "notes.md"),
+		SessionsDir:     filepath.Join(songDir, "sessions"),
+		PitchCacheFile:  filepath.Join(songDir, "pitch_cache.bin"),
 	}
 }
 
@@ -140,3 +716,163 @@ func EnsureSongDir(songName string) (string, error) {
 	err := os.MkdirAll(dir, 0755)
 	return dir, err
 }
+
+/*
+ListSongs returns the song folders in the songs directory.
+
+Input:
+  - None
+
+Called by:
+  - main.main when launched without a song
+  - App.openSongBrowser
+
+Task:
+  - Find every song the browser can offer
+
+Logic:
+ 1. os.ReadDir(SongsDir) (entries come back sorted by name)
+ 2. Keep directory names only (history.jsonl and other files are skipped)
+
+Output:
+  - []string: Folder names (e.g., "Kasoor"), relative to SongsDir
+  - error: Read error (e.g. no songs directory yet)
+*/
+func ListSongs() ([]string, error) {
+	entries, err := os.ReadDir(SongsDir)
+	if err != nil {
+		return nil, err
+	}
+	var songs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			songs = append(songs, e.Name())
+		}
+	}
+	return songs, nil
+}
+
+/*
+FilterSongs returns the songs whose names contain a search query.
+
+Input:
+  - songs: []string - Song folder names (from ListSongs)
+  - query: string - Text typed in the song browser
+
+Called by:
+  - App.browserSongs for each song's folder name and label
+
+Task:
+  - Narrow long libraries down by name as the user types
+
+Logic:
+ 1. Empty query: every song
+ 2. Otherwise keep names containing the query, ignoring case, in their original order
+
+Output:
+  - []string: Matching song folder names
+*/
+func FilterSongs(songs []string, query string) []string {
+	if query == "" {
+		return songs
+	}
+	query = strings.ToLower(query)
+	var out []string
+	for _, s := range songs {
+		if strings.Contains(strings.ToLower(s), query) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+/*
+HistoryPath returns the location of the practice history log.
+
+Input:
+  - None
+
+Called by:
+  - App.recordHistory and App.openHistory
+
+Task:
+  - Keep the log next to the songs so it persists with the library
+
+Logic:
+ 1. Join SongsDir with HistoryFile
+
+Output:
+  - string: Path to history.jsonl
+*/
+func HistoryPath() string {
+	return filepath.Join(SongsDir, HistoryFile)
+}
+
+/*
+FreestylePath returns where a freestyle session recorded at a given time is saved.
+
+Input:
+  - songDir: string - Song folder the session was sung in
+  - at: time.Time - When the session ended
+
+Called by:
+  - scoring.SaveFreestyle
+
+Task:
+  - Keep every freestyle session instead of overwriting the last one
+
+Logic:
+ 1. Join songDir with freestyle_<YYYYMMDD_HHMMSS>.json
+
+Output:
+  - string: Path to the session file
+*/
+func FreestylePath(songDir string, at time.Time) string {
+	return filepath.Join(songDir, "freestyle_"+at.Format("20060102_150405")+".json")
+}
+
+/*
+RecordingPath returns the WAV file a session's mic recording is saved to.
+
+Input:
+  - songDir: string - Song folder the session is sung in
+  - at: time.Time - When the session started
+
+Called by:
+  - App.startGame
+
+Task:
+  - Keep every recording instead of overwriting the last one
+
+Logic:
+ 1. Join songDir with recording_<YYYYMMDD_HHMMSS>.wav
+
+Output:
+  - string: Path to the recording
+*/
+func RecordingPath(songDir string, at time.Time) string {
+	return filepath.Join(songDir, "recording_"+at.Format("20060102_150405")+".wav")
+}
+
+/*
+IsSongFile reports whether a path has a supported audio extension.
+
+Input:
+  - path: string - File path (e.g., "Kasoor.flac")
+
+Called by:
+  - main.main when deciding whether an argument is a file to import
+
+Task:
+  - Recognize importable audio files
+
+Logic:
+ 1. Compare the lower-cased extension against SongExtensions and ConvertExtensions
+
+Output:
+  - bool: true for .mp3, .flac, .ogg, .wav, .m4a and .aac files
+*/
+func IsSongFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return slices.Contains(SongExtensions, ext) || slices.Contains(ConvertExtensions, ext)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestListSongs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if _, err := ListSongs(); err == nil {
+		t.Error("ListSongs without a songs directory succeeded, want an error")
+	}
+
+	for _, name := range []string{"Kasoor", "Aaoge Tum Kabhi", "été"} {
+		if err := os.MkdirAll(filepath.Join(SongsDir, name, "sessions"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(SongsDir, "history.jsonl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ListSongs()
+	if err != nil {
+		t.Fatalf("ListSongs: %v", err)
+	}
+	if want := []string{"Aaoge Tum Kabhi", "Kasoor", "été"}; !slices.Equal(got, want) {
+		t.Errorf("ListSongs = %q, want %q", got, want)
+	}
+}
+
+func TestFilterSongs(t *testing.T) {
+	songs := []string{"Aaoge Tum Kabhi", "Kasoor", "Tum Hi Ho"}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", songs},
+		{"tum", []string{"Aaoge Tum Kabhi", "Tum Hi Ho"}},
+		{"KAS", []string{"Kasoor"}},
+		{"xyz", nil},
+	}
+	for _, tt := range tests {
+		if got := FilterSongs(songs, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("FilterSongs(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
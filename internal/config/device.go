@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// DeviceFile stores the microphone picked on the start screen.
+const DeviceFile = "config/device.json"
+
+/*
+InputDevice is the on-disk form of DeviceFile.
+
+Fields:
+  - Name: Device name; matched first, since indices change when devices come and go
+  - Index: PortAudio device index the name had when it was saved
+*/
+type InputDevice struct {
+	Name  string `json:"name"`
+	Index int    `json:"index"`
+}
+
+/*
+LoadInputDevice reads the saved microphone choice.
+
+Input:
+  - None (reads DeviceFile)
+
+Called by:
+  - App.loadDevices when the app starts
+
+Task:
+  - Reopen the same microphone on the next launch
+
+Logic:
+ 1. Read and decode DeviceFile
+
+Output:
+  - InputDevice: The saved device
+  - bool: false when nothing usable is saved (use the system default)
+*/
+func LoadInputDevice() (InputDevice, bool) {
+	var d InputDevice
+	data, err := os.ReadFile(DeviceFile)
+	if err != nil || json.Unmarshal(data, &d) != nil || d.Name == "" {
+		return InputDevice{}, false
+	}
+	return d, true
+}
+
+/*
+SaveInputDevice stores the microphone choice, or clears it.
+
+Input:
+  - d: InputDevice - Selected device; an empty Name means the system default
+
+Called by:
+  - App.handleStartScreenInput when the device selector changes
+
+Task:
+  - Persist the choice across launches
+
+Logic:
+ 1. Empty name: remove DeviceFile
+ 2. Otherwise create the config directory and write DeviceFile as JSON
+
+Output:
+  - error: nil on success, filesystem error otherwise
+*/
+func SaveInputDevice(d InputDevice) error {
+	if d.Name == "" {
+		if err := os.Remove(DeviceFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(DeviceFile), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(DeviceFile, data, 0644)
+}
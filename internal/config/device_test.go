@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestInputDeviceRoundTrip(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	if _, ok := LoadInputDevice(); ok {
+		t.Error("LoadInputDevice found a device before one was saved")
+	}
+	want := InputDevice{Name: "USB Audio Interface", Index: 3}
+	if err := SaveInputDevice(want); err != nil {
+		t.Fatalf("SaveInputDevice: %v", err)
+	}
+	if got, ok := LoadInputDevice(); !ok || got != want {
+		t.Errorf("LoadInputDevice = %+v, %v; want %+v", got, ok, want)
+	}
+
+	if err := SaveInputDevice(InputDevice{}); err != nil {
+		t.Fatalf("clearing the device: %v", err)
+	}
+	if _, err := os.Stat(DeviceFile); !os.IsNotExist(err) {
+		t.Errorf("choosing the default left %s behind", DeviceFile)
+	}
+	if err := SaveInputDevice(InputDevice{}); err != nil {
+		t.Errorf("clearing an already cleared device: %v", err)
+	}
+}
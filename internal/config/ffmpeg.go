@@ -0,0 +1,30 @@
+package config
+
+import "os/exec"
+
+// HasFFMPEG is whether ffmpeg was found on the PATH at startup (see DetectFFMPEG).
+var HasFFMPEG bool
+
+/*
+DetectFFMPEG checks whether ffmpeg is installed.
+
+Input:
+  - None
+
+Called by:
+  - main.main at startup
+
+Task:
+  - Know up front whether .m4a/.aac files can be imported
+
+Logic:
+ 1. exec.LookPath("ffmpeg"); store the result in HasFFMPEG (set before any
+    goroutine reads it)
+
+Output:
+  - None (sets HasFFMPEG)
+*/
+func DetectFFMPEG() {
+	_, err := exec.LookPath("ffmpeg")
+	HasFFMPEG = err == nil
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// KeybindingsFile holds user overrides for the playback keys, e.g.
+// {"Pause": "KeyP"}. It is written with the defaults on first run.
+const KeybindingsFile = "config/keybindings.json"
+
+/*
+Keybindings maps playback actions to keys.
+
+Fields:
+  - Pause: Toggle play/pause
+  - SeekBack, SeekForward: Jump 10 seconds back or forward
+  - Fullscreen: Toggle fullscreen
+  - Exit: Leave playback and return to the menu
+  - BlockView: Switch between line and block song view
+  - TransposeUp, TransposeDown: Transpose the song ±1 semitone
+  - Echo: Capture a phrase / start or stop echo practice
+  - Heatmap: Pause and show the pitch heatmap
+  - Visualization: Toggle the spectrogram (with Shift: save session PNG)
+  - Ghost: Show or hide the ghost trail (with Shift: cycle gap filling)
+  - Grid: Show or hide the semitone grid
+  - Metronome: Turn the click track on or off
+  - ReferenceTone: Start or stop the reference tone (no-audio mode)
+  - SightReading: Hide or show the upcoming song line
+  - MicMonitor: Hear the microphone through the speakers, or stop
+  - Intonation: Show or hide the intonation graph
+*/
+type Keybindings struct {
+	Pause         ebiten.Key
+	SeekBack      ebiten.Key
+	SeekForward   ebiten.Key
+	Fullscreen    ebiten.Key
+	Exit          ebiten.Key
+	BlockView     ebiten.Key
+	TransposeUp   ebiten.Key
+	TransposeDown ebiten.Key
+	Echo          ebiten.Key
+	Heatmap       ebiten.Key
+	Visualization ebiten.Key
+	Ghost         ebiten.Key
+	Grid          ebiten.Key
+	Metronome     ebiten.Key
+	ReferenceTone ebiten.Key
+	SightReading  ebiten.Key
+	MicMonitor    ebiten.Key
+	Intonation    ebiten.Key
+}
+
+// Keys is the active key map, replaced by LoadKeybindings at startup.
+var Keys = DefaultKeybindings()
+
+/*
+DefaultKeybindings returns the built-in key map.
+
+Input:
+  - None
+
+Called by:
+  - Keys initialisation, LoadKeybindings, WriteDefaultKeybindings
+
+Task:
+  - Keep the original controls when no file overrides them
+
+Logic:
+ 1. Return the hard-coded assignments
+
+Output:
+  - Keybindings: Default key map
+*/
+func DefaultKeybindings() Keybindings {
+	return Keybindings{
+		Pause:         ebiten.KeySpace,
+		SeekBack:      ebiten.KeyLeft,
+		SeekForward:   ebiten.KeyRight,
+		Fullscreen:    ebiten.KeyF,
+		Exit:          ebiten.KeyEscape,
+		BlockView:     ebiten.KeyB,
+		TransposeUp:   ebiten.KeyEqual,
+		TransposeDown: ebiten.KeyMinus,
+		Echo:          ebiten.KeyE,
+		Heatmap:       ebiten.KeyR,
+		Visualization: ebiten.KeyS,
+		Ghost:         ebiten.KeyG,
+		Grid:          ebiten.KeyN,
+		Metronome:     ebiten.KeyM,
+		ReferenceTone: ebiten.KeyT,
+		SightReading:  ebiten.KeyH,
+		MicMonitor:    ebiten.KeyV,
+		Intonation:    ebiten.KeyI,
+	}
+}
+
+/*
+bindings lists every action by its JSON name.
+
+Input:
+  - None
+
+Called by:
+  - LoadKeybindings, WriteDefaultKeybindings
+
+Task:
+  - Map file names to struct fields without reflection
+
+Logic:
+ 1. Return pointers to each field keyed by its name
+
+Output:
+  - map[string]*ebiten.Key: Action name to field
+*/
+func (k *Keybindings) bindings() map[string]*ebiten.Key {
+	return map[string]*ebiten.Key{
+		"Pause":         &k.Pause,
+		"SeekBack":      &k.SeekBack,
+		"SeekForward":   &k.SeekForward,
+		"Fullscreen":    &k.Fullscreen,
+		"Exit":          &k.Exit,
+		"BlockView":     &k.BlockView,
+		"TransposeUp":   &k.TransposeUp,
+		"TransposeDown": &k.TransposeDown,
+		"Echo":          &k.Echo,
+		"Heatmap":       &k.Heatmap,
+		"Visualization": &k.Visualization,
+		"Ghost":         &k.Ghost,
+		"Grid":          &k.Grid,
+		"Metronome":     &k.Metronome,
+		"ReferenceTone": &k.ReferenceTone,
+		"SightReading":  &k.SightReading,
+		"MicMonitor":    &k.MicMonitor,
+		"Intonation":    &k.Intonation,
+	}
+}
+
+/*
+LoadKeybindings reads a keybindings file over the defaults.
+
+Input:
+  - path: string - JSON file of action name to key name
+
+Called by:
+  - main at startup
+
+Task:
+  - Let the user remap playback keys
+
+Logic:
+ 1. Start from DefaultKeybindings; actions missing from the file keep them
+ 2. Decode the file as a map of action name to key name
+ 3. Reject unknown actions; parse key names with or without the "Key" prefix
+    ("KeyP" and "P" both work)
+
+Output:
+  - Keybindings: Resulting key map (defaults on error)
+  - error: Read, JSON, unknown action or unknown key error
+*/
+func LoadKeybindings(path string) (Keybindings, error) {
+	keys := DefaultKeybindings()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keys, err
+	}
+	var names map[string]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return DefaultKeybindings(), err
+	}
+
+	fields := keys.bindings()
+	for action, name := range names {
+		field, ok := fields[action]
+		if !ok {
+			return DefaultKeybindings(), fmt.Errorf("unknown action %q in %s", action, path)
+		}
+		if err := field.UnmarshalText([]byte(strings.TrimPrefix(name, "Key"))); err != nil {
+			return DefaultKeybindings(), fmt.Errorf("%s: %s: %v", path, action, err)
+		}
+	}
+	return keys, nil
+}
+
+/*
+WriteDefaultKeybindings writes the default key map so it can be edited.
+
+Input:
+  - path: string - Destination file
+
+Called by:
+  - main on first run when the file does not exist
+
+Task:
+  - Give the user a complete template to remap from
+
+Logic:
+ 1. Create the config directory if needed
+ 2. Write every action as "Key<Name>" (sorted JSON object)
+
+Output:
+  - error: nil on success, filesystem error otherwise
+*/
+func WriteDefaultKeybindings(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	keys := DefaultKeybindings()
+	names := make(map[string]string)
+	for action, field := range keys.bindings() {
+		names[action] = "Key" + field.String()
+	}
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestLoadKeybindingsRemapsPause(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keybindings.json")
+	if err := os.WriteFile(path, []byte(`{"Pause": "KeyP", "Exit": "Q"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadKeybindings(path)
+	if err != nil {
+		t.Fatalf("LoadKeybindings: %v", err)
+	}
+	if keys.Pause != ebiten.KeyP || keys.Exit != ebiten.KeyQ {
+		t.Errorf("Pause = %v, Exit = %v; want P and Q", keys.Pause, keys.Exit)
+	}
+	for action, field := range keys.bindings() {
+		if *field == ebiten.KeySpace {
+			t.Errorf("Space still triggers %s", action)
+		}
+	}
+	if keys.SeekBack != ebiten.KeyLeft {
+		t.Errorf("SeekBack = %v, want the default Left", keys.SeekBack)
+	}
+}
+
+func TestLoadKeybindingsErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"action.json": `{"Jump": "KeyJ"}`,
+		"key.json":    `{"Pause": "KeyBogus"}`,
+		"json.json":   `{"Pause": `,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		keys, err := LoadKeybindings(path)
+		if err == nil {
+			t.Errorf("%s: no error", name)
+		}
+		if keys != DefaultKeybindings() {
+			t.Errorf("%s: got %+v, want the defaults", name, keys)
+		}
+	}
+	if keys, err := LoadKeybindings(filepath.Join(dir, "missing.json")); err == nil || keys != DefaultKeybindings() {
+		t.Errorf("missing file = %+v, %v; want the defaults and an error", keys, err)
+	}
+}
+
+func TestWriteDefaultKeybindingsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "keybindings.json")
+	if err := WriteDefaultKeybindings(path); err != nil {
+		t.Fatalf("WriteDefaultKeybindings: %v", err)
+	}
+	if keys, err := LoadKeybindings(path); err != nil || keys != DefaultKeybindings() {
+		t.Errorf("reloaded %+v, %v; want the defaults", keys, err)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// LatencyFile stores the measured output-to-input latency (written by the
+// Calibrate Latency screen).
+const LatencyFile = "config/latency.json"
+
+var (
+	latencyMu     sync.Mutex
+	latencyLoaded bool
+	latencyMs     float64
+)
+
+/*
+latencySetting is the on-disk form of LatencyFile.
+
+Fields:
+  - LatencyMs: Measured round-trip latency in milliseconds
+*/
+type latencySetting struct {
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+/*
+GetAudioLatencyMs returns the latency compensation to apply to mic timestamps.
+
+Input:
+  - None (reads LatencyFile on first call)
+
+Called by:
+  - Hit detection, scoring, heatmap, echo and export code wherever the user
+    trail is lined up with the song
+
+Task:
+  - Use the measured latency when there is one, AudioLatencyMs otherwise
+
+Logic:
+ 1. Lock latencyMu (called from the draw loop and micLoop)
+ 2. On first call: read LatencyFile; missing, unreadable or out-of-range
+    (0..1000 ms) values fall back to AudioLatencyMs
+ 3. Return the cached value
+
+Output:
+  - float64: Latency in milliseconds
+*/
+func GetAudioLatencyMs() float64 {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+
+	if !latencyLoaded {
+		latencyLoaded = true
+		latencyMs = AudioLatencyMs
+
+		var s latencySetting
+		if data, err := os.ReadFile(LatencyFile); err == nil && json.Unmarshal(data, &s) == nil {
+			if s.LatencyMs > 0 && s.LatencyMs < 1000 {
+				latencyMs = s.LatencyMs
+			}
+		}
+	}
+	return latencyMs
+}
+
+/*
+SaveAudioLatencyMs stores a measured latency and starts using it.
+
+Input:
+  - ms: float64 - Measured latency in milliseconds
+
+Called by:
+  - App.runLatencyCalibration after a successful measurement
+
+Task:
+  - Persist the calibration across launches
+
+Logic:
+ 1. Create the config directory if needed
+ 2. Write LatencyFile as JSON
+ 3. Update the cached value so the next session uses it immediately
+
+Output:
+  - error: nil on success, filesystem error otherwise
+*/
+func SaveAudioLatencyMs(ms float64) error {
+	if err := os.MkdirAll(filepath.Dir(LatencyFile), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(latencySetting{LatencyMs: ms}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(LatencyFile, data, 0644); err != nil {
+		return err
+	}
+
+	latencyMu.Lock()
+	latencyLoaded = true
+	latencyMs = ms
+	latencyMu.Unlock()
+	return nil
+}
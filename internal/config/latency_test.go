@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// reloadLatency makes the next GetAudioLatencyMs read LatencyFile again, as
+// on a fresh start.
+func reloadLatency() {
+	latencyMu.Lock()
+	latencyLoaded = false
+	latencyMu.Unlock()
+}
+
+func TestAudioLatencyRoundTrip(t *testing.T) {
+	t.Chdir(t.TempDir())
+	reloadLatency()
+	t.Cleanup(reloadLatency)
+
+	if got := GetAudioLatencyMs(); got != AudioLatencyMs {
+		t.Errorf("latency without a file = %v, want the default %v", got, AudioLatencyMs)
+	}
+
+	if err := SaveAudioLatencyMs(87); err != nil {
+		t.Fatalf("SaveAudioLatencyMs: %v", err)
+	}
+	if got := GetAudioLatencyMs(); got != 87 {
+		t.Errorf("latency after saving = %v, want 87", got)
+	}
+	reloadLatency()
+	if got := GetAudioLatencyMs(); got != 87 {
+		t.Errorf("latency after a restart = %v, want 87 from %s", got, LatencyFile)
+	}
+}
+
+func TestAudioLatencyIgnoresBadFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Cleanup(reloadLatency)
+
+	for _, data := range []string{`{"latency_ms": 5000}`, `{"latency_ms": -3}`, `not json`} {
+		if err := os.MkdirAll(filepath.Dir(LatencyFile), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(LatencyFile, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		reloadLatency()
+		if got := GetAudioLatencyMs(); got != AudioLatencyMs {
+			t.Errorf("latency from %s = %v, want the default %v", data, got, AudioLatencyMs)
+		}
+	}
+}
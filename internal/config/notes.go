@@ -0,0 +1,53 @@
+package config
+
+import "os"
+
+/*
+LoadNotes reads a song's practice notes.
+
+Input:
+  - songDir: string - Song folder (e.g. "songs/MySong")
+
+Called by:
+  - App.selectSong when a song is selected
+  - app.New for the song given on the command line
+
+Task:
+  - Show the user's notes on the start screen and in the note editor
+
+Logic:
+ 1. Read SongPaths.NotesFile; a missing or unreadable file has no notes
+
+Output:
+  - string: Notes as written ("" if none)
+*/
+func LoadNotes(songDir string) string {
+	data, err := os.ReadFile(GetSongPaths(songDir).NotesFile)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+/*
+SaveNotes writes a song's practice notes.
+
+Input:
+  - songDir: string - Song folder
+  - content: string - Notes (Markdown, kept as typed)
+
+Called by:
+  - App.handleNoteEditorInput on Ctrl+S
+
+Task:
+  - Keep the notes next to the song
+
+Logic:
+ 1. Write content to SongPaths.NotesFile
+
+Output:
+  - error: nil on success
+*/
+func SaveNotes(songDir string, content string) error {
+	return os.WriteFile(GetSongPaths(songDir).NotesFile, []byte(content), 0644)
+}
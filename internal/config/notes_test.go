@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+func TestNotesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if got := LoadNotes(dir); got != "" {
+		t.Errorf("LoadNotes without notes.md = %q, want \"\"", got)
+	}
+
+	want := "# Kasoor\n\nBridge: breathe before the high G.\n  Chorus is flat on the second line.\n"
+	if err := SaveNotes(dir, want); err != nil {
+		t.Fatalf("SaveNotes: %v", err)
+	}
+	if got := LoadNotes(dir); got != want {
+		t.Errorf("LoadNotes = %q, want %q", got, want)
+	}
+}
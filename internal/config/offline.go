@@ -0,0 +1,49 @@
+package config
+
+// offlineMode is set once at startup by -offline (see SetOfflineMode).
+var offlineMode bool
+
+/*
+SetOfflineMode turns offline mode on or off.
+
+Input:
+  - on: bool - Whether -offline was given
+
+Called by:
+  - main.main after parsing flags
+
+Task:
+  - Record that there is no internet and no Python on this machine
+
+Logic:
+ 1. Store on in offlineMode (set before any goroutine reads it)
+
+Output:
+  - None
+*/
+func SetOfflineMode(on bool) {
+	offlineMode = on
+}
+
+/*
+IsOfflineMode reports whether downloads and Python separation are disabled.
+
+Input:
+  - None
+
+Called by:
+  - audio.LoadAndAnalyzeSong before separating a song
+  - youtube.Download, DownloadPlaylist and DownloadFromURL
+
+Task:
+  - Let deployments without internet or Python (school computers, CI) still practice
+
+Logic:
+ 1. Return offlineMode
+
+Output:
+  - bool: true with -offline
+*/
+func IsOfflineMode() bool {
+	return offlineMode
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// SensitivityFile holds the mic sensitivity chosen on the start screen.
+const SensitivityFile = "config/sensitivity.json"
+
+// sensitivityJSON is the on-disk form of SensitivityFile.
+type sensitivityJSON struct {
+	Multiplier float64 `json:"multiplier"`
+}
+
+/*
+LoadSensitivity reads the saved mic sensitivity multiplier.
+
+Input:
+  - None (reads SensitivityFile)
+
+Called by:
+  - audio.NewMicHandler, app.New
+
+Task:
+  - Let quiet rooms use a lower noise gate than the default margin
+
+Logic:
+ 1. No file: DefaultSensitivity and no error
+ 2. Otherwise decode it and clamp to MinSensitivity..MaxSensitivity
+
+Output:
+  - float64: Multiplier applied to the calibrated noise level
+  - error: Read or JSON error (DefaultSensitivity is returned with it)
+*/
+func LoadSensitivity() (float64, error) {
+	data, err := os.ReadFile(SensitivityFile)
+	if os.IsNotExist(err) {
+		return DefaultSensitivity, nil
+	}
+	if err != nil {
+		return DefaultSensitivity, err
+	}
+	var s sensitivityJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return DefaultSensitivity, err
+	}
+	return max(MinSensitivity, min(MaxSensitivity, s.Multiplier)), nil
+}
+
+/*
+SaveSensitivity writes the mic sensitivity multiplier.
+
+Input:
+  - multiplier: float64 - Value chosen on the start screen slider
+
+Called by:
+  - App.handleSensitivityInput when the slider is released
+
+Task:
+  - Keep the choice for later sessions
+
+Logic:
+ 1. Create the config folder if needed
+ 2. Marshal indented JSON and write SensitivityFile
+
+Output:
+  - error: nil on success
+*/
+func SaveSensitivity(multiplier float64) error {
+	if err := os.MkdirAll(filepath.Dir(SensitivityFile), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sensitivityJSON{Multiplier: multiplier}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(SensitivityFile, data, 0644)
+}
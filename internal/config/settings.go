@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+)
+
+/*
+SongSettings holds practice settings kept per song (settings.json in its folder).
+
+Fields:
+  - HitToleranceSemitones: How close (in semitones) the user must be to the song
+    to count a hit (DefaultHitTolerance; larger is easier)
+*/
+type SongSettings struct {
+	HitToleranceSemitones float64 `json:"hit_tolerance_semitones"`
+}
+
+/*
+LoadSongSettings reads a song's settings.json.
+
+Input:
+  - songDir: string - Song folder (e.g. "songs/MySong")
+
+Called by:
+  - App.loadSongSettings when a song is selected
+
+Task:
+  - Restore the settings the user chose for this song
+
+Logic:
+ 1. Start from the defaults (DefaultHitTolerance); a missing file keeps them
+ 2. Decode the file over them
+ 3. Clamp the tolerance to MinHitTolerance..MaxHitTolerance
+
+Output:
+  - SongSettings: Settings (defaults on error)
+  - error: Read or JSON error
+*/
+func LoadSongSettings(songDir string) (SongSettings, error) {
+	s := SongSettings{HitToleranceSemitones: DefaultHitTolerance}
+	data, err := os.ReadFile(GetSongPaths(songDir).SettingsFile)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return SongSettings{HitToleranceSemitones: DefaultHitTolerance}, err
+	}
+	s.HitToleranceSemitones = max(MinHitTolerance, min(MaxHitTolerance, s.HitToleranceSemitones))
+	return s, nil
+}
+
+/*
+SaveSongSettings writes a song's settings.json.
+
+Input:
+  - songDir: string - Song folder
+  - s: SongSettings - Settings to store
+
+Called by:
+  - App.handleHitToleranceInput after a change
+
+Task:
+  - Keep the settings for the next run of the song
+
+Logic:
+ 1. Marshal indented JSON and write it to SongPaths.SettingsFile
+
+Output:
+  - error: nil on success
+*/
+func SaveSongSettings(songDir string, s SongSettings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(GetSongPaths(songDir).SettingsFile, data, 0644)
+}
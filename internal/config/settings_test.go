@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSongSettingsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if s, err := LoadSongSettings(dir); err != nil || s.HitToleranceSemitones != DefaultHitTolerance {
+		t.Errorf("settings without a file = %+v, %v; want the default tolerance", s, err)
+	}
+
+	if err := SaveSongSettings(dir, SongSettings{HitToleranceSemitones: 2.0}); err != nil {
+		t.Fatalf("SaveSongSettings: %v", err)
+	}
+	if s, err := LoadSongSettings(dir); err != nil || s.HitToleranceSemitones != 2.0 {
+		t.Errorf("reloaded settings = %+v, %v; want tolerance 2.0", s, err)
+	}
+}
+
+func TestSongSettingsClampsTolerance(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		json string
+		want float64
+	}{
+		{`{"hit_tolerance_semitones": 12}`, MaxHitTolerance},
+		{`{"hit_tolerance_semitones": 0}`, MinHitTolerance},
+		{`{}`, DefaultHitTolerance},
+	}
+	for _, tt := range tests {
+		os.WriteFile(GetSongPaths(dir).SettingsFile, []byte(tt.json), 0644)
+		if s, err := LoadSongSettings(dir); err != nil || s.HitToleranceSemitones != tt.want {
+			t.Errorf("%s loaded as %+v, %v; want tolerance %v", tt.json, s, err, tt.want)
+		}
+	}
+
+	os.WriteFile(GetSongPaths(dir).SettingsFile, []byte("{"), 0644)
+	if s, err := LoadSongSettings(dir); err == nil || s.HitToleranceSemitones != DefaultHitTolerance {
+		t.Errorf("corrupt settings.json = %+v, %v; want the default and an error", s, err)
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+)
+
+/*
+SongInfo holds the descriptive tags of a song (info.json in its folder).
+
+Fields:
+  - Title: Track title (e.g. "Never Gonna Give You Up")
+  - Artist: Performing artist
+  - Genre: Free-form genre used by the song browser's genre filter
+*/
+type SongInfo struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	Genre  string `json:"genre"`
+}
+
+/*
+LoadSongInfo reads a song's info.json.
+
+Input:
+  - songDir: string - Song folder (e.g. "songs/MySong")
+
+Called by:
+  - App.openSongBrowser for every listed song
+
+Task:
+  - Show artist and title instead of the folder name where they are known
+
+Logic:
+ 1. No info.json: zero SongInfo and no error (most songs are untagged)
+ 2. Otherwise decode it
+
+Output:
+  - SongInfo: Tags (empty fields where unknown)
+  - error: Read or JSON error
+*/
+func LoadSongInfo(songDir string) (SongInfo, error) {
+	var info SongInfo
+	data, err := os.ReadFile(GetSongPaths(songDir).InfoFile)
+	if os.IsNotExist(err) {
+		return info, nil
+	}
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+/*
+SaveSongInfo writes a song's info.json.
+
+Input:
+  - songDir: string - Song folder
+  - info: SongInfo - Tags to store
+
+Called by:
+  - youtube.Download with the artist and title yt-dlp reports
+
+Task:
+  - Keep the tags with the song (edit the file to add a genre)
+
+Logic:
+ 1. Marshal indented JSON and write it to SongPaths.InfoFile
+
+Output:
+  - error: nil on success
+*/
+func SaveSongInfo(songDir string, info SongInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(GetSongPaths(songDir).InfoFile, data, 0644)
+}
+
+/*
+Label returns the name the song browser shows for a song.
+
+Input:
+  - folder: string - Song folder name, used when there is no title
+
+Called by:
+  - App.browserSongs, App.drawSongBrowser
+
+Task:
+  - One spelling of "Artist - Title" across the browser and its search
+
+Logic:
+ 1. No title: folder
+ 2. Title with artist: "Artist - Title"; otherwise the title alone
+
+Output:
+  - string: Display name
+*/
+func (s SongInfo) Label(folder string) string {
+	if s.Title == "" {
+		return folder
+	}
+	if s.Artist == "" {
+		return s.Title
+	}
+	return s.Artist + " - " + s.Title
+}
+
+/*
+SongGenres lists the genres used by a set of songs.
+
+Input:
+  - infos: map[string]SongInfo - Tags by song folder name
+
+Called by:
+  - App.openSongBrowser for the genre filter
+
+Task:
+  - Offer only genres that match at least one song
+
+Logic:
+ 1. Collect the non-empty genres, merging ones that differ only in case
+ 2. Sort them case-insensitively
+
+Output:
+  - []string: Distinct genres (first spelling seen wins)
+*/
+func SongGenres(infos map[string]SongInfo) []string {
+	seen := make(map[string]bool)
+	var genres []string
+	folders := make([]string, 0, len(infos))
+	for folder := range infos {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+	for _, folder := range folders {
+		g := strings.TrimSpace(infos[folder].Genre)
+		if g == "" || seen[strings.ToLower(g)] {
+			continue
+		}
+		seen[strings.ToLower(g)] = true
+		genres = append(genres, g)
+	}
+	sort.Slice(genres, func(i, j int) bool {
+		return strings.ToLower(genres[i]) < strings.ToLower(genres[j])
+	})
+	return genres
+}
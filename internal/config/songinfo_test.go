@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestLoadSongInfoMissingFile(t *testing.T) {
+	info, err := LoadSongInfo(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadSongInfo without info.json: %v", err)
+	}
+	if info != (SongInfo{}) {
+		t.Errorf("LoadSongInfo = %+v, want zero values", info)
+	}
+}
+
+func TestSongInfoRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	want := SongInfo{Title: "Kasoor", Artist: "Prateek Kuhad", Genre: "Indie"}
+	if err := SaveSongInfo(dir, want); err != nil {
+		t.Fatalf("SaveSongInfo: %v", err)
+	}
+	if got, err := LoadSongInfo(dir); err != nil || got != want {
+		t.Errorf("LoadSongInfo = %+v, %v; want %+v", got, err, want)
+	}
+
+	os.WriteFile(GetSongPaths(dir).InfoFile, []byte("{"), 0644)
+	if _, err := LoadSongInfo(dir); err == nil {
+		t.Error("LoadSongInfo accepted a corrupt info.json")
+	}
+}
+
+func TestSongInfoLabel(t *testing.T) {
+	tests := []struct {
+		info SongInfo
+		want string
+	}{
+		{SongInfo{}, "kasoor_live"},
+		{SongInfo{Artist: "Prateek Kuhad"}, "kasoor_live"},
+		{SongInfo{Title: "Kasoor"}, "Kasoor"},
+		{SongInfo{Title: "Kasoor", Artist: "Prateek Kuhad"}, "Prateek Kuhad - Kasoor"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.Label("kasoor_live"); got != tt.want {
+			t.Errorf("%+v.Label = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestSongGenresMergesCase(t *testing.T) {
+	infos := map[string]SongInfo{
+		"a": {Genre: "indie"},
+		"b": {Genre: "Bollywood"},
+		"c": {Genre: " Indie "},
+		"d": {},
+	}
+	if got, want := SongGenres(infos), []string{"Bollywood", "indie"}; !slices.Equal(got, want) {
+		t.Errorf("SongGenres = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// VenvPathFile holds the path of the Python virtual environment used for
+// vocal separation. The setup screen writes it on first launch.
+const VenvPathFile = "venv_path.txt"
+
+// wizardTimeout bounds how long RunWizard waits for "python --version".
+const wizardTimeout = 5 * time.Second
+
+/*
+NeedsSetup reports whether the first-run setup screen should be shown.
+
+Input:
+  - None (checks VenvPathFile)
+
+Called by:
+  - app.New
+
+Task:
+  - Detect a first launch
+
+Logic:
+ 1. True when VenvPathFile does not exist
+
+Output:
+  - bool: true if no venv path was ever saved
+*/
+func NeedsSetup() bool {
+	_, err := os.Stat(VenvPathFile)
+	return os.IsNotExist(err)
+}
+
+/*
+RunWizard validates a virtual environment path typed on the setup screen.
+
+Input:
+  - input: string - Venv folder as typed (relative paths are from the working directory)
+
+Called by:
+  - app.checkVenv after Enter on the setup screen (off the UI thread)
+
+Task:
+  - Catch typos before they turn into a failed separation minutes later
+
+Logic:
+ 1. Trim the input; reject an empty path
+ 2. Reject a path that is not a directory or has no bin/python
+ 3. Run "<venv>/bin/python --version" (wizardTimeout); reject on failure
+ 4. Success message is the reported version (e.g. "Python 3.11.4")
+
+Output:
+  - bool: true if the venv works
+  - string: Python version on success, otherwise why the path was rejected
+*/
+func RunWizard(input string) (bool, string) {
+	venv := strings.TrimSpace(input)
+	if venv == "" {
+		return false, "Enter the path of your Python virtual environment"
+	}
+
+	if info, err := os.Stat(venv); err != nil || !info.IsDir() {
+		return false, fmt.Sprintf("%s is not a folder", venv)
+	}
+	python := venvPython(venv)
+	if _, err := os.Stat(python); err != nil {
+		return false, fmt.Sprintf("No bin/python in %s (is it a virtual environment?)", venv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wizardTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, python, "--version").CombinedOutput()
+	if err != nil {
+		return false, fmt.Sprintf("%s --version failed: %v", python, err)
+	}
+	return true, strings.TrimSpace(string(out))
+}
+
+/*
+SaveVenvPath writes the venv path accepted by RunWizard.
+
+Input:
+  - venv: string - Virtual environment folder ("" when setup was skipped)
+
+Called by:
+  - App.applySetupCheck after RunWizard succeeds
+  - App.handleSetupInput on Escape, with ""
+
+Task:
+  - Remember the venv so GetPythonPath finds it and setup is not shown again
+
+Logic:
+ 1. Write the trimmed path and a newline to VenvPathFile (an empty path
+    makes GetPythonPath use the system python3)
+
+Output:
+  - error: nil on success
+*/
+func SaveVenvPath(venv string) error {
+	return os.WriteFile(VenvPathFile, []byte(strings.TrimSpace(venv)+"\n"), 0644)
+}
+
+/*
+venvPython returns the Python executable inside a virtual environment.
+
+Input:
+  - venv: string - Virtual environment folder
+
+Called by:
+  - GetPythonPath, RunWizard
+
+Task:
+  - Resolve the interpreter the same way everywhere
+
+Logic:
+ 1. If venv is relative, join it with the current working directory
+ 2. Append bin/python
+
+Output:
+  - string: Path to the interpreter (not checked for existence)
+*/
+func venvPython(venv string) string {
+	if !filepath.IsAbs(venv) {
+		if cwd, err := os.Getwd(); err == nil {
+			venv = filepath.Join(cwd, venv)
+		}
+	}
+	return filepath.Join(venv, "bin", "python")
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeVenv creates a venv folder whose bin/python is a shell script running script.
+func fakeVenv(t *testing.T, script string) string {
+	t.Helper()
+	venv := filepath.Join(t.TempDir(), "venv")
+	if err := os.MkdirAll(filepath.Join(venv, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(venv, "bin", "python"), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return venv
+}
+
+func TestRunWizardValidVenv(t *testing.T) {
+	venv := fakeVenv(t, `echo "Python 3.11.4"`)
+	ok, msg := RunWizard("  " + venv + "  ")
+	if !ok || msg != "Python 3.11.4" {
+		t.Errorf("RunWizard(%q) = %v, %q; want true, the Python version", venv, ok, msg)
+	}
+}
+
+func TestRunWizardRejects(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "   ", "Enter the path"},
+		{"missing folder", filepath.Join(t.TempDir(), "nope"), "is not a folder"},
+		{"file", file, "is not a folder"},
+		{"no interpreter", t.TempDir(), "No bin/python"},
+		{"broken interpreter", fakeVenv(t, "exit 3"), "--version failed"},
+	}
+	for _, tt := range tests {
+		ok, msg := RunWizard(tt.input)
+		if ok || !strings.Contains(msg, tt.want) {
+			t.Errorf("%s: RunWizard(%q) = %v, %q; want false and a message containing %q", tt.name, tt.input, ok, msg, tt.want)
+		}
+	}
+}
+
+func TestSkippedSetupIsRemembered(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if !NeedsSetup() {
+		t.Fatal("NeedsSetup() = false without a venv file")
+	}
+	if err := SaveVenvPath(""); err != nil {
+		t.Fatal(err)
+	}
+	if NeedsSetup() {
+		t.Error("NeedsSetup() = true after setup was skipped")
+	}
+	if got := GetPythonPath(); got != "python3" {
+		t.Errorf("GetPythonPath() = %q after a skipped setup, want python3", got)
+	}
+}
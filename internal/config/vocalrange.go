@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// VocalRangeFile holds the range found by the vocal range test.
+const VocalRangeFile = "config/vocal_range.json"
+
+/*
+VocalRange is the user's comfortable singing range.
+
+Fields:
+  - LowHz, HighHz: Lowest and highest note matched in the range test
+  - LowNote, HighNote: The same notes by name (e.g. "E3", "G5"), for display
+*/
+type VocalRange struct {
+	LowHz    float64 `json:"low_hz"`
+	HighHz   float64 `json:"high_hz"`
+	LowNote  string  `json:"low_note"`
+	HighNote string  `json:"high_note"`
+}
+
+/*
+LoadVocalRange reads the saved vocal range.
+
+Input:
+  - None (reads VocalRangeFile)
+
+Called by:
+  - audio.NewMicHandler
+
+Task:
+  - Let pitch detection search only where the user can sing
+
+Logic:
+ 1. No file: zero VocalRange and no error (the test was never taken)
+ 2. Otherwise decode it
+
+Output:
+  - VocalRange: Saved range (zero if none)
+  - error: Read or JSON error
+*/
+func LoadVocalRange() (VocalRange, error) {
+	var r VocalRange
+	data, err := os.ReadFile(VocalRangeFile)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(data, &r)
+	return r, err
+}
+
+/*
+SaveVocalRange writes the vocal range.
+
+Input:
+  - r: VocalRange - Range to store
+
+Called by:
+  - App.finishRangeTest
+
+Task:
+  - Keep the range test result for later sessions
+
+Logic:
+ 1. Create the config folder if needed
+ 2. Marshal indented JSON and write VocalRangeFile
+
+Output:
+  - error: nil on success
+*/
+func SaveVocalRange(r VocalRange) error {
+	if err := os.MkdirAll(filepath.Dir(VocalRangeFile), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(VocalRangeFile, data, 0644)
+}
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+
+	"singAssist/internal/config"
+	"singAssist/internal/scoring"
+	"singAssist/internal/ui"
+)
+
+var (
+	songColor = color.RGBA{100, 150, 255, 255}
+	userColor = color.RGBA{255, 200, 50, 255}
+	hitColor  = color.RGBA{50, 255, 50, 255}
+)
+
+/*
+ExportPitchImage renders a whole session's pitch comparison to a PNG file.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...] for the session
+  - songPitch: []float64 - Song pitch at 10ms intervals
+  - duration: float64 - Seconds of song that map to the image width
+  - hit: scoring.HitRule - The session's rule, so the colors match its score
+  - outPath: string - PNG file to write
+
+Called by:
+  - App.exportSessionImage on Shift+S
+
+Task:
+  - Shareable picture of a performance
+
+Logic:
+ 1. Create a config.ExportImageW x config.ExportImageH black image
+ 2. Use ui.PitchVisualizer's Y transform so the picture matches the screen;
+    X spans 0..duration across the full width
+ 3. Draw the song pitch curve in blue
+ 4. Draw the user trail (shifted by config.GetAudioLatencyMs() like the live view)
+    in yellow, green where hit accepts it against the song
+ 5. Encode as PNG to outPath
+
+Output:
+  - error: nil on success, file or encode error otherwise
+*/
+func ExportPitchImage(userPitch, songPitch []float64, duration float64, hit scoring.HitRule, outPath string) error {
+	w, h := config.ExportImageW, config.ExportImageH
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillRect(img, img.Bounds(), color.RGBA{0, 0, 0, 255})
+
+	if duration <= 0 {
+		duration = float64(len(songPitch)) / 100
+	}
+	vis := ui.NewPitchVisualizer(w, h)
+	toX := func(t float64) float64 { return t / duration * float64(w) }
+
+	prevX, prevY, first := 0.0, 0.0, true
+	for i, p := range songPitch {
+		if p <= 5 {
+			first = true
+			continue
+		}
+		x, y := toX(float64(i)/100), vis.FreqToY(p)
+		if !first {
+			drawLine(img, prevX, prevY, x, y, songColor)
+		}
+		prevX, prevY, first = x, y, false
+	}
+
+	latency := config.GetAudioLatencyMs() / 1000.0
+	first = true
+	for i := 0; i+1 < len(userPitch); i += 2 {
+		t := userPitch[i]/1000 - latency
+		p := userPitch[i+1]
+		if p <= 10 {
+			first = true
+			continue
+		}
+
+		col := userColor
+		if sIdx := int(t * 100); sIdx >= 0 && sIdx < len(songPitch) {
+			if ref := songPitch[sIdx]; ref > 10 && hit(p, ref) {
+				col = hitColor
+			}
+		}
+
+		x, y := toX(t), vis.FreqToY(p)
+		if !first {
+			drawLine(img, prevX, prevY, x, y, col)
+		}
+		prevX, prevY, first = x, y, false
+	}
+
+	f, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+/*
+drawLine draws a 2px-thick line segment into an RGBA image.
+
+Input:
+  - img: *image.RGBA - Target image
+  - x1, y1, x2, y2: float64 - Endpoints in pixels
+  - col: color.RGBA - Line color
+
+Called by:
+  - ExportPitchImage for both curves
+
+Task:
+  - Minimal line rasterizer (no ebiten outside the game loop)
+
+Logic:
+ 1. Step along the longer axis one pixel at a time
+ 2. Set the pixel and the one below it; SetRGBA ignores out-of-bounds points
+
+Output:
+  - None (modifies img)
+*/
+func drawLine(img *image.RGBA, x1, y1, x2, y2 float64, col color.RGBA) {
+	steps := int(math.Max(math.Abs(x2-x1), math.Abs(y2-y1))) + 1
+	for s := 0; s <= steps; s++ {
+		t := float64(s) / float64(steps)
+		x := int(math.Round(x1 + (x2-x1)*t))
+		y := int(math.Round(y1 + (y2-y1)*t))
+		img.SetRGBA(x, y, col)
+		img.SetRGBA(x, y+1, col)
+	}
+}
+
+/*
+fillRect fills a rectangle of an RGBA image with one color.
+
+Input:
+  - img: *image.RGBA - Target image
+  - r: image.Rectangle - Area to fill
+  - col: color.RGBA - Fill color
+
+Called by:
+  - ExportPitchImage for the background
+
+Task:
+  - Clear the canvas
+
+Logic:
+ 1. Set every pixel in r
+
+Output:
+  - None (modifies img)
+*/
+func fillRect(img *image.RGBA, r image.Rectangle, col color.RGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, col)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"singAssist/internal/config"
+	"singAssist/internal/scoring"
+	"singAssist/internal/ui"
+)
+
+// sinePitch is a 4 s vibrato-like line: A3 swinging ±2 semitones once a second.
+func sinePitch() []float64 {
+	pitch := make([]float64, 400)
+	for i := range pitch {
+		pitch[i] = 220 * math.Pow(2, 2*math.Sin(2*math.Pi*float64(i)/100)/12)
+	}
+	return pitch
+}
+
+// sessionOf records song scaled by factor at every frame, as micLoop would.
+func sessionOf(song []float64, factor float64) []float64 {
+	var session []float64
+	for i, p := range song {
+		session = append(session, float64(10*i)+config.GetAudioLatencyMs(), p*factor)
+	}
+	return session
+}
+
+// render exports and decodes the image.
+func render(t *testing.T, user, song []float64, hit scoring.HitRule) image.Image {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.png")
+	if err := ExportPitchImage(user, song, 4, hit, path); err != nil {
+		t.Fatalf("ExportPitchImage: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decoding the PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != config.ExportImageW || b.Dy() != config.ExportImageH {
+		t.Fatalf("image is %dx%d, want %dx%d", b.Dx(), b.Dy(), config.ExportImageW, config.ExportImageH)
+	}
+	return img
+}
+
+// colorAt reports the color on the curve at song frame i, where the line of
+// pitch p passes through (checking the 2px thickness and rounding).
+func colorAt(img image.Image, i int, p float64) color.RGBA {
+	vis := ui.NewPitchVisualizer(config.ExportImageW, config.ExportImageH)
+	x := int(math.Round(float64(i) / 400 * float64(config.ExportImageW)))
+	y := int(math.Round(vis.FreqToY(p)))
+	for dy := -1; dy <= 1; dy++ {
+		if c := color.RGBAModel.Convert(img.At(x, y+dy)).(color.RGBA); c != (color.RGBA{0, 0, 0, 255}) {
+			return c
+		}
+	}
+	return color.RGBA{0, 0, 0, 255}
+}
+
+func TestExportPitchImageDrawsCurves(t *testing.T) {
+	t.Chdir(t.TempDir())
+	song := sinePitch()
+
+	// Without a user trail the song line is blue along its whole length.
+	img := render(t, nil, song, scoring.NoteHit(false, 1))
+	for _, i := range []int{10, 25, 75, 150, 390} {
+		if c := colorAt(img, i, song[i]); c != songColor {
+			t.Errorf("song frame %d drawn as %v, want blue", i, c)
+		}
+	}
+	if c := color.RGBAModel.Convert(img.At(5, 5)); c != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("corner pixel = %v, want the black background", c)
+	}
+
+	// A user three semitones sharp misses at 1 semitone and hits at 4.
+	sharp := math.Pow(2, 3.0/12)
+	img = render(t, sessionOf(song, sharp), song, scoring.NoteHit(false, 1))
+	if c := colorAt(img, 150, song[150]*sharp); c != userColor {
+		t.Errorf("trail 3 semitones sharp at tolerance 1 drawn as %v, want yellow", c)
+	}
+	img = render(t, sessionOf(song, sharp), song, scoring.NoteHit(false, 4))
+	if c := colorAt(img, 150, song[150]*sharp); c != hitColor {
+		t.Errorf("trail 3 semitones sharp at tolerance 4 drawn as %v, want green", c)
+	}
+}
+
+func TestExportPitchImageIgnoreOctave(t *testing.T) {
+	t.Chdir(t.TempDir())
+	song := sinePitch()
+	user := sessionOf(song, 2)
+
+	img := render(t, user, song, scoring.NoteHit(false, 1))
+	if c := colorAt(img, 150, song[150]*2); c != userColor {
+		t.Errorf("an octave up drawn as %v, want yellow", c)
+	}
+	img = render(t, user, song, scoring.NoteHit(true, 1))
+	if c := colorAt(img, 150, song[150]*2); c != hitColor {
+		t.Errorf("an octave up with ignoreOctave drawn as %v, want green", c)
+	}
+}
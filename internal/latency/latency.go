@@ -0,0 +1,231 @@
+package latency
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+)
+
+const (
+	// envelopeMs is the resolution of the recorded envelope and reference.
+	envelopeMs = 1
+
+	// clickMs is the length of each test click.
+	clickMs = 5
+
+	// maxLatencyMs is the longest latency Measure searches for.
+	maxLatencyMs = 500
+
+	// leadIn is silence before the first click so the mic stream settles.
+	leadIn = 500 * time.Millisecond
+)
+
+// clickGapsMs are the spacings between clicks; they are uneven so the
+// correlation has one clear peak instead of repeating every interval.
+var clickGapsMs = []int{610, 830, 540, 970, 720}
+
+/*
+AudioPlayer is the part of an audio player Measure needs.
+
+Fields:
+  - Play: Start playback of the click track
+  - Pause: Stop playback
+  - Position: Playback clock (the clock App.micLoop stamps mic buffers with)
+*/
+type AudioPlayer interface {
+	Play()
+	Pause()
+	Position() time.Duration
+}
+
+/*
+clickOnsets returns when each click starts, relative to playback start.
+
+Input:
+  - testDuration: time.Duration - Length of the click track
+
+Called by:
+  - ClickPCM, Measure
+
+Task:
+  - One deterministic click schedule shared by the track and the analysis
+
+Logic:
+ 1. Start after leadIn
+ 2. Step through clickGapsMs repeatedly until the end of the track,
+    leaving maxLatencyMs at the end for the last click to arrive
+
+Output:
+  - []int: Click onsets in milliseconds
+*/
+func clickOnsets(testDuration time.Duration) []int {
+	var onsets []int
+	endMs := int(testDuration.Milliseconds()) - maxLatencyMs
+	for t, i := int(leadIn.Milliseconds()), 0; t < endMs; i++ {
+		onsets = append(onsets, t)
+		t += clickGapsMs[i%len(clickGapsMs)]
+	}
+	return onsets
+}
+
+/*
+ClickPCM renders the click track played during calibration.
+
+Input:
+  - testDuration: time.Duration - Length of the track
+
+Called by:
+  - App.runLatencyCalibration to build the player passed to Measure
+
+Task:
+  - Sharp, easy-to-detect clicks at the clickOnsets schedule
+
+Logic:
+ 1. Allocate 16-bit stereo PCM at config.SampleRate
+ 2. At each onset write a clickMs burst of a 2 kHz tone with a fast decay
+
+Output:
+  - []byte: Little-endian 16-bit stereo PCM
+*/
+func ClickPCM(testDuration time.Duration) []byte {
+	frames := int(testDuration.Seconds() * config.SampleRate)
+	pcm := make([]byte, frames*4)
+	clickFrames := clickMs * config.SampleRate / 1000
+
+	for _, onset := range clickOnsets(testDuration) {
+		start := onset * config.SampleRate / 1000
+		for i := 0; i < clickFrames && start+i < frames; i++ {
+			decay := math.Exp(-float64(i) / float64(clickFrames) * 4)
+			v := int16(30000 * decay * math.Sin(2*math.Pi*2000*float64(i)/config.SampleRate))
+			off := (start + i) * 4
+			binary.LittleEndian.PutUint16(pcm[off:], uint16(v))
+			binary.LittleEndian.PutUint16(pcm[off+2:], uint16(v))
+		}
+	}
+	return pcm
+}
+
+/*
+Measure plays clicks and finds how late they arrive at the microphone.
+
+Input:
+  - mic: audio.MicInput - Started microphone
+  - player: AudioPlayer - Player loaded with ClickPCM(testDuration)
+  - testDuration: time.Duration - Length of the click track
+
+Called by:
+  - App.runLatencyCalibration
+
+Task:
+  - Measure the delay the app should subtract from mic timestamps
+
+Logic:
+ 1. Start the player
+ 2. Read mic buffers until the track ends (or a second past it by the wall
+    clock); stamp each buffer with player.Position when Read returns, exactly
+    as App.micLoop does, and spread its samples back over the buffer length
+    into a 1ms peak-amplitude envelope
+ 3. Remove the noise floor (subtract the mean envelope, clamp at 0) and
+    build the reference (1 for clickMs at each click onset)
+ 4. CrossCorrelate envelope and reference over 0..maxLatencyMs
+ 5. Reject results whose peak is not clearly above the average lag score
+
+Output:
+  - float64: Latency in milliseconds
+  - error: Mic read failure or no clicks detected
+*/
+func Measure(mic audio.MicInput, player AudioPlayer, testDuration time.Duration) (float64, error) {
+	envLen := int(testDuration.Milliseconds()) / envelopeMs
+	envelope := make([]float64, envLen)
+
+	player.Play()
+	defer player.Pause()
+	deadline := time.Now().Add(testDuration + time.Second)
+
+	for player.Position() < testDuration && time.Now().Before(deadline) {
+		if err := mic.Read(); err != nil {
+			return 0, err
+		}
+		endMs := float64(player.Position().Microseconds()) / 1000
+		samples := mic.Samples()
+		for j, s := range samples {
+			t := endMs - float64(len(samples)-j)*1000/config.SampleRate
+			idx := int(t / envelopeMs)
+			if idx >= 0 && idx < envLen {
+				envelope[idx] = math.Max(envelope[idx], math.Abs(float64(s)))
+			}
+		}
+	}
+
+	floor := 0.0
+	for _, e := range envelope {
+		floor += e
+	}
+	floor /= float64(envLen)
+	for i, e := range envelope {
+		envelope[i] = math.Max(0, e-floor)
+	}
+
+	reference := make([]float64, envLen)
+	for _, onset := range clickOnsets(testDuration) {
+		for d := 0; d < clickMs/envelopeMs; d++ {
+			if i := onset/envelopeMs + d; i < envLen {
+				reference[i] = 1
+			}
+		}
+	}
+
+	lag, peak, mean := CrossCorrelate(envelope, reference, maxLatencyMs/envelopeMs)
+	if peak <= 0 || peak < 3*mean {
+		return 0, fmt.Errorf("could not hear the clicks; turn the speaker up or move the mic closer")
+	}
+	return float64(lag * envelopeMs), nil
+}
+
+/*
+CrossCorrelate finds the delay of a reference pattern inside a recording.
+
+Input:
+  - recorded: []float64 - Recorded signal (e.g. envelope)
+  - reference: []float64 - Pattern that was played
+  - maxLag: int - Largest delay to test, in samples
+
+Called by:
+  - Measure
+
+Task:
+  - Time offset between what was played and what was heard
+
+Logic:
+ 1. For each lag 0..maxLag: score = Σ reference[i] * recorded[i+lag]
+ 2. Track the best lag and the average score over all lags
+
+Output:
+  - int: Lag with the highest score
+  - float64: That score
+  - float64: Mean score over all tested lags
+*/
+func CrossCorrelate(recorded, reference []float64, maxLag int) (int, float64, float64) {
+	bestLag, best, total := 0, math.Inf(-1), 0.0
+	for lag := 0; lag <= maxLag; lag++ {
+		score := 0.0
+		for i, r := range reference {
+			if r == 0 {
+				continue
+			}
+			if i+lag >= len(recorded) {
+				break
+			}
+			score += r * recorded[i+lag]
+		}
+		total += score
+		if score > best {
+			bestLag, best = lag, score
+		}
+	}
+	return bestLag, best, total / float64(maxLag+1)
+}
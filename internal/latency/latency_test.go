@@ -0,0 +1,90 @@
+package latency
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"singAssist/internal/audio"
+	"singAssist/internal/config"
+)
+
+func TestCrossCorrelateFindsInjectedDelay(t *testing.T) {
+	reference := make([]float64, 3000)
+	for _, onset := range []int{100, 710, 1540, 2080} {
+		for d := 0; d < clickMs; d++ {
+			reference[onset+d] = 1
+		}
+	}
+	const delay = 137
+	recorded := make([]float64, len(reference))
+	for i, r := range reference {
+		if i+delay < len(recorded) {
+			recorded[i+delay] = 0.8 * r
+		}
+	}
+
+	lag, peak, mean := CrossCorrelate(recorded, reference, maxLatencyMs)
+	if lag != delay {
+		t.Errorf("lag = %d, want %d", lag, delay)
+	}
+	if peak < 3*mean {
+		t.Errorf("peak %v is not clearly above the mean %v", peak, mean)
+	}
+}
+
+// loopback is a speaker and microphone on one clock: the mic hears the
+// click track delay late. Only Read and Samples of audio.MicInput are used.
+type loopback struct {
+	audio.MicInput
+	clicks  []byte
+	delay   int
+	pos     int
+	playing bool
+	buf     []float32
+}
+
+func (l *loopback) Play()  { l.playing = true }
+func (l *loopback) Pause() { l.playing = false }
+func (l *loopback) Position() time.Duration {
+	return time.Duration(l.pos) * time.Second / config.SampleRate
+}
+
+func (l *loopback) Read() error {
+	l.buf = make([]float32, config.BufferSize)
+	for i := range l.buf {
+		src := l.pos + i - l.delay
+		if src >= 0 && 4*src+1 < len(l.clicks) {
+			l.buf[i] = 0.5 * float32(int16(binary.LittleEndian.Uint16(l.clicks[4*src:]))) / 32768
+		}
+	}
+	l.pos += config.BufferSize
+	return nil
+}
+
+func (l *loopback) Samples() []float32 { return l.buf }
+
+func TestMeasureLoopback(t *testing.T) {
+	const testDuration = 4 * time.Second
+	for _, delayMs := range []int{20, 150, 300} {
+		l := &loopback{clicks: ClickPCM(testDuration), delay: delayMs * config.SampleRate / 1000}
+		got, err := Measure(l, l, testDuration)
+		if err != nil {
+			t.Fatalf("delay %d ms: Measure: %v", delayMs, err)
+		}
+		if got < float64(delayMs-2) || got > float64(delayMs+2) {
+			t.Errorf("measured %v ms, want %d ms", got, delayMs)
+		}
+		if l.playing {
+			t.Errorf("delay %d ms: player still playing after Measure", delayMs)
+		}
+	}
+}
+
+func TestMeasureWithoutClicks(t *testing.T) {
+	const testDuration = 2 * time.Second
+	l := &loopback{clicks: make([]byte, 4*config.SampleRate*2)}
+	if _, err := Measure(l, l, testDuration); err == nil {
+		t.Error("Measure succeeded on a silent recording")
+	}
+}
@@ -0,0 +1,204 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+// historySize is how many recent entries are kept in memory for in-app display.
+const historySize = 200
+
+/*
+Entry is one recorded log line.
+
+Fields:
+  - Time: When the entry was logged
+  - Level: Severity of the entry
+  - Message: Formatted message text
+*/
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+}
+
+var (
+	mu       sync.Mutex
+	minLevel = LevelInfo
+	history  []Entry
+)
+
+/*
+String returns the upper-case name of the level.
+
+Input:
+  - None
+
+Called by:
+  - logf when prefixing output
+
+Task:
+  - Human-readable level names
+
+Logic:
+ 1. Index into levelNames, fall back to "Level(<n>)"
+
+Output:
+  - string: Level name (e.g., "WARN")
+*/
+func (l Level) String() string {
+	if l >= 0 && int(l) < len(levelNames) {
+		return levelNames[l]
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+/*
+ParseLevel converts a level name into a Level.
+
+Input:
+  - name: string - "debug", "info", "warn" or "error" (case-insensitive)
+
+Called by:
+  - main.main when handling the -verbosity flag
+
+Task:
+  - Validate a user-supplied verbosity
+
+Logic:
+ 1. Compare the upper-cased name against levelNames ("WARNING" also accepted)
+
+Output:
+  - Level: Matching level
+  - error: nil on success, descriptive error otherwise
+*/
+func ParseLevel(name string) (Level, error) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	if upper == "WARNING" {
+		upper = "WARN"
+	}
+	for i, n := range levelNames {
+		if n == upper {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown verbosity %q (valid: debug, info, warn, error)", name)
+}
+
+/*
+SetLevel sets the minimum level that is printed.
+
+Input:
+  - l: Level - Entries below this level are dropped
+
+Called by:
+  - main.main after parsing -verbosity
+
+Task:
+  - Control output verbosity
+
+Logic:
+ 1. Store l under the mutex
+
+Output:
+  - None
+*/
+func SetLevel(l Level) {
+	mu.Lock()
+	minLevel = l
+	mu.Unlock()
+}
+
+/*
+Recent returns the retained entries at or above a level, oldest first.
+
+Input:
+  - min: Level - Lowest level to include
+
+Called by:
+  - In-app log display
+
+Task:
+  - Let the UI show and filter recent log output
+
+Logic:
+ 1. Copy matching entries from the history ring under the mutex
+
+Output:
+  - []Entry: Matching entries
+*/
+func Recent(min Level) []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+
+	out := make([]Entry, 0, len(history))
+	for _, e := range history {
+		if e.Level >= min {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+/*
+logf records and prints an entry if it passes the level filter.
+
+Input:
+  - l: Level - Entry severity
+  - format: string, args: ...any - Printf-style message
+
+Called by:
+  - Debugf, Infof, Warnf, Errorf
+
+Task:
+  - Shared implementation of the leveled helpers
+
+Logic:
+ 1. Drop entries below minLevel
+ 2. Append to history, trimming it to historySize
+ 3. Print via the standard logger with a "[LEVEL]" prefix
+
+Output:
+  - None
+*/
+func logf(l Level, format string, args ...any) {
+	mu.Lock()
+	if l < minLevel {
+		mu.Unlock()
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	history = append(history, Entry{Time: time.Now(), Level: l, Message: msg})
+	if len(history) > historySize {
+		history = history[len(history)-historySize:]
+	}
+	mu.Unlock()
+
+	log.Printf("[%s] %s", l, msg)
+}
+
+// Debugf logs detail that is only useful when diagnosing a problem.
+func Debugf(format string, args ...any) { logf(LevelDebug, format, args...) }
+
+// Infof logs normal progress messages.
+func Infof(format string, args ...any) { logf(LevelInfo, format, args...) }
+
+// Warnf logs recoverable problems.
+func Warnf(format string, args ...any) { logf(LevelWarn, format, args...) }
+
+// Errorf logs failures.
+func Errorf(format string, args ...any) { logf(LevelError, format, args...) }
@@ -0,0 +1,145 @@
+package lyrics
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+LyricLine is one timed line of an LRC file.
+
+Fields:
+  - Time: When the line starts, from the song start
+  - Text: Line text ("" for an instrumental break)
+*/
+type LyricLine struct {
+	Time time.Duration
+	Text string
+}
+
+// Lyrics is a song's lyric lines sorted by Time.
+type Lyrics []LyricLine
+
+// timestampRe matches one LRC timestamp such as [01:23.45] or [01:23].
+var timestampRe = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+/*
+LoadLRC reads a lyrics file in LRC format.
+
+Input:
+  - path: string - LRC file (config.SongPaths.LyricsFile)
+
+Called by:
+  - App.startGame
+
+Task:
+  - Turn "[mm:ss.xx]text" lines into sorted LyricLines
+
+Logic:
+ 1. Read line by line; strip every leading timestamp (a line may have several,
+    e.g. a repeated chorus) and keep the rest as the text
+ 2. Lines without a timestamp and tags such as [ar:Artist] are skipped
+ 3. Fractions are read as hundredths for two digits, milliseconds for three
+ 4. Sort by Time (stable, so equal times keep file order)
+
+Output:
+  - Lyrics: Parsed lines, possibly empty
+  - error: Read error
+*/
+func LoadLRC(path string) (Lyrics, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines Lyrics
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rest := strings.TrimSpace(scanner.Text())
+		var times []time.Duration
+		for {
+			m := timestampRe.FindStringSubmatch(rest)
+			if m == nil {
+				break
+			}
+			times = append(times, parseTimestamp(m[1], m[2], m[3]))
+			rest = rest[len(m[0]):]
+		}
+		text := strings.TrimSpace(rest)
+		for _, t := range times {
+			lines = append(lines, LyricLine{Time: t, Text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time < lines[j].Time })
+	return lines, nil
+}
+
+/*
+parseTimestamp converts the parts of an LRC timestamp to a duration.
+
+Input:
+  - mm, ss, frac: string - Minutes, seconds and optional fraction digits
+
+Called by:
+  - LoadLRC
+
+Task:
+  - Handle both [mm:ss.xx] and [mm:ss.xxx]
+
+Logic:
+ 1. minutes*60s + seconds
+ 2. Scale the fraction by its number of digits (".5" = 500ms, ".45" = 450ms)
+
+Output:
+  - time.Duration: Offset from the song start
+*/
+func parseTimestamp(mm, ss, frac string) time.Duration {
+	m, _ := strconv.Atoi(mm)
+	s, _ := strconv.Atoi(ss)
+	d := time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	if frac != "" {
+		f, _ := strconv.Atoi(frac)
+		for i := len(frac); i < 3; i++ {
+			f *= 10
+		}
+		d += time.Duration(f) * time.Millisecond
+	}
+	return d
+}
+
+/*
+CurrentLine returns the lyric line being sung at a position.
+
+Input:
+  - pos: time.Duration - Position from the song start
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Follow the song with its lyrics
+
+Logic:
+ 1. Binary-search the last line starting at or before pos
+ 2. "" before the first line
+
+Output:
+  - string: Active line text
+*/
+func (l Lyrics) CurrentLine(pos time.Duration) string {
+	i := sort.Search(len(l), func(i int) bool { return l[i].Time > pos })
+	if i == 0 {
+		return ""
+	}
+	return l[i-1].Text
+}
@@ -0,0 +1,74 @@
+package lyrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeLRC writes content to a lyrics.lrc in a temporary directory.
+func writeLRC(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "lyrics.lrc")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCurrentLine(t *testing.T) {
+	path := writeLRC(t, "[ar:Someone]\n[00:05.50]First line\n[00:12.00]Second line\n[01:02.345]Third line\n")
+	l, err := LoadLRC(path)
+	if err != nil {
+		t.Fatalf("LoadLRC: %v", err)
+	}
+	if len(l) != 3 {
+		t.Fatalf("parsed %d lines %v, want 3", len(l), l)
+	}
+
+	tests := []struct {
+		pos  time.Duration
+		want string
+	}{
+		{0, ""},
+		{5499 * time.Millisecond, ""},
+		{5500 * time.Millisecond, "First line"},
+		{11 * time.Second, "First line"},
+		{12 * time.Second, "Second line"},
+		{62344 * time.Millisecond, "Second line"},
+		{62345 * time.Millisecond, "Third line"},
+		{10 * time.Minute, "Third line"},
+	}
+	for _, tt := range tests {
+		if got := l.CurrentLine(tt.pos); got != tt.want {
+			t.Errorf("CurrentLine(%v) = %q, want %q", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestLoadLRCRepeatedAndUnsorted(t *testing.T) {
+	path := writeLRC(t, "[00:20]Verse\n[00:10][00:30]Chorus\nno timestamp here\n[00:25]\n")
+	l, err := LoadLRC(path)
+	if err != nil {
+		t.Fatalf("LoadLRC: %v", err)
+	}
+	want := Lyrics{
+		{10 * time.Second, "Chorus"},
+		{20 * time.Second, "Verse"},
+		{25 * time.Second, ""},
+		{30 * time.Second, "Chorus"},
+	}
+	if len(l) != len(want) {
+		t.Fatalf("parsed %v, want %v", l, want)
+	}
+	for i := range want {
+		if l[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, l[i], want[i])
+		}
+	}
+
+	if _, err := LoadLRC(filepath.Join(t.TempDir(), "missing.lrc")); err == nil {
+		t.Error("LoadLRC succeeded on a missing file")
+	}
+}
@@ -0,0 +1,289 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"singAssist/internal/config"
+	"singAssist/internal/theory"
+	"singAssist/internal/warmup"
+)
+
+// defaultLowMidi and defaultHighMidi bound the quiz notes (C3..C5) until the
+// vocal range test has been taken.
+const (
+	defaultLowMidi  = 48
+	defaultHighMidi = 72
+)
+
+// intervals are the quiz questions, in semitones above the root.
+var intervals = []struct {
+	semitones int
+	name      string
+}{
+	{1, "Minor 2nd"}, {2, "Major 2nd"}, {3, "Minor 3rd"}, {4, "Major 3rd"},
+	{5, "Perfect 4th"}, {6, "Tritone"}, {7, "Perfect 5th"}, {8, "Minor 6th"},
+	{9, "Major 6th"}, {10, "Minor 7th"}, {11, "Major 7th"}, {12, "Octave"},
+}
+
+/*
+IntervalQuizSession asks the user to sing intervals above a played root note.
+
+Fields:
+  - rng: Picks roots and intervals
+  - lowMidi, highMidi: MIDI range every root and target stays within
+  - root, target: Current question's notes in Hz (0 before the first question)
+  - interval: Current question's interval name (e.g. "Major 3rd")
+  - asked, correct: Answers recorded and how many were right
+  - feedback: Verdict on the last answer ("" before the first)
+*/
+type IntervalQuizSession struct {
+	rng               *rand.Rand
+	lowMidi, highMidi int
+	root, target      float64
+	interval          string
+	asked, correct    int
+	feedback          string
+}
+
+/*
+NewIntervalQuizSession creates a quiz within the user's vocal range.
+
+Input:
+  - vr: config.VocalRange - Saved range (zero when the range test was never taken)
+  - seed: uint64 - Random seed (e.g. the start time)
+
+Called by:
+  - App.startGame for ModeIntervalQuiz
+
+Task:
+  - Only ask for notes the user can sing
+
+Logic:
+ 1. Range = vr's notes rounded inwards to whole MIDI notes
+ 2. Fall back to C3..C5 when there is no range or it spans less than an
+    octave (every interval must fit above some root)
+
+Output:
+  - *IntervalQuizSession: Quiz ready for NextQuestion
+*/
+func NewIntervalQuizSession(vr config.VocalRange, seed uint64) *IntervalQuizSession {
+	q := &IntervalQuizSession{
+		rng:      rand.New(rand.NewPCG(seed, seed>>1|1)),
+		lowMidi:  defaultLowMidi,
+		highMidi: defaultHighMidi,
+	}
+	if vr.LowHz > 0 && vr.HighHz > vr.LowHz {
+		low, high := int(math.Ceil(theory.FreqToMidi(vr.LowHz)-0.01)), int(math.Floor(theory.FreqToMidi(vr.HighHz)+0.01))
+		if high-low >= 12 {
+			q.lowMidi, q.highMidi = low, high
+		}
+	}
+	return q
+}
+
+/*
+NextQuestion picks a new root and interval.
+
+Input:
+  - None
+
+Called by:
+  - App.tickIntervalQuiz at the start of each question
+
+Task:
+  - Ask a random interval the user can reach
+
+Logic:
+ 1. Random interval from intervals
+ 2. Random root so that root + interval stays within lowMidi..highMidi
+ 3. Store and return both notes in Hz with the interval name
+
+Output:
+  - root, target: float64 - Root played and note to sing, in Hz
+  - intervalName: string - e.g. "Major 3rd"
+*/
+func (q *IntervalQuizSession) NextQuestion() (root, target float64, intervalName string) {
+	iv := intervals[q.rng.IntN(len(intervals))]
+	rootMidi := q.lowMidi + q.rng.IntN(q.highMidi-iv.semitones-q.lowMidi+1)
+	q.root = warmup.MidiToFreq(rootMidi)
+	q.target = warmup.MidiToFreq(rootMidi + iv.semitones)
+	q.interval = iv.name
+	return q.root, q.target, q.interval
+}
+
+/*
+RecordUserAnswer judges what the user sang for the current question.
+
+Input:
+  - userPitch: float64 - Sung pitch in Hz (<= 10 for none)
+
+Called by:
+  - App.tickIntervalQuiz at the end of the listening window
+
+Task:
+  - Score the answer and word the verdict
+
+Logic:
+ 1. Correct when within config.QuizToleranceCents of the target
+ 2. Count the answer; feedback is "Correct! <interval>", "Almost - you sang
+    <note>, target was <note>", or "No pitch heard - target was <note>"
+
+Output:
+  - bool: true if correct
+*/
+func (q *IntervalQuizSession) RecordUserAnswer(userPitch float64) bool {
+	if q.target <= 0 {
+		return false
+	}
+	q.asked++
+	targetNote := noteName(q.target)
+	if userPitch <= 10 {
+		q.feedback = "No pitch heard - target was " + targetNote
+		return false
+	}
+	if math.Abs(theory.FreqToMidi(userPitch)-theory.FreqToMidi(q.target))*100 <= config.QuizToleranceCents {
+		q.correct++
+		q.feedback = "Correct! " + q.interval
+		return true
+	}
+	q.feedback = fmt.Sprintf("Almost - you sang %s, target was %s", noteName(userPitch), targetNote)
+	return false
+}
+
+/*
+Score returns the share of correct answers.
+
+Input:
+  - None
+
+Called by:
+  - App.drawIntervalQuiz
+
+Task:
+  - Summarise the session
+
+Logic:
+ 1. correct / asked × 100, 0 before the first answer
+
+Output:
+  - float64: Percentage 0..100
+*/
+func (q *IntervalQuizSession) Score() float64 {
+	if q.asked == 0 {
+		return 0
+	}
+	return float64(q.correct) / float64(q.asked) * 100
+}
+
+/*
+Interval returns the current question's interval name.
+
+Input:
+  - None
+
+Called by:
+  - App.drawIntervalQuiz for the prompt
+
+Task:
+  - Expose the question without asking a new one
+
+Logic:
+ 1. Return interval ("" before the first question)
+
+Output:
+  - string: e.g. "Major 3rd"
+*/
+func (q *IntervalQuizSession) Interval() string {
+	return q.interval
+}
+
+/*
+Question returns the current question's notes.
+
+Input:
+  - None
+
+Called by:
+  - App.drawIntervalQuiz to name the root
+
+Task:
+  - Expose the question without asking a new one
+
+Logic:
+ 1. Return root and target (0, 0 before the first question)
+
+Output:
+  - root, target: float64 - Notes in Hz
+*/
+func (q *IntervalQuizSession) Question() (root, target float64) {
+	return q.root, q.target
+}
+
+/*
+Feedback returns the verdict on the last answer.
+
+Input:
+  - None
+
+Called by:
+  - App.drawIntervalQuiz after an answer
+
+Task:
+  - Show the user how they did
+
+Logic:
+ 1. Return feedback (set by RecordUserAnswer)
+
+Output:
+  - string: e.g. "Correct! Major 3rd" ("" before the first answer)
+*/
+func (q *IntervalQuizSession) Feedback() string {
+	return q.feedback
+}
+
+/*
+Counts returns how many answers were recorded and how many were right.
+
+Input:
+  - None
+
+Called by:
+  - App.drawIntervalQuiz for "3/4"
+
+Task:
+  - Expose the tally behind Score
+
+Logic:
+ 1. Return correct and asked
+
+Output:
+  - correct, asked: int - Tally so far
+*/
+func (q *IntervalQuizSession) Counts() (correct, asked int) {
+	return q.correct, q.asked
+}
+
+/*
+noteName names a frequency with its octave.
+
+Input:
+  - freq: float64 - Frequency in Hz
+
+Called by:
+  - RecordUserAnswer for the feedback
+
+Task:
+  - Word notes the way the HUD does
+
+Logic:
+ 1. theory.FreqToNote, joined as <note><octave>
+
+Output:
+  - string: e.g. "G#4"
+*/
+func noteName(freq float64) string {
+	note, octave := theory.FreqToNote(freq)
+	return fmt.Sprintf("%s%d", note, octave)
+}
@@ -0,0 +1,94 @@
+package quiz
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"singAssist/internal/config"
+	"singAssist/internal/theory"
+)
+
+// centsFrom returns the pitch cents away from hz.
+func centsFrom(hz, cents float64) float64 {
+	return hz * math.Pow(2, cents/1200)
+}
+
+func TestRecordUserAnswerCentOffsets(t *testing.T) {
+	tests := []struct {
+		cents float64
+		want  bool
+	}{
+		{0, true},
+		{20, true},
+		{-20, true},
+		{49, true},
+		{-49, true},
+		{51, false},
+		{-60, false},
+		{100, false},
+		{-1200, false},
+	}
+	for _, tt := range tests {
+		q := NewIntervalQuizSession(config.VocalRange{}, 1)
+		_, target, name := q.NextQuestion()
+		if got := q.RecordUserAnswer(centsFrom(target, tt.cents)); got != tt.want {
+			t.Errorf("%+.0f cents from the target: correct = %v, want %v", tt.cents, got, tt.want)
+		}
+		if tt.want && q.Feedback() != "Correct! "+name {
+			t.Errorf("%+.0f cents: feedback %q, want %q", tt.cents, q.Feedback(), "Correct! "+name)
+		}
+		if !tt.want && !strings.HasPrefix(q.Feedback(), "Almost - you sang ") {
+			t.Errorf("%+.0f cents: feedback %q, want the sung note named", tt.cents, q.Feedback())
+		}
+	}
+}
+
+func TestRecordUserAnswerSilenceAndNoQuestion(t *testing.T) {
+	q := NewIntervalQuizSession(config.VocalRange{}, 1)
+	if q.RecordUserAnswer(440) {
+		t.Error("an answer before the first question was counted correct")
+	}
+	if _, asked := q.Counts(); asked != 0 {
+		t.Errorf("answer before the first question counted: asked = %d", asked)
+	}
+
+	q.NextQuestion()
+	if q.RecordUserAnswer(0) {
+		t.Error("silence was counted correct")
+	}
+	if !strings.HasPrefix(q.Feedback(), "No pitch heard") {
+		t.Errorf("feedback for silence = %q", q.Feedback())
+	}
+}
+
+func TestScoreCountsCorrectShare(t *testing.T) {
+	q := NewIntervalQuizSession(config.VocalRange{}, 7)
+	if got := q.Score(); got != 0 {
+		t.Errorf("score before any answer = %v, want 0", got)
+	}
+	for _, cents := range []float64{0, 30, 80, -200} {
+		_, target, _ := q.NextQuestion()
+		q.RecordUserAnswer(centsFrom(target, cents))
+	}
+	if got := q.Score(); got != 50 {
+		t.Errorf("score after 2 of 4 right = %v, want 50", got)
+	}
+}
+
+func TestNextQuestionStaysInRange(t *testing.T) {
+	// A3..A4 is exactly an octave, so every root is A3 up to A4 minus the interval.
+	vr := config.VocalRange{LowHz: 220, HighHz: 440}
+	q := NewIntervalQuizSession(vr, 3)
+	for range 200 {
+		root, target, name := q.NextQuestion()
+		lo, hi := theory.FreqToMidi(root), theory.FreqToMidi(target)
+		if lo < 57-0.01 || hi > 69+0.01 {
+			t.Fatalf("%s from %.2f to %.2f Hz leaves the A3..A4 range", name, root, target)
+		}
+		semis := int(math.Round(hi - lo))
+		if semis < 1 || semis > 12 || intervals[semis-1].name != name {
+			t.Fatalf("question %q spans %d semitones", name, semis)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package scoring
+
+import (
+	"encoding/json"
+	"os"
+
+	"singAssist/internal/config"
+)
+
+/*
+BestSession is the pitch trail of a song's best-scoring run (best_session.json).
+
+Fields:
+  - Score: The run's score 0..100
+  - Mode: audio.Mode name the run was sung in
+  - UserPitch: Recorded pairs [timeMs, pitch, ...] in the song's key (unpruned)
+*/
+type BestSession struct {
+	Score     float64   `json:"score"`
+	Mode      string    `json:"mode"`
+	UserPitch []float64 `json:"user_pitch"`
+}
+
+/*
+SaveBestSession stores a run's pitch trail as the song's ghost.
+
+Input:
+  - songDir: string - Song folder
+  - r: SessionResult - The run's score
+  - userPitch: []float64 - The run's recorded pairs [timeMs, pitch, ...]
+
+Called by:
+  - App.finishSong when a run beats the saved best score
+
+Task:
+  - Keep the best run around to sing against next time
+
+Logic:
+ 1. Marshal a BestSession (compact, the trail can be long)
+ 2. Write to a temp file and rename it over config.SongPaths.BestSessionFile
+
+Output:
+  - error: nil on success
+*/
+func SaveBestSession(songDir string, r SessionResult, userPitch []float64) error {
+	data, err := json.Marshal(BestSession{Score: r.Score, Mode: r.Mode, UserPitch: userPitch})
+	if err != nil {
+		return err
+	}
+
+	path := config.GetSongPaths(songDir).BestSessionFile
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+/*
+LoadBestSession reads the ghost trail saved by SaveBestSession.
+
+Input:
+  - songDir: string - Song folder
+
+Called by:
+  - App.startGame
+
+Task:
+  - Load the ghost shown behind the live trail
+
+Logic:
+ 1. Missing file: empty trail, no error (no run has been saved yet)
+ 2. Unmarshal the BestSession and return its pitch pairs
+
+Output:
+  - []float64: Pairs [timeMs, pitch, ...] (empty when there is no best run)
+  - error: Read or decode error
+*/
+func LoadBestSession(songDir string) ([]float64, error) {
+	data, err := os.ReadFile(config.GetSongPaths(songDir).BestSessionFile)
+	if os.IsNotExist(err) {
+		return []float64{}, nil
+	}
+	if err != nil {
+		return []float64{}, err
+	}
+
+	var s BestSession
+	if err := json.Unmarshal(data, &s); err != nil {
+		return []float64{}, err
+	}
+	if s.UserPitch == nil {
+		return []float64{}, nil
+	}
+	return s.UserPitch, nil
+}
@@ -0,0 +1,48 @@
+package scoring
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestLoadBestSessionMissing(t *testing.T) {
+	got, err := LoadBestSession(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadBestSession without a saved run: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("LoadBestSession = %#v, want an empty, non-nil slice", got)
+	}
+}
+
+func TestBestSessionRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	trail := []float64{0, 220, 10, 0, 20, 221.5}
+	if err := SaveBestSession(dir, SessionResult{Score: 87.5, Mode: "Singing"}, trail); err != nil {
+		t.Fatalf("SaveBestSession: %v", err)
+	}
+	got, err := LoadBestSession(dir)
+	if err != nil || !slices.Equal(got, trail) {
+		t.Errorf("LoadBestSession = %v, %v; want %v", got, err, trail)
+	}
+	if _, err := os.Stat(config.GetSongPaths(dir).BestSessionFile + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+func TestLoadBestSessionCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(config.GetSongPaths(dir).BestSessionFile, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadBestSession(dir)
+	if err == nil {
+		t.Error("LoadBestSession on a corrupt file succeeded, want an error")
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("LoadBestSession = %#v, want an empty slice alongside the error", got)
+	}
+}
@@ -0,0 +1,60 @@
+package scoring
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"singAssist/internal/config"
+)
+
+/*
+FreestyleSession is a recorded freestyle run (freestyle_<timestamp>.json).
+
+Fields:
+  - RecordedAt: When the session ended
+  - Seconds: Session length
+  - UserPitch: Recorded pairs [timeMs, pitch, ...] (unpruned, no reference)
+*/
+type FreestyleSession struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	Seconds    float64   `json:"seconds"`
+	UserPitch  []float64 `json:"user_pitch"`
+}
+
+/*
+SaveFreestyle stores a freestyle pitch journal for later review.
+
+Input:
+  - songDir: string - Song folder the session belongs to
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...]
+  - at: time.Time - When the session ended (names the file)
+
+Called by:
+  - App.finishFreestyle
+
+Task:
+  - Keep each freestyle session; there is nothing to score it against
+
+Logic:
+ 1. Session length is the last recorded timestamp
+ 2. Marshal a FreestyleSession (compact, the trail can be long)
+ 3. Write it to config.FreestylePath
+
+Output:
+  - string: Path written
+  - error: nil on success
+*/
+func SaveFreestyle(songDir string, userPitch []float64, at time.Time) (string, error) {
+	s := FreestyleSession{RecordedAt: at, UserPitch: userPitch}
+	if n := len(userPitch); n >= 2 {
+		s.Seconds = userPitch[n-2] / 1000
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	path := config.FreestylePath(songDir, at)
+	return path, os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,192 @@
+package scoring
+
+import (
+	"math"
+
+	"singAssist/internal/theory"
+)
+
+const (
+	HeatmapMinMidi   = 36    // C2, lowest target note tracked
+	HeatmapMaxMidi   = 84    // C6, highest target note tracked
+	HeatmapCentsSpan = 300.0 // Offsets beyond ±span are clamped to the edge bins
+	HeatmapCentsBin  = 25.0  // Width of one offset bin in cents
+)
+
+/*
+CentsError returns how far the sung pitch is from the target in cents.
+
+Input:
+  - user: float64 - Sung frequency in Hz
+  - ref: float64 - Target (song) frequency in Hz
+  - ignoreOctave: bool - Fold the error into ±600 cents (octave-agnostic scoring)
+
+Called by:
+  - Heatmap.Add for binning
+
+Task:
+  - Signed pitch error; positive = sharp, negative = flat
+
+Logic:
+ 1. (FreqToMidi(user) - FreqToMidi(ref)) * 100
+ 2. If ignoreOctave: wrap into [-600, 600)
+
+Output:
+  - float64: Error in cents
+*/
+func CentsError(user, ref float64, ignoreOctave bool) float64 {
+	cents := (theory.FreqToMidi(user) - theory.FreqToMidi(ref)) * 100
+	if ignoreOctave {
+		cents = math.Mod(cents+600, 1200)
+		if cents < 0 {
+			cents += 1200
+		}
+		cents -= 600
+	}
+	return cents
+}
+
+/*
+Heatmap accumulates how long the user sang at each offset from each target note.
+
+Fields:
+  - Cells: Seconds spent, indexed [targetMidi-HeatmapMinMidi][centsBin]
+  - Total: Total seconds added
+  - IgnoreOctave: Fold errors by octave before binning
+*/
+type Heatmap struct {
+	Cells        [][]float64
+	Total        float64
+	IgnoreOctave bool
+}
+
+/*
+NewHeatmap creates an empty heatmap covering HeatmapMinMidi..HeatmapMaxMidi.
+
+Input:
+  - ignoreOctave: bool - Use octave-agnostic cents errors
+
+Called by:
+  - App.startGame when a new session begins
+
+Task:
+  - Allocate the note x offset grid
+
+Logic:
+ 1. One row per target MIDI note
+ 2. One column per HeatmapCentsBin across ±HeatmapCentsSpan
+
+Output:
+  - *Heatmap: Empty heatmap
+*/
+func NewHeatmap(ignoreOctave bool) *Heatmap {
+	bins := HeatmapBins()
+	cells := make([][]float64, HeatmapMaxMidi-HeatmapMinMidi+1)
+	for i := range cells {
+		cells[i] = make([]float64, bins)
+	}
+	return &Heatmap{Cells: cells, IgnoreOctave: ignoreOctave}
+}
+
+/*
+HeatmapBins returns the number of cents bins in a heatmap row.
+
+Input:
+  - None
+
+Called by:
+  - NewHeatmap, ui.DrawHeatmap callers
+
+Task:
+  - Share the bin count between building and drawing
+
+Logic:
+ 1. 2*span/binWidth
+
+Output:
+  - int: Bin count
+*/
+func HeatmapBins() int {
+	return int(2 * HeatmapCentsSpan / HeatmapCentsBin)
+}
+
+/*
+Add records one sung frame against its target.
+
+Input:
+  - user: float64 - Sung frequency in Hz (ignored if <= 10)
+  - ref: float64 - Target frequency in Hz (ignored if <= 10)
+  - seconds: float64 - Duration the frame represents
+
+Called by:
+  - App.micLoop for every recorded mic frame
+
+Task:
+  - Bin the frame by target note and cents offset
+
+Logic:
+ 1. Skip when either pitch is unvoiced
+ 2. Row = round(FreqToMidi(ref)), skip if outside tracked range
+ 3. Column = CentsError clamped to ±HeatmapCentsSpan
+ 4. Add seconds to the cell and to Total
+
+Output:
+  - None (updates h)
+*/
+func (h *Heatmap) Add(user, ref, seconds float64) {
+	if user <= 10 || ref <= 10 {
+		return
+	}
+	row := int(math.Round(theory.FreqToMidi(ref))) - HeatmapMinMidi
+	if row < 0 || row >= len(h.Cells) {
+		return
+	}
+
+	bins := len(h.Cells[row])
+	cents := CentsError(user, ref, h.IgnoreOctave)
+	col := int(math.Floor((cents + HeatmapCentsSpan) / HeatmapCentsBin))
+	if col < 0 {
+		col = 0
+	}
+	if col >= bins {
+		col = bins - 1
+	}
+
+	h.Cells[row][col] += seconds
+	h.Total += seconds
+}
+
+/*
+UsedRange returns the first and last rows that contain any data.
+
+Input:
+  - None
+
+Called by:
+  - App.Draw before calling ui.DrawHeatmap
+
+Task:
+  - Let the view zoom in on the notes the song actually uses
+
+Logic:
+ 1. Scan rows for any non-zero cell
+ 2. Return (-1, -1) if the heatmap is empty
+
+Output:
+  - first, last: int - Row indices (add HeatmapMinMidi for MIDI)
+*/
+func (h *Heatmap) UsedRange() (first, last int) {
+	first, last = -1, -1
+	for i, row := range h.Cells {
+		for _, v := range row {
+			if v > 0 {
+				if first < 0 {
+					first = i
+				}
+				last = i
+				break
+			}
+		}
+	}
+	return first, last
+}
@@ -0,0 +1,145 @@
+package scoring
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"singAssist/internal/config"
+)
+
+// historyMu serializes appends and rotation within this process; writes from
+// other processes rely on O_APPEND, with each entry written as a single line.
+var historyMu sync.Mutex
+
+/*
+HistoryEntry is one completed practice run in the history log.
+
+Fields:
+  - PlayedAt: When the run ended
+  - Song: Song folder name
+  - Mode: audio.Mode name (e.g. "vocals")
+  - Score: Hit percentage 0..100
+  - Seconds: How long the run lasted
+  - Singer: 1 or 2 for duet runs, 0 otherwise
+*/
+type HistoryEntry struct {
+	PlayedAt time.Time `json:"played_at"`
+	Song     string    `json:"song"`
+	Mode     string    `json:"mode"`
+	Score    float64   `json:"score"`
+	Seconds  float64   `json:"seconds"`
+	Singer   int       `json:"singer,omitempty"`
+}
+
+/*
+AppendHistory adds one run to the JSON-lines history file.
+
+Input:
+  - path: string - History file (usually config.HistoryPath())
+  - e: HistoryEntry - Run to record
+
+Called by:
+  - App.recordHistory when leaving a session
+
+Task:
+  - Persist progress across sessions without rewriting the whole file
+
+Logic:
+ 1. Lock historyMu
+ 2. If the file exceeds config.HistoryMaxBytes: rotate it to <path>.1
+ 3. Create the parent directory if needed
+ 4. Open with O_APPEND|O_CREATE and write the entry as one JSON line
+
+Output:
+  - error: nil on success, marshal/filesystem error otherwise
+*/
+func AppendHistory(path string, e HistoryEntry) error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if info, err := os.Stat(path); err == nil && info.Size() > config.HistoryMaxBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("rotating history: %w", err)
+		}
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+/*
+LoadHistory reads the most recent runs from the history file.
+
+Input:
+  - path: string - History file
+  - n: int - Maximum number of entries to return
+
+Called by:
+  - App.openHistory for the history screen
+
+Task:
+  - Newest-first list of recent runs
+
+Logic:
+ 1. Missing file: return no entries, no error
+ 2. Decode each line, skipping malformed ones (e.g. a torn write)
+ 3. Keep the last n and reverse to newest first
+
+Output:
+  - []HistoryEntry: Up to n entries, newest first
+  - error: Filesystem error other than "not exist"
+*/
+func LoadHistory(path string, n int) ([]HistoryEntry, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e HistoryEntry
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
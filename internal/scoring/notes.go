@@ -0,0 +1,77 @@
+package scoring
+
+import (
+	"math"
+
+	"singAssist/internal/theory"
+)
+
+/*
+NoteSegment is a run of song frames that hold the same note.
+
+Fields:
+  - StartFrame: First 10ms frame of the note
+  - EndFrame: Frame after the last one (exclusive)
+  - Midi: Rounded MIDI note number
+*/
+type NoteSegment struct {
+	StartFrame int
+	EndFrame   int
+	Midi       int
+}
+
+/*
+SegmentNotes quantizes a pitch contour into discrete notes.
+
+Input:
+  - pitches: []float64 - Pitch values at 10ms intervals (0 = silence)
+  - minFrames: int - Shortest segment kept (shorter runs are treated as glides/noise)
+
+Called by:
+  - App.drawPlayingMode for the piano-roll view
+
+Task:
+  - Turn a continuous contour into targetable note blocks
+
+Logic:
+ 1. Round each voiced frame to the nearest MIDI note
+ 2. Start a new segment whenever the rounded note changes or silence begins
+ 3. Allow a single-frame blip back to the previous note without splitting
+ 4. Drop segments shorter than minFrames
+
+Output:
+  - []NoteSegment: Segments in time order
+*/
+func SegmentNotes(pitches []float64, minFrames int) []NoteSegment {
+	var segs []NoteSegment
+	cur := NoteSegment{StartFrame: -1}
+
+	flush := func(end int) {
+		if cur.StartFrame >= 0 && end-cur.StartFrame >= minFrames {
+			cur.EndFrame = end
+			segs = append(segs, cur)
+		}
+		cur = NoteSegment{StartFrame: -1}
+	}
+
+	for i, p := range pitches {
+		if p <= 10 {
+			flush(i)
+			continue
+		}
+		midi := int(math.Round(theory.FreqToMidi(p)))
+		if cur.StartFrame >= 0 && midi != cur.Midi {
+			next := i + 1
+			if next < len(pitches) && pitches[next] > 10 && int(math.Round(theory.FreqToMidi(pitches[next]))) == cur.Midi {
+				continue
+			}
+			flush(i)
+		}
+		if cur.StartFrame < 0 {
+			cur = NoteSegment{StartFrame: i, Midi: midi}
+		}
+	}
+	flush(len(pitches))
+
+	return segs
+}
@@ -0,0 +1,318 @@
+package scoring
+
+import (
+	"math"
+	"time"
+
+	"singAssist/internal/config"
+	"singAssist/internal/theory"
+)
+
+// HitSemitones is how close (in semitones) the user must be to count a hit
+// where no per-song tolerance applies (settings.json, see config.SongSettings).
+const HitSemitones = config.DefaultHitTolerance
+
+/*
+SessionResult is the score of one practice run.
+
+Fields:
+  - Score: Hit percentage 0..100
+  - TotalFrames: Voiced song frames (10ms) the user was scored on
+  - HitFrames: Of those, frames sung within HitSemitones
+  - Mode: audio.Mode name (e.g. "vocals")
+  - SongName: Song folder name
+  - PlayedAt: When the run ended
+  - Singer: 1 or 2 for duet runs (each singer is scored separately), 0 otherwise
+  - File: Session file the result was read from by ListSessions (not stored)
+*/
+type SessionResult struct {
+	Score       float64   `json:"score"`
+	TotalFrames int       `json:"total_frames"`
+	HitFrames   int       `json:"hit_frames"`
+	Mode        string    `json:"mode"`
+	SongName    string    `json:"song"`
+	PlayedAt    time.Time `json:"played_at"`
+	Singer      int       `json:"singer,omitempty"`
+	File        string    `json:"-"`
+}
+
+/*
+HitRule decides whether a user pitch hits a voiced song pitch.
+
+Input:
+  - user: float64 - User pitch in Hz (0 = silence)
+  - ref: float64 - Song pitch in Hz (always voiced)
+
+Output:
+  - bool: true for a hit
+*/
+type HitRule func(user, ref float64) bool
+
+/*
+NoteHit returns the normal rule: the user is on the song note.
+
+Input:
+  - ignoreOctave: bool - Accept the right note in any octave (-octave-agnostic)
+  - tolerance: float64 - Hit distance in semitones (the song's hit tolerance, or HitSemitones)
+
+Called by:
+  - ComputeScore, ComputeSessionStats, App.hitRule
+
+Task:
+  - The hit test shared by scoring and statistics
+
+Logic:
+ 1. Hit when the user is voiced and within tolerance of the song
+
+Output:
+  - HitRule: The rule
+*/
+func NoteHit(ignoreOctave bool, tolerance float64) HitRule {
+	return func(user, ref float64) bool {
+		return user > 10 && theory.SemitoneDistance(user, ref, ignoreOctave) < tolerance
+	}
+}
+
+/*
+HarmonyHit returns the harmony trainer's rule: the user is on a target harmony.
+
+Input:
+  - offsets: []int - Target harmonies in semitones from the melody (harmony.json)
+  - ignoreOctave: bool - Accept a harmony note in any octave
+  - tolerance: float64 - Hit distance in semitones, as in NoteHit
+
+Called by:
+  - ComputeHarmonyScore, App.hitRule
+
+Task:
+  - Harmony-mode hit test shared by scoring and statistics
+
+Logic:
+ 1. Hit when theory.MatchesHarmony finds the user within tolerance of the
+    melody shifted by any offset
+
+Output:
+  - HitRule: The rule
+*/
+func HarmonyHit(offsets []int, ignoreOctave bool, tolerance float64) HitRule {
+	return func(user, ref float64) bool {
+		return theory.MatchesHarmony(user, ref, offsets, tolerance, ignoreOctave)
+	}
+}
+
+/*
+ComputeScore scores a recorded pitch trail against the song.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...] (unpruned)
+  - songPitch: []float64 - Song pitch at 10ms intervals
+  - latencyMs: float64 - Output latency; user time t sings song time t-latency
+  - ignoreOctave: bool - Accept the right note in any octave (-octave-agnostic)
+  - tolerance: float64 - Hit distance in semitones (the song's hit tolerance)
+
+Called by:
+  - App.sessionResults, App.liveScorer
+
+Task:
+  - Fraction of voiced song frames the user hit, over the part they sang through
+
+Logic:
+ 1. Count the covered song frames with scoreFrames
+ 2. Frames where the user is within tolerance count as hits (NoteHit)
+
+Output:
+  - SessionResult: Score and frame counts (Mode, SongName, PlayedAt left empty)
+*/
+func ComputeScore(userPitch, songPitch []float64, latencyMs float64, ignoreOctave bool, tolerance float64) SessionResult {
+	return scoreFrames(userPitch, songPitch, latencyMs, NoteHit(ignoreOctave, tolerance))
+}
+
+/*
+ComputeHarmonyScore scores a harmony part against the song melody.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...] (unpruned)
+  - songPitch: []float64 - Song (melody) pitch at 10ms intervals
+  - latencyMs: float64 - Output latency, as in ComputeScore
+  - offsets: []int - Target harmonies in semitones from the melody (harmony.json)
+  - ignoreOctave: bool - Accept a harmony note in any octave
+  - tolerance: float64 - Hit distance in semitones, as in ComputeScore
+
+Called by:
+  - App.sessionResults in harmony mode, App.LiveStatus
+
+Task:
+  - Fraction of voiced song frames sung on one of the target harmonies
+
+Logic:
+ 1. Count frames exactly like ComputeScore, with the HarmonyHit rule
+
+Output:
+  - SessionResult: Score and frame counts (Mode, SongName, PlayedAt left empty)
+*/
+func ComputeHarmonyScore(userPitch, songPitch []float64, latencyMs float64, offsets []int, ignoreOctave bool, tolerance float64) SessionResult {
+	return scoreFrames(userPitch, songPitch, latencyMs, HarmonyHit(offsets, ignoreOctave, tolerance))
+}
+
+/*
+scoreFrames counts the hits over the song frames a recorded trail covers.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...]
+  - songPitch: []float64 - Song pitch at 10ms intervals
+  - latencyMs: float64 - Output latency; user time t sings song time t-latency
+  - hit: HitRule - Whether a user pitch hits a voiced song pitch
+
+Called by:
+  - ComputeScore, ComputeHarmonyScore
+
+Task:
+  - Turn frame hits into a score
+
+Logic:
+ 1. Visit the voiced song frames with walkFrames
+ 2. Every one counts toward TotalFrames, hits toward HitFrames
+ 3. Score = 100 * HitFrames / TotalFrames (0 when nothing was voiced)
+
+Output:
+  - SessionResult: Score and frame counts
+*/
+func scoreFrames(userPitch, songPitch []float64, latencyMs float64, hit HitRule) SessionResult {
+	var r SessionResult
+	walkFrames(userPitch, songPitch, latencyMs, func(user, ref float64) {
+		r.TotalFrames++
+		if hit(user, ref) {
+			r.HitFrames++
+		}
+	})
+
+	if r.TotalFrames > 0 {
+		r.Score = 100 * float64(r.HitFrames) / float64(r.TotalFrames)
+	}
+	return r
+}
+
+/*
+ClosestPart merges several song parts into the one the user followed.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...] (unpruned)
+  - parts: [][]float64 - Pitch of each vocal part at 10ms intervals (LoadResult.SongPitches)
+  - latencyMs: float64 - Output latency, as in ComputeScore
+
+Called by:
+  - App.sessionResults, App.finishSong for songs with several vocal parts
+
+Task:
+  - Score a singer against whichever part they are closest to at each frame
+
+Logic:
+ 1. Start every frame from the first part that is voiced there
+ 2. Walk the frames the trail covers (same alignment as walkFrames); where the
+    user is voiced, take the voiced part with the smallest SemitoneDistance
+
+Output:
+  - []float64: Song pitch at 10ms intervals, as long as the longest part
+*/
+func ClosestPart(userPitch []float64, parts [][]float64, latencyMs float64) []float64 {
+	n := 0
+	for _, part := range parts {
+		n = max(n, len(part))
+	}
+	song := make([]float64, n)
+	for s := range song {
+		for _, part := range parts {
+			if s < len(part) && part[s] > 10 {
+				song[s] = part[s]
+				break
+			}
+		}
+	}
+
+	walkSpans(userPitch, n, latencyMs, func(s int, user float64) {
+		if user <= 10 {
+			return
+		}
+		best := math.Inf(1)
+		for _, part := range parts {
+			if s >= len(part) || part[s] <= 10 {
+				continue
+			}
+			if d := theory.SemitoneDistance(user, part[s], false); d < best {
+				song[s], best = part[s], d
+			}
+		}
+	})
+	return song
+}
+
+/*
+walkFrames visits every voiced song frame a recorded trail covers.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...]
+  - songPitch: []float64 - Song pitch at 10ms intervals
+  - latencyMs: float64 - Output latency; user time t sings song time t-latency
+  - visit: func(user, ref float64) - Called with the user and song pitch of each frame
+
+Called by:
+  - scoreFrames, ComputeSessionStatsWith
+
+Task:
+  - Shared frame alignment for every scoring rule and statistic
+
+Logic:
+ 1. Walk the covered song frames in order with walkSpans
+ 2. Skip unvoiced song frames (<= 10 Hz)
+
+Output:
+  - None (calls visit)
+*/
+func walkFrames(userPitch, songPitch []float64, latencyMs float64, visit func(user, ref float64)) {
+	walkSpans(userPitch, len(songPitch), latencyMs, func(s int, user float64) {
+		if ref := songPitch[s]; ref > 10 {
+			visit(user, ref)
+		}
+	})
+}
+
+/*
+walkSpans visits every song frame index a recorded trail covers.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...]
+  - frames: int - Number of 10ms song frames
+  - latencyMs: float64 - Output latency; user time t sings song time t-latency
+  - visit: func(s int, user float64) - Called with each frame index and the user pitch there
+
+Called by:
+  - walkFrames, ClosestPart
+
+Task:
+  - Map user samples to song frames in one place
+
+Logic:
+ 1. Each user sample holds from its time until the next sample, capped at one
+    mic buffer (so seeks don't stretch a sample across skipped audio)
+ 2. Shift that span by latencyMs and walk the song frames it covers, in order
+
+Output:
+  - None (calls visit)
+*/
+func walkSpans(userPitch []float64, frames int, latencyMs float64, visit func(s int, user float64)) {
+	bufferMs := float64(config.BufferSize) / config.SampleRate * 1000
+
+	for i := 0; i+1 < len(userPitch); i += 2 {
+		t, p := userPitch[i], userPitch[i+1]
+		end := t + bufferMs
+		if i+2 < len(userPitch) && userPitch[i+2] > t && userPitch[i+2] < end {
+			end = userPitch[i+2]
+		}
+
+		from := max(0, int((t-latencyMs)/10))
+		to := min(frames, int((end-latencyMs)/10))
+		for s := from; s < to; s++ {
+			visit(s, p)
+		}
+	}
+}
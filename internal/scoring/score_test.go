@@ -0,0 +1,124 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// constSong returns n 10ms frames of freq.
+func constSong(n int, freq float64) []float64 {
+	song := make([]float64, n)
+	for i := range song {
+		song[i] = freq
+	}
+	return song
+}
+
+// trail records pitch(i) every 10ms for frames 0..n-1, starting at startMs.
+func trail(n int, startMs float64, pitch func(i int) float64) []float64 {
+	var out []float64
+	for i := range n {
+		out = append(out, startMs+float64(10*i), pitch(i))
+	}
+	return out
+}
+
+func TestComputeScore(t *testing.T) {
+	// 50 voiced frames of A3 followed by 50 silent ones.
+	song := append(constSong(50, 220), make([]float64, 50)...)
+	semitoneUp := 220 * math.Pow(2, 1.0/12)
+
+	tests := []struct {
+		name         string
+		user         []float64
+		latencyMs    float64
+		ignoreOctave bool
+		tolerance    float64
+		wantTotal    int
+		wantHits     int
+	}{
+		{"all on pitch", trail(100, 0, func(int) float64 { return 220 }), 0, false, 0.7, 50, 50},
+		{"half a semitone off", trail(100, 0, func(i int) float64 {
+			if i < 25 {
+				return 220
+			}
+			return semitoneUp
+		}), 0, false, 0.7, 50, 25},
+		{"wide tolerance", trail(100, 0, func(int) float64 { return semitoneUp }), 0, false, 1.5, 50, 50},
+		{"silent user", trail(100, 0, func(int) float64 { return 0 }), 0, false, 0.7, 50, 0},
+		{"octave up", trail(100, 0, func(int) float64 { return 440 }), 0, false, 0.7, 50, 0},
+		{"octave up, octave-agnostic", trail(100, 0, func(int) float64 { return 440 }), 0, true, 0.7, 50, 50},
+		{"latency shifts the trail", trail(100, 200, func(i int) float64 {
+			if i < 50 {
+				return 220
+			}
+			return 0
+		}), 200, false, 0.7, 50, 50},
+		// The last sample holds for one mic buffer (~46ms): frames 19-22.
+		{"only the sung part counts", trail(20, 0, func(int) float64 { return 220 }), 0, false, 0.7, 23, 23},
+	}
+	for _, tt := range tests {
+		got := ComputeScore(tt.user, song, tt.latencyMs, tt.ignoreOctave, tt.tolerance)
+		if got.TotalFrames != tt.wantTotal || got.HitFrames != tt.wantHits {
+			t.Errorf("%s: %d/%d frames hit, want %d/%d", tt.name, got.HitFrames, got.TotalFrames, tt.wantHits, tt.wantTotal)
+		}
+		if want := 100 * float64(tt.wantHits) / float64(tt.wantTotal); math.Abs(got.Score-want) > 1e-9 {
+			t.Errorf("%s: score %v, want %v", tt.name, got.Score, want)
+		}
+	}
+}
+
+func TestComputeScoreEmpty(t *testing.T) {
+	if got := ComputeScore(nil, constSong(10, 220), 0, false, 0.7); got.TotalFrames != 0 || got.Score != 0 {
+		t.Errorf("empty trail = %+v, want a zero result", got)
+	}
+	user := trail(10, 0, func(int) float64 { return 220 })
+	if got := ComputeScore(user, make([]float64, 10), 0, false, 0.7); got.TotalFrames != 0 || got.Score != 0 {
+		t.Errorf("silent song = %+v, want a zero result", got)
+	}
+}
+
+func TestComputeScoreCapsSamplesAtOneBuffer(t *testing.T) {
+	// Two samples 5s apart (a seek): the first covers one mic buffer, not the gap.
+	user := []float64{0, 220, 5000, 220}
+	got := ComputeScore(user, constSong(600, 220), 0, false, 0.7)
+	if got.TotalFrames != 8 {
+		t.Errorf("two samples around a seek covered %d frames, want one mic buffer (4 frames) each", got.TotalFrames)
+	}
+}
+
+func TestComputeHarmonyScore(t *testing.T) {
+	// The user sings a fifth above the melody for 30 frames, then the
+	// melody itself for 20 frames.
+	song := constSong(50, 220)
+	fifth := 220 * math.Pow(2, 7.0/12)
+	user := trail(50, 0, func(i int) float64 {
+		if i < 30 {
+			return fifth
+		}
+		return 220
+	})
+
+	res := ComputeHarmonyScore(user, song, 0, []int{4, 7}, false, 0.7)
+	if res.TotalFrames != 50 || res.HitFrames != 30 {
+		t.Errorf("harmony score hit %d of %d frames, want 30 of 50", res.HitFrames, res.TotalFrames)
+	}
+}
+
+func TestHitToleranceWidensHits(t *testing.T) {
+	song := constSong(100, 220)
+	near := 220 * math.Pow(2, 1.5/12)
+	user := trail(100, 0, func(int) float64 { return near })
+
+	if !NoteHit(false, 2.0)(near, 220) {
+		t.Error("1.5 semitones off is not a hit at tolerance 2.0")
+	}
+	if got := ComputeScore(user, song, 0, false, 2.0).Score; got != 100 {
+		t.Errorf("score at tolerance 2.0 = %v, want 100", got)
+	}
+	if got := ComputeScore(user, song, 0, false, config.DefaultHitTolerance).Score; got != 0 {
+		t.Errorf("score at the default tolerance = %v, want 0", got)
+	}
+}
@@ -0,0 +1,116 @@
+package scoring
+
+import (
+	"encoding/json"
+	"os"
+)
+
+/*
+LoadScores reads all saved results for one song.
+
+Input:
+  - path: string - The song's scores.json (config.SongPaths.ScoresFile)
+
+Called by:
+  - SaveScore before appending
+  - BestScore for the start screen
+
+Task:
+  - Decode the per-song results array
+
+Logic:
+ 1. Missing file: no results, no error
+ 2. Unmarshal the JSON array
+
+Output:
+  - []SessionResult: Saved results in the order they were played
+  - error: Read or decode error
+*/
+func LoadScores(path string) ([]SessionResult, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SessionResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/*
+SaveScore appends one result to the song's scores.json.
+
+Input:
+  - path: string - The song's scores.json
+  - r: SessionResult - Result to add
+
+Called by:
+  - App.finishSong when a song plays to the end
+
+Task:
+  - Keep every finished run for the high-score panel
+
+Logic:
+ 1. Load existing results (a corrupt file is an error, not overwritten)
+ 2. Append r and marshal with indentation
+ 3. Write to a temp file and rename it over the original
+
+Output:
+  - error: nil on success
+*/
+func SaveScore(path string, r SessionResult) error {
+	results, err := LoadScores(path)
+	if err != nil {
+		return err
+	}
+	results = append(results, r)
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+/*
+BestScore returns the highest saved score for a song.
+
+Input:
+  - path: string - The song's scores.json
+
+Called by:
+  - App.refreshBestScore for the start screen
+
+Task:
+  - Pick the high score
+
+Logic:
+ 1. Load results, treating errors as "no score"
+ 2. Return the maximum Score
+
+Output:
+  - float64: Best score 0..100
+  - bool: false if the song has no saved results
+*/
+func BestScore(path string) (float64, bool) {
+	results, err := LoadScores(path)
+	if err != nil || len(results) == 0 {
+		return 0, false
+	}
+
+	best := results[0].Score
+	for _, r := range results[1:] {
+		best = max(best, r.Score)
+	}
+	return best, true
+}
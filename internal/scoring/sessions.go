@@ -0,0 +1,151 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"singAssist/internal/config"
+)
+
+/*
+RecordedSession is one finished run as stored in the song's sessions folder.
+
+Fields:
+  - SessionResult: The run's score (inlined in the JSON)
+  - UserPitch: Recorded pairs [timeMs, pitch, ...] (unpruned)
+*/
+type RecordedSession struct {
+	SessionResult
+	UserPitch []float64 `json:"user_pitch"`
+}
+
+/*
+SaveSession stores a finished run with its pitch trail.
+
+Input:
+  - songDir: string - Song folder
+  - r: SessionResult - The run's score (PlayedAt names the file)
+  - userPitch: []float64 - The run's recorded pairs [timeMs, pitch, ...]
+
+Called by:
+  - App.finishSong for every singer that scored anything
+
+Task:
+  - Keep runs around to compare later (scores.json holds only the numbers)
+
+Logic:
+ 1. Create config.SongPaths.SessionsDir if needed
+ 2. Name the file session_<YYYYMMDD_HHMMSS>.json (…_singer<N> in duet mode)
+ 3. Marshal a RecordedSession compactly and write it
+
+Output:
+  - string: Path written
+  - error: nil on success
+*/
+func SaveSession(songDir string, r SessionResult, userPitch []float64) (string, error) {
+	dir := config.GetSongPaths(songDir).SessionsDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	name := "session_" + r.PlayedAt.Format("20060102_150405")
+	if r.Singer > 0 {
+		name += fmt.Sprintf("_singer%d", r.Singer)
+	}
+	data, err := json.Marshal(RecordedSession{SessionResult: r, UserPitch: userPitch})
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name+".json")
+	return path, os.WriteFile(path, data, 0644)
+}
+
+/*
+ListSessions lists the runs saved for a song.
+
+Input:
+  - songDir: string - Song folder
+
+Called by:
+  - App.openSessionCompare
+
+Task:
+  - Offer the saved runs for A/B comparison
+
+Logic:
+ 1. Missing sessions folder: no sessions, no error
+ 2. Read every *.json file in it as a RecordedSession, skipping unreadable ones,
+    and keep its SessionResult with File set to the path
+ 3. Sort oldest first by PlayedAt
+
+Output:
+  - []SessionResult: Saved runs (trails are loaded with LoadSessionPitch)
+  - error: Error reading the folder
+*/
+func ListSessions(songDir string) ([]SessionResult, error) {
+	dir := config.GetSongPaths(songDir).SessionsDir
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []SessionResult
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var s RecordedSession
+		if json.Unmarshal(data, &s) != nil {
+			continue
+		}
+		s.File = path
+		sessions = append(sessions, s.SessionResult)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].PlayedAt.Before(sessions[j].PlayedAt) })
+	return sessions, nil
+}
+
+/*
+LoadSessionPitch reads the pitch trail of a saved run.
+
+Input:
+  - path: string - SessionResult.File from ListSessions
+
+Called by:
+  - App.loadComparedSessions
+
+Task:
+  - Load a trail only when it is compared (they can be long)
+
+Logic:
+ 1. Read and unmarshal the RecordedSession
+
+Output:
+  - []float64: Pairs [timeMs, pitch, ...]
+  - error: Read or decode error
+*/
+func LoadSessionPitch(path string) ([]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s RecordedSession
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return s.UserPitch, nil
+}
@@ -0,0 +1,60 @@
+package scoring
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"singAssist/internal/config"
+)
+
+func TestListSessionsEnumeratesJSONFiles(t *testing.T) {
+	songDir := t.TempDir()
+	base := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	runs := []SessionResult{
+		{Score: 71, Mode: "vocals", PlayedAt: base.Add(2 * time.Hour)},
+		{Score: 64, Mode: "vocals", PlayedAt: base},
+		{Score: 80, Mode: "duet", PlayedAt: base.Add(time.Hour), Singer: 1},
+		{Score: 55, Mode: "duet", PlayedAt: base.Add(time.Hour), Singer: 2},
+	}
+	for _, r := range runs {
+		if _, err := SaveSession(songDir, r, []float64{0, 220, 10, 233}); err != nil {
+			t.Fatalf("SaveSession: %v", err)
+		}
+	}
+	dir := config.GetSongPaths(songDir).SessionsDir
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a session"), 0644)
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644)
+	os.Mkdir(filepath.Join(dir, "old.json"), 0755)
+
+	got, err := ListSessions(songDir)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(got) != len(runs) {
+		t.Fatalf("listed %d sessions, want %d", len(got), len(runs))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].PlayedAt.Before(got[i-1].PlayedAt) {
+			t.Errorf("session %d played at %v lists after %v", i, got[i].PlayedAt, got[i-1].PlayedAt)
+		}
+	}
+	if got[0].Score != 64 || got[3].Score != 71 {
+		t.Errorf("first and last scores = %v, %v; want 64 (oldest) and 71 (newest)", got[0].Score, got[3].Score)
+	}
+	for _, s := range got {
+		trail, err := LoadSessionPitch(s.File)
+		if err != nil || !slices.Equal(trail, []float64{0, 220, 10, 233}) {
+			t.Errorf("trail of %s = %v, %v", s.File, trail, err)
+		}
+	}
+}
+
+func TestListSessionsWithoutFolder(t *testing.T) {
+	got, err := ListSessions(t.TempDir())
+	if err != nil || len(got) != 0 {
+		t.Errorf("ListSessions of a song never played = %v, %v; want none", got, err)
+	}
+}
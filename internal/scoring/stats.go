@@ -0,0 +1,185 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+
+	"singAssist/internal/theory"
+)
+
+/*
+SessionStats summarizes a finished run for the results screen.
+
+Fields:
+  - Score: Hit percentage 0..100
+  - TotalFrames: Voiced song frames (10ms) the user was scored on
+  - HitFrames: Of those, frames sung on the note
+  - LongestStreak: Most consecutive hit frames (song rests do not break a streak)
+  - BestNote: Song note hit most often (e.g. "A4"), "" if none was hit
+  - WorstNote: Song note missed most often, "" if none was missed
+  - PitchClasses: Hit rate 0..1 per pitch class (C, C#, ... B) of the song
+    note; -1 for pitch classes the song never used
+  - Multiplier: Score multiplier of the run (config.SightReadingMultiplier for sight reading, else 1)
+  - Points: Score × Multiplier
+*/
+type SessionStats struct {
+	Score         float64
+	TotalFrames   int
+	HitFrames     int
+	LongestStreak int
+	BestNote      string
+	WorstNote     string
+	PitchClasses  [12]float64
+	Multiplier    float64
+	Points        float64
+}
+
+/*
+ComputeSessionStats computes the results-screen statistics of a run.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...] (unpruned)
+  - songPitch: []float64 - Song pitch at 10ms intervals
+  - latencyMs: float64 - Output latency, as in ComputeScore
+
+Called by:
+  - Callers without a mode-specific rule (the app uses ComputeSessionStatsWith)
+
+Task:
+  - Statistics with the standard NoteHit rule
+
+Logic:
+ 1. ComputeSessionStatsWith, NoteHit(false, HitSemitones) and no multiplier
+
+Output:
+  - SessionStats: Score, counts, streak and best/worst note
+*/
+func ComputeSessionStats(userPitch, songPitch []float64, latencyMs float64) SessionStats {
+	return ComputeSessionStatsWith(userPitch, songPitch, latencyMs, NoteHit(false, HitSemitones), 1)
+}
+
+/*
+ComputeSessionStatsWith computes run statistics with a given hit rule.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...] (unpruned)
+  - songPitch: []float64 - Song pitch at 10ms intervals
+  - latencyMs: float64 - Output latency, as in ComputeScore
+  - hit: HitRule - The rule the run was scored with (so Score matches the saved score)
+  - multiplier: float64 - Points per score percent (config.SightReadingMultiplier
+    for sight-reading runs, 1 otherwise)
+
+Called by:
+  - ComputeSessionStats
+  - App.finishSong for the results screen
+
+Task:
+  - Go beyond the percentage: streaks and which notes went well or badly
+
+Logic:
+ 1. Visit the voiced song frames with walkFrames, in song order
+ 2. Count hits and total frames; extend the current streak on a hit,
+    reset it on a miss, and keep the longest
+ 3. Tally hits and misses per song note (nearest semitone, e.g. "A4")
+ 4. Best note has the most hits, worst note the most misses
+    (ties go to the lower note so the result is stable)
+ 5. Per pitch class: hits / frames, -1 where there were no frames
+ 6. Points = Score × multiplier (Score itself stays a plain percentage)
+
+Output:
+  - SessionStats: Score, points, counts, streak, best/worst note and pitch-class hit rates
+*/
+func ComputeSessionStatsWith(userPitch, songPitch []float64, latencyMs float64, hit HitRule, multiplier float64) SessionStats {
+	st := SessionStats{Multiplier: multiplier}
+	hits := map[int]int{}
+	misses := map[int]int{}
+	var classHits, classFrames [12]int
+	streak := 0
+
+	walkFrames(userPitch, songPitch, latencyMs, func(user, ref float64) {
+		st.TotalFrames++
+		note := int(theory.FreqToMidi(ref) + 0.5)
+		classFrames[note%12]++
+		if hit(user, ref) {
+			st.HitFrames++
+			hits[note]++
+			classHits[note%12]++
+			streak++
+			st.LongestStreak = max(st.LongestStreak, streak)
+		} else {
+			misses[note]++
+			streak = 0
+		}
+	})
+
+	if st.TotalFrames > 0 {
+		st.Score = 100 * float64(st.HitFrames) / float64(st.TotalFrames)
+	}
+	st.Points = st.Score * multiplier
+	st.BestNote = mostFrequentNote(hits)
+	st.WorstNote = mostFrequentNote(misses)
+	for pc := range st.PitchClasses {
+		st.PitchClasses[pc] = -1
+		if classFrames[pc] > 0 {
+			st.PitchClasses[pc] = float64(classHits[pc]) / float64(classFrames[pc])
+		}
+	}
+	return st
+}
+
+/*
+PitchClassAccuracy returns the hit rate per pitch class of a run.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...] (unpruned)
+  - songPitch: []float64 - Song pitch at 10ms intervals
+  - latencyMs: float64 - Output latency, as in ComputeScore
+
+Called by:
+  - Callers that only need the histogram (the app reads SessionStats.PitchClasses)
+
+Task:
+  - Show which notes the user consistently misses, whatever the octave
+
+Logic:
+ 1. ComputeSessionStats and return its PitchClasses
+
+Output:
+  - [12]float64: Hit rate 0..1 for C, C#, ... B; -1 where the song has no such note
+*/
+func PitchClassAccuracy(userPitch, songPitch []float64, latencyMs float64) [12]float64 {
+	return ComputeSessionStats(userPitch, songPitch, latencyMs).PitchClasses
+}
+
+/*
+mostFrequentNote names the note with the highest count.
+
+Input:
+  - counts: map[int]int - Frames per MIDI note
+
+Called by:
+  - ComputeSessionStatsWith
+
+Task:
+  - Pick the best or worst note
+
+Logic:
+ 1. Highest count wins; ties go to the lower MIDI note
+ 2. Name it with theory.FreqToNote via the note's frequency
+
+Output:
+  - string: e.g. "C#4", "" for an empty map
+*/
+func mostFrequentNote(counts map[int]int) string {
+	best, bestCount := 0, 0
+	for note, c := range counts {
+		if c > bestCount || (c == bestCount && note < best) {
+			best, bestCount = note, c
+		}
+	}
+	if bestCount == 0 {
+		return ""
+	}
+	name, octave := theory.FreqToNote(440 * math.Pow(2, float64(best-69)/12))
+	return fmt.Sprintf("%s%d", name, octave)
+}
@@ -0,0 +1,89 @@
+package scoring
+
+import (
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestPitchClassAccuracy(t *testing.T) {
+	// 50 frames of A3 (class 9) sung exactly, then 50 of C4 (class 0) left silent.
+	song := append(constSong(50, 220), constSong(50, 261.63)...)
+	user := trail(100, 0, func(i int) float64 {
+		if i < 50 {
+			return 220
+		}
+		return 0
+	})
+
+	acc := PitchClassAccuracy(user, song, 0)
+	if acc[9] != 1 {
+		t.Errorf("A accuracy = %v, want 1", acc[9])
+	}
+	if acc[0] != 0 {
+		t.Errorf("C accuracy = %v, want 0", acc[0])
+	}
+	for pc, v := range acc {
+		if pc != 0 && pc != 9 && v != -1 {
+			t.Errorf("class %d has accuracy %v but is not in the song, want -1", pc, v)
+		}
+	}
+}
+
+func TestPitchClassAccuracyFoldsOctaves(t *testing.T) {
+	// A2, A3 and A4 all count towards class 9; the A4 third is sung flat.
+	song := append(append(constSong(30, 110), constSong(30, 220)...), constSong(30, 440)...)
+	user := trail(90, 0, func(i int) float64 {
+		if i >= 60 {
+			return 415.3
+		}
+		return song[i]
+	})
+
+	if got := PitchClassAccuracy(user, song, 0)[9]; got < 0.66 || got > 0.67 {
+		t.Errorf("A accuracy = %v, want 2/3", got)
+	}
+}
+
+func TestSightReadingMultiplierScalesPoints(t *testing.T) {
+	// Three quarters of the song sung on pitch: a 75% score either way.
+	song := constSong(100, 220)
+	user := trail(100, 0, func(i int) float64 {
+		if i < 75 {
+			return 220
+		}
+		return 0
+	})
+
+	plain := ComputeSessionStatsWith(user, song, 0, NoteHit(false, HitSemitones), 1)
+	sight := ComputeSessionStatsWith(user, song, 0, NoteHit(false, HitSemitones), config.SightReadingMultiplier)
+	if plain.Score != 75 || sight.Score != 75 {
+		t.Errorf("scores = %v, %v; want 75 with and without sight reading", plain.Score, sight.Score)
+	}
+	if plain.Points != 75 || sight.Points != 112.5 {
+		t.Errorf("points = %v, %v; want 75 and 112.5", plain.Points, sight.Points)
+	}
+}
+
+func TestSessionStatsLongestStreak(t *testing.T) {
+	// 30 frames of A3 then 30 of E4. The singer hits 5, misses one, hits 15,
+	// misses one, hits 8, then stays on A3 through the E4 section.
+	song := append(constSong(30, 220), constSong(30, 330)...)
+	user := trail(60, 0, func(i int) float64 {
+		if i == 5 || i == 21 {
+			return 247
+		}
+		return 220
+	})
+
+	st := ComputeSessionStats(user, song, 0)
+	if st.LongestStreak != 15 {
+		t.Errorf("longest streak = %d, want 15", st.LongestStreak)
+	}
+	if st.HitFrames != 28 || st.TotalFrames != 60 {
+		t.Errorf("hit %d of %d frames, want 28 of 60", st.HitFrames, st.TotalFrames)
+	}
+	if st.BestNote != "A3" || st.WorstNote != "E4" {
+		t.Errorf("best/worst note = %s/%s, want A3/E4", st.BestNote, st.WorstNote)
+	}
+}
@@ -0,0 +1,61 @@
+package setlist
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"singAssist/internal/config"
+)
+
+/*
+LoadSetlist reads a list of songs to play back-to-back.
+
+Input:
+  - path: string - Text file with one song folder per line
+
+Called by:
+  - main.main for the -setlist flag
+
+Task:
+  - Let a practice session run through several songs without the menu
+
+Logic:
+ 1. Read the file line by line; skip blank lines and lines starting with #
+ 2. Keep paths that exist as given; a bare name that does not exist is looked
+    up in config.SongsDir (so "Kasoor" means songs/Kasoor)
+ 3. Fail if no song is listed
+
+Output:
+  - []string: Song folders in play order
+  - error: Read error, or an error for an empty setlist
+*/
+func LoadSetlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var songs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := os.Stat(line); err != nil && !strings.ContainsAny(line, `/\`) {
+			line = filepath.Join(config.SongsDir, line)
+		}
+		songs = append(songs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(songs) == 0 {
+		return nil, fmt.Errorf("setlist %s lists no songs", path)
+	}
+	return songs, nil
+}
@@ -0,0 +1,97 @@
+package theory
+
+import (
+	"slices"
+	"strings"
+)
+
+// pitchClasses maps note names (sharps as FreqToNote writes them, and
+// flats) to semitones above C.
+var pitchClasses = map[string]int{
+	"C": 0, "C#": 1, "Db": 1, "D": 2, "D#": 3, "Eb": 3, "E": 4, "F": 5,
+	"F#": 6, "Gb": 6, "G": 7, "G#": 8, "Ab": 8, "A": 9, "A#": 10, "Bb": 10, "B": 11,
+}
+
+// chordNames lists the pitch-class sets ChordName recognises, as intervals
+// above the root, with the suffix appended to the root name. The 7th without
+// its fifth is listed because DetectChord keeps only three notes.
+var chordNames = []struct {
+	intervals []int
+	suffix    string
+}{
+	{[]int{0, 4, 7}, ""},
+	{[]int{0, 3, 7}, "m"},
+	{[]int{0, 4, 7, 10}, "7"},
+	{[]int{0, 4, 10}, "7"},
+	{[]int{0, 3, 6}, "dim"},
+}
+
+/*
+ChordName names the chord a set of notes forms.
+
+Input:
+  - notes: []string - Note names without octave (e.g. "C", "E", "G"), any order,
+    duplicates and octave doublings allowed
+
+Called by:
+  - App.drawPlayingMode in instrumental mode
+
+Task:
+  - Turn detected chord frequencies into a name a musician reads
+
+Logic:
+ 1. Convert the names to a sorted set of pitch classes (unknown names are skipped)
+ 2. Try each pitch class as the root: the intervals above it must equal one
+    of chordNames exactly (major, minor, dominant 7th, diminished)
+ 3. Return root + suffix, e.g. "C", "Am", "G7", "Bdim"
+
+Output:
+  - string: Chord name, "" when the notes form none of the known chords
+*/
+func ChordName(notes []string) string {
+	var classes []int
+	for _, n := range notes {
+		pc, ok := pitchClasses[strings.TrimSpace(n)]
+		if ok && !slices.Contains(classes, pc) {
+			classes = append(classes, pc)
+		}
+	}
+	slices.Sort(classes)
+
+	for _, root := range classes {
+		intervals := make([]int, len(classes))
+		for i, pc := range classes {
+			intervals[i] = (pc - root + 12) % 12
+		}
+		slices.Sort(intervals)
+
+		for _, c := range chordNames {
+			if slices.Equal(intervals, c.intervals) {
+				return noteNameOf(root) + c.suffix
+			}
+		}
+	}
+	return ""
+}
+
+/*
+noteNameOf returns the sharp spelling of a pitch class.
+
+Input:
+  - pc: int - Semitones above C (0..11)
+
+Called by:
+  - ChordName
+
+Task:
+  - Spell chord roots the same way as the note HUD
+
+Logic:
+ 1. Index the sharp note names
+
+Output:
+  - string: e.g. "C#"
+*/
+func noteNameOf(pc int) string {
+	return []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}[pc]
+}
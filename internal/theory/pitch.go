@@ -0,0 +1,153 @@
+package theory
+
+import "math"
+
+/*
+FreqToMidi converts frequency in Hz to MIDI note number.
+
+Input:
+  - freq: float64 - Frequency in Hz
+
+Called by:
+  - FreqToNote for note name conversion
+  - ui.PitchVisualizer.FreqToY for Y coordinate calculation
+  - ui.PitchVisualizer.DrawUserPitch for hit detection
+
+Task:
+  - Convert frequency to continuous MIDI scale for visualization
+
+Logic:
+ 1. If freq <= 0: return 0 (invalid)
+ 2. Apply formula: 69 + 12 * log2(freq / 440)
+ 3. MIDI 69 = A4 = 440Hz
+
+Output:
+  - float64: Continuous MIDI note number (can be fractional)
+*/
+func FreqToMidi(freq float64) float64 {
+	if freq <= 0 {
+		return 0
+	}
+	return 69 + 12*math.Log2(freq/440.0)
+}
+
+/*
+SemitoneDistance returns how far apart two frequencies are in semitones.
+
+Input:
+  - freq, ref: float64 - Frequencies in Hz (both must be > 0)
+  - ignoreOctave: bool - Compare pitch classes only (MIDI mod 12)
+
+Called by:
+  - App.drawPlayingMode for the HUD match indicator
+  - ui.PitchVisualizer.DrawUserPitch for hit detection
+
+Task:
+  - Measure pitch error, optionally treating notes an octave apart as equal
+
+Logic:
+ 1. Take absolute difference of FreqToMidi values
+ 2. If ignoreOctave: reduce modulo 12 and fold to the nearer side (0-6)
+
+Output:
+  - float64: Distance in semitones
+*/
+func SemitoneDistance(freq, ref float64, ignoreOctave bool) float64 {
+	diff := math.Abs(FreqToMidi(freq) - FreqToMidi(ref))
+	if ignoreOctave {
+		diff = math.Mod(diff, 12)
+		if diff > 6 {
+			diff = 12 - diff
+		}
+	}
+	return diff
+}
+
+/*
+Transpose shifts a frequency by a number of semitones.
+
+Input:
+  - freq: float64 - Frequency in Hz (0 = silence)
+  - steps: int - Semitones; positive = up, negative = down
+
+Called by:
+  - ui.PitchVisualizer drawing and App hit detection for the key offset
+
+Task:
+  - Let the user sing a song in a different key
+
+Logic:
+ 1. freq * 2^(steps/12); silence and steps == 0 are returned unchanged
+
+Output:
+  - float64: Shifted frequency in Hz
+*/
+func Transpose(freq float64, steps int) float64 {
+	if steps == 0 || freq <= 0 {
+		return freq
+	}
+	return freq * math.Pow(2, float64(steps)/12.0)
+}
+
+/*
+CentsOffset returns how far a frequency is from the nearest note center.
+
+Input:
+  - freq: float64 - Frequency in Hz
+
+Called by:
+  - ui.DrawNoteHUD for the cents readout
+  - ui.DrawIntonationGraph for each point of the curve
+
+Task:
+  - Express fine tuning within a semitone
+
+Logic:
+ 1. If freq <= 0: return 0
+ 2. midi = FreqToMidi(freq)
+ 3. cents = (midi - round(midi)) * 100
+
+Output:
+  - float64: Offset in cents, in [-50, 50] (positive = sharp)
+*/
+func CentsOffset(freq float64) float64 {
+	if freq <= 0 {
+		return 0
+	}
+	midi := FreqToMidi(freq)
+	return (midi - math.Round(midi)) * 100
+}
+
+/*
+FreqToNote converts frequency to musical note name and octave.
+
+Input:
+  - freq: float64 - Frequency in Hz
+
+Called by:
+  - App.drawPlayingMode for displaying current notes
+  - App.drawFreestyleMode for displaying user pitch
+
+Task:
+  - Convert frequency to human-readable note name
+
+Logic:
+ 1. If freq <= 0: return "-", 0
+ 2. Convert to MIDI, round to nearest integer
+ 3. Note name = notes[midi % 12]
+ 4. Octave = midi / 12 - 1
+
+Output:
+  - string: Note name (e.g., "C#", "A")
+  - int: Octave number (e.g., 4 for A4)
+*/
+func FreqToNote(freq float64) (string, int) {
+	if freq <= 0 {
+		return "-", 0
+	}
+	midi := int(math.Round(FreqToMidi(freq)))
+	notes := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	note := notes[midi%12]
+	octave := midi/12 - 1
+	return note, octave
+}
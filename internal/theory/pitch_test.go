@@ -0,0 +1,81 @@
+package theory
+
+import (
+	"math"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestTransposeOctaveMakesHit(t *testing.T) {
+	const user, song = 440.0, 220.0
+	if d := SemitoneDistance(user, song, false); d <= config.DefaultHitTolerance {
+		t.Fatalf("untransposed distance = %.2f semitones, want a miss", d)
+	}
+
+	ref := Transpose(song, 12)
+	if math.Abs(ref-440) > 1e-9 {
+		t.Errorf("Transpose(220, 12) = %v, want 440", ref)
+	}
+	if d := SemitoneDistance(user, ref, false); d >= config.DefaultHitTolerance {
+		t.Errorf("distance to the transposed song = %.4f semitones, want a hit", d)
+	}
+
+	// micLoop stores frames back in the song's key for scoring.
+	if inSongKey := Transpose(user, -12); SemitoneDistance(inSongKey, song, false) >= config.DefaultHitTolerance {
+		t.Errorf("Transpose(440, -12) = %v, want a hit on 220 Hz", inSongKey)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		freq  float64
+		steps int
+		want  float64
+	}{
+		{440, 0, 440},
+		{440, -12, 220},
+		{440, 3, 523.2511306011972},
+		{440, -3, 369.9944227116344},
+		{0, 5, 0},
+	}
+	for _, tt := range tests {
+		if got := Transpose(tt.freq, tt.steps); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Transpose(%v, %d) = %v, want %v", tt.freq, tt.steps, got, tt.want)
+		}
+	}
+	for steps := -12; steps <= 12; steps++ {
+		if got := Transpose(Transpose(330, steps), -steps); math.Abs(got-330) > 1e-9 {
+			t.Errorf("Transpose round trip by %d = %v, want 330", steps, got)
+		}
+	}
+}
+
+func TestCentsOffset(t *testing.T) {
+	tests := []struct {
+		cents float64
+		want  float64
+	}{
+		{0, 0},
+		{10, 10},
+		{-10, -10},
+		{25, 25},
+		{-30, -30},
+		{49, 49},
+		{60, -40}, // closer to A#4
+		{-70, 30}, // closer to G#4
+	}
+	for _, tt := range tests {
+		freq := 440 * math.Pow(2, tt.cents/1200)
+		if got := CentsOffset(freq); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("CentsOffset(A4 %+v cents = %.3f Hz) = %v, want %v", tt.cents, freq, got, tt.want)
+		}
+	}
+	if got := CentsOffset(0); got != 0 {
+		t.Errorf("CentsOffset(0) = %v, want 0", got)
+	}
+	// 432 Hz is the classic "A=432" tuning, about 31.8 cents flat of A4.
+	if got := CentsOffset(432); math.Abs(got+31.77) > 0.01 {
+		t.Errorf("CentsOffset(432) = %v, want -31.77", got)
+	}
+}
@@ -0,0 +1,152 @@
+package theory
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+)
+
+// intervalNames are the short names of the simple intervals, indexed by semitones.
+var intervalNames = []string{"P1", "m2", "M2", "m3", "M3", "P4", "TT", "P5", "m6", "M6", "m7", "M7", "Oct"}
+
+// DefaultHarmony is used when a song has no harmony.json: a third
+// (minor or major) or a fifth above the melody.
+var DefaultHarmony = []int{3, 4, 7}
+
+/*
+IntervalName returns the standard short name of an interval.
+
+Input:
+  - semitones: int - Interval size; the sign (direction) is ignored
+
+Called by:
+  - IntervalLabel
+
+Task:
+  - Name intervals the way singers read them
+
+Logic:
+ 1. Take the absolute value
+ 2. 0..12 map straight onto intervalNames (P1, m2, ..., M7, Oct)
+ 3. Wider intervals reduce to their simple interval, with whole octaves named "Oct"
+
+Output:
+  - string: e.g. "m3", "P5", "Oct"
+*/
+func IntervalName(semitones int) string {
+	if semitones < 0 {
+		semitones = -semitones
+	}
+	if semitones > 12 {
+		semitones %= 12
+		if semitones == 0 {
+			semitones = 12
+		}
+	}
+	return intervalNames[semitones]
+}
+
+/*
+IntervalLabel names the interval from the song note to the user's note.
+
+Input:
+  - user: float64 - User pitch in Hz
+  - song: float64 - Song pitch in Hz
+
+Called by:
+  - App.drawPlayingMode in harmony mode
+
+Task:
+  - Show which harmony the user is currently singing
+
+Logic:
+ 1. Round FreqToMidi(user) - FreqToMidi(song) to whole semitones
+ 2. Append ↑ when the user is above the song, ↓ when below
+
+Output:
+  - string: e.g. "P5 ↑", "m3 ↓", "P1"
+*/
+func IntervalLabel(user, song float64) string {
+	semitones := int(math.Round(FreqToMidi(user) - FreqToMidi(song)))
+	switch {
+	case semitones > 0:
+		return IntervalName(semitones) + " ↑"
+	case semitones < 0:
+		return IntervalName(semitones) + " ↓"
+	}
+	return IntervalName(0)
+}
+
+/*
+MatchesHarmony reports whether the user is singing one of the target harmonies.
+
+Input:
+  - user: float64 - User pitch in Hz
+  - song: float64 - Song pitch in Hz
+  - offsets: []int - Target harmonies in semitones from the song note
+  - tolerance: float64 - Allowed distance in semitones
+  - ignoreOctave: bool - Accept a target note in any octave
+
+Called by:
+  - scoring.ComputeHarmonyScore, App.drawPlayingMode
+
+Task:
+  - One hit test for the HUD colour and the score
+
+Logic:
+ 1. Both pitches must be voiced
+ 2. Hit if the user is within tolerance of the song note transposed by any offset
+
+Output:
+  - bool: true on a harmony hit
+*/
+func MatchesHarmony(user, song float64, offsets []int, tolerance float64, ignoreOctave bool) bool {
+	if user <= 10 || song <= 10 {
+		return false
+	}
+	for _, off := range offsets {
+		if SemitoneDistance(user, Transpose(song, off), ignoreOctave) < tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+LoadHarmony reads a song's target harmonies.
+
+Input:
+  - path: string - The song's harmony.json (config.SongPaths.HarmonyFile)
+
+Called by:
+  - App.calibrateAndPlay in harmony mode
+
+Task:
+  - Let each song define which harmony part to practise
+
+Logic:
+ 1. Missing file: return DefaultHarmony
+ 2. Decode a JSON array of semitone offsets (e.g. [-5, 4])
+ 3. An empty array also falls back to DefaultHarmony
+
+Output:
+  - []int: Target offsets in semitones
+  - error: Read or decode error (DefaultHarmony is still returned)
+*/
+func LoadHarmony(path string) ([]int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultHarmony, nil
+	}
+	if err != nil {
+		return DefaultHarmony, err
+	}
+	var offsets []int
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return DefaultHarmony, err
+	}
+	if len(offsets) == 0 {
+		return DefaultHarmony, nil
+	}
+	return offsets, nil
+}
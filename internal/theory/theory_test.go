@@ -0,0 +1,122 @@
+package theory
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIntervalNameAllSimpleIntervals(t *testing.T) {
+	want := []string{"P1", "m2", "M2", "m3", "M3", "P4", "TT", "P5", "m6", "M6", "m7", "M7", "Oct"}
+	for semitones, name := range want {
+		if got := IntervalName(semitones); got != name {
+			t.Errorf("IntervalName(%d) = %q, want %q", semitones, got, name)
+		}
+		if got := IntervalName(-semitones); got != name {
+			t.Errorf("IntervalName(%d) = %q, want %q", -semitones, got, name)
+		}
+	}
+}
+
+func TestIntervalNameCompound(t *testing.T) {
+	tests := map[int]string{13: "m2", 19: "P5", 24: "Oct", -16: "M3", 36: "Oct"}
+	for semitones, want := range tests {
+		if got := IntervalName(semitones); got != want {
+			t.Errorf("IntervalName(%d) = %q, want %q", semitones, got, want)
+		}
+	}
+}
+
+func TestIntervalLabel(t *testing.T) {
+	tests := []struct {
+		user, song float64
+		want       string
+	}{
+		{Transpose(440, 7), 440, "P5 ↑"},
+		{Transpose(440, -3), 440, "m3 ↓"},
+		{441, 440, "P1"},
+		{Transpose(220, 12), 220, "Oct ↑"},
+	}
+	for _, tt := range tests {
+		if got := IntervalLabel(tt.user, tt.song); got != tt.want {
+			t.Errorf("IntervalLabel(%.2f, %.2f) = %q, want %q", tt.user, tt.song, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesHarmony(t *testing.T) {
+	const song = 440.0
+	offsets := []int{4, 7}
+	tests := []struct {
+		name         string
+		user         float64
+		ignoreOctave bool
+		want         bool
+	}{
+		{"major third", Transpose(song, 4), false, true},
+		{"fifth, 0.6 semitones sharp", song * math.Pow(2, 7.6/12), false, true},
+		{"fifth, 0.8 semitones sharp", song * math.Pow(2, 7.8/12), false, false},
+		{"unison", song, false, false},
+		{"fifth an octave down", Transpose(song, -5), false, false},
+		{"fifth an octave down, any octave", Transpose(song, -5), true, true},
+		{"silence", 0, false, false},
+	}
+	for _, tt := range tests {
+		if got := MatchesHarmony(tt.user, song, offsets, 0.7, tt.ignoreOctave); got != tt.want {
+			t.Errorf("%s: MatchesHarmony = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if MatchesHarmony(Transpose(song, 4), 0, offsets, 0.7, false) {
+		t.Error("a harmony against a silent melody counted")
+	}
+}
+
+func TestLoadHarmony(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if got, err := LoadHarmony(write("thirds.json", "[-5, 4]")); err != nil || !slices.Equal(got, []int{-5, 4}) {
+		t.Errorf("LoadHarmony = %v, %v; want [-5 4]", got, err)
+	}
+	if got, err := LoadHarmony(filepath.Join(dir, "missing.json")); err != nil || !slices.Equal(got, DefaultHarmony) {
+		t.Errorf("missing file: %v, %v; want DefaultHarmony without an error", got, err)
+	}
+	if got, err := LoadHarmony(write("empty.json", "[]")); err != nil || !slices.Equal(got, DefaultHarmony) {
+		t.Errorf("empty list: %v, %v; want DefaultHarmony", got, err)
+	}
+	if got, err := LoadHarmony(write("bad.json", "{")); err == nil || !slices.Equal(got, DefaultHarmony) {
+		t.Errorf("bad file: %v, %v; want DefaultHarmony and an error", got, err)
+	}
+}
+
+func TestChordName(t *testing.T) {
+	tests := []struct {
+		notes []string
+		want  string
+	}{
+		{[]string{"C", "E", "G"}, "C"},
+		{[]string{"G", "C", "E"}, "C"},
+		{[]string{"A", "C", "E"}, "Am"},
+		{[]string{"Eb", "G", "Bb"}, "D#"},
+		{[]string{"G", "B", "D", "F"}, "G7"},
+		{[]string{"G", "B", "F"}, "G7"},
+		{[]string{"B", "D", "F"}, "Bdim"},
+		{[]string{"C", "E", "G", "C"}, "C"},
+		{[]string{"C", "D", "E"}, ""},
+		{[]string{"C"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := ChordName(tt.notes); got != tt.want {
+			t.Errorf("ChordName(%v) = %q, want %q", tt.notes, got, tt.want)
+		}
+	}
+}
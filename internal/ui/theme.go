@@ -0,0 +1,319 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// ThemeFile holds the user's colour theme, e.g. {"Name": "light"} to pick a
+// built-in theme, plus any colour fields to override on top of it.
+const ThemeFile = "config/theme.json"
+
+/*
+Theme holds the colours the ui package draws with.
+
+Fields:
+  - Name: Built-in theme this one starts from ("dark", "light", "high-contrast")
+  - Background: Screen fill behind everything
+  - SongPitch: Song pitch line and note block edges (block fill is a translucent copy)
+  - UserPitchHit, UserPitchMiss: User trail on and off the song pitch
+  - NowLine: Vertical "now" line
+  - GridLine: Semitone grid line (quarter tones and octaves are fainter and brighter copies)
+  - HUDBackground: Panels behind the HUD and results text
+  - HUDText: Titles and HUD text
+  - DimText, FaintText: Secondary labels and hints, and the faintest captions
+  - Highlight: Selected rows, typed input, the beat light and other emphasis
+  - Info: Informational labels (tolerance, reference tone, session B)
+  - Good, Bad: Hits, high scores and sharp notes; misses, low scores, flat notes and errors
+  - Border: Panel and input box outlines
+  - Track: Empty bars, slider tracks and unlit stars
+  - ButtonColors: Start screen button fills, cycled from the top (see startButtonColor)
+*/
+type Theme struct {
+	Name          string
+	Background    color.RGBA
+	SongPitch     color.RGBA
+	UserPitchHit  color.RGBA
+	UserPitchMiss color.RGBA
+	NowLine       color.RGBA
+	GridLine      color.RGBA
+	HUDBackground color.RGBA
+	HUDText       color.RGBA
+	DimText       color.RGBA
+	FaintText     color.RGBA
+	Highlight     color.RGBA
+	Info          color.RGBA
+	Good          color.RGBA
+	Bad           color.RGBA
+	Border        color.RGBA
+	Track         color.RGBA
+	ButtonColors  [4]color.RGBA
+}
+
+// builtinThemes are the themes LoadTheme knows by name.
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Name:          "dark",
+		Background:    color.RGBA{0, 0, 0, 255},
+		SongPitch:     color.RGBA{100, 150, 255, 255},
+		UserPitchHit:  color.RGBA{50, 255, 50, 255},
+		UserPitchMiss: color.RGBA{255, 200, 50, 255},
+		NowLine:       color.RGBA{100, 100, 100, 255},
+		GridLine:      color.RGBA{32, 32, 32, 32},
+		HUDBackground: color.RGBA{20, 20, 25, 200},
+		HUDText:       color.RGBA{255, 255, 255, 255},
+		DimText:       color.RGBA{160, 160, 160, 255},
+		FaintText:     color.RGBA{100, 100, 100, 255},
+		Highlight:     color.RGBA{255, 220, 0, 255},
+		Info:          color.RGBA{120, 200, 255, 255},
+		Good:          color.RGBA{80, 220, 80, 255},
+		Bad:           color.RGBA{255, 80, 80, 255},
+		Border:        color.RGBA{70, 70, 80, 255},
+		Track:         color.RGBA{60, 60, 60, 255},
+		ButtonColors: [4]color.RGBA{
+			{0, 200, 100, 255},
+			{100, 100, 200, 255},
+			{200, 100, 100, 255},
+			{200, 140, 40, 255},
+		},
+	},
+	"light": {
+		Name:          "light",
+		Background:    color.RGBA{240, 240, 235, 255},
+		SongPitch:     color.RGBA{30, 80, 200, 255},
+		UserPitchHit:  color.RGBA{0, 150, 40, 255},
+		UserPitchMiss: color.RGBA{210, 110, 0, 255},
+		NowLine:       color.RGBA{120, 120, 120, 255},
+		GridLine:      color.RGBA{0, 0, 0, 28},
+		HUDBackground: color.RGBA{215, 215, 210, 220},
+		HUDText:       color.RGBA{20, 20, 20, 255},
+		DimText:       color.RGBA{85, 85, 85, 255},
+		FaintText:     color.RGBA{140, 140, 140, 255},
+		Highlight:     color.RGBA{170, 100, 0, 255},
+		Info:          color.RGBA{0, 120, 140, 255},
+		Good:          color.RGBA{0, 140, 40, 255},
+		Bad:           color.RGBA{200, 30, 30, 255},
+		Border:        color.RGBA{160, 160, 165, 255},
+		Track:         color.RGBA{200, 200, 195, 255},
+		ButtonColors: [4]color.RGBA{
+			{40, 150, 90, 255},
+			{70, 90, 170, 255},
+			{180, 70, 70, 255},
+			{190, 120, 20, 255},
+		},
+	},
+	"high-contrast": {
+		Name:          "high-contrast",
+		Background:    color.RGBA{0, 0, 0, 255},
+		SongPitch:     color.RGBA{0, 255, 255, 255},
+		UserPitchHit:  color.RGBA{0, 255, 0, 255},
+		UserPitchMiss: color.RGBA{255, 0, 255, 255},
+		NowLine:       color.RGBA{255, 255, 255, 255},
+		GridLine:      color.RGBA{70, 70, 70, 70},
+		HUDBackground: color.RGBA{0, 0, 0, 255},
+		HUDText:       color.RGBA{255, 255, 0, 255},
+		DimText:       color.RGBA{230, 230, 230, 255},
+		FaintText:     color.RGBA{180, 180, 180, 255},
+		Highlight:     color.RGBA{255, 255, 0, 255},
+		Info:          color.RGBA{0, 255, 255, 255},
+		Good:          color.RGBA{0, 255, 0, 255},
+		Bad:           color.RGBA{255, 60, 60, 255},
+		Border:        color.RGBA{255, 255, 255, 255},
+		Track:         color.RGBA{90, 90, 90, 255},
+		ButtonColors: [4]color.RGBA{
+			{0, 110, 0, 255},
+			{0, 0, 170, 255},
+			{170, 0, 0, 255},
+			{120, 70, 0, 255},
+		},
+	},
+}
+
+// ActiveTheme is the theme every drawing function uses. main replaces it
+// with the contents of ThemeFile at startup.
+var ActiveTheme = builtinThemes["dark"]
+
+/*
+LoadTheme returns a built-in theme by name.
+
+Input:
+  - name: string - "dark", "light" or "high-contrast"
+
+Called by:
+  - LoadThemeFile for the theme a file starts from
+
+Task:
+  - Offer ready-made themes without writing colours by hand
+
+Logic:
+ 1. Look the name up in builtinThemes
+
+Output:
+  - Theme: The named theme (dark on error)
+  - error: Unknown theme name
+*/
+func LoadTheme(name string) (Theme, error) {
+	t, ok := builtinThemes[name]
+	if !ok {
+		return builtinThemes["dark"], fmt.Errorf("unknown theme %q (want dark, light or high-contrast)", name)
+	}
+	return t, nil
+}
+
+/*
+LoadThemeFile reads a theme file over the built-in theme it names.
+
+Input:
+  - path: string - JSON theme file (ThemeFile)
+
+Called by:
+  - main at startup
+
+Task:
+  - Let the user pick a built-in theme or tweak single colours
+
+Logic:
+ 1. Decode "Name" (default "dark") and start from LoadTheme(Name)
+ 2. Decode the file again over it; colours missing from the file keep the
+    built-in values
+
+Output:
+  - Theme: Resulting theme (dark on error)
+  - error: Read, JSON or unknown theme error
+*/
+func LoadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return builtinThemes["dark"], err
+	}
+	base := struct{ Name string }{Name: "dark"}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return builtinThemes["dark"], err
+	}
+	t, err := LoadTheme(base.Name)
+	if err != nil {
+		return t, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return builtinThemes["dark"], err
+	}
+	return t, nil
+}
+
+/*
+SaveThemeName writes a theme file that only names a built-in theme.
+
+Input:
+  - name: string - Built-in theme ("dark", "light" or "high-contrast")
+  - path: string - Destination file (ThemeFile)
+
+Called by:
+  - main on first run when ThemeFile does not exist
+
+Task:
+  - Give the user a file where changing "Name" alone switches theme
+
+Logic:
+ 1. Write {"Name": name}; a full SaveCustomTheme dump would pin every colour
+    to the dark values and override the theme the user switches to
+
+Output:
+  - error: nil on success
+*/
+func SaveThemeName(name, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(struct{ Name string }{name}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+/*
+SaveCustomTheme writes a theme so it can be edited and loaded again.
+
+Input:
+  - t: Theme - Theme to store
+  - path: string - Destination file
+
+Called by:
+  - Users of the ui package that want a complete template to recolour from
+
+Task:
+  - Write every colour of a theme so it can be tweaked field by field
+
+Logic:
+ 1. Create the parent directory if needed
+ 2. Write every field as indented JSON
+
+Output:
+  - error: nil on success
+*/
+func SaveCustomTheme(t Theme, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+/*
+scaleColor multiplies every channel of a premultiplied colour.
+
+Input:
+  - c: color.RGBA - Premultiplied colour
+  - f: float64 - Factor (< 1 fainter, > 1 stronger)
+
+Called by:
+  - DrawSemitoneGrid, DrawSongPitchBlocks, DrawNowLine, DrawSessionCompare
+
+Task:
+  - Derive fainter and stronger shades from one theme colour
+
+Logic:
+ 1. Scale R, G, B and A by f, capped at 255 (stays premultiplied)
+
+Output:
+  - color.RGBA: Scaled colour
+*/
+func scaleColor(c color.RGBA, f float64) color.RGBA {
+	scale := func(v uint8) uint8 {
+		return uint8(min(255, float64(v)*f))
+	}
+	return color.RGBA{scale(c.R), scale(c.G), scale(c.B), scale(c.A)}
+}
+
+/*
+startButtonColor returns the fill of the i-th start screen button.
+
+Input:
+  - i: int - Index into StartButtons
+
+Called by:
+  - DrawStartScreen
+
+Task:
+  - Give every start button its own colour from the four theme fills
+
+Logic:
+ 1. Take ButtonColors[i%4]
+ 2. Darken each further row of four (100%, 75%, 56%, ...) so buttons
+    sharing a base colour still differ; alpha stays opaque
+
+Output:
+  - color.RGBA: Button fill
+*/
+func startButtonColor(i int) color.RGBA {
+	c := ActiveTheme.ButtonColors[i%len(ActiveTheme.ButtonColors)]
+	f := math.Pow(0.75, float64(i/len(ActiveTheme.ButtonColors)))
+	return color.RGBA{uint8(float64(c.R) * f), uint8(float64(c.G) * f), uint8(float64(c.B) * f), c.A}
+}
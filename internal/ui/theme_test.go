@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTheme(t *testing.T) {
+	tests := []struct {
+		name       string
+		background color.RGBA
+	}{
+		{"dark", color.RGBA{0, 0, 0, 255}},
+		{"light", color.RGBA{240, 240, 235, 255}},
+		{"high-contrast", color.RGBA{0, 0, 0, 255}},
+	}
+	for _, tt := range tests {
+		theme, err := LoadTheme(tt.name)
+		if err != nil {
+			t.Fatalf("LoadTheme(%q): %v", tt.name, err)
+		}
+		if theme.Name != tt.name || theme.Background != tt.background {
+			t.Errorf("LoadTheme(%q) = %q with background %v, want background %v", tt.name, theme.Name, theme.Background, tt.background)
+		}
+	}
+
+	if theme, err := LoadTheme("sepia"); err == nil || theme.Name != "dark" {
+		t.Errorf("LoadTheme(\"sepia\") = %q, %v; want dark and an error", theme.Name, err)
+	}
+}
+
+func TestThemeFileFirstRunFollowsName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "theme.json")
+	if err := SaveThemeName("dark", path); err != nil {
+		t.Fatalf("SaveThemeName: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"Name": "light"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadThemeFile(path)
+	if err != nil {
+		t.Fatalf("LoadThemeFile: %v", err)
+	}
+	if want, _ := LoadTheme("light"); got != want {
+		t.Errorf("LoadThemeFile after switching the name = %+v, want the light theme", got)
+	}
+}
+
+func TestSaveThemeNameWritesOnlyTheName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := SaveThemeName("high-contrast", path); err != nil {
+		t.Fatalf("SaveThemeName: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"Name\": \"high-contrast\"\n}"; string(data) != want {
+		t.Errorf("theme file = %s, want %s", data, want)
+	}
+}
+
+func TestThemeFileOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.json")
+	data := `{"Name": "light", "Highlight": {"R": 10, "G": 20, "B": 30, "A": 255}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadThemeFile(path)
+	if err != nil {
+		t.Fatalf("LoadThemeFile: %v", err)
+	}
+	light, _ := LoadTheme("light")
+	if got.Highlight != (color.RGBA{10, 20, 30, 255}) {
+		t.Errorf("Highlight = %v, want the override", got.Highlight)
+	}
+	if got.Background != light.Background || got.DimText != light.DimText {
+		t.Errorf("colours missing from the file = %v, %v; want the light theme's", got.Background, got.DimText)
+	}
+}
+
+func TestSaveCustomThemeRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.json")
+	want, _ := LoadTheme("high-contrast")
+	want.Bad = color.RGBA{1, 2, 3, 255}
+	if err := SaveCustomTheme(want, path); err != nil {
+		t.Fatalf("SaveCustomTheme: %v", err)
+	}
+	got, err := LoadThemeFile(path)
+	if err != nil {
+		t.Fatalf("LoadThemeFile: %v", err)
+	}
+	if got != want {
+		t.Errorf("LoadThemeFile = %+v, want %+v", got, want)
+	}
+}
+
+func TestStartButtonColorsDistinct(t *testing.T) {
+	for _, name := range []string{"dark", "light", "high-contrast"} {
+		theme, _ := LoadTheme(name)
+		old := ActiveTheme
+		ActiveTheme = theme
+		seen := make(map[color.RGBA]string)
+		for i, label := range StartButtons {
+			c := startButtonColor(i)
+			if other, ok := seen[c]; ok {
+				t.Errorf("%s: %q and %q share colour %v", name, other, label, c)
+			}
+			if c.A != 255 {
+				t.Errorf("%s: %q is not opaque (%v)", name, label, c)
+			}
+			seen[c] = label
+		}
+		ActiveTheme = old
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"strings"
+	"time"
 
 	"singAssist/internal/config"
+	"singAssist/internal/theory"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -75,7 +78,7 @@ Input:
   - rw, rh: int - Rectangle width and height
 
 Called by:
-  - App.handleStartScreenInput for button click detection
+  - App.handleStartScreenInput for button click detection (via StartButtonRect)
 
 Task:
   - Simple bounds check for mouse interaction
@@ -92,66 +95,51 @@ func InRect(x, y, rx, ry, rw, rh int) bool {
 }
 
 /*
-FreqToMidi converts frequency in Hz to MIDI note number.
-
-Input:
-  - freq: float64 - Frequency in Hz
-
-Called by:
-  - FreqToNote for note name conversion
-  - PitchVisualizer.FreqToY for Y coordinate calculation
-  - PitchVisualizer.DrawUserPitch for hit detection
-
-Task:
-  - Convert frequency to continuous MIDI scale for visualization
-
-Logic:
- 1. If freq <= 0: return 0 (invalid)
- 2. Apply formula: 69 + 12 * log2(freq / 440)
- 3. MIDI 69 = A4 = 440Hz
-
-Output:
-  - float64: Continuous MIDI note number (can be fractional)
+StartButtons lists the start screen button labels from top to bottom.
+App.handleStartScreenInput maps the same indices to modes; the last
+button (after every mode) opens latency calibration. Fills come from
+startButtonColor.
 */
-func FreqToMidi(freq float64) float64 {
-	if freq <= 0 {
-		return 0
-	}
-	return 69 + 12*math.Log2(freq/440.0)
+var StartButtons = []string{
+	"Vocals Only",
+	"Vocals (Rough, no Python)",
+	"Instrumental",
+	"Full Mix",
+	"No Audio",
+	"Duet (2 mics, stereo in)",
+	"Warmup",
+	"Harmony Trainer",
+	"Freestyle",
+	"Vocal Range Test",
+	"Interval Quiz",
+	"Calibrate Latency",
 }
 
 /*
-FreqToNote converts frequency to musical note name and octave.
+StartButtonRect returns the bounds of the i-th start screen button.
 
 Input:
-  - freq: float64 - Frequency in Hz
+  - i: int - Index into StartButtons
+  - sw, sh: int - Screen width and height
 
 Called by:
-  - App.drawPlayingMode for displaying current notes
-  - App.drawNoAudioMode for displaying user pitch
+  - DrawStartScreen for drawing buttons
+  - App.handleStartScreenInput for click detection
 
 Task:
-  - Convert frequency to human-readable note name
+  - Keep button layout in one place for drawing and hit testing
 
 Logic:
- 1. If freq <= 0: return "-", 0
- 2. Convert to MIDI, round to nearest integer
- 3. Note name = notes[midi % 12]
- 4. Octave = midi / 12 - 1
+ 1. Center 240px wide buttons horizontally
+ 2. Stack buttons every 60px starting at sh/2-120, tightening the spacing
+    when that would run past the bottom hint line (buttons stay 10px apart)
 
 Output:
-  - string: Note name (e.g., "C#", "A")
-  - int: Octave number (e.g., 4 for A4)
+  - x, y, w, h: int - Button rectangle
 */
-func FreqToNote(freq float64) (string, int) {
-	if freq <= 0 {
-		return "-", 0
-	}
-	midi := int(math.Round(FreqToMidi(freq)))
-	notes := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
-	note := notes[midi%12]
-	octave := midi/12 - 1
-	return note, octave
+func StartButtonRect(i, sw, sh int) (x, y, w, h int) {
+	step := min(60, (sh/2+90)/len(StartButtons))
+	return sw/2 - 120, sh/2 - 120 + i*step, 240, step - 10
 }
 
 /*
@@ -161,453 +149,3252 @@ Input:
   - screen: *ebiten.Image - Target drawing surface
   - sw, sh: int - Screen width and height
   - songName: string - Current song name for title
+  - bestScore: float64 - Best saved score for the song, negative if none
+  - stars: int - Song difficulty 1..5, 0 if not rated yet
+  - setlistLen: int - Songs in the active setlist, 0 if none
+  - notes: string - The song's notes.md ("" if none)
 
 Called by:
   - App.Draw when state is StateStartScreen
 
 Task:
-  - Draw title and four mode selection buttons
+  - Draw title and mode selection buttons
 
 Logic:
- 1. Fill screen with black
- 2. Draw title (with song name if available)
- 3. Draw four buttons: Vocals, Instrumental, Full Mix, No Audio
+ 1. Fill screen with the theme background
+ 2. Draw title (with song name if available), the difficulty stars beneath it,
+    then the best score and "Setlist: X songs"; the first 3 lines of the notes
+    (noteSnippet) go directly beneath the title, which moves up 16px per line
+ 3. Draw one button per StartButtons entry at StartButtonRect
  4. Buttons are centered horizontally, stacked vertically
 
 Output:
   - None (draws to screen)
 */
-func DrawStartScreen(screen *ebiten.Image, sw, sh int, songName string) {
-	screen.Fill(color.Black)
+func DrawStartScreen(screen *ebiten.Image, sw, sh int, songName string, bestScore float64, stars, setlistLen int, notes string) {
+	screen.Fill(ActiveTheme.Background)
 
 	title := "SingAssist"
 	if songName != "" {
 		title = "SingAssist - " + songName
 	}
-	text.Draw(screen, title, basicfont.Face7x13, sw/2-40, sh/2-160, color.White)
+	snippet := noteSnippet(notes, 3, 44)
+	titleY := sh/2 - 160 - 16*len(snippet)
+	text.Draw(screen, title, basicfont.Face7x13, sw/2-40, titleY, ActiveTheme.HUDText)
+	for i, l := range snippet {
+		text.Draw(screen, l, basicfont.Face7x13, sw/2-40, titleY+16*(i+1), ActiveTheme.DimText)
+	}
+	if bestScore >= 0 {
+		text.Draw(screen, fmt.Sprintf("Best: %.1f%%", bestScore), basicfont.Face7x13, sw/2-40, sh/2-130, ActiveTheme.Highlight)
+	}
+	if setlistLen > 0 {
+		text.Draw(screen, fmt.Sprintf("Setlist: %d songs", setlistLen), basicfont.Face7x13, sw/2+60, sh/2-130, ActiveTheme.Info)
+	}
+	if stars > 0 {
+		text.Draw(screen, "Difficulty:", basicfont.Face7x13, sw/2-40, sh/2-145, ActiveTheme.DimText)
+		for i := 0; i < 5; i++ {
+			clr := ActiveTheme.Track
+			if i < stars {
+				clr = ActiveTheme.Highlight
+			}
+			drawStar(screen, float32(sw/2+47+i*14), float32(sh/2-150), 6, clr)
+		}
+	}
+
+	for i, label := range StartButtons {
+		x, y, w, h := StartButtonRect(i, sw, sh)
+		DrawButton(screen, x, y, w, h, label, startButtonColor(i))
+	}
 
-	DrawButton(screen, sw/2-100, sh/2-120, 200, 50, "Vocals Only", color.RGBA{0, 200, 100, 255})
-	DrawButton(screen, sw/2-100, sh/2-60, 200, 50, "Instrumental", color.RGBA{100, 100, 200, 255})
-	DrawButton(screen, sw/2-100, sh/2, 200, 50, "Full Mix", color.RGBA{200, 100, 100, 255})
-	DrawButton(screen, sw/2-100, sh/2+60, 200, 50, "No Audio", color.RGBA{150, 150, 50, 255})
+	DrawTextAt(screen, "H: Practice history  L: Song list  Left/Right: Microphone  +/-: Hit tolerance  N: Notes", 10, sh-20, ActiveTheme.HUDText)
 }
 
 /*
-DrawCalibrating renders the calibration screen with instructions.
+DrawDeviceSelector renders the microphone choice at the top of the start screen.
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
-  - sw, sh: int - Screen width and height
+  - names: []string - Selectable input names ("Default" first)
+  - selected: int - Index of the chosen name
+  - sw: int - Screen width
 
 Called by:
-  - App.Draw when state is StateCalibrating
+  - App.drawDeviceSelector when state is StateStartScreen
 
 Task:
-  - Display calibration message
+  - Show the active microphone and the ones Left/Right switch to
 
 Logic:
- 1. Fill screen with black
- 2. Draw centered message asking for silence
+ 1. Truncate names to deviceNameLen characters
+ 2. Centre the selected name in "Mic: < name >", highlighted
+ 3. Draw the previous and next names (wrapping) dimmed on either side
 
 Output:
   - None (draws to screen)
 */
-func DrawCalibrating(screen *ebiten.Image, sw, sh int) {
-	screen.Fill(color.Black)
-	msg := "Calibrating Silence...\nPlease stay quiet."
-	text.Draw(screen, msg, basicfont.Face7x13, sw/2-60, sh/2, color.White)
+func DrawDeviceSelector(screen *ebiten.Image, names []string, selected int, sw int) {
+	if len(names) == 0 {
+		return
+	}
+	const deviceNameLen = 28
+	short := func(i int) string {
+		name := names[(i+len(names))%len(names)]
+		if len(name) > deviceNameLen {
+			name = name[:deviceNameLen-3] + "..."
+		}
+		return name
+	}
+
+	y := 24
+	label := "Mic: < " + short(selected) + " >"
+	x := sw/2 - len(label)*7/2
+	text.Draw(screen, label, basicfont.Face7x13, x, y, ActiveTheme.Good)
+	if len(names) < 2 {
+		return
+	}
+	dim := ActiveTheme.FaintText
+	prev := short(selected - 1)
+	text.Draw(screen, prev, basicfont.Face7x13, x-20-len(prev)*7, y, dim)
+	text.Draw(screen, short(selected+1), basicfont.Face7x13, x+len(label)*7+20, y, dim)
 }
 
 /*
-DrawMessage renders a debug/status message at top-left.
+drawStar fills a five-pointed star.
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
-  - msg: string - Message to display
+  - cx, cy: float32 - Centre
+  - r: float32 - Outer radius (the inner points are at 0.4r)
+  - clr: color.Color - Fill colour
 
 Called by:
-  - App.Draw for error/loading messages
+  - DrawStartScreen for the difficulty rating
 
 Task:
-  - Display status text
+  - Draw rating stars (the bitmap fonts have no ★ glyph)
 
 Logic:
- 1. Use ebitenutil.DebugPrint for simple text at (0,0)
+ 1. Path through 10 points alternating outer and inner radius, first point up
+ 2. vector.FillPath with the colour as the colour scale
 
 Output:
   - None (draws to screen)
 */
-func DrawMessage(screen *ebiten.Image, msg string) {
-	ebitenutil.DebugPrint(screen, msg)
-}
+func drawStar(screen *ebiten.Image, cx, cy, r float32, clr color.Color) {
+	var path vector.Path
+	for i := 0; i < 10; i++ {
+		rad := r
+		if i%2 == 1 {
+			rad = r * 0.4
+		}
+		a := float64(i)*math.Pi/5 - math.Pi/2
+		x, y := cx+rad*float32(math.Cos(a)), cy+rad*float32(math.Sin(a))
+		if i == 0 {
+			path.MoveTo(x, y)
+		} else {
+			path.LineTo(x, y)
+		}
+	}
+	path.Close()
 
-/*
-NoteDisplay contains info for rendering a prominent note indicator.
-*/
-type NoteDisplay struct {
-	Note      string
-	Octave    int
-	Freq      float64
-	IsMatched bool
+	op := &vector.DrawPathOptions{AntiAlias: true}
+	op.ColorScale.ScaleWithColor(clr)
+	vector.FillPath(screen, &path, nil, op)
 }
 
 /*
-DrawNoteHUD renders the production-grade note display with large notes on left and right.
+DrawCalibrating renders the calibration screen with instructions.
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
-  - sw: int - Screen width
-  - songNote: NoteDisplay - Current song note info
-  - userNote: NoteDisplay - Current user note info
+  - sw, sh: int - Screen width and height
 
 Called by:
-  - App.drawPlayingMode
+  - App.Draw when state is StateCalibrating
 
 Task:
-  - Display prominent note indicators: song on left, user on right
+  - Display calibration message
 
 Logic:
- 1. Draw semi-transparent background panels
- 2. Draw large note text (e.g., "C#4") in gray
- 3. Draw smaller frequency below
- 4. If notes match, show green highlight on user side
+ 1. Fill screen with the theme background
+ 2. Draw centered message asking for silence
 
 Output:
   - None (draws to screen)
 */
-func DrawNoteHUD(screen *ebiten.Image, sw int, songNote, userNote NoteDisplay) {
-	gray := color.RGBA{140, 140, 140, 255}
-	dimGray := color.RGBA{80, 80, 80, 255}
-	green := color.RGBA{80, 220, 80, 255}
-	panelBg := color.RGBA{20, 20, 25, 200}
+func DrawCalibrating(screen *ebiten.Image, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	msg := "Calibrating Silence...\nPlease stay quiet."
+	text.Draw(screen, msg, basicfont.Face7x13, sw/2-60, sh/2, ActiveTheme.HUDText)
+}
 
-	vector.DrawFilledRect(screen, 15, 15, 130, 80, panelBg, false)
-	vector.DrawFilledRect(screen, float32(sw-145), 15, 130, 80, panelBg, false)
+/*
+DrawCountdown renders the pre-song countdown in large text.
 
-	if bigFont != nil {
-		songNoteText := songNote.Note
-		if songNote.Note != "-" && songNote.Octave > 0 {
-			songNoteText = fmt.Sprintf("%s%d", songNote.Note, songNote.Octave)
-		}
-		text.Draw(screen, songNoteText, bigFont, 25, 65, gray)
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - remaining: time.Duration - Time left until "GO!"
+  - sw, sh: int - Screen width and height
 
-		userNoteText := userNote.Note
-		if userNote.Note != "-" && userNote.Octave > 0 {
-			userNoteText = fmt.Sprintf("%s%d", userNote.Note, userNote.Octave)
-		}
-		noteColor := gray
-		if userNote.IsMatched {
-			noteColor = green
-		}
-		text.Draw(screen, userNoteText, bigFont, sw-135, 65, noteColor)
-	}
+Called by:
+  - App.drawCountdown when state is StateCountdown
 
-	if smallFont != nil {
-		songFreqText := "---"
-		if songNote.Freq > 10 {
-			songFreqText = fmt.Sprintf("%.0f Hz", songNote.Freq)
-		}
-		text.Draw(screen, songFreqText, smallFont, 25, 85, dimGray)
+Task:
+  - Give the singer a "3… 2… 1… GO!" lead-in
 
-		userFreqText := "---"
-		if userNote.Freq > 10 {
-			userFreqText = fmt.Sprintf("%.0f Hz", userNote.Freq)
-		}
-		text.Draw(screen, userFreqText, smallFont, sw-135, 85, dimGray)
-	}
+Logic:
+ 1. Fill screen with the theme background
+ 2. Label is the remaining whole seconds rounded up, or "GO!" once it runs out
+ 3. Draw it centered in the big font, with a hint line underneath
 
-	if smallFont != nil {
-		text.Draw(screen, "SONG", smallFont, 25, 28, dimGray)
-		text.Draw(screen, "YOU", smallFont, sw-65, 28, dimGray)
+Output:
+  - None (draws to screen)
+*/
+func DrawCountdown(screen *ebiten.Image, remaining time.Duration, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+
+	label := "GO!"
+	if remaining > 0 {
+		label = fmt.Sprintf("%d", int(math.Ceil(remaining.Seconds())))
+	}
+	if bigFont != nil {
+		b := text.BoundString(bigFont, label)
+		text.Draw(screen, label, bigFont, sw/2-b.Dx()/2, sh/2+b.Dy()/2, ActiveTheme.Highlight)
 	}
+	text.Draw(screen, "Get ready to sing", basicfont.Face7x13, sw/2-60, sh/2+60, ActiveTheme.DimText)
 }
 
 /*
-PitchVisualizer handles coordinate transformations and pitch graph rendering.
+ResultsPanel is one singer's statistics on the results screen.
 
 Fields:
-  - OffsetY: Y position of lowest displayed note
-  - ScaleY: Pixels per semitone
-  - BaseMidi: MIDI note number at bottom of display
-  - OffsetX: X position of "now" line
+  - Label: Panel heading ("Singer 1" in duet mode, "" for a solo run)
+  - Score: Hit percentage 0..100
+  - HitFrames, TotalFrames: Voiced song frames (10ms) hit and scored
+  - LongestStreak: Most consecutive hit frames
+  - BestNote, WorstNote: Song notes hit and missed most often ("" if none)
+  - PitchClasses: Hit rate 0..1 per pitch class C..B, -1 where the song had none
+  - Points, Multiplier: Score times the run's multiplier (above 1 for sight-reading runs)
+  - Journal: Freestyle pitch pairs [timeMs, pitch, ...]; when set the panel shows
+    the whole-session pitch overview instead of a score
 */
-type PitchVisualizer struct {
-	OffsetY  float64
-	ScaleY   float64
-	BaseMidi float64
-	OffsetX  float64
+type ResultsPanel struct {
+	Label         string
+	Score         float64
+	HitFrames     int
+	TotalFrames   int
+	LongestStreak int
+	BestNote      string
+	WorstNote     string
+	PitchClasses  [12]float64
+	Points        float64
+	Multiplier    float64
+	Journal       []float64
 }
 
 /*
-NewPitchVisualizer creates a visualizer configured for given screen size.
+DrawResultsScreen renders the end-of-song statistics.
 
 Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - songName: string - Song folder name for the title
+  - panels: []ResultsPanel - One panel per singer
   - sw, sh: int - Screen width and height
 
 Called by:
-  - App.drawPlayingMode multiple times per frame
-  - App.drawNoAudioMode for pitch marker
+  - App.Draw when state is StateResults
 
 Task:
-  - Calculate layout parameters for pitch visualization
+  - Summarize the finished run
 
 Logic:
- 1. OffsetY = bottom margin (sh - 50)
- 2. ScaleY = available height / 60 semitones
- 3. BaseMidi = 30 (approximately F#1, low bass)
- 4. OffsetX = 20% from left (position of "now" line)
+ 1. Fill screen with the theme background and draw the title
+ 2. Lay the panels out side by side, centered
+ 3. In each: the score in the big font (green from 80%, yellow from 50%,
+    red below), the sight-reading points when a multiplier applied, then hit frames, longest streak in seconds, best and worst note,
+    and the pitch-class histogram (DrawPitchClassHistogram)
+    (a freestyle panel with a Journal: drawJournalPanel across the screen instead)
+ 4. Draw the key hint (C opens the session comparison after a scored run)
 
 Output:
-  - *PitchVisualizer: Configured for current screen size
+  - None (draws to screen)
 */
-func NewPitchVisualizer(sw, sh int) *PitchVisualizer {
-	return &PitchVisualizer{
-		OffsetY:  float64(sh) - 50,
-		ScaleY:   float64(sh-100) / 60.0,
-		BaseMidi: 30.0,
-		OffsetX:  float64(sw) * 0.2,
+func DrawResultsScreen(screen *ebiten.Image, songName string, panels []ResultsPanel, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	text.Draw(screen, "RESULTS - "+songName, basicfont.Face7x13, sw/2-35-7*len(songName)/2, sh/2-170, ActiveTheme.HUDText)
+
+	if len(panels) == 1 && panels[0].Journal != nil {
+		drawJournalPanel(screen, panels[0].Journal, 60, sh/2-140, sw-120, 280)
+		DrawTextAt(screen, "ENTER/SPACE: Back to menu", 10, sh-20, ActiveTheme.HUDText)
+		return
+	}
+
+	const panelW, panelH, gap = 260, 380, 30
+	left := sw/2 - (len(panels)*panelW+(len(panels)-1)*gap)/2
+	for i, p := range panels {
+		x := left + i*(panelW+gap)
+		y := sh/2 - 140
+		vector.DrawFilledRect(screen, float32(x), float32(y), panelW, panelH, ActiveTheme.HUDBackground, false)
+		vector.StrokeRect(screen, float32(x), float32(y), panelW, panelH, 1, ActiveTheme.Border, false)
+
+		if p.Label != "" {
+			text.Draw(screen, p.Label, basicfont.Face7x13, x+15, y+22, ActiveTheme.DimText)
+		}
+
+		scoreCol := ActiveTheme.Bad
+		if p.Score >= 80 {
+			scoreCol = ActiveTheme.Good
+		} else if p.Score >= 50 {
+			scoreCol = ActiveTheme.Highlight
+		}
+		if bigFont != nil {
+			text.Draw(screen, fmt.Sprintf("%.1f%%", p.Score), bigFont, x+15, y+85, scoreCol)
+		}
+		if p.Multiplier > 1 {
+			label := fmt.Sprintf("SIGHT READ x%.1f = %.1f pts", p.Multiplier, p.Points)
+			text.Draw(screen, label, basicfont.Face7x13, x+15, y+108, ActiveTheme.Info)
+		}
+
+		orDash := func(s string) string {
+			if s == "" {
+				return "-"
+			}
+			return s
+		}
+		rows := []string{
+			fmt.Sprintf("Frames hit:     %d / %d", p.HitFrames, p.TotalFrames),
+			fmt.Sprintf("Longest streak: %.1f s", float64(p.LongestStreak)/100),
+			"Best note:      " + orDash(p.BestNote),
+			"Worst note:     " + orDash(p.WorstNote),
+		}
+		for r, row := range rows {
+			text.Draw(screen, row, basicfont.Face7x13, x+15, y+130+r*28, ActiveTheme.HUDText)
+		}
+		DrawPitchClassHistogram(screen, p.PitchClasses, x+15, y+240, panelW-30, 125)
 	}
+
+	DrawTextAt(screen, "ENTER/SPACE: Back to menu  C: Compare sessions", 10, sh-20, ActiveTheme.HUDText)
 }
 
 /*
-FreqToY converts frequency to Y screen coordinate.
+DrawPitchClassHistogram renders the hit rate per pitch class as a bar chart.
 
 Input:
-  - f: float64 - Frequency in Hz
+  - screen: *ebiten.Image - Target drawing surface
+  - accuracy: [12]float64 - Hit rate 0..1 for C, C#, ... B (negative: not in the song)
+  - x, y, w, h: int - Chart rectangle (labels included)
 
 Called by:
-  - DrawSongPitch, DrawUserPitch, DrawCurrentPitch
+  - DrawResultsScreen in each singer's panel
 
 Task:
-  - Map frequency to vertical position (higher freq = higher on screen)
+  - Show at a glance which notes the user consistently misses
 
 Logic:
- 1. If f <= 0: return off-screen (-100)
- 2. Convert to MIDI note
- 3. Calculate Y = OffsetY - (midi - BaseMidi) * ScaleY
+ 1. Split the width into 12 columns and keep 16px at the bottom for labels
+ 2. Bar height is proportional to the hit rate; green from 80%, yellow
+    from 50%, red below (a missed-every-time note still gets a 1px red bar)
+ 3. Pitch classes the song never used get no bar and a grey label
 
 Output:
-  - float64: Y coordinate (lower = higher pitch)
+  - None (draws to screen)
 */
-func (v *PitchVisualizer) FreqToY(f float64) float64 {
-	if f <= 0 {
-		return -100
+func DrawPitchClassHistogram(screen *ebiten.Image, accuracy [12]float64, x, y, w, h int) {
+	names := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	colW := float32(w) / 12
+	barMax := float32(h - 16)
+	base := float32(y) + barMax
+
+	vector.StrokeLine(screen, float32(x), base, float32(x+w), base, 1, ActiveTheme.Border, false)
+	for pc, acc := range accuracy {
+		cx := float32(x) + float32(pc)*colW
+		var labelCol color.Color = ActiveTheme.FaintText
+		if acc >= 0 {
+			labelCol = ActiveTheme.HUDText
+			clr := ActiveTheme.Bad
+			if acc >= 0.8 {
+				clr = ActiveTheme.Good
+			} else if acc >= 0.5 {
+				clr = ActiveTheme.Highlight
+			}
+			bh := max(1, float32(acc)*barMax)
+			vector.DrawFilledRect(screen, cx+2, base-bh, colW-4, bh, clr, false)
+		}
+		text.Draw(screen, names[pc], basicfont.Face7x13, int(cx+colW/2)-len(names[pc])*7/2, y+h-2, labelCol)
 	}
-	m := FreqToMidi(f)
-	return v.OffsetY - (m-v.BaseMidi)*v.ScaleY
 }
 
 /*
-DrawSongPitch renders the song's pitch contour as a blue line.
+drawJournalPanel renders a freestyle session overview.
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
-  - data: []float64 - Pitch values at 10ms intervals
-  - currTime: float64 - Current playback time in seconds
-  - sw, sh: int - Screen dimensions
+  - journal: []float64 - Recorded pairs [timeMs, pitch, ...]
+  - x, y, w, h: int - Panel rectangle
 
 Called by:
-  - App.drawPlayingMode
+  - DrawResultsScreen for a freestyle run
 
 Task:
-  - Draw song pitch within visible time window (-3s to +5s from now)
+  - Show the whole session at a glance, with no reference to compare against
 
 Logic:
- 1. Calculate visible index range from currTime ± window
- 2. For each pitch sample in range:
-    a. Skip if pitch <= 5 (silence), break line continuity
-    b. Calculate X from time offset, Y from FreqToY
-    c. Draw line segment to previous point, or 3x3 rect if first point
- 3. Track previous point for line continuity
+ 1. Draw the panel box and summary rows (length, voiced time, note range)
+ 2. Fit the pitch axis to the sung range (±2 semitones) below the rows
+ 3. Draw the whole journal across the panel with DrawPitchJournal
 
 Output:
   - None (draws to screen)
 */
-func (v *PitchVisualizer) DrawSongPitch(screen *ebiten.Image, data []float64, currTime float64, sw, sh int) {
-	col := color.RGBA{100, 150, 255, 255}
-	stepSec := 0.01
-
-	var prevX, prevY float64
-	first := true
-
-	startIdx := int((currTime - 3.0) / stepSec)
-	if startIdx < 0 {
-		startIdx = 0
+func drawJournalPanel(screen *ebiten.Image, journal []float64, x, y, w, h int) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), ActiveTheme.HUDBackground, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, ActiveTheme.Border, false)
+
+	lo, hi, voiced := journalRange(journal)
+	length := 0.0
+	if n := len(journal); n >= 2 {
+		length = journal[n-2] / 1000
 	}
-	endIdx := int((currTime + 5.0) / stepSec)
-	if endIdx >= len(data) {
-		endIdx = len(data) - 1
+	rangeStr := "-"
+	if hi > 0 {
+		loNote, loOct := theory.FreqToNote(lo)
+		hiNote, hiOct := theory.FreqToNote(hi)
+		rangeStr = fmt.Sprintf("%s%d - %s%d", loNote, loOct, hiNote, hiOct)
 	}
+	summary := fmt.Sprintf("FREESTYLE   Length: %.0f s   Voiced: %.0f s   Range: %s", length, voiced, rangeStr)
+	text.Draw(screen, summary, basicfont.Face7x13, x+15, y+22, ActiveTheme.HUDText)
 
-	for i := startIdx; i <= endIdx; i++ {
-		p := data[i]
-		if p <= 5 {
-			first = true
-			continue
-		}
-
-		t := float64(i) * stepSec
-		x := (t-currTime)*config.PixelsPerSec + v.OffsetX
-		y := v.FreqToY(p)
-
-		if y < 0 || y > float64(sh) {
-			first = true
-			continue
-		}
-
-		if !first {
-			ebitenutil.DrawLine(screen, prevX, prevY, x, y, col)
-		} else {
-			ebitenutil.DrawRect(screen, x, y, 3, 3, col)
-		}
-
-		prevX, prevY = x, y
-		first = false
+	if hi <= 0 || length <= 0 {
+		return
 	}
+	top, bottom := float64(y+40), float64(y+h-15)
+	loMidi, hiMidi := math.Floor(theory.FreqToMidi(lo))-2, math.Ceil(theory.FreqToMidi(hi))+2
+	vis := &PitchVisualizer{
+		OffsetY:  bottom,
+		ScaleY:   (bottom - top) / (hiMidi - loMidi),
+		BaseMidi: loMidi,
+	}
+	vis.DrawPitchJournal(screen, journal, 0, length*1000, float64(x+10), float64(x+w-10))
 }
 
 /*
-DrawUserPitch renders the user's recorded pitch trail with hit detection.
+journalRange finds the sung range and voiced time of a pitch journal.
 
 Input:
-  - screen: *ebiten.Image - Target drawing surface
-  - userPitch: []float64 - Pairs of [timeMs, pitch, timeMs, pitch, ...]
-  - songPitch: []float64 - Song pitch data for hit comparison
-  - currTime: float64 - Current playback time in seconds
-  - sw, sh: int - Screen dimensions
+  - journal: []float64 - Pairs [timeMs, pitch, ...]
 
 Called by:
-  - App.drawPlayingMode
+  - drawJournalPanel
 
 Task:
-  - Draw user pitch trail, colored by accuracy (green=hit, yellow=miss)
+  - Summarize a freestyle session
 
 Logic:
- 1. Apply latency compensation to time values
- 2. Iterate userPitch in pairs (time, pitch)
- 3. Skip silence (pitch <= 10)
- 4. Calculate X from time, Y from FreqToY
- 5. Skip if off-screen left (<-50), break if off-screen right
- 6. Compare pitch to song pitch at same time:
-    - Green if within 0.7 semitones
-    - Yellow otherwise
- 7. Draw line to previous point
+ 1. Lowest and highest pitch above 10 Hz
+ 2. Voiced time: sum of the gaps before each voiced sample
 
 Output:
-  - None (draws to screen)
+  - lo, hi: float64 - Lowest and highest pitch in Hz (0 when nothing was sung)
+  - voiced: float64 - Seconds with a pitch
 */
-func (v *PitchVisualizer) DrawUserPitch(screen *ebiten.Image, userPitch []float64, songPitch []float64, currTime float64, sw, sh int) {
-	var prevX, prevY float64
-	first := true
-
-	latencyOffset := config.AudioLatencyMs / 1000.0
-
-	for i := 0; i < len(userPitch); i += 2 {
-		rawT := userPitch[i] / 1000.0
-		t := rawT - latencyOffset
-		p := userPitch[i+1]
-
+func journalRange(journal []float64) (lo, hi, voiced float64) {
+	for i := 0; i+1 < len(journal); i += 2 {
+		p := journal[i+1]
 		if p <= 10 {
-			first = true
 			continue
 		}
-
-		x := (t-currTime)*config.PixelsPerSec + v.OffsetX
-		y := v.FreqToY(p)
-
-		if x < -50 {
-			continue
-		}
-		if x > float64(sw) {
-			break
+		if lo == 0 || p < lo {
+			lo = p
 		}
-
-		col := color.RGBA{255, 200, 50, 255}
-
-		sIdx := int(t * 100)
-		if sIdx >= 0 && sIdx < len(songPitch) {
-			ref := songPitch[sIdx]
-			if ref > 10 && math.Abs(FreqToMidi(p)-FreqToMidi(ref)) < 0.7 {
-				col = color.RGBA{50, 255, 50, 255}
-			}
-		}
-
-		if !first {
-			ebitenutil.DrawLine(screen, prevX, prevY, x, y, col)
+		hi = max(hi, p)
+		if i >= 2 {
+			voiced += (journal[i] - journal[i-2]) / 1000
 		}
-		prevX, prevY = x, y
-		first = false
 	}
+	return lo, hi, voiced
 }
 
 /*
-DrawCurrentPitch renders a white square marker at the current pitch position.
+DrawMessage renders a debug/status message at top-left.
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
-  - pitch: float64 - Current pitch in Hz
+  - msg: string - Message to display
 
 Called by:
-  - App.drawPlayingMode, App.drawNoAudioMode
+  - App.Draw for error/loading messages
 
 Task:
-  - Show real-time pitch indicator
+  - Display status text
 
 Logic:
- 1. If pitch > 10: draw 10x10 white rectangle centered on (OffsetX, FreqToY(pitch))
+ 1. DrawTextAt the top-left corner in the HUD text colour
 
 Output:
   - None (draws to screen)
 */
-func (v *PitchVisualizer) DrawCurrentPitch(screen *ebiten.Image, pitch float64) {
-	if pitch > 10 {
-		y := v.FreqToY(pitch)
-		ebitenutil.DrawRect(screen, v.OffsetX-5, y-5, 10, 10, color.White)
-	}
+func DrawMessage(screen *ebiten.Image, msg string) {
+	DrawTextAt(screen, msg, 0, 0, ActiveTheme.HUDText)
 }
 
 /*
-DrawNowLine draws the vertical timeline indicator.
+DrawTextAt draws plain text with its top-left corner at (x, y).
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
-  - sh: int - Screen height
+  - msg: string - Text, may span several lines
+  - x, y: int - Top-left corner, as for ebitenutil.DebugPrintAt
+  - clr: color.Color - Text colour (usually ActiveTheme.HUDText)
 
 Called by:
-  - App.drawPlayingMode
+  - DrawMessage, DrawControls and the other key hint lines
+  - App draw functions for status and hint text
 
 Task:
-  - Draw vertical gray line at "now" position
+  - Replace ebitenutil.DebugPrintAt, which always draws white and is
+    unreadable on the light theme
 
 Logic:
- 1. Draw vertical line from (OffsetX, 0) to (OffsetX, sh)
+ 1. Draw each line in basicfont at 16px line spacing, the baseline 12px
+    below the line top
 
 Output:
   - None (draws to screen)
 */
-func (v *PitchVisualizer) DrawNowLine(screen *ebiten.Image, sh int) {
-	ebitenutil.DrawLine(screen, v.OffsetX, 0, v.OffsetX, float64(sh), color.Gray{100})
+func DrawTextAt(screen *ebiten.Image, msg string, x, y int, clr color.Color) {
+	for i, line := range strings.Split(msg, "\n") {
+		text.Draw(screen, line, basicfont.Face7x13, x, y+12+16*i, clr)
+	}
 }
 
 /*
-DrawControls renders keyboard shortcut hints at bottom of screen.
+DrawProgressBar renders a progress bar beneath the status message.
 
 Input:
   - screen: *ebiten.Image - Target drawing surface
-  - sh: int - Screen height
+  - progress: float64 - Fraction done (0..1)
 
 Called by:
-  - App.drawPlayingMode
+  - App.Draw while the song pitch is analyzed
 
 Task:
-  - Show available controls to user
+  - Show how far along a long song analysis is
 
 Logic:
- 1. Draw text at (10, sh-20)
+ 1. Draw a 300px track under the DrawMessage line
+ 2. Fill it in the good colour up to progress
 
 Output:
   - None (draws to screen)
 */
-func DrawControls(screen *ebiten.Image, sh int) {
-	ebitenutil.DebugPrintAt(screen, "SPACE:Pause  ←→:±10s  F:Fullscreen  ESC:Exit", 10, sh-20)
+func DrawProgressBar(screen *ebiten.Image, progress float64) {
+	const x, y, w, h = 4, 20, 300, 8
+	progress = math.Max(0, math.Min(1, progress))
+	vector.DrawFilledRect(screen, x, y, w, h, ActiveTheme.Track, false)
+	vector.DrawFilledRect(screen, x, y, float32(progress*w), h, ActiveTheme.Good, false)
+}
+
+/*
+NoteDisplay contains info for rendering a prominent note indicator.
+*/
+type NoteDisplay struct {
+	Note      string
+	Octave    int
+	Freq      float64
+	IsMatched bool
+	CentsDev  float64
+	Transpose int
+	Label     string
+}
+
+/*
+DrawNoteHUD renders the production-grade note display with large notes on left and right.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+  - songNote: NoteDisplay - Current song note info
+  - userNote: NoteDisplay - Current user note info
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Display prominent note indicators: song on left, user on right
+
+Logic:
+ 1. Draw a semi-transparent background panel for the song
+ 2. Draw large note text (e.g., "C#4") in the dim text colour
+ 3. Draw smaller frequency and cents offset below (e.g., "440 Hz +12¢"),
+    hidden when there is no pitch
+ 4. Show the transposition (e.g. "+3 st") next to the SONG label
+ 5. Draw the user's panel with DrawUserNotePanel (label defaults to "YOU")
+
+Output:
+  - None (draws to screen)
+*/
+func DrawNoteHUD(screen *ebiten.Image, sw int, songNote, userNote NoteDisplay) {
+	dim := ActiveTheme.DimText
+	faint := ActiveTheme.FaintText
+	panelBg := ActiveTheme.HUDBackground
+
+	vector.DrawFilledRect(screen, 15, 15, 130, 80, panelBg, false)
+
+	if bigFont != nil {
+		songNoteText := songNote.Note
+		if songNote.Note != "-" && songNote.Octave > 0 {
+			songNoteText = fmt.Sprintf("%s%d", songNote.Note, songNote.Octave)
+		}
+		text.Draw(screen, songNoteText, bigFont, 25, 65, dim)
+	}
+
+	if smallFont != nil {
+		songFreqText := "---"
+		if songNote.Freq > 10 {
+			songFreqText = fmt.Sprintf("%.0f Hz %+.0f¢", songNote.Freq, theory.CentsOffset(songNote.Freq))
+		}
+		text.Draw(screen, songFreqText, smallFont, 25, 85, faint)
+
+		text.Draw(screen, "SONG", smallFont, 25, 28, faint)
+		if songNote.Transpose != 0 {
+			text.Draw(screen, fmt.Sprintf("%+d st", songNote.Transpose), smallFont, 95, 28, ActiveTheme.Highlight)
+		}
+	}
+
+	if userNote.Label == "" {
+		userNote.Label = "YOU"
+	}
+	DrawUserNotePanel(screen, sw-145, userNote)
+}
+
+/*
+DrawUserNotePanel renders one singer's note panel and tuning bar.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - x: int - Left edge of the 130px panel
+  - note: NoteDisplay - The singer's current note (Label names the panel)
+
+Called by:
+  - DrawNoteHUD for the right-hand panel
+  - App.drawPlayingMode for the second singer's centre panel in duet mode
+
+Task:
+  - Share the user panel layout between solo and duet HUDs
+
+Logic:
+ 1. Draw the panel background
+ 2. Draw the note name, green when matched
+ 3. Draw frequency and cents offset, "---" when there is no pitch
+ 4. Draw the label right-aligned in the panel header
+ 5. Draw the cents-deviation bar underneath while there is a pitch
+
+Output:
+  - None (draws to screen)
+*/
+func DrawUserNotePanel(screen *ebiten.Image, x int, note NoteDisplay) {
+	dim := ActiveTheme.DimText
+	faint := ActiveTheme.FaintText
+	panelBg := ActiveTheme.HUDBackground
+
+	vector.DrawFilledRect(screen, float32(x), 15, 130, 80, panelBg, false)
+
+	if bigFont != nil {
+		noteText := note.Note
+		if note.Note != "-" && note.Octave > 0 {
+			noteText = fmt.Sprintf("%s%d", note.Note, note.Octave)
+		}
+		noteColor := dim
+		if note.IsMatched {
+			noteColor = ActiveTheme.Good
+		}
+		text.Draw(screen, noteText, bigFont, x+10, 65, noteColor)
+	}
+
+	if smallFont != nil {
+		freqText := "---"
+		if note.Freq > 10 {
+			freqText = fmt.Sprintf("%.0f Hz %+.0f¢", note.Freq, theory.CentsOffset(note.Freq))
+		}
+		text.Draw(screen, freqText, smallFont, x+10, 85, faint)
+		text.Draw(screen, note.Label, smallFont, x+104-8*len(note.Label), 28, faint)
+	}
+
+	if note.Freq > 10 {
+		DrawCentsBar(screen, note.CentsDev, x, 100, 130)
+	}
+}
+
+/*
+DrawIntervalPanel renders the harmony trainer's interval readout.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width (the panel is centred)
+  - label: string - Interval from song to user (e.g. "P5 ↑"), "-" when either is silent
+  - onTarget: bool - Whether the user is on one of the song's target harmonies
+
+Called by:
+  - App.drawPlayingMode in harmony mode
+
+Task:
+  - Show which harmony the user is singing, between the SONG and YOU panels
+
+Logic:
+ 1. Draw a panel the size of the note panels in the top centre
+ 2. Draw an "INTERVAL" header and the label, good colour on target, HUD text otherwise
+
+Output:
+  - None (draws to screen)
+*/
+func DrawIntervalPanel(screen *ebiten.Image, sw int, label string, onTarget bool) {
+	x := sw/2 - 65
+	vector.DrawFilledRect(screen, float32(x), 15, 130, 80, ActiveTheme.HUDBackground, false)
+
+	if smallFont != nil {
+		text.Draw(screen, "INTERVAL", smallFont, x+10, 28, ActiveTheme.FaintText)
+	}
+	if bigFont != nil {
+		var clr color.Color = ActiveTheme.HUDText
+		if onTarget {
+			clr = ActiveTheme.Good
+		}
+		text.Draw(screen, label, bigFont, x+10, 75, clr)
+	}
+}
+
+/*
+DrawChordPanel renders the song's current chord in instrumental mode.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width (the panel is centred)
+  - name: string - Chord name (e.g. "Am"), "" when the notes form no known chord
+  - notes: []string - Detected note names, strongest first
+
+Called by:
+  - App.drawPlayingMode in instrumental mode
+
+Task:
+  - Show the harmony that the single song pitch line cannot
+
+Logic:
+ 1. Draw a panel the size of the note panels in the top centre
+ 2. Draw a "CHORD" header, the name in the big font ("-" if unknown) and the
+    notes underneath
+
+Output:
+  - None (draws to screen)
+*/
+func DrawChordPanel(screen *ebiten.Image, sw int, name string, notes []string) {
+	x := sw/2 - 65
+	vector.DrawFilledRect(screen, float32(x), 15, 130, 80, ActiveTheme.HUDBackground, false)
+
+	if name == "" {
+		name = "-"
+	}
+	if smallFont != nil {
+		faint := ActiveTheme.FaintText
+		text.Draw(screen, "CHORD", smallFont, x+10, 28, faint)
+		text.Draw(screen, strings.Join(notes, " "), smallFont, x+10, 85, faint)
+	}
+	if bigFont != nil {
+		text.Draw(screen, name, bigFont, x+10, 65, ActiveTheme.DimText)
+	}
+}
+
+/*
+DrawSightReadingLabel renders the "SIGHT READ" badge while the future song line is hidden.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+
+Called by:
+  - App.drawPlayingMode while sight reading is on
+
+Task:
+  - Remind the user why the song line stops at "now" and that the run scores extra
+
+Logic:
+ 1. Draw "SIGHT READ" centred under the middle panels
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSightReadingLabel(screen *ebiten.Image, sw int) {
+	text.Draw(screen, "SIGHT READ", basicfont.Face7x13, sw/2-35, 112, ActiveTheme.Info)
+}
+
+/*
+DrawPhonemeLabel renders the vowel the user is singing under the song tempo.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - phoneme: string - audio.Phoneme ("a", "e", "i", "o", "u", "consonant"; "" for none)
+
+Called by:
+  - App.drawPlayingMode in vocal modes
+
+Task:
+  - Show whether the vowel being sung is the one intended
+
+Logic:
+ 1. Draw "VOWEL /a/" for vowels, "CONSONANT" for unpitched sound, "VOWEL -" otherwise
+
+Output:
+  - None (draws to screen)
+*/
+func DrawPhonemeLabel(screen *ebiten.Image, phoneme string) {
+	label := "VOWEL -"
+	switch phoneme {
+	case "":
+	case "consonant":
+		label = "CONSONANT"
+	default:
+		label = "VOWEL /" + phoneme + "/"
+	}
+	text.Draw(screen, label, basicfont.Face7x13, 15, 144, ActiveTheme.DimText)
+}
+
+/*
+DrawSongKey renders the detected song key right under the song note panel.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - key: string - e.g. "D Major" ("" = unknown, nothing is drawn)
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Show the key the song is in
+
+Logic:
+ 1. Draw "KEY <key>" above the song tempo
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSongKey(screen *ebiten.Image, key string) {
+	if key == "" {
+		return
+	}
+	text.Draw(screen, "KEY "+key, basicfont.Face7x13, 15, 108, ActiveTheme.DimText)
+}
+
+/*
+DrawSongBPM renders the detected song tempo under the song note panel.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - bpm: float64 - Detected tempo (0 = unknown, nothing is drawn)
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Show the tempo to practice at
+
+Logic:
+ 1. Draw "SONG <bpm> BPM" below the song panel
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSongBPM(screen *ebiten.Image, bpm float64) {
+	if bpm <= 0 {
+		return
+	}
+	text.Draw(screen, fmt.Sprintf("SONG %.0f BPM", bpm), basicfont.Face7x13, 15, 126, ActiveTheme.DimText)
+}
+
+/*
+DrawPlaybackSpeed renders the playback speed under the song tempo.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - speed: float64 - Playback speed factor (1 = original)
+
+Called by:
+  - App.drawPlayingMode while a song is playing
+
+Task:
+  - Remind the user the song is slowed down (or sped up)
+
+Logic:
+ 1. Draw "SPEED 0.50x" below the phoneme label; yellow unless the speed is 1
+
+Output:
+  - None (draws to screen)
+*/
+func DrawPlaybackSpeed(screen *ebiten.Image, speed float64) {
+	clr := ActiveTheme.DimText
+	if speed != 1 {
+		clr = ActiveTheme.Highlight
+	}
+	text.Draw(screen, fmt.Sprintf("SPEED %.2fx", speed), basicfont.Face7x13, 15, 162, clr)
+}
+
+/*
+DrawMetronome renders the click track tempo under the user note panel and its cents bar.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+  - bpm: float64 - Metronome tempo
+  - onBeat: bool - Whether a beat just started
+
+Called by:
+  - App.drawMetronome while the metronome is on
+
+Task:
+  - Show the tempo the clicks follow
+
+Logic:
+ 1. Draw a square beat light (highlight on the beat, track colour otherwise)
+ 2. Draw "<bpm> BPM" next to it
+
+Output:
+  - None (draws to screen)
+*/
+func DrawMetronome(screen *ebiten.Image, sw int, bpm float64, onBeat bool) {
+	x := float32(sw - 145)
+	light := ActiveTheme.Track
+	if onBeat {
+		light = ActiveTheme.Highlight
+	}
+	vector.DrawFilledRect(screen, x, 116, 10, 10, light, false)
+	text.Draw(screen, fmt.Sprintf("%.0f BPM", bpm), basicfont.Face7x13, int(x)+16, 126, ActiveTheme.DimText)
+}
+
+/*
+DrawReferenceTone renders the sounding reference tone under the user note panel.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+  - note: string - Tone note name (e.g. "A")
+  - octave: int - Tone octave
+  - freq: float64 - Tone frequency in Hz
+
+Called by:
+  - App.drawReferenceTone while the no-audio reference tone plays
+
+Task:
+  - Show what to tune against next to the user's pitch
+
+Logic:
+ 1. Draw "TONE <note><octave> (<freq> Hz)" below the metronome line
+
+Output:
+  - None (draws to screen)
+*/
+func DrawReferenceTone(screen *ebiten.Image, sw int, note string, octave int, freq float64) {
+	label := fmt.Sprintf("TONE %s%d (%.0f Hz)", note, octave, freq)
+	text.Draw(screen, label, basicfont.Face7x13, sw-145, 144, ActiveTheme.Info)
+}
+
+/*
+DrawVibrato renders the detected vibrato under the user note panel.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+  - rate: float64 - Vibrato rate in Hz
+  - depth: float64 - Vibrato depth in semitones
+
+Called by:
+  - App.drawPlayingMode while the mic reports vibrato
+
+Task:
+  - Give feedback on vibrato speed and width
+
+Logic:
+ 1. Draw "Vibrato: <rate> Hz ±<depth> st" below the reference tone line
+
+Output:
+  - None (draws to screen)
+*/
+func DrawVibrato(screen *ebiten.Image, sw int, rate, depth float64) {
+	label := fmt.Sprintf("Vibrato: %.1f Hz ±%.1f st", rate, depth)
+	text.Draw(screen, label, basicfont.Face7x13, sw-175, 162, ActiveTheme.Highlight)
+}
+
+/*
+DrawHitTolerance renders the song's hit tolerance on the start screen.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - tolerance: float64 - Hit distance in semitones
+  - sw: int - Screen width
+
+Called by:
+  - App.Draw when state is StateStartScreen
+
+Task:
+  - Show how strict scoring is for this song and that +/- change it
+
+Logic:
+ 1. Centre "Hit tolerance: - <tolerance> st +" under the microphone selector
+
+Output:
+  - None (draws to screen)
+*/
+func DrawHitTolerance(screen *ebiten.Image, tolerance float64, sw int) {
+	label := fmt.Sprintf("Hit tolerance: - %.1f st +", tolerance)
+	text.Draw(screen, label, basicfont.Face7x13, sw/2-len(label)*7/2, 44, ActiveTheme.Info)
+}
+
+/*
+SensitivitySliderPos places the mic sensitivity slider on the start screen.
+
+Input:
+  - sh: int - Screen height
+
+Called by:
+  - App.handleSensitivityInput, App.Draw
+
+Task:
+  - One place for the slider's position, shared by drawing and dragging
+
+Logic:
+ 1. Bottom left, above the key hint line, 200px wide
+
+Output:
+  - x, y, w: int - Left end, vertical centre and width of the track
+*/
+func SensitivitySliderPos(sh int) (x, y, w int) {
+	return 20, sh - 45, 200
+}
+
+/*
+SliderValueAt maps a horizontal position on a slider to its value.
+
+Input:
+  - mx: int - Cursor x
+  - x, w: int - Slider track left end and width
+  - lo, hi: float64 - Value range
+
+Called by:
+  - App.handleSensitivityInput while the slider is dragged
+
+Task:
+  - Turn a drag into a value
+
+Logic:
+ 1. lo + (mx-x)/w × (hi-lo), clamped to lo..hi
+
+Output:
+  - float64: Slider value
+*/
+func SliderValueAt(mx, x, w int, lo, hi float64) float64 {
+	if w <= 0 {
+		return lo
+	}
+	return max(lo, min(hi, lo+float64(mx-x)/float64(w)*(hi-lo)))
+}
+
+/*
+DrawSlider renders a horizontal slider with its label.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - x, y, w: int - Track left end, vertical centre and width
+  - value: float64 - Current value
+  - min, max: float64 - Value range
+  - label: string - Text drawn above the track (e.g. "Mic sensitivity: 1.5x")
+
+Called by:
+  - App.Draw on the start screen (mic sensitivity)
+
+Task:
+  - Show a draggable setting
+
+Logic:
+ 1. Label above the track; a 4px track from x to x+w
+ 2. Handle at SliderHandleX
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSlider(screen *ebiten.Image, x, y, w int, value, min, max float64, label string) {
+	text.Draw(screen, label, basicfont.Face7x13, x, y-12, ActiveTheme.HUDText)
+	vector.DrawFilledRect(screen, float32(x), float32(y-2), float32(w), 4, ActiveTheme.Track, false)
+	vector.DrawFilledCircle(screen, SliderHandleX(x, w, value, min, max), float32(y), 7, ActiveTheme.Info, true)
+}
+
+/*
+SliderHandleX returns where a slider's handle is drawn.
+
+Input:
+  - x, w: int - Track left end and width
+  - value: float64 - Current value
+  - lo, hi: float64 - Value range
+
+Called by:
+  - DrawSlider
+
+Task:
+  - Keep the inverse of SliderValueAt next to it
+
+Logic:
+ 1. x + (value-lo)/(hi-lo) × w, with the fraction clamped to 0..1 (x for an
+    empty range)
+
+Output:
+  - float32: Handle centre x
+*/
+func SliderHandleX(x, w int, value, lo, hi float64) float32 {
+	frac := 0.0
+	if hi > lo {
+		frac = math.Max(0, math.Min(1, (value-lo)/(hi-lo)))
+	}
+	return float32(x) + float32(frac)*float32(w)
+}
+
+/*
+DrawMicMonitor renders the mic monitoring warning under the user note panel.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sw: int - Screen width
+
+Called by:
+  - App.drawMicMonitor while monitoring is on
+
+Task:
+  - Warn that speakers feed the monitored voice back into the mic
+
+Logic:
+ 1. Draw "MONITOR ON" and "Use headphones with monitoring!" below the vibrato line
+
+Output:
+  - None (draws to screen)
+*/
+func DrawMicMonitor(screen *ebiten.Image, sw int) {
+	text.Draw(screen, "MONITOR ON", basicfont.Face7x13, sw-175, 180, ActiveTheme.Bad)
+	text.Draw(screen, "Use headphones with monitoring!", basicfont.Face7x13, sw-225, 198, ActiveTheme.Bad)
+}
+
+/*
+DrawCentsBar renders a ±50 cent tuning meter.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - cents: float64 - Deviation from the nearest semitone (-50..50)
+  - x, y, w: int - Left edge, top and width of the bar
+
+Called by:
+  - DrawNoteHUD beneath the user's note panel
+
+Task:
+  - Show how sharp or flat the user is within the semitone
+
+Logic:
+ 1. Draw a thin grey track with a center tick
+ 2. Clamp cents to ±50 and map to an X position on the track
+ 3. Needle color: green within ±10, yellow within ±30, red beyond
+
+Output:
+  - None (draws to screen)
+*/
+func DrawCentsBar(screen *ebiten.Image, cents float64, x, y, w int) {
+	fx, fy, fw := float32(x), float32(y), float32(w)
+	vector.DrawFilledRect(screen, fx, fy+3, fw, 2, ActiveTheme.Track, false)
+	vector.DrawFilledRect(screen, fx+fw/2-0.5, fy, 1, 8, ActiveTheme.DimText, false)
+
+	cents = math.Max(-50, math.Min(50, cents))
+	nx := fx + fw/2 + float32(cents/50)*fw/2
+
+	clr := ActiveTheme.Bad
+	if math.Abs(cents) <= 10 {
+		clr = ActiveTheme.Good
+	} else if math.Abs(cents) <= 30 {
+		clr = ActiveTheme.Highlight
+	}
+	vector.DrawFilledRect(screen, nx-2, fy-1, 4, 10, clr, false)
+}
+
+/*
+PitchVisualizer handles coordinate transformations and pitch graph rendering.
+
+Fields:
+  - OffsetY: Y position of lowest displayed note
+  - ScaleY: Pixels per semitone
+  - BaseMidi: MIDI note number at bottom of display
+  - OffsetX: X position of "now" line
+  - IgnoreOctave: Score hits by pitch class only (octave-agnostic)
+  - TransposeSteps: Semitones the song pitch is shifted before drawing and hit tests
+  - Ghost: Best previous run's [timeMs, pitch, ...] pairs in the song's key,
+    drawn behind the user trail by DrawUserPitch (nil to hide)
+  - PixelsPerSec: Horizontal scroll speed (config.PixelsPerSec by default, Ctrl +/- adjusts)
+  - HideFuture: Sight-reading: draw the song (and ghost) only up to the "now" line
+*/
+type PitchVisualizer struct {
+	OffsetY        float64
+	ScaleY         float64
+	BaseMidi       float64
+	OffsetX        float64
+	IgnoreOctave   bool
+	TransposeSteps int
+	Ghost          []float64
+	PixelsPerSec   float64
+	HideFuture     bool
+}
+
+/*
+NewPitchVisualizer creates a visualizer configured for given screen size.
+
+Input:
+  - sw, sh: int - Screen width and height
+
+Called by:
+  - App.drawPlayingMode multiple times per frame
+  - App.drawFreestyleMode for pitch marker
+
+Task:
+  - Calculate layout parameters for pitch visualization
+
+Logic:
+ 1. OffsetY = bottom margin (sh - 50)
+ 2. ScaleY = available height / 60 semitones
+ 3. BaseMidi = 30 (approximately F#1, low bass)
+ 4. OffsetX = 20% from left (position of "now" line)
+ 5. PixelsPerSec = config.PixelsPerSec
+
+Output:
+  - *PitchVisualizer: Configured for current screen size
+*/
+func NewPitchVisualizer(sw, sh int) *PitchVisualizer {
+	return &PitchVisualizer{
+		OffsetY:  float64(sh) - 50,
+		ScaleY:   float64(sh-100) / 60.0,
+		BaseMidi: 30.0,
+		OffsetX:  float64(sw) * 0.2,
+
+		PixelsPerSec: config.PixelsPerSec,
+	}
+}
+
+/*
+FreqToY converts frequency to Y screen coordinate.
+
+Input:
+  - f: float64 - Frequency in Hz
+
+Called by:
+  - DrawSongPitch, DrawUserPitch, DrawCurrentPitch
+
+Task:
+  - Map frequency to vertical position (higher freq = higher on screen)
+
+Logic:
+ 1. If f <= 0: return off-screen (-100)
+ 2. Convert to MIDI note and place it with MidiToY
+
+Output:
+  - float64: Y coordinate (lower = higher pitch)
+*/
+func (v *PitchVisualizer) FreqToY(f float64) float64 {
+	if f <= 0 {
+		return -100
+	}
+	return v.MidiToY(theory.FreqToMidi(f))
+}
+
+/*
+MidiToY converts a (fractional) MIDI note to a Y screen coordinate.
+
+Input:
+  - m: float64 - MIDI note number (69 = A4)
+
+Called by:
+  - FreqToY, DrawSemitoneGrid
+
+Task:
+  - The pitch axis mapping, shared by pitches and grid lines
+
+Logic:
+ 1. Y = OffsetY - (m - BaseMidi) * ScaleY
+
+Output:
+  - float64: Y coordinate (lower = higher pitch)
+*/
+func (v *PitchVisualizer) MidiToY(m float64) float64 {
+	return v.OffsetY - (m-v.BaseMidi)*v.ScaleY
+}
+
+/*
+DrawBreathMarkers marks the singer's breaths along the bottom of the pitch graph.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - vis: *PitchVisualizer - Graph the trail is drawn on
+  - breaths: []float64 - Breath times in ms (audio.BreathDetector)
+  - currTime: float64 - Current playback time in seconds
+
+Called by:
+  - App.drawPlayingMode in the pitch line view
+
+Task:
+  - Show where breaths fell in the phrasing
+
+Logic:
+ 1. Place each breath like the pitch trail (latency compensated, scrolling
+    with PixelsPerSec from the "now" line); skip those off screen
+ 2. Fill a small upward triangle with its tip 12px above the graph bottom
+
+Output:
+  - None (draws to screen)
+*/
+func DrawBreathMarkers(screen *ebiten.Image, vis *PitchVisualizer, breaths []float64, currTime float64) {
+	latencyOffset := config.GetAudioLatencyMs() / 1000.0
+	w := float64(screen.Bounds().Dx())
+	op := &vector.DrawPathOptions{AntiAlias: true}
+	op.ColorScale.ScaleWithColor(ActiveTheme.Info)
+	for _, ms := range breaths {
+		x := (ms/1000.0-latencyOffset-currTime)*vis.PixelsPerSec + vis.OffsetX
+		if x < -10 || x > w+10 {
+			continue
+		}
+		var path vector.Path
+		path.MoveTo(float32(x), float32(vis.OffsetY-12))
+		path.LineTo(float32(x+5), float32(vis.OffsetY))
+		path.LineTo(float32(x-5), float32(vis.OffsetY))
+		path.Close()
+		vector.FillPath(screen, &path, nil, op)
+	}
+}
+
+/*
+TimeAtX converts an X screen coordinate back to song time.
+
+Input:
+  - x: float64 - X coordinate on the pitch graph
+  - currTime: float64 - Song time at the "now" line, in seconds
+
+Called by:
+  - App.graphClickTime to seek where the pitch graph was clicked
+
+Task:
+  - Invert the scroll mapping the pitch lines are drawn with
+
+Logic:
+ 1. currTime + (x - OffsetX) / PixelsPerSec
+
+Output:
+  - float64: Song time in seconds (may be outside the song)
+*/
+func (v *PitchVisualizer) TimeAtX(x, currTime float64) float64 {
+	return currTime + (x-v.OffsetX)/v.PixelsPerSec
+}
+
+// Kinds of semitone grid line, faintest first.
+const (
+	gridQuarterTone = iota
+	gridSemitone
+	gridOctave
+)
+
+/*
+gridLine is one horizontal line of the semitone grid.
+
+Fields:
+  - Y: Screen Y of the line
+  - Kind: gridQuarterTone, gridSemitone or gridOctave
+  - Label: Note name at octave lines (e.g. "C4"), empty otherwise
+*/
+type gridLine struct {
+	Y     float64
+	Kind  int
+	Label string
+}
+
+/*
+semitoneGridLines lists the semitone grid lines visible on the pitch graph.
+
+Input:
+  - vis: *PitchVisualizer - Axis mapping of the graph
+  - sh: int - Screen height
+
+Called by:
+  - DrawSemitoneGrid
+
+Task:
+  - Place the grid on the graph's note axis
+
+Logic:
+ 1. Visible MIDI range is the notes between Y = sh and Y = 0
+ 2. For each note: a quarter-tone line half a semitone above it, then the
+    note's own line, an octave line labelled with the octave at every C
+
+Output:
+  - []gridLine: Lines from the lowest note up
+*/
+func semitoneGridLines(vis *PitchVisualizer, sh int) []gridLine {
+	lo := int(math.Floor(vis.BaseMidi - (float64(sh)-vis.OffsetY)/vis.ScaleY))
+	hi := int(math.Ceil(vis.BaseMidi + vis.OffsetY/vis.ScaleY))
+
+	var lines []gridLine
+	for m := lo; m <= hi; m++ {
+		lines = append(lines, gridLine{Y: vis.MidiToY(float64(m) + 0.5), Kind: gridQuarterTone})
+		if m%12 != 0 {
+			lines = append(lines, gridLine{Y: vis.MidiToY(float64(m)), Kind: gridSemitone})
+			continue
+		}
+		lines = append(lines, gridLine{Y: vis.MidiToY(float64(m)), Kind: gridOctave, Label: fmt.Sprintf("C%d", m/12-1)})
+	}
+	return lines
+}
+
+/*
+DrawSemitoneGrid draws reference lines at every semitone of the pitch graph.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - vis: *PitchVisualizer - Axis mapping of the graph
+  - sh: int - Screen height
+
+Called by:
+  - App.drawPlayingMode before the pitch lines, while the grid is shown
+
+Task:
+  - Show where exact notes are, so the user can see how far off they are
+
+Logic:
+ 1. Lay out the lines with semitoneGridLines
+ 2. Very faint lines at quarter tones (half-way between semitones)
+ 3. Faint lines at every semitone
+ 4. Brighter lines at every C (octave boundary), labelled e.g. "C4"
+    (shades of ActiveTheme.GridLine, a premultiplied colour at low alpha, so
+    they stay behind the trails)
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSemitoneGrid(screen *ebiten.Image, vis *PitchVisualizer, sh int) {
+	sw := float32(screen.Bounds().Dx())
+	semitone := ActiveTheme.GridLine
+	colors := [...]color.RGBA{
+		gridQuarterTone: scaleColor(semitone, 0.45),
+		gridSemitone:    semitone,
+		gridOctave:      scaleColor(semitone, 2.5),
+	}
+
+	for _, l := range semitoneGridLines(vis, sh) {
+		vector.DrawFilledRect(screen, 0, float32(l.Y), sw, 1, colors[l.Kind], false)
+		if l.Label != "" {
+			text.Draw(screen, l.Label, basicfont.Face7x13, 4, int(l.Y)-3, ActiveTheme.FaintText)
+		}
+	}
+}
+
+/*
+DrawSongPitch renders the song's pitch contours, one line per vocal part.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - parts: [][]float64 - Pitch values at 10ms intervals, one slice per part
+  - colors: []color.Color - Line colour of each part (missing entries use
+    ActiveTheme.SongPitch; see SongPartColors)
+  - currTime: float64 - Current playback time in seconds
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.drawPlayingMode, DrawSessionCompare
+
+Task:
+  - Draw song pitch within visible time window (-3s to +5s from now)
+
+Logic:
+ 1. For each part, calculate visible index range from currTime ± window
+    (HideFuture: stop at currTime, nothing right of the "now" line)
+ 2. For each pitch sample in range:
+    a. Skip if pitch <= 5 (silence), break line continuity
+    b. Calculate X from time offset, Y from FreqToY
+    c. Draw line segment to previous point, or 3x3 rect if first point
+ 3. Track previous point for line continuity
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawSongPitch(screen *ebiten.Image, parts [][]float64, colors []color.Color, currTime float64, sw, sh int) {
+	for i, data := range parts {
+		var col color.Color = ActiveTheme.SongPitch
+		if i < len(colors) {
+			col = colors[i]
+		}
+		v.drawSongLine(screen, data, col, currTime, sh)
+	}
+}
+
+/*
+SongPartColors returns the line colours for a song with several vocal parts.
+
+Input:
+  - n: int - Number of parts
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Keep the parts apart while the first one keeps the theme colour
+
+Logic:
+ 1. Part 1 is ActiveTheme.SongPitch, the others cycle through pink, teal and violet
+
+Output:
+  - []color.Color: n colours for DrawSongPitch
+*/
+func SongPartColors(n int) []color.Color {
+	extra := []color.Color{
+		color.RGBA{255, 120, 180, 255},
+		color.RGBA{60, 200, 170, 255},
+		color.RGBA{170, 120, 255, 255},
+	}
+	colors := make([]color.Color, n)
+	for i := range colors {
+		if i == 0 {
+			colors[i] = ActiveTheme.SongPitch
+		} else {
+			colors[i] = extra[(i-1)%len(extra)]
+		}
+	}
+	return colors
+}
+
+/*
+songPoint is one visible sample of a pitch line (song contour or intonation graph).
+
+Fields:
+  - X, Y: Screen position
+  - Start: First point of a stroke (after silence or an off-screen sample)
+*/
+type songPoint struct {
+	X, Y  float64
+	Start bool
+}
+
+/*
+drawSongLine renders one song pitch contour.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - data: []float64 - Pitch values at 10ms intervals
+  - col: color.Color - Line colour
+  - currTime: float64 - Current playback time in seconds
+  - sh: int - Screen height
+
+Called by:
+  - DrawSongPitch for each part
+
+Task:
+  - Draw one part as described in DrawSongPitch
+
+Logic:
+ 1. songLinePoints
+ 2. Draw a 3x3 rect at each stroke start, else a line from the previous point
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) drawSongLine(screen *ebiten.Image, data []float64, col color.Color, currTime float64, sh int) {
+	var prev songPoint
+	for _, p := range v.songLinePoints(data, currTime, sh) {
+		if p.Start {
+			ebitenutil.DrawRect(screen, p.X, p.Y, 3, 3, col)
+		} else {
+			ebitenutil.DrawLine(screen, prev.X, prev.Y, p.X, p.Y, col)
+		}
+		prev = p
+	}
+}
+
+/*
+songLinePoints lays out the visible samples of one song pitch contour.
+
+Input:
+  - data: []float64 - Pitch values at 10ms intervals
+  - currTime: float64 - Current playback time in seconds
+  - sh: int - Screen height
+
+Called by:
+  - drawSongLine
+
+Task:
+  - Keep the layout of DrawSongPitch apart from the drawing
+
+Logic:
+ 1. See DrawSongPitch, steps 1-3
+
+Output:
+  - []songPoint: Points to draw, in time order
+*/
+func (v *PitchVisualizer) songLinePoints(data []float64, currTime float64, sh int) []songPoint {
+	stepSec := 0.01
+
+	var points []songPoint
+	first := true
+
+	startIdx := int((currTime - 3.0) / stepSec)
+	if startIdx < 0 {
+		startIdx = 0
+	}
+	endIdx := int((currTime + 5.0) / stepSec)
+	if v.HideFuture {
+		endIdx = int(currTime / stepSec)
+	}
+	if endIdx >= len(data) {
+		endIdx = len(data) - 1
+	}
+
+	for i := startIdx; i <= endIdx; i++ {
+		p := theory.Transpose(data[i], v.TransposeSteps)
+		if p <= 5 {
+			first = true
+			continue
+		}
+
+		t := float64(i) * stepSec
+		x := (t-currTime)*v.PixelsPerSec + v.OffsetX
+		y := v.FreqToY(p)
+
+		if y < 0 || y > float64(sh) {
+			first = true
+			continue
+		}
+
+		points = append(points, songPoint{X: x, Y: y, Start: first})
+		first = false
+	}
+	return points
+}
+
+/*
+NoteBlock is one quantized song note for the piano-roll view.
+
+Fields:
+  - Start, End: Note boundaries in seconds
+  - Midi: MIDI note number
+*/
+type NoteBlock struct {
+	Start float64
+	End   float64
+	Midi  int
+}
+
+/*
+DrawSongPitchBlocks renders song notes as filled horizontal bars (piano roll).
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - blocks: []NoteBlock - Notes to draw
+  - currTime: float64 - Current playback time in seconds
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.drawPlayingMode when the block view is enabled
+
+Task:
+  - Beginner-friendly alternative to the thin pitch line
+
+Logic:
+ 1. For each block, map Start/End to X like DrawSongPitch
+    (HideFuture: skip blocks that have not started, cut the rest at the "now" line)
+ 2. Center the bar on the note's Y with one semitone of height
+ 3. Skip blocks fully off-screen
+ 4. Fill with translucent blue and draw a brighter top edge
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawSongPitchBlocks(screen *ebiten.Image, blocks []NoteBlock, currTime float64, sw, sh int) {
+	edge := ActiveTheme.SongPitch
+	fill := scaleColor(edge, 0.63)
+
+	for _, b := range blocks {
+		x1 := (b.Start-currTime)*v.PixelsPerSec + v.OffsetX
+		x2 := (b.End-currTime)*v.PixelsPerSec + v.OffsetX
+		if v.HideFuture {
+			x2 = math.Min(x2, v.OffsetX)
+		}
+		if x2 < 0 || x1 > float64(sw) || x1 >= x2 {
+			continue
+		}
+
+		y := v.OffsetY - (float64(b.Midi+v.TransposeSteps)-v.BaseMidi)*v.ScaleY
+		h := math.Max(v.ScaleY, 4)
+		if y < 0 || y > float64(sh) {
+			continue
+		}
+
+		vector.DrawFilledRect(screen, float32(x1), float32(y-h/2), float32(x2-x1), float32(h), fill, false)
+		vector.DrawFilledRect(screen, float32(x1), float32(y-h/2), float32(x2-x1), 1, edge, false)
+	}
+}
+
+/*
+DrawUserPitch renders the user's recorded pitch trail with hit detection.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - userPitch: []float64 - Pairs of [timeMs, pitch, timeMs, pitch, ...]
+  - songParts: [][]float64 - Song pitch data for hit comparison, one slice per vocal part
+  - currTime: float64 - Current playback time in seconds
+  - sw, sh: int - Screen dimensions
+  - tolerance: float64 - Hit distance in semitones (the song's hit tolerance)
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Draw user pitch trail, colored by accuracy (green=hit, yellow=miss)
+
+Logic:
+ 1. Draw the Ghost trail first, if set (see drawGhostTrail)
+ 2. Apply latency compensation to time values
+ 3. Iterate userPitch in pairs (time, pitch)
+ 4. Skip silence (pitch <= 10)
+ 5. Calculate X from time, Y from FreqToY
+ 6. Skip if off-screen left (<-50), break if off-screen right
+ 7. Compare pitch to song pitch at same time:
+    - Green if within tolerance semitones of any part (pitch class only when IgnoreOctave)
+    - Yellow otherwise
+ 8. Draw line to previous point
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawUserPitch(screen *ebiten.Image, userPitch []float64, songParts [][]float64, currTime float64, sw, sh int, tolerance float64) {
+	if v.Ghost != nil {
+		v.drawGhostTrail(screen, currTime, sw)
+	}
+	v.drawPitchTrail(screen, userPitch, songParts, currTime, sw, tolerance, ActiveTheme.UserPitchHit, ActiveTheme.UserPitchMiss)
+}
+
+/*
+DrawSecondUserPitch renders the duet partner's pitch trail in magenta.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - userPitch: []float64 - Second singer's [timeMs, pitch, ...] pairs
+  - songParts: [][]float64 - Song pitch data for hit comparison, one slice per vocal part
+  - currTime: float64 - Current playback time in seconds
+  - sw, sh: int - Screen dimensions
+  - tolerance: float64 - Hit distance in semitones, as in DrawUserPitch
+
+Called by:
+  - App.drawPlayingMode in duet mode
+
+Task:
+  - Draw the second trail so it stays distinguishable from the first
+
+Logic:
+ 1. Same as DrawUserPitch, with light magenta for hits and dark magenta for misses
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawSecondUserPitch(screen *ebiten.Image, userPitch []float64, songParts [][]float64, currTime float64, sw, sh int, tolerance float64) {
+	v.drawPitchTrail(screen, userPitch, songParts, currTime, sw, tolerance, color.RGBA{255, 120, 255, 255}, color.RGBA{170, 40, 170, 255})
+}
+
+/*
+drawPitchTrail draws a recorded pitch trail in hit/miss colours.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - userPitch: []float64 - Pairs of [timeMs, pitch, ...]
+  - songParts: [][]float64 - Song pitch data for hit comparison (one slice per part)
+  - currTime: float64 - Current playback time in seconds
+  - sw: int - Screen width
+  - tolerance: float64 - Hit distance in semitones
+  - hitCol, missCol: color.RGBA - Segment colours on and off the song note
+
+Called by:
+  - DrawUserPitch, DrawSecondUserPitch, DrawSessionCompare
+
+Task:
+  - Share trail drawing between the solo and duet singers
+
+Logic:
+ 1. See DrawUserPitch
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) drawPitchTrail(screen *ebiten.Image, userPitch []float64, songParts [][]float64, currTime float64, sw int, tolerance float64, hitCol, missCol color.RGBA) {
+	var prevX, prevY float64
+	first := true
+
+	latencyOffset := config.GetAudioLatencyMs() / 1000.0
+
+	for i := 0; i < len(userPitch); i += 2 {
+		rawT := userPitch[i] / 1000.0
+		t := rawT - latencyOffset
+		p := userPitch[i+1]
+
+		if p <= 10 {
+			first = true
+			continue
+		}
+
+		x := (t-currTime)*v.PixelsPerSec + v.OffsetX
+		y := v.FreqToY(p)
+
+		if x < -50 {
+			continue
+		}
+		if x > float64(sw) {
+			break
+		}
+
+		col := missCol
+
+		sIdx := int(t * 100)
+		for _, songPitch := range songParts {
+			if sIdx >= 0 && sIdx < len(songPitch) {
+				ref := theory.Transpose(songPitch[sIdx], v.TransposeSteps)
+				if ref > 10 && theory.SemitoneDistance(p, ref, v.IgnoreOctave) < tolerance {
+					col = hitCol
+				}
+			}
+		}
+
+		if !first {
+			ebitenutil.DrawLine(screen, prevX, prevY, x, y, col)
+		}
+		prevX, prevY = x, y
+		first = false
+	}
+}
+
+/*
+drawGhostTrail draws the best previous run as a faint magenta line.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - currTime: float64 - Current playback time in seconds
+  - sw: int - Screen width
+
+Called by:
+  - DrawUserPitch
+
+Task:
+  - Show how the best run sang this part, without competing with the live trail
+
+Logic:
+ 1. Same timing as drawPitchTrail: the recorded time minus the current latency
+ 2. Shift the pitch by TransposeSteps (the ghost is stored in the song's key)
+ 3. Skip silence and off-screen points, breaking the line there
+    (the trail is unpruned and may jump back in time after seeks and loops;
+    with HideFuture everything right of the "now" line counts as off-screen)
+ 4. Draw every segment in magenta at alpha 100 (premultiplied)
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) drawGhostTrail(screen *ebiten.Image, currTime float64, sw int) {
+	col := color.RGBA{100, 0, 100, 100}
+	latencyOffset := config.GetAudioLatencyMs() / 1000.0
+	right := float64(sw)
+	if v.HideFuture {
+		right = v.OffsetX
+	}
+
+	var prevX, prevY float64
+	first := true
+	for i := 0; i+1 < len(v.Ghost); i += 2 {
+		p := v.Ghost[i+1]
+		x := (v.Ghost[i]/1000.0-latencyOffset-currTime)*v.PixelsPerSec + v.OffsetX
+		if p <= 10 || x < -50 || x > right {
+			first = true
+			continue
+		}
+
+		y := v.FreqToY(theory.Transpose(p, v.TransposeSteps))
+		if !first && x >= prevX {
+			ebitenutil.DrawLine(screen, prevX, prevY, x, y, col)
+		}
+		prevX, prevY = x, y
+		first = false
+	}
+}
+
+/*
+DrawFormantTrack renders the singer's first formant as a dashed magenta line.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - formant: []float64 - Pairs of [timeMs, F1 in Hz, ...] (0 = unvoiced)
+  - currTime: float64 - Current playback time in seconds
+  - sw: int - Screen width
+
+Called by:
+  - App.drawPlayingMode in the pitch line view
+
+Task:
+  - Show vowel openness (F1 rises as the mouth opens) next to the pitch trail
+
+Logic:
+ 1. Same timing as drawPitchTrail: the recorded time minus the current latency
+ 2. Plot F1 on the pitch axis with FreqToY (not transposed, it is not a note)
+ 3. Join consecutive voiced points with 4px dashes (dashSegments), carrying
+    the dash phase from one short segment to the next so the curve stays
+    dashed; a gap restarts the pattern
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawFormantTrack(screen *ebiten.Image, formant []float64, currTime float64, sw int) {
+	col := color.RGBA{255, 60, 255, 255}
+	latencyOffset := config.GetAudioLatencyMs() / 1000.0
+
+	var prevX, prevY, phase float64
+	first := true
+	for i := 0; i+1 < len(formant); i += 2 {
+		f := formant[i+1]
+		x := (formant[i]/1000.0-latencyOffset-currTime)*v.PixelsPerSec + v.OffsetX
+		if f <= 0 || x < -50 {
+			first, phase = true, 0
+			continue
+		}
+		if x > float64(sw) {
+			break
+		}
+
+		y := v.FreqToY(f)
+		if !first {
+			var dashes [][4]float64
+			dashes, phase = dashSegments(prevX, prevY, x, y, 4, phase)
+			for _, d := range dashes {
+				ebitenutil.DrawLine(screen, d[0], d[1], d[2], d[3], col)
+			}
+		}
+		prevX, prevY = x, y
+		first = false
+	}
+}
+
+/*
+DrawDashedLine draws a 1px line as alternating dashes and gaps.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - x1, y1, x2, y2: float64 - End points
+  - segLen: float64 - Length of each dash and each gap in pixels
+  - col: color.Color - Dash colour
+
+Called by:
+  - PitchVisualizer.DrawFormantTrack
+
+Task:
+  - Tell an overlay line apart from the solid pitch lines
+
+Logic:
+ 1. Split the line with dashSegments, starting with a dash at (x1, y1)
+ 2. Draw each dash as a 1px line
+
+Output:
+  - None (draws to screen)
+*/
+func DrawDashedLine(screen *ebiten.Image, x1, y1, x2, y2 float64, segLen float64, col color.Color) {
+	dashes, _ := dashSegments(x1, y1, x2, y2, segLen, 0)
+	for _, d := range dashes {
+		ebitenutil.DrawLine(screen, d[0], d[1], d[2], d[3], col)
+	}
+}
+
+/*
+dashSegments splits a line into the dashes of a dash/gap pattern.
+
+Input:
+  - x1, y1, x2, y2: float64 - End points
+  - segLen: float64 - Length of each dash and each gap in pixels
+  - phase: float64 - How far into the 2*segLen pattern the line starts
+    (0 = at the start of a dash)
+
+Called by:
+  - DrawDashedLine, PitchVisualizer.DrawFormantTrack
+
+Task:
+  - Keep a polyline of short segments dashed by continuing the pattern
+    where the previous segment stopped
+
+Logic:
+ 1. Walk the line: inside the first segLen of the pattern emit a dash up to
+    the end of the dash (or of the line), otherwise skip to the next dash
+ 2. Wrap the phase at 2*segLen
+ 3. A zero-length line or segLen <= 0 is one solid dash and keeps the phase
+
+Output:
+  - [][4]float64: Dashes as {x1, y1, x2, y2}
+  - float64: Phase at (x2, y2), to pass to the next segment
+*/
+func dashSegments(x1, y1, x2, y2 float64, segLen float64, phase float64) ([][4]float64, float64) {
+	length := math.Hypot(x2-x1, y2-y1)
+	if length == 0 || segLen <= 0 {
+		return [][4]float64{{x1, y1, x2, y2}}, phase
+	}
+	period := 2 * segLen
+	phase = math.Mod(phase, period)
+	dx, dy := (x2-x1)/length, (y2-y1)/length
+
+	var dashes [][4]float64
+	for d := 0.0; d < length; {
+		var end float64
+		if phase < segLen {
+			end = math.Min(d+segLen-phase, length)
+			dashes = append(dashes, [4]float64{x1 + dx*d, y1 + dy*d, x1 + dx*end, y1 + dy*end})
+		} else {
+			end = math.Min(d+period-phase, length)
+		}
+		phase += end - d
+		if phase >= period {
+			phase -= period
+		}
+		d = end
+	}
+	return dashes, phase
+}
+
+/*
+DrawPitchJournal draws a pitch trail scaled to a time window.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - trail: []float64 - Pairs [timeMs, pitch, ...]
+  - fromMs, toMs: float64 - Time window shown
+  - x0, x1: float64 - Screen X of fromMs and toMs
+
+Called by:
+  - App.drawFreestyleMode for the scrolling live view
+  - drawJournalPanel for the whole-session overview
+
+Task:
+  - Freestyle has no song to scroll against, so time is fitted to the window
+    instead of PixelsPerSec
+
+Logic:
+ 1. Map time linearly from fromMs..toMs to x0..x1, pitch with FreqToY
+ 2. Skip silence and points outside the window, breaking the line there
+ 3. Draw segments in light blue
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawPitchJournal(screen *ebiten.Image, trail []float64, fromMs, toMs, x0, x1 float64) {
+	if toMs <= fromMs {
+		return
+	}
+	col := ActiveTheme.Info
+	scale := (x1 - x0) / (toMs - fromMs)
+
+	var prevX, prevY float64
+	first := true
+	for i := 0; i+1 < len(trail); i += 2 {
+		t, p := trail[i], trail[i+1]
+		if p <= 10 || t < fromMs || t > toMs {
+			first = true
+			continue
+		}
+
+		x := x0 + (t-fromMs)*scale
+		y := v.FreqToY(p)
+		if !first {
+			ebitenutil.DrawLine(screen, prevX, prevY, x, y, col)
+		}
+		prevX, prevY = x, y
+		first = false
+	}
+}
+
+/*
+DrawSessionCompare renders two saved runs over the song for A/B comparison.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sessionA, sessionB: []float64 - Recorded pairs [timeMs, pitch, ...] of each run
+  - songPitch: []float64 - Song pitch at 10ms intervals (may be empty)
+  - currTime: float64 - Shared comparison clock in seconds
+  - sw, sh: int - Screen dimensions
+  - tolerance: float64 - The song's hit distance in semitones (settings.json)
+
+Called by:
+  - App.drawSessionCompare when state is StateSessionCompare
+
+Task:
+  - Show how two attempts at the same song differ, without audio
+
+Logic:
+ 1. Fill with the theme background; song pitch in the theme colour (DrawSongPitch)
+ 2. Session A in ActiveTheme.Highlight, session B in ActiveTheme.Info, both timed like the live trail
+    (drawPitchTrail against the song with its tolerance, as the runs were
+    scored: hits in full colour, misses at half strength)
+ 3. Draw the "now" line
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSessionCompare(screen *ebiten.Image, sessionA, sessionB []float64, songPitch []float64, currTime float64, sw, sh int, tolerance float64) {
+	screen.Fill(ActiveTheme.Background)
+
+	v := NewPitchVisualizer(sw, sh)
+	v.DrawSongPitch(screen, [][]float64{songPitch}, nil, currTime, sw, sh)
+	colA, colB := ActiveTheme.Highlight, ActiveTheme.Info
+	song := [][]float64{songPitch}
+	v.drawPitchTrail(screen, sessionA, song, currTime, sw, tolerance, colA, scaleColor(colA, 0.5))
+	v.drawPitchTrail(screen, sessionB, song, currTime, sw, tolerance, colB, scaleColor(colB, 0.5))
+	v.DrawNowLine(screen, sh, false)
+}
+
+/*
+DrawCurrentPitch renders a square marker at the current pitch position.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - pitch: float64 - Current pitch in Hz
+
+Called by:
+  - App.drawPlayingMode, App.drawFreestyleMode
+
+Task:
+  - Show real-time pitch indicator
+
+Logic:
+ 1. If pitch > 10: draw a 10x10 rectangle in the HUD text colour centered on (OffsetX, FreqToY(pitch))
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawCurrentPitch(screen *ebiten.Image, pitch float64) {
+	if pitch > 10 {
+		y := v.FreqToY(pitch)
+		ebitenutil.DrawRect(screen, v.OffsetX-5, y-5, 10, 10, ActiveTheme.HUDText)
+	}
+}
+
+/*
+DrawLockIndicator renders the tuning-lock meter next to the current pitch marker.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - pitch: float64 - Current pitch in Hz (indicator hidden if <= 10)
+  - charge: float64 - Lock charge in [0, 1]; 1 = locked
+
+Called by:
+  - App.drawPlayingMode each frame
+
+Task:
+  - Reward holding a note steadily in tune
+
+Logic:
+ 1. Draw a small vertical track right of the marker
+ 2. Fill it from the bottom proportionally to charge (yellow -> green)
+ 3. When locked, flash a "LOCK" label at ~4 Hz
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawLockIndicator(screen *ebiten.Image, pitch, charge float64) {
+	if pitch <= 10 {
+		return
+	}
+
+	y := float32(v.FreqToY(pitch))
+	x := float32(v.OffsetX) + 12
+	const trackH = 24
+
+	vector.DrawFilledRect(screen, x, y-trackH/2, 5, trackH, ActiveTheme.Track, false)
+
+	fill := float32(math.Max(0, math.Min(1, charge))) * trackH
+	clr := ActiveTheme.UserPitchMiss
+	if charge >= 1 {
+		clr = ActiveTheme.UserPitchHit
+	}
+	vector.DrawFilledRect(screen, x, y+trackH/2-fill, 5, fill, clr, false)
+
+	if charge >= 1 && time.Now().UnixMilli()/125%2 == 0 {
+		text.Draw(screen, "LOCK", basicfont.Face7x13, int(x)+9, int(y)+4, clr)
+	}
+}
+
+/*
+DrawLoopMarkers draws the practice loop boundaries on the pitch graph.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - startSec, endSec: float64 - Loop boundaries in seconds
+  - currTime: float64 - Current playback time in seconds
+  - sh: int - Screen height
+
+Called by:
+  - App.drawPlayingMode when a loop is set
+
+Task:
+  - Show where the loop starts (green) and ends (red)
+
+Logic:
+ 1. Map each boundary to X like the song pitch line
+ 2. Draw a vertical line for each
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawLoopMarkers(screen *ebiten.Image, startSec, endSec, currTime float64, sh int) {
+	sx := (startSec-currTime)*v.PixelsPerSec + v.OffsetX
+	ex := (endSec-currTime)*v.PixelsPerSec + v.OffsetX
+	vector.StrokeLine(screen, float32(sx), 0, float32(sx), float32(sh), 2, ActiveTheme.Good, false)
+	vector.StrokeLine(screen, float32(ex), 0, float32(ex), float32(sh), 2, ActiveTheme.Bad, false)
+}
+
+/*
+WaveformBarRect returns the bounds of the song overview bar.
+
+Input:
+  - sw, sh: int - Screen width and height
+
+Called by:
+  - DrawWaveformBar, App.handlePlayingInput for click-to-seek
+
+Task:
+  - One place for the bar geometry shared by drawing and hit testing
+
+Logic:
+ 1. Full width, 20px tall, just above the control hints
+
+Output:
+  - x, y, w, h: int - Bar rectangle
+*/
+func WaveformBarRect(sw, sh int) (x, y, w, h int) {
+	return 0, sh - 45, sw, 20
+}
+
+/*
+DrawWaveformBar renders the whole-song energy overview with a position cursor.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - bins: []float64 - Normalized energy per time slice (0..1)
+  - frac: float64 - Current position as a fraction of the song (0..1)
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Show where in the full song the user is
+
+Logic:
+ 1. Dark background over WaveformBarRect
+ 2. One column per pixel, sampled from bins; brightness = energy
+ 3. Cursor in the HUD text colour at frac of the width
+
+Output:
+  - None (draws to screen)
+*/
+func DrawWaveformBar(screen *ebiten.Image, bins []float64, frac float64, sw, sh int) {
+	x, y, w, h := WaveformBarRect(sw, sh)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), ActiveTheme.HUDBackground, false)
+
+	if len(bins) > 0 {
+		for px := 0; px < w; px++ {
+			e := bins[px*len(bins)/w]
+			if e <= 0 {
+				continue
+			}
+			c := uint8(40 + 200*e)
+			barH := float32(h) * float32(0.3+0.7*e)
+			vector.DrawFilledRect(screen, float32(x+px), float32(y)+(float32(h)-barH)/2, 1, barH, color.RGBA{c / 2, c / 2, c, 255}, false)
+		}
+	}
+
+	cx := float32(x) + float32(math.Max(0, math.Min(1, frac)))*float32(w)
+	vector.DrawFilledRect(screen, cx-1, float32(y)-2, 2, float32(h)+4, ActiveTheme.HUDText, false)
+}
+
+/*
+DrawDifficultyHeatBar colours the song by how hard each second is to sing.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - difficulty: []float64 - Difficulty per second (0..1, audio.ComputeSegmentDifficulty)
+  - currTime: float64 - Current playback time in seconds
+  - totalDuration: float64 - Song length in seconds
+  - x, y, w, h: int - Bar rectangle (just above the waveform bar)
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Point the user at the hardest sections of the song
+
+Logic:
+ 1. Nothing without difficulty data or a song length
+ 2. One column per pixel, sampled from difficulty; green (easy) blends to red (hard)
+ 3. Tick in the HUD text colour at currTime / totalDuration of the width
+
+Output:
+  - None (draws to screen)
+*/
+func DrawDifficultyHeatBar(screen *ebiten.Image, difficulty []float64, currTime, totalDuration float64, x, y, w, h int) {
+	if len(difficulty) == 0 || totalDuration <= 0 || w <= 0 {
+		return
+	}
+	for px := 0; px < w; px++ {
+		d := math.Max(0, math.Min(1, difficulty[px*len(difficulty)/w]))
+		vector.DrawFilledRect(screen, float32(x+px), float32(y), 1, float32(h), color.RGBA{uint8(255 * d), uint8(255 * (1 - d)), 0, 255}, false)
+	}
+	cx := float32(x) + float32(math.Max(0, math.Min(1, currTime/totalDuration)))*float32(w)
+	vector.DrawFilledRect(screen, cx-1, float32(y), 2, float32(h), ActiveTheme.HUDText, false)
+}
+
+/*
+DrawNowLine draws the vertical timeline indicator.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sh: int - Screen height
+  - pulse: bool - Playback is on a beat of the song's tempo
+
+Called by:
+  - App.drawPlayingMode, App.drawFreestyleMode, DrawSessionCompare
+
+Task:
+  - Draw vertical gray line at "now" position, pulsing with the beat
+
+Logic:
+ 1. Draw vertical line from (OffsetX, 0) to (OffsetX, sh)
+ 2. On a beat: 3px wide and a brighter copy of the theme colour instead
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawNowLine(screen *ebiten.Image, sh int, pulse bool) {
+	if pulse {
+		vector.StrokeLine(screen, float32(v.OffsetX), 0, float32(v.OffsetX), float32(sh), 3, scaleColor(ActiveTheme.NowLine, 1.8), false)
+		return
+	}
+	ebitenutil.DrawLine(screen, v.OffsetX, 0, v.OffsetX, float64(sh), ActiveTheme.NowLine)
+}
+
+/*
+DrawLyricLine renders the current lyric line above the waveform bar.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - line: string - Lyric text
+  - sw, sh: int - Screen width and height
+
+Called by:
+  - App.drawPlayingMode when the song has lyrics.lrc
+
+Task:
+  - Show the words to sing without covering the pitch graph's centre
+
+Logic:
+ 1. Use bigFont, or smallFont when the line would not fit the screen width
+ 2. Centre it horizontally with its baseline just above the waveform bar,
+    over a dark drop shadow so it stays readable on the graph
+
+Output:
+  - None (draws to screen)
+*/
+func DrawLyricLine(screen *ebiten.Image, line string, sw, sh int) {
+	face := bigFont
+	if face == nil || text.BoundString(face, line).Dx() > sw-20 {
+		face = smallFont
+	}
+	if face == nil {
+		return
+	}
+	b := text.BoundString(face, line)
+	x, y := sw/2-b.Dx()/2, sh-55
+	text.Draw(screen, line, face, x+2, y+2, ActiveTheme.Background)
+	text.Draw(screen, line, face, x, y, ActiveTheme.HUDText)
+}
+
+/*
+DrawControls renders keyboard shortcut hints at bottom of screen.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - sh: int - Screen height
+  - scrollSpeed: float64 - Current scroll speed in pixels per second
+
+Called by:
+  - App.drawPlayingMode
+
+Task:
+  - Show available controls to user
+
+Logic:
+ 1. Build the hints from config.Keys with controlsHint (so a remap in
+    keybindings.json shows up)
+ 2. Draw text at (10, sh-20), ending with the scroll speed
+
+Output:
+  - None (draws to screen)
+*/
+func DrawControls(screen *ebiten.Image, sh int, scrollSpeed float64) {
+	hint := controlsHint(config.Keys)
+	DrawTextAt(screen, fmt.Sprintf("%s  Ctrl+/-:Speed %.0fpx/s", hint, scrollSpeed), 10, sh-20, ActiveTheme.HUDText)
+}
+
+/*
+controlsHint lists the playback shortcuts of a key map.
+
+Input:
+  - k: config.Keybindings - Active key map
+
+Called by:
+  - DrawControls
+
+Task:
+  - Name the keys the user actually has to press
+
+Logic:
+ 1. "<key>:<action>" for each remappable action, in the original order,
+    with keyLabel names; seek and transpose pairs as "<back>/<forward>"
+ 2. Ctrl+↑↓ (scroll speed) is fixed and listed as is
+
+Output:
+  - string: e.g. "SPACE:Pause  ←/→:±10s  +/-:Key  ...  ESC:Exit"
+*/
+func controlsHint(k config.Keybindings) string {
+	hints := []string{
+		keyLabel(k.Pause) + ":Pause",
+		keyLabel(k.SeekBack) + "/" + keyLabel(k.SeekForward) + ":±10s",
+		keyLabel(k.TransposeUp) + "/" + keyLabel(k.TransposeDown) + ":Key",
+		keyLabel(k.BlockView) + ":Blocks",
+		keyLabel(k.Visualization) + ":Spectrum",
+		keyLabel(k.Ghost) + ":Ghost",
+		keyLabel(k.Grid) + ":Grid",
+		keyLabel(k.Intonation) + ":Intonation",
+		"Ctrl+↑↓:Speed",
+		keyLabel(k.SightReading) + ":Sight",
+		keyLabel(k.Echo) + ":Echo",
+		keyLabel(k.Heatmap) + ":Heatmap",
+		keyLabel(k.Metronome) + ":Click",
+		keyLabel(k.Fullscreen) + ":Fullscreen",
+		keyLabel(k.Exit) + ":Exit",
+	}
+	return strings.Join(hints, "  ")
+}
+
+/*
+keyLabel returns the short on-screen name of a key.
+
+Input:
+  - key: ebiten.Key - Key to name
+
+Called by:
+  - controlsHint
+
+Task:
+  - Keep the hint line short and readable
+
+Logic:
+ 1. Arrows as ←→↑↓, Equal and Minus as + and -, Space and Escape as SPACE and ESC
+ 2. Anything else: ebiten's name in upper case (e.g. "P", "F1", "TAB")
+
+Output:
+  - string: Label for the hint line
+*/
+func keyLabel(key ebiten.Key) string {
+	switch key {
+	case ebiten.KeyArrowLeft:
+		return "←"
+	case ebiten.KeyArrowRight:
+		return "→"
+	case ebiten.KeyArrowUp:
+		return "↑"
+	case ebiten.KeyArrowDown:
+		return "↓"
+	case ebiten.KeyEqual:
+		return "+"
+	case ebiten.KeyMinus:
+		return "-"
+	case ebiten.KeySpace:
+		return "SPACE"
+	case ebiten.KeyEscape:
+		return "ESC"
+	}
+	return strings.ToUpper(key.String())
+}
+
+/*
+DrawHeatmap renders time spent per target note and cents offset.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - cells: [][]float64 - Seconds per [note][centsBin], already cropped to the notes to show
+  - firstMidi: int - MIDI note of cells[0]
+  - centsSpan: float64 - Cents covered on each side of the target (bins span ±centsSpan)
+  - x, y, w, h: int - Area to draw into
+
+Called by:
+  - App.Draw when state is StateHeatmap
+
+Task:
+  - Show whether the user is scattered or consistently sharp/flat per note
+
+Logic:
+ 1. Find the largest cell value for color scaling
+ 2. Columns = target notes (left to right), rows = cents offset (sharp at top)
+ 3. Color each cell from dark blue to yellow by sqrt(value/max)
+ 4. Draw a gray line at 0 cents and label the axes
+
+Output:
+  - None (draws to screen)
+*/
+func DrawHeatmap(screen *ebiten.Image, cells [][]float64, firstMidi int, centsSpan float64, x, y, w, h int) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), ActiveTheme.HUDBackground, false)
+	if len(cells) == 0 || len(cells[0]) == 0 {
+		text.Draw(screen, "No pitch data yet", basicfont.Face7x13, x+w/2-60, y+h/2, ActiveTheme.HUDText)
+		return
+	}
+
+	maxV := 0.0
+	for _, row := range cells {
+		for _, v := range row {
+			maxV = math.Max(maxV, v)
+		}
+	}
+
+	bins := len(cells[0])
+	cellW := float32(w) / float32(len(cells))
+	cellH := float32(h) / float32(bins)
+
+	for n, row := range cells {
+		for b, v := range row {
+			if v <= 0 || maxV <= 0 {
+				continue
+			}
+			t := math.Sqrt(v / maxV)
+			clr := color.RGBA{uint8(255 * t), uint8(200 * t), uint8(255 * (1 - t) * 0.6), 255}
+			cx := float32(x) + float32(n)*cellW
+			cy := float32(y) + float32(bins-1-b)*cellH
+			vector.DrawFilledRect(screen, cx, cy, cellW, cellH, clr, false)
+		}
+
+		if n%2 == 0 || cellW > 30 {
+			name, octave := theory.FreqToNote(440 * math.Pow(2, float64(firstMidi+n-69)/12))
+			text.Draw(screen, fmt.Sprintf("%s%d", name, octave), basicfont.Face7x13, x+int(float32(n)*cellW), y+h+15, ActiveTheme.DimText)
+		}
+	}
+
+	zeroY := float32(y) + float32(h)/2
+	vector.StrokeLine(screen, float32(x), zeroY, float32(x+w), zeroY, 1, ActiveTheme.FaintText, false)
+	text.Draw(screen, fmt.Sprintf("+%.0f¢", centsSpan), basicfont.Face7x13, x-45, y+10, ActiveTheme.DimText)
+	text.Draw(screen, "0¢", basicfont.Face7x13, x-25, int(zeroY)+4, ActiveTheme.DimText)
+	text.Draw(screen, fmt.Sprintf("-%.0f¢", centsSpan), basicfont.Face7x13, x-45, y+h, ActiveTheme.DimText)
+}
+
+/*
+HistoryRow is one formatted line of the history screen.
+
+Fields:
+  - When: Formatted date/time of the run
+  - Song: Song name
+  - Mode: Mode name
+  - Score: Hit percentage 0..100
+*/
+type HistoryRow struct {
+	When  string
+	Song  string
+	Mode  string
+	Score float64
+}
+
+/*
+DrawHistory renders the list of recent practice runs.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - rows: []HistoryRow - Runs to list, newest first
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.drawHistory when state is StateHistory
+
+Task:
+  - Simple table of past attempts
+
+Logic:
+ 1. Fill black and draw title and column headers
+ 2. One line per row, stopping at the bottom of the screen
+ 3. Color the score green/yellow/red by value
+ 4. Draw key hint
+
+Output:
+  - None (draws to screen)
+*/
+func DrawHistory(screen *ebiten.Image, rows []HistoryRow, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	text.Draw(screen, "PRACTICE HISTORY", basicfont.Face7x13, sw/2-55, 30, ActiveTheme.HUDText)
+
+	if len(rows) == 0 {
+		text.Draw(screen, "No runs recorded yet", basicfont.Face7x13, sw/2-70, sh/2, ActiveTheme.DimText)
+	} else {
+		text.Draw(screen, fmt.Sprintf("%-18s %-30s %-12s %s", "DATE", "SONG", "MODE", "SCORE"), basicfont.Face7x13, 40, 60, ActiveTheme.DimText)
+	}
+
+	for i, r := range rows {
+		y := 80 + i*18
+		if y > sh-40 {
+			break
+		}
+
+		song := []rune(r.Song)
+		if len(song) > 30 {
+			song = append(song[:27], []rune("...")...)
+		}
+
+		clr := ActiveTheme.Bad
+		if r.Score >= 70 {
+			clr = ActiveTheme.Good
+		} else if r.Score >= 40 {
+			clr = ActiveTheme.Highlight
+		}
+
+		text.Draw(screen, fmt.Sprintf("%-18s %-30s %-12s", r.When, string(song), r.Mode), basicfont.Face7x13, 40, y, ActiveTheme.HUDText)
+		text.Draw(screen, fmt.Sprintf("%5.1f%%", r.Score), basicfont.Face7x13, 40+62*7, y, clr)
+	}
+
+	DrawTextAt(screen, "H/ESC: Back", 10, sh-20, ActiveTheme.HUDText)
+}
+
+/*
+DrawWarmupMenu renders the warmup exercise sub-menu.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - rows: []string - Formatted settings, one per line (e.g. "Scale: Major")
+  - selected: int - Index of the highlighted row
+  - summary: string - Description of the resulting exercise
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.Draw when state is StateWarmupMenu
+
+Task:
+  - Let the user pick scale type, starting octave and tempo
+
+Logic:
+ 1. Clear the screen and draw the title
+ 2. Draw each row, the selected one in yellow between < > arrows
+ 3. Draw the exercise summary and key hints
+
+Output:
+  - None (draws to screen)
+*/
+func DrawWarmupMenu(screen *ebiten.Image, rows []string, selected int, summary string, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	text.Draw(screen, "VOCAL WARMUP", basicfont.Face7x13, sw/2-42, sh/2-120, ActiveTheme.HUDText)
+
+	for i, r := range rows {
+		y := sh/2 - 70 + i*30
+		if i == selected {
+			text.Draw(screen, "< "+r+" >", basicfont.Face7x13, sw/2-100, y, ActiveTheme.Highlight)
+		} else {
+			text.Draw(screen, r, basicfont.Face7x13, sw/2-86, y, ActiveTheme.DimText)
+		}
+	}
+
+	text.Draw(screen, summary, basicfont.Face7x13, sw/2-100, sh/2+60, ActiveTheme.Highlight)
+	DrawTextAt(screen, "UP/DOWN: Select  LEFT/RIGHT: Change  ENTER: Start  ESC: Back", 10, sh-20, ActiveTheme.HUDText)
+}
+
+// songBrowserRowH is the line spacing of the song browser list.
+const songBrowserRowH = 20
+
+/*
+GenreSelectorRect returns the song browser's genre selector box.
+
+Input:
+  - sw: int - Screen width
+
+Called by:
+  - DrawSongBrowser, App.handleSongBrowserInput (click hit test)
+
+Task:
+  - Keep drawing and clicking on the same rectangle
+
+Logic:
+ 1. 120px wide, right of the search field on the same row
+
+Output:
+  - x, y, w, h: int - Box position and size
+*/
+func GenreSelectorRect(sw int) (x, y, w, h int) {
+	return sw/2 + 94, 52, 120, 24
+}
+
+/*
+DrawSongBrowser renders the song selection list.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - songs: []string - Song labels to list (already filtered; "Artist - Title" or folder name)
+  - query: string - Search text typed so far
+  - genre: string - Genre filter ("" for all genres)
+  - selected: int - Index of the highlighted song
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.drawSongBrowser when state is StateSongBrowser
+
+Task:
+  - Let the user pick a song from the songs folder
+
+Logic:
+ 1. Clear the screen and draw the title, then the search field with the
+    query and a cursor (a hint while it is empty) and the genre selector
+ 2. Fit as many rows as the screen allows and scroll so the selection
+    stays in the middle of the visible window
+ 3. Draw each visible song, the selected one in yellow with a > marker
+ 4. Show "No songs found" (or "No songs match") when the list is empty, and the key hints
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSongBrowser(screen *ebiten.Image, songs []string, query, genre string, selected int, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	text.Draw(screen, "SELECT A SONG", basicfont.Face7x13, sw/2-45, 40, ActiveTheme.HUDText)
+
+	x, y := sw/2-214, 52
+	vector.DrawFilledRect(screen, float32(x), float32(y), 300, 24, ActiveTheme.HUDBackground, false)
+	vector.StrokeRect(screen, float32(x), float32(y), 300, 24, 1, ActiveTheme.Border, false)
+	if query == "" {
+		text.Draw(screen, "Type to search...", basicfont.Face7x13, x+10, y+17, ActiveTheme.FaintText)
+	} else {
+		text.Draw(screen, query+"_", basicfont.Face7x13, x+10, y+17, ActiveTheme.Highlight)
+	}
+
+	gx, gy, gw, gh := GenreSelectorRect(sw)
+	vector.DrawFilledRect(screen, float32(gx), float32(gy), float32(gw), float32(gh), ActiveTheme.HUDBackground, false)
+	vector.StrokeRect(screen, float32(gx), float32(gy), float32(gw), float32(gh), 1, ActiveTheme.Border, false)
+	label, labelCol := "Genre: All", color.Color(ActiveTheme.DimText)
+	if genre != "" {
+		label, labelCol = genre, ActiveTheme.Highlight
+	}
+	if len(label) > 15 {
+		label = label[:14] + "~"
+	}
+	text.Draw(screen, label, basicfont.Face7x13, gx+8, gy+17, labelCol)
+
+	if len(songs) == 0 {
+		msg := "No songs found"
+		if query != "" || genre != "" {
+			msg = "No songs match"
+		}
+		text.Draw(screen, msg, basicfont.Face7x13, sw/2-50, sh/2, ActiveTheme.DimText)
+	}
+
+	rows := max(1, (sh-140)/songBrowserRowH)
+	first := max(0, min(selected-rows/2, len(songs)-rows))
+	for i := first; i < len(songs) && i < first+rows; i++ {
+		y := 100 + (i-first)*songBrowserRowH
+		if i == selected {
+			text.Draw(screen, "> "+songs[i], basicfont.Face7x13, sw/2-214, y, ActiveTheme.Highlight)
+		} else {
+			text.Draw(screen, songs[i], basicfont.Face7x13, sw/2-200, y, ActiveTheme.DimText)
+		}
+	}
+
+	DrawTextAt(screen, "UP/DOWN: Select  ENTER: Open  Type: Filter  BACKSPACE: Clear letter  TAB: Genre  ESC: Back", 10, sh-20, ActiveTheme.HUDText)
+}
+
+/*
+DrawSetupScreen renders the first-run Python venv setup.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - input: string - Venv path typed so far
+  - message: string - Why the last path was rejected ("" if none)
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.Draw when state is StateSetup
+
+Task:
+  - Explain what the venv is for and take its path
+
+Logic:
+ 1. Clear the screen and draw the title and instructions
+ 2. Draw the input box with the typed path and a cursor
+ 3. Draw the rejection message in red, and the key hints
+
+Output:
+  - None (draws to screen)
+*/
+func DrawSetupScreen(screen *ebiten.Image, input, message string, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	text.Draw(screen, "FIRST-RUN SETUP", basicfont.Face7x13, sw/2-52, 60, ActiveTheme.HUDText)
+
+	lines := []string{
+		"Vocal separation runs spleeter or demucs in a Python virtual environment.",
+		"Create one (python3 -m venv venv && venv/bin/pip install spleeter),",
+		"then type its folder below, e.g. venv or /home/me/singassist/venv.",
+	}
+	for i, l := range lines {
+		text.Draw(screen, l, basicfont.Face7x13, sw/2-260, 110+i*20, ActiveTheme.DimText)
+	}
+
+	x, y := sw/2-260, 190
+	vector.DrawFilledRect(screen, float32(x), float32(y), 520, 28, ActiveTheme.HUDBackground, false)
+	vector.StrokeRect(screen, float32(x), float32(y), 520, 28, 1, ActiveTheme.Border, false)
+	text.Draw(screen, input+"_", basicfont.Face7x13, x+10, y+19, ActiveTheme.Highlight)
+
+	if message != "" {
+		text.Draw(screen, message, basicfont.Face7x13, x, y+55, ActiveTheme.Bad)
+	}
+
+	DrawTextAt(screen, "Type: Path  BACKSPACE: Delete  ENTER: Check and save  ESC: Skip (use system python3)", 10, sh-20, ActiveTheme.HUDText)
+}
+
+// The spectrogram covers MIDI SpectrumMinMidi (E2, 82 Hz) to SpectrumMaxMidi
+// (B6, 1976 Hz) in SpectrumBinsPerSemitone steps, on the same note axis as
+// the pitch graph.
+const (
+	SpectrumMinMidi         = 40
+	SpectrumMaxMidi         = 95
+	SpectrumBinsPerSemitone = 4
+)
+
+/*
+SpectrumColumn is one analyzed microphone buffer for the spectrogram view.
+
+Fields:
+  - TimeMs: Playback position when the buffer was read
+  - Levels: Brightness 0..1 per bin, lowest frequency first (see MicSpectrum)
+*/
+type SpectrumColumn struct {
+	TimeMs float64
+	Levels []float64
+}
+
+/*
+MicSpectrum measures the energy of a microphone buffer on the note grid.
+
+Input:
+  - samples: []float32 - Mono microphone samples (e.g. config.BufferSize)
+
+Called by:
+  - App.micLoop while the spectrogram view is on
+
+Task:
+  - Short-time spectrum at the frequencies the pitch graph can show
+
+Logic:
+ 1. Apply a Hann window
+ 2. For each bin (quarter semitone from SpectrumMinMidi to SpectrumMaxMidi),
+    run a Goertzel filter at its centre frequency
+ 3. Convert the amplitude to dB and map -70..-10 dB to 0..1
+
+Output:
+  - []float64: Levels per bin, lowest frequency first
+*/
+func MicSpectrum(samples []float32) []float64 {
+	n := len(samples)
+	windowed := make([]float64, n)
+	for i, s := range samples {
+		windowed[i] = float64(s) * 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+	}
+
+	bins := (SpectrumMaxMidi - SpectrumMinMidi) * SpectrumBinsPerSemitone
+	levels := make([]float64, bins)
+	for b := range levels {
+		midi := SpectrumMinMidi + float64(b)/SpectrumBinsPerSemitone
+		freq := 440 * math.Pow(2, (midi-69)/12)
+
+		coeff := 2 * math.Cos(2*math.Pi*freq/config.SampleRate)
+		var s1, s2 float64
+		for _, x := range windowed {
+			s1, s2 = x+coeff*s1-s2, s1
+		}
+		power := s1*s1 + s2*s2 - coeff*s1*s2
+		amp := 4 * math.Sqrt(math.Max(power, 0)) / float64(n)
+
+		db := 20 * math.Log10(amp+1e-12)
+		levels[b] = math.Max(0, math.Min(1, (db+70)/60))
+	}
+	return levels
+}
+
+/*
+DrawSpectrogram renders recent microphone spectra as a scrolling heat map.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - cols: []SpectrumColumn - Analyzed buffers, oldest first
+  - currTime: float64 - Current playback time in seconds
+  - sw: int - Screen width
+
+Called by:
+  - App.drawPlayingMode in the spectrogram view
+
+Task:
+  - Show harmonics next to the song line, with rows on the pitch graph's note axis
+
+Logic:
+ 1. Place each column like the user trail (latency-compensated time -> X),
+    the newest ending at OffsetX
+ 2. Width runs to the next column's start (one buffer for the newest)
+ 3. Each bin is drawn at FreqToY of its frequency (one quarter-semitone row)
+ 4. Skip quiet bins; colour goes dark blue -> orange -> yellow with level
+
+Output:
+  - None (draws to screen)
+*/
+func (v *PitchVisualizer) DrawSpectrogram(screen *ebiten.Image, cols []SpectrumColumn, currTime float64, sw int) {
+	latencyOffset := config.GetAudioLatencyMs() / 1000.0
+	bufSec := float64(config.BufferSize) / config.SampleRate
+	rowH := float32(v.ScaleY/SpectrumBinsPerSemitone) + 1
+
+	for i, c := range cols {
+		t := c.TimeMs/1000.0 - latencyOffset
+		x := (t-currTime)*v.PixelsPerSec + v.OffsetX
+		next := t + bufSec
+		if i+1 < len(cols) {
+			next = cols[i+1].TimeMs/1000.0 - latencyOffset
+		}
+		w := (next - t) * v.PixelsPerSec
+		if x+w < 0 {
+			continue
+		}
+		if x > float64(sw) {
+			break
+		}
+
+		for b, lvl := range c.Levels {
+			if lvl < 0.1 {
+				continue
+			}
+			midi := SpectrumMinMidi + float64(b)/SpectrumBinsPerSemitone
+			y := v.FreqToY(440 * math.Pow(2, (midi-69)/12))
+			clr := color.RGBA{uint8(255 * lvl), uint8(220 * lvl * lvl), uint8(120 * (1 - lvl)), 255}
+			vector.DrawFilledRect(screen, float32(x), float32(y)-rowH/2, float32(w)+1, rowH, clr, false)
+		}
+	}
+}
+
+/*
+DrawLatencyCalibration renders the latency calibration screen.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - status: string - Progress or result message
+  - done: bool - Whether the measurement has finished
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.Draw when state is StateLatencyCalibration
+
+Task:
+  - Explain the test and show its result
+
+Logic:
+ 1. Clear the screen, draw title and instructions
+ 2. Draw the status line
+ 3. Show the back hint once the measurement is done
+
+Output:
+  - None (draws to screen)
+*/
+func DrawLatencyCalibration(screen *ebiten.Image, status string, done bool, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	text.Draw(screen, "LATENCY CALIBRATION", basicfont.Face7x13, sw/2-66, sh/2-80, ActiveTheme.HUDText)
+	text.Draw(screen, "Unplug headphones and keep quiet while the clicks play.", basicfont.Face7x13, sw/2-192, sh/2-50, ActiveTheme.DimText)
+	text.Draw(screen, status, basicfont.Face7x13, sw/2-192, sh/2, ActiveTheme.Highlight)
+	if done {
+		DrawTextAt(screen, "ESC/ENTER: Back", 10, sh-20, ActiveTheme.HUDText)
+	}
+}
+
+/*
+DrawRangeTest renders the vocal range test.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - target: string - Note to sing, e.g. "C3" ("" once the test is done)
+  - listening: bool - The tone has stopped and the user should sing it back
+  - low, high: string - Lowest and highest matched notes ("" while none matched)
+  - sw, sh: int - Screen width and height
+
+Called by:
+  - App.drawRangeTest
+
+Task:
+  - Guide the user note by note, then show the range found
+
+Logic:
+ 1. Fill screen with the theme background
+ 2. Running: the target note centered in the big font, "Listen..." while the
+    tone plays and "Sing it!" afterwards, and the range matched so far
+ 3. Done: "Your range: E3 - G5" (or a hint to retry when nothing matched)
+
+Output:
+  - None (draws to screen)
+*/
+func DrawRangeTest(screen *ebiten.Image, target string, listening bool, low, high string, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+
+	matched := "No notes matched yet"
+	if low != "" {
+		matched = fmt.Sprintf("Matched so far: %s - %s", low, high)
+	}
+	if target == "" {
+		result := "No notes matched - sing louder or move closer to the mic"
+		if low != "" {
+			result = fmt.Sprintf("Your range: %s - %s", low, high)
+		}
+		text.Draw(screen, result, basicfont.Face7x13, sw/2-len(result)*7/2, sh/2, ActiveTheme.Highlight)
+		return
+	}
+
+	if bigFont != nil {
+		b := text.BoundString(bigFont, target)
+		text.Draw(screen, target, bigFont, sw/2-b.Dx()/2, sh/2+b.Dy()/2, ActiveTheme.HUDText)
+	}
+	prompt := "Listen..."
+	if listening {
+		prompt = "Sing it!"
+	}
+	text.Draw(screen, prompt, basicfont.Face7x13, sw/2-len(prompt)*7/2, sh/2+60, ActiveTheme.Highlight)
+	text.Draw(screen, matched, basicfont.Face7x13, sw/2-len(matched)*7/2, sh/2+90, ActiveTheme.DimText)
+}
+
+/*
+DrawIntervalQuiz renders the interval ear-training quiz.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - interval: string - Interval to sing, e.g. "Major 3rd"
+  - root: string - Root note played, e.g. "C4"
+  - listening: bool - The root has stopped and the user should sing the interval
+  - feedback: string - Verdict on the answer ("" while the question is open)
+  - correct, asked: int - Tally so far
+  - score: float64 - Share of correct answers in percent
+  - sw, sh: int - Screen width and height
+
+Called by:
+  - App.drawIntervalQuiz
+
+Task:
+  - Pose each question, then show how the answer went
+
+Logic:
+ 1. Fill screen with the theme background
+ 2. "Sing a <interval> above" centered in the big font, the root note below it
+ 3. Open question: "Listen..." while the root plays and "Sing it!" afterwards;
+    answered: the feedback, green when it starts with "Correct", red otherwise
+ 4. "Score: 3/4 (75%)" underneath once an answer was recorded
+
+Output:
+  - None (draws to screen)
+*/
+func DrawIntervalQuiz(screen *ebiten.Image, interval, root string, listening bool, feedback string, correct, asked int, score float64, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+
+	prompt := fmt.Sprintf("Sing a %s above", interval)
+	if bigFont != nil {
+		b := text.BoundString(bigFont, prompt)
+		text.Draw(screen, prompt, bigFont, sw/2-b.Dx()/2, sh/2+b.Dy()/2, ActiveTheme.HUDText)
+	}
+	rootLine := "Root: " + root
+	text.Draw(screen, rootLine, basicfont.Face7x13, sw/2-len(rootLine)*7/2, sh/2+40, ActiveTheme.DimText)
+
+	status, clr := "Listen...", color.Color(ActiveTheme.Highlight)
+	if feedback != "" {
+		status, clr = feedback, ActiveTheme.Bad
+		if strings.HasPrefix(feedback, "Correct") {
+			clr = ActiveTheme.Good
+		}
+	} else if listening {
+		status = "Sing it!"
+	}
+	text.Draw(screen, status, basicfont.Face7x13, sw/2-len(status)*7/2, sh/2+70, clr)
+
+	if asked > 0 {
+		tally := fmt.Sprintf("Score: %d/%d (%.0f%%)", correct, asked, score)
+		text.Draw(screen, tally, basicfont.Face7x13, sw/2-len(tally)*7/2, sh/2+100, ActiveTheme.DimText)
+	}
+}
+
+/*
+DrawIntonationGraph plots how far the user sings from the nearest semitone.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...]
+  - currTime: float64 - Current playback time in seconds
+  - x, y, w, h: int - Area to draw into
+
+Called by:
+  - App.drawPlayingMode while the intonation graph is shown (I)
+
+Task:
+  - Reveal a systematic sharp or flat tendency, whatever the song is doing
+
+Logic:
+ 1. Panel background with a zero line across the middle (in tune) and ±50 labels
+ 2. Lay the trail out with intonationPoints
+ 3. Join neighbouring voiced points; segments above the zero line are green
+    (sharp), below it red (flat)
+
+Output:
+  - None (draws to screen)
+*/
+func DrawIntonationGraph(screen *ebiten.Image, userPitch []float64, currTime float64, x, y, w, h int) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), ActiveTheme.HUDBackground, false)
+	mid := float64(y) + float64(h)/2
+	vector.StrokeLine(screen, float32(x), float32(mid), float32(x+w), float32(mid), 1, ActiveTheme.FaintText, false)
+	DrawTextAt(screen, "+50", x+2, y, ActiveTheme.DimText)
+	DrawTextAt(screen, "-50", x+2, y+h-16, ActiveTheme.DimText)
+
+	var prev songPoint
+	for _, p := range intonationPoints(userPitch, currTime, x, y, w, h) {
+		if !p.Start {
+			col := ActiveTheme.Good
+			if (prev.Y+p.Y)/2 > mid {
+				col = ActiveTheme.Bad
+			}
+			ebitenutil.DrawLine(screen, prev.X, prev.Y, p.X, p.Y, col)
+		}
+		prev = p
+	}
+}
+
+/*
+intonationPoints lays out the intonation graph's deviation curve.
+
+Input:
+  - userPitch: []float64 - Recorded pairs [timeMs, pitch, ...]
+  - currTime: float64 - Current playback time in seconds
+  - x, y, w, h: int - Graph area
+
+Called by:
+  - DrawIntonationGraph
+
+Task:
+  - Keep the cents mapping apart from the drawing
+
+Logic:
+ 1. Keep the last config.IntonationGraphSeconds of userPitch, left to right
+ 2. Each voiced point's CentsOffset maps ±50 cents to the top and bottom edges
+ 3. Silence starts a new stroke
+
+Output:
+  - []songPoint: Points in recording order
+*/
+func intonationPoints(userPitch []float64, currTime float64, x, y, w, h int) []songPoint {
+	mid := float64(y) + float64(h)/2
+	toMs := currTime * 1000
+	fromMs := toMs - config.IntonationGraphSeconds*1000
+	scale := float64(w) / (toMs - fromMs)
+
+	var points []songPoint
+	first := true
+	for i := 0; i+1 < len(userPitch); i += 2 {
+		t, p := userPitch[i], userPitch[i+1]
+		if p <= 10 || t < fromMs || t > toMs {
+			first = true
+			continue
+		}
+		points = append(points, songPoint{
+			X:     float64(x) + (t-fromMs)*scale,
+			Y:     mid - theory.CentsOffset(p)/50*float64(h)/2,
+			Start: first,
+		})
+		first = false
+	}
+	return points
+}
+
+/*
+DrawNoteEditor renders the song's practice notes as an editable text area.
+
+Input:
+  - screen: *ebiten.Image - Target drawing surface
+  - songName: string - Song the notes belong to
+  - content: string - Notes typed so far
+  - message: string - Result of the last save ("" if none)
+  - sw, sh: int - Screen dimensions
+
+Called by:
+  - App.drawNoteEditor when state is StateNoteEditor
+
+Task:
+  - Multi-line editing of notes.md
+
+Logic:
+ 1. Clear the screen and draw the title
+ 2. Draw the text area with one row per line and a cursor after the last one;
+    when the text is taller than the area, only its last lines are shown
+ 3. Draw the save message and the key hints
+
+Output:
+  - None (draws to screen)
+*/
+func DrawNoteEditor(screen *ebiten.Image, songName, content, message string, sw, sh int) {
+	screen.Fill(ActiveTheme.Background)
+	text.Draw(screen, "NOTES - "+songName, basicfont.Face7x13, 40, 40, ActiveTheme.HUDText)
+
+	x, y, w, h := 40, 60, sw-80, sh-120
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), ActiveTheme.HUDBackground, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, ActiveTheme.Border, false)
+
+	lines := strings.Split(content+"_", "\n")
+	rows := (h - 20) / 16
+	if len(lines) > rows {
+		lines = lines[len(lines)-rows:]
+	}
+	for i, l := range lines {
+		text.Draw(screen, l, basicfont.Face7x13, x+10, y+20+i*16, ActiveTheme.Highlight)
+	}
+
+	if message != "" {
+		text.Draw(screen, message, basicfont.Face7x13, x, y+h+20, ActiveTheme.DimText)
+	}
+	DrawTextAt(screen, "Type: Edit  ENTER: New line  BACKSPACE: Delete  CTRL+S: Save  ESC: Back", 10, sh-20, ActiveTheme.HUDText)
+}
+
+/*
+noteSnippet returns the first lines of a song's notes for the start screen.
+
+Input:
+  - notes: string - Contents of notes.md
+  - maxLines, maxChars: int - Lines to keep and characters per line
+
+Called by:
+  - DrawStartScreen
+
+Task:
+  - Remind the user of their notes without opening the editor
+
+Logic:
+ 1. Trim surrounding blank space and split into lines
+ 2. Keep the first maxLines, cutting longer lines to maxChars with "..."
+
+Output:
+  - []string: Lines to draw (nil without notes)
+*/
+func noteSnippet(notes string, maxLines, maxChars int) []string {
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		return nil
+	}
+	lines := strings.Split(notes, "\n")
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+	}
+	for i, l := range lines {
+		if r := []rune(l); len(r) > maxChars {
+			lines[i] = string(r[:maxChars-3]) + "..."
+		}
+	}
+	return lines
 }
@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"singAssist/internal/config"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestDashSegmentsAlternate(t *testing.T) {
+	dashes, _ := dashSegments(0, 0, 20, 0, 4, 0)
+	want := [][4]float64{{0, 0, 4, 0}, {8, 0, 12, 0}, {16, 0, 20, 0}}
+	if len(dashes) != len(want) {
+		t.Fatalf("got %d dashes %v, want %v", len(dashes), dashes, want)
+	}
+	for i := range want {
+		for k := range want[i] {
+			if math.Abs(dashes[i][k]-want[i][k]) > 1e-9 {
+				t.Errorf("dash %d = %v, want %v", i, dashes[i], want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestDashSegmentsCarryPhase(t *testing.T) {
+	// Ten 1px segments must draw the same pattern as one 10px line: dashes
+	// over 0-4 and 8-10, a gap over 4-8.
+	var phase, drawn float64
+	for x := 0.0; x < 10; x++ {
+		var dashes [][4]float64
+		dashes, phase = dashSegments(x, 0, x+1, 0, 4, phase)
+		for _, d := range dashes {
+			if mid := (d[0] + d[2]) / 2; mid > 4 && mid < 8 {
+				t.Errorf("dash %v drawn inside the gap", d)
+			}
+			drawn += d[2] - d[0]
+		}
+	}
+	if math.Abs(drawn-6) > 1e-9 {
+		t.Errorf("dashes cover %.2fpx of 10px, want 6px", drawn)
+	}
+	if math.Abs(phase-2) > 1e-9 {
+		t.Errorf("phase after 10px = %v, want 2", phase)
+	}
+}
+
+func TestDashSegmentsDegenerate(t *testing.T) {
+	dashes, phase := dashSegments(3, 3, 3, 3, 4, 5)
+	if len(dashes) != 1 || phase != 5 {
+		t.Errorf("zero-length line = %v, phase %v; want one dash and the phase unchanged", dashes, phase)
+	}
+}
+
+func TestSliderValueAtClamps(t *testing.T) {
+	tests := []struct {
+		mx   int
+		want float64
+	}{
+		{-50, 0.5},  // left of the track
+		{20, 0.5},   // left end
+		{120, 2.75}, // middle
+		{220, 5},    // right end
+		{400, 5},    // right of the track
+	}
+	for _, tt := range tests {
+		if got := SliderValueAt(tt.mx, 20, 200, 0.5, 5); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("SliderValueAt(%d) = %v, want %v", tt.mx, got, tt.want)
+		}
+	}
+}
+
+func TestSliderHandleX(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  float32
+	}{
+		{0.5, 20},
+		{1.5, 20 + 200*1.0/4.5},
+		{5, 220},
+		{0, 20},  // below min
+		{9, 220}, // above max
+	}
+	for _, tt := range tests {
+		if got := SliderHandleX(20, 200, tt.value, 0.5, 5); math.Abs(float64(got-tt.want)) > 1e-3 {
+			t.Errorf("SliderHandleX(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	// The handle sits where a drag to it would set the same value.
+	for _, v := range []float64{0.5, 1.5, 3.2, 5} {
+		x := SliderHandleX(20, 200, v, 0.5, 5)
+		if got := SliderValueAt(int(math.Round(float64(x))), 20, 200, 0.5, 5); math.Abs(got-v) > 0.03 {
+			t.Errorf("dragging to the handle of %v gives %v", v, got)
+		}
+	}
+}
+
+func TestMicSpectrumBrightAtA4(t *testing.T) {
+	samples := make([]float32, config.BufferSize)
+	for i := range samples {
+		samples[i] = float32(0.3 * math.Sin(2*math.Pi*440*float64(i)/config.SampleRate))
+	}
+	levels := MicSpectrum(samples)
+	if want := (SpectrumMaxMidi - SpectrumMinMidi) * SpectrumBinsPerSemitone; len(levels) != want {
+		t.Fatalf("got %d bins, want %d", len(levels), want)
+	}
+
+	a4 := (69 - SpectrumMinMidi) * SpectrumBinsPerSemitone
+	brightest := 0
+	for b, lvl := range levels {
+		if lvl > levels[brightest] {
+			brightest = b
+		}
+	}
+	if brightest != a4 {
+		t.Errorf("brightest bin = %d, want the A4 bin %d", brightest, a4)
+	}
+	for b := a4 - 2; b <= a4+2; b++ {
+		if levels[b] < 0.8 {
+			t.Errorf("bin %d next to A4 has level %.2f, want a bright cluster (>= 0.8)", b, levels[b])
+		}
+	}
+	for _, midi := range []int{45, 57, 64, 81, 93} {
+		if lvl := levels[(midi-SpectrumMinMidi)*SpectrumBinsPerSemitone]; lvl > 0.3 {
+			t.Errorf("MIDI %d has level %.2f on a pure A4, want dark (<= 0.3)", midi, lvl)
+		}
+	}
+
+	// The A4 bin is drawn on the pitch graph's A4 row.
+	v := NewPitchVisualizer(1280, 720)
+	midi := SpectrumMinMidi + float64(a4)/SpectrumBinsPerSemitone
+	if y, want := v.FreqToY(440*math.Pow(2, (midi-69)/12)), v.FreqToY(440); math.Abs(y-want) > 1e-9 {
+		t.Errorf("A4 bin row y = %v, want the A4 line at %v", y, want)
+	}
+}
+
+func TestMicSpectrumSilenceIsDark(t *testing.T) {
+	for b, lvl := range MicSpectrum(make([]float32, config.BufferSize)) {
+		if lvl != 0 {
+			t.Fatalf("bin %d = %v on silence, want 0", b, lvl)
+		}
+	}
+}
+
+func TestSemitoneGridA4Line(t *testing.T) {
+	// 720px screen: OffsetY 670, ScaleY 620/60 px per semitone, BaseMidi 30,
+	// so A4 (MIDI 69) sits 39 semitones above the bottom line.
+	v := NewPitchVisualizer(1280, 720)
+	wantY := 670 - 39*620.0/60
+	lines := semitoneGridLines(v, 720)
+
+	found := false
+	for _, l := range lines {
+		if math.Abs(l.Y-wantY) < 1e-9 {
+			found = true
+			if l.Kind != gridSemitone {
+				t.Errorf("A4 line kind = %d, want a semitone line", l.Kind)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no grid line at y = %.2f for A4", wantY)
+	}
+
+	labels := map[string]float64{}
+	for _, l := range lines {
+		if l.Kind == gridOctave {
+			labels[l.Label] = l.Y
+		}
+		if l.Y < -v.ScaleY || l.Y > 720+v.ScaleY {
+			t.Errorf("line at y = %.2f is off screen", l.Y)
+		}
+	}
+	if y, ok := labels["C4"]; !ok || math.Abs(y-v.MidiToY(60)) > 1e-9 {
+		t.Errorf("C4 octave line at %v (present %v), want y = %.2f", y, ok, v.MidiToY(60))
+	}
+	if _, ok := labels["C3"]; !ok {
+		t.Error("no C3 octave line")
+	}
+}
+
+func TestSemitoneGridQuarterTones(t *testing.T) {
+	v := NewPitchVisualizer(1280, 720)
+	for _, l := range semitoneGridLines(v, 720) {
+		if l.Kind != gridQuarterTone {
+			continue
+		}
+		m := (v.OffsetY-l.Y)/v.ScaleY + v.BaseMidi
+		if frac := m - math.Floor(m); math.Abs(frac-0.5) > 1e-9 {
+			t.Fatalf("quarter-tone line at MIDI %.3f, want half-way between semitones", m)
+		}
+	}
+}
+
+func TestDoubleScrollSpeedHalvesVisibleWindow(t *testing.T) {
+	const sw, sh = 1280, 720
+	window := func(pps float64) float64 {
+		vis := NewPitchVisualizer(sw, sh)
+		vis.PixelsPerSec = pps
+		return vis.TimeAtX(sw, 30) - vis.TimeAtX(0, 30)
+	}
+
+	if got, want := window(config.PixelsPerSec), sw/config.PixelsPerSec; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("window at the default speed = %v s, want %v s", got, want)
+	}
+	for _, pps := range []float64{config.MinScrollSpeed, 75, config.PixelsPerSec, 200} {
+		if got, want := window(2*pps), window(pps)/2; math.Abs(got-want) > 1e-9 {
+			t.Errorf("window at %v px/s = %v s, want half of %v s", 2*pps, got, window(pps))
+		}
+	}
+}
+
+func TestSightReadingHidesFutureSongLine(t *testing.T) {
+	const sw, sh = 1280, 720
+	song := make([]float64, 3000)
+	for i := range song {
+		song[i] = 220
+	}
+	const currTime = 10.0
+
+	vis := NewPitchVisualizer(sw, sh)
+	ahead := false
+	for _, p := range vis.songLinePoints(song, currTime, sh) {
+		if p.X > vis.OffsetX {
+			ahead = true
+		}
+	}
+	if !ahead {
+		t.Fatal("without sight reading no song pitch is drawn ahead of the now line")
+	}
+
+	vis.HideFuture = true
+	points := vis.songLinePoints(song, currTime, sh)
+	if len(points) == 0 {
+		t.Fatal("sight reading hid the past song line too")
+	}
+	for _, p := range points {
+		if p.X > vis.OffsetX {
+			t.Fatalf("sight reading drew song pitch at x=%v, right of the now line at %v", p.X, vis.OffsetX)
+		}
+	}
+}
+
+func TestIntonationPointsPlotCentsDeviation(t *testing.T) {
+	const x, y, w, h = 100, 50, 600, 200
+	const currTime = 40.0
+	mid := float64(y) + h/2
+	// 432 Hz from 20 s to 40 s, a 1 s gap at 30 s, and 440 Hz before 10 s
+	// (older than the graph shows).
+	var trail []float64
+	for ms := 0; ms <= 40000; ms += 100 {
+		p := 432.0
+		switch {
+		case ms < 10000:
+			p = 440
+		case ms < 20000, ms >= 30000 && ms < 31000:
+			p = 0
+		}
+		trail = append(trail, float64(ms), p)
+	}
+
+	points := intonationPoints(trail, currTime, x, y, w, h)
+	wantY := mid + 31.77/50*h/2
+	strokes := 0
+	for _, p := range points {
+		if p.X < x || p.X > x+w {
+			t.Fatalf("point at x=%v outside the graph", p.X)
+		}
+		if math.Abs(p.Y-wantY) > 0.1 {
+			t.Fatalf("432 Hz plotted at y=%v, want %v (31.8 cents below the zero line)", p.Y, wantY)
+		}
+		if p.Start {
+			strokes++
+		}
+	}
+	if strokes != 2 {
+		t.Errorf("%d strokes, want 2 (split by the silent second)", strokes)
+	}
+	if want := 100 + 91; len(points) != want { // 20.0-29.9 s and 31.0-40.0 s
+		t.Errorf("%d points, want %d from the last %v s", len(points), want, config.IntonationGraphSeconds)
+	}
+}
+
+func TestTimeAtXKnownPositions(t *testing.T) {
+	vis := NewPitchVisualizer(1280, 720) // "now" line at x = 256
+	tests := []struct {
+		x, pps, want float64
+	}{
+		{256, 150, 30},
+		{406, 150, 31},
+		{1006, 150, 35},
+		{106, 150, 29},
+		{0, 150, 30 - 256.0/150},
+		{556, 300, 31},
+		{556, 75, 34},
+	}
+	for _, tt := range tests {
+		vis.PixelsPerSec = tt.pps
+		if got := vis.TimeAtX(tt.x, 30); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("TimeAtX(%v) at %v px/s = %v s, want %v s", tt.x, tt.pps, got, tt.want)
+		}
+	}
+}
+
+func TestControlsHintFollowsKeybindings(t *testing.T) {
+	keys := config.DefaultKeybindings()
+	if got := controlsHint(keys); !strings.HasPrefix(got, "SPACE:Pause  ←/→:±10s  +/-:Key  B:Blocks") || !strings.HasSuffix(got, "F:Fullscreen  ESC:Exit") {
+		t.Errorf("default hint = %q", got)
+	}
+
+	keys.Pause, keys.SeekBack, keys.SeekForward = ebiten.KeyP, ebiten.KeyJ, ebiten.KeyL
+	got := controlsHint(keys)
+	if !strings.HasPrefix(got, "P:Pause  J/L:±10s") {
+		t.Errorf("remapped hint = %q, want P:Pause and J/L:±10s", got)
+	}
+	if strings.Contains(got, "SPACE") || strings.Contains(got, "←") {
+		t.Errorf("remapped hint %q still names the default keys", got)
+	}
+}
+
+func TestNoteSnippet(t *testing.T) {
+	got := noteSnippet("\n  Verse: soft\nChorus: push the high note hard\nBridge\nOutro\n", 3, 12)
+	want := []string{"Verse: soft", "Chorus: p...", "Bridge"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("noteSnippet = %q, want %q", got, want)
+	}
+	if got := noteSnippet(" \n\n", 3, 12); got != nil {
+		t.Errorf("blank notes gave %q, want nil", got)
+	}
+}
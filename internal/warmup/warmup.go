@@ -0,0 +1,339 @@
+package warmup
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"singAssist/internal/theory"
+)
+
+// Pattern is a warmup exercise shape.
+type Pattern int
+
+const (
+	PatternMajor Pattern = iota
+	PatternChromatic
+	PatternArpeggio
+)
+
+// Patterns lists every Pattern in sub-menu order.
+var Patterns = []Pattern{PatternMajor, PatternChromatic, PatternArpeggio}
+
+// patternNames maps each Pattern to its name in warmup.json.
+var patternNames = map[Pattern]string{
+	PatternMajor:     "major",
+	PatternChromatic: "chromatic",
+	PatternArpeggio:  "arpeggio",
+}
+
+// patternSteps are the semitone offsets from the root going up; the exercise
+// then comes back down the same way.
+var patternSteps = map[Pattern][]int{
+	PatternMajor:     {0, 2, 4, 5, 7, 9, 11, 12},
+	PatternChromatic: {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	PatternArpeggio:  {0, 4, 7, 12},
+}
+
+const (
+	// MinBPM and MaxBPM bound the sub-menu tempo setting.
+	MinBPM = 40.0
+	MaxBPM = 200.0
+
+	// MinOctave and MaxOctave bound the starting octave (C2..C5 covers bass to soprano).
+	MinOctave = 2
+	MaxOctave = 5
+
+	// noteGapFrac is the part of each beat left silent so repeated or
+	// neighbouring notes read as separate blocks.
+	noteGapFrac = 0.15
+)
+
+/*
+String returns the display name of the pattern.
+
+Input:
+  - None
+
+Called by:
+  - ScaleExercise.Label, App.drawWarmupMenu
+
+Task:
+  - Human-readable pattern name
+
+Logic:
+ 1. Capitalize the patternNames entry ("major" -> "Major")
+ 2. Fall back to "Pattern(<n>)" for unknown values
+
+Output:
+  - string: Pattern name (e.g., "Chromatic")
+*/
+func (p Pattern) String() string {
+	if name, ok := patternNames[p]; ok {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	return fmt.Sprintf("Pattern(%d)", int(p))
+}
+
+/*
+ScaleExercise is one generated warmup: a pattern sung up and back down.
+
+Fields:
+  - Pattern: Exercise shape (major, chromatic, arpeggio)
+  - RootMidi: MIDI note the exercise starts and ends on (60 = C4)
+  - BPM: Tempo, one note per beat
+*/
+type ScaleExercise struct {
+	Pattern  Pattern
+	RootMidi int
+	BPM      float64
+}
+
+/*
+Generate renders the exercise as a song pitch contour.
+
+Input:
+  - None
+
+Called by:
+  - App.calibrateAndPlay in warmup mode (used as songPitch, no audio file)
+
+Task:
+  - Produce a reference melody at the analysis resolution
+
+Logic:
+ 1. Walk the pattern's steps up, then back down without repeating the top note
+ 2. Each note lasts one beat (6000/BPM frames of 10ms)
+ 3. Fill the first 85% of the beat with the note frequency, leave the rest silent
+ 4. Append one silent beat at the end so the last note can be scored
+
+Output:
+  - []float64: Pitch in Hz per 10ms frame (0 = silence)
+*/
+func (e ScaleExercise) Generate() []float64 {
+	steps := patternSteps[e.Pattern]
+	seq := append([]int(nil), steps...)
+	for i := len(steps) - 2; i >= 0; i-- {
+		seq = append(seq, steps[i])
+	}
+
+	beatFrames := int(math.Round(6000 / e.BPM))
+	noteFrames := int(float64(beatFrames) * (1 - noteGapFrac))
+	pitch := make([]float64, (len(seq)+1)*beatFrames)
+	for n, step := range seq {
+		freq := MidiToFreq(e.RootMidi + step)
+		start := n * beatFrames
+		for f := start; f < start+noteFrames; f++ {
+			pitch[f] = freq
+		}
+	}
+	return pitch
+}
+
+/*
+Label describes the exercise for the playing screen.
+
+Input:
+  - None
+
+Called by:
+  - App.drawPlayingMode in warmup mode
+
+Task:
+  - Show what is being practised
+
+Logic:
+ 1. Name the root note without octave, then pattern and tempo
+
+Output:
+  - string: e.g. "C Major, 120 BPM"
+*/
+func (e ScaleExercise) Label() string {
+	root, _ := theory.FreqToNote(MidiToFreq(e.RootMidi))
+	return fmt.Sprintf("%s %s, %.0f BPM", root, e.Pattern, e.BPM)
+}
+
+/*
+MidiToFreq converts a MIDI note number to Hz.
+
+Input:
+  - midi: int - MIDI note (69 = A4)
+
+Called by:
+  - ScaleExercise.Generate, ScaleExercise.Label
+
+Task:
+  - Equal-tempered A440 tuning
+
+Logic:
+ 1. 440 * 2^((midi-69)/12)
+
+Output:
+  - float64: Frequency in Hz
+*/
+func MidiToFreq(midi int) float64 {
+	return 440 * math.Pow(2, float64(midi-69)/12)
+}
+
+/*
+WarmupConfig is the on-disk warmup setting (warmup.json in the song folder).
+
+Fields:
+  - Pattern: "major", "chromatic" or "arpeggio"
+  - Octave: Starting octave of the C root (4 = C4)
+  - BPM: Tempo
+*/
+type WarmupConfig struct {
+	Pattern string  `json:"pattern"`
+	Octave  int     `json:"octave"`
+	BPM     float64 `json:"bpm"`
+}
+
+/*
+DefaultConfig returns the warmup used when no warmup.json exists.
+
+Input:
+  - None
+
+Called by:
+  - LoadConfig
+
+Task:
+  - A comfortable middle-range starting point
+
+Logic:
+ 1. C4 major scale at 120 BPM
+
+Output:
+  - WarmupConfig: Default settings
+*/
+func DefaultConfig() WarmupConfig {
+	return WarmupConfig{Pattern: patternNames[PatternMajor], Octave: 4, BPM: 120}
+}
+
+/*
+LoadConfig reads warmup.json, falling back to the defaults.
+
+Input:
+  - path: string - The song's warmup.json (config.SongPaths.WarmupFile)
+
+Called by:
+  - App.openWarmupMenu
+
+Task:
+  - Remember the user's last warmup setting
+
+Logic:
+ 1. Start from DefaultConfig
+ 2. Missing or unreadable file: return the defaults
+ 3. Unmarshal over the defaults so missing keys keep their default values
+
+Output:
+  - WarmupConfig: Loaded settings
+  - error: Read or decode error (the defaults are still returned)
+*/
+func LoadConfig(path string) (WarmupConfig, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return DefaultConfig(), err
+	}
+	return cfg, nil
+}
+
+/*
+SaveConfig writes warmup.json.
+
+Input:
+  - path: string - The song's warmup.json
+  - cfg: WarmupConfig - Settings to store
+
+Called by:
+  - App.handleWarmupMenuInput when the exercise is started
+
+Task:
+  - Persist the sub-menu choice for next time
+
+Logic:
+ 1. Marshal with indentation
+ 2. Write to a temp file and rename it over the original
+
+Output:
+  - error: nil on success
+*/
+func SaveConfig(path string, cfg WarmupConfig) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+/*
+Exercise turns the config into a ScaleExercise.
+
+Input:
+  - None
+
+Called by:
+  - App.handleWarmupMenuInput, App.drawWarmupMenu
+
+Task:
+  - Validate and clamp the stored settings
+
+Logic:
+ 1. Look up the pattern by name, unknown names fall back to major
+ 2. Clamp octave to MinOctave..MaxOctave and BPM to MinBPM..MaxBPM
+ 3. Root is C of that octave: MIDI 12*(octave+1)
+
+Output:
+  - ScaleExercise: Exercise ready to Generate
+*/
+func (c WarmupConfig) Exercise() ScaleExercise {
+	pattern := PatternMajor
+	for p, name := range patternNames {
+		if name == strings.ToLower(c.Pattern) {
+			pattern = p
+		}
+	}
+	octave := max(MinOctave, min(MaxOctave, c.Octave))
+	return ScaleExercise{
+		Pattern:  pattern,
+		RootMidi: 12 * (octave + 1),
+		BPM:      max(MinBPM, min(MaxBPM, c.BPM)),
+	}
+}
+
+/*
+PatternName returns the warmup.json name of a pattern.
+
+Input:
+  - p: Pattern - Exercise shape
+
+Called by:
+  - App.handleWarmupMenuInput when cycling the pattern
+
+Task:
+  - Map back from Pattern to the config string
+
+Logic:
+ 1. Look up patternNames
+
+Output:
+  - string: e.g. "chromatic"
+*/
+func PatternName(p Pattern) string {
+	return patternNames[p]
+}
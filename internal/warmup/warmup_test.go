@@ -0,0 +1,76 @@
+package warmup
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateChromatic(t *testing.T) {
+	// At 120 BPM a beat is 50 frames: 42 frames of note, 8 of silence.
+	e := ScaleExercise{Pattern: PatternChromatic, RootMidi: 60, BPM: 120}
+	pitch := e.Generate()
+
+	// 12 notes up, 11 back down, then one silent beat.
+	if len(pitch) != 24*50 {
+		t.Fatalf("got %d frames, want %d", len(pitch), 24*50)
+	}
+	for n := 0; n < 23; n++ {
+		step := n
+		if n >= 12 {
+			step = 22 - n
+		}
+		want := MidiToFreq(60 + step)
+		for f := n * 50; f < n*50+50; f++ {
+			if f < n*50+42 && math.Abs(pitch[f]-want) > 1e-9 {
+				t.Fatalf("note %d frame %d = %.2f Hz, want %.2f Hz", n, f, pitch[f], want)
+			}
+			if f >= n*50+42 && pitch[f] != 0 {
+				t.Fatalf("note %d frame %d = %.2f Hz, want the gap to be silent", n, f, pitch[f])
+			}
+		}
+	}
+	for n := 1; n < 12; n++ {
+		if ratio := pitch[n*50] / pitch[(n-1)*50]; math.Abs(ratio-math.Pow(2, 1.0/12)) > 1e-9 {
+			t.Errorf("note %d is %.4f times note %d, want one semitone", n, ratio, n-1)
+		}
+	}
+	for f := 23 * 50; f < len(pitch); f++ {
+		if pitch[f] != 0 {
+			t.Fatalf("frame %d of the closing beat = %.2f Hz, want silence", f, pitch[f])
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	e := ScaleExercise{Pattern: PatternMajor, RootMidi: 60, BPM: 120}
+	if got := e.Label(); got != "C Major, 120 BPM" {
+		t.Errorf("Label = %q, want %q", got, "C Major, 120 BPM")
+	}
+}
+
+func TestConfigRoundTripAndClamping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warmup.json")
+	if cfg, err := LoadConfig(path); err != nil || cfg != DefaultConfig() {
+		t.Errorf("LoadConfig without a file = %+v, %v; want the defaults", cfg, err)
+	}
+
+	want := WarmupConfig{Pattern: "chromatic", Octave: 3, BPM: 90}
+	if err := SaveConfig(path, want); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	if got, err := LoadConfig(path); err != nil || got != want {
+		t.Errorf("LoadConfig = %+v, %v; want %+v", got, err, want)
+	}
+
+	os.WriteFile(path, []byte("{"), 0644)
+	if cfg, err := LoadConfig(path); err == nil || cfg != DefaultConfig() {
+		t.Errorf("LoadConfig on bad JSON = %+v, %v; want the defaults and an error", cfg, err)
+	}
+
+	ex := WarmupConfig{Pattern: "Bogus", Octave: 9, BPM: 10}.Exercise()
+	if ex.Pattern != PatternMajor || ex.RootMidi != 12*(MaxOctave+1) || ex.BPM != MinBPM {
+		t.Errorf("Exercise = %+v, want major, C%d, %v BPM", ex, MaxOctave, MinBPM)
+	}
+}
@@ -0,0 +1,184 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"singAssist/internal/app"
+	"singAssist/internal/config"
+	"singAssist/internal/logging"
+)
+
+/*
+StartHTTPHUD serves the score HUD as a web page, for a second monitor.
+
+Input:
+  - a: *app.App - Running application to report on
+  - port: int - TCP port to listen on (loopback only)
+
+Called by:
+  - main when -web-hud is given
+
+Task:
+  - Ebiten has one window, so the HUD is detached into a browser instead
+
+Logic:
+ 1. Build the handler with NewHUDHandler over a.LiveStatus
+ 2. Listen on 127.0.0.1 only, so the HUD is not exposed to the network
+ 3. Listen in a goroutine; a failure (e.g. port in use) is logged, the game
+    keeps running
+
+Output:
+  - None (the server runs until the program exits)
+*/
+func StartHTTPHUD(a *app.App, port int) {
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	handler := NewHUDHandler(a.LiveStatus, config.WebHUDInterval)
+	go func() {
+		logging.Infof("Web HUD on http://%s", addr)
+		if err := http.ListenAndServe(addr, handler); err != nil {
+			logging.Warnf("Web HUD stopped: %v", err)
+		}
+	}()
+}
+
+/*
+NewHUDHandler returns the HUD page and its event stream.
+
+Input:
+  - status: func() app.LiveStatus - Snapshot source (App.LiveStatus)
+  - interval: time.Duration - Time between events
+
+Called by:
+  - StartHTTPHUD
+
+Task:
+  - Keep the routes in one place, independent of a real App
+
+Logic:
+ 1. "/" serves hudPage
+ 2. "/events" streams status() with serveEvents
+
+Output:
+  - http.Handler: Router for both routes
+*/
+func NewHUDHandler(status func() app.LiveStatus, interval time.Duration) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, hudPage)
+	})
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		serveEvents(w, r, status, interval)
+	})
+	return mux
+}
+
+/*
+serveEvents streams LiveStatus snapshots as Server-Sent Events.
+
+Input:
+  - w: http.ResponseWriter - Must support http.Flusher
+  - r: *http.Request - Its context ends the stream when the browser leaves
+  - status: func() app.LiveStatus - Snapshot source
+  - interval: time.Duration - Time between events
+
+Called by:
+  - NewHUDHandler for "/events"
+
+Task:
+  - Push updates without the page polling
+
+Logic:
+ 1. Reject writers that cannot flush (500)
+ 2. Send the event-stream headers
+ 3. Right away and then every interval: write "data: <json>\n\n" and flush
+ 4. Stop when the request context is done or a write fails
+
+Output:
+  - None (writes to w)
+*/
+func serveEvents(w http.ResponseWriter, r *http.Request, status func() app.LiveStatus, interval time.Duration) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		data, err := json.Marshal(status())
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hudPage shows the note names and a cents meter, updated from /events; the
+// mic note turns green on the status's hit flag.
+const hudPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>SingAssist HUD</title>
+<style>
+  body { background: #000; color: #fff; font-family: monospace; margin: 0; padding: 2em; }
+  h1 { font-size: 1.2em; color: #a0a0a0; }
+  .notes { display: flex; gap: 4em; margin: 1em 0 2em; }
+  .label { color: #a0a0a0; }
+  .note { font-size: 5em; }
+  #song { color: #6496ff; }
+  #mic.hit { color: #32ff32; }
+  #mic.miss { color: #ffc832; }
+  .meter { position: relative; height: 24px; width: 100%; max-width: 600px; background: #14141a; border: 1px solid #464650; }
+  .meter .centre { position: absolute; left: 50%; top: 0; bottom: 0; width: 2px; background: #646464; }
+  #needle { position: absolute; top: 0; bottom: 0; width: 6px; margin-left: -3px; left: 50%; background: #fff; transition: left 0.1s; }
+  .score { font-size: 3em; margin-top: 1em; color: #ffdc00; }
+</style>
+</head>
+<body>
+<h1 id="title">SingAssist</h1>
+<div class="notes">
+  <div><div class="label">SONG</div><div class="note" id="song">-</div></div>
+  <div><div class="label">YOU</div><div class="note" id="mic">-</div></div>
+</div>
+<div class="label">-50 cents &nbsp; flat / sharp &nbsp; +50 cents</div>
+<div class="meter"><div class="centre"></div><div id="needle"></div></div>
+<div class="score" id="score">0.0%</div>
+<script>
+  const events = new EventSource("/events");
+  events.onmessage = (e) => {
+    const s = JSON.parse(e.data);
+    document.getElementById("title").textContent = s.playing ? "SingAssist - " + s.song : "SingAssist - waiting for a song";
+    document.getElementById("song").textContent = s.song_note;
+    const mic = document.getElementById("mic");
+    mic.textContent = s.mic_note;
+    mic.className = s.mic_pitch > 0 ? (s.hit ? "hit" : "miss") : "";
+    const cents = Math.max(-50, Math.min(50, s.cents));
+    document.getElementById("needle").style.left = (50 + cents) + "%";
+    document.getElementById("score").textContent = s.score.toFixed(1) + "%";
+  };
+</script>
+</body>
+</html>
+`
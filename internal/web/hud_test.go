@@ -0,0 +1,108 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"singAssist/internal/app"
+)
+
+func TestHUDEventsStreamJSON(t *testing.T) {
+	want := app.LiveStatus{
+		Song:      "Test Song",
+		Playing:   true,
+		MicPitch:  440,
+		MicNote:   "A4",
+		SongPitch: 440,
+		SongNote:  "A4",
+		Hit:       true,
+		Score:     87.5,
+	}
+	srv := httptest.NewServer(NewHUDHandler(func() app.LiveStatus { return want }, 10*time.Millisecond))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	for i := 0; i < 2; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("event %d: %v", i, err)
+		}
+		data, ok := strings.CutPrefix(strings.TrimSuffix(line, "\n"), "data: ")
+		if !ok {
+			t.Fatalf("event %d = %q, want a data: line", i, line)
+		}
+		var got app.LiveStatus
+		if err := json.Unmarshal([]byte(data), &got); err != nil {
+			t.Fatalf("event %d is not valid JSON: %v (%s)", i, err, data)
+		}
+		if got != want {
+			t.Errorf("event %d = %+v, want %+v", i, got, want)
+		}
+		if blank, err := r.ReadString('\n'); err != nil || blank != "\n" {
+			t.Fatalf("event %d not terminated by a blank line: %q, %v", i, blank, err)
+		}
+	}
+}
+
+func TestHUDEventsFieldNames(t *testing.T) {
+	srv := httptest.NewServer(NewHUDHandler(func() app.LiveStatus { return app.LiveStatus{Cents: -12} }, time.Hour))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"song", "playing", "mic_pitch", "mic_note", "song_pitch", "song_note", "cents", "hit", "score"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("event has no %q field (the page reads it): %s", name, line)
+		}
+	}
+}
+
+func TestHUDPage(t *testing.T) {
+	srv := httptest.NewServer(NewHUDHandler(func() app.LiveStatus { return app.LiveStatus{} }, time.Hour))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `new EventSource("/events")`) {
+		t.Errorf("GET / = %d, want the HUD page subscribing to /events", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /missing = %d, want 404", resp.StatusCode)
+	}
+}
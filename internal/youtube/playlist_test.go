@@ -0,0 +1,152 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// fakeRunner answers the playlist listing from titles (stdout) and warnings
+// (stderr only), and "downloads" every search except those in fail by
+// writing the -o file.
+type fakeRunner struct {
+	titles   string
+	warnings string
+	fail     map[string]bool
+	calls    [][]string
+}
+
+func (f *fakeRunner) CombinedOutput(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if slices.Contains(args, "--flat-playlist") {
+		return []byte(f.warnings + f.titles), nil
+	}
+	query := strings.TrimPrefix(args[0], "ytsearch1:")
+	if f.fail[query] {
+		return []byte("ERROR: unavailable"), errors.New("exit status 1")
+	}
+	out := args[slices.Index(args, "-o")+1]
+	if err := os.WriteFile(out, []byte("mp3"), 0644); err != nil {
+		return nil, err
+	}
+	return []byte(infoPrefix + "\tArtist\t" + query + "\tPop\n"), nil
+}
+
+func (f *fakeRunner) Output(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if f.titles == "" {
+		return nil, errors.New("exit status 1: ERROR: not a playlist")
+	}
+	return []byte(f.titles), nil
+}
+
+// useFakeRunner swaps Runner for f in a fresh working directory, with
+// online mode and no retry waits.
+func useFakeRunner(t *testing.T, f *fakeRunner) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	oldRunner, oldDelay := Runner, retryDelay
+	Runner, retryDelay = f, 0
+	t.Cleanup(func() { Runner, retryDelay = oldRunner, oldDelay })
+}
+
+func TestDownloadPlaylist(t *testing.T) {
+	f := &fakeRunner{
+		titles:   "First Song\nSecond Song\n",
+		warnings: "WARNING: [youtube] Some formats are missing\n",
+	}
+	useFakeRunner(t, f)
+
+	type progress struct {
+		n, total int
+		name     string
+	}
+	var got []progress
+	dirs, err := DownloadPlaylist("https://www.youtube.com/playlist?list=PL1", func(n, total int, name string) {
+		got = append(got, progress{n, total, name})
+	})
+	if err != nil {
+		t.Fatalf("DownloadPlaylist: %v", err)
+	}
+
+	wantDirs := []string{filepath.Join(config.SongsDir, "First_Song"), filepath.Join(config.SongsDir, "Second_Song")}
+	if !slices.Equal(dirs, wantDirs) {
+		t.Errorf("dirs = %q, want %q", dirs, wantDirs)
+	}
+	wantProgress := []progress{{1, 2, "First Song"}, {2, 2, "Second Song"}}
+	if !slices.Equal(got, wantProgress) {
+		t.Errorf("progress = %v, want %v", got, wantProgress)
+	}
+	wantList := []string{"yt-dlp", "--flat-playlist", "--print", "%(title)s", "https://www.youtube.com/playlist?list=PL1"}
+	if len(f.calls) == 0 || !slices.Equal(f.calls[0], wantList) {
+		t.Errorf("first command = %q, want %q", f.calls[0], wantList)
+	}
+	for _, dir := range wantDirs {
+		if _, err := os.Stat(filepath.Join(dir, "song.mp3")); err != nil {
+			t.Errorf("song file missing: %v", err)
+		}
+	}
+}
+
+func TestDownloadPlaylistSkipsFailedTracks(t *testing.T) {
+	f := &fakeRunner{titles: "Good\nGone\nAlso Good\n", fail: map[string]bool{"Gone": true}}
+	useFakeRunner(t, f)
+
+	dirs, err := DownloadPlaylist("https://www.youtube.com/playlist?list=PL2", nil)
+	if err != nil {
+		t.Fatalf("DownloadPlaylist: %v", err)
+	}
+	want := []string{filepath.Join(config.SongsDir, "Good"), filepath.Join(config.SongsDir, "Also_Good")}
+	if !slices.Equal(dirs, want) {
+		t.Errorf("dirs = %q, want %q", dirs, want)
+	}
+
+	attempts := 0
+	for _, c := range f.calls {
+		if len(c) > 1 && c[1] == "ytsearch1:Gone" {
+			attempts++
+		}
+	}
+	if attempts != 3 {
+		t.Errorf("failed track tried %d times, want 3", attempts)
+	}
+}
+
+func TestDownloadPlaylistErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		f    *fakeRunner
+	}{
+		{"listing fails", &fakeRunner{}},
+		{"empty playlist", &fakeRunner{titles: "\n\n"}},
+		{"every track fails", &fakeRunner{titles: "A\nB\n", fail: map[string]bool{"A": true, "B": true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeRunner(t, tt.f)
+			if dirs, err := DownloadPlaylist("https://www.youtube.com/playlist?list=PL3", nil); err == nil {
+				t.Errorf("DownloadPlaylist = %q, want an error", dirs)
+			}
+		})
+	}
+}
+
+func TestDownloadPlaylistOffline(t *testing.T) {
+	f := &fakeRunner{titles: "A\n"}
+	useFakeRunner(t, f)
+	config.SetOfflineMode(true)
+	t.Cleanup(func() { config.SetOfflineMode(false) })
+
+	if _, err := DownloadPlaylist("https://www.youtube.com/playlist?list=PL4", nil); !errors.Is(err, errOffline) {
+		t.Errorf("DownloadPlaylist offline = %v, want errOffline", err)
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("ran %q while offline", f.calls)
+	}
+}
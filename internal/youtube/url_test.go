@@ -0,0 +1,157 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+// mp3Fixture stands in for a small MP3; DownloadFromURL only copies the bytes.
+var mp3Fixture = []byte("ID3\x04\x00\x00\x00\x00\x00\x00\xff\xfb\x90\x64fake mp3 frame")
+
+// newAudioServer serves mp3Fixture at /Kasoor%20(Live).mp3, the same bytes as
+// FLAC at /download, and /hop/N redirects N times before reaching the MP3.
+func newAudioServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch p := r.URL.Path; {
+		case p == "/Kasoor (Live).mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.Write(mp3Fixture)
+		case p == "/download":
+			w.Header().Set("Content-Type", "audio/flac")
+			w.Write(mp3Fixture)
+		case strings.HasPrefix(p, "/hop/"):
+			n, _ := strconv.Atoi(strings.TrimPrefix(p, "/hop/"))
+			if n == 0 {
+				http.Redirect(w, r, "/Kasoor%20(Live).mp3", http.StatusFound)
+				return
+			}
+			http.Redirect(w, r, fmt.Sprintf("/hop/%d", n-1), http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// checkSongFile checks that dir holds file with the fixture bytes.
+func checkSongFile(t *testing.T, dir, file string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		t.Fatalf("reading %s: %v", file, err)
+	}
+	if string(data) != string(mp3Fixture) {
+		t.Errorf("%s holds %q, want the served bytes", file, data)
+	}
+}
+
+func TestDownloadFromURL(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := newAudioServer(t)
+
+	dir, err := DownloadFromURL(srv.URL + "/Kasoor%20(Live).mp3")
+	if err != nil {
+		t.Fatalf("DownloadFromURL: %v", err)
+	}
+	if want := filepath.Join(config.SongsDir, "Kasoor_Live"); dir != want {
+		t.Errorf("song dir = %q, want %q", dir, want)
+	}
+	checkSongFile(t, dir, "song.mp3")
+}
+
+func TestDownloadFromURLGenericName(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := newAudioServer(t)
+
+	dir, err := DownloadFromURL(srv.URL + "/download")
+	if err != nil {
+		t.Fatalf("DownloadFromURL: %v", err)
+	}
+	u, _ := url.Parse(srv.URL + "/download")
+	if want := filepath.Join(config.SongsDir, urlSongName(u)); dir != want || !strings.HasPrefix(filepath.Base(dir), "url_") {
+		t.Errorf("song dir = %q, want %q named by the URL hash", dir, want)
+	}
+	checkSongFile(t, dir, "song.flac")
+}
+
+func TestDownloadFromURLRedirects(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := newAudioServer(t)
+
+	// /hop/4 is five redirects: hop/4..hop/0, then the file.
+	dir, err := DownloadFromURL(srv.URL + "/hop/4")
+	if err != nil {
+		t.Fatalf("DownloadFromURL after 5 redirects: %v", err)
+	}
+	if filepath.Base(dir) != "Kasoor_Live" {
+		t.Errorf("song dir = %q, want it named after the final URL", dir)
+	}
+
+	if _, err := DownloadFromURL(srv.URL + "/hop/5"); err == nil {
+		t.Error("DownloadFromURL followed 6 redirects, want an error")
+	}
+}
+
+func TestDownloadFromURLErrors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := newAudioServer(t)
+
+	for _, rawURL := range []string{srv.URL + "/missing.mp3", "ftp://example.com/a.mp3", "not a url"} {
+		if _, err := DownloadFromURL(rawURL); err == nil {
+			t.Errorf("DownloadFromURL(%q) succeeded, want an error", rawURL)
+		}
+	}
+	if entries, _ := os.ReadDir(config.SongsDir); len(entries) != 0 {
+		t.Errorf("failed downloads left %d song folders", len(entries))
+	}
+
+	config.SetOfflineMode(true)
+	t.Cleanup(func() { config.SetOfflineMode(false) })
+	if _, err := DownloadFromURL(srv.URL + "/Kasoor%20(Live).mp3"); !errors.Is(err, errOffline) {
+		t.Errorf("DownloadFromURL offline = %v, want errOffline", err)
+	}
+}
+
+func TestDownloadFromURLSameFileName(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("audio of " + r.URL.Path))
+	}))
+	t.Cleanup(srv.Close)
+
+	first, err := DownloadFromURL(srv.URL + "/album1/Kasoor.mp3")
+	if err != nil {
+		t.Fatalf("first download: %v", err)
+	}
+	second, err := DownloadFromURL(srv.URL + "/album2/Kasoor.mp3")
+	if err != nil {
+		t.Fatalf("second download: %v", err)
+	}
+	if filepath.Base(first) != "Kasoor" || filepath.Base(second) != "Kasoor_2" {
+		t.Fatalf("song dirs = %q, %q; want Kasoor and Kasoor_2", first, second)
+	}
+	for dir, want := range map[string]string{first: "audio of /album1/Kasoor.mp3", second: "audio of /album2/Kasoor.mp3"} {
+		if data, _ := os.ReadFile(filepath.Join(dir, "song.mp3")); string(data) != want {
+			t.Errorf("%s holds %q, want %q", dir, data, want)
+		}
+	}
+
+	again, err := DownloadFromURL(srv.URL + "/album2/Kasoor.mp3")
+	if err != nil || again != second {
+		t.Errorf("same URL again = %q, %v; want its folder %q", again, err, second)
+	}
+}
@@ -1,16 +1,57 @@
 package youtube
 
 import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
+	"singAssist/internal/audio"
 	"singAssist/internal/config"
+	"singAssist/internal/logging"
 )
 
+const (
+	// sourceFile records which query or URL a downloaded song folder belongs to.
+	sourceFile = "source.txt"
+	// downloadTimeout bounds a single yt-dlp run so a stalled connection cannot hang the app.
+	downloadTimeout = 5 * time.Minute
+	// maxRedirects is how many HTTP redirects DownloadFromURL follows.
+	maxRedirects = 5
+	// infoPrefix marks the line yt-dlp prints with the artist, title and genre
+	// of a finished download (fields separated by tabs).
+	infoPrefix = "SINGASSIST_INFO"
+)
+
+// errOffline is returned by every download while offline mode is on.
+var errOffline = errors.New("downloads are disabled in offline mode (-offline)")
+
+// genericNames are URL file names that say nothing about the song; such URLs
+// are named by hash instead.
+var genericNames = map[string]bool{
+	"downloaded_song": true, "download": true, "file": true, "audio": true,
+	"stream": true, "index": true, "track": true, "song": true, "media": true,
+}
+
+// Runner executes yt-dlp; replace it to run the package without the real tool.
+var Runner audio.CommandRunner = audio.ExecRunner{}
+
+// retryDelay shifted left by the attempt number is how long Download waits
+// before retrying (2s, then 4s).
+var retryDelay = time.Second
+
 /*
 Download downloads an audio file from YouTube using yt-dlp.
 
@@ -26,53 +67,362 @@ Task:
   - Save to songs/<sanitized_name>/song.mp3
 
 Logic:
- 1. Sanitize query to create valid folder name
- 2. Create song directory using config.EnsureSongDir
+ 1. Fail straight away in offline mode (config.IsOfflineMode); sanitize query
+    to create valid folder name, suffixed if another song owns it
+ 2. Create song directory using config.EnsureSongDir and record the query in source.txt
  3. Check if song already exists, skip download if so
- 4. Execute yt-dlp with: ytsearch1:<query>, extract audio, mp3 format, best quality
- 5. Verify downloaded file exists
+ 4. Execute yt-dlp (via Runner) with: ytsearch1:<query>, extract audio, mp3 format, best quality,
+    printing the artist, title and genre once the file is in place
+ 5. Kill yt-dlp if it runs longer than downloadTimeout
+ 6. On failure or timeout, retry up to 3 times with exponential backoff
+ 7. Verify downloaded file exists
+ 8. Save the printed tags to info.json (config.SaveSongInfo; failure only logs)
 
 Output:
   - string: Song directory path (e.g., "songs/Never_Gonna_Give_You_Up")
   - error: nil on success, wrapped error with details on failure
 */
 func Download(query string) (string, error) {
-	songName := sanitizeName(query)
+	if config.IsOfflineMode() {
+		return "", errOffline
+	}
+	songName := uniqueSongName(sanitizeName(query), query)
 
 	songDir, err := config.EnsureSongDir(songName)
 	if err != nil {
 		return "", fmt.Errorf("failed to create song directory: %w", err)
 	}
 
+	if err := os.WriteFile(filepath.Join(songDir, sourceFile), []byte(query+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to record song source: %w", err)
+	}
+
 	paths := config.GetSongPaths(songDir)
 
 	if _, err := os.Stat(paths.SongFile); err == nil {
-		fmt.Printf("Song already exists: %s\n", paths.SongFile)
+		logging.Infof("Song already exists: %s", paths.SongFile)
 		return songDir, nil
 	}
 
-	cmd := exec.Command("yt-dlp",
-		fmt.Sprintf("ytsearch1:%s", query),
-		"-x",
-		"--audio-format", "mp3",
-		"--audio-quality", "0",
-		"-o", paths.SongFile,
-	)
-
-	fmt.Printf("Downloading: %s\n", query)
-	output, err := cmd.CombinedOutput()
+	var output []byte
+	maxRetries := 3
+
+	logging.Infof("Downloading: %s", query)
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := retryDelay << attempt
+			logging.Warnf("Retrying in %v (attempt %d/%d)...", wait, attempt+1, maxRetries)
+			time.Sleep(wait)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+		output, err = Runner.CombinedOutput(ctx, "yt-dlp",
+			fmt.Sprintf("ytsearch1:%s", query),
+			"-x",
+			"--audio-format", "mp3",
+			"--audio-quality", "0",
+			"-o", paths.SongFile,
+			"--print", "after_move:"+infoPrefix+"\t%(artist)s\t%(title)s\t%(genre)s",
+		)
+		timedOut := ctx.Err() == context.DeadlineExceeded
+		cancel()
+
+		if timedOut {
+			err = fmt.Errorf("download timed out after %v", downloadTimeout)
+			continue
+		}
+		if err == nil {
+			break
+		}
+	}
 	if err != nil {
-		return "", fmt.Errorf("yt-dlp failed: %w\nOutput: %s", err, string(output))
+		return "", fmt.Errorf("yt-dlp failed after %d attempts: %w\nOutput: %s", maxRetries, err, string(output))
 	}
 
 	if _, err := os.Stat(paths.SongFile); os.IsNotExist(err) {
 		return "", fmt.Errorf("download failed - file not created")
 	}
 
-	fmt.Printf("Downloaded to: %s\n", paths.SongFile)
+	logging.Infof("Downloaded to: %s", paths.SongFile)
+	if info, ok := parseSongInfo(string(output)); ok {
+		if err := config.SaveSongInfo(songDir, info); err != nil {
+			logging.Warnf("Could not save song info: %v", err)
+		}
+	}
 	return songDir, nil
 }
 
+/*
+parseSongInfo reads the tags yt-dlp printed after a download.
+
+Input:
+  - output: string - Combined yt-dlp output
+
+Called by:
+  - Download after a successful run
+
+Task:
+  - Turn the infoPrefix line into a SongInfo
+
+Logic:
+ 1. Find the line starting with infoPrefix and split it on tabs
+ 2. yt-dlp prints "NA" for fields it does not know; treat those as empty
+ 3. Report ok only if the artist or title is known
+
+Output:
+  - config.SongInfo: Parsed tags
+  - bool: true if there is anything worth saving
+*/
+func parseSongInfo(output string) (config.SongInfo, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) != 4 || fields[0] != infoPrefix {
+			continue
+		}
+		field := func(v string) string {
+			if v = strings.TrimSpace(v); v == "NA" {
+				return ""
+			}
+			return v
+		}
+		info := config.SongInfo{Artist: field(fields[1]), Title: field(fields[2]), Genre: field(fields[3])}
+		return info, info.Artist != "" || info.Title != ""
+	}
+	return config.SongInfo{}, false
+}
+
+/*
+DownloadPlaylist downloads every track of a YouTube playlist.
+
+Input:
+  - url: string - Playlist URL
+  - onProgress: func(n, total int, name string) - Called before each track (can be nil)
+
+Called by:
+  - main.main when user provides -playlist flag
+
+Task:
+  - Batch-import a playlist into the songs folder
+
+Logic:
+ 1. Fail straight away in offline mode; list the track titles with
+    yt-dlp --flat-playlist --print "%(title)s", reading stdout only (Runner.Output)
+    so warnings yt-dlp prints on stderr are not taken for titles
+ 2. For each title: report progress, then Download it by title
+ 3. Log failed tracks (logging.Warnf) and continue with the rest
+ 4. Error only if the listing fails or every track fails
+
+Output:
+  - []string: Song directories created (or already present), in playlist order
+  - error: nil if at least one track was downloaded
+*/
+func DownloadPlaylist(url string, onProgress func(n, total int, name string)) ([]string, error) {
+	if config.IsOfflineMode() {
+		return nil, errOffline
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	output, err := Runner.Output(ctx, "yt-dlp", "--flat-playlist", "--print", "%(title)s", url)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlist: %w", err)
+	}
+
+	var titles []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if t := strings.TrimSpace(line); t != "" {
+			titles = append(titles, t)
+		}
+	}
+	if len(titles) == 0 {
+		return nil, fmt.Errorf("playlist has no tracks")
+	}
+
+	var dirs []string
+	for i, title := range titles {
+		if onProgress != nil {
+			onProgress(i+1, len(titles), title)
+		}
+		dir, err := Download(title)
+		if err != nil {
+			logging.Warnf("Skipping %q: %v", title, err)
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("all %d playlist downloads failed", len(titles))
+	}
+	return dirs, nil
+}
+
+/*
+DownloadFromURL fetches a direct audio link and imports it as a song.
+
+Input:
+  - rawURL: string - HTTP or HTTPS link to an MP3/FLAC/OGG/WAV file
+
+Called by:
+  - main.main when user provides -url flag
+
+Task:
+  - Practice with CDN-hosted audio without yt-dlp
+
+Logic:
+ 1. Fail straight away in offline mode; parse the URL, only http and https are accepted
+ 2. GET it (following up to maxRedirects redirects, bounded by downloadTimeout)
+ 3. Require a 200 response
+ 4. Name the song with urlSongName of the final (post-redirect) URL, suffixed
+    by uniqueSongName if a different URL owns that folder; take the extension
+    from its path, else from the Content-Type, else .mp3
+ 5. Stream the body to <temp dir>/<name><ext>
+ 6. Record rawURL in the folder's source.txt and copy the file in with
+    importInto, then remove the temp dir
+
+Output:
+  - string: Song directory path (e.g., "songs/my_track")
+  - error: nil on success, wrapped error on request, status or write failure
+*/
+func DownloadFromURL(rawURL string) (string, error) {
+	if config.IsOfflineMode() {
+		return "", errOffline
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("not an http(s) URL: %q", rawURL)
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	logging.Infof("Downloading: %s", rawURL)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	final := resp.Request.URL
+	ext := strings.ToLower(path.Ext(final.Path))
+	if !config.IsSongFile(final.Path) {
+		ext = extFromContentType(resp.Header.Get("Content-Type"))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "singassist-url-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	songName := uniqueSongName(urlSongName(final), rawURL)
+	tmpPath := filepath.Join(tmpDir, songName+ext)
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return "", fmt.Errorf("download interrupted: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	songDir, err := config.EnsureSongDir(songName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create song directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(songDir, sourceFile), []byte(rawURL+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to record song source: %w", err)
+	}
+	return importInto(tmpPath, songDir)
+}
+
+/*
+urlSongName derives a song folder name from a download URL.
+
+Input:
+  - u: *url.URL - Parsed download URL
+
+Called by:
+  - DownloadFromURL
+
+Task:
+  - Readable names for links like .../Kasoor%20(Live).mp3
+
+Logic:
+ 1. Take the last path element, URL-decoded, with its extension stripped
+ 2. Sanitize it with sanitizeName
+ 3. If that is empty or generic (genericNames, e.g. "download"): use
+    "url_" plus the first 10 hex digits of the URL's SHA1
+
+Output:
+  - string: Folder name
+*/
+func urlSongName(u *url.URL) string {
+	base := path.Base(u.Path)
+	if decoded, err := url.PathUnescape(base); err == nil {
+		base = decoded
+	}
+	name := sanitizeName(strings.TrimSuffix(base, path.Ext(base)))
+
+	if genericNames[strings.ToLower(name)] {
+		sum := sha1.Sum([]byte(u.String()))
+		name = "url_" + hex.EncodeToString(sum[:])[:10]
+	}
+	return name
+}
+
+/*
+extFromContentType maps an audio MIME type to a song file extension.
+
+Input:
+  - contentType: string - Content-Type header (e.g. "audio/mpeg; charset=binary")
+
+Called by:
+  - DownloadFromURL when the URL path has no audio extension
+
+Task:
+  - Pick the decoder for extension-less links
+
+Logic:
+ 1. Parse the media type
+ 2. audio/flac or audio/x-flac -> .flac, audio/ogg or audio/vorbis -> .ogg,
+    audio/wav, audio/x-wav or audio/wave -> .wav
+ 3. Anything else -> .mp3
+
+Output:
+  - string: ".mp3", ".flac", ".ogg" or ".wav"
+*/
+func extFromContentType(contentType string) string {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch mt {
+	case "audio/flac", "audio/x-flac":
+		return ".flac"
+	case "audio/ogg", "audio/vorbis", "application/ogg":
+		return ".ogg"
+	case "audio/wav", "audio/x-wav", "audio/wave", "audio/vnd.wave":
+		return ".wav"
+	}
+	return ".mp3"
+}
+
 /*
 sanitizeName converts a search query into a valid filesystem folder name.
 
@@ -83,28 +433,39 @@ Called by:
   - Download when creating folder name from YouTube query
 
 Task:
-  - Remove special characters
+  - Remove special characters while keeping Unicode letters and digits
   - Replace spaces with underscores
   - Limit length to 50 characters
 
 Logic:
- 1. Remove all non-alphanumeric characters except spaces using regex
- 2. Trim leading/trailing whitespace
- 3. Replace remaining spaces with underscores
- 4. Truncate to 50 characters if longer
+ 1. Keep runes that are letters or digits (unicode.IsLetter/IsDigit)
+ 2. Turn whitespace runs into single underscores, drop everything else
+ 3. Trim leading/trailing underscores
+ 4. Truncate to 50 runes if longer (never splits a multi-byte character)
  5. Return "downloaded_song" if result is empty
 
 Output:
   - string: Sanitized folder name safe for filesystem use
 */
 func sanitizeName(query string) string {
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\s]+`)
-	name := reg.ReplaceAllString(query, "")
-
-	name = strings.ReplaceAll(strings.TrimSpace(name), " ", "_")
+	var b strings.Builder
+	pendingSpace := false
+	for _, r := range query {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if pendingSpace && b.Len() > 0 {
+				b.WriteRune('_')
+			}
+			pendingSpace = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			pendingSpace = true
+		}
+	}
+	name := b.String()
 
-	if len(name) > 50 {
-		name = name[:50]
+	if runes := []rune(name); len(runes) > 50 {
+		name = strings.TrimRight(string(runes[:50]), "_")
 	}
 
 	if name == "" {
@@ -115,56 +476,138 @@ func sanitizeName(query string) string {
 }
 
 /*
-ImportSong copies an existing MP3 file into the songs folder structure.
+uniqueSongName picks a folder name that does not belong to a different song.
+
+Input:
+  - name: string - Sanitized folder name
+  - source: string - Original query (or URL) the folder is for
+
+Called by:
+  - Download before creating the song directory
+  - DownloadFromURL for the folder named after the URL
+
+Task:
+  - Avoid overwriting another song whose query sanitized to the same name
+
+Logic:
+ 1. A folder that does not exist yet is free: use it
+ 2. An existing folder whose source.txt holds this query belongs to it: reuse it
+ 3. The unsuffixed folder with no source.txt but a song file (downloaded before
+    source.txt was recorded) also belongs to it; Download then records the query
+ 4. Any other existing folder (different query, unreadable source.txt, or no
+    source.txt and no song) is taken: try "name_2", "name_3", ... until one is
+    free or belongs to this query
+
+Output:
+  - string: Folder name to use for this query
+*/
+func uniqueSongName(name, source string) string {
+	candidate := name
+	for i := 2; ; i++ {
+		dir := filepath.Join(config.SongsDir, candidate)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return candidate
+		}
+		data, err := os.ReadFile(filepath.Join(dir, sourceFile))
+		if err == nil && strings.TrimSpace(string(data)) == source {
+			return candidate
+		}
+		if os.IsNotExist(err) && candidate == name {
+			if _, err := os.Stat(config.GetSongPaths(dir).SongFile); err == nil {
+				return candidate
+			}
+		}
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+}
+
+/*
+ImportSong copies an existing audio file into the songs folder structure.
 
 Input:
-  - srcPath: string - Path to existing MP3 file (e.g., "Kasoor.mp3")
+  - srcPath: string - Path to existing MP3/FLAC/OGG/WAV/M4A/AAC file (e.g., "Kasoor.mp3")
 
 Called by:
-  - main.main when user provides an MP3 file path instead of song folder
+  - main.main when user provides an audio file path instead of song folder
 
 Task:
   - Extract song name from filename
   - Create song directory
-  - Copy file as song.mp3
+  - Copy file as song.<ext> (song.mp3, song.flac, song.ogg or song.wav)
+  - Convert .m4a/.aac files, which cannot be decoded directly, to song.mp3
 
 Logic:
- 1. Extract base filename and remove .mp3 extension to get song name
+ 1. Extract base filename and remove the extension to get song name
  2. Create directory using config.EnsureSongDir
- 3. If song.mp3 already exists in destination, return existing path
- 4. Read source file completely into memory
- 5. Write to destination as song.mp3
- 6. Print confirmation message
+ 3. Copy the file in with importInto
 
 Output:
   - string: Song directory path (e.g., "songs/Kasoor")
-  - error: nil on success, wrapped error on read/write failure
+  - error: nil on success, wrapped error on read/write/conversion failure
 */
 func ImportSong(srcPath string) (string, error) {
 	baseName := filepath.Base(srcPath)
-	ext := filepath.Ext(baseName)
-	songName := strings.TrimSuffix(baseName, ext)
+	songName := strings.TrimSuffix(baseName, filepath.Ext(baseName))
 
 	songDir, err := config.EnsureSongDir(songName)
 	if err != nil {
 		return "", err
 	}
+	return importInto(srcPath, songDir)
+}
+
+/*
+importInto copies an audio file into an existing song folder.
+
+Input:
+  - srcPath: string - MP3/FLAC/OGG/WAV/M4A/AAC file
+  - songDir: string - Song folder it becomes the song of
+
+Called by:
+  - ImportSong, DownloadFromURL with the downloaded temp file
+
+Task:
+  - Store the file as song.<ext>, converting formats that cannot be decoded
+
+Logic:
+ 1. If the song folder already has audio, return it unchanged
+ 2. .m4a or .aac: audio.ConvertToMP3 into the song's song.mp3 (fails with a
+    hint to install ffmpeg when it is missing)
+ 3. Otherwise read source file completely into memory and write it as song
+    plus the lower-cased source extension
+ 4. Print confirmation message
 
+Output:
+  - string: songDir
+  - error: nil on success, wrapped error on read/write/conversion failure
+*/
+func importInto(srcPath, songDir string) (string, error) {
+	ext := filepath.Ext(srcPath)
 	paths := config.GetSongPaths(songDir)
 
 	if _, err := os.Stat(paths.SongFile); err == nil {
 		return songDir, nil
 	}
 
+	if slices.Contains(config.ConvertExtensions, strings.ToLower(ext)) {
+		dest := filepath.Join(songDir, "song.mp3")
+		if err := audio.ConvertToMP3(srcPath, dest); err != nil {
+			return "", fmt.Errorf("failed to convert song: %w", err)
+		}
+		logging.Infof("Imported: %s -> %s", srcPath, dest)
+		return songDir, nil
+	}
+
 	input, err := os.ReadFile(srcPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read source: %w", err)
 	}
 
-	if err := os.WriteFile(paths.SongFile, input, 0644); err != nil {
+	dest := filepath.Join(songDir, "song"+strings.ToLower(ext))
+	if err := os.WriteFile(dest, input, 0644); err != nil {
 		return "", fmt.Errorf("failed to write song: %w", err)
 	}
 
-	fmt.Printf("Imported: %s -> %s\n", srcPath, paths.SongFile)
+	logging.Infof("Imported: %s -> %s", srcPath, dest)
 	return songDir, nil
 }
@@ -0,0 +1,194 @@
+package youtube
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"singAssist/internal/config"
+)
+
+func TestSanitizeNameKeepsUnicode(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"Never Gonna Give You Up", "Never_Gonna_Give_You_Up"},
+		{"Café del Mar", "Café_del_Mar"},
+		{"Señorita – Shawn Mendes", "Señorita_Shawn_Mendes"},
+		{"夜に駆ける YOASOBI", "夜に駆ける_YOASOBI"},
+		{"아리랑", "아리랑"},
+		{"  !!!  ", "downloaded_song"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeName(tt.query); got != tt.want {
+			t.Errorf("sanitizeName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeNameTruncatesRunes(t *testing.T) {
+	query := ""
+	for range 60 {
+		query += "日"
+	}
+	got := []rune(sanitizeName(query))
+	if len(got) != 50 {
+		t.Fatalf("sanitizeName kept %d runes, want 50", len(got))
+	}
+}
+
+// makeSong creates songs/<name> in the current directory, with a source.txt
+// holding source unless it is empty.
+func makeSong(t *testing.T, name, source string) {
+	t.Helper()
+	dir := filepath.Join(config.SongsDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if source != "" {
+		if err := os.WriteFile(filepath.Join(dir, sourceFile), []byte(source+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUniqueSongName(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	if got := uniqueSongName("Café", "Café"); got != "Café" {
+		t.Errorf("free folder: got %q, want %q", got, "Café")
+	}
+
+	makeSong(t, "Café", "Café")
+	if got := uniqueSongName("Café", "Café"); got != "Café" {
+		t.Errorf("same query: got %q, want the existing folder", got)
+	}
+	if got := uniqueSongName("Café", "Cafe!"); got != "Café_2" {
+		t.Errorf("different query: got %q, want %q", got, "Café_2")
+	}
+
+	makeSong(t, "Café_2", "Cafe!")
+	if got := uniqueSongName("Café", "Café?"); got != "Café_3" {
+		t.Errorf("two taken folders: got %q, want %q", got, "Café_3")
+	}
+	if got := uniqueSongName("Café", "Cafe!"); got != "Café_2" {
+		t.Errorf("query of the suffixed folder: got %q, want %q", got, "Café_2")
+	}
+}
+
+func TestUniqueSongNameWithoutSource(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	makeSong(t, "downloaded_song", "")
+	if got := uniqueSongName("downloaded_song", "夜に駆ける"); got != "downloaded_song_2" {
+		t.Errorf("folder without source.txt: got %q, want %q", got, "downloaded_song_2")
+	}
+}
+
+func TestUniqueSongNameReusesFolderWithSong(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	// Downloaded before source.txt existed: the folder has only its song.
+	makeSong(t, "Kasoor", "")
+	os.WriteFile(filepath.Join(config.SongsDir, "Kasoor", "song.mp3"), []byte("mp3"), 0644)
+	if got := uniqueSongName("Kasoor", "Kasoor"); got != "Kasoor" {
+		t.Errorf("folder with a song and no source.txt: got %q, want %q", got, "Kasoor")
+	}
+
+	// A suffixed folder without source.txt is never adopted.
+	makeSong(t, "Café", "Café")
+	makeSong(t, "Café_2", "")
+	os.WriteFile(filepath.Join(config.SongsDir, "Café_2", "song.mp3"), []byte("mp3"), 0644)
+	if got := uniqueSongName("Café", "Cafe!"); got != "Café_3" {
+		t.Errorf("suffixed folder without source.txt: got %q, want %q", got, "Café_3")
+	}
+}
+
+func TestDownloadReusesExistingSong(t *testing.T) {
+	f := &fakeRunner{}
+	useFakeRunner(t, f)
+	makeSong(t, "Kasoor", "")
+	os.WriteFile(filepath.Join(config.SongsDir, "Kasoor", "song.mp3"), []byte("mp3"), 0644)
+
+	dir, err := Download("Kasoor")
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if want := filepath.Join(config.SongsDir, "Kasoor"); dir != want {
+		t.Errorf("song dir = %q, want the existing %q", dir, want)
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("ran %q for a song that was already there", f.calls)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, sourceFile)); strings.TrimSpace(string(data)) != "Kasoor" {
+		t.Errorf("source.txt = %q, want the query recorded", data)
+	}
+	if _, err := os.Stat(filepath.Join(config.SongsDir, "Kasoor_2")); !os.IsNotExist(err) {
+		t.Error("Download made a duplicate Kasoor_2 folder")
+	}
+}
+
+func TestImportSongWAV(t *testing.T) {
+	t.Chdir(t.TempDir())
+	src := filepath.Join(t.TempDir(), "Studio Take.WAV")
+	if err := os.WriteFile(src, []byte("RIFF....WAVE"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ImportSong(src)
+	if err != nil {
+		t.Fatalf("ImportSong: %v", err)
+	}
+	want := filepath.Join(dir, "song.wav")
+	if got := config.GetSongPaths(dir).SongFile; got != want {
+		t.Errorf("SongFile = %q, want %q", got, want)
+	}
+}
+
+func TestParseSongInfo(t *testing.T) {
+	tests := []struct {
+		output string
+		want   config.SongInfo
+		ok     bool
+	}{
+		{"[download] 100%\n" + infoPrefix + "\tPrateek Kuhad\tKasoor\tIndie\n", config.SongInfo{Artist: "Prateek Kuhad", Title: "Kasoor", Genre: "Indie"}, true},
+		{infoPrefix + "\tNA\tKasoor (Live)\tNA\r\n", config.SongInfo{Title: "Kasoor (Live)"}, true},
+		{infoPrefix + "\tNA\tNA\tPop\n", config.SongInfo{}, false},
+		{"[download] 100%\n", config.SongInfo{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseSongInfo(tt.output)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("parseSongInfo(%q) = %+v, %v; want %+v, %v", tt.output, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDownloadSavesSongInfo(t *testing.T) {
+	useFakeRunner(t, &fakeRunner{})
+
+	dir, err := Download("Kasoor")
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	info, err := config.LoadSongInfo(dir)
+	if want := (config.SongInfo{Artist: "Artist", Title: "Kasoor", Genre: "Pop"}); err != nil || info != want {
+		t.Errorf("info.json = %+v, %v; want %+v", info, err, want)
+	}
+}
+
+func TestImportSongM4AWithoutFFMPEG(t *testing.T) {
+	t.Chdir(t.TempDir())
+	old := config.HasFFMPEG
+	config.HasFFMPEG = false
+	t.Cleanup(func() { config.HasFFMPEG = old })
+
+	src := filepath.Join(t.TempDir(), "Voice Memo.m4a")
+	if err := os.WriteFile(src, []byte("....ftypM4A "), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportSong(src); err == nil || !strings.Contains(err.Error(), "ffmpeg") {
+		t.Errorf("ImportSong(.m4a) without ffmpeg = %v, want an install hint", err)
+	}
+}
@@ -8,7 +8,12 @@ import (
 	"path/filepath"
 
 	"singAssist/internal/app"
+	"singAssist/internal/audio"
 	"singAssist/internal/config"
+	"singAssist/internal/logging"
+	"singAssist/internal/setlist"
+	"singAssist/internal/ui"
+	"singAssist/internal/web"
 	"singAssist/internal/youtube"
 
 	"github.com/gordonklaus/portaudio"
@@ -19,7 +24,7 @@ import (
 main is the application entry point.
 
 Input:
-  - Command line args: [-yt "query"] or <song_folder> or <song.mp3>
+  - Command line args: analyze <song_folder> (see runAnalyze), or [-yt "query"] [-url URL] [-playlist URL] [-start 1m05s] [-end 1m40s] [-mode fullmix] [-octave-agnostic] [-channel left] [-verbosity debug] [-blocks] [-transpose -3] [-recache] [-parts 2] [-web-hud 8080] [-no-record] [-analyze-only] [-setlist list.txt] [-offline] [-benchmark] or <song_folder> or <song.mp3|.flac|.ogg|.wav|.m4a|.aac>
 
 Task:
   - Parse CLI arguments
@@ -28,24 +33,116 @@ Task:
   - Launch game
 
 Logic:
- 1. Parse -yt flag for YouTube download
+ 1. "analyze" sub-command: run runAnalyze and exit with its code; otherwise
+    parse flags; validate -verbosity, -mode and -channel against the known values;
+    load config/keybindings.json and config/theme.json (written on first run
+    with the default keybindings and {"Name": "dark"}); -offline: config.SetOfflineMode; config.DetectFFMPEG
  2. Initialize PortAudio (required for microphone)
- 3. If -yt flag: call youtube.Download
+ 3. If -playlist flag: call youtube.DownloadPlaylist and play the first track;
+    else if -url flag: call youtube.DownloadFromURL;
+    else if -setlist flag: setlist.LoadSetlist and start with its first song;
+    else if -yt flag: call youtube.Download
  4. Else: use positional argument as song path
- 5. If no args: print usage and exit
- 6. Verify song.mp3 exists in songDir
- 7. If path is .mp3 file: import to songs folder
- 8. Create app.New with songDir
- 9. Configure Ebiten window
- 10. Run game loop
+ 5. If no args: open the song browser (print usage and exit when there are no songs)
+ 6. Verify song.mp3 (or song.flac/song.ogg/song.wav, or reference.mid for no-audio practice) exists in songDir
+ 7. If path is an .mp3/.flac/.ogg/.wav file: import to songs folder (.m4a/.aac
+    files are converted to song.mp3 with ffmpeg)
+ 8. -analyze-only: analyze the song, write pitch.csv and exit without a window;
+    -benchmark: run app.Benchmark in the -mode (default vocals) and exit
+ 9. Create app.New with songDir and practice section options (sessions are
+    recorded to WAV unless -no-record); -web-hud: serve the score HUD on that
+    port (web.StartHTTPHUD)
+ 10. Configure Ebiten window
+ 11. Run game loop
 
 Output:
   - Exit 0 on normal exit, Exit 1 on error
 */
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		os.Exit(runAnalyze(os.Args[2:]))
+	}
+
 	ytQuery := flag.String("yt", "", "YouTube search query to download and play")
+	songURL := flag.String("url", "", "Direct HTTP(S) link to an MP3, FLAC, OGG or WAV file to download and play")
+	playlistURL := flag.String("playlist", "", "YouTube playlist URL to download (plays the first track)")
+	startAt := flag.Duration("start", 0, "Start of practice section (e.g. 1m05s)")
+	endAt := flag.Duration("end", 0, "End of practice section (e.g. 1m40s)")
+	transpose := flag.Int("transpose", 0, "Shift the song by this many semitones (e.g. -4 to sing a third lower)")
+	blockView := flag.Bool("blocks", false, "Draw the song as piano-roll note blocks (toggle with B)")
+	ignoreOctave := flag.Bool("octave-agnostic", false, "Score by note name only, ignoring octave")
+	channelName := flag.String("channel", "mix", "Channel to analyze for song pitch: mix, left, right")
+	verbosity := flag.String("verbosity", "info", "Log level: debug, info, warn, error")
+	recache := flag.Bool("recache", false, "Re-analyze the song pitch even if a cached analysis exists")
+	webHUD := flag.Int("web-hud", 0, "Serve the score HUD as a web page on this local port (e.g. 8080) for a second screen")
+	noRecord := flag.Bool("no-record", false, "Do not save the microphone to recording_<time>.wav in the song folder")
+	parts := flag.Int("parts", 1, "Vocal parts in the song: 2 follows the left and right vocal channel as separate melodies")
+	analyzeOnly := flag.Bool("analyze-only", false, "Analyze the song, write its pitch to pitch.csv and exit without opening a window")
+	setlistPath := flag.String("setlist", "", "Text file of song folders (one per line) to play back-to-back")
+	benchmark := flag.Bool("benchmark", false, "Time song analysis, pitch detection and drawing for the song and exit (the drawing runs in a short-lived window)")
+	offline := flag.Bool("offline", false, "No internet or Python: disable downloads and analyze the full mix when a song is not separated yet")
+	modeName := flag.String("mode", "", "Start directly in this mode: vocals, roughvocals, instrumental, fullmix, noaudio, duet, warmup, harmony, freestyle, rangetest, intervalquiz")
 	flag.Parse()
 
+	level, err := logging.ParseLevel(*verbosity)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.SetLevel(level)
+	config.SetOfflineMode(*offline)
+	config.DetectFFMPEG()
+
+	if _, err := os.Stat(config.KeybindingsFile); os.IsNotExist(err) {
+		if err := config.WriteDefaultKeybindings(config.KeybindingsFile); err != nil {
+			logging.Warnf("Could not write default keybindings: %v", err)
+		}
+	} else if keys, err := config.LoadKeybindings(config.KeybindingsFile); err != nil {
+		logging.Warnf("Using default keybindings: %v", err)
+	} else {
+		config.Keys = keys
+	}
+
+	if _, err := os.Stat(ui.ThemeFile); os.IsNotExist(err) {
+		if err := ui.SaveThemeName(ui.ActiveTheme.Name, ui.ThemeFile); err != nil {
+			logging.Warnf("Could not write default theme: %v", err)
+		}
+	} else if theme, err := ui.LoadThemeFile(ui.ThemeFile); err != nil {
+		logging.Warnf("Using default theme: %v", err)
+	} else {
+		ui.ActiveTheme = theme
+	}
+
+	analysis := audio.DefaultAnalysisParams()
+	channel, err := audio.ParseChannel(*channelName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	analysis.Channel = channel
+
+	opts := app.Options{
+		Load: audio.LoadOptions{
+			Start:    *startAt,
+			End:      *endAt,
+			Analysis: analysis,
+			Recache:  *recache,
+			Parts:    *parts,
+		},
+		IgnoreOctave: *ignoreOctave,
+		BlockView:    *blockView,
+		Record:       !*noRecord,
+		Offline:      *offline,
+
+		TransposeSteps: max(-12, min(12, *transpose)),
+	}
+	if *modeName != "" {
+		mode, err := audio.ParseMode(*modeName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.AutoStart = true
+		opts.DefaultMode = mode
+	}
+
 	if err := portaudio.Initialize(); err != nil {
 		log.Fatal("Failed to initialize PortAudio:", err)
 	}
@@ -53,7 +150,30 @@ func main() {
 
 	var songDir string
 
-	if *ytQuery != "" {
+	if *playlistURL != "" {
+		dirs, err := youtube.DownloadPlaylist(*playlistURL, func(n, total int, name string) {
+			fmt.Printf("[%d/%d] %s\n", n, total, name)
+		})
+		if err != nil {
+			log.Fatal("Playlist download failed:", err)
+		}
+		fmt.Printf("Downloaded %d songs\n", len(dirs))
+		songDir = dirs[0]
+	} else if *songURL != "" {
+		dir, err := youtube.DownloadFromURL(*songURL)
+		if err != nil {
+			log.Fatal("URL download failed:", err)
+		}
+		songDir = dir
+	} else if *setlistPath != "" {
+		songs, err := setlist.LoadSetlist(*setlistPath)
+		if err != nil {
+			log.Fatal("Could not read setlist:", err)
+		}
+		fmt.Printf("Setlist: %d songs\n", len(songs))
+		opts.Setlist = songs
+		songDir = songs[0]
+	} else if *ytQuery != "" {
 		fmt.Printf("Downloading from YouTube: %s\n", *ytQuery)
 		dir, err := youtube.Download(*ytQuery)
 		if err != nil {
@@ -64,17 +184,19 @@ func main() {
 		args := flag.Args()
 		if len(args) > 0 {
 			songDir = args[0]
-		} else {
+		} else if songs, _ := config.ListSongs(); len(songs) == 0 {
 			printUsage()
 			os.Exit(1)
 		}
 	}
 
 	paths := config.GetSongPaths(songDir)
-	if _, err := os.Stat(paths.SongFile); os.IsNotExist(err) {
-		if filepath.Ext(songDir) == ".mp3" {
+	_, songErr := os.Stat(paths.SongFile)
+	_, midiErr := os.Stat(paths.MidiFile)
+	if songDir != "" && os.IsNotExist(songErr) && midiErr != nil {
+		if config.IsSongFile(songDir) {
 			if _, err := os.Stat(songDir); err == nil {
-				fmt.Printf("Importing MP3 file: %s\n", songDir)
+				fmt.Printf("Importing audio file: %s\n", songDir)
 				dir, err := youtube.ImportSong(songDir)
 				if err != nil {
 					log.Fatalf("Failed to import song: %v", err)
@@ -88,10 +210,51 @@ func main() {
 		}
 	}
 
-	application := app.New(songDir)
+	if *analyzeOnly {
+		if songDir == "" {
+			log.Fatal("-analyze-only needs a song folder or file")
+		}
+		mode := audio.ModeSinging
+		if opts.AutoStart {
+			mode = opts.DefaultMode
+		}
+		result, err := audio.LoadAndAnalyzeSong(songDir, mode, opts.Load, func(msg string) { fmt.Println(msg) })
+		if err != nil {
+			log.Fatal("Analysis failed:", err)
+		}
+		csvPath := config.GetSongPaths(songDir).PitchCSVFile
+		if err := audio.WritePitchCSV(csvPath, result.SongPitch); err != nil {
+			log.Fatal("Could not write pitch CSV:", err)
+		}
+		fmt.Printf("Wrote %s\n", csvPath)
+		return
+	}
+
+	if *benchmark {
+		if songDir == "" {
+			log.Fatal("-benchmark needs a song folder or file")
+		}
+		mode := audio.ModeSinging
+		if opts.AutoStart {
+			mode = opts.DefaultMode
+		}
+		if err := app.Benchmark(songDir, mode); err != nil {
+			log.Fatal("Benchmark failed:", err)
+		}
+		return
+	}
+
+	application := app.New(songDir, opts)
+	if *webHUD > 0 {
+		web.StartHTTPHUD(application, *webHUD)
+	}
 
 	ebiten.SetWindowSize(config.ScreenW, config.ScreenH)
-	ebiten.SetWindowTitle("SingAssist - " + filepath.Base(songDir))
+	if songDir != "" {
+		ebiten.SetWindowTitle("SingAssist - " + filepath.Base(songDir))
+	} else {
+		ebiten.SetWindowTitle("SingAssist")
+	}
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
 	if err := ebiten.RunGame(application); err != nil {
@@ -99,6 +262,71 @@ func main() {
 	}
 }
 
+/*
+runAnalyze implements "singAssist analyze <song_folder>".
+
+Input:
+  - args: []string - Arguments after "analyze": [-mode vocals] [-channel mix] [-recache] <song_folder>
+
+Called by:
+  - main when the first argument is "analyze"
+
+Task:
+  - Print a song's analysis without opening a window
+
+Logic:
+ 1. Parse the sub-command flags with their own flag.FlagSet; exactly one song folder
+ 2. audio.LoadAndAnalyzeSong in the chosen mode (default vocals), progress to stderr
+ 3. Print the report with audio.WriteAnalysisReport to stdout
+ 4. Any error: print it to stderr and return 1
+
+Output:
+  - int: Exit code (0 on success, 1 on error)
+*/
+func runAnalyze(args []string) int {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	modeName := fs.String("mode", "vocals", "Mode to analyze the song in: vocals, roughvocals, instrumental, fullmix, noaudio, duet, warmup, harmony, freestyle, rangetest, intervalquiz")
+	channelName := fs.String("channel", "mix", "Channel to analyze for song pitch: mix, left, right")
+	recache := fs.Bool("recache", false, "Re-analyze the song pitch even if a cached analysis exists")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: singAssist analyze [-mode vocals] [-channel mix] [-recache] <song_folder>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 1
+	}
+	songDir := fs.Arg(0)
+
+	mode, err := audio.ParseMode(*modeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	opts := audio.LoadOptions{Analysis: audio.DefaultAnalysisParams(), Recache: *recache}
+	if opts.Analysis.Channel, err = audio.ParseChannel(*channelName); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	result, err := audio.LoadAndAnalyzeSong(songDir, mode, opts, func(msg string) { fmt.Fprintln(os.Stderr, msg) })
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Analysis failed:", err)
+		return 1
+	}
+	if result.Player != nil {
+		result.Player.Close()
+	}
+	if err := audio.WriteAnalysisReport(os.Stdout, result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
 /*
 printUsage displays command line help and available songs.
 
@@ -106,7 +334,7 @@ Input:
   - None
 
 Called by:
-  - main when no song argument provided
+  - main when no song argument is given and the songs folder is empty
 
 Task:
   - Show usage instructions
@@ -127,16 +355,53 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  singAssist <song_folder>           Play from a song folder")
-	fmt.Println("  singAssist <song.mp3>              Import and play an MP3 file")
+	fmt.Println("  singAssist <song.mp3>              Import and play an MP3, FLAC, OGG or WAV file (M4A/AAC via ffmpeg)")
 	fmt.Println("  singAssist -yt \"song name\"         Download from YouTube and play")
+	fmt.Println("  singAssist -url <link.mp3>         Download a direct audio link and play")
+	fmt.Println("  singAssist -playlist <url>         Download a whole YouTube playlist")
+	fmt.Println("  singAssist -setlist list.txt       Play the listed song folders back-to-back")
+	fmt.Println("  singAssist analyze <song_folder>   Print duration, tempo, key, range and difficulty")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -start 1m05s -end 1m40s            Only load and practice that section")
+	fmt.Println("  -mode fullmix                      Skip the menu (vocals, roughvocals, instrumental, fullmix, noaudio, duet, warmup, harmony, freestyle, rangetest, intervalquiz)")
+	fmt.Println("  -octave-agnostic                   Count the right note in any octave as a hit")
+	fmt.Println("  -channel left                      Analyze only one channel (mix, left, right)")
+	fmt.Println("  -verbosity debug                   Log level (debug, info, warn, error)")
+	fmt.Println("  -blocks                            Show the song as note blocks (B toggles)")
+	fmt.Println("  -transpose -3                      Sing in another key, in semitones (+/- keys adjust)")
+	fmt.Println("  -recache                           Ignore the saved pitch analysis and analyze again")
+	fmt.Println("  -web-hud 8080                      Show notes and score at http://127.0.0.1:8080 (second screen)")
+	fmt.Println("  -no-record                         Do not record the microphone to recording_<time>.wav")
+	fmt.Println("  -parts 2                           Song has two vocal parts (left/right vocals); score the closer one")
+	fmt.Println("  -analyze-only                      Write the song pitch to pitch.csv and exit (uses -mode, default vocals)")
+	fmt.Println("  -benchmark                         Print load, pitch detection and drawing times and exit (uses -mode)")
+	fmt.Println("  -offline                           No internet or Python: no downloads, unseparated songs use the full mix")
+	fmt.Println()
+	fmt.Println("Environment:")
+	fmt.Println("  SINGASSIST_SMOOTH=median           Smooth mic pitch with a moving median instead of a mean")
+	fmt.Println("  SINGASSIST_PITCH_ALGO=fft          Detect pitch with the FFT harmonic product spectrum (autocorrelation; default: yin)")
+	fmt.Println("  SINGASSIST_SEPARATOR=demucs        Separate with demucs or spleeter (default: whichever is installed)")
+	fmt.Println("  SINGASSIST_SEPARATE_SCRIPT=my.py   Run this script instead (args: <song> <dir> --format mp3;")
+	fmt.Println("                                     also read from config/separate_script.txt)")
+	fmt.Println("  SINGASSIST_STEREO_MIC=mix          Capture a stereo mic and average both channels (loudest: keep the louder one)")
+	fmt.Println("  SINGASSIST_HOSTAPI=asio            Open the mic through this PortAudio host API (asio, wasapi, ...; default)")
+	fmt.Println()
+	fmt.Println("Keys:")
+	fmt.Println("  config/keybindings.json            Remap playback keys, e.g. {\"Pause\": \"KeyP\"}")
+	fmt.Println("  config/theme.json                  Colours: {\"Name\": \"light\"} (dark, light, high-contrast) plus overrides")
 	fmt.Println()
 	fmt.Println("Song Folder Structure:")
 	fmt.Println("  songs/<song_name>/")
-	fmt.Println("    ├── song.mp3           (original audio)")
+	fmt.Println("    ├── song.mp3           (original audio; song.flac, song.ogg or song.wav also work)")
 	fmt.Println("    ├── vocals.mp3         (separated, created on demand)")
-	fmt.Println("    └── accompaniment.mp3  (separated, created on demand)")
+	fmt.Println("    ├── accompaniment.mp3  (separated, created on demand)")
+	fmt.Println("    ├── reference.mid      (optional melody for No Audio mode)")
+	fmt.Println("    ├── harmony.json       (optional harmony offsets in semitones, e.g. [3, 7])")
+	fmt.Println("    └── lyrics.lrc         (optional timed lyrics shown during playback)")
 	fmt.Println()
 	fmt.Println("Examples:")
+	fmt.Println("  singAssist                         Pick a song from the songs folder")
 	fmt.Println("  singAssist songs/Kasoor")
 	fmt.Println("  singAssist Kasoor.mp3")
 	fmt.Println("  singAssist -yt \"Never Gonna Give You Up\"")
@@ -146,7 +411,7 @@ func printUsage() {
 		fmt.Println("Available songs:")
 		for _, e := range entries {
 			if e.IsDir() {
-				songPath := filepath.Join(config.SongsDir, e.Name(), "song.mp3")
+				songPath := config.GetSongPaths(filepath.Join(config.SongsDir, e.Name())).SongFile
 				if _, err := os.Stat(songPath); err == nil {
 					fmt.Printf("  - songs/%s\n", e.Name())
 				}
@@ -0,0 +1,56 @@
+#!/usr/bin/env python3
+"""
+Audio separator using Demucs.
+Separates vocals and accompaniment from an input audio file.
+
+Usage: python separate_demucs.py <input.mp3> <output_dir>
+Output: Creates <output_dir>/htdemucs/<track_name>/vocals.wav and no_vocals.wav
+        (singAssist converts them to vocals.mp3 and accompaniment.mp3)
+"""
+
+import sys
+import os
+import subprocess
+
+def separate_with_demucs(input_file, output_dir):
+    """Separate using Demucs (better quality)."""
+    print(f"Separating with Demucs: {input_file}")
+
+    # Demucs outputs to <output_dir>/<model_name>/<track_name>/
+    result = subprocess.run([
+        sys.executable, "-m", "demucs",
+        "--two-stems=vocals",  # Only separate vocals vs rest
+        "-o", output_dir,
+        input_file
+    ], capture_output=True, text=True)
+
+    if result.returncode != 0:
+        print(f"Demucs error: {result.stderr}")
+        return False
+
+    base_name = os.path.splitext(os.path.basename(input_file))[0]
+    stems_dir = os.path.join(output_dir, "htdemucs", base_name)
+    return os.path.exists(os.path.join(stems_dir, "vocals.wav"))
+
+def main():
+    if len(sys.argv) < 3:
+        print("Usage: python separate_demucs.py <input.mp3> <output_dir>")
+        sys.exit(1)
+
+    input_file = sys.argv[1]
+    output_dir = sys.argv[2]
+
+    if not os.path.exists(input_file):
+        print(f"Error: Input file not found: {input_file}")
+        sys.exit(1)
+
+    os.makedirs(output_dir, exist_ok=True)
+
+    if separate_with_demucs(input_file, output_dir):
+        print(f"Separation complete! Stems in: {os.path.join(output_dir, 'htdemucs')}")
+    else:
+        print("Separation failed! Install Demucs with: pip install demucs")
+        sys.exit(1)
+
+if __name__ == "__main__":
+    main()
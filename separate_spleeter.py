@@ -0,0 +1,69 @@
+#!/usr/bin/env python3
+"""
+Audio separator using Spleeter.
+Separates vocals and accompaniment from an input audio file.
+
+Usage: python separate_spleeter.py <input.mp3> <output_dir>
+Output: Creates vocals.mp3 and accompaniment.mp3 in output_dir
+"""
+
+import sys
+import os
+import subprocess
+import shutil
+
+def separate_with_spleeter(input_file, output_dir):
+    """Separate using Spleeter (faster but lower quality)."""
+    print(f"Separating with Spleeter: {input_file}")
+
+    result = subprocess.run([
+        sys.executable, "-m", "spleeter", "separate",
+        "-p", "spleeter:2stems",
+        "-o", output_dir,
+        input_file
+    ], capture_output=True, text=True)
+
+    if result.returncode != 0:
+        print(f"Spleeter error: {result.stderr}")
+        return False
+
+    # Spleeter outputs to <output_dir>/<track_name>/vocals.wav and accompaniment.wav
+    base_name = os.path.splitext(os.path.basename(input_file))[0]
+    stems_dir = os.path.join(output_dir, base_name)
+
+    if os.path.exists(stems_dir):
+        for name in ["vocals", "accompaniment"]:
+            src = os.path.join(stems_dir, f"{name}.wav")
+            dst = os.path.join(output_dir, f"{name}.mp3")
+            if os.path.exists(src):
+                subprocess.run(["ffmpeg", "-i", src, "-q:a", "2", dst, "-y"],
+                             capture_output=True)
+        shutil.rmtree(stems_dir, ignore_errors=True)
+        return True
+
+    return False
+
+def main():
+    if len(sys.argv) < 3:
+        print("Usage: python separate_spleeter.py <input.mp3> <output_dir>")
+        sys.exit(1)
+
+    input_file = sys.argv[1]
+    output_dir = sys.argv[2]
+
+    if not os.path.exists(input_file):
+        print(f"Error: Input file not found: {input_file}")
+        sys.exit(1)
+
+    os.makedirs(output_dir, exist_ok=True)
+
+    if separate_with_spleeter(input_file, output_dir):
+        print(f"Separation complete! Files in: {output_dir}")
+        print(f"  - vocals.mp3")
+        print(f"  - accompaniment.mp3")
+    else:
+        print("Separation failed! Install Spleeter with: pip install spleeter")
+        sys.exit(1)
+
+if __name__ == "__main__":
+    main()